@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mami0tsu/homeops/internal/buildinfo"
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/config"
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/errorreport"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/logging"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/quake"
+	"github.com/mami0tsu/homeops/internal/tracing"
+)
+
+type Config struct {
+	DiscordBotName   string `env:"DISCORD_BOT_NAME,required" ssm:"discord"`
+	DiscordBotToken  string `env:"DISCORD_BOT_TOKEN,required" ssm:"discord"`
+	DiscordChannelID string `env:"DISCORD_CHANNEL_ID,required" ssm:"discord"`
+	DiscordUseThread bool   `env:"DISCORD_USE_THREAD" envDefault:"false" ssm:"discord"` // remind と同じ日付ごとのスレッドに投稿する
+
+	AlertTableName string `env:"ALERT_TABLE_NAME,required"` // 投稿した地震情報と発表中の気象警報・注意報を記録するテーブル
+	AlertMention   string `env:"ALERT_MENTION"`             // 強い揺れや特別警報を投稿する際のメンション、e.g. @here, <@&123>
+
+	QuakeURL          string        `env:"QUAKE_URL" envDefault:"https://api.p2pquake.net/v2/jma/quake"`
+	QuakePrefectures  []string      `env:"QUAKE_PREFECTURES"`                   // 震度を確認する都道府県、e.g. 東京都,神奈川県
+	QuakeMinScale     quake.Scale   `env:"QUAKE_MIN_SCALE" envDefault:"3"`      // 観測した震度が満たない地震は投稿しない
+	QuakeMentionScale quake.Scale   `env:"QUAKE_MENTION_SCALE" envDefault:"5弱"` // 観測した震度が満たす地震はメンションする
+	QuakeMaxAge       time.Duration `env:"QUAKE_MAX_AGE" envDefault:"1h"`       // 発生から経過した地震は投稿しない
+
+	WarningURL               string       `env:"WARNING_URL" envDefault:"https://www.jma.go.jp/bosai/warning/data/warning"`
+	WarningAreas             WarningAreas `env:"WARNING_AREAS"`                                 // 気象警報・注意報を確認する地域、JSON で指定する
+	WarningIncludeAdvisories bool         `env:"WARNING_INCLUDE_ADVISORIES" envDefault:"false"` // 注意報も投稿する
+
+	SentryDSN string `env:"SENTRY_DSN" ssm:"sentry"` // 指定した場合はエラーを Sentry に通知する
+}
+
+func loadConfig(ctx context.Context) (*Config, error) {
+	var cfg Config
+	if err := config.Load(ctx, "alert", &cfg); err != nil {
+		slog.Error("failed to load config", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+func (c *Config) notifyConfig(clk clock.Clock) *notify.Config {
+	return &notify.Config{
+		HTTPClient:       httpclient.Default,
+		Clock:            clk,
+		DiscordBotName:   c.DiscordBotName,
+		DiscordBotToken:  c.DiscordBotToken,
+		DiscordChannelID: c.DiscordChannelID,
+		DiscordUseThread: c.DiscordUseThread,
+	}
+}
+
+func newAlertStore(ctx context.Context, cfg *Config) (*AlertStore, error) {
+	client, err := dynamodb.NewClient(ctx, httpclient.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAlertStore(client, cfg.AlertTableName), nil
+}
+
+// 呼び出しの間で再利用する設定とクライアント
+type clients struct {
+	cfg   *Config
+	store *AlertStore
+}
+
+// SSM パラメータや認証情報の更新を反映するため、一定時間が経過したら次の呼び出しで作り直す
+const clientsTTL = 15 * time.Minute
+
+// 数分ごとの呼び出しで SSM や Secrets Manager を毎回呼び出さないように、呼び出しの間で再利用する
+var cachedClients = config.NewCache(clientsTTL, newClients)
+
+func newClients(ctx context.Context) (*clients, error) {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	store, err := newAlertStore(ctx, cfg)
+	if err != nil {
+		slog.Error("failed to init DynamoDB client", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &clients{cfg: cfg, store: store}, nil
+}
+
+// コールドスタート時に作成し、呼び出しごとにリクエスト ID を付けて使う
+var logger = slog.Default()
+
+// EventBridge から数分ごとに呼び出され、地震情報と気象警報・注意報を確認する
+func handleRequest(ctx context.Context) error {
+	slog.SetDefault(logging.WithLambdaContext(ctx, logger))
+	defer tracing.Flush(ctx)
+
+	defer func() {
+		if v := recover(); v != nil {
+			errorreport.FromEnv().CapturePanic(ctx, v, nil)
+			panic(v)
+		}
+	}()
+
+	c, err := cachedClients.Get(ctx)
+	if err != nil {
+		return err
+	}
+	cfg := c.cfg
+
+	ctx, span := tracing.Start(ctx, "alert.relay")
+	err = runRelay(ctx, clock.System(), cfg, c.store, httpclient.Default, cfg.notifyConfig(clock.System()))
+	tracing.End(span, err)
+	if err != nil {
+		// 認証情報の期限切れなどに備えて、次の呼び出しでクライアントを作り直す
+		cachedClients.Invalidate()
+		errorreport.FromEnv().Capture(ctx, err, nil)
+		return err
+	}
+
+	return nil
+}
+
+func main() {
+	if _, err := config.ApplyProfile(); err != nil {
+		slog.Error("failed to apply profile", slog.Any("error", err))
+		os.Exit(1)
+	}
+	logger = logging.NewFromEnv()
+	logger.Info("starting alert", buildinfo.Get().Attr())
+	tracing.Setup("alert")
+	slog.SetDefault(logger)
+
+	// Lambda 以外で実行された場合は 1 度だけ確認する
+	if os.Getenv("AWS_LAMBDA_RUNTIME_API") == "" {
+		if err := handleRequest(context.Background()); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// コールドスタート時に作成しておく、失敗した場合は最初の呼び出しで再度作成する
+	if _, err := cachedClients.Get(context.Background()); err != nil {
+		slog.Warn("failed to init clients on cold start", slog.Any("error", err))
+	}
+
+	lambda.Start(handleRequest)
+}
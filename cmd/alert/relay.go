@@ -0,0 +1,279 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/quake"
+	"github.com/mami0tsu/homeops/internal/weather"
+)
+
+const (
+	green  = 0x2ecc71
+	yellow = 0xf1c40f
+	orange = 0xe67e22
+	red    = 0xe74c3c
+)
+
+// 1 回に取得する地震情報の件数
+const quakeLimit = 20
+
+// 投稿した地震情報と気象警報・注意報を保存する
+type alertStore interface {
+	PostedQuakes(ctx context.Context) (map[string]bool, error)
+	PutQuake(ctx context.Context, id string, t time.Time) error
+	Warnings(ctx context.Context) (map[string][]string, error)
+	PutWarnings(ctx context.Context, area string, names []string) error
+}
+
+// 気象警報・注意報を確認する地域
+type WarningArea struct {
+	Name   string `json:"name"`   // e.g. 東京都
+	Office string `json:"office"` // 気象台の地域コード、e.g. 130000
+	Area   string `json:"area"`   // 市区町村の地域コード、空の場合は気象台が受け持つすべての地域を対象とする
+}
+
+// e.g. [{"name": "東京都", "office": "130000"}, {"name": "自宅", "office": "130000", "area": "1310100"}]
+type WarningAreas []WarningArea
+
+func (a *WarningAreas) UnmarshalText(text []byte) error {
+	// WarningAreas のまま読み込むと UnmarshalText が再度呼び出されるため、スライスとして読み込む
+	var areas []WarningArea
+	if err := json.Unmarshal(text, &areas); err != nil {
+		return fmt.Errorf("invalid warning areas: %w", err)
+	}
+	for _, area := range areas {
+		if area.Name == "" || area.Office == "" {
+			return fmt.Errorf("invalid warning areas: name and office are required")
+		}
+	}
+	*a = areas
+
+	return nil
+}
+
+// 新しく取得した地震情報と、発表もしくは解除された気象警報・注意報を投稿する
+// 投稿に失敗した場合は記録せず、次回に再度投稿する
+func runRelay(ctx context.Context, clk clock.Clock, cfg *Config, store alertStore, client *http.Client, nc *notify.Config) error {
+	now := clk.Now()
+
+	var errs []error
+	if len(cfg.QuakePrefectures) > 0 {
+		errs = append(errs, relayQuakes(ctx, now, cfg, store, client, nc))
+	}
+	if len(cfg.WarningAreas) > 0 {
+		errs = append(errs, relayWarnings(ctx, cfg, store, client, nc))
+	}
+
+	return errors.Join(errs...)
+}
+
+func relayQuakes(ctx context.Context, now time.Time, cfg *Config, store alertStore, client *http.Client, nc *notify.Config) error {
+	reports, err := quake.Fetch(ctx, client, cfg.QuakeURL, quakeLimit, now.Location())
+	if err != nil {
+		slog.Error("failed to fetch quakes", slog.Any("error", err))
+		return err
+	}
+	posted, err := store.PostedQuakes(ctx)
+	if err != nil {
+		slog.Error("failed to get posted quakes", slog.Any("error", err))
+		return err
+	}
+
+	var messages []*discord.WebhookMessage
+	var ids []string
+	// 新しいものから返されるため、古いものから投稿する
+	for _, r := range slices.Backward(reports) {
+		if posted[r.ID] || now.Sub(r.Time) > cfg.QuakeMaxAge {
+			continue
+		}
+		scale, points := r.ScaleIn(cfg.QuakePrefectures)
+		if scale < cfg.QuakeMinScale {
+			continue
+		}
+		msg := &discord.WebhookMessage{Embeds: []*discord.Embed{createQuakeEmbed(r, scale, points)}}
+		if scale >= cfg.QuakeMentionScale {
+			msg.Content = cfg.AlertMention
+		}
+		messages = append(messages, msg)
+		ids = append(ids, r.ID)
+	}
+	if err := notify.PostDiscordMessages(ctx, nc, messages...); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, id := range ids {
+		if err := store.PutQuake(ctx, id, now); err != nil {
+			slog.Error("failed to put quake", slog.String("id", id), slog.Any("error", err))
+			errs = append(errs, err)
+		}
+	}
+	slog.Info("succeeded to relay quakes", slog.Int("reports", len(reports)), slog.Int("posted", len(ids)))
+
+	return errors.Join(errs...)
+}
+
+var quakeTypeNames = map[string]string{
+	"ScalePrompt":         "震度速報",
+	"ScaleAndDestination": "震源・震度に関する情報",
+	"DetailScale":         "各地の震度に関する情報",
+}
+
+// 震度ごとに観測した地点をまとめる
+func createQuakeEmbed(r quake.Report, scale quake.Scale, points []quake.Point) *discord.Embed {
+	color := yellow
+	if scale >= quake.Scale5Lower {
+		color = red
+	} else if scale >= quake.Scale4 {
+		color = orange
+	}
+	embed := discord.NewEmbed(fmt.Sprintf("🌏 %s の地震がありました", scale), color)
+
+	lines := []string{"発生時刻: " + r.Time.Format("2006-01-02 15:04")}
+	if r.Hypocenter != "" {
+		hypocenter := "震源: " + r.Hypocenter
+		var details []string
+		if r.Depth >= 0 {
+			details = append(details, fmt.Sprintf("深さ %dkm", r.Depth))
+		}
+		if r.Magnitude >= 0 {
+			details = append(details, fmt.Sprintf("M%.1f", r.Magnitude))
+		}
+		if len(details) > 0 {
+			hypocenter += fmt.Sprintf(" (%s)", strings.Join(details, "、"))
+		}
+		lines = append(lines, hypocenter)
+	}
+	if s := r.TsunamiText(); s != "" {
+		lines = append(lines, s)
+	}
+	embed.Description = strings.Join(lines, "\n")
+
+	slices.SortStableFunc(points, func(a, b quake.Point) int {
+		return cmp.Compare(b.Scale, a.Scale)
+	})
+	var addrs []string
+	for i, p := range points {
+		addrs = append(addrs, p.Pref+" "+p.Addr)
+		if i == len(points)-1 || points[i+1].Scale != p.Scale {
+			embed.AddField(p.Scale.String(), strings.Join(addrs, "、"))
+			addrs = nil
+		}
+	}
+	if name, ok := quakeTypeNames[r.Type]; ok {
+		embed.SetFooter(name)
+	}
+
+	return embed
+}
+
+func relayWarnings(ctx context.Context, cfg *Config, store alertStore, client *http.Client, nc *notify.Config) error {
+	prev, err := store.Warnings(ctx)
+	if err != nil {
+		slog.Error("failed to get warnings", slog.Any("error", err))
+		return err
+	}
+
+	var errs []error
+	var messages []*discord.WebhookMessage
+	next := map[string][]string{}
+	for _, a := range cfg.WarningAreas {
+		names, err := weather.FetchOfficeWarnings(ctx, client, cfg.WarningURL, a.Office, a.Area)
+		if err != nil {
+			// 取得できなかった地域は前回の状態のまま、他の地域は投稿する
+			slog.Error("failed to fetch warnings", slog.String("area", a.Name), slog.Any("error", err))
+			errs = append(errs, err)
+			continue
+		}
+		if !cfg.WarningIncludeAdvisories {
+			names = slices.DeleteFunc(names, func(name string) bool {
+				return weather.LevelOf(name) == weather.LevelAdvisory
+			})
+		}
+
+		before, seen := prev[a.Name]
+		issued := difference(names, before)
+		lifted := difference(before, names)
+		if seen && len(issued) == 0 && len(lifted) == 0 {
+			continue
+		}
+		next[a.Name] = names
+		if len(issued) == 0 && len(lifted) == 0 {
+			continue
+		}
+		msg := &discord.WebhookMessage{Embeds: []*discord.Embed{createWarningEmbed(a.Name, names, issued, lifted)}}
+		if slices.ContainsFunc(issued, func(name string) bool { return weather.LevelOf(name) == weather.LevelEmergency }) {
+			msg.Content = cfg.AlertMention
+		}
+		messages = append(messages, msg)
+	}
+	if err := notify.PostDiscordMessages(ctx, nc, messages...); err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+
+	for area, names := range next {
+		if err := store.PutWarnings(ctx, area, names); err != nil {
+			slog.Error("failed to put warnings", slog.String("area", area), slog.Any("error", err))
+			errs = append(errs, err)
+		}
+	}
+	slog.Info("succeeded to relay warnings", slog.Int("areas", len(cfg.WarningAreas)), slog.Int("posted", len(messages)))
+
+	return errors.Join(errs...)
+}
+
+var warningColors = map[weather.WarningLevel]int{
+	weather.LevelAdvisory:  yellow,
+	weather.LevelWarning:   orange,
+	weather.LevelEmergency: red,
+}
+
+// 発表された気象警報・注意報のうち最も程度が高いものの色にする
+func createWarningEmbed(area string, names, issued, lifted []string) *discord.Embed {
+	color := green
+	if len(issued) > 0 {
+		level := weather.LevelAdvisory
+		for _, name := range issued {
+			level = max(level, weather.LevelOf(name))
+		}
+		color = warningColors[level]
+	}
+	embed := discord.NewEmbed(fmt.Sprintf("🌀 %s の気象警報・注意報", area), color)
+	if len(issued) > 0 {
+		embed.AddField("発表", strings.Join(issued, "、"))
+	}
+	if len(lifted) > 0 {
+		embed.AddField("解除", strings.Join(lifted, "、"))
+	}
+	current := "なし"
+	if len(names) > 0 {
+		current = strings.Join(names, "、")
+	}
+	embed.AddField("発表中", current)
+
+	return embed
+}
+
+// a に含まれ b に含まれない名称を返す
+func difference(a, b []string) []string {
+	var d []string
+	for _, s := range a {
+		if !slices.Contains(b, s) {
+			d = append(d, s)
+		}
+	}
+
+	return d
+}
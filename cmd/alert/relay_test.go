@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/quake"
+	"github.com/mami0tsu/homeops/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAlertStore struct {
+	quakes   map[string]bool
+	warnings map[string][]string
+}
+
+func (s *fakeAlertStore) PostedQuakes(ctx context.Context) (map[string]bool, error) {
+	return s.quakes, nil
+}
+
+func (s *fakeAlertStore) PutQuake(ctx context.Context, id string, t time.Time) error {
+	s.quakes[id] = true
+	return nil
+}
+
+func (s *fakeAlertStore) Warnings(ctx context.Context) (map[string][]string, error) {
+	return s.warnings, nil
+}
+
+func (s *fakeAlertStore) PutWarnings(ctx context.Context, area string, names []string) error {
+	s.warnings[area] = names
+	return nil
+}
+
+func TestWarningAreasUnmarshalText(t *testing.T) {
+	cases := []struct {
+		name    string
+		text    string
+		want    WarningAreas
+		wantErr bool
+	}{
+		{
+			name: "正常系/市区町村を省略した場合",
+			text: `[{"name": "東京都", "office": "130000"}, {"name": "自宅", "office": "130000", "area": "1310100"}]`,
+			want: WarningAreas{{Name: "東京都", Office: "130000"}, {Name: "自宅", Office: "130000", Area: "1310100"}},
+		},
+		{
+			name:    "異常系/気象台の地域コードがない場合",
+			text:    `[{"name": "東京都"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "異常系/JSON ではない場合",
+			text:    `東京都`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			var got WarningAreas
+			err := got.UnmarshalText([]byte(tt.text))
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestCreateQuakeEmbed(t *testing.T) {
+	ta := assert.New(t)
+
+	r := quake.Report{
+		Type:       "DetailScale",
+		Time:       time.Date(2025, 3, 1, 12, 34, 0, 0, clock.JST()),
+		Hypocenter: "千葉県北西部",
+		Magnitude:  5.2,
+		Depth:      40,
+		Tsunami:    "None",
+	}
+	points := []quake.Point{
+		{Pref: "東京都", Addr: "千代田区", Scale: quake.Scale3},
+		{Pref: "東京都", Addr: "江戸川区", Scale: quake.Scale5Lower},
+		{Pref: "東京都", Addr: "中央区", Scale: quake.Scale3},
+	}
+
+	embed := createQuakeEmbed(r, quake.Scale5Lower, points)
+	ta.Equal("🌏 震度5弱 の地震がありました", embed.Title)
+	ta.Equal(red, embed.Color)
+	ta.Equal("発生時刻: 2025-03-01 12:34\n震源: 千葉県北西部 (深さ 40km、M5.2)\nこの地震による津波の心配はありません", embed.Description)
+	tr := require.New(t)
+	tr.Len(embed.Fields, 2)
+	ta.Equal("震度5弱", embed.Fields[0].Name)
+	ta.Equal("東京都 江戸川区", embed.Fields[0].Value)
+	ta.Equal("震度3", embed.Fields[1].Name)
+	ta.Equal("東京都 千代田区、東京都 中央区", embed.Fields[1].Value)
+	ta.Equal("各地の震度に関する情報", embed.Footer.Text)
+}
+
+func TestRunRelay(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	warnings := `{"areaTypes": [{"areas": [{"code": "130010", "warnings": [{"code": "03", "status": "発表"}, {"code": "14", "status": "発表"}]}]}]}`
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/quake":
+			w.Write([]byte(`[
+				{"id": "q3", "issue": {"type": "DetailScale"}, "earthquake": {"time": "2025/03/01 11:58:00", "hypocenter": {"name": "千葉県北西部", "depth": 40, "magnitude": 5.2}, "maxScale": 50, "domesticTsunami": "None"},
+				 "points": [{"pref": "千葉県", "addr": "浦安市", "scale": 50}, {"pref": "東京都", "addr": "江戸川区", "scale": 45}]},
+				{"id": "q2", "issue": {"type": "DetailScale"}, "earthquake": {"time": "2025/03/01 11:30:00", "hypocenter": {"name": "茨城県南部", "depth": 50, "magnitude": 3.8}, "maxScale": 30, "domesticTsunami": "None"},
+				 "points": [{"pref": "茨城県", "addr": "土浦市", "scale": 30}, {"pref": "東京都", "addr": "足立区", "scale": 10}]},
+				{"id": "q1", "issue": {"type": "DetailScale"}, "earthquake": {"time": "2025/03/01 09:00:00", "hypocenter": {"name": "東京湾", "depth": 30, "magnitude": 4.5}, "maxScale": 40, "domesticTsunami": "None"},
+				 "points": [{"pref": "東京都", "addr": "港区", "scale": 40}]}
+			]`))
+		case "/warning/130000.json":
+			w.Write([]byte(warnings))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer source.Close()
+
+	srv := testsupport.NewServer(t)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, clock.JST())
+	clk := clock.Fixed(now)
+	cfg := &Config{
+		DiscordBotName:    "alert",
+		DiscordBotToken:   "token",
+		DiscordChannelID:  "123",
+		AlertMention:      "@here",
+		QuakeURL:          source.URL + "/quake",
+		QuakePrefectures:  []string{"東京都"},
+		QuakeMinScale:     quake.Scale3,
+		QuakeMentionScale: quake.Scale5Lower,
+		QuakeMaxAge:       time.Hour,
+		WarningURL:        source.URL + "/warning",
+		WarningAreas:      WarningAreas{{Name: "東京都", Office: "130000"}},
+	}
+	nc := cfg.notifyConfig(clk)
+	nc.HTTPClient = srv.Client()
+	store := &fakeAlertStore{quakes: map[string]bool{}, warnings: map[string][]string{}}
+
+	tr.NoError(runRelay(context.Background(), clk, cfg, store, source.Client(), nc))
+
+	// 1 時間以上前の地震と、設定した都道府県の震度が小さい地震は投稿しない
+	msgs := srv.Discord.Messages()
+	tr.Len(msgs, 2)
+	ta.Equal("@here", msgs[0].Content)
+	ta.Equal("🌏 震度5弱 の地震がありました", msgs[0].Embeds[0].Title)
+	// 初めて確認した地域は発表中の警報を投稿し、注意報は投稿しない
+	ta.Empty(msgs[1].Content)
+	ta.Equal("🌀 東京都 の気象警報・注意報", msgs[1].Embeds[0].Title)
+	ta.Equal("大雨警報", msgs[1].Embeds[0].Fields[0].Value)
+	ta.Equal(map[string]bool{"q3": true}, store.quakes)
+	ta.Equal(map[string][]string{"東京都": {"大雨警報"}}, store.warnings)
+
+	// 変わらなければ再度投稿しない
+	tr.NoError(runRelay(context.Background(), clk, cfg, store, source.Client(), nc))
+	ta.Len(srv.Discord.Messages(), 2)
+
+	// 特別警報が発表された場合はメンションし、解除された警報も投稿する
+	warnings = `{"areaTypes": [{"areas": [{"code": "130010", "warnings": [{"code": "03", "status": "解除"}, {"code": "33", "status": "発表"}]}]}]}`
+	tr.NoError(runRelay(context.Background(), clk, cfg, store, source.Client(), nc))
+	msgs = srv.Discord.Messages()
+	tr.Len(msgs, 3)
+	ta.Equal("@here", msgs[2].Content)
+	ta.Equal(red, msgs[2].Embeds[0].Color)
+	ta.Equal("大雨特別警報", msgs[2].Embeds[0].Fields[0].Value)
+	ta.Equal("大雨警報", msgs[2].Embeds[0].Fields[1].Value)
+
+	// すべて解除された場合はメンションしない
+	warnings = `{"areaTypes": [{"areas": [{"code": "130010", "warnings": [{"code": "33", "status": "解除"}]}]}]}`
+	tr.NoError(runRelay(context.Background(), clk, cfg, store, source.Client(), nc))
+	msgs = srv.Discord.Messages()
+	tr.Len(msgs, 4)
+	ta.Empty(msgs[3].Content)
+	ta.Equal(green, msgs[3].Embeds[0].Color)
+	ta.Equal("なし", msgs[3].Embeds[0].Fields[1].Value)
+}
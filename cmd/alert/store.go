@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+)
+
+const (
+	quakePartitionKey   = "quake"
+	warningPartitionKey = "warning"
+)
+
+// 同じ地震情報を再度取得しても投稿しないように、投稿した地震情報を残す期間
+const quakeRetention = 7 * 24 * time.Hour
+
+// 投稿した地震情報と、地域ごとに投稿した発表中の気象警報・注意報を DynamoDB に保存する
+// テーブルのキーは pk (パーティションキー) と sk (ソートキー) とし、
+// 地震情報は "quake" に情報の ID で、気象警報・注意報は "warning" に地域の名前でそれぞれ保存する
+type AlertStore struct {
+	client *dynamodb.Client
+	table  string
+}
+
+func NewAlertStore(client *dynamodb.Client, table string) *AlertStore {
+	return &AlertStore{client: client, table: table}
+}
+
+func (s *AlertStore) PostedQuakes(ctx context.Context) (map[string]bool, error) {
+	items, err := s.client.Query(ctx, s.table, "pk = :pk", dynamodb.Item{":pk": dynamodb.S(quakePartitionKey)})
+	if err != nil {
+		return nil, err
+	}
+
+	posted := make(map[string]bool, len(items))
+	for _, item := range items {
+		posted[item.Str("sk")] = true
+	}
+
+	return posted, nil
+}
+
+// 期限が過ぎた項目は TTL で削除する
+func (s *AlertStore) PutQuake(ctx context.Context, id string, t time.Time) error {
+	return s.client.PutItem(ctx, s.table, dynamodb.Item{
+		"pk":         dynamodb.S(quakePartitionKey),
+		"sk":         dynamodb.S(id),
+		"expires_at": dynamodb.N(t.Add(quakeRetention).Unix()),
+	}, "", nil)
+}
+
+func (s *AlertStore) Warnings(ctx context.Context) (map[string][]string, error) {
+	items, err := s.client.Query(ctx, s.table, "pk = :pk", dynamodb.Item{":pk": dynamodb.S(warningPartitionKey)})
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := make(map[string][]string, len(items))
+	for _, item := range items {
+		var names []string
+		if err := json.Unmarshal([]byte(item.Str("names")), &names); err != nil {
+			// 壊れた項目は初めて確認する地域として扱う
+			slog.Warn("failed to parse warnings", slog.String("area", item.Str("sk")), slog.Any("error", err))
+			continue
+		}
+		warnings[item.Str("sk")] = names
+	}
+
+	return warnings, nil
+}
+
+func (s *AlertStore) PutWarnings(ctx context.Context, area string, names []string) error {
+	// 発表中のものがない場合も、確認済みの地域として空の配列を保存する
+	if names == nil {
+		names = []string{}
+	}
+	b, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+
+	return s.client.PutItem(ctx, s.table, dynamodb.Item{
+		"pk":    dynamodb.S(warningPartitionKey),
+		"sk":    dynamodb.S(area),
+		"names": dynamodb.S(string(b)),
+	}, "", nil)
+}
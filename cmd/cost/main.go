@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mami0tsu/homeops/internal/awsjson"
+	"github.com/mami0tsu/homeops/internal/buildinfo"
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/config"
+	"github.com/mami0tsu/homeops/internal/cost"
+	"github.com/mami0tsu/homeops/internal/errorreport"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/logging"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/tracing"
+)
+
+type Config struct {
+	DiscordBotName   string `env:"DISCORD_BOT_NAME,required" ssm:"discord"`
+	DiscordBotToken  string `env:"DISCORD_BOT_TOKEN,required" ssm:"discord"`
+	DiscordChannelID string `env:"DISCORD_CHANNEL_ID,required" ssm:"discord"`
+	DiscordUseThread bool   `env:"DISCORD_USE_THREAD" envDefault:"false" ssm:"discord"` // remind と同じ日付ごとのスレッドに投稿する
+
+	Budget           float64 `env:"COST_BUDGET"`                                   // 1 か月の予算 (USD)、0 の場合は予算に対する警告を行わない
+	BudgetThresholds []int   `env:"COST_BUDGET_THRESHOLDS" envDefault:"50,80,100"` // 今月の利用料金が予算の N% を超えたら警告する
+	TopServices      int     `env:"COST_TOP_SERVICES" envDefault:"10"`             // 上位 N 件のサービスを表示し、残りはまとめる
+
+	SentryDSN string `env:"SENTRY_DSN" ssm:"sentry"` // 指定した場合はエラーを Sentry に通知する
+}
+
+func loadConfig(ctx context.Context) (*Config, error) {
+	var cfg Config
+	if err := config.Load(ctx, "cost", &cfg); err != nil {
+		slog.Error("failed to load config", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+func (c *Config) notifyConfig(clk clock.Clock) *notify.Config {
+	return &notify.Config{
+		HTTPClient:       httpclient.Default,
+		Clock:            clk,
+		DiscordBotName:   c.DiscordBotName,
+		DiscordBotToken:  c.DiscordBotToken,
+		DiscordChannelID: c.DiscordChannelID,
+		DiscordUseThread: c.DiscordUseThread,
+	}
+}
+
+// 呼び出しの間で再利用する設定とクライアント
+type clients struct {
+	cfg  *Config
+	cost *awsjson.Client
+}
+
+// SSM パラメータや認証情報の更新を反映するため、一定時間が経過したら次の呼び出しで作り直す
+const clientsTTL = 15 * time.Minute
+
+var cachedClients = config.NewCache(clientsTTL, newClients)
+
+func newClients(ctx context.Context) (*clients, error) {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := awsjson.NewInRegion(ctx, cost.Service, cost.Region, cost.Target, cost.ContentType)
+	if err != nil {
+		slog.Error("failed to init Cost Explorer client", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &clients{cfg: cfg, cost: client}, nil
+}
+
+// Lambda の呼び出し時に渡される値、e.g. {"period": "weekly"}
+type Payload struct {
+	Period Period `json:"period"` // 空の場合は daily
+}
+
+// コールドスタート時に作成し、呼び出しごとにリクエスト ID を付けて使う
+var logger = slog.Default()
+
+func handleRequest(ctx context.Context, p Payload) error {
+	slog.SetDefault(logging.WithLambdaContext(ctx, logger))
+	defer tracing.Flush(ctx)
+
+	defer func() {
+		if v := recover(); v != nil {
+			errorreport.FromEnv().CapturePanic(ctx, v, nil)
+			panic(v)
+		}
+	}()
+
+	ctx, span := tracing.Start(ctx, "cost")
+	err := run(ctx, clock.System(), p)
+	tracing.End(span, err)
+	if err != nil {
+		// 認証情報の期限切れなどに備えて、次の呼び出しでクライアントを作り直す
+		cachedClients.Invalidate()
+		errorreport.FromEnv().Capture(ctx, err, nil)
+		return err
+	}
+
+	return nil
+}
+
+func run(ctx context.Context, clk clock.Clock, p Payload) error {
+	c, err := cachedClients.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	return postReport(ctx, clk, c.cfg, c.cost, c.cfg.notifyConfig(clk), p.Period)
+}
+
+func main() {
+	if _, err := config.ApplyProfile(); err != nil {
+		slog.Error("failed to apply profile", slog.Any("error", err))
+		os.Exit(1)
+	}
+	logger = logging.NewFromEnv()
+	logger.Info("starting cost", buildinfo.Get().Attr())
+	tracing.Setup("cost")
+	slog.SetDefault(logger)
+
+	// Lambda 以外で実行された場合は 1 度だけ投稿する、COST_PERIOD で期間を指定できる
+	if os.Getenv("AWS_LAMBDA_RUNTIME_API") == "" {
+		ctx := context.Background()
+		err := run(ctx, clock.System(), Payload{Period: Period(os.Getenv("COST_PERIOD"))})
+		tracing.Flush(ctx)
+		if err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// コールドスタート時に作成しておく、失敗した場合は最初の呼び出しで再度作成する
+	if _, err := cachedClients.Get(context.Background()); err != nil {
+		slog.Warn("failed to init clients on cold start", slog.Any("error", err))
+	}
+
+	lambda.Start(handleRequest)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/cost"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/notify"
+)
+
+// 集計する期間
+type Period string
+
+const (
+	PeriodDaily  Period = "daily"  // 前日
+	PeriodWeekly Period = "weekly" // 前日までの 7 日間
+)
+
+const (
+	green  = 0x2ecc71
+	orange = 0xe67e22
+	red    = 0xe74c3c
+)
+
+// 実行日の前日までの期間を返す、終了日は実行日 (その日を含まない)
+func (p Period) Range(today time.Time) (time.Time, time.Time, error) {
+	switch p {
+	case "", PeriodDaily:
+		return today.AddDate(0, 0, -1), today, nil
+	case PeriodWeekly:
+		return today.AddDate(0, 0, -7), today, nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period: %s", p)
+	}
+}
+
+// 期間中の利用料金をサービスごとに投稿する
+// 予算を指定した場合は、前日までの今月の利用料金と予算に対する割合もあわせて投稿する
+func postReport(ctx context.Context, clk clock.Clock, cfg *Config, c cost.Caller, nc *notify.Config, period Period) error {
+	today := clock.Today(clk)
+	start, end, err := period.Range(today)
+	if err != nil {
+		slog.Error("failed to resolve period", slog.Any("error", err))
+		return err
+	}
+
+	// Cost Explorer は UTC の日付で集計する
+	r, err := cost.Fetch(ctx, c, utcDate(start), utcDate(end))
+	if err != nil {
+		slog.Error("failed to fetch costs", slog.Any("error", err))
+		return err
+	}
+
+	var month *cost.Report
+	if cfg.Budget > 0 {
+		// 月初に実行した場合は前月の利用料金を集計する
+		last := end.AddDate(0, 0, -1)
+		first := time.Date(last.Year(), last.Month(), 1, 0, 0, 0, 0, last.Location())
+		m, err := cost.Fetch(ctx, c, utcDate(first), utcDate(end))
+		if err != nil {
+			slog.Error("failed to fetch monthly costs", slog.Any("error", err))
+			return err
+		}
+		month = &m
+	}
+
+	embed := createReportEmbed(r, month, cfg)
+	if err := notify.PostDiscordMessages(ctx, nc, &discord.WebhookMessage{Embeds: []*discord.Embed{embed}}); err != nil {
+		return err
+	}
+	slog.Info("succeeded to post cost report", slog.String("period", string(period)), slog.Float64("total", r.Total()))
+
+	return nil
+}
+
+func utcDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// 予算の閾値を超えた場合は橙に、予算を超えた場合は赤にする
+func createReportEmbed(r cost.Report, month *cost.Report, cfg *Config) *discord.Embed {
+	last := r.End.AddDate(0, 0, -1)
+	title := fmt.Sprintf("💰 AWS の利用料金 (%s)", last.Format("1/2"))
+	if !r.Start.Equal(last) {
+		title = fmt.Sprintf("💰 AWS の利用料金 (%s〜%s)", r.Start.Format("1/2"), last.Format("1/2"))
+	}
+
+	color := green
+	description := fmt.Sprintf("合計 %s", r.Format(r.Total()))
+	if len(r.Services) == 0 {
+		description = "利用料金は発生していません"
+	}
+	if month != nil {
+		spent := month.Total()
+		if exceeded := cost.ExceededThreshold(spent, cfg.Budget, cfg.BudgetThresholds); exceeded > 0 {
+			color = orange
+			if exceeded >= 100 {
+				color = red
+			}
+			description += fmt.Sprintf("\n⚠ 今月の利用料金が予算の %d%% を超えました", exceeded)
+		}
+	}
+
+	embed := discord.NewEmbed(title, color)
+	embed.Description = description
+	for _, s := range r.Top(cfg.TopServices) {
+		embed.AddField(s.Service, r.Format(s.Amount))
+	}
+	if month != nil {
+		spent := month.Total()
+		embed.AddField(fmt.Sprintf("%s の利用料金", month.Start.Format("1月")), fmt.Sprintf("%s / 予算 %s (%.0f%%)", month.Format(spent), month.Format(cfg.Budget), spent/cfg.Budget*100))
+	}
+
+	return embed
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/cost"
+	"github.com/mami0tsu/homeops/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodRange(t *testing.T) {
+	today := time.Date(2025, 3, 1, 0, 0, 0, 0, clock.JST())
+
+	cases := []struct {
+		name      string
+		period    Period
+		wantStart time.Time
+		wantErr   bool
+	}{
+		{name: "正常系/未指定の場合は前日", wantStart: time.Date(2025, 2, 28, 0, 0, 0, 0, clock.JST())},
+		{name: "正常系/weekly", period: PeriodWeekly, wantStart: time.Date(2025, 2, 22, 0, 0, 0, 0, clock.JST())},
+		{name: "異常系/不明な期間", period: "monthly", wantErr: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			start, end, err := tt.period.Range(today)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.wantStart, start)
+			ta.Equal(today, end)
+		})
+	}
+}
+
+func TestCreateReportEmbed(t *testing.T) {
+	r := cost.Report{
+		Start:    time.Date(2025, 2, 22, 0, 0, 0, 0, time.UTC),
+		End:      time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		Unit:     "USD",
+		Services: []cost.ServiceCost{{Service: "Amazon S3", Amount: 1.5}, {Service: "AWS Lambda", Amount: 0.25}},
+	}
+
+	cases := []struct {
+		name            string
+		spent           float64
+		budget          float64
+		wantColor       int
+		wantDescription string
+	}{
+		{
+			name:            "正常系/予算を指定しない",
+			wantColor:       green,
+			wantDescription: "合計 $1.75",
+		},
+		{
+			name:            "正常系/予算の閾値を超えた",
+			spent:           8.5,
+			budget:          10,
+			wantColor:       orange,
+			wantDescription: "合計 $1.75\n⚠ 今月の利用料金が予算の 80% を超えました",
+		},
+		{
+			name:            "正常系/予算を超えた",
+			spent:           12,
+			budget:          10,
+			wantColor:       red,
+			wantDescription: "合計 $1.75\n⚠ 今月の利用料金が予算の 100% を超えました",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			cfg := &Config{Budget: tt.budget, BudgetThresholds: []int{50, 80, 100}, TopServices: 10}
+			var month *cost.Report
+			if tt.budget > 0 {
+				month = &cost.Report{Start: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), Unit: "USD", Services: []cost.ServiceCost{{Service: "Amazon S3", Amount: tt.spent}}}
+			}
+
+			e := createReportEmbed(r, month, cfg)
+			ta.Equal("💰 AWS の利用料金 (2/22〜2/28)", e.Title)
+			ta.Equal(tt.wantColor, e.Color)
+			ta.Equal(tt.wantDescription, e.Description)
+			ta.Equal("$1.50", e.Fields[0].Value)
+			if month != nil {
+				ta.Equal("2月 の利用料金", e.Fields[2].Name)
+			}
+		})
+	}
+}
+
+// 呼び出された操作の入力を記録し、同じレスポンスを返す
+type fakeCostExplorer struct {
+	inputs []string
+}
+
+func (c *fakeCostExplorer) Do(ctx context.Context, operation string, in any, out any) error {
+	b, _ := json.Marshal(in)
+	c.inputs = append(c.inputs, string(b))
+
+	return json.Unmarshal([]byte(`{"ResultsByTime":[{"Groups":[{"Keys":["Amazon S3"],"Metrics":{"UnblendedCost":{"Amount":"6.00","Unit":"USD"}}}]}]}`), out)
+}
+
+func TestPostReport(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	s := testsupport.NewServer(t)
+	clk := clock.Fixed(time.Date(2025, 3, 1, 9, 0, 0, 0, clock.JST()))
+	cfg := &Config{
+		DiscordBotName:   "cost",
+		DiscordBotToken:  "token",
+		DiscordChannelID: "123",
+		Budget:           10,
+		BudgetThresholds: []int{50, 80, 100},
+	}
+	nc := cfg.notifyConfig(clk)
+	nc.HTTPClient = s.Client()
+	c := &fakeCostExplorer{}
+
+	err := postReport(context.Background(), clk, cfg, c, nc, PeriodDaily)
+	tr.NoError(err)
+
+	// 月初に実行した場合は前月の利用料金を集計する
+	tr.Len(c.inputs, 2)
+	ta.Contains(c.inputs[0], `"TimePeriod":{"Start":"2025-02-28","End":"2025-03-01"}`)
+	ta.Contains(c.inputs[1], `"TimePeriod":{"Start":"2025-02-01","End":"2025-03-01"}`)
+
+	msgs := s.Discord.Messages()
+	tr.Len(msgs, 1)
+	tr.Len(msgs[0].Embeds, 1)
+	ta.Equal("💰 AWS の利用料金 (2/28)", msgs[0].Embeds[0].Title)
+	ta.Equal(orange, msgs[0].Embeds[0].Color)
+}
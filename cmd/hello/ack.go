@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// remind が投稿したボタンが押された場合に、イベントの対応状況を更新する
+// custom_id の形式は event.AckCustomID を参照
+func handleComponent(ctx context.Context, c *clients, clk clock.Clock, req discord.Interaction) (discord.InteractionResponse, error) {
+	a, err := event.ParseAckCustomID(req.Data.CustomID, clk.Location())
+	if err != nil {
+		return discord.InteractionResponse{}, err
+	}
+	if c.cfg.AckTableName == "" {
+		return discord.InteractionResponse{}, fmt.Errorf("ACK_TABLE_NAME is not set")
+	}
+
+	// 単発のイベントはスプレッドシートのチェックボックスも更新する
+	if a.Row > 0 {
+		if err := markDone(ctx, c, a.Row); err != nil {
+			return discord.InteractionResponse{}, err
+		}
+	}
+
+	itemKey := dynamodb.Item{
+		"pk": dynamodb.S(event.AckPartitionKey),
+		"sk": dynamodb.S(event.AckSortKey(a.Date, a.Key)),
+	}
+
+	// remind が登録していないイベントは有効期限が設定されないため、新しく作成しない
+	var content string
+	switch a.Status {
+	case event.AckDone:
+		err = c.dynamodb.UpdateItemIf(ctx, c.cfg.AckTableName, itemKey, "SET ack_status = :s REMOVE snooze_until", "attribute_exists(pk)", dynamodb.Item{
+			":s": dynamodb.S(string(event.AckDone)),
+		})
+		content = "✅ Marked as done"
+	case event.AckSnoozed:
+		// ボタンを押した日の翌日から再通知する
+		tomorrow := clock.Today(clk).AddDate(0, 0, 1)
+		err = c.dynamodb.UpdateItemIf(ctx, c.cfg.AckTableName, itemKey, "SET ack_status = :s, snooze_until = :u", "attribute_exists(pk)", dynamodb.Item{
+			":s": dynamodb.S(string(event.AckSnoozed)),
+			":u": dynamodb.S(tomorrow.Format(event.AckDateFormat)),
+		})
+		content = fmt.Sprintf("💤 Snoozed until %s", tomorrow.Format("2006-01-02"))
+	}
+	switch {
+	case dynamodb.IsConditionalCheckFailed(err):
+		slog.Warn("ack record not found", slog.String("date", a.Date.Format(event.AckDateFormat)), slog.String("key", a.Key))
+		content = "⚠ This event is no longer tracked"
+	case err != nil:
+		return discord.InteractionResponse{}, err
+	default:
+		slog.Info("updated ack status", slog.String("status", string(a.Status)), slog.String("date", a.Date.Format(event.AckDateFormat)), slog.String("key", a.Key))
+	}
+
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+			Flags:   discord.FlagEphemeral,
+		},
+	}, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/shopping"
+)
+
+// /buy add item:<品目> | /buy list | /buy done item:<番号または品目> で買い物リストを操作する
+func handleBuy(ctx context.Context, c *clients, clk clock.Clock, req discord.Interaction) (discord.InteractionResponse, error) {
+	if c.cfg.ShoppingTableName == "" {
+		return discord.InteractionResponse{}, fmt.Errorf("SHOPPING_TABLE_NAME is not set")
+	}
+	if len(req.Data.Options) != 1 {
+		return discord.InteractionResponse{}, fmt.Errorf("invalid buy command options")
+	}
+	sub := req.Data.Options[0]
+	item, _ := discord.FindOption(sub.Options, "item")
+
+	store := shopping.NewStore(c.dynamodb, c.cfg.ShoppingTableName)
+
+	var content string
+	switch sub.Name {
+	case "add":
+		added, err := store.Add(ctx, item.String(), req.UserName(), clk.Now())
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = fmt.Sprintf("🛒 %s を追加しました", item.String())
+		if !added {
+			content = fmt.Sprintf("🛒 %s はすでにリストにあります", item.String())
+		}
+	case "list":
+		items, err := store.List(ctx)
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = "🛒 買い物リストは空です"
+		if len(items) > 0 {
+			content = "🛒 買い物リスト\n" + shopping.Format(items)
+		}
+	case "done":
+		items, err := store.List(ctx)
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		target, ok := shopping.Find(items, item.String())
+		if !ok {
+			return discord.InteractionResponse{
+				Type: discord.ResponseChannelMessageWithSource,
+				Data: &discord.InteractionResponseData{
+					Content: fmt.Sprintf("⚠ %s はリストにありません", item.String()),
+					Flags:   discord.FlagEphemeral,
+				},
+			}, nil
+		}
+		if err := store.Done(ctx, target); err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = fmt.Sprintf("✅ %s を購入済みにしました", target.Name)
+	default:
+		return discord.InteractionResponse{}, fmt.Errorf("invalid buy subcommand: %s", sub.Name)
+	}
+	slog.Info("handled buy command", slog.String("subcommand", sub.Name), slog.String("item", item.String()))
+
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+		},
+	}, nil
+}
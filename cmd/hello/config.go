@@ -0,0 +1,29 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/mami0tsu/homeops/internal/config"
+)
+
+// 署名の検証で失敗しないように、設定値の形式を起動時にまとめて検証する
+func (c *Config) Validate() error {
+	// Ed25519 の公開鍵は 32 バイト
+	errs := []error{
+		config.CheckHex("DISCORD_PUBLIC_KEY", c.DiscordPublicKey, 32),
+	}
+	if c.GoogleCredentials != "" {
+		errs = append(errs, config.CheckGoogleCredentials("GOOGLE_CREDENTIALS", c.GoogleCredentials))
+	}
+	if c.GoogleSpreadsheetID != "" {
+		errs = append(errs, config.CheckSpreadsheetID("GOOGLE_SPREADSHEET_ID", c.GoogleSpreadsheetID))
+	}
+	if c.LibraryTableName != "" && c.BookLookupURL != "" {
+		errs = append(errs, config.CheckURL("BOOK_LOOKUP_URL", c.BookLookupURL))
+	}
+	if c.SentryDSN != "" {
+		errs = append(errs, config.CheckURL("SENTRY_DSN", c.SentryDSN))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/ledger"
+)
+
+// /expense amount:<円> category:<カテゴリ> [memo:<メモ>] で支出をスプレッドシートに記録する
+func handleExpense(ctx context.Context, c *clients, clk clock.Clock, req discord.Interaction) (discord.InteractionResponse, error) {
+	e, err := parseExpense(req, clk)
+	if err != nil {
+		// 入力の誤りは操作したユーザーに伝える
+		return discord.InteractionResponse{
+			Type: discord.ResponseChannelMessageWithSource,
+			Data: &discord.InteractionResponseData{
+				Content: fmt.Sprintf("⚠ %s", err),
+				Flags:   discord.FlagEphemeral,
+			},
+		}, nil
+	}
+	if err := appendExpense(ctx, c, e); err != nil {
+		return discord.InteractionResponse{}, err
+	}
+
+	content := fmt.Sprintf("💴 %s %s を記録しました", e.Category, ledger.FormatYen(e.Amount))
+	if e.Memo != "" {
+		content += fmt.Sprintf(" (%s)", e.Memo)
+	}
+
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+		},
+	}, nil
+}
+
+func parseExpense(req discord.Interaction, clk clock.Clock) (ledger.Entry, error) {
+	options := req.Data.Options
+	amount, ok := discord.FindOption(options, "amount")
+	if !ok {
+		return ledger.Entry{}, fmt.Errorf("amount is required")
+	}
+	n, ok := amount.Number()
+	if !ok || n != math.Trunc(n) || math.Abs(n) > math.MaxInt32 {
+		return ledger.Entry{}, fmt.Errorf("invalid amount: %v", amount.Value)
+	}
+	category, _ := discord.FindOption(options, "category")
+	memo, _ := discord.FindOption(options, "memo")
+
+	e := ledger.Entry{
+		Date:     clock.Today(clk),
+		Amount:   int(n),
+		Category: category.String(),
+		Memo:     memo.String(),
+		User:     req.UserName(),
+	}
+	if err := e.Validate(); err != nil {
+		return ledger.Entry{}, err
+	}
+
+	return e, nil
+}
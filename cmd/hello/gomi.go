@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/gomi"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/sources"
+)
+
+// 今日と明日に収集するゴミを返す
+func handleGomi(ctx context.Context, c *clients, clk clock.Clock) (discord.InteractionResponse, error) {
+	cal, err := loadGomi(ctx, c)
+	if err != nil {
+		return discord.InteractionResponse{}, err
+	}
+
+	// 祝日を取得できなかった場合も、祝日を考慮せずに返す
+	var holidays event.Holidays
+	if c.cfg.HolidaysURL != "" {
+		if holidays, err = sources.FetchHolidays(ctx, httpclient.Default, c.cfg.HolidaysURL); err != nil {
+			slog.Warn("failed to fetch holidays", slog.Any("error", err))
+		}
+	}
+
+	now := clk.Now()
+	content := fmt.Sprintf("今日は %s\n明日は %s", gomi.Describe(cal.Collections(now, holidays)), gomi.Describe(cal.Collections(now.AddDate(0, 0, 1), holidays)))
+
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+			Flags:   discord.FlagEphemeral,
+		},
+	}, nil
+}
+
+// remind と同じ設定からゴミの収集日の規則を読み込む
+func loadGomi(ctx context.Context, c *clients) (*gomi.Calendar, error) {
+	cfg := c.cfg
+	switch {
+	case cfg.GomiSheetTab != "":
+		srv, err := c.sheetsService("read the gomi schedule")
+		if err != nil {
+			return nil, err
+		}
+		resp, err := srv.Spreadsheets.Values.Get(cfg.GoogleSpreadsheetID, fmt.Sprintf("%s!A:F", cfg.GomiSheetTab)).Context(ctx).Do()
+		if err != nil {
+			return nil, err
+		}
+		return gomi.ParseRows(resp.Values)
+	case cfg.GomiSchedule != "":
+		return gomi.Parse([]byte(cfg.GomiSchedule))
+	default:
+		return nil, fmt.Errorf("GOMI_SCHEDULE or GOMI_SHEET_TAB is required")
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/habit"
+)
+
+// /habit add name:<習慣> | /habit list | /habit remove name:<習慣> | /habit done name:<習慣> で習慣を操作する
+// 習慣と記録は remind のイベントの対応状況と同じテーブルに保存し、remind の投稿で週ごとにまとめる
+func handleHabit(ctx context.Context, c *clients, clk clock.Clock, req discord.Interaction) (discord.InteractionResponse, error) {
+	if c.cfg.AckTableName == "" {
+		return discord.InteractionResponse{}, fmt.Errorf("ACK_TABLE_NAME is not set")
+	}
+	if len(req.Data.Options) != 1 {
+		return discord.InteractionResponse{}, fmt.Errorf("invalid habit command options")
+	}
+	sub := req.Data.Options[0]
+	name, _ := discord.FindOption(sub.Options, "name")
+
+	store := habit.NewStore(c.dynamodb, c.cfg.AckTableName)
+
+	var content string
+	switch sub.Name {
+	case "add":
+		added, err := store.Add(ctx, name.String(), req.UserName(), clk.Now())
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = fmt.Sprintf("📈 %s を登録しました", name.String())
+		if !added {
+			content = fmt.Sprintf("📈 %s は登録済みです", name.String())
+		}
+	case "list":
+		habits, err := store.List(ctx)
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = "📈 登録した習慣はありません"
+		if len(habits) > 0 {
+			lines := []string{"📈 登録した習慣"}
+			for _, h := range habits {
+				dates, err := store.CheckIns(ctx, h, clk.Location())
+				if err != nil {
+					return discord.InteractionResponse{}, err
+				}
+				lines = append(lines, "- "+habit.Summarize(h, dates, clock.Today(clk)).String())
+			}
+			content = strings.Join(lines, "\n")
+		}
+	case "remove", "done":
+		habits, err := store.List(ctx)
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		target, ok := habit.Find(habits, name.String())
+		if !ok {
+			return createHabitWarning(fmt.Sprintf("⚠ %s は登録されていません", name.String())), nil
+		}
+		if sub.Name == "remove" {
+			if err := store.Remove(ctx, target); err != nil {
+				return discord.InteractionResponse{}, err
+			}
+			content = fmt.Sprintf("🗑 %s の登録を削除しました", target.Name)
+			break
+		}
+
+		today := clock.Today(clk)
+		checked, err := store.CheckIn(ctx, target, today, req.UserName())
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		if !checked {
+			return createHabitWarning(fmt.Sprintf("⚠ %s は今日すでに記録しています", target.Name)), nil
+		}
+		dates, err := store.CheckIns(ctx, target, clk.Location())
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = fmt.Sprintf("✅ %s を記録しました\n%s", target.Name, habit.Summarize(target, dates, today))
+	default:
+		return discord.InteractionResponse{}, fmt.Errorf("invalid habit subcommand: %s", sub.Name)
+	}
+	slog.Info("handled habit command", slog.String("subcommand", sub.Name), slog.String("name", name.String()))
+
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+		},
+	}, nil
+}
+
+func createHabitWarning(content string) discord.InteractionResponse {
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+			Flags:   discord.FlagEphemeral,
+		},
+	}
+}
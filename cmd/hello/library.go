@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/library"
+)
+
+// /library add due:<返却期限> [title:<タイトル>] [isbn:<ISBN>] | /library list | /library return item:<番号またはタイトル> で借りている本を操作する
+// タイトルを省略した場合は ISBN から書名を取得し、返却期限が近い本は remind の投稿で知らせる
+func handleLibrary(ctx context.Context, c *clients, clk clock.Clock, req discord.Interaction) (discord.InteractionResponse, error) {
+	if c.cfg.LibraryTableName == "" {
+		return discord.InteractionResponse{}, fmt.Errorf("LIBRARY_TABLE_NAME is not set")
+	}
+	if len(req.Data.Options) != 1 {
+		return discord.InteractionResponse{}, fmt.Errorf("invalid library command options")
+	}
+	sub := req.Data.Options[0]
+
+	store := library.NewStore(c.dynamodb, c.cfg.LibraryTableName)
+	today := clock.Today(clk)
+
+	var content string
+	switch sub.Name {
+	case "add":
+		dueOpt, _ := discord.FindOption(sub.Options, "due")
+		due, err := library.ParseDue(dueOpt.String(), today)
+		if err != nil {
+			return createLibraryWarning(fmt.Sprintf("⚠ %s は返却期限の形式ではありません (e.g. 2025-03-15, 3/15, 14d)", dueOpt.String())), nil
+		}
+		b := library.Book{Due: due, BorrowedBy: req.UserName()}
+		if opt, ok := discord.FindOption(sub.Options, "isbn"); ok {
+			b.ISBN, err = library.NormalizeISBN(opt.String())
+			if err != nil {
+				return createLibraryWarning(fmt.Sprintf("⚠ %s は ISBN の形式ではありません", opt.String())), nil
+			}
+		}
+		title, _ := discord.FindOption(sub.Options, "title")
+		b.Title = strings.TrimSpace(title.String())
+		if b.Title == "" {
+			b.Title, err = lookupBookTitle(ctx, c.cfg, b.ISBN)
+			if err != nil {
+				slog.Warn("failed to look up book title", slog.String("isbn", b.ISBN), slog.Any("error", err))
+				return createLibraryWarning("⚠ 書名を取得できませんでした、title を指定してください"), nil
+			}
+		}
+		added, err := store.Add(ctx, b, clk.Now())
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = fmt.Sprintf("📚 %s を登録しました", b)
+		if !added {
+			content = fmt.Sprintf("📚 %s は登録済みです", b.Title)
+		}
+	case "list":
+		books, err := store.List(ctx, clk.Location())
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = "📚 借りている本はありません"
+		if len(books) > 0 {
+			content = "📚 借りている本\n" + library.Format(books)
+		}
+	case "return":
+		item, _ := discord.FindOption(sub.Options, "item")
+		books, err := store.List(ctx, clk.Location())
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		target, ok := library.Find(books, item.String())
+		if !ok {
+			return createLibraryWarning(fmt.Sprintf("⚠ %s は登録されていません", item.String())), nil
+		}
+		if err := store.Return(ctx, target); err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = fmt.Sprintf("✅ %s を返却済みにしました", target.Title)
+	default:
+		return discord.InteractionResponse{}, fmt.Errorf("invalid library subcommand: %s", sub.Name)
+	}
+	slog.Info("handled library command", slog.String("subcommand", sub.Name))
+
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+		},
+	}, nil
+}
+
+// ISBN を指定していない場合と、書名の取得を無効にしている場合はエラーを返す
+func lookupBookTitle(ctx context.Context, cfg Config, isbn string) (string, error) {
+	if isbn == "" {
+		return "", errors.New("neither title nor isbn is specified")
+	}
+	if cfg.BookLookupURL == "" {
+		return "", errors.New("BOOK_LOOKUP_URL is not set")
+	}
+
+	return library.NewLookup(httpclient.Default, cfg.BookLookupURL).Title(ctx, isbn)
+}
+
+func createLibraryWarning(content string) discord.InteractionResponse {
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+			Flags:   discord.FlagEphemeral,
+		},
+	}
+}
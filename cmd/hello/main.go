@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mami0tsu/homeops/internal/authz"
+	"github.com/mami0tsu/homeops/internal/buildinfo"
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/config"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/errorreport"
+	"github.com/mami0tsu/homeops/internal/flags"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/logging"
+	"github.com/mami0tsu/homeops/internal/meal"
+	"github.com/mami0tsu/homeops/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/api/sheets/v4"
+)
+
+type Config struct {
+	DiscordPublicKey string `env:"DISCORD_PUBLIC_KEY,required" ssm:"discord"`
+
+	AckTableName string `env:"ACK_TABLE_NAME"` // remind のイベントの対応状況を記録するテーブル、/habit の習慣と記録も保存する
+
+	// remind のスプレッドシートの完了のチェックボックスを更新する
+	GoogleCredentials   string `env:"GOOGLE_CREDENTIALS" ssm:"google"`
+	GoogleSpreadsheetID string `env:"GOOGLE_SPREADSHEET_ID" ssm:"google"`
+
+	// /gomi で remind と同じゴミの収集日の規則を参照する
+	GomiSchedule string `env:"GOMI_SCHEDULE"`
+	GomiSheetTab string `env:"GOMI_SHEET_TAB"`
+	HolidaysURL  string `env:"HOLIDAYS_URL" envDefault:"https://holidays-jp.github.io/api/v1/date.json"`
+
+	ExpenseSheetTab string `env:"EXPENSE_SHEET_TAB" envDefault:"expense"` // /expense で支出を記録するシート
+	StockSheetTab   string `env:"STOCK_SHEET_TAB" envDefault:"stock"`     // /stock で食品の在庫を記録するシート
+
+	ShoppingTableName string `env:"SHOPPING_TABLE_NAME"` // /buy で買い物リストを記録するテーブル
+	TrackingTableName string `env:"TRACKING_TABLE_NAME"` // /track で追跡する荷物を記録するテーブル
+
+	PriceWatchTableName string `env:"PRICE_WATCH_TABLE_NAME"` // /price で価格を確認する商品を記録するテーブル
+
+	// /library で借りている本を記録する
+	LibraryTableName string `env:"LIBRARY_TABLE_NAME"`
+	BookLookupURL    string `env:"BOOK_LOOKUP_URL" envDefault:"https://api.openbd.jp/v1/get"` // ISBN から書名を取得する openBD の API、空の場合は取得しない
+
+	// remind が投稿した献立のボタンで献立を入れ替えて採用する、採用した献立の材料は SHOPPING_TABLE_NAME の買い物リストに追加する
+	MealTableName      string `env:"MEAL_TABLE_NAME"`
+	MealRecipeSheetTab string `env:"MEAL_RECIPE_SHEET_TAB"`
+	MealPlanSheetTab   string `env:"MEAL_PLAN_SHEET_TAB" envDefault:"meal_plan"` // 採用した献立を書き込むシート
+
+	// /remo で Nature Remo に登録した家電を操作する
+	RemoToken string `env:"REMO_TOKEN" ssm:"remo"`
+	RemoURL   string `env:"REMO_URL" envDefault:"https://api.nature.global"`
+
+	// /switchbot で SwitchBot のボットとプラグを操作する
+	SwitchBotToken  string `env:"SWITCHBOT_TOKEN" ssm:"switchbot"`
+	SwitchBotSecret string `env:"SWITCHBOT_SECRET" ssm:"switchbot"`
+	SwitchBotURL    string `env:"SWITCHBOT_URL" envDefault:"https://api.switch-bot.com"`
+
+	DeviceAllowedUsers authz.Allowlist `env:"DEVICE_ALLOWED_USERS"` // /remo と /switchbot で家電を操作できる Discord のユーザー ID、e.g. 123456789,987654321
+
+	SentryDSN string `env:"SENTRY_DSN" ssm:"sentry"` // 指定した場合はエラーを Sentry に通知する
+}
+
+// 呼び出しの間で再利用する設定とクライアント
+type clients struct {
+	cfg      Config
+	dynamodb *dynamodb.Client // テーブルを使う処理で共有する、テーブル名の設定がなければ呼び出さない
+	sheets   *sheets.Service  // GOOGLE_CREDENTIALS が設定されていない場合は nil
+}
+
+// SSM パラメータや認証情報の更新を反映するため、一定時間が経過したら次の呼び出しで作り直す
+const clientsTTL = 15 * time.Minute
+
+// コールドスタート時に作成し、以降の呼び出しで再利用する
+var cachedClients = config.NewCache(clientsTTL, newClients)
+
+// SSM パラメータを展開して設定を読み込み、DynamoDB とスプレッドシートのクライアントを作成する
+func newClients(ctx context.Context) (*clients, error) {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := dynamodb.NewClient(ctx, httpclient.Default)
+	if err != nil {
+		slog.Error("failed to init DynamoDB client", slog.Any("error", err))
+		return nil, err
+	}
+
+	var srv *sheets.Service
+	if cfg.GoogleCredentials != "" {
+		// 呼び出しが終わった後もトークンを更新できるように、呼び出しのキャンセルを引き継がない
+		if srv, err = newSheetsService(context.WithoutCancel(ctx), cfg); err != nil {
+			slog.Error("failed to init Sheets service", slog.Any("error", err))
+			return nil, err
+		}
+	}
+
+	return &clients{cfg: cfg, dynamodb: db, sheets: srv}, nil
+}
+
+func loadConfig(ctx context.Context) (Config, error) {
+	var cfg Config
+	if err := config.Load(ctx, "hello", &cfg, "flags"); err != nil {
+		slog.Error("failed to load config", slog.Any("error", err))
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// コールドスタート時に作成し、呼び出しごとにリクエスト ID を付けて使う
+var logger = slog.Default()
+
+func handleRequest(ctx context.Context, req events.APIGatewayProxyRequest) (resp events.APIGatewayProxyResponse, err error) {
+	slog.SetDefault(logging.WithLambdaContext(ctx, logger))
+	defer tracing.Flush(ctx)
+
+	ctx, span := tracing.Start(ctx, "hello")
+	defer func() {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		tracing.End(span, err)
+	}()
+
+	// 署名の検証に失敗したリクエストは通知しない
+	tags := map[string]string{"payload_hash": errorreport.PayloadHash([]byte(req.Body))}
+	defer func() {
+		if v := recover(); v != nil {
+			errorreport.FromEnv().CapturePanic(ctx, v, tags)
+			panic(v)
+		}
+	}()
+
+	c, err := cachedClients.Get(ctx)
+	if err != nil {
+		slog.Error("failed to init clients", slog.Any("error", err))
+		return createResponse(500, "internal server error"), err
+	}
+	ctx = flags.WithSet(ctx, flags.FromEnv())
+
+	slog.Info("received request", slog.Any("request", req))
+
+	// Discord による署名を検証する
+	if err := verifySignature(c.cfg, req); err != nil {
+		slog.Error("failed to verify request signature", slog.Any("error", err))
+		return createResponse(400, "invalid request"), err
+	}
+
+	request, err := discord.ParseInteraction(req.Body)
+	if err != nil {
+		slog.Error("failed to parse request body", slog.Any("error", err))
+		return createResponse(400, "invalid request"), err
+	}
+
+	span.SetAttributes(attribute.Int("interaction.type", int(request.Type)))
+	tags["interaction_type"] = strconv.Itoa(int(request.Type))
+	response, err := handleRequestType(ctx, c, clock.System(), request)
+	if err != nil {
+		slog.Error("failed to process request", slog.Any("error", err))
+		errorreport.FromEnv().Capture(ctx, err, tags)
+		return createResponse(400, "invalid request"), err
+	}
+
+	return createResponse(200, response), nil
+}
+
+func verifySignature(cfg Config, req events.APIGatewayProxyRequest) error {
+	return discord.VerifySignature(cfg.DiscordPublicKey, req.Headers["x-signature-ed25519"], req.Headers["x-signature-timestamp"], req.Body)
+}
+
+func handleRequestType(ctx context.Context, c *clients, clk clock.Clock, req discord.Interaction) (discord.InteractionResponse, error) {
+	switch req.Type {
+	case discord.InteractionPing:
+		return discord.InteractionResponse{Type: discord.ResponsePong}, nil
+	case discord.InteractionApplicationCommand:
+		return handleCommand(ctx, c, clk, req)
+	case discord.InteractionMessageComponent:
+		if authz.IsConfirm(req.Data.CustomID) {
+			return handleConfirm(ctx, c.cfg, req)
+		}
+		if meal.IsCustomID(req.Data.CustomID) {
+			return handleMeal(ctx, c, clk, req)
+		}
+		return handleComponent(ctx, c, clk, req)
+	default:
+		return discord.InteractionResponse{}, fmt.Errorf("unknown interaction type")
+	}
+}
+
+func handleCommand(ctx context.Context, c *clients, clk clock.Clock, req discord.Interaction) (discord.InteractionResponse, error) {
+	switch req.Data.Name {
+	case "hello":
+		return discord.InteractionResponse{
+			Type: discord.ResponseChannelMessageWithSource,
+			Data: &discord.InteractionResponseData{
+				Content: "hello, world!",
+			},
+		}, nil
+	case "version":
+		return discord.InteractionResponse{
+			Type: discord.ResponseChannelMessageWithSource,
+			Data: &discord.InteractionResponseData{
+				Content: buildinfo.Get().String(),
+				Flags:   discord.FlagEphemeral,
+			},
+		}, nil
+	case "gomi":
+		return handleGomi(ctx, c, clk)
+	case "expense":
+		return handleExpense(ctx, c, clk, req)
+	case "buy":
+		return handleBuy(ctx, c, clk, req)
+	case "track":
+		return handleTrack(ctx, c, clk, req)
+	case "habit":
+		return handleHabit(ctx, c, clk, req)
+	case "stock":
+		return handleStock(ctx, c, clk, req)
+	case "price":
+		return handlePrice(ctx, c, clk, req)
+	case "library":
+		return handleLibrary(ctx, c, clk, req)
+	case "remo":
+		return handleRemo(ctx, c.cfg, req)
+	case "switchbot":
+		return handleSwitchBot(ctx, c.cfg, req)
+	default:
+		return discord.InteractionResponse{
+			Type: discord.ResponseChannelMessageWithSource,
+			Data: &discord.InteractionResponseData{
+				Content: "unknown command",
+			},
+		}, nil
+	}
+}
+
+func createResponse(statusCode int, body any) events.APIGatewayProxyResponse {
+	respBody, err := json.Marshal(body)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 500,
+			Body:       "failed to create response",
+		}
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: statusCode,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		Body: string(respBody),
+	}
+}
+
+// Lambda 以外で実行する場合の待ち受けるアドレス、e.g. :8080
+func listenAddr() string {
+	if addr := os.Getenv("LISTEN_ADDR"); addr != "" {
+		return addr
+	}
+
+	return ":8080"
+}
+
+func main() {
+	if _, err := config.ApplyProfile(); err != nil {
+		slog.Error("failed to apply profile", slog.Any("error", err))
+		os.Exit(1)
+	}
+	logger = logging.NewFromEnv()
+	logger.Info("starting hello", buildinfo.Get().Attr())
+	tracing.Setup("hello")
+
+	// コールドスタート時に読み込んでおく、失敗した場合は最初の呼び出しで再度読み込む
+	slog.SetDefault(logger)
+	if _, err := cachedClients.Get(context.Background()); err != nil {
+		slog.Warn("failed to init clients on cold start", slog.Any("error", err))
+	}
+	// Lambda 以外で実行された場合は HTTP サーバーとして常駐する
+	if os.Getenv("AWS_LAMBDA_RUNTIME_API") == "" {
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		err := runServer(ctx, listenAddr())
+		stop()
+		tracing.Flush(context.Background())
+		if err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+	// Function URL で応答をストリーミングする場合は、ボタンの操作に先に応答する
+	if streaming, _ := strconv.ParseBool(os.Getenv("RESPONSE_STREAMING")); streaming {
+		lambda.Start(handleStreamingRequest)
+		return
+	}
+	lambda.Start(handleRequest)
+}
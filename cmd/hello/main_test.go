@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/authz"
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// スラッシュコマンドのリクエストを作成する、操作したユーザーの ID は "2"
+func command(name string, options ...discord.InteractionOption) discord.Interaction {
+	return discord.Interaction{
+		Type:   discord.InteractionApplicationCommand,
+		Data:   discord.InteractionData{Name: name, Options: options},
+		Member: &discord.Member{User: &discord.User{ID: "2", Username: "alice"}},
+	}
+}
+
+func subcommand(name string, options ...discord.InteractionOption) discord.InteractionOption {
+	return discord.InteractionOption{Name: name, Type: 1, Options: options}
+}
+
+func opt(name string, value any) discord.InteractionOption {
+	return discord.InteractionOption{Name: name, Value: value}
+}
+
+func TestHandleCommand(t *testing.T) {
+	// 2025/03/03 は月曜日
+	clk := clock.Fixed(time.Date(2025, 3, 3, 8, 0, 0, 0, clock.JST()))
+	s := testsupport.NewServer(t)
+	srv, err := s.SheetsService(context.Background())
+	require.NoError(t, err)
+	s.Sheets.SetValues("sheet-id", "stock", [][]any{
+		{"品名", "期限", "数量", "追加した人"},
+		{"牛乳", "2025-03-05", "2", "alice"},
+	})
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		req     discord.Interaction
+		want    string // 応答の内容に含まれる文字列
+		wantErr bool
+	}{
+		{name: "正常系/hello", req: command("hello"), want: "hello, world!"},
+		{name: "正常系/不明なコマンド", req: command("unknown"), want: "unknown command"},
+
+		// /gomi
+		{
+			name: "正常系/gomi で今日と明日のゴミを返す",
+			cfg:  Config{GomiSchedule: `[{"type": "燃えるゴミ", "weekdays": "mon,thu", "emoji": "🔥"}]`},
+			req:  command("gomi"),
+			want: "今日は 🔥 燃えるゴミ\n明日は なし",
+		},
+		{name: "異常系/gomi の規則が設定されていない場合", req: command("gomi"), wantErr: true},
+
+		// /expense
+		{
+			name: "異常系/expense の金額がない場合",
+			cfg:  Config{GoogleSpreadsheetID: "sheet-id"},
+			req:  command("expense", opt("category", "食費")),
+			want: "⚠ amount is required",
+		},
+		{
+			name: "異常系/expense の金額が整数でない場合",
+			cfg:  Config{GoogleSpreadsheetID: "sheet-id"},
+			req:  command("expense", opt("amount", 1.5), opt("category", "食費")),
+			want: "⚠ invalid amount: 1.5",
+		},
+		{
+			name:    "異常系/expense のスプレッドシートが設定されていない場合",
+			req:     command("expense", opt("amount", float64(500)), opt("category", "食費")),
+			wantErr: true,
+		},
+
+		// /buy
+		{name: "異常系/buy のテーブルが設定されていない場合", req: command("buy", subcommand("list")), wantErr: true},
+		{name: "異常系/buy のサブコマンドがない場合", cfg: Config{ShoppingTableName: "shopping"}, req: command("buy"), wantErr: true},
+
+		// /track
+		{
+			name: "異常系/track の追跡番号の形式が異なる場合",
+			cfg:  Config{TrackingTableName: "tracking"},
+			req:  command("track", subcommand("add", opt("number", "abc"), opt("carrier", "yamato"))),
+			want: "⚠ abc は追跡番号の形式ではありません",
+		},
+		{name: "異常系/track のテーブルが設定されていない場合", req: command("track", subcommand("list")), wantErr: true},
+		{name: "異常系/track のサブコマンドがない場合", cfg: Config{TrackingTableName: "tracking"}, req: command("track"), wantErr: true},
+
+		// /habit
+		{name: "異常系/habit のテーブルが設定されていない場合", req: command("habit", subcommand("list")), wantErr: true},
+		{name: "異常系/habit のサブコマンドがない場合", cfg: Config{AckTableName: "ack"}, req: command("habit"), wantErr: true},
+
+		// /stock
+		{
+			name: "正常系/stock で在庫の一覧を返す",
+			cfg:  Config{GoogleSpreadsheetID: "sheet-id", StockSheetTab: "stock"},
+			req:  command("stock", subcommand("list")),
+			want: "🥫 在庫\n- 牛乳 ×2 (3/5 まで)",
+		},
+		{
+			name: "異常系/stock の数量が 0 の場合",
+			cfg:  Config{GoogleSpreadsheetID: "sheet-id", StockSheetTab: "stock"},
+			req:  command("stock", subcommand("use", opt("name", "牛乳"), opt("quantity", float64(0)))),
+			want: "⚠ 0 は数量として使えません",
+		},
+		{
+			name: "異常系/stock の期限の形式が異なる場合",
+			cfg:  Config{GoogleSpreadsheetID: "sheet-id", StockSheetTab: "stock"},
+			req:  command("stock", subcommand("add", opt("name", "牛乳"), opt("expiry", "来週"))),
+			want: "⚠ 来週 は期限の形式ではありません",
+		},
+		{
+			name: "異常系/stock の在庫にない食品を使った場合",
+			cfg:  Config{GoogleSpreadsheetID: "sheet-id", StockSheetTab: "stock"},
+			req:  command("stock", subcommand("use", opt("name", "卵"))),
+			want: "⚠ 卵 は在庫にありません",
+		},
+		{name: "異常系/stock のサブコマンドがない場合", req: command("stock"), wantErr: true},
+
+		// /price
+		{
+			name: "異常系/price の URL の形式が異なる場合",
+			cfg:  Config{PriceWatchTableName: "price"},
+			req:  command("price", subcommand("add", opt("url", "not a url"), opt("target", float64(1000)))),
+			want: "⚠ not a url は URL または ASIN の形式ではありません",
+		},
+		{
+			name: "異常系/price の目標の価格が整数でない場合",
+			cfg:  Config{PriceWatchTableName: "price"},
+			req:  command("price", subcommand("add", opt("url", "https://example.com/item"), opt("target", 99.5))),
+			want: "⚠ 99.5 は目標の価格として使えません",
+		},
+		{name: "異常系/price のテーブルが設定されていない場合", req: command("price", subcommand("list")), wantErr: true},
+
+		// /library
+		{
+			name: "異常系/library の返却期限の形式が異なる場合",
+			cfg:  Config{LibraryTableName: "library"},
+			req:  command("library", subcommand("add", opt("due", "来週"), opt("title", "Go 言語"))),
+			want: "⚠ 来週 は返却期限の形式ではありません",
+		},
+		{
+			name: "異常系/library の ISBN の形式が異なる場合",
+			cfg:  Config{LibraryTableName: "library"},
+			req:  command("library", subcommand("add", opt("due", "14d"), opt("isbn", "123"))),
+			want: "⚠ 123 は ISBN の形式ではありません",
+		},
+		{name: "異常系/library のテーブルが設定されていない場合", req: command("library", subcommand("list")), wantErr: true},
+
+		// /remo
+		{
+			name: "異常系/remo を許可されていないユーザーが操作した場合",
+			cfg:  Config{RemoToken: "token", DeviceAllowedUsers: authz.Allowlist{"1"}},
+			req:  command("remo", subcommand("aircon", opt("state", "on"))),
+			want: "⛔ このコマンドを実行する権限がありません",
+		},
+		{name: "異常系/remo のトークンが設定されていない場合", req: command("remo", subcommand("aircon", opt("state", "on"))), wantErr: true},
+		{name: "異常系/remo のサブコマンドがない場合", cfg: Config{RemoToken: "token"}, req: command("remo"), wantErr: true},
+
+		// /switchbot
+		{
+			name: "異常系/switchbot を許可されていないユーザーが操作した場合",
+			cfg:  Config{SwitchBotToken: "token", DeviceAllowedUsers: authz.Allowlist{"1"}},
+			req:  command("switchbot", opt("command", "press"), opt("device", "照明")),
+			want: "⛔ このコマンドを実行する権限がありません",
+		},
+		{
+			name:    "異常系/switchbot のコマンドが不明な場合",
+			cfg:     Config{SwitchBotToken: "token", DeviceAllowedUsers: authz.Allowlist{"2"}},
+			req:     command("switchbot", opt("command", "explode"), opt("device", "照明")),
+			wantErr: true,
+		},
+		{name: "異常系/switchbot のトークンが設定されていない場合", req: command("switchbot", opt("command", "press")), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			tr := require.New(t)
+
+			c := &clients{cfg: tt.cfg, sheets: srv}
+			resp, err := handleCommand(context.Background(), c, clk, tt.req)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			tr.NoError(err)
+			ta.Equal(discord.ResponseChannelMessageWithSource, resp.Type)
+			tr.NotNil(resp.Data)
+			ta.Contains(resp.Data.Content, tt.want)
+		})
+	}
+}
+
+func TestHandleComponent(t *testing.T) {
+	clk := clock.Fixed(time.Date(2025, 3, 3, 8, 0, 0, 0, clock.JST()))
+	component := func(customID string) discord.Interaction {
+		return discord.Interaction{
+			Type: discord.InteractionMessageComponent,
+			Data: discord.InteractionData{CustomID: customID},
+		}
+	}
+
+	tests := []struct {
+		name string
+		cfg  Config
+		req  discord.Interaction
+	}{
+		{name: "異常系/ack の custom_id の形式が異なる場合", cfg: Config{AckTableName: "ack"}, req: component("ack:done:2025-03-03:0123456789ab")},
+		{name: "異常系/ack のテーブルが設定されていない場合", req: component("ack:done:20250303:0123456789ab")},
+		{name: "異常系/meal のテーブルが設定されていない場合", req: component("meal:accept:20250303")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handleRequestType(context.Background(), &clients{cfg: tt.cfg}, clk, tt.req)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/meal"
+	"github.com/mami0tsu/homeops/internal/shopping"
+)
+
+// remind が投稿した献立のボタンが押された場合に、料理を入れ替えるか献立を採用する
+// 採用した献立はシートに書き込み、材料を買い物リストに追加する
+func handleMeal(ctx context.Context, c *clients, clk clock.Clock, req discord.Interaction) (discord.InteractionResponse, error) {
+	if c.cfg.MealTableName == "" || c.cfg.MealRecipeSheetTab == "" {
+		return discord.InteractionResponse{}, fmt.Errorf("MEAL_TABLE_NAME and MEAL_RECIPE_SHEET_TAB are required to plan meals")
+	}
+	action, start, day, err := meal.ParseCustomID(req.Data.CustomID, clk.Location())
+	if err != nil {
+		return discord.InteractionResponse{}, err
+	}
+
+	store := meal.NewStore(c.dynamodb, c.cfg.MealTableName)
+	p, ok, err := store.Get(ctx, start)
+	if err != nil {
+		return discord.InteractionResponse{}, err
+	}
+	if !ok {
+		return createMealWarning("⚠ 献立の記録が見つかりません、次の提案を待ってください"), nil
+	}
+	// 他の人が採用した後に押された場合は、採用済みの表示に更新する
+	if p.Accepted {
+		return createMealResponse(p, ""), nil
+	}
+	recipes, err := readRecipes(ctx, c)
+	if err != nil {
+		return discord.InteractionResponse{}, err
+	}
+	if len(recipes) == 0 {
+		return createMealWarning("⚠ レシピが登録されていません"), nil
+	}
+
+	rng := rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	var note string
+	switch action {
+	case meal.ActionShuffle:
+		p = meal.Propose(recipes, start, rng)
+	case meal.ActionVeto:
+		if !p.Replace(day, recipes, rng) {
+			return createMealWarning("⚠ 入れ替えられる料理がありません"), nil
+		}
+	case meal.ActionAccept:
+		// 書き込みに失敗した場合に再度押せるように、採用済みにする前に書き込む
+		if err := appendMealPlan(ctx, c, p); err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		added, err := addIngredients(ctx, c.cfg, c.dynamodb, p.Ingredients(recipes), req.UserName(), clk.Now())
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		if _, err := store.Accept(ctx, p); err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		p.Accepted = true
+		note = fmt.Sprintf("✅ %s が採用しました", req.UserName())
+		if added > 0 {
+			note += fmt.Sprintf("、買い物リストに %d 品目を追加しました", added)
+		}
+	}
+	if action != meal.ActionAccept {
+		saved, err := store.Put(ctx, p)
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		// 入れ替えている間に採用された場合は、採用された献立を表示する
+		if !saved {
+			p, _, err = store.Get(ctx, start)
+			if err != nil {
+				return discord.InteractionResponse{}, err
+			}
+		}
+	}
+	slog.Info("handled meal plan", slog.String("action", action), slog.String("start", start.Format("2006-01-02")))
+
+	return createMealResponse(p, note), nil
+}
+
+// 買い物リストに材料を追加して、追加した品目の数を返す、リストにある品目は追加しない
+// 買い物リストを記録していない場合は追加しない
+func addIngredients(ctx context.Context, cfg Config, client *dynamodb.Client, items []string, user string, now time.Time) (int, error) {
+	if cfg.ShoppingTableName == "" {
+		return 0, nil
+	}
+	store := shopping.NewStore(client, cfg.ShoppingTableName)
+	var added int
+	for i, item := range items {
+		// 追加した日時をソートキーにするため、品目ごとにずらす
+		ok, err := store.Add(ctx, item, user, now.Add(time.Duration(i)*time.Microsecond))
+		if err != nil {
+			return added, err
+		}
+		if ok {
+			added++
+		}
+	}
+
+	return added, nil
+}
+
+// 押されたボタンのメッセージを献立で置き換える
+func createMealResponse(p meal.Plan, note string) discord.InteractionResponse {
+	content := p.Content()
+	if note != "" {
+		content += "\n\n" + note
+	}
+
+	return discord.InteractionResponse{
+		Type: discord.ResponseUpdateMessage,
+		Data: &discord.InteractionResponseData{
+			Content:    content,
+			Components: p.Components(),
+		},
+	}
+}
+
+func createMealWarning(content string) discord.InteractionResponse {
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+			Flags:   discord.FlagEphemeral,
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/ledger"
+	"github.com/mami0tsu/homeops/internal/pricewatch"
+)
+
+// /price add url:<URL または ASIN> target:<円> [name:<名前>] | /price list | /price remove item:<URL、ASIN または名前> で価格を確認する商品を操作する
+// 価格は remind の pricewatch モードで定期的に取得する
+func handlePrice(ctx context.Context, c *clients, clk clock.Clock, req discord.Interaction) (discord.InteractionResponse, error) {
+	if c.cfg.PriceWatchTableName == "" {
+		return discord.InteractionResponse{}, fmt.Errorf("PRICE_WATCH_TABLE_NAME is not set")
+	}
+	if len(req.Data.Options) != 1 {
+		return discord.InteractionResponse{}, fmt.Errorf("invalid price command options")
+	}
+	sub := req.Data.Options[0]
+
+	store := pricewatch.NewStore(c.dynamodb, c.cfg.PriceWatchTableName)
+
+	var content string
+	switch sub.Name {
+	case "add":
+		urlOpt, _ := discord.FindOption(sub.Options, "url")
+		u, err := pricewatch.NormalizeURL(urlOpt.String())
+		if err != nil {
+			return createPriceWarning(fmt.Sprintf("⚠ %s は URL または ASIN の形式ではありません", urlOpt.String())), nil
+		}
+		targetOpt, _ := discord.FindOption(sub.Options, "target")
+		target, ok := targetOpt.Number()
+		if !ok || target != math.Trunc(target) || target < 1 || target > math.MaxInt32 {
+			return createPriceWarning(fmt.Sprintf("⚠ %v は目標の価格として使えません", targetOpt.Value)), nil
+		}
+		name, _ := discord.FindOption(sub.Options, "name")
+		w := pricewatch.Watch{URL: u, Name: strings.TrimSpace(name.String()), Target: int(target), AddedBy: req.UserName(), AddedByID: req.UserID()}
+		added, err := store.Add(ctx, w, clk.Now())
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = fmt.Sprintf("🏷 %s を登録しました (目標 %s)", w.Label(), ledger.FormatYen(w.Target))
+		if !added {
+			content = fmt.Sprintf("🏷 %s は登録済みです", w.Label())
+		}
+	case "list":
+		watches, err := store.List(ctx)
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = "🏷 登録した商品はありません"
+		if len(watches) > 0 {
+			lines := []string{"🏷 登録した商品"}
+			for _, w := range watches {
+				price := "未取得"
+				if w.LastPrice != 0 {
+					price = ledger.FormatYen(w.LastPrice)
+				}
+				lines = append(lines, fmt.Sprintf("- %s: %s (目標 %s)", w.Label(), price, ledger.FormatYen(w.Target)))
+			}
+			content = strings.Join(lines, "\n")
+		}
+	case "remove":
+		item, _ := discord.FindOption(sub.Options, "item")
+		watches, err := store.List(ctx)
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		target, ok := pricewatch.Find(watches, item.String())
+		if !ok {
+			return createPriceWarning(fmt.Sprintf("⚠ %s は登録されていません", item.String())), nil
+		}
+		if err := store.Delete(ctx, target); err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = fmt.Sprintf("🗑 %s の登録を削除しました", target.Label())
+	default:
+		return discord.InteractionResponse{}, fmt.Errorf("invalid price subcommand: %s", sub.Name)
+	}
+	slog.Info("handled price command", slog.String("subcommand", sub.Name))
+
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+		},
+	}, nil
+}
+
+func createPriceWarning(content string) discord.InteractionResponse {
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+			Flags:   discord.FlagEphemeral,
+		},
+	}
+}
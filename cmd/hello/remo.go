@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/authz"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/remo"
+)
+
+// /remo aircon state:<on|off> [appliance:<家電の名前>] | /remo signal appliance:<家電の名前> signal:<信号の名前> で家電を操作する
+// 家電の操作は DEVICE_ALLOWED_USERS に含まれるユーザーのみに許可する
+func handleRemo(ctx context.Context, cfg Config, req discord.Interaction) (discord.InteractionResponse, error) {
+	if cfg.RemoToken == "" {
+		return discord.InteractionResponse{}, fmt.Errorf("REMO_TOKEN is not set")
+	}
+	if len(req.Data.Options) != 1 {
+		return discord.InteractionResponse{}, fmt.Errorf("invalid remo command options")
+	}
+	if err := cfg.DeviceAllowedUsers.Check(req); err != nil {
+		slog.Warn("denied device command", slog.String("command", "remo"), slog.String("user_id", req.UserID()))
+		return authz.ForbiddenResponse(), nil
+	}
+	sub := req.Data.Options[0]
+	name, _ := discord.FindOption(sub.Options, "appliance")
+
+	client := remo.NewClient(httpclient.Default, cfg.RemoURL, cfg.RemoToken)
+	appliances, err := client.Appliances(ctx)
+	if err != nil {
+		return discord.InteractionResponse{}, err
+	}
+	appliance, ok := findAppliance(appliances, name.String(), sub.Name == "aircon")
+	if !ok {
+		target := name.String()
+		if target == "" {
+			target = "エアコン"
+		}
+		return createDeviceWarning(fmt.Sprintf("⚠ %s が見つかりません", target)), nil
+	}
+
+	var content string
+	switch sub.Name {
+	case "aircon":
+		state, _ := discord.FindOption(sub.Options, "state")
+		on := state.String() == "on"
+		if err := client.SetAircon(ctx, appliance.ID, on); err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = fmt.Sprintf("❄️ %s の電源を切りました", appliance.Nickname)
+		if on {
+			content = fmt.Sprintf("❄️ %s の電源を入れました", appliance.Nickname)
+		}
+	case "signal":
+		signalName, _ := discord.FindOption(sub.Options, "signal")
+		signal, ok := appliance.FindSignal(signalName.String())
+		if !ok {
+			return createDeviceWarning(fmt.Sprintf("⚠ %s に %s の信号が登録されていません", appliance.Nickname, signalName.String())), nil
+		}
+		if err := client.SendSignal(ctx, signal.ID); err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = fmt.Sprintf("📡 %s に %s を送信しました", appliance.Nickname, signal.Name)
+	default:
+		return discord.InteractionResponse{}, fmt.Errorf("invalid remo subcommand: %s", sub.Name)
+	}
+	slog.Info("handled remo command", slog.String("subcommand", sub.Name), slog.String("appliance", appliance.Nickname), slog.String("user", req.UserName()))
+
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: fmt.Sprintf("%s (%s)", content, req.UserName()),
+		},
+	}, nil
+}
+
+// 名前が一致する家電を返す、エアコンの名前が未指定の場合は最初のエアコンを返す
+func findAppliance(appliances []remo.Appliance, name string, aircon bool) (remo.Appliance, bool) {
+	name = strings.TrimSpace(name)
+	for _, a := range appliances {
+		if aircon && !a.IsAircon() {
+			continue
+		}
+		if name == "" || strings.EqualFold(a.Nickname, name) {
+			return a, true
+		}
+	}
+
+	return remo.Appliance{}, false
+}
+
+// 操作できなかった理由を、操作したユーザーにのみ表示する
+func createDeviceWarning(content string) discord.InteractionResponse {
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+			Flags:   discord.FlagEphemeral,
+		},
+	}
+}
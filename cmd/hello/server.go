@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Discord が送るリクエストのボディの上限
+const maxRequestBody = 1 << 20
+
+// Lambda の代わりにコンテナや Kubernetes で常駐し、Interactions Endpoint を HTTP サーバーとして公開する
+// ctx がキャンセルされたら、処理中のリクエストを終えてから戻る
+func runServer(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /", serveInteraction)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("started server", slog.String("addr", addr))
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		slog.Error("failed to serve", slog.Any("error", err))
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("failed to shutdown server", slog.Any("error", err))
+		return err
+	}
+	slog.Info("stopped server")
+
+	return nil
+}
+
+// 署名の検証などを Lambda の場合と共通にするため、API Gateway のリクエストに変換して処理する
+func serveInteraction(w http.ResponseWriter, r *http.Request) {
+	resp, _ := handleRequest(r.Context(), toAPIGatewayRequest(r))
+	for k, v := range resp.Headers {
+		w.Header().Set(k, v)
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.WriteString(w, resp.Body)
+}
+
+func toAPIGatewayRequest(r *http.Request) events.APIGatewayProxyRequest {
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxRequestBody))
+	if err != nil {
+		// 読み込めなかったボディは署名の検証で拒否される
+		slog.Warn("failed to read request body", slog.Any("error", err))
+	}
+	// API Gateway と同じく、ヘッダーの名前は小文字で参照する
+	headers := make(map[string]string, len(r.Header))
+	for k, v := range r.Header {
+		headers[strings.ToLower(k)] = strings.Join(v, ",")
+	}
+
+	return events.APIGatewayProxyRequest{
+		HTTPMethod: r.Method,
+		Path:       r.URL.Path,
+		Headers:    headers,
+		Body:       string(body),
+	}
+}
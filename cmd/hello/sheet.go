@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/ledger"
+	"github.com/mami0tsu/homeops/internal/meal"
+	"github.com/mami0tsu/homeops/internal/stock"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+// remind のシートで完了のチェックボックスがある列
+const doneColumn = "N"
+
+// 指定した行の完了のチェックボックスをオンにする
+func markDone(ctx context.Context, c *clients, row int) error {
+	srv, err := c.sheetsService("update the spreadsheet")
+	if err != nil {
+		return err
+	}
+
+	cell := fmt.Sprintf("remind!%s%d", doneColumn, row)
+	vr := &sheets.ValueRange{Values: [][]interface{}{{true}}}
+	if _, err := srv.Spreadsheets.Values.Update(c.cfg.GoogleSpreadsheetID, cell, vr).ValueInputOption("USER_ENTERED").Context(ctx).Do(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// 支出を記録するシートの末尾に行を追加する
+func appendExpense(ctx context.Context, c *clients, e ledger.Entry) error {
+	srv, err := c.sheetsService("record expenses")
+	if err != nil {
+		return err
+	}
+
+	// 日付が文字列として扱われないように、入力した値として解釈させる
+	rng := fmt.Sprintf("%s!%s", c.cfg.ExpenseSheetTab, ledger.Columns)
+	vr := &sheets.ValueRange{Values: [][]interface{}{e.Row()}}
+	if _, err := srv.Spreadsheets.Values.Append(c.cfg.GoogleSpreadsheetID, rng, vr).ValueInputOption("USER_ENTERED").InsertDataOption("INSERT_ROWS").Context(ctx).Do(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// 在庫を記録するシートから、消費済みではない食品を読み込む
+func readStock(ctx context.Context, c *clients, loc *time.Location) ([]stock.Item, error) {
+	srv, err := c.sheetsService("manage the food stock")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := srv.Spreadsheets.Values.Get(c.cfg.GoogleSpreadsheetID, fmt.Sprintf("%s!%s", c.cfg.StockSheetTab, stock.Columns)).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return stock.ParseRows(resp.Values, loc)
+}
+
+// 在庫を記録するシートの末尾に行を追加する
+func appendStock(ctx context.Context, c *clients, item stock.Item) error {
+	srv, err := c.sheetsService("manage the food stock")
+	if err != nil {
+		return err
+	}
+
+	rng := fmt.Sprintf("%s!%s", c.cfg.StockSheetTab, stock.Columns)
+	vr := &sheets.ValueRange{Values: [][]interface{}{item.Values()}}
+	if _, err := srv.Spreadsheets.Values.Append(c.cfg.GoogleSpreadsheetID, rng, vr).ValueInputOption("USER_ENTERED").InsertDataOption("INSERT_ROWS").Context(ctx).Do(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// 食品の数量を更新する、0 の場合は行を空にする
+func updateStock(ctx context.Context, c *clients, item stock.Item) error {
+	srv, err := c.sheetsService("manage the food stock")
+	if err != nil {
+		return err
+	}
+
+	rng := fmt.Sprintf("%s!A%d:D%d", c.cfg.StockSheetTab, item.Row, item.Row)
+	if item.Quantity == 0 {
+		_, err = srv.Spreadsheets.Values.Clear(c.cfg.GoogleSpreadsheetID, rng, &sheets.ClearValuesRequest{}).Context(ctx).Do()
+		return err
+	}
+	vr := &sheets.ValueRange{Values: [][]interface{}{item.Values()}}
+	_, err = srv.Spreadsheets.Values.Update(c.cfg.GoogleSpreadsheetID, rng, vr).ValueInputOption("USER_ENTERED").Context(ctx).Do()
+
+	return err
+}
+
+// レシピを記録するシートから料理と材料を読み込む
+func readRecipes(ctx context.Context, c *clients) ([]meal.Recipe, error) {
+	srv, err := c.sheetsService("plan meals")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := srv.Spreadsheets.Values.Get(c.cfg.GoogleSpreadsheetID, fmt.Sprintf("%s!%s", c.cfg.MealRecipeSheetTab, meal.RecipeColumns)).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	return meal.ParseRecipes(resp.Values), nil
+}
+
+// 採用した献立を献立のシートの末尾に追加する
+func appendMealPlan(ctx context.Context, c *clients, p meal.Plan) error {
+	srv, err := c.sheetsService("plan meals")
+	if err != nil {
+		return err
+	}
+
+	rng := fmt.Sprintf("%s!%s", c.cfg.MealPlanSheetTab, meal.PlanColumns)
+	vr := &sheets.ValueRange{Values: p.Rows()}
+	if _, err := srv.Spreadsheets.Values.Append(c.cfg.GoogleSpreadsheetID, rng, vr).ValueInputOption("USER_ENTERED").InsertDataOption("INSERT_ROWS").Context(ctx).Do(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// スプレッドシートのクライアントを返す、purpose はクライアントがない場合のエラーに含める操作
+func (c *clients) sheetsService(purpose string) (*sheets.Service, error) {
+	if c.sheets == nil || c.cfg.GoogleSpreadsheetID == "" {
+		return nil, fmt.Errorf("GOOGLE_CREDENTIALS and GOOGLE_SPREADSHEET_ID are required to %s", purpose)
+	}
+
+	return c.sheets, nil
+}
+
+// 読み込みと書き込みの両方に使うため、書き込みの権限で作成する
+func newSheetsService(ctx context.Context, cfg Config) (*sheets.Service, error) {
+	jwt, err := google.JWTConfigFromJSON([]byte(cfg.GoogleCredentials), sheets.SpreadsheetsScope)
+	if err != nil {
+		return nil, err
+	}
+
+	return sheets.NewService(ctx, option.WithHTTPClient(jwt.Client(context.WithValue(ctx, oauth2.HTTPClient, httpclient.Default))))
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/stock"
+)
+
+// /stock add name:<品名> expiry:<期限> [quantity:<数量>] | /stock use name:<品名> [quantity:<数量>] | /stock list で食品の在庫を操作する
+// 在庫はスプレッドシートに記録し、期限が近い食品は remind の投稿で知らせる
+func handleStock(ctx context.Context, c *clients, clk clock.Clock, req discord.Interaction) (discord.InteractionResponse, error) {
+	if len(req.Data.Options) != 1 {
+		return discord.InteractionResponse{}, fmt.Errorf("invalid stock command options")
+	}
+	sub := req.Data.Options[0]
+	name, _ := discord.FindOption(sub.Options, "name")
+	quantity := 1
+	if opt, ok := discord.FindOption(sub.Options, "quantity"); ok {
+		n, ok := opt.Number()
+		if !ok || n != math.Trunc(n) || n < 1 || n > math.MaxInt32 {
+			return createStockWarning(fmt.Sprintf("⚠ %v は数量として使えません", opt.Value)), nil
+		}
+		quantity = int(n)
+	}
+	today := clock.Today(clk)
+
+	var content string
+	switch sub.Name {
+	case "add":
+		expiryOpt, _ := discord.FindOption(sub.Options, "expiry")
+		expiry, err := stock.ParseDate(expiryOpt.String(), today)
+		if err != nil {
+			return createStockWarning(fmt.Sprintf("⚠ %s は期限の形式ではありません (e.g. 2025-03-05, 3/5)", expiryOpt.String())), nil
+		}
+		item := stock.Item{Name: strings.TrimSpace(name.String()), Expiry: expiry, Quantity: quantity, AddedBy: req.UserName()}
+		if err := item.Validate(); err != nil {
+			return createStockWarning(fmt.Sprintf("⚠ %s", err)), nil
+		}
+		if err := appendStock(ctx, c, item); err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = fmt.Sprintf("🥫 %s を追加しました", item)
+	case "use":
+		items, err := readStock(ctx, c, clk.Location())
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		// 期限が近いものから消費する
+		item, ok := stock.Find(items, name.String())
+		if !ok {
+			return createStockWarning(fmt.Sprintf("⚠ %s は在庫にありません", name.String())), nil
+		}
+		used := min(quantity, item.Quantity)
+		item.Quantity -= used
+		if err := updateStock(ctx, c, item); err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = fmt.Sprintf("🍽 %s を %d 個使いました (残り %d)", item.Name, used, item.Quantity)
+	case "list":
+		items, err := readStock(ctx, c, clk.Location())
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = "🥫 在庫はありません"
+		if len(items) > 0 {
+			lines := []string{"🥫 在庫"}
+			stock.SortByExpiry(items)
+			for _, item := range items {
+				lines = append(lines, "- "+item.String())
+			}
+			content = strings.Join(lines, "\n")
+		}
+	default:
+		return discord.InteractionResponse{}, fmt.Errorf("invalid stock subcommand: %s", sub.Name)
+	}
+	slog.Info("handled stock command", slog.String("subcommand", sub.Name), slog.String("name", name.String()))
+
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+		},
+	}, nil
+}
+
+func createStockWarning(content string) discord.InteractionResponse {
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+			Flags:   discord.FlagEphemeral,
+		},
+	}
+}
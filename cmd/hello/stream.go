@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/errorreport"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/logging"
+)
+
+// Function URL の InvokeMode を RESPONSE_STREAM にした場合のハンドラー
+// ボタンの操作は DynamoDB とスプレッドシートの更新に時間がかかるため、処理中の応答を先に返してから処理し、
+// 処理が終わった後に応答の内容を設定する
+// それ以外のリクエストは handleRequest で処理した応答をそのまま返す
+func handleStreamingRequest(ctx context.Context, req events.LambdaFunctionURLRequest) (*events.LambdaFunctionURLStreamingResponse, error) {
+	proxyReq, err := toProxyRequest(req)
+	if err != nil {
+		slog.Error("failed to decode request body", slog.Any("error", err))
+		return streamResponse(createResponse(400, "invalid request")), err
+	}
+	interaction, err := discord.ParseInteraction(proxyReq.Body)
+	if err != nil || interaction.Type != discord.InteractionMessageComponent {
+		resp, err := handleRequest(ctx, proxyReq)
+		return streamResponse(resp), err
+	}
+
+	slog.SetDefault(logging.WithLambdaContext(ctx, logger))
+	c, err := cachedClients.Get(ctx)
+	if err != nil {
+		slog.Error("failed to init clients", slog.Any("error", err))
+		return streamResponse(createResponse(500, "internal server error")), err
+	}
+	if err := verifySignature(c.cfg, proxyReq); err != nil {
+		slog.Error("failed to verify request signature", slog.Any("error", err))
+		return streamResponse(createResponse(400, "invalid request")), err
+	}
+
+	pr, pw := io.Pipe()
+	go respondDeferred(ctx, c, interaction, pw)
+
+	return &events.LambdaFunctionURLStreamingResponse{
+		StatusCode: 200,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       pr,
+	}, nil
+}
+
+// 処理中の応答を書き込んだ後にボタンの操作を処理し、結果を応答の内容として設定する
+// 処理が終わるまで w を閉じないため、呼び出しは処理が終わるまで続く
+func respondDeferred(ctx context.Context, c *clients, req discord.Interaction, w *io.PipeWriter) {
+	defer w.Close()
+
+	// ハンドラーが返った後もキャンセルされないように、期限のみを引き継ぐ
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(30 * time.Second)
+	}
+	ctx, cancel := context.WithDeadline(context.WithoutCancel(ctx), deadline)
+	defer cancel()
+
+	deferred := discord.InteractionResponse{
+		Type: discord.ResponseDeferredChannelMessage,
+		Data: &discord.InteractionResponseData{Flags: discord.FlagEphemeral},
+	}
+	if err := json.NewEncoder(w).Encode(deferred); err != nil {
+		slog.Error("failed to write deferred response", slog.Any("error", err))
+		return
+	}
+
+	resp, err := handleComponent(ctx, c, clock.System(), req)
+	data := resp.Data
+	if err != nil {
+		slog.Error("failed to process request", slog.Any("error", err))
+		errorreport.FromEnv().Capture(ctx, err, map[string]string{"interaction_type": "3", "streaming": "true"})
+		data = &discord.InteractionResponseData{Content: "failed to process request", Flags: discord.FlagEphemeral}
+	}
+	if err := discord.NewClient(httpclient.Default, "").EditOriginalResponse(ctx, req.ApplicationID, req.Token, data); err != nil {
+		slog.Error("failed to edit deferred response", slog.Any("error", err))
+	}
+}
+
+// 署名の検証などを API Gateway の場合と共通にするため、Function URL のリクエストを変換する
+func toProxyRequest(req events.LambdaFunctionURLRequest) (events.APIGatewayProxyRequest, error) {
+	body := req.Body
+	if req.IsBase64Encoded {
+		b, err := base64.StdEncoding.DecodeString(req.Body)
+		if err != nil {
+			return events.APIGatewayProxyRequest{}, err
+		}
+		body = string(b)
+	}
+
+	return events.APIGatewayProxyRequest{
+		HTTPMethod: req.RequestContext.HTTP.Method,
+		Path:       req.RawPath,
+		Headers:    req.Headers,
+		Body:       body,
+	}, nil
+}
+
+func streamResponse(resp events.APIGatewayProxyResponse) *events.LambdaFunctionURLStreamingResponse {
+	return &events.LambdaFunctionURLStreamingResponse{
+		StatusCode: resp.StatusCode,
+		Headers:    resp.Headers,
+		Body:       strings.NewReader(resp.Body),
+	}
+}
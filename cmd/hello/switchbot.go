@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/authz"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/switchbot"
+)
+
+// 確認のボタンに含める操作、"switchbot:<コマンド>:<機器の ID>" の形式
+const switchBotAction = "switchbot"
+
+// /switchbot command:<press|turnOn|turnOff> device:<機器の名前> でボットとプラグを操作する
+// 誤って操作しないように、確認のボタンが押されてから実行する
+func handleSwitchBot(ctx context.Context, cfg Config, req discord.Interaction) (discord.InteractionResponse, error) {
+	if cfg.SwitchBotToken == "" {
+		return discord.InteractionResponse{}, fmt.Errorf("SWITCHBOT_TOKEN is not set")
+	}
+	if err := cfg.DeviceAllowedUsers.Check(req); err != nil {
+		slog.Warn("denied device command", slog.String("command", "switchbot"), slog.String("user_id", req.UserID()))
+		return authz.ForbiddenResponse(), nil
+	}
+	cmdOpt, _ := discord.FindOption(req.Data.Options, "command")
+	cmd, err := switchbot.ParseCommand(cmdOpt.String())
+	if err != nil {
+		return discord.InteractionResponse{}, err
+	}
+	name, _ := discord.FindOption(req.Data.Options, "device")
+
+	devices, err := newSwitchBotClient(cfg).Devices(ctx)
+	if err != nil {
+		return discord.InteractionResponse{}, err
+	}
+	d, ok := switchbot.FindDevice(devices, name.String())
+	if !ok || !d.IsController() {
+		return createDeviceWarning(fmt.Sprintf("⚠ 操作できる機器に %s が見つかりません", name.String())), nil
+	}
+
+	action := strings.Join([]string{switchBotAction, string(cmd), d.ID}, ":")
+	return authz.ConfirmResponse(fmt.Sprintf("🤖 %s で「%s」を実行しますか？", d.Name, cmd), action), nil
+}
+
+// 確認のボタンが押された場合に操作を実行する
+// ボタンは操作したユーザーにのみ表示されるが、押された時点でも権限を確認する
+func handleConfirm(ctx context.Context, cfg Config, req discord.Interaction) (discord.InteractionResponse, error) {
+	action, ok := authz.ParseConfirm(req.Data.CustomID)
+	if !ok {
+		return discord.InteractionResponse{}, fmt.Errorf("invalid custom id: %s", req.Data.CustomID)
+	}
+	if action == "" {
+		return authz.ResultResponse("キャンセルしました"), nil
+	}
+	if err := cfg.DeviceAllowedUsers.Check(req); err != nil {
+		slog.Warn("denied device command", slog.String("action", action), slog.String("user_id", req.UserID()))
+		return authz.ForbiddenResponse(), nil
+	}
+
+	parts := strings.Split(action, ":")
+	if len(parts) != 3 || parts[0] != switchBotAction {
+		return discord.InteractionResponse{}, fmt.Errorf("invalid custom id: %s", req.Data.CustomID)
+	}
+	cmd, err := switchbot.ParseCommand(parts[1])
+	if err != nil {
+		return discord.InteractionResponse{}, err
+	}
+	deviceID := parts[2]
+
+	if err := newSwitchBotClient(cfg).Send(ctx, deviceID, cmd); err != nil {
+		return discord.InteractionResponse{}, err
+	}
+	slog.Info("sent switchbot command", slog.String("command", string(cmd)), slog.String("device_id", deviceID), slog.String("user", req.UserName()))
+
+	return authz.ResultResponse(fmt.Sprintf("🤖 「%s」を実行しました", cmd)), nil
+}
+
+func newSwitchBotClient(cfg Config) *switchbot.Client {
+	return switchbot.NewClient(httpclient.Default, cfg.SwitchBotURL, cfg.SwitchBotToken, cfg.SwitchBotSecret)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/tracking"
+)
+
+// /track add number:<追跡番号> carrier:<配送業者> memo:<メモ> | /track list | /track remove number:<追跡番号またはメモ> で荷物を操作する
+// 配送状況は remind の tracking モードで定期的に取得する
+func handleTrack(ctx context.Context, c *clients, clk clock.Clock, req discord.Interaction) (discord.InteractionResponse, error) {
+	if c.cfg.TrackingTableName == "" {
+		return discord.InteractionResponse{}, fmt.Errorf("TRACKING_TABLE_NAME is not set")
+	}
+	if len(req.Data.Options) != 1 {
+		return discord.InteractionResponse{}, fmt.Errorf("invalid track command options")
+	}
+	sub := req.Data.Options[0]
+	number, _ := discord.FindOption(sub.Options, "number")
+
+	store := tracking.NewStore(c.dynamodb, c.cfg.TrackingTableName)
+
+	var content string
+	switch sub.Name {
+	case "add":
+		n, err := tracking.NormalizeNumber(number.String())
+		if err != nil {
+			return createTrackWarning(fmt.Sprintf("⚠ %s は追跡番号の形式ではありません", number.String())), nil
+		}
+		carrierOpt, _ := discord.FindOption(sub.Options, "carrier")
+		carrier, err := tracking.ParseCarrier(carrierOpt.String())
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		memo, _ := discord.FindOption(sub.Options, "memo")
+		p := tracking.Package{Carrier: carrier, Number: n, Memo: memo.String(), AddedBy: req.UserName()}
+		added, err := store.Add(ctx, p, clk.Now())
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = fmt.Sprintf("📦 %s を登録しました", p.Label())
+		if !added {
+			content = fmt.Sprintf("📦 %s は登録済みです", p.Label())
+		}
+	case "list":
+		packages, err := store.List(ctx)
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = "📦 追跡中の荷物はありません"
+		if len(packages) > 0 {
+			lines := []string{"📦 追跡中の荷物"}
+			for _, p := range packages {
+				status := p.Status
+				if status == "" {
+					status = "未取得"
+				}
+				lines = append(lines, fmt.Sprintf("- %s: %s", p.Label(), status))
+			}
+			content = strings.Join(lines, "\n")
+		}
+	case "remove":
+		packages, err := store.List(ctx)
+		if err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		target, ok := tracking.Find(packages, number.String())
+		if !ok {
+			return createTrackWarning(fmt.Sprintf("⚠ %s は登録されていません", number.String())), nil
+		}
+		if err := store.Delete(ctx, target); err != nil {
+			return discord.InteractionResponse{}, err
+		}
+		content = fmt.Sprintf("🗑 %s の追跡をやめました", target.Label())
+	default:
+		return discord.InteractionResponse{}, fmt.Errorf("invalid track subcommand: %s", sub.Name)
+	}
+	slog.Info("handled track command", slog.String("subcommand", sub.Name), slog.String("number", number.String()))
+
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+		},
+	}, nil
+}
+
+func createTrackWarning(content string) discord.InteractionResponse {
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+			Flags:   discord.FlagEphemeral,
+		},
+	}
+}
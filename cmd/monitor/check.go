@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/health"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/probe"
+)
+
+// 監視対象の状態と、投稿した状態を保存する
+type monitorStore interface {
+	Reports(ctx context.Context) ([]health.Report, error)
+	States(ctx context.Context) (map[string]State, error)
+	PutState(ctx context.Context, key string, s State) error
+}
+
+// 監視対象ごとの評価の結果
+type Check struct {
+	Key    string // e.g. host#nas
+	Name   string // e.g. nas
+	OK     bool
+	Detail string
+}
+
+// 前回から状態が変わった監視対象
+type Transition struct {
+	Check
+	Recovered bool
+	Downtime  time.Duration // 回復した場合の異常が続いた時間
+}
+
+// ハートビートが途絶えたホストと、ディスクに異常があるホストを異常とする
+func hostChecks(reports []health.Report, now time.Time, cfg *Config) []Check {
+	th := health.Thresholds{UsagePercent: cfg.DiskUsageThreshold, Temperature: cfg.DiskTemperatureThreshold}
+
+	checks := make([]Check, 0, len(reports))
+	for _, r := range reports {
+		c := Check{Key: "host#" + r.Host, Name: r.Host, OK: true}
+		if r.Missed(now, cfg.HeartbeatTimeout) {
+			c.OK = false
+			c.Detail = fmt.Sprintf("ハートビートが途絶えています (最終: %s)", r.Time.In(now.Location()).Format("2006-01-02 15:04"))
+		} else if problems := r.Problems(th); len(problems) > 0 {
+			c.OK = false
+			c.Detail = strings.Join(problems, "\n")
+		}
+		checks = append(checks, c)
+	}
+
+	return checks
+}
+
+// 応答しなかったサービスを異常とする
+func probeChecks(ctx context.Context, client *http.Client, targets probe.Targets) []Check {
+	checks := make([]Check, 0, len(targets))
+	for _, t := range targets {
+		r := probe.Check(ctx, client, t)
+		if !r.OK {
+			slog.Warn("probe failed", slog.String("target", t.Name), slog.String("detail", r.Detail))
+		}
+		checks = append(checks, Check{Key: "probe#" + t.Name, Name: t.Name, OK: r.OK, Detail: r.Detail})
+	}
+
+	return checks
+}
+
+// 前回の状態と比べて、異常になった監視対象と回復した監視対象を返す
+// 異常が続いている場合も、内容が変わった場合は返す
+// 初めて評価した監視対象は、異常な場合のみ返す
+func evaluateTransitions(prev map[string]State, checks []Check, now time.Time) ([]Transition, map[string]State) {
+	var transitions []Transition
+	next := map[string]State{}
+	for _, c := range checks {
+		p, seen := prev[c.Key]
+		s := State{OK: c.OK, Detail: c.Detail, Since: now}
+		switch {
+		case !seen:
+			if !c.OK {
+				transitions = append(transitions, Transition{Check: c})
+			}
+		case p.OK == c.OK:
+			s.Since = p.Since
+			if !c.OK && p.Detail != c.Detail {
+				transitions = append(transitions, Transition{Check: c})
+			}
+		case c.OK:
+			transitions = append(transitions, Transition{Check: c, Recovered: true, Downtime: now.Sub(p.Since)})
+		default:
+			transitions = append(transitions, Transition{Check: c})
+		}
+		if !seen || s != p {
+			next[c.Key] = s
+		}
+	}
+
+	return transitions, next
+}
+
+// ハートビートを送るホストと応答を確認するサービスを評価し、状態が変わったものを投稿する
+// 投稿に失敗した場合は状態を保存せず、次回に再度投稿する
+func runCheck(ctx context.Context, clk clock.Clock, cfg *Config, store monitorStore, client *http.Client, nc *notify.Config) error {
+	now := clk.Now()
+	reports, err := store.Reports(ctx)
+	if err != nil {
+		slog.Error("failed to get heartbeats", slog.Any("error", err))
+		return err
+	}
+	prev, err := store.States(ctx)
+	if err != nil {
+		slog.Error("failed to get states", slog.Any("error", err))
+		return err
+	}
+
+	checks := append(hostChecks(reports, now, cfg), probeChecks(ctx, client, cfg.ProbeTargets)...)
+	transitions, next := evaluateTransitions(prev, checks, now)
+	if len(transitions) > 0 {
+		embeds := make([]*discord.Embed, 0, len(transitions))
+		for _, t := range transitions {
+			embeds = append(embeds, createTransitionEmbed(t))
+		}
+		if err := notify.PostDiscordMessages(ctx, nc, discord.NewEmbedMessages(embeds)...); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	for key, s := range next {
+		if err := store.PutState(ctx, key, s); err != nil {
+			slog.Error("failed to put state", slog.String("key", key), slog.Any("error", err))
+			errs = append(errs, err)
+		}
+	}
+	slog.Info("succeeded to check", slog.Int("checks", len(checks)), slog.Int("transitions", len(transitions)))
+
+	return errors.Join(errs...)
+}
+
+func createTransitionEmbed(t Transition) *discord.Embed {
+	if t.Recovered {
+		embed := discord.NewEmbed(fmt.Sprintf("✅ %s が回復しました", t.Name), discord.ColorGreen)
+		embed.Description = fmt.Sprintf("停止時間: %s", formatDuration(t.Downtime))
+		return embed
+	}
+
+	embed := discord.NewEmbed(fmt.Sprintf("🚨 %s で異常を検知しました", t.Name), discord.ColorRed)
+	embed.Description = t.Detail
+
+	return embed
+}
+
+// e.g. 1日2時間3分、1 分未満は切り捨てる
+func formatDuration(d time.Duration) string {
+	d = d.Truncate(time.Minute)
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	var s string
+	if days > 0 {
+		s += fmt.Sprintf("%d日", days)
+	}
+	if hours > 0 {
+		s += fmt.Sprintf("%d時間", hours)
+	}
+	if minutes > 0 || s == "" {
+		s += fmt.Sprintf("%d分", minutes)
+	}
+
+	return s
+}
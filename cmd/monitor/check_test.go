@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/health"
+	"github.com/mami0tsu/homeops/internal/probe"
+	"github.com/mami0tsu/homeops/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostChecks(t *testing.T) {
+	ta := assert.New(t)
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, clock.JST())
+	failed := false
+	cfg := &Config{HeartbeatTimeout: 15 * time.Minute, DiskUsageThreshold: 90}
+	reports := []health.Report{
+		{Host: "nas", Time: now.Add(-time.Minute), Disks: []health.Disk{{Device: "/dev/sda", SMARTPassed: &failed}, {Device: "/", UsagePercent: 95}}},
+		{Host: "pi", Time: now.Add(-time.Hour)},
+		{Host: "server", Time: now.Add(-time.Minute)},
+	}
+
+	ta.Equal([]Check{
+		{Key: "host#nas", Name: "nas", Detail: "/dev/sda: SMART の自己診断に失敗しました\n/: 使用率が 95% です"},
+		{Key: "host#pi", Name: "pi", Detail: "ハートビートが途絶えています (最終: 2025-03-01 11:00)"},
+		{Key: "host#server", Name: "server", OK: true},
+	}, hostChecks(reports, now, cfg))
+}
+
+func TestEvaluateTransitions(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, clock.JST())
+	since := now.Add(-90 * time.Minute)
+
+	cases := []struct {
+		name            string
+		prev            map[string]State
+		check           Check
+		wantTransitions []Transition
+		wantNext        map[string]State
+	}{
+		{
+			name:     "正常系/初めて評価して正常な場合は投稿しない",
+			check:    Check{Key: "host#nas", OK: true},
+			wantNext: map[string]State{"host#nas": {OK: true, Since: now}},
+		},
+		{
+			name:            "正常系/初めて評価して異常な場合は投稿する",
+			check:           Check{Key: "host#nas", Detail: "down"},
+			wantTransitions: []Transition{{Check: Check{Key: "host#nas", Detail: "down"}}},
+			wantNext:        map[string]State{"host#nas": {Detail: "down", Since: now}},
+		},
+		{
+			name:            "正常系/異常になった場合",
+			prev:            map[string]State{"host#nas": {OK: true, Since: since}},
+			check:           Check{Key: "host#nas", Detail: "down"},
+			wantTransitions: []Transition{{Check: Check{Key: "host#nas", Detail: "down"}}},
+			wantNext:        map[string]State{"host#nas": {Detail: "down", Since: now}},
+		},
+		{
+			name:     "正常系/同じ異常が続いている場合は投稿しない",
+			prev:     map[string]State{"host#nas": {Detail: "down", Since: since}},
+			check:    Check{Key: "host#nas", Detail: "down"},
+			wantNext: map[string]State{},
+		},
+		{
+			name:            "正常系/異常の内容が変わった場合は投稿する",
+			prev:            map[string]State{"host#nas": {Detail: "disk", Since: since}},
+			check:           Check{Key: "host#nas", Detail: "disk\ntemperature"},
+			wantTransitions: []Transition{{Check: Check{Key: "host#nas", Detail: "disk\ntemperature"}}},
+			wantNext:        map[string]State{"host#nas": {Detail: "disk\ntemperature", Since: since}},
+		},
+		{
+			name:            "正常系/回復した場合は停止時間を含める",
+			prev:            map[string]State{"host#nas": {Detail: "down", Since: since}},
+			check:           Check{Key: "host#nas", OK: true},
+			wantTransitions: []Transition{{Check: Check{Key: "host#nas", OK: true}, Recovered: true, Downtime: 90 * time.Minute}},
+			wantNext:        map[string]State{"host#nas": {OK: true, Since: now}},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			transitions, next := evaluateTransitions(tt.prev, []Check{tt.check}, now)
+			ta.Equal(tt.wantTransitions, transitions)
+			ta.Equal(tt.wantNext, next)
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.Equal("0分", formatDuration(30*time.Second))
+	ta.Equal("1時間30分", formatDuration(90*time.Minute))
+	ta.Equal("1日2時間", formatDuration(26*time.Hour))
+}
+
+type fakeMonitorStore struct {
+	reports []health.Report
+	states  map[string]State
+}
+
+func (s *fakeMonitorStore) PutReport(ctx context.Context, r health.Report) error {
+	s.reports = append(s.reports, r)
+	return nil
+}
+
+func (s *fakeMonitorStore) Reports(ctx context.Context) ([]health.Report, error) {
+	return s.reports, nil
+}
+
+func (s *fakeMonitorStore) States(ctx context.Context) (map[string]State, error) {
+	return s.states, nil
+}
+
+func (s *fakeMonitorStore) PutState(ctx context.Context, key string, st State) error {
+	s.states[key] = st
+	return nil
+}
+
+func TestRunCheck(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	srv := testsupport.NewServer(t)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, clock.JST())
+	clk := clock.Fixed(now)
+	cfg := &Config{
+		DiscordBotName:   "monitor",
+		DiscordBotToken:  "token",
+		DiscordChannelID: "123",
+		HeartbeatTimeout: 15 * time.Minute,
+	}
+	nc := cfg.notifyConfig(clk)
+	nc.HTTPClient = srv.Client()
+	store := &fakeMonitorStore{
+		reports: []health.Report{{Host: "nas", Time: now.Add(-time.Minute)}, {Host: "pi", Time: now.Add(-time.Hour)}},
+		states: map[string]State{
+			"host#nas":     {Detail: "down", Since: now.Add(-2 * time.Hour)},
+			"probe#router": {OK: true, Since: now.Add(-24 * time.Hour)},
+		},
+	}
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer service.Close()
+	cfg.ProbeTargets = probe.Targets{{Name: "router", Type: probe.TypeHTTP, URL: service.URL}}
+
+	tr.NoError(runCheck(context.Background(), clk, cfg, store, service.Client(), nc))
+
+	msgs := srv.Discord.Messages()
+	tr.Len(msgs, 1)
+	tr.Len(msgs[0].Embeds, 3)
+	ta.Equal("✅ nas が回復しました", msgs[0].Embeds[0].Title)
+	ta.Equal("停止時間: 2時間", msgs[0].Embeds[0].Description)
+	ta.Equal("🚨 pi で異常を検知しました", msgs[0].Embeds[1].Title)
+	ta.Equal("🚨 router で異常を検知しました", msgs[0].Embeds[2].Title)
+	ta.Equal("returned status 502", msgs[0].Embeds[2].Description)
+	ta.True(store.states["host#nas"].OK)
+	ta.False(store.states["host#pi"].OK)
+	ta.Equal(now, store.states["probe#router"].Since)
+
+	// 状態が変わらなければ再度投稿しない
+	tr.NoError(runCheck(context.Background(), clk, cfg, store, service.Client(), nc))
+	ta.Len(srv.Discord.Messages(), 1)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/health"
+)
+
+// ハートビートなどのボディの上限
+const maxReportBody = 64 << 10
+
+// ハートビートを保存する
+type reportStore interface {
+	PutReport(ctx context.Context, r health.Report) error
+}
+
+// 自宅のマシンが Function URL の POST /heartbeat に送るハートビートを受け付ける
+func handleIngest(ctx context.Context, cfg *Config, clk clock.Clock, store reportStore, req events.LambdaFunctionURLRequest) events.LambdaFunctionURLResponse {
+	if req.RawPath != "/heartbeat" {
+		return createResponse(http.StatusNotFound)
+	}
+	body, status := readBody(cfg, req)
+	if status != 0 {
+		return createResponse(status)
+	}
+
+	var r health.Report
+	if err := json.Unmarshal(body, &r); err != nil {
+		slog.Warn("failed to parse heartbeat", slog.Any("error", err))
+		return createResponse(http.StatusBadRequest)
+	}
+	if err := r.Validate(); err != nil {
+		slog.Warn("invalid heartbeat", slog.Any("error", err))
+		return createResponse(http.StatusBadRequest)
+	}
+	// 送信元の時計がずれていても判定できるように、受け付けた時刻を記録する
+	r.Time = clk.Now()
+
+	if err := store.PutReport(ctx, r); err != nil {
+		slog.Error("failed to put heartbeat", slog.String("host", r.Host), slog.Any("error", err))
+		return createResponse(http.StatusInternalServerError)
+	}
+	slog.Info("received heartbeat", slog.String("host", r.Host), slog.Int("disks", len(r.Disks)))
+
+	return createResponse(http.StatusNoContent)
+}
+
+// メソッドとトークンを確認してボディを返す、受け付けない場合はステータスコードを返す
+// Authorization ヘッダーに "Bearer <MONITOR_TOKEN>" を指定する
+func readBody(cfg *Config, req events.LambdaFunctionURLRequest) ([]byte, int) {
+	if req.RequestContext.HTTP.Method != http.MethodPost {
+		return nil, http.StatusMethodNotAllowed
+	}
+	// Function URL のヘッダー名は小文字になる
+	token, ok := strings.CutPrefix(req.Headers["authorization"], "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(cfg.MonitorToken)) != 1 {
+		slog.Warn("rejected request with invalid token", slog.String("path", req.RawPath))
+		return nil, http.StatusUnauthorized
+	}
+
+	body := []byte(req.Body)
+	if req.IsBase64Encoded {
+		b, err := base64.StdEncoding.DecodeString(req.Body)
+		if err != nil {
+			return nil, http.StatusBadRequest
+		}
+		body = b
+	}
+	if len(body) > maxReportBody {
+		return nil, http.StatusRequestEntityTooLarge
+	}
+
+	return body, 0
+}
+
+func createResponse(status int) events.LambdaFunctionURLResponse {
+	return events.LambdaFunctionURLResponse{StatusCode: status}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/plant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleIngest(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, clock.JST())
+	body := `{"host": "nas", "time": "2000-01-01T00:00:00Z", "disks": [{"device": "/dev/sda", "smart_passed": true, "usage_percent": 40}]}`
+
+	request := func(method, path, auth, body string) events.LambdaFunctionURLRequest {
+		req := events.LambdaFunctionURLRequest{
+			RawPath: path,
+			Headers: map[string]string{"authorization": auth},
+			Body:    body,
+		}
+		req.RequestContext.HTTP.Method = method
+		return req
+	}
+	encoded := request(http.MethodPost, "/heartbeat", "Bearer secret", base64.StdEncoding.EncodeToString([]byte(body)))
+	encoded.IsBase64Encoded = true
+
+	cases := []struct {
+		name       string
+		req        events.LambdaFunctionURLRequest
+		wantStatus int
+		wantStored bool
+	}{
+		{name: "正常系/ハートビートを保存する", req: request(http.MethodPost, "/heartbeat", "Bearer secret", body), wantStatus: http.StatusNoContent, wantStored: true},
+		{name: "正常系/Base64 でエンコードされたボディ", req: encoded, wantStatus: http.StatusNoContent, wantStored: true},
+		{name: "異常系/トークンが異なる", req: request(http.MethodPost, "/heartbeat", "Bearer wrong", body), wantStatus: http.StatusUnauthorized},
+		{name: "異常系/トークンがない", req: request(http.MethodPost, "/heartbeat", "", body), wantStatus: http.StatusUnauthorized},
+		{name: "異常系/パスが異なる", req: request(http.MethodPost, "/", "Bearer secret", body), wantStatus: http.StatusNotFound},
+		{name: "異常系/メソッドが異なる", req: request(http.MethodGet, "/heartbeat", "Bearer secret", ""), wantStatus: http.StatusMethodNotAllowed},
+		{name: "異常系/ホスト名がない", req: request(http.MethodPost, "/heartbeat", "Bearer secret", `{"disks": []}`), wantStatus: http.StatusBadRequest},
+		{name: "異常系/JSON ではない", req: request(http.MethodPost, "/heartbeat", "Bearer secret", "ok"), wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			store := &fakeMonitorStore{}
+			resp := handleIngest(context.Background(), &Config{MonitorToken: "secret"}, clock.Fixed(now), store, tt.req)
+			ta.Equal(tt.wantStatus, resp.StatusCode)
+			if !tt.wantStored {
+				ta.Empty(store.reports)
+				return
+			}
+			if ta.Len(store.reports, 1) {
+				ta.Equal("nas", store.reports[0].Host)
+				// 送信元の時刻ではなく受け付けた時刻を記録する
+				ta.Equal(now, store.reports[0].Time)
+			}
+		})
+	}
+}
+
+type fakeMoistureStore struct {
+	readings []plant.Reading
+}
+
+func (s *fakeMoistureStore) Put(ctx context.Context, r plant.Reading) error {
+	s.readings = append(s.readings, r)
+	return nil
+}
+
+func TestHandleMoisture(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, clock.JST())
+	body := `{"sensor": "living-1", "moisture": 42.5}`
+	cfg := &Config{MonitorToken: "secret", PlantTableName: "plants"}
+
+	request := func(method, path, auth, body string) events.LambdaFunctionURLRequest {
+		req := events.LambdaFunctionURLRequest{
+			RawPath: path,
+			Headers: map[string]string{"authorization": auth},
+			Body:    body,
+		}
+		req.RequestContext.HTTP.Method = method
+		return req
+	}
+
+	cases := []struct {
+		name       string
+		cfg        *Config
+		req        events.LambdaFunctionURLRequest
+		wantStatus int
+		wantStored bool
+	}{
+		{name: "正常系/センサーの値を保存する", cfg: cfg, req: request(http.MethodPost, "/moisture", "Bearer secret", body), wantStatus: http.StatusNoContent, wantStored: true},
+		{name: "異常系/テーブルを指定していない", cfg: &Config{MonitorToken: "secret"}, req: request(http.MethodPost, "/moisture", "Bearer secret", body), wantStatus: http.StatusNotFound},
+		{name: "異常系/トークンが異なる", cfg: cfg, req: request(http.MethodPost, "/moisture", "Bearer wrong", body), wantStatus: http.StatusUnauthorized},
+		{name: "異常系/メソッドが異なる", cfg: cfg, req: request(http.MethodGet, "/moisture", "Bearer secret", ""), wantStatus: http.StatusMethodNotAllowed},
+		{name: "異常系/センサーの ID がない", cfg: cfg, req: request(http.MethodPost, "/moisture", "Bearer secret", `{"moisture": 42.5}`), wantStatus: http.StatusBadRequest},
+		{name: "異常系/値が範囲外", cfg: cfg, req: request(http.MethodPost, "/moisture", "Bearer secret", `{"sensor": "living-1", "moisture": 420}`), wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			store := &fakeMoistureStore{}
+			resp := handleMoisture(context.Background(), tt.cfg, clock.Fixed(now), store, tt.req)
+			ta.Equal(tt.wantStatus, resp.StatusCode)
+			if !tt.wantStored {
+				ta.Empty(store.readings)
+				return
+			}
+			ta.Equal([]plant.Reading{{Sensor: "living-1", Moisture: 42.5, MeasuredAt: now}}, store.readings)
+		})
+	}
+}
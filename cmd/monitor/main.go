@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mami0tsu/homeops/internal/buildinfo"
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/config"
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/errorreport"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/logging"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/plant"
+	"github.com/mami0tsu/homeops/internal/probe"
+	"github.com/mami0tsu/homeops/internal/tracing"
+)
+
+type Config struct {
+	DiscordBotName   string `env:"DISCORD_BOT_NAME,required" ssm:"discord"`
+	DiscordBotToken  string `env:"DISCORD_BOT_TOKEN,required" ssm:"discord"`
+	DiscordChannelID string `env:"DISCORD_CHANNEL_ID,required" ssm:"discord"`
+	DiscordUseThread bool   `env:"DISCORD_USE_THREAD" envDefault:"false" ssm:"discord"` // remind と同じ日付ごとのスレッドに投稿する
+
+	MonitorToken     string `env:"MONITOR_TOKEN,required" ssm:"monitor"` // ハートビートを送るマシンと共有するトークン
+	MonitorTableName string `env:"MONITOR_TABLE_NAME,required"`          // ハートビートと監視対象の状態を記録するテーブル
+
+	HeartbeatTimeout         time.Duration `env:"HEARTBEAT_TIMEOUT" envDefault:"15m"`         // 最後のハートビートから経過したら異常とする
+	DiskUsageThreshold       int           `env:"DISK_USAGE_THRESHOLD" envDefault:"90"`       // 使用率 (%) が超えたら異常とする、0 の場合は評価しない
+	DiskTemperatureThreshold int           `env:"DISK_TEMPERATURE_THRESHOLD" envDefault:"60"` // 温度 (℃) が超えたら異常とする、0 の場合は評価しない
+
+	ProbeTargets probe.Targets `env:"PROBE_TARGETS"` // 応答を確認するサービス、JSON で指定する
+
+	PlantTableName string `env:"PLANT_TABLE_NAME"` // 指定した場合は POST /moisture で土壌水分センサーの値を受け付ける、remind でも同じ値を指定する
+
+	SentryDSN string `env:"SENTRY_DSN" ssm:"sentry"` // 指定した場合はエラーを Sentry に通知する
+}
+
+func loadConfig(ctx context.Context) (*Config, error) {
+	var cfg Config
+	if err := config.Load(ctx, "monitor", &cfg); err != nil {
+		slog.Error("failed to load config", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+func (c *Config) notifyConfig(clk clock.Clock) *notify.Config {
+	return &notify.Config{
+		HTTPClient:       httpclient.Default,
+		Clock:            clk,
+		DiscordBotName:   c.DiscordBotName,
+		DiscordBotToken:  c.DiscordBotToken,
+		DiscordChannelID: c.DiscordChannelID,
+		DiscordUseThread: c.DiscordUseThread,
+	}
+}
+
+func newMonitorStore(ctx context.Context, cfg *Config) (*MonitorStore, error) {
+	client, err := dynamodb.NewClient(ctx, httpclient.Default)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMonitorStore(client, cfg.MonitorTableName), nil
+}
+
+// 呼び出しの間で再利用する設定とクライアント
+type clients struct {
+	cfg   *Config
+	store *MonitorStore
+}
+
+// SSM パラメータや認証情報の更新を反映するため、一定時間が経過したら次の呼び出しで作り直す
+const clientsTTL = 15 * time.Minute
+
+// 毎分のハートビートごとに SSM や Secrets Manager を呼び出さないように、呼び出しの間で再利用する
+var cachedClients = config.NewCache(clientsTTL, newClients)
+
+func newClients(ctx context.Context) (*clients, error) {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	store, err := newMonitorStore(ctx, cfg)
+	if err != nil {
+		slog.Error("failed to init DynamoDB client", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &clients{cfg: cfg, store: store}, nil
+}
+
+// コールドスタート時に作成し、呼び出しごとにリクエスト ID を付けて使う
+var logger = slog.Default()
+
+// Function URL から呼び出された場合はハートビートや土壌水分センサーの値を受け付け、EventBridge から呼び出された場合はホストとサービスを評価する
+func handleRequest(ctx context.Context, payload json.RawMessage) (any, error) {
+	slog.SetDefault(logging.WithLambdaContext(ctx, logger))
+	defer tracing.Flush(ctx)
+
+	defer func() {
+		if v := recover(); v != nil {
+			errorreport.FromEnv().CapturePanic(ctx, v, nil)
+			panic(v)
+		}
+	}()
+
+	// Function URL のリクエストには rawPath が含まれる
+	var kind struct {
+		RawPath *string `json:"rawPath"`
+	}
+	if err := json.Unmarshal(payload, &kind); err != nil {
+		slog.Error("failed to parse payload", slog.Any("error", err))
+		return nil, err
+	}
+
+	c, err := cachedClients.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cfg, store := c.cfg, c.store
+
+	if kind.RawPath != nil {
+		var req events.LambdaFunctionURLRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			slog.Error("failed to parse request", slog.Any("error", err))
+			return nil, err
+		}
+		if req.RawPath == moisturePath {
+			ctx, span := tracing.Start(ctx, "monitor.moisture")
+			resp := handleMoisture(ctx, cfg, clock.System(), plant.NewStore(store.client, cfg.PlantTableName), req)
+			tracing.End(span, nil)
+			return resp, nil
+		}
+		ctx, span := tracing.Start(ctx, "monitor.ingest")
+		resp := handleIngest(ctx, cfg, clock.System(), store, req)
+		tracing.End(span, nil)
+		return resp, nil
+	}
+
+	ctx, span := tracing.Start(ctx, "monitor.check")
+	err = runCheck(ctx, clock.System(), cfg, store, httpclient.Default, cfg.notifyConfig(clock.System()))
+	tracing.End(span, err)
+	if err != nil {
+		// 認証情報の期限切れなどに備えて、次の呼び出しでクライアントを作り直す
+		cachedClients.Invalidate()
+		errorreport.FromEnv().Capture(ctx, err, nil)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func main() {
+	if _, err := config.ApplyProfile(); err != nil {
+		slog.Error("failed to apply profile", slog.Any("error", err))
+		os.Exit(1)
+	}
+	logger = logging.NewFromEnv()
+	logger.Info("starting monitor", buildinfo.Get().Attr())
+	tracing.Setup("monitor")
+	slog.SetDefault(logger)
+
+	// Lambda 以外で実行された場合は 1 度だけ監視対象を評価する
+	if os.Getenv("AWS_LAMBDA_RUNTIME_API") == "" {
+		if _, err := handleRequest(context.Background(), json.RawMessage("{}")); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// コールドスタート時に作成しておく、失敗した場合は最初の呼び出しで再度作成する
+	if _, err := cachedClients.Get(context.Background()); err != nil {
+		slog.Warn("failed to init clients on cold start", slog.Any("error", err))
+	}
+
+	lambda.Start(handleRequest)
+}
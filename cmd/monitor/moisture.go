@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/plant"
+)
+
+const moisturePath = "/moisture"
+
+// 土壌水分センサーの値を保存する
+type moistureStore interface {
+	Put(ctx context.Context, r plant.Reading) error
+}
+
+// 土壌水分センサーが Function URL の POST /moisture に送る値を受け付ける、e.g. {"sensor": "living-1", "moisture": 42.5}
+// PLANT_TABLE_NAME を指定した場合のみ受け付け、remind は土が湿っている植物の水やりを知らせない
+func handleMoisture(ctx context.Context, cfg *Config, clk clock.Clock, store moistureStore, req events.LambdaFunctionURLRequest) events.LambdaFunctionURLResponse {
+	if req.RawPath != moisturePath || cfg.PlantTableName == "" {
+		return createResponse(http.StatusNotFound)
+	}
+	body, status := readBody(cfg, req)
+	if status != 0 {
+		return createResponse(status)
+	}
+
+	var r plant.Reading
+	if err := json.Unmarshal(body, &r); err != nil {
+		slog.Warn("failed to parse soil moisture", slog.Any("error", err))
+		return createResponse(http.StatusBadRequest)
+	}
+	if err := r.Validate(); err != nil {
+		slog.Warn("invalid soil moisture", slog.Any("error", err))
+		return createResponse(http.StatusBadRequest)
+	}
+	// センサーは時計を持たないことが多いため、受け付けた時刻を記録する
+	r.MeasuredAt = clk.Now()
+
+	if err := store.Put(ctx, r); err != nil {
+		slog.Error("failed to put soil moisture", slog.String("sensor", r.Sensor), slog.Any("error", err))
+		return createResponse(http.StatusInternalServerError)
+	}
+	slog.Info("received soil moisture", slog.String("sensor", r.Sensor), slog.Float64("moisture", r.Moisture))
+
+	return createResponse(http.StatusNoContent)
+}
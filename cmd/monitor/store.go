@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/health"
+)
+
+const (
+	reportPartitionKey = "heartbeat"
+	statePartitionKey  = "monitor"
+)
+
+// 監視対象ごとに最後に投稿した状態
+type State struct {
+	OK     bool
+	Detail string    // 異常の内容、変わった場合は再度投稿する
+	Since  time.Time // 現在の状態になった日時
+}
+
+// 受け付けたハートビートと監視対象の状態を DynamoDB に保存する
+// テーブルのキーは pk (パーティションキー) と sk (ソートキー) とし、
+// ハートビートは "heartbeat" に、監視対象の状態は "monitor" にそれぞれホスト名などのキーで保存する
+type MonitorStore struct {
+	client *dynamodb.Client
+	table  string
+}
+
+func NewMonitorStore(client *dynamodb.Client, table string) *MonitorStore {
+	return &MonitorStore{client: client, table: table}
+}
+
+// ホストごとに最新のハートビートのみを残す
+func (s *MonitorStore) PutReport(ctx context.Context, r health.Report) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	return s.client.PutItem(ctx, s.table, dynamodb.Item{
+		"pk":     dynamodb.S(reportPartitionKey),
+		"sk":     dynamodb.S(r.Host),
+		"report": dynamodb.S(string(b)),
+	}, "", nil)
+}
+
+func (s *MonitorStore) Reports(ctx context.Context) ([]health.Report, error) {
+	items, err := s.client.Query(ctx, s.table, "pk = :pk", dynamodb.Item{":pk": dynamodb.S(reportPartitionKey)})
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []health.Report
+	for _, item := range items {
+		var r health.Report
+		if err := json.Unmarshal([]byte(item.Str("report")), &r); err != nil {
+			// 壊れた項目があっても他のホストは監視する
+			slog.Warn("failed to parse heartbeat", slog.String("host", item.Str("sk")), slog.Any("error", err))
+			continue
+		}
+		reports = append(reports, r)
+	}
+
+	return reports, nil
+}
+
+func (s *MonitorStore) States(ctx context.Context) (map[string]State, error) {
+	items, err := s.client.Query(ctx, s.table, "pk = :pk", dynamodb.Item{":pk": dynamodb.S(statePartitionKey)})
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]State, len(items))
+	for _, item := range items {
+		states[item.Str("sk")] = State{
+			OK:     item.Str("state") == "ok",
+			Detail: item.Str("detail"),
+			Since:  time.Unix(item.Num("since"), 0),
+		}
+	}
+
+	return states, nil
+}
+
+func (s *MonitorStore) PutState(ctx context.Context, key string, st State) error {
+	state := "ng"
+	if st.OK {
+		state = "ok"
+	}
+
+	return s.client.PutItem(ctx, s.table, dynamodb.Item{
+		"pk":     dynamodb.S(statePartitionKey),
+		"sk":     dynamodb.S(key),
+		"state":  dynamodb.S(state),
+		"detail": dynamodb.S(st.Detail),
+		"since":  dynamodb.N(st.Since.Unix()),
+	}, "", nil)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/digest"
+	"github.com/mami0tsu/homeops/internal/remind"
+	"github.com/mami0tsu/homeops/internal/tracing"
+)
+
+// 出力先
+const (
+	outputJSON    = "json"    // 投稿内容を JSON で標準出力に書き出す
+	outputDiscord = "discord" // Discord に投稿する
+)
+
+func main() {
+	if err := remind.Setup(); err != nil {
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	err := run(ctx, os.Args[1:], os.Stdout, clock.System(), remind.NewLocal)
+	tracing.Flush(ctx)
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// 設定を読み込み、Lambda と同じ取得元と投稿先を作成する、テストでは偽の取得元と投稿先に差し替える
+type loader func(ctx context.Context, clk clock.Clock, today time.Time) (*remind.Local, error)
+
+// Lambda を呼び出さずに、ローカルで remind と同じ処理を実行する
+// e.g. go run . --date 2025-03-01 --output json
+//
+//	go run . --date 2025-03-01 --output discord --dry-run
+func run(ctx context.Context, args []string, w io.Writer, clk clock.Clock, load loader) error {
+	fs := flag.NewFlagSet("remind-cli", flag.ContinueOnError)
+	date := fs.String("date", "", "実行日として扱う日付、e.g. 2025-03-01")
+	dryRun := fs.Bool("dry-run", false, "Discord に投稿せずに、投稿するメッセージを JSON で書き出す")
+	output := fs.String("output", outputJSON, "出力先 (json, discord)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := validateOutput(*output); err != nil {
+		slog.Error("failed to parse flags", slog.Any("error", err))
+		return err
+	}
+	today, err := remind.ResolveToday(clk, *date)
+	if err != nil {
+		slog.Error("failed to parse date", slog.Any("error", err))
+		return err
+	}
+
+	l, err := load(ctx, clk, today)
+	if err != nil {
+		return err
+	}
+	ctx = l.Context(ctx)
+	d, err := digest.Build(ctx, digest.NewApp(l.Source), today, l.Dates, l.Options)
+	if err != nil {
+		slog.Error("failed to get any events", slog.Any("error", err))
+		return err
+	}
+	d.Failures = append(d.Failures, l.Failures...)
+
+	if *output == outputJSON {
+		return writeJSON(w, remind.NewOutput(d))
+	}
+	if *dryRun {
+		return writeJSON(w, l.Sink.Messages(d))
+	}
+
+	if _, err := digest.NewApp(l.Source, l.Sink).Post(ctx, d); err != nil {
+		slog.Error("failed to post events", slog.Any("error", err))
+		return err
+	}
+	if err := l.RegisterAcks(ctx, today, d); err != nil {
+		slog.Error("failed to register events", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func validateOutput(output string) error {
+	if output != outputJSON && output != outputDiscord {
+		return fmt.Errorf("invalid output: %s", output)
+	}
+
+	return nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
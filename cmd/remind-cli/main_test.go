@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/remind"
+	"github.com/mami0tsu/homeops/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateOutput(t *testing.T) {
+	cases := []struct {
+		name    string
+		output  string
+		wantErr bool
+	}{
+		{name: "正常系/JSON で書き出す", output: outputJSON},
+		{name: "正常系/Discord に投稿する", output: outputDiscord},
+		{name: "異常系/不明な出力先", output: "slack", wantErr: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOutput(tt.output)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// 日付ごとに、日付を名前に含むイベントを返す取得元
+type dateSource struct{}
+
+func (dateSource) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	return []event.Event{{Name: "Garbage " + t.Format("01/02"), Interval: event.Weekly}}, nil
+}
+
+// 偽の取得元と、テスト用のサーバーに投稿する Discord の投稿先を返す
+func fakeLoader(s *testsupport.Server, loaded *time.Time) loader {
+	return func(ctx context.Context, clk clock.Clock, today time.Time) (*remind.Local, error) {
+		*loaded = today
+		nc := &notify.Config{
+			HTTPClient:       s.Client(),
+			Clock:            clk,
+			DiscordBotName:   "remind",
+			DiscordBotToken:  "token",
+			DiscordChannelID: "123",
+			NoEventsMode:     notify.NoEventsMode("empty"),
+			Locale:           notify.Locale("en"),
+		}
+		return &remind.Local{
+			Source: dateSource{},
+			Sink:   notify.NewDiscordWebhookSink(nc, notify.FormatRich),
+			Dates:  []time.Time{today},
+		}, nil
+	}
+}
+
+func TestRunOutputJSON(t *testing.T) {
+	clk := clock.Fixed(time.Date(2025, 3, 1, 8, 0, 0, 0, clock.JST()))
+	tests := []struct {
+		name     string
+		args     []string
+		expected string // 投稿対象の日付
+	}{
+		{
+			name:     "正常系/日付を指定しない場合は実行した日を対象にする",
+			args:     []string{"--output", "json"},
+			expected: "2025-03-01",
+		},
+		{
+			name:     "正常系/日付を指定した場合はその日を対象にする",
+			args:     []string{"--date", "2025-03-05"},
+			expected: "2025-03-05",
+		},
+		{
+			name:     "正常系/JSON で書き出す場合は dry-run を指定しても投稿しない",
+			args:     []string{"--date", "2025-03-05", "--output", "json", "--dry-run"},
+			expected: "2025-03-05",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			tr := require.New(t)
+
+			s := testsupport.NewServer(t)
+			var loaded time.Time
+			var buf bytes.Buffer
+			tr.NoError(run(context.Background(), tt.args, &buf, clk, fakeLoader(s, &loaded)))
+
+			ta.Equal(tt.expected, loaded.Format("2006-01-02"))
+			ta.Equal(clock.JST().String(), loaded.Location().String())
+			var out struct {
+				Schedules []struct {
+					Date   string `json:"date"`
+					Events []struct {
+						Name     string `json:"name"`
+						Interval string `json:"interval"`
+					} `json:"events"`
+				} `json:"schedules"`
+			}
+			tr.NoError(json.Unmarshal(buf.Bytes(), &out))
+			tr.Len(out.Schedules, 1)
+			ta.Equal(tt.expected, out.Schedules[0].Date)
+			tr.Len(out.Schedules[0].Events, 1)
+			ta.Equal("Garbage "+loaded.Format("01/02"), out.Schedules[0].Events[0].Name)
+			ta.Equal("Weekly", out.Schedules[0].Events[0].Interval)
+			ta.Empty(s.Discord.Messages())
+		})
+	}
+}
+
+// dry-run では Discord に投稿せず、投稿するメッセージを書き出す
+func TestRunDryRun(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	s := testsupport.NewServer(t)
+	clk := clock.Fixed(time.Date(2025, 3, 1, 8, 0, 0, 0, clock.JST()))
+	var loaded time.Time
+	var buf bytes.Buffer
+	err := run(context.Background(), []string{"--date", "2025-03-01", "--output", "discord", "--dry-run"}, &buf, clk, fakeLoader(s, &loaded))
+	tr.NoError(err)
+
+	var messages []*discord.WebhookMessage
+	tr.NoError(json.Unmarshal(buf.Bytes(), &messages))
+	tr.Len(messages, 1)
+	tr.NotEmpty(messages[0].Embeds)
+	ta.Contains(messages[0].Embeds[0].Fields[0].Name, "Garbage 03/01")
+	ta.Empty(s.Discord.Messages())
+}
+
+func TestRunPostDiscord(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	s := testsupport.NewServer(t)
+	clk := clock.Fixed(time.Date(2025, 3, 1, 8, 0, 0, 0, clock.JST()))
+	var loaded time.Time
+	var buf bytes.Buffer
+	tr.NoError(run(context.Background(), []string{"--output", "discord"}, &buf, clk, fakeLoader(s, &loaded)))
+
+	ta.Empty(buf.String())
+	msgs := s.Discord.Messages()
+	tr.Len(msgs, 1)
+	tr.NotEmpty(msgs[0].Embeds)
+	ta.Contains(msgs[0].Embeds[0].Fields[0].Name, "Garbage 03/01")
+}
+
+func TestRunInvalidFlags(t *testing.T) {
+	clk := clock.Fixed(time.Date(2025, 3, 1, 8, 0, 0, 0, clock.JST()))
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "異常系/日付の形式が異なる場合", args: []string{"--date", "2025/03/01"}},
+		{name: "異常系/不明な出力先", args: []string{"--output", "slack"}},
+		{name: "異常系/削除したフラグ", args: []string{"--source", "sheet"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			called := false
+			load := func(ctx context.Context, clk clock.Clock, today time.Time) (*remind.Local, error) {
+				called = true
+				return nil, errors.New("must not be called")
+			}
+			var buf bytes.Buffer
+			ta.Error(run(context.Background(), tt.args, &buf, clk, load))
+			ta.False(called)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/remind"
+)
+
+func main() {
+	if err := remind.Setup(); err != nil {
+		os.Exit(1)
+	}
+
+	// Lambda 以外で実行された場合は、常駐して定期実行する
+	// ローカルで 1 回だけ実行する場合は cmd/remind-cli を使う
+	if os.Getenv("AWS_LAMBDA_RUNTIME_API") == "" {
+		if len(os.Args) < 2 || os.Args[1] != "serve" {
+			slog.Error("failed to start remind", slog.String("error", "serve is required outside Lambda, use remind-cli to run once locally"))
+			os.Exit(1)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		err := remind.Serve(ctx, clock.System())
+		stop()
+		if err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// コールドスタート時にクライアントを作成し、Lambda のハンドラーとして呼び出しを待ち受ける
+	remind.Warmup(context.Background())
+	lambda.Start(remind.HandleInvocation)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/weather"
+)
+
+// 地点ごとの天気予報を取得して投稿する
+// 取得できなかった地点があっても、取得できた地点は投稿してからエラーを返す
+func postBriefing(ctx context.Context, clk clock.Clock, cfg *Config, client *http.Client, nc *notify.Config) error {
+	var embeds []*discord.Embed
+	var errs []error
+	for _, loc := range cfg.Locations {
+		f, err := weather.FetchForecast(ctx, client, cfg.ForecastURL, cfg.ForecastModel, loc, clk.Location())
+		if err != nil {
+			slog.Error("failed to fetch forecast", slog.String("location", loc.Name), slog.Any("error", err))
+			errs = append(errs, fmt.Errorf("%s: %w", loc.Name, err))
+			continue
+		}
+		// 警報・注意報を取得できなかった場合も予報は投稿する
+		warnings, err := weather.FetchWarnings(ctx, client, cfg.WarningURL, loc)
+		if err != nil {
+			slog.Warn("failed to fetch warnings", slog.String("location", loc.Name), slog.Any("error", err))
+			errs = append(errs, fmt.Errorf("%s: %w", loc.Name, err))
+		}
+		embed := createBriefingEmbed(loc, f, warnings, err != nil)
+		// PM2.5 と花粉を取得できなかった場合も予報は投稿する
+		if cfg.AirQualityURL != "" {
+			a, err := weather.FetchAirQuality(ctx, client, cfg.AirQualityURL, loc, clk.Location())
+			if err != nil {
+				slog.Warn("failed to fetch air quality", slog.String("location", loc.Name), slog.Any("error", err))
+				errs = append(errs, fmt.Errorf("%s: %w", loc.Name, err))
+			}
+			addAirQualityFields(embed, a, err != nil, cfg.PM25Threshold, cfg.PollenThreshold)
+		}
+		embeds = append(embeds, embed)
+	}
+
+	messages := discord.NewEmbedMessages(embeds)
+	if err := notify.PostDiscordMessages(ctx, nc, messages...); err != nil {
+		return err
+	}
+	slog.Info("succeeded to post weather briefing", slog.Int("locations", len(embeds)))
+
+	return errors.Join(errs...)
+}
+
+// 警報が発表されている場合は赤、注意報のみの場合は橙にする
+func createBriefingEmbed(loc weather.Location, f weather.Forecast, warnings []string, warningsUnavailable bool) *discord.Embed {
+	color := discord.ColorBlue
+	for _, w := range warnings {
+		if strings.HasSuffix(w, "警報") {
+			color = discord.ColorRed
+			break
+		}
+		color = discord.ColorOrange
+	}
+
+	title := fmt.Sprintf("%s %s の天気 (%s)", weather.Describe(f.WeatherCode), loc.Name, f.Date.Format("1/2"))
+	embed := discord.NewEmbed(title, color)
+	embed.AddField("気温", fmt.Sprintf("最高 %.1f℃ / 最低 %.1f℃", f.MaxTemp, f.MinTemp))
+
+	blocks := make([]string, 0, 3)
+	for _, b := range f.Blocks() {
+		blocks = append(blocks, fmt.Sprintf("%02d-%02d時 %d%%", b.Start, b.End, b.Precipitation))
+	}
+	embed.AddField("降水確率", strings.Join(blocks, " / "))
+
+	index := f.LaundryIndex()
+	embed.AddField("洗濯指数", fmt.Sprintf("%d %s", index, weather.LaundryLabel(index)))
+
+	switch {
+	case warningsUnavailable:
+		embed.AddField("警報・注意報", "取得できませんでした")
+	case len(warnings) > 0:
+		embed.AddField("警報・注意報", "⚠️ "+strings.Join(warnings, "、"))
+	}
+
+	return embed
+}
+
+// PM2.5 と花粉の予報を追加し、どちらかが基準以上の場合はマスクを推奨する
+func addAirQualityFields(embed *discord.Embed, a weather.AirQuality, unavailable bool, pm25Threshold, pollenThreshold float64) {
+	if unavailable {
+		embed.AddField("PM2.5・花粉", "取得できませんでした")
+		return
+	}
+
+	values := []string{fmt.Sprintf("PM2.5 %.0fµg/m³ (%s)", a.PM25, weather.PM25Label(a.PM25))}
+	if a.Pollen != nil {
+		values = append(values, fmt.Sprintf("花粉 %.0f粒/m³ (%s)", *a.Pollen, weather.PollenLabel(*a.Pollen)))
+	}
+	embed.AddField("PM2.5・花粉", strings.Join(values, " / "))
+
+	if a.MaskRecommended(pm25Threshold, pollenThreshold) {
+		embed.AddField("注意", "😷 マスク推奨")
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/testsupport"
+	"github.com/mami0tsu/homeops/internal/weather"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBriefingEmbed(t *testing.T) {
+	jst := clock.JST()
+	f := weather.Forecast{
+		Date:        time.Date(2025, 6, 1, 0, 0, 0, 0, jst),
+		WeatherCode: 61,
+		MaxTemp:     24.1,
+		MinTemp:     17.5,
+		Hours: []weather.Hourly{
+			{Time: time.Date(2025, 6, 1, 9, 0, 0, 0, jst), Temperature: 20, Humidity: 90, Precipitation: 60},
+			{Time: time.Date(2025, 6, 1, 15, 0, 0, 0, jst), Precipitation: 80},
+		},
+	}
+	loc := weather.Location{Name: "自宅"}
+
+	cases := []struct {
+		name                string
+		warnings            []string
+		warningsUnavailable bool
+		wantColor           int
+		wantWarnings        string
+	}{
+		{
+			name:      "正常系/警報・注意報がない",
+			wantColor: discord.ColorBlue,
+		},
+		{
+			name:         "正常系/注意報のみ",
+			warnings:     []string{"雷注意報"},
+			wantColor:    discord.ColorOrange,
+			wantWarnings: "⚠️ 雷注意報",
+		},
+		{
+			name:         "正常系/警報を含む",
+			warnings:     []string{"雷注意報", "大雨警報"},
+			wantColor:    discord.ColorRed,
+			wantWarnings: "⚠️ 雷注意報、大雨警報",
+		},
+		{
+			name:                "異常系/警報・注意報を取得できなかった",
+			warningsUnavailable: true,
+			wantColor:           discord.ColorBlue,
+			wantWarnings:        "取得できませんでした",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			got := createBriefingEmbed(loc, f, tt.warnings, tt.warningsUnavailable)
+			ta.Equal("☔ 雨 自宅 の天気 (6/1)", got.Title)
+			ta.Equal(tt.wantColor, got.Color)
+			ta.Equal("最高 24.1℃ / 最低 17.5℃", got.Fields[0].Value)
+			ta.Equal("06-12時 60% / 12-18時 80% / 18-24時 0%", got.Fields[1].Value)
+			ta.Equal("10 部屋干し推奨", got.Fields[2].Value)
+			if tt.wantWarnings == "" {
+				ta.Len(got.Fields, 3)
+				return
+			}
+			ta.Equal(tt.wantWarnings, got.Fields[3].Value)
+		})
+	}
+}
+
+func TestAddAirQualityFields(t *testing.T) {
+	pollen := func(v float64) *float64 { return &v }
+	cases := []struct {
+		name        string
+		air         weather.AirQuality
+		unavailable bool
+		expected    []string
+	}{
+		{
+			name:     "正常系/基準未満",
+			air:      weather.AirQuality{PM25: 12.4, Pollen: pollen(8)},
+			expected: []string{"PM2.5 12µg/m³ (少ない) / 花粉 8粒/m³ (少ない)"},
+		},
+		{
+			name:     "正常系/花粉が基準以上",
+			air:      weather.AirQuality{PM25: 20, Pollen: pollen(64)},
+			expected: []string{"PM2.5 20µg/m³ (やや多い) / 花粉 64粒/m³ (多い)", "😷 マスク推奨"},
+		},
+		{
+			name:     "正常系/花粉の予報がない地域で PM2.5 が基準以上",
+			air:      weather.AirQuality{PM25: 41},
+			expected: []string{"PM2.5 41µg/m³ (多い)", "😷 マスク推奨"},
+		},
+		{
+			name:        "異常系/取得できなかった",
+			unavailable: true,
+			expected:    []string{"取得できませんでした"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			embed := discord.NewEmbed("自宅 の天気", discord.ColorBlue)
+			addAirQualityFields(embed, tt.air, tt.unavailable, 35, 50)
+
+			var values []string
+			for _, f := range embed.Fields {
+				values = append(values, f.Value)
+			}
+			assert.Equal(t, tt.expected, values)
+		})
+	}
+}
+
+// 天気予報を取得できなかった地点があっても、取得できた地点は投稿する
+func TestPostBriefing(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/forecast" && r.URL.Query().Get("latitude") == "35":
+			w.Write([]byte(`{"hourly": {"time": ["2025-06-01T09:00"], "precipitation_probability": [10]}, "daily": {"time": ["2025-06-01"], "weather_code": [0], "temperature_2m_max": [28], "temperature_2m_min": [19]}}`))
+		case r.URL.Path == "/air-quality" && r.URL.Query().Get("latitude") == "35":
+			w.Write([]byte(`{"hourly": {"time": ["2025-06-01T09:00"], "pm2_5": [48]}}`))
+		case r.URL.Path == "/warning/130000.json":
+			w.Write([]byte(`{"areaTypes": [{"areas": [{"code": "1310100", "warnings": [{"code": "14", "status": "発表"}]}]}]}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer api.Close()
+	s := testsupport.NewServer(t)
+
+	clk := clock.Fixed(time.Date(2025, 6, 1, 6, 0, 0, 0, clock.JST()))
+	cfg := &Config{
+		DiscordBotName:   "weather",
+		DiscordBotToken:  "token",
+		DiscordChannelID: "123",
+		Locations: weather.Locations{
+			{Name: "自宅", Latitude: 35, Longitude: 139, Office: "130000", Area: "1310100"},
+			{Name: "実家", Latitude: 34, Longitude: 135},
+		},
+		ForecastURL:     api.URL + "/forecast",
+		WarningURL:      api.URL + "/warning",
+		AirQualityURL:   api.URL + "/air-quality",
+		PM25Threshold:   35,
+		PollenThreshold: 50,
+	}
+	nc := cfg.notifyConfig(clk)
+	nc.HTTPClient = s.Client()
+
+	err := postBriefing(context.Background(), clk, cfg, api.Client(), nc)
+	ta.ErrorContains(err, "実家")
+
+	msgs := s.Discord.Messages()
+	tr.Len(msgs, 1)
+	tr.Len(msgs[0].Embeds, 1)
+	ta.Equal("☀️ 快晴 自宅 の天気 (6/1)", msgs[0].Embeds[0].Title)
+	ta.Equal("⚠️ 雷注意報", msgs[0].Embeds[0].Fields[3].Value)
+	ta.Equal("PM2.5 48µg/m³ (多い)", msgs[0].Embeds[0].Fields[4].Value)
+	ta.Equal("😷 マスク推奨", msgs[0].Embeds[0].Fields[5].Value)
+}
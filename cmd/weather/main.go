@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/mami0tsu/homeops/internal/buildinfo"
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/config"
+	"github.com/mami0tsu/homeops/internal/errorreport"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/logging"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/tracing"
+	"github.com/mami0tsu/homeops/internal/weather"
+)
+
+type Config struct {
+	DiscordBotName   string `env:"DISCORD_BOT_NAME,required" ssm:"discord"`
+	DiscordBotToken  string `env:"DISCORD_BOT_TOKEN,required" ssm:"discord"`
+	DiscordChannelID string `env:"DISCORD_CHANNEL_ID,required" ssm:"discord"`
+	DiscordUseThread bool   `env:"DISCORD_USE_THREAD" envDefault:"false" ssm:"discord"` // remind と同じ日付ごとのスレッドに投稿する
+
+	Locations     weather.Locations `env:"WEATHER_LOCATIONS,required"` // 天気予報を取得する地点、JSON で指定する
+	ForecastURL   string            `env:"FORECAST_URL" envDefault:"https://api.open-meteo.com/v1/forecast"`
+	ForecastModel string            `env:"FORECAST_MODEL" envDefault:"jma_seamless"` // 空の場合は Open-Meteo が選択する
+	WarningURL    string            `env:"WARNING_URL" envDefault:"https://www.jma.go.jp/bosai/warning/data/warning"`
+
+	AirQualityURL   string  `env:"AIR_QUALITY_URL" envDefault:"https://air-quality-api.open-meteo.com/v1/air-quality"` // 空の場合は PM2.5 と花粉を表示しない
+	PM25Threshold   float64 `env:"PM25_MASK_THRESHOLD" envDefault:"35"`                                                // マスクを推奨する PM2.5 の 1 時間値 (µg/m³)、既定は環境基準の 1 日平均値
+	PollenThreshold float64 `env:"POLLEN_MASK_THRESHOLD" envDefault:"50"`                                              // マスクを推奨する花粉の 1 時間値 (粒/m³)
+
+	SentryDSN string `env:"SENTRY_DSN" ssm:"sentry"` // 指定した場合はエラーを Sentry に通知する
+}
+
+func loadConfig(ctx context.Context) (*Config, error) {
+	var cfg Config
+	if err := config.Load(ctx, "weather", &cfg); err != nil {
+		slog.Error("failed to load config", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+func (c *Config) notifyConfig(clk clock.Clock) *notify.Config {
+	return &notify.Config{
+		HTTPClient:       httpclient.Default,
+		Clock:            clk,
+		DiscordBotName:   c.DiscordBotName,
+		DiscordBotToken:  c.DiscordBotToken,
+		DiscordChannelID: c.DiscordChannelID,
+		DiscordUseThread: c.DiscordUseThread,
+	}
+}
+
+// SSM パラメータの更新を反映するため、一定時間が経過したら次の呼び出しで読み込み直す
+const configTTL = 15 * time.Minute
+
+var cachedConfig = config.NewCache(configTTL, loadConfig)
+
+// コールドスタート時に作成し、呼び出しごとにリクエスト ID を付けて使う
+var logger = slog.Default()
+
+func handleRequest(ctx context.Context) error {
+	slog.SetDefault(logging.WithLambdaContext(ctx, logger))
+	defer tracing.Flush(ctx)
+
+	defer func() {
+		if v := recover(); v != nil {
+			errorreport.FromEnv().CapturePanic(ctx, v, nil)
+			panic(v)
+		}
+	}()
+
+	ctx, span := tracing.Start(ctx, "weather")
+	err := run(ctx, clock.System())
+	tracing.End(span, err)
+	if err != nil {
+		// SSM パラメータの更新に備えて、次の呼び出しで設定を読み込み直す
+		cachedConfig.Invalidate()
+		errorreport.FromEnv().Capture(ctx, err, nil)
+		return err
+	}
+
+	return nil
+}
+
+func run(ctx context.Context, clk clock.Clock) error {
+	cfg, err := cachedConfig.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	return postBriefing(ctx, clk, cfg, httpclient.Default, cfg.notifyConfig(clk))
+}
+
+func main() {
+	if _, err := config.ApplyProfile(); err != nil {
+		slog.Error("failed to apply profile", slog.Any("error", err))
+		os.Exit(1)
+	}
+	logger = logging.NewFromEnv()
+	logger.Info("starting weather", buildinfo.Get().Attr())
+	tracing.Setup("weather")
+	slog.SetDefault(logger)
+
+	// Lambda 以外で実行された場合は 1 度だけ投稿する
+	if os.Getenv("AWS_LAMBDA_RUNTIME_API") == "" {
+		ctx := context.Background()
+		err := run(ctx, clock.System())
+		tracing.Flush(ctx)
+		if err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// コールドスタート時に読み込んでおく、失敗した場合は最初の呼び出しで再度読み込む
+	if _, err := cachedConfig.Get(context.Background()); err != nil {
+		slog.Warn("failed to load config on cold start", slog.Any("error", err))
+	}
+
+	lambda.Start(handleRequest)
+}
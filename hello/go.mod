@@ -1,32 +0,0 @@
-module github.com/mami0tsu/homeops/hello
-
-go 1.23.1
-
-require (
-	github.com/aws/aws-lambda-go v1.47.0
-	github.com/caarlos0/env/v11 v11.3.1
-	github.com/handlename/ssmwrap/v2 v2.2.0
-)
-
-require (
-	github.com/aws/aws-sdk-go-v2 v1.30.1 // indirect
-	github.com/aws/aws-sdk-go-v2/config v1.27.23 // indirect
-	github.com/aws/aws-sdk-go-v2/credentials v1.17.23 // indirect
-	github.com/aws/aws-sdk-go-v2/feature/ec2/imds v1.16.9 // indirect
-	github.com/aws/aws-sdk-go-v2/internal/configsources v1.3.13 // indirect
-	github.com/aws/aws-sdk-go-v2/internal/endpoints/v2 v2.6.13 // indirect
-	github.com/aws/aws-sdk-go-v2/internal/ini v1.8.0 // indirect
-	github.com/aws/aws-sdk-go-v2/service/internal/accept-encoding v1.11.3 // indirect
-	github.com/aws/aws-sdk-go-v2/service/internal/presigned-url v1.11.15 // indirect
-	github.com/aws/aws-sdk-go-v2/service/ssm v1.52.1 // indirect
-	github.com/aws/aws-sdk-go-v2/service/sso v1.22.1 // indirect
-	github.com/aws/aws-sdk-go-v2/service/ssooidc v1.26.1 // indirect
-	github.com/aws/aws-sdk-go-v2/service/sts v1.30.1 // indirect
-	github.com/aws/smithy-go v1.20.3 // indirect
-	github.com/jmespath/go-jmespath v0.4.0 // indirect
-	github.com/lmittmann/tint v1.0.4 // indirect
-	github.com/mattn/go-isatty v0.0.20 // indirect
-	github.com/samber/lo v1.44.0 // indirect
-	golang.org/x/sys v0.6.0 // indirect
-	golang.org/x/text v0.16.0 // indirect
-)
@@ -0,0 +1,61 @@
+// Package authz は家電の操作など影響の大きいコマンドについて、実行できる Discord のユーザーを制限し、実行する前に確認する
+package authz
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/discord"
+)
+
+var ErrForbidden = errors.New("user is not allowed")
+
+// コマンドの実行を許可するユーザー ID の一覧
+// 空の場合は誰にも許可しない
+type Allowlist []string
+
+// e.g. 123456789,987654321
+func (a *Allowlist) UnmarshalText(text []byte) error {
+	var ids []string
+	for _, id := range strings.Split(string(text), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	*a = ids
+
+	return nil
+}
+
+func (a Allowlist) Allows(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	for _, id := range a {
+		if id == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 操作したユーザーが許可されていなければ ErrForbidden を返す
+func (a Allowlist) Check(i discord.Interaction) error {
+	if !a.Allows(i.UserID()) {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// 許可されていないユーザーに、本人にのみ見えるメッセージで応答する
+func ForbiddenResponse() discord.InteractionResponse {
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: "⛔ このコマンドを実行する権限がありません",
+			Flags:   discord.FlagEphemeral,
+		},
+	}
+}
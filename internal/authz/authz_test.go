@@ -0,0 +1,81 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowlistUnmarshalText(t *testing.T) {
+	ta := assert.New(t)
+
+	var a Allowlist
+	ta.NoError(a.UnmarshalText([]byte(" 123, 456,,")))
+	ta.Equal(Allowlist{"123", "456"}, a)
+}
+
+func TestAllowlistCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		allowlist   Allowlist
+		interaction discord.Interaction
+		err         error
+	}{
+		{
+			name:        "正常系/許可されたユーザーの場合",
+			allowlist:   Allowlist{"123"},
+			interaction: discord.Interaction{Member: &discord.Member{User: &discord.User{ID: "123"}}},
+		},
+		{
+			name:        "異常系/許可されていないユーザーの場合",
+			allowlist:   Allowlist{"123"},
+			interaction: discord.Interaction{User: &discord.User{ID: "456"}},
+			err:         ErrForbidden,
+		},
+		{
+			name:        "異常系/一覧が空の場合は誰にも許可しない",
+			interaction: discord.Interaction{User: &discord.User{ID: "123"}},
+			err:         ErrForbidden,
+		},
+		{
+			name:        "異常系/ユーザーが不明な場合",
+			allowlist:   Allowlist{""},
+			interaction: discord.Interaction{},
+			err:         ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.allowlist.Check(tt.interaction), tt.err)
+		})
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	ta := assert.New(t)
+
+	resp := ConfirmResponse("給湯器を押しますか？", "switchbot:press:b1")
+	ta.Equal(discord.FlagEphemeral, resp.Data.Flags)
+	buttons := resp.Data.Components[0].Components
+	ta.Len(buttons, 2)
+	ta.True(IsConfirm(buttons[0].CustomID))
+
+	action, ok := ParseConfirm(buttons[0].CustomID)
+	ta.True(ok)
+	ta.Equal("switchbot:press:b1", action)
+
+	action, ok = ParseConfirm(buttons[1].CustomID)
+	ta.True(ok)
+	ta.Empty(action)
+
+	_, ok = ParseConfirm("ack:done:20250301:abc")
+	ta.False(ok)
+
+	resp = ResultResponse("給湯器を押しました")
+	ta.Equal(discord.ResponseUpdateMessage, resp.Type)
+	ta.True(resp.Data.Components[0].Components[0].Disabled)
+	_, ok = ParseConfirm(resp.Data.Components[0].Components[0].CustomID)
+	ta.False(ok)
+}
@@ -0,0 +1,59 @@
+package authz
+
+import (
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/discord"
+)
+
+// 確認のボタンの custom_id は "confirm:<操作>" の形式とし、キャンセルは "confirm:cancel" とする
+// 操作の内容は呼び出し側で決める、e.g. "switchbot:press:0123456789ab"
+const (
+	confirmPrefix = "confirm:"
+	cancelAction  = "cancel"
+	doneAction    = "done"
+)
+
+// 確認のボタンが押された場合は true を返す
+func IsConfirm(customID string) bool {
+	return strings.HasPrefix(customID, confirmPrefix)
+}
+
+// 操作を実行する前に、実行とキャンセルのボタンを操作したユーザーにのみ表示する
+func ConfirmResponse(content, action string) discord.InteractionResponse {
+	return discord.InteractionResponse{
+		Type: discord.ResponseChannelMessageWithSource,
+		Data: &discord.InteractionResponseData{
+			Content: content,
+			Flags:   discord.FlagEphemeral,
+			Components: []discord.Component{discord.ActionRow(
+				discord.Button(discord.DangerButton, "実行", confirmPrefix+action),
+				discord.Button(discord.SecondaryButton, "キャンセル", confirmPrefix+cancelAction),
+			)},
+		},
+	}
+}
+
+// 確認のボタンの custom_id から操作を返す、キャンセルされた場合は空を返す
+func ParseConfirm(customID string) (string, bool) {
+	action, ok := strings.CutPrefix(customID, confirmPrefix)
+	if !ok || action == "" || action == doneAction {
+		return "", false
+	}
+	if action == cancelAction {
+		return "", true
+	}
+
+	return action, true
+}
+
+// 確認のメッセージを結果で置き換え、再度押せないようにボタンを無効にする
+func ResultResponse(content string) discord.InteractionResponse {
+	return discord.InteractionResponse{
+		Type: discord.ResponseUpdateMessage,
+		Data: &discord.InteractionResponseData{
+			Content:    content,
+			Components: []discord.Component{discord.ActionRow(discord.DisabledButton(discord.SecondaryButton, "完了", confirmPrefix+doneAction))},
+		},
+	}
+}
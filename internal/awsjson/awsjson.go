@@ -0,0 +1,103 @@
+// Package awsjson は AWS の JSON プロトコルの API を署名付きの HTTP リクエストで呼び出すクライアントを提供する
+// SDK のサービスごとのクライアントを追加せずに、必要な操作のみを呼び出すために使う
+package awsjson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/sigv4"
+)
+
+// AWS が返すエラー
+type Error struct {
+	Type    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Type, e.Message)
+}
+
+type Client struct {
+	HTTPClient  *http.Client
+	endpoint    string
+	target      string // X-Amz-Target の接頭辞、e.g. "secretsmanager"
+	contentType string // e.g. "application/x-amz-json-1.1"
+	signer      *sigv4.Signer
+}
+
+// 認証情報とリージョンは SDK の既定の方法で読み込む
+func New(ctx context.Context, service, target, contentType string) (*Client, error) {
+	return NewInRegion(ctx, service, "", target, contentType)
+}
+
+// 指定したリージョンのエンドポイントを呼び出す、Cost Explorer のように特定のリージョンのみで提供される API に使う
+// region が空の場合は SDK の既定の方法で読み込む
+func NewInRegion(ctx context.Context, service, region, target, contentType string) (*Client, error) {
+	signer, err := sigv4.New(ctx, service, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		HTTPClient:  httpclient.Default,
+		endpoint:    fmt.Sprintf("https://%s.%s.amazonaws.com/", service, signer.Region),
+		target:      target,
+		contentType: contentType,
+		signer:      signer,
+	}, nil
+}
+
+// operation を呼び出し、レスポンスを out に読み込む
+func (c *Client) Do(ctx context.Context, operation string, in any, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", c.contentType)
+	req.Header.Set("X-Amz-Target", c.target+"."+operation)
+
+	if err := c.signer.Sign(ctx, req, body); err != nil {
+		return err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(b, &e)
+		// "com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException" のように名前空間が付く場合がある
+		if _, typ, ok := strings.Cut(e.Type, "#"); ok {
+			e.Type = typ
+		}
+		return &Error{Type: e.Type, Message: e.Message}
+	}
+	if out == nil {
+		return nil
+	}
+
+	return json.Unmarshal(b, out)
+}
@@ -0,0 +1,76 @@
+// Package budget は Lambda の残りの実行時間を処理の段階ごとに配分する
+// 取得元が遅い場合でも、投稿に使う時間を残してタイムアウト前に投稿できるようにする
+package budget
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 処理の段階と、残りの時間を配分する比率
+type Phase struct {
+	Name   string
+	Weight float64
+}
+
+// 段階は指定した順に実行する前提で、開始した時点の残りの時間を、未実行の段階の比率で分ける
+// 前の段階が早く終われば、残りの段階に多くの時間を配分する
+type Budget struct {
+	deadline time.Time // ゼロ値の場合は期限なし
+	reserve  time.Duration
+	phases   []Phase
+	now      func() time.Time
+}
+
+// reserve は全ての段階の後に残しておく時間 (失敗の通知やトレースの送信など)
+// ctx に期限がなければ、各段階にも期限を設けない
+func New(ctx context.Context, reserve time.Duration, phases ...Phase) *Budget {
+	deadline, _ := ctx.Deadline()
+
+	return &Budget{
+		deadline: deadline,
+		reserve:  reserve,
+		phases:   phases,
+		now:      time.Now,
+	}
+}
+
+// 指定した段階に配分した時間で期限を設けた ctx を返す
+func (b *Budget) Start(ctx context.Context, name string) (context.Context, context.CancelFunc) {
+	d, ok := b.allot(name)
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeoutCause(ctx, d, fmt.Errorf("%s phase exceeded its time budget of %s", name, d))
+}
+
+// 期限がない場合と、未定義の段階の場合は false を返す
+func (b *Budget) allot(name string) (time.Duration, bool) {
+	i := b.index(name)
+	if b.deadline.IsZero() || i < 0 {
+		return 0, false
+	}
+
+	var total float64
+	for _, p := range b.phases[i:] {
+		total += p.Weight
+	}
+	available := b.deadline.Sub(b.now()) - b.reserve
+	if available <= 0 || total <= 0 {
+		return 0, true
+	}
+
+	return time.Duration(float64(available) * b.phases[i].Weight / total), true
+}
+
+func (b *Budget) index(name string) int {
+	for i, p := range b.phases {
+		if p.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,89 @@
+package budget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllot(t *testing.T) {
+	now := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	phases := []Phase{{Name: "fetch", Weight: 3}, {Name: "notify", Weight: 2}}
+
+	tests := []struct {
+		name     string
+		deadline time.Time
+		elapsed  time.Duration
+		phase    string
+		expected time.Duration
+		limited  bool
+	}{
+		{
+			name:     "正常系/最初の段階の場合",
+			deadline: now.Add(12 * time.Second),
+			phase:    "fetch",
+			expected: 6 * time.Second,
+			limited:  true,
+		},
+		{
+			name:     "正常系/前の段階が早く終わった場合",
+			deadline: now.Add(12 * time.Second),
+			elapsed:  2 * time.Second,
+			phase:    "notify",
+			expected: 8 * time.Second,
+			limited:  true,
+		},
+		{
+			name:     "正常系/残りの時間がない場合",
+			deadline: now.Add(12 * time.Second),
+			elapsed:  11 * time.Second,
+			phase:    "notify",
+			expected: 0,
+			limited:  true,
+		},
+		{
+			name:    "正常系/期限がない場合",
+			phase:   "fetch",
+			limited: false,
+		},
+		{
+			name:     "異常系/未定義の段階の場合",
+			deadline: now.Add(12 * time.Second),
+			phase:    "render",
+			limited:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			b := &Budget{
+				deadline: tt.deadline,
+				reserve:  2 * time.Second,
+				phases:   phases,
+				now:      func() time.Time { return now.Add(tt.elapsed) },
+			}
+			d, limited := b.allot(tt.phase)
+
+			ta.Equal(tt.limited, limited)
+			ta.Equal(tt.expected, d)
+		})
+	}
+}
+
+func TestStart(t *testing.T) {
+	ta := assert.New(t)
+
+	parent, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	b := New(parent, time.Minute, Phase{Name: "fetch", Weight: 1}, Phase{Name: "notify", Weight: 1})
+
+	ctx, cancel := b.Start(parent, "fetch")
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	ta.True(ok)
+	ta.WithinDuration(time.Now().Add(29*time.Minute+30*time.Second), deadline, time.Second)
+}
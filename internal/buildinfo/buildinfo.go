@@ -0,0 +1,73 @@
+// Package buildinfo はビルド時に埋め込んだバージョン情報を提供する
+// 値は -ldflags で設定する
+// e.g. go build -ldflags "-X github.com/mami0tsu/homeops/internal/buildinfo.Commit=abc1234"
+// 未設定の場合は、Go がバイナリに記録した VCS の情報を使う
+package buildinfo
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+)
+
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = "" // e.g. 2025-03-01T09:00:00+0900
+)
+
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.GoVersion = bi.GoVersion
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = s.Value
+			}
+		case "vcs.time":
+			if info.BuildTime == "" {
+				info.BuildTime = s.Value
+			}
+		}
+	}
+
+	return info
+}
+
+// e.g. "dev (abc1234, 2025-03-01T09:00:00+0900)"
+func (i Info) String() string {
+	commit := i.Commit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	if commit == "" {
+		commit = "unknown"
+	}
+	if i.BuildTime == "" {
+		return fmt.Sprintf("%s (%s)", i.Version, commit)
+	}
+
+	return fmt.Sprintf("%s (%s, %s)", i.Version, commit, i.BuildTime)
+}
+
+// ログに付けるための属性
+func (i Info) Attr() slog.Attr {
+	return slog.Group("build",
+		slog.String("version", i.Version),
+		slog.String("commit", i.Commit),
+		slog.String("build_time", i.BuildTime),
+		slog.String("go_version", i.GoVersion),
+	)
+}
@@ -0,0 +1,38 @@
+package buildinfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     Info
+		expected string
+	}{
+		{
+			name:     "正常系/全ての値が設定されている場合",
+			info:     Info{Version: "v1.2.0", Commit: "abc1234def5678", BuildTime: "2025-03-01T09:00:00+0900"},
+			expected: "v1.2.0 (abc1234, 2025-03-01T09:00:00+0900)",
+		},
+		{
+			name:     "正常系/ビルド日時が未設定の場合",
+			info:     Info{Version: "dev", Commit: "abc1234"},
+			expected: "dev (abc1234)",
+		},
+		{
+			name:     "正常系/コミットが不明な場合",
+			info:     Info{Version: "dev"},
+			expected: "dev (unknown)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			ta.Equal(tt.expected, tt.info.String())
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Package chore は繰り返す家事の担当者を、家族のメンバーで持ち回りに割り当てる
+package chore
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// 担当者の決め方
+type Strategy string
+
+const (
+	RoundRobin Strategy = "round-robin" // 順番に割り当てる
+	Weighted   Strategy = "weighted"    // 重みに比例した回数を割り当てる
+)
+
+// 家事を担当するメンバー
+type Member struct {
+	Name    string `json:"name"`    // e.g. alice
+	Mention string `json:"mention"` // e.g. <@123456789>、未指定の場合は名前を表示する
+	Weight  int    `json:"weight"`  // weighted の場合の重み、未指定の場合は 1
+}
+
+// 投稿に表示する担当者
+func (m Member) Display() string {
+	if m.Mention != "" {
+		return m.Mention
+	}
+
+	return m.Name
+}
+
+// 家事ごとの持ち回りの設定
+type Rotation struct {
+	Event    string   `json:"event"`    // 対象とするイベントの名前
+	Strategy Strategy `json:"strategy"` // 未指定の場合は round-robin
+	Members  []Member `json:"members"`
+}
+
+// e.g. [{"event": "お風呂掃除", "members": [{"name": "alice", "mention": "<@123>"}, {"name": "bob", "mention": "<@456>"}]}]
+type Rotations []Rotation
+
+func (r *Rotations) UnmarshalText(text []byte) error {
+	// Rotations のまま読み込むと UnmarshalText が再度呼び出されるため、スライスとして読み込む
+	var rotations []Rotation
+	if err := json.Unmarshal(text, &rotations); err != nil {
+		return fmt.Errorf("invalid chore rotations: %w", err)
+	}
+	for i := range rotations {
+		if err := rotations[i].init(); err != nil {
+			return fmt.Errorf("invalid chore rotations: %w", err)
+		}
+	}
+	*r = rotations
+
+	return nil
+}
+
+// 設定ファイル以外で定義した家事の持ち回りを作成する、既定値は CHORE_ROTATIONS と同じく補う
+func NewRotation(event string, strategy Strategy, members []Member) (Rotation, error) {
+	r := Rotation{Event: event, Strategy: strategy, Members: slices.Clone(members)}
+	if err := r.init(); err != nil {
+		return Rotation{}, err
+	}
+
+	return r, nil
+}
+
+func (r *Rotation) init() error {
+	if r.Event == "" {
+		return fmt.Errorf("event is blank")
+	}
+	switch r.Strategy {
+	case "":
+		r.Strategy = RoundRobin
+	case RoundRobin, Weighted:
+	default:
+		return fmt.Errorf("%s: invalid strategy: %s", r.Event, r.Strategy)
+	}
+	if len(r.Members) == 0 {
+		return fmt.Errorf("%s: members are empty", r.Event)
+	}
+	seen := map[string]bool{}
+	for i, m := range r.Members {
+		if m.Name == "" {
+			return fmt.Errorf("%s: member name is blank", r.Event)
+		}
+		if seen[m.Name] {
+			return fmt.Errorf("%s: duplicate member: %s", r.Event, m.Name)
+		}
+		seen[m.Name] = true
+		if m.Weight < 0 {
+			return fmt.Errorf("%s: invalid weight: %d", r.Event, m.Weight)
+		}
+		if m.Weight == 0 {
+			r.Members[i].Weight = 1
+		}
+	}
+
+	return nil
+}
+
+// イベントの名前に対応する持ち回りの設定を返す
+func (r Rotations) Find(name string) (Rotation, bool) {
+	i := slices.IndexFunc(r, func(v Rotation) bool { return v.Event == name })
+	if i < 0 {
+		return Rotation{}, false
+	}
+
+	return r[i], true
+}
+
+// これまでに割り当てた回数から、次の担当者を返す
+// 割り当てた回数を重みで割った値が次に最も小さくなるメンバーを選び、同じ場合は設定した順に選ぶ
+// round-robin の場合は重みを 1 として扱うため、設定した順に割り当てる
+func (r Rotation) Next(counts map[string]int) Member {
+	next := r.Members[0]
+	var best float64
+	for i, m := range r.Members {
+		w := m.Weight
+		if r.Strategy != Weighted || w == 0 {
+			w = 1
+		}
+		v := float64(counts[m.Name]+1) / float64(w)
+		if i == 0 || v < best {
+			next, best = m, v
+		}
+	}
+
+	return next
+}
+
+// 名前が一致するメンバーを返す、設定から削除されていれば false を返す
+func (r Rotation) Member(name string) (Member, bool) {
+	i := slices.IndexFunc(r.Members, func(m Member) bool { return m.Name == name })
+	if i < 0 {
+		return Member{}, false
+	}
+
+	return r.Members[i], true
+}
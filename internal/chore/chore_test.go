@@ -0,0 +1,149 @@
+package chore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotationsUnmarshalText(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		want    Rotations
+		wantErr bool
+	}{
+		{
+			name:  "正常系/既定値を補う",
+			input: `[{"event": "お風呂掃除", "members": [{"name": "alice", "mention": "<@1>"}, {"name": "bob"}]}]`,
+			want: Rotations{{
+				Event:    "お風呂掃除",
+				Strategy: RoundRobin,
+				Members:  []Member{{Name: "alice", Mention: "<@1>", Weight: 1}, {Name: "bob", Weight: 1}},
+			}},
+		},
+		{
+			name:  "正常系/重みを指定する",
+			input: `[{"event": "料理", "strategy": "weighted", "members": [{"name": "alice", "weight": 2}, {"name": "bob", "weight": 1}]}]`,
+			want: Rotations{{
+				Event:    "料理",
+				Strategy: Weighted,
+				Members:  []Member{{Name: "alice", Weight: 2}, {Name: "bob", Weight: 1}},
+			}},
+		},
+		{
+			name:    "異常系/イベントの名前が空",
+			input:   `[{"members": [{"name": "alice"}]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "異常系/メンバーが空",
+			input:   `[{"event": "お風呂掃除", "members": []}]`,
+			wantErr: true,
+		},
+		{
+			name:    "異常系/メンバーが重複している",
+			input:   `[{"event": "お風呂掃除", "members": [{"name": "alice"}, {"name": "alice"}]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "異常系/不明な決め方",
+			input:   `[{"event": "お風呂掃除", "strategy": "random", "members": [{"name": "alice"}]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "異常系/負の重み",
+			input:   `[{"event": "料理", "strategy": "weighted", "members": [{"name": "alice", "weight": -1}]}]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			var got Rotations
+			err := got.UnmarshalText([]byte(tt.input))
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestRotationNext(t *testing.T) {
+	cases := []struct {
+		name     string
+		rotation Rotation
+		want     []string
+	}{
+		{
+			name: "正常系/順番に割り当てる",
+			rotation: Rotation{Strategy: RoundRobin, Members: []Member{
+				{Name: "alice", Weight: 1}, {Name: "bob", Weight: 1}, {Name: "carol", Weight: 1},
+			}},
+			want: []string{"alice", "bob", "carol", "alice", "bob", "carol"},
+		},
+		{
+			name: "正常系/round-robin の場合は重みを無視する",
+			rotation: Rotation{Strategy: RoundRobin, Members: []Member{
+				{Name: "alice", Weight: 2}, {Name: "bob", Weight: 1},
+			}},
+			want: []string{"alice", "bob", "alice", "bob"},
+		},
+		{
+			name: "正常系/重みに比例した回数を割り当てる",
+			rotation: Rotation{Strategy: Weighted, Members: []Member{
+				{Name: "alice", Weight: 2}, {Name: "bob", Weight: 1},
+			}},
+			want: []string{"alice", "alice", "bob", "alice", "alice", "bob"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			counts := map[string]int{}
+			var got []string
+			for range tt.want {
+				m := tt.rotation.Next(counts)
+				counts[m.Name]++
+				got = append(got, m.Name)
+			}
+			ta.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestRotationsFind(t *testing.T) {
+	ta := assert.New(t)
+
+	r := Rotations{{Event: "お風呂掃除"}, {Event: "料理"}}
+	got, ok := r.Find("料理")
+	ta.True(ok)
+	ta.Equal("料理", got.Event)
+	_, ok = r.Find("洗濯")
+	ta.False(ok)
+}
+
+func TestMemberDisplay(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.Equal("<@1>", Member{Name: "alice", Mention: "<@1>"}.Display())
+	ta.Equal("bob", Member{Name: "bob"}.Display())
+}
+
+func TestNewRotation(t *testing.T) {
+	ta := assert.New(t)
+
+	r, err := NewRotation("ミケ: トイレ掃除", "", []Member{{Name: "alice"}, {Name: "bob", Weight: 2}})
+	ta.NoError(err)
+	ta.Equal(Rotation{Event: "ミケ: トイレ掃除", Strategy: RoundRobin, Members: []Member{{Name: "alice", Weight: 1}, {Name: "bob", Weight: 2}}}, r)
+
+	_, err = NewRotation("ミケ: トイレ掃除", RoundRobin, nil)
+	ta.Error(err)
+}
@@ -0,0 +1,115 @@
+// Package civic は確定申告や住民税、自動車税など、毎年決まった時期にある税金や公共料金の期限を提供する
+// 世帯に当てはまるものをキーで指定して使う
+package civic
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// 毎年決まった月にある期限
+type Deadline struct {
+	Name        string
+	Interval    event.Interval // 表示用
+	Months      []time.Month
+	Day         int  // 0 の場合は月末
+	Shift       bool // 土日・祝日に当たる場合は翌営業日にする
+	Remind      bool // 期限の前にも通知する
+	Emoji       string
+	Description string
+	URL         string
+}
+
+// 世帯に当てはまるものを指定するキーごとの期限
+// 住民税や自動車税の期限は自治体によって異なる場合があるため、一般的な期限を使う
+var deadlines = map[string][]Deadline{
+	// 所得税の確定申告、申告と納付の期限は 3/15
+	"kakutei": {
+		{Name: "確定申告の受付開始", Interval: event.Yearly, Months: []time.Month{time.February}, Day: 16, Shift: true, Emoji: "🧾", Description: "期限は 3/15 (土日・祝日の場合は翌営業日)", URL: "https://www.keisan.nta.go.jp/"},
+		{Name: "確定申告の期限", Interval: event.Yearly, Months: []time.Month{time.March}, Day: 15, Shift: true, Remind: true, Emoji: "🧾", Description: "所得税の申告と納付の期限", URL: "https://www.keisan.nta.go.jp/"},
+	},
+	// 住民税の普通徴収、給与から天引きされる特別徴収の場合は不要
+	"juminzei": {
+		{Name: "住民税の納付期限", Interval: event.Quarterly, Months: []time.Month{time.June, time.August, time.October, time.January}, Shift: true, Remind: true, Emoji: "🏛️", Description: "6 月 (第 1 期)、8 月 (第 2 期)、10 月 (第 3 期)、1 月 (第 4 期) の末日、口座振替の場合は残高を確認する"},
+	},
+	// 会社員の年末調整、提出の期限は勤務先の案内を確認する
+	"nenmatsu": {
+		{Name: "年末調整の書類の準備", Interval: event.Yearly, Months: []time.Month{time.November}, Day: 1, Emoji: "📝", Description: "保険料控除証明書や扶養控除等申告書を揃えて、勤務先の期限までに提出する"},
+	},
+	// NHK の受信料、2 か月払の場合は偶数月、6 か月前払の場合は 4 月と 10 月、12 か月前払の場合は 4 月に支払う
+	"nhk": {
+		{Name: "NHK 受信料の支払い (2 か月払)", Interval: event.Monthly, Months: []time.Month{time.February, time.April, time.June, time.August, time.October, time.December}, Remind: true, Emoji: "📺"},
+	},
+	"nhk-6": {
+		{Name: "NHK 受信料の支払い (6 か月前払)", Interval: event.Semiannual, Months: []time.Month{time.April, time.October}, Remind: true, Emoji: "📺"},
+	},
+	"nhk-12": {
+		{Name: "NHK 受信料の支払い (12 か月前払)", Interval: event.Yearly, Months: []time.Month{time.April}, Remind: true, Emoji: "📺"},
+	},
+	// 4/1 時点の所有者に課税される、納税通知書は 5 月上旬に届く
+	"jidosha": {
+		{Name: "自動車税 (種別割) の納付期限", Interval: event.Yearly, Months: []time.Month{time.May}, Shift: true, Remind: true, Emoji: "🚗", Description: "都道府県から届く納税通知書で納付する"},
+	},
+	"keijidosha": {
+		{Name: "軽自動車税 (種別割) の納付期限", Interval: event.Yearly, Months: []time.Month{time.May}, Shift: true, Remind: true, Emoji: "🚙", Description: "市区町村から届く納税通知書で納付する"},
+	},
+}
+
+// 指定できるキーを辞書順に返す
+func Keys() []string {
+	keys := make([]string, 0, len(deadlines))
+	for k := range deadlines {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// 指定したキーがすべて存在することを確認する
+func Validate(keys []string) error {
+	for _, k := range keys {
+		if _, ok := deadlines[normalizeKey(k)]; !ok {
+			return fmt.Errorf("invalid civic deadline: %s (available: %s)", k, strings.Join(Keys(), ", "))
+		}
+	}
+
+	return nil
+}
+
+func normalizeKey(k string) string {
+	return strings.ToLower(strings.TrimSpace(k))
+}
+
+// 指定した年と月の期限を返す、翌営業日にする場合は土日・祝日を飛ばす
+func (d Deadline) Date(year int, month time.Month, loc *time.Location, holidays event.Holidays) time.Time {
+	t := time.Date(year, month+1, 0, 0, 0, 0, 0, loc)
+	if d.Day > 0 {
+		t = time.Date(year, month, d.Day, 0, 0, 0, 0, loc)
+	}
+	if !d.Shift {
+		return t
+	}
+	for t.Weekday() == time.Saturday || t.Weekday() == time.Sunday || holidays.IsHoliday(t) {
+		t = t.AddDate(0, 0, 1)
+	}
+
+	return t
+}
+
+// t が期限かどうか、翌営業日にした場合に年をまたぐことがあるため前年の期限も確認する
+func (d Deadline) occursOn(t time.Time, holidays event.Holidays) bool {
+	for _, year := range []int{t.Year() - 1, t.Year()} {
+		for _, m := range d.Months {
+			if d.Date(year, m, t.Location(), holidays).Equal(t) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,85 @@
+package civic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, jst)
+}
+
+func TestValidate(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.NoError(Validate([]string{"kakutei", " JUMINZEI ", "nhk-6"}))
+	ta.NoError(Validate(nil))
+	ta.ErrorContains(Validate([]string{"kakutei", "koteishisan"}), "koteishisan")
+}
+
+func TestDeadlineDate(t *testing.T) {
+	holidays := event.Holidays{"2025-05-05": "こどもの日", "2025-05-06": "振替休日"}
+
+	tests := []struct {
+		name     string
+		deadline Deadline
+		year     int
+		month    time.Month
+		expected time.Time
+	}{
+		{name: "正常系/日付で指定した場合", deadline: Deadline{Day: 15, Shift: true}, year: 2024, month: time.March, expected: date(2024, 3, 15)},
+		{name: "正常系/土曜日は翌営業日にする", deadline: Deadline{Day: 15, Shift: true}, year: 2025, month: time.March, expected: date(2025, 3, 17)},
+		{name: "正常系/祝日は翌営業日にする", deadline: Deadline{Day: 5, Shift: true}, year: 2025, month: time.May, expected: date(2025, 5, 7)},
+		{name: "正常系/月末", deadline: Deadline{}, year: 2024, month: time.February, expected: date(2024, 2, 29)},
+		{name: "正常系/月末が日曜日の場合は翌月になる", deadline: Deadline{Shift: true}, year: 2025, month: time.August, expected: date(2025, 9, 1)},
+		{name: "正常系/翌営業日にしない場合", deadline: Deadline{}, year: 2025, month: time.August, expected: date(2025, 8, 31)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.deadline.Date(tt.year, tt.month, jst, holidays))
+		})
+	}
+}
+
+func TestSourceFetch(t *testing.T) {
+	s := NewSource([]string{"kakutei", "juminzei", "nhk"}, 7, nil)
+
+	tests := []struct {
+		name     string
+		date     time.Time
+		expected []string
+		leadDays []int
+	}{
+		{name: "正常系/受付開始が日曜日の場合は翌日", date: date(2025, 2, 17), expected: []string{"確定申告の受付開始"}, leadDays: []int{0}},
+		{name: "正常系/期限の前にも通知する", date: date(2025, 3, 10), expected: []string{"確定申告の期限"}, leadDays: []int{7}},
+		{name: "正常系/期限が土曜日の場合は翌営業日", date: date(2025, 3, 17), expected: []string{"確定申告の期限"}, leadDays: []int{0}},
+		{name: "正常系/年をまたいだ住民税の第 4 期", date: date(2026, 2, 2), expected: []string{"住民税の納付期限"}, leadDays: []int{0}},
+		{name: "正常系/月末の支払い", date: date(2025, 2, 28), expected: []string{"NHK 受信料の支払い (2 か月払)"}, leadDays: []int{0}},
+		{name: "正常系/指定していない期限は返さない", date: date(2025, 11, 1)},
+		{name: "正常系/期限でない日", date: date(2025, 3, 15)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			tr := require.New(t)
+
+			events, err := s.Fetch(context.Background(), tt.date)
+			tr.NoError(err)
+			tr.Len(events, len(tt.expected))
+			for i, e := range events {
+				ta.Equal(tt.expected[i], e.Name)
+				ta.Equal(tt.leadDays[i], e.LeadDays)
+				ta.Equal([]string{Tag}, e.Tags)
+			}
+		})
+	}
+}
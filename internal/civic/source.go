@@ -0,0 +1,56 @@
+package civic
+
+import (
+	"context"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// イベントに付けるタグ
+const Tag = "civic"
+
+// 世帯に当てはまる期限を返す取得元
+// 期限の当日に加えて、Remind を指定した期限は notifyBefore 日前にも通知する
+type Source struct {
+	deadlines    []Deadline
+	notifyBefore int
+	holidays     event.Holidays
+}
+
+// keys は Validate で確認したものを指定する、存在しないキーは無視する
+func NewSource(keys []string, notifyBefore int, holidays event.Holidays) *Source {
+	var selected []Deadline
+	for _, k := range keys {
+		selected = append(selected, deadlines[normalizeKey(k)]...)
+	}
+
+	return &Source{deadlines: selected, notifyBefore: notifyBefore, holidays: holidays}
+}
+
+func (s *Source) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	var events []event.Event
+	for _, d := range s.deadlines {
+		if d.occursOn(t, s.holidays) {
+			events = append(events, s.createEvent(d, 0))
+		}
+		// N 日後の期限も事前に通知する
+		if d.Remind && s.notifyBefore > 0 && d.occursOn(t.AddDate(0, 0, s.notifyBefore), s.holidays) {
+			events = append(events, s.createEvent(d, s.notifyBefore))
+		}
+	}
+
+	return events, nil
+}
+
+func (s *Source) createEvent(d Deadline, leadDays int) event.Event {
+	return event.Event{
+		Name:        d.Name,
+		Interval:    d.Interval,
+		Emoji:       d.Emoji,
+		Description: d.Description,
+		URL:         d.URL,
+		Tags:        []string{Tag},
+		LeadDays:    leadDays,
+	}
+}
@@ -0,0 +1,73 @@
+// Package clock は現在時刻の取得を提供する
+// 「今日」の判定をこのパッケージに集め、テストでは任意の日時に固定できるようにする
+package clock
+
+import (
+	"log/slog"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+	Location() *time.Location
+}
+
+// 日付の判定に使うタイムゾーン
+func JST() *time.Location {
+	jst, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		slog.Warn("failed to load JST location, using fixed offset", slog.Any("error", err))
+		return time.FixedZone("JST", 9*60*60)
+	}
+
+	return jst
+}
+
+type systemClock struct {
+	loc *time.Location
+}
+
+// JST でのシステムの現在時刻を返す
+func System() Clock {
+	return systemClock{loc: JST()}
+}
+
+func (c systemClock) Now() time.Time {
+	return time.Now().In(c.loc)
+}
+
+func (c systemClock) Location() *time.Location {
+	return c.loc
+}
+
+type fixedClock struct {
+	t time.Time
+}
+
+// 常に t を返す、タイムゾーンは t のものを使う
+func Fixed(t time.Time) Clock {
+	return fixedClock{t: t}
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.t
+}
+
+func (c fixedClock) Location() *time.Location {
+	return c.t.Location()
+}
+
+// 今日の 0 時を返す
+func Today(c Clock) time.Time {
+	now := c.Now()
+
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, c.Location())
+}
+
+// t が今日の日付かどうか
+func IsToday(c Clock, t time.Time) bool {
+	now := c.Now()
+	t = t.In(c.Location())
+
+	return t.Year() == now.Year() && t.Month() == now.Month() && t.Day() == now.Day()
+}
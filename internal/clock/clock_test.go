@@ -0,0 +1,50 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var tz = time.FixedZone("JST", 9*60*60)
+
+func TestToday(t *testing.T) {
+	ta := assert.New(t)
+
+	c := Fixed(time.Date(2025, 3, 1, 23, 30, 0, 0, tz))
+	ta.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, tz), Today(c))
+}
+
+func TestIsToday(t *testing.T) {
+	c := Fixed(time.Date(2025, 3, 1, 8, 0, 0, 0, tz))
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		expected bool
+	}{
+		{
+			name:     "正常系/同じ日付の場合",
+			t:        time.Date(2025, 3, 1, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/翌日の場合",
+			t:        time.Date(2025, 3, 2, 0, 0, 0, 0, tz),
+			expected: false,
+		},
+		{
+			name:     "正常系/UTC では前日でも JST で同じ日付の場合",
+			t:        time.Date(2025, 2, 28, 16, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			ta.Equal(tt.expected, IsToday(c, tt.t))
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 設定とそれを使って作成したクライアントを、Lambda の呼び出し間で再利用する
+// 初回の Get (コールドスタート時) に作成し、ttl が経過した後の Get で作り直す
+// 呼び出しごとの SSM パラメータの取得や、認証情報の解析を省くために使う
+type Cache[T any] struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	load     func(ctx context.Context) (T, error)
+	now      func() time.Time
+	value    T
+	loaded   bool
+	loadedAt time.Time
+}
+
+func NewCache[T any](ttl time.Duration, load func(ctx context.Context) (T, error)) *Cache[T] {
+	return &Cache[T]{ttl: ttl, load: load, now: time.Now}
+}
+
+// 作成に失敗した場合は保持せず、次の Get で再度作成する
+func (c *Cache[T]) Get(ctx context.Context) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.loaded && c.now().Sub(c.loadedAt) < c.ttl {
+		return c.value, nil
+	}
+	v, err := c.load(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	c.value, c.loaded, c.loadedAt = v, true, c.now()
+
+	return v, nil
+}
+
+// 認証情報の更新などを反映できるように、次の Get で作り直す
+func (c *Cache[T]) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero T
+	c.value, c.loaded = zero, false
+}
@@ -0,0 +1,169 @@
+// Package config は各関数で共通の設定の読み込み処理を提供する
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/handlename/ssmwrap/v2"
+	"github.com/mami0tsu/homeops/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// USE_SSM が true の場合は SSM パラメータを環境変数に展開した上で、環境変数を cfg に読み込む
+// SSM パラメータは /<APP_ENV>/<app>/<group>/* に保存し、<GROUP>_ を接頭辞とした環境変数に展開する
+// e.g. /prd/remind/discord/bot_token -> DISCORD_BOT_TOKEN
+// group は cfg の ssm タグから作成し、フィールドに対応しないもの (e.g. flags) は groups で追加する
+// CONFIG_S3_URI が指定されている場合は S3 に保存した運用上の設定で上書きする (環境変数には展開しない)
+// SECRET_ID が指定されている場合は Secrets Manager のシークレットも展開する (SSM と同じ名前の値は上書きする)
+func Load(ctx context.Context, app string, cfg any, groups ...string) error {
+	ctx, span := tracing.Start(ctx, "config.load", attribute.String("app", app))
+	err := load(ctx, app, cfg, groups...)
+	tracing.End(span, err)
+
+	return err
+}
+
+func load(ctx context.Context, app string, cfg any, groups ...string) error {
+	useSSM, err := parseBool(os.Getenv("USE_SSM"))
+	if err != nil {
+		return fmt.Errorf("invalid USE_SSM: %w", err)
+	}
+
+	if useSSM {
+		services, err := Services(cfg)
+		if err != nil {
+			return err
+		}
+		for _, g := range groups {
+			if !slices.Contains(services, g) {
+				services = append(services, g)
+			}
+		}
+		if err := ssmwrap.Export(ctx, ExportRules(os.Getenv("APP_ENV"), app, services...), ssmwrap.ExportOptions{}); err != nil {
+			return fmt.Errorf("failed to get parameters from SSM: %w", err)
+		}
+	}
+	if id := os.Getenv("SECRET_ID"); id != "" {
+		if err := exportSecret(ctx, id); err != nil {
+			return fmt.Errorf("failed to get secret from Secrets Manager: %w", err)
+		}
+	}
+
+	return refresh(ctx, cfg)
+}
+
+// Load で展開済みの SSM パラメータとシークレットはそのまま使い、S3 の運用上の設定のみを取得し直して cfg に読み込む
+// Cache で設定を再利用する場合に、呼び出しごとに運用上の設定の変更を反映するために使う
+func Refresh(ctx context.Context, cfg any) error {
+	ctx, span := tracing.Start(ctx, "config.refresh")
+	err := refresh(ctx, cfg)
+	tracing.End(span, err)
+
+	return err
+}
+
+func refresh(ctx context.Context, cfg any) error {
+	var overrides map[string]string
+	if uri := os.Getenv("CONFIG_S3_URI"); uri != "" {
+		var err error
+		if overrides, err = getS3Overrides(ctx, uri); err != nil {
+			return fmt.Errorf("failed to get config from S3: %w", err)
+		}
+	}
+
+	return parse(cfg, overrides)
+}
+
+// 環境変数を cfg に読み込む
+// 必須の環境変数が複数不足している場合は、まとめて 1 つのエラーとして返す
+// cfg が Validator を実装している場合は、読み込んだ値の形式も検証する
+func Parse(cfg any) error {
+	return parse(cfg, nil)
+}
+
+// 環境変数を overrides で上書きした値を cfg に読み込む
+// 環境変数は変更しないため、上書きをやめた値は次の読み込みで元の値に戻る
+func parse(cfg any, overrides map[string]string) error {
+	environment := env.ToMap(os.Environ())
+	maps.Copy(environment, overrides)
+	err := env.ParseWithOptions(cfg, env.Options{Environment: environment})
+	if err == nil {
+		if v, ok := cfg.(Validator); ok {
+			return v.Validate()
+		}
+		return nil
+	}
+
+	var agg env.AggregateError
+	if !errors.As(err, &agg) {
+		return err
+	}
+	var missing []string
+	var errs []error
+	for _, e := range agg.Errors {
+		var notSet env.EnvVarIsNotSetError
+		var empty env.EmptyEnvVarError
+		var parse env.ParseError
+		switch {
+		case errors.As(e, &notSet):
+			missing = append(missing, notSet.Key)
+		case errors.As(e, &empty):
+			missing = append(missing, empty.Key)
+		case errors.As(e, &parse):
+			// ParseError にはフィールド名しか含まれないため、環境変数の名前に置き換える
+			if key := envKey(reflect.TypeOf(cfg), parse.Name); key != "" {
+				errs = append(errs, fmt.Errorf("invalid %s: %w", key, parse.Err))
+				continue
+			}
+			errs = append(errs, e)
+		default:
+			errs = append(errs, e)
+		}
+	}
+	if len(missing) > 0 {
+		errs = append([]error{fmt.Errorf("required environment variables are not set: %s", strings.Join(missing, ", "))}, errs...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// フィールド名に対応する env タグの環境変数の名前を返す、入れ子の構造体も探す
+func envKey(t reflect.Type, name string) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return ""
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Name == name {
+			if key, _, _ := strings.Cut(f.Tag.Get("env"), ","); key != "" {
+				return key
+			}
+		}
+		if key := envKey(f.Type, name); key != "" {
+			return key
+		}
+	}
+
+	return ""
+}
+
+// 未設定の場合は false とする
+func parseBool(s string) (bool, error) {
+	if s == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(s)
+}
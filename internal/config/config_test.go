@@ -0,0 +1,303 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/handlename/ssmwrap/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportRules(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.Equal([]ssmwrap.ExportRule{
+		{Path: "/prd/remind/discord/*", Prefix: "DISCORD_"},
+		{Path: "/prd/remind/google/*", Prefix: "GOOGLE_"},
+	}, ExportRules("prd", "remind", "discord", "google"))
+}
+
+func TestParameterPath(t *testing.T) {
+	ta := assert.New(t)
+
+	p := ParameterPath{Env: "prd", Function: "remind", Service: "discord", Name: "bot_token"}
+	ta.Equal("/prd/remind/discord/bot_token", p.String())
+	ta.Equal("DISCORD_BOT_TOKEN", p.EnvName())
+	ta.Equal(ssmwrap.ExportRule{Path: "/prd/remind/discord/*", Prefix: "DISCORD_"}, p.ExportRule())
+}
+
+func TestServices(t *testing.T) {
+	type validConfig struct {
+		Token   string `env:"DISCORD_BOT_TOKEN,required" ssm:"discord"`
+		Channel string `env:"DISCORD_CHANNEL_ID" ssm:"discord"`
+		DSN     string `env:"SENTRY_DSN" ssm:"sentry"`
+		Days    int    `env:"LOOKAHEAD_DAYS"`
+	}
+	type invalidConfig struct {
+		Token string `env:"DISCROD_BOT_TOKEN" ssm:"discord"`
+	}
+
+	tests := []struct {
+		name        string
+		cfg         any
+		expectError bool
+		expected    []string
+	}{
+		{
+			name:     "正常系/ssm タグを付けたフィールドのみ対象とする場合",
+			cfg:      &validConfig{},
+			expected: []string{"discord", "sentry"},
+		},
+		{
+			name:        "異常系/環境変数の名前がサービス名で始まらない場合",
+			cfg:         &invalidConfig{},
+			expectError: true,
+		},
+		{
+			name:        "異常系/構造体でない場合",
+			cfg:         "config",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			services, err := Services(tt.cfg)
+			if tt.expectError {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, services)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	type testConfig struct {
+		Token   string `env:"TEST_TOKEN,required"`
+		Channel string `env:"TEST_CHANNEL,required"`
+		Days    int    `env:"TEST_DAYS" envDefault:"2"`
+	}
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expectError string
+		expected    testConfig
+	}{
+		{
+			name:     "正常系/必須の環境変数が設定されている場合",
+			env:      map[string]string{"TEST_TOKEN": "token", "TEST_CHANNEL": "123"},
+			expected: testConfig{Token: "token", Channel: "123", Days: 2},
+		},
+		{
+			name:        "異常系/必須の環境変数が複数不足している場合",
+			env:         map[string]string{},
+			expectError: "required environment variables are not set: TEST_TOKEN, TEST_CHANNEL",
+		},
+		{
+			name:        "異常系/値の形式が不正な場合",
+			env:         map[string]string{"TEST_TOKEN": "token", "TEST_CHANNEL": "123", "TEST_DAYS": "two"},
+			expectError: "TEST_DAYS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			var cfg testConfig
+			err := Parse(&cfg)
+			if tt.expectError != "" {
+				ta.ErrorContains(err, tt.expectError)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, cfg)
+		})
+	}
+}
+
+func TestParseSecret(t *testing.T) {
+	ta := assert.New(t)
+
+	values, err := parseSecret(`{"DISCORD_BOT_TOKEN": "token", "GOOGLE_CREDENTIALS": {"type": "service_account"}}`)
+	ta.NoError(err)
+	ta.Equal(map[string]string{
+		"DISCORD_BOT_TOKEN":  "token",
+		"GOOGLE_CREDENTIALS": `{"type": "service_account"}`,
+	}, values)
+
+	_, err = parseSecret("token")
+	ta.Error(err)
+}
+
+func TestApplyProfile(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expectError bool
+		expected    Profile
+		expectedEnv map[string]string
+	}{
+		{
+			name:        "正常系/APP_ENV が未指定の場合",
+			env:         map[string]string{"APP_ENV": ""},
+			expected:    ProfileLocal,
+			expectedEnv: map[string]string{"LOG_LEVEL": "debug", "DRY_RUN": "true"},
+		},
+		{
+			name:        "正常系/環境変数で指定した値を優先する場合",
+			env:         map[string]string{"APP_ENV": "prd", "LOG_LEVEL": "warn"},
+			expected:    ProfilePrd,
+			expectedEnv: map[string]string{"LOG_LEVEL": "warn", "DRY_RUN": "false"},
+		},
+		{
+			name:        "異常系/APP_ENV が不正な場合",
+			env:         map[string]string{"APP_ENV": "production"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			// 既定値が設定されたかを確認するため、テストの終了時に元に戻せる状態で削除しておく
+			for _, k := range []string{"LOG_LEVEL", "DRY_RUN"} {
+				t.Setenv(k, "")
+				os.Unsetenv(k)
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			p, err := ApplyProfile()
+			if tt.expectError {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, p)
+			for k, v := range tt.expectedEnv {
+				ta.Equal(v, os.Getenv(k))
+			}
+		})
+	}
+}
+
+func TestParseOverrides(t *testing.T) {
+	ta := assert.New(t)
+
+	values, err := parseOverrides([]byte(`{
+		"SINKS": ["discord", "google_chat"],
+		"SINK_FORMATS": {"google_chat": "text", "discord": "markdown"},
+		"LOCALE": "ja",
+		"LOOKAHEAD_DAYS": 3,
+		"ICS_ATTACHMENT": true
+	}`))
+	ta.NoError(err)
+	ta.Equal(map[string]string{
+		"SINKS":          "discord,google_chat",
+		"SINK_FORMATS":   "discord:markdown,google_chat:text",
+		"LOCALE":         "ja",
+		"LOOKAHEAD_DAYS": "3",
+		"ICS_ATTACHMENT": "true",
+	}, values)
+
+	_, err = parseOverrides([]byte(`["discord"]`))
+	ta.Error(err)
+
+	// 認証情報などの上書きできない値を含む場合
+	_, err = parseOverrides([]byte(`{"LOCALE": "ja", "DISCORD_BOT_TOKEN": "token"}`))
+	ta.ErrorContains(err, "DISCORD_BOT_TOKEN")
+
+	// プロファイルで投稿先のチャンネルを書き換えられる場合
+	_, err = parseOverrides([]byte(`{"SCHEDULE_PROFILES": {"weekly": {"channel": "999"}}}`))
+	ta.ErrorContains(err, "SCHEDULE_PROFILES")
+}
+
+func TestParseWithOverrides(t *testing.T) {
+	type testConfig struct {
+		Locale string `env:"LOCALE" envDefault:"en"`
+		Days   int    `env:"LOOKAHEAD_DAYS" envDefault:"2"`
+	}
+	ta := assert.New(t)
+	t.Setenv("LOOKAHEAD_DAYS", "3")
+
+	var cfg testConfig
+	ta.NoError(parse(&cfg, map[string]string{"LOCALE": "ja", "LOOKAHEAD_DAYS": "7"}))
+	ta.Equal(testConfig{Locale: "ja", Days: 7}, cfg)
+	ta.Equal("3", os.Getenv("LOOKAHEAD_DAYS"))
+
+	// 上書きをやめた値は、環境変数の値もしくは既定値に戻る
+	cfg = testConfig{}
+	ta.NoError(parse(&cfg, map[string]string{"LOCALE": "ja"}))
+	ta.Equal(testConfig{Locale: "ja", Days: 3}, cfg)
+	cfg = testConfig{}
+	ta.NoError(parse(&cfg, nil))
+	ta.Equal(testConfig{Locale: "en", Days: 3}, cfg)
+}
+
+func TestParseS3URI(t *testing.T) {
+	ta := assert.New(t)
+
+	bucket, key, err := parseS3URI("s3://homeops-config/prd/remind.json")
+	ta.NoError(err)
+	ta.Equal("homeops-config", bucket)
+	ta.Equal("prd/remind.json", key)
+
+	_, _, err = parseS3URI("https://homeops-config/prd/remind.json")
+	ta.Error(err)
+	_, _, err = parseS3URI("s3://homeops-config/")
+	ta.Error(err)
+}
+
+func TestCache(t *testing.T) {
+	ta := assert.New(t)
+	ctx := context.Background()
+
+	loads := 0
+	fail := false
+	c := NewCache(time.Minute, func(ctx context.Context) (int, error) {
+		if fail {
+			return 0, errors.New("failed to load")
+		}
+		loads++
+		return loads, nil
+	})
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	// 期限内は作成済みの値を再利用する
+	v, err := c.Get(ctx)
+	ta.NoError(err)
+	ta.Equal(1, v)
+	now = now.Add(30 * time.Second)
+	v, err = c.Get(ctx)
+	ta.NoError(err)
+	ta.Equal(1, v)
+
+	// 期限が切れた場合は作り直す
+	now = now.Add(time.Minute)
+	v, err = c.Get(ctx)
+	ta.NoError(err)
+	ta.Equal(2, v)
+
+	// 作成に失敗した場合は保持せず、次の Get で再度作成する
+	c.Invalidate()
+	fail = true
+	_, err = c.Get(ctx)
+	ta.Error(err)
+	fail = false
+	v, err = c.Get(ctx)
+	ta.NoError(err)
+	ta.Equal(3, v)
+}
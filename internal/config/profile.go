@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// APP_ENV で指定する実行環境
+// 環境ごとの値 (チャンネル ID など) は SSM パラメータ /<APP_ENV>/<app>/* に保存して切り替える
+type Profile string
+
+const (
+	ProfileLocal Profile = "local" // APP_ENV が未指定の場合
+	ProfileDev   Profile = "dev"
+	ProfileStg   Profile = "stg"
+	ProfilePrd   Profile = "prd"
+)
+
+// 環境ごとの設定の既定値、環境変数や SSM パラメータで指定した値を優先する
+var profileDefaults = map[Profile]map[string]string{
+	ProfileLocal: {"LOG_LEVEL": "debug", "DRY_RUN": "true"},
+	ProfileDev:   {"LOG_LEVEL": "debug", "DRY_RUN": "false"},
+	ProfileStg:   {"LOG_LEVEL": "info", "DRY_RUN": "false"},
+	ProfilePrd:   {"LOG_LEVEL": "info", "DRY_RUN": "false"},
+}
+
+func ParseProfile(s string) (Profile, error) {
+	if s == "" {
+		return ProfileLocal, nil
+	}
+	p := Profile(s)
+	if _, ok := profileDefaults[p]; !ok {
+		return "", fmt.Errorf("invalid APP_ENV: %s", s)
+	}
+
+	return p, nil
+}
+
+// APP_ENV に応じた既定値を、未設定の環境変数に設定する
+// ロガーの作成や設定の読み込みより前に、起動時に 1 度だけ呼び出す
+func ApplyProfile() (Profile, error) {
+	p, err := ParseProfile(os.Getenv("APP_ENV"))
+	if err != nil {
+		return "", err
+	}
+	for k, v := range profileDefaults[p] {
+		if _, ok := os.LookupEnv(k); ok {
+			continue
+		}
+		if err := os.Setenv(k, v); err != nil {
+			return "", err
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/s3"
+)
+
+// S3 に保存した運用上の設定 (投稿先の振り分け、表示形式、絞り込みなど) を、環境変数を上書きする値として取得する
+// 呼び出しごとに読み込むため、表示の調整などを再デプロイせずに反映できる
+// 設定は {"SINKS": ["discord", "google_chat"], "SINK_FORMATS": {"discord": "markdown"}, "LOCALE": "ja"} のような JSON で保存し、
+// 配列はカンマ区切り、オブジェクトは "キー:値" のカンマ区切りとして展開する
+// 秘匿情報は SSM パラメータか Secrets Manager に保存し、overridableKeys 以外の値は上書きできない
+func getS3Overrides(ctx context.Context, uri string) (map[string]string, error) {
+	bucket, key, err := parseS3URI(uri)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s3.NewClient(ctx, httpclient.Default)
+	if err != nil {
+		return nil, err
+	}
+	b, err := client.GetObject(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseOverrides(b)
+}
+
+// S3 の設定で上書きできる環境変数
+// 認証情報や投稿先のチャンネルなどを書き換えられないように、表示や絞り込みに関する値のみとする
+// SCHEDULE_PROFILES はプロファイルごとに投稿先のチャンネルを指定できるため含めない
+var overridableKeys = []string{
+	"SHEET_TAB",
+	"SHEET_CHUNK_ROWS",
+	"SINKS",
+	"SINK_FORMATS",
+	"NO_EVENTS_MODE",
+	"LOCALE",
+	"LOOKAHEAD_DAYS",
+	"UPCOMING_DAYS",
+	"ICS_ATTACHMENT",
+	"SORT_ORDER",
+	"COLLATION",
+	"ACK_LOOKBACK_DAYS",
+	"OVERDUE_MENTION",
+	"OVERDUE_MENTION_AFTER",
+}
+
+// e.g. s3://homeops-config/prd/remind.json
+func parseS3URI(uri string) (string, string, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "s3" || u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return "", "", fmt.Errorf("invalid s3 uri: %s", uri)
+	}
+
+	return u.Host, strings.TrimPrefix(u.Path, "/"), nil
+}
+
+func parseOverrides(b []byte) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("config object must be a JSON object")
+	}
+
+	var denied []string
+	for _, k := range slices.Sorted(maps.Keys(raw)) {
+		if !slices.Contains(overridableKeys, k) {
+			denied = append(denied, k)
+		}
+	}
+	if len(denied) > 0 {
+		return nil, fmt.Errorf("config object must not override %s", strings.Join(denied, ", "))
+	}
+
+	values := make(map[string]string, len(raw))
+	for k, v := range raw {
+		var list []string
+		if err := json.Unmarshal(v, &list); err == nil {
+			values[k] = strings.Join(list, ",")
+			continue
+		}
+		var m map[string]string
+		if err := json.Unmarshal(v, &m); err == nil {
+			var pairs []string
+			for _, mk := range slices.Sorted(maps.Keys(m)) {
+				pairs = append(pairs, mk+":"+m[mk])
+			}
+			values[k] = strings.Join(pairs, ",")
+			continue
+		}
+		var str string
+		if err := json.Unmarshal(v, &str); err == nil {
+			values[k] = str
+			continue
+		}
+		values[k] = string(v)
+	}
+
+	return values, nil
+}
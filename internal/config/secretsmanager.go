@@ -0,0 +1,58 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mami0tsu/homeops/internal/awsjson"
+)
+
+// Secrets Manager のシークレットを環境変数に展開する
+// シークレットは {"DISCORD_BOT_TOKEN": "...", "GOOGLE_CREDENTIALS": {...}} のような JSON で保存し、
+// 値がオブジェクトの場合は JSON の文字列として展開する
+func exportSecret(ctx context.Context, secretID string) error {
+	client, err := awsjson.New(ctx, "secretsmanager", "secretsmanager", "application/x-amz-json-1.1")
+	if err != nil {
+		return err
+	}
+
+	var out struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := client.Do(ctx, "GetSecretValue", map[string]string{"SecretId": secretID}, &out); err != nil {
+		return err
+	}
+
+	values, err := parseSecret(out.SecretString)
+	if err != nil {
+		return err
+	}
+	for k, v := range values {
+		if err := os.Setenv(k, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseSecret(s string) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		return nil, fmt.Errorf("secret must be a JSON object")
+	}
+
+	values := make(map[string]string, len(raw))
+	for k, v := range raw {
+		var str string
+		if err := json.Unmarshal(v, &str); err == nil {
+			values[k] = str
+			continue
+		}
+		values[k] = string(v)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/handlename/ssmwrap/v2"
+)
+
+// SSM パラメータのパス /<env>/<function>/<service>/<name>
+// e.g. /prd/remind/discord/bot_token
+type ParameterPath struct {
+	Env      string // e.g. prd
+	Function string // e.g. remind
+	Service  string // e.g. discord
+	Name     string // e.g. bot_token、空の場合はサービスの全てのパラメータ
+}
+
+func (p ParameterPath) String() string {
+	name := p.Name
+	if name == "" {
+		name = "*"
+	}
+
+	return fmt.Sprintf("/%s/%s/%s/%s", p.Env, p.Function, p.Service, name)
+}
+
+// 展開先の環境変数の名前、e.g. DISCORD_BOT_TOKEN
+func (p ParameterPath) EnvName() string {
+	return strings.ToUpper(p.Service + "_" + p.Name)
+}
+
+// サービスの全てのパラメータを、サービス名を接頭辞とした環境変数に展開するルール
+func (p ParameterPath) ExportRule() ssmwrap.ExportRule {
+	return ssmwrap.ExportRule{
+		Path:   ParameterPath{Env: p.Env, Function: p.Function, Service: p.Service}.String(),
+		Prefix: strings.ToUpper(p.Service) + "_",
+	}
+}
+
+func ExportRules(appEnv, app string, groups ...string) []ssmwrap.ExportRule {
+	var rules []ssmwrap.ExportRule
+	for _, g := range groups {
+		rules = append(rules, ParameterPath{Env: appEnv, Function: app, Service: g}.ExportRule())
+	}
+
+	return rules
+}
+
+// cfg のフィールドの ssm タグからサービスの一覧を作成する
+// ssm タグを付けたフィールドは env タグの名前がサービス名で始まっている必要がある
+// e.g. DiscordBotToken string `env:"DISCORD_BOT_TOKEN" ssm:"discord"`
+func Services(cfg any) ([]string, error) {
+	t := reflect.TypeOf(cfg)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config must be a struct: %s", t)
+	}
+
+	var services []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		service, ok := f.Tag.Lookup("ssm")
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("env"), ",")
+		if service == "" || !strings.HasPrefix(name, strings.ToUpper(service)+"_") {
+			return nil, fmt.Errorf("invalid ssm tag on %s: env %q does not start with %q", f.Name, name, strings.ToUpper(service)+"_")
+		}
+		if !slices.Contains(services, service) {
+			services = append(services, service)
+		}
+	}
+
+	return services, nil
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// 設定値の形式を検証する
+// Parse の後に呼び出され、全ての問題をまとめて返す
+type Validator interface {
+	Validate() error
+}
+
+var (
+	snowflakePattern     = regexp.MustCompile(`^[0-9]{17,20}$`)
+	spreadsheetIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{30,}$`)
+)
+
+// Discord の ID (snowflake) の形式かどうか
+func CheckSnowflake(name, v string) error {
+	if !snowflakePattern.MatchString(v) {
+		return fmt.Errorf("%s must be a numeric Discord ID", name)
+	}
+
+	return nil
+}
+
+// n バイトの値を 16 進数で表した文字列かどうか
+func CheckHex(name, v string, n int) error {
+	b, err := hex.DecodeString(v)
+	if err != nil || len(b) != n {
+		return fmt.Errorf("%s must be %d hex characters", name, n*2)
+	}
+
+	return nil
+}
+
+// Google のサービスアカウントの認証情報の形式かどうか
+func CheckGoogleCredentials(name, v string) error {
+	var creds struct {
+		Type        string `json:"type"`
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+	}
+	if err := json.Unmarshal([]byte(v), &creds); err != nil {
+		return fmt.Errorf("%s must be a service account key in JSON", name)
+	}
+	if creds.Type != "service_account" || creds.ClientEmail == "" || creds.PrivateKey == "" {
+		return fmt.Errorf("%s must contain type, client_email and private_key of a service account", name)
+	}
+
+	return nil
+}
+
+// スプレッドシートの URL の /d/ と /edit の間の文字列かどうか
+func CheckSpreadsheetID(name, v string) error {
+	if !spreadsheetIDPattern.MatchString(v) {
+		return fmt.Errorf("%s must be the ID part of the spreadsheet URL", name)
+	}
+
+	return nil
+}
+
+func CheckURL(name, v string) error {
+	u, err := url.Parse(v)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("%s must be an http(s) URL", name)
+	}
+
+	return nil
+}
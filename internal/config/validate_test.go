@@ -0,0 +1,68 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecks(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		expectError bool
+	}{
+		{
+			name: "正常系/Discord の ID の場合",
+			err:  CheckSnowflake("ID", "123456789012345678"),
+		},
+		{
+			name:        "異常系/Discord の ID が数値ではない場合",
+			err:         CheckSnowflake("ID", "general"),
+			expectError: true,
+		},
+		{
+			name: "正常系/32 バイトの 16 進数の場合",
+			err:  CheckHex("KEY", "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", 32),
+		},
+		{
+			name:        "異常系/16 進数の長さが足りない場合",
+			err:         CheckHex("KEY", "0123456789abcdef", 32),
+			expectError: true,
+		},
+		{
+			name: "正常系/サービスアカウントの認証情報の場合",
+			err:  CheckGoogleCredentials("CREDS", `{"type": "service_account", "client_email": "a@example.com", "private_key": "key"}`),
+		},
+		{
+			name:        "異常系/認証情報が JSON ではない場合",
+			err:         CheckGoogleCredentials("CREDS", "credentials"),
+			expectError: true,
+		},
+		{
+			name: "正常系/スプレッドシートの ID の場合",
+			err:  CheckSpreadsheetID("ID", "1BxiMVs0XRA5nFMdKvBdBZjgmUUqptlbs74OgvE2upms"),
+		},
+		{
+			name:        "異常系/スプレッドシートの URL が指定されている場合",
+			err:         CheckSpreadsheetID("ID", "https://docs.google.com/spreadsheets/d/1BxiMVs0XRA5nFMdKvBdBZjgmUUqptlbs74OgvE2upms/edit"),
+			expectError: true,
+		},
+		{
+			name:        "異常系/URL にスキームがない場合",
+			err:         CheckURL("URL", "example.com/webhook"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			if tt.expectError {
+				ta.Error(tt.err)
+			} else {
+				ta.NoError(tt.err)
+			}
+		})
+	}
+}
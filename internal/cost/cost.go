@@ -0,0 +1,174 @@
+// Package cost は Cost Explorer から AWS の利用料金をサービスごとに集計する
+package cost
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+const SourceName = "cost_explorer"
+
+// Cost Explorer の API は us-east-1 のみで提供される
+const (
+	Service     = "ce"
+	Region      = "us-east-1"
+	Target      = "AWSInsightsIndexService"
+	ContentType = "application/x-amz-json-1.1"
+)
+
+const dateFormat = "2006-01-02"
+
+// AWS の JSON プロトコルの API を呼び出す、e.g. awsjson.Client
+type Caller interface {
+	Do(ctx context.Context, operation string, in any, out any) error
+}
+
+type ServiceCost struct {
+	Service string // e.g. Amazon DynamoDB
+	Amount  float64
+}
+
+// 期間中の利用料金
+type Report struct {
+	Start    time.Time // 開始日
+	End      time.Time // 終了日の翌日
+	Unit     string    // e.g. USD
+	Services []ServiceCost
+}
+
+func (r Report) Total() float64 {
+	var total float64
+	for _, s := range r.Services {
+		total += s.Amount
+	}
+
+	return total
+}
+
+// 金額の大きい上位 n 件を残し、残りは "その他" にまとめる
+func (r Report) Top(n int) []ServiceCost {
+	if n <= 0 || len(r.Services) <= n {
+		return r.Services
+	}
+	top := slices.Clone(r.Services[:n])
+	var rest float64
+	for _, s := range r.Services[n:] {
+		rest += s.Amount
+	}
+
+	return append(top, ServiceCost{Service: "その他", Amount: rest})
+}
+
+// 金額を表示用の文字列にする、e.g. $1.23
+func (r Report) Format(amount float64) string {
+	if r.Unit == "" || r.Unit == "USD" {
+		return fmt.Sprintf("$%.2f", amount)
+	}
+
+	return fmt.Sprintf("%.2f %s", amount, r.Unit)
+}
+
+// GetCostAndUsage のリクエストとレスポンスのうち使用する部分
+type getCostAndUsageInput struct {
+	TimePeriod    timePeriod `json:"TimePeriod"`
+	Granularity   string     `json:"Granularity"`
+	Metrics       []string   `json:"Metrics"`
+	GroupBy       []groupBy  `json:"GroupBy"`
+	NextPageToken string     `json:"NextPageToken,omitempty"`
+}
+
+type timePeriod struct {
+	Start string `json:"Start"`
+	End   string `json:"End"`
+}
+
+type groupBy struct {
+	Type string `json:"Type"`
+	Key  string `json:"Key"`
+}
+
+type getCostAndUsageOutput struct {
+	ResultsByTime []struct {
+		Groups []struct {
+			Keys    []string `json:"Keys"`
+			Metrics map[string]struct {
+				Amount string `json:"Amount"`
+				Unit   string `json:"Unit"`
+			} `json:"Metrics"`
+		} `json:"Groups"`
+	} `json:"ResultsByTime"`
+	NextPageToken string `json:"NextPageToken"`
+}
+
+// start から end の前日までの利用料金をサービスごとに合計し、金額の大きい順に返す
+// 日付は UTC として扱われる
+func Fetch(ctx context.Context, c Caller, start, end time.Time) (Report, error) {
+	in := getCostAndUsageInput{
+		TimePeriod:  timePeriod{Start: start.Format(dateFormat), End: end.Format(dateFormat)},
+		Granularity: "DAILY",
+		Metrics:     []string{"UnblendedCost"},
+		GroupBy:     []groupBy{{Type: "DIMENSION", Key: "SERVICE"}},
+	}
+
+	r := Report{Start: start, End: end}
+	totals := map[string]float64{}
+	for {
+		var out getCostAndUsageOutput
+		if err := c.Do(ctx, "GetCostAndUsage", in, &out); err != nil {
+			return Report{}, event.NewSourceUnavailableError(SourceName, err)
+		}
+		for _, result := range out.ResultsByTime {
+			for _, g := range result.Groups {
+				m, ok := g.Metrics["UnblendedCost"]
+				if !ok || len(g.Keys) == 0 {
+					continue
+				}
+				v, err := strconv.ParseFloat(m.Amount, 64)
+				if err != nil {
+					return Report{}, event.NewParseError(SourceName, g.Keys[0], err)
+				}
+				totals[g.Keys[0]] += v
+				r.Unit = m.Unit
+			}
+		}
+		if out.NextPageToken == "" {
+			break
+		}
+		in.NextPageToken = out.NextPageToken
+	}
+
+	for name, v := range totals {
+		// 表示すると $0.00 になるサービスは除く
+		if math.Abs(v) < 0.005 {
+			continue
+		}
+		r.Services = append(r.Services, ServiceCost{Service: name, Amount: v})
+	}
+	slices.SortFunc(r.Services, func(a, b ServiceCost) int {
+		return cmp.Or(cmp.Compare(b.Amount, a.Amount), cmp.Compare(a.Service, b.Service))
+	})
+
+	return r, nil
+}
+
+// 予算に対する利用料金の割合が超えた閾値 (%) のうち最も大きいものを返す、いずれも超えていない場合は 0 を返す
+func ExceededThreshold(spent, budget float64, thresholds []int) int {
+	if budget <= 0 {
+		return 0
+	}
+	var exceeded int
+	for _, t := range thresholds {
+		if spent >= budget*float64(t)/100 && t > exceeded {
+			exceeded = t
+		}
+	}
+
+	return exceeded
+}
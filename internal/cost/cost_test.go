@@ -0,0 +1,118 @@
+package cost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ページごとのレスポンスを順に返す
+type fakeCaller struct {
+	pages  []string
+	inputs []getCostAndUsageInput
+	err    error
+}
+
+func (c *fakeCaller) Do(ctx context.Context, operation string, in any, out any) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.inputs = append(c.inputs, in.(getCostAndUsageInput))
+	page := c.pages[0]
+	c.pages = c.pages[1:]
+
+	return json.Unmarshal([]byte(page), out)
+}
+
+func TestFetch(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	t.Run("正常系/サービスごとに合計して金額の大きい順に返す", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		c := &fakeCaller{pages: []string{
+			`{"ResultsByTime":[{"Groups":[
+				{"Keys":["Amazon DynamoDB"],"Metrics":{"UnblendedCost":{"Amount":"0.10","Unit":"USD"}}},
+				{"Keys":["AWS Lambda"],"Metrics":{"UnblendedCost":{"Amount":"0.0001","Unit":"USD"}}},
+				{"Keys":["Amazon S3"],"Metrics":{"UnblendedCost":{"Amount":"0.30","Unit":"USD"}}}
+			]}],"NextPageToken":"next"}`,
+			`{"ResultsByTime":[{"Groups":[
+				{"Keys":["Amazon DynamoDB"],"Metrics":{"UnblendedCost":{"Amount":"0.25","Unit":"USD"}}}
+			]}]}`,
+		}}
+
+		r, err := Fetch(context.Background(), c, start, end)
+		tr.NoError(err)
+		ta.Equal("USD", r.Unit)
+		ta.Equal([]ServiceCost{{Service: "Amazon DynamoDB", Amount: 0.35}, {Service: "Amazon S3", Amount: 0.30}}, r.Services)
+		ta.InDelta(0.65, r.Total(), 1e-9)
+
+		tr.Len(c.inputs, 2)
+		ta.Equal(timePeriod{Start: "2025-03-01", End: "2025-03-03"}, c.inputs[0].TimePeriod)
+		ta.Equal("next", c.inputs[1].NextPageToken)
+	})
+
+	t.Run("異常系/呼び出しに失敗した場合", func(t *testing.T) {
+		ta := assert.New(t)
+
+		_, err := Fetch(context.Background(), &fakeCaller{err: errors.New("AccessDeniedException")}, start, end)
+		ta.ErrorIs(err, event.ErrSourceUnavailable)
+	})
+
+	t.Run("異常系/金額を解釈できない場合", func(t *testing.T) {
+		ta := assert.New(t)
+
+		c := &fakeCaller{pages: []string{`{"ResultsByTime":[{"Groups":[{"Keys":["Amazon S3"],"Metrics":{"UnblendedCost":{"Amount":"-","Unit":"USD"}}}]}]}`}}
+		_, err := Fetch(context.Background(), c, start, end)
+		ta.ErrorIs(err, event.ErrParse)
+	})
+}
+
+func TestReportTop(t *testing.T) {
+	ta := assert.New(t)
+
+	r := Report{Services: []ServiceCost{{"A", 3}, {"B", 2}, {"C", 1}, {"D", 0.5}}}
+	ta.Equal([]ServiceCost{{"A", 3}, {"B", 2}, {"その他", 1.5}}, r.Top(2))
+	ta.Equal(r.Services, r.Top(4))
+	ta.Equal(r.Services, r.Top(0))
+}
+
+func TestReportFormat(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.Equal("$1.23", Report{Unit: "USD"}.Format(1.234))
+	ta.Equal("1.23 EUR", Report{Unit: "EUR"}.Format(1.234))
+}
+
+func TestExceededThreshold(t *testing.T) {
+	thresholds := []int{50, 80, 100}
+
+	tests := []struct {
+		name     string
+		spent    float64
+		budget   float64
+		expected int
+	}{
+		{name: "正常系/いずれも超えていない場合", spent: 4, budget: 10},
+		{name: "正常系/閾値と等しい場合", spent: 5, budget: 10, expected: 50},
+		{name: "正常系/複数の閾値を超えた場合", spent: 9, budget: 10, expected: 80},
+		{name: "正常系/予算を超えた場合", spent: 12, budget: 10, expected: 100},
+		{name: "正常系/予算が指定されていない場合", spent: 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			ta.Equal(tt.expected, ExceededThreshold(tt.spent, tt.budget, thresholds))
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Package digest は取得元からイベント情報を取得して投稿内容を作成し、投稿先ごとに投稿する
+// remind の Lambda とローカルで実行する CLI で共有する
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/metrics"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/sources"
+	"github.com/mami0tsu/homeops/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 取得元と投稿先の組
+type App struct {
+	source sources.Source
+	sinks  []notify.Sink
+}
+
+func NewApp(source sources.Source, sinks ...notify.Sink) *App {
+	return &App{source: source, sinks: sinks}
+}
+
+// 指定した日付ごとにイベント情報を取得する、取得できたイベント情報と日付ごとのエラーを返す
+// 一部の取得元のみ失敗した場合は、取得できたイベント情報を返した上でエラーも返す
+func (a *App) FetchSchedules(ctx context.Context, dates []time.Time) ([]event.Schedule, []error) {
+	var schedules []event.Schedule
+	var errs []error
+	for _, d := range dates {
+		events, err := a.fetch(ctx, d)
+		if err != nil {
+			slog.Error("failed to get events", slog.String("component", event.FailedComponent(err)), slog.Any("error", err))
+			errs = append(errs, err)
+			if events == nil {
+				continue
+			}
+		}
+
+		schedules = append(schedules, event.Schedule{Date: d, Events: events})
+	}
+
+	return schedules, errs
+}
+
+// 投稿先ごとの投稿結果
+type Delivery struct {
+	Sink  string `json:"sink"`
+	Error string `json:"error,omitempty"`
+}
+
+// 投稿先ごとにイベント情報を投稿する、一部の投稿先が失敗しても残りの投稿は続ける
+func (a *App) Post(ctx context.Context, d event.Digest) ([]Delivery, error) {
+	return a.PostOnce(ctx, nil, time.Time{}, "", d)
+}
+
+// 再試行された呼び出しで重複して投稿しないように、投稿状況を記録する
+type Guard interface {
+	// key の投稿を 1 度だけ行う、投稿済みで post を呼び出さなかった場合は false を返す
+	Once(ctx context.Context, date time.Time, key string, post func() error) (bool, error)
+}
+
+// 投稿先ごとに、実行日と実行 ID ごとに 1 度だけイベント情報を投稿する
+// 投稿状況は投稿先ごとに記録するため、一部の投稿先のみ失敗した場合は、再試行で失敗した投稿先にのみ投稿する
+// 投稿済みで投稿しなかった投稿先は、投稿結果に含めない、guard が nil の場合は常に投稿する
+func (a *App) PostOnce(ctx context.Context, guard Guard, date time.Time, runID string, d event.Digest) ([]Delivery, error) {
+	var deliveries []Delivery
+	var errs []error
+	for _, s := range a.sinks {
+		posted, err := once(ctx, guard, date, runID+"#"+s.Name(), func() error {
+			return a.postTo(ctx, s, d)
+		})
+		if err != nil {
+			slog.Error("failed to post events", slog.String("sink", s.Name()), slog.Any("error", err))
+			errs = append(errs, event.NewNotifyError(s.Name(), err))
+		} else if !posted {
+			continue
+		}
+		deliveries = append(deliveries, newDelivery(s.Name(), err))
+	}
+
+	return deliveries, errors.Join(errs...)
+}
+
+func once(ctx context.Context, guard Guard, date time.Time, key string, post func() error) (bool, error) {
+	if guard == nil {
+		return true, post()
+	}
+
+	return guard.Once(ctx, date, key, post)
+}
+
+func newDelivery(sink string, err error) Delivery {
+	d := Delivery{Sink: sink}
+	if err != nil {
+		d.Error = err.Error()
+	}
+
+	return d
+}
+
+func (a *App) fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	ctx, span := tracing.Start(ctx, "source.fetch", attribute.String("date", t.Format("2006-01-02")))
+	events, err := fetchSafely(ctx, a.source, t)
+	span.SetAttributes(attribute.Int("events", len(events)))
+	tracing.End(span, err)
+
+	return events, err
+}
+
+func (a *App) postTo(ctx context.Context, s notify.Sink, d event.Digest) error {
+	ctx, span := tracing.Start(ctx, "sink.post", attribute.String("sink", s.Name()))
+	err := postSafely(ctx, s, d)
+	tracing.End(span, err)
+
+	// 投稿できなかった場合も 0 件として記録し、投稿が途絶えたことを検知できるようにする
+	posted, failed := countEvents(d), 0
+	if err != nil {
+		posted, failed = 0, 1
+	}
+	m := metrics.FromContext(ctx)
+	m.Add("EventsPosted", float64(posted), metrics.Count, "Sink", s.Name())
+	m.Add("SinkErrors", float64(failed), metrics.Count, "Sink", s.Name())
+
+	return err
+}
+
+// 1 つの取得元や投稿先のパニックで処理全体を止めず、エラーとして扱って残りの処理を続ける
+// 取得元ごとのパニックは MultiSource で扱い、ここでは絞り込みや並べ替えなどのパニックを扱う
+func fetchSafely(ctx context.Context, src sources.Source, t time.Time) (events []event.Event, err error) {
+	defer recoverAsError("source", &err)
+
+	return src.Fetch(ctx, t)
+}
+
+func postSafely(ctx context.Context, s notify.Sink, d event.Digest) (err error) {
+	defer recoverAsError(s.Name(), &err)
+
+	return s.Post(ctx, d)
+}
+
+func recoverAsError(component string, err *error) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	slog.Error("recovered from panic", slog.String("component", component), slog.Any("panic", v), slog.String("stack", string(debug.Stack())))
+	*err = fmt.Errorf("recovered from panic: %v", v)
+}
+
+// 投稿対象の日付のイベント数を返す
+func countEvents(d event.Digest) int {
+	n := 0
+	for _, s := range d.Schedules {
+		n += len(s.Events)
+	}
+
+	return n
+}
@@ -0,0 +1,89 @@
+package digest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/sources"
+	"github.com/stretchr/testify/assert"
+)
+
+var tz = time.FixedZone("JST", 9*60*60)
+
+type panicSource struct{}
+
+func (panicSource) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	if t.Day() == 1 {
+		var e *event.Event
+		_ = e.Name
+	}
+
+	return []event.Event{{Name: "Garbage"}}, nil
+}
+
+type recordSink struct {
+	name   string
+	panics bool
+	posted int
+}
+
+func (s *recordSink) Name() string {
+	return s.name
+}
+
+func (s *recordSink) Post(ctx context.Context, d event.Digest) error {
+	if s.panics {
+		panic("sink is broken")
+	}
+	s.posted++
+
+	return nil
+}
+
+func TestAppRecoversFromPanic(t *testing.T) {
+	ta := assert.New(t)
+
+	broken := &recordSink{name: "broken", panics: true}
+	healthy := &recordSink{name: "healthy"}
+	a := NewApp(panicSource{}, broken, healthy)
+	ctx := context.Background()
+
+	dates := []time.Time{
+		time.Date(2025, 3, 1, 0, 0, 0, 0, tz),
+		time.Date(2025, 3, 2, 0, 0, 0, 0, tz),
+	}
+	schedules, errs := a.FetchSchedules(ctx, dates)
+	ta.Len(schedules, 1)
+	ta.Len(errs, 1)
+
+	deliveries, err := a.Post(ctx, event.Digest{Schedules: schedules})
+	ta.Equal([]Delivery{
+		{Sink: "broken", Error: "recovered from panic: sink is broken"},
+		{Sink: "healthy"},
+	}, deliveries)
+	ta.ErrorContains(err, "recovered from panic: sink is broken")
+	ta.Equal("broken", event.FailedComponent(err))
+	ta.Equal(1, healthy.posted)
+}
+
+type staticSource []event.Event
+
+func (s staticSource) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	return s, nil
+}
+
+// 追加の取得元の 1 つがパニックしても、他の取得元のイベントは投稿し、失敗した取得元を記録する
+func TestAppFetchesFromHealthySources(t *testing.T) {
+	ta := assert.New(t)
+
+	src := sources.NewMultiSource(staticSource{{Name: "Piano"}}, panicSource{})
+	a := NewApp(src)
+	schedules, errs := a.FetchSchedules(context.Background(), []time.Time{time.Date(2025, 3, 1, 0, 0, 0, 0, tz)})
+	ta.Len(schedules, 1)
+	ta.Equal([]event.Event{{Name: "Piano"}}, schedules[0].Events)
+	ta.Len(errs, 1)
+	ta.ErrorContains(errs[0], "recovered from panic")
+	ta.Equal("digest", event.FailedComponent(errs[0]))
+}
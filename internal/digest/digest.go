@@ -0,0 +1,58 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// 未対応のイベントの取得元
+type OverdueSource interface {
+	// 未対応のイベントと、催促が必要かどうかを返す
+	Overdue(ctx context.Context, today time.Time) ([]event.Event, bool, error)
+}
+
+type Options struct {
+	Overdue      OverdueSource // nil の場合は未対応のイベントを取得しない
+	UpcomingDays int           // 0 の場合は今後のイベントを取得しない
+}
+
+// 投稿対象の日付のイベントに、未対応のイベントと今後のイベントを加えた投稿内容を作成する
+// 投稿対象の全ての日付で取得に失敗した場合のみエラーを返し、それ以外の失敗は Failures に含める
+func Build(ctx context.Context, a *App, today time.Time, dates []time.Time, opts Options) (event.Digest, error) {
+	schedules, errs := a.FetchSchedules(ctx, dates)
+	if len(dates) > 0 && len(schedules) == 0 {
+		return event.Digest{}, errors.Join(errs...)
+	}
+	d := event.Digest{Schedules: schedules, Failures: errs}
+
+	// 未対応のイベントを取得する
+	if opts.Overdue != nil {
+		overdue, escalate, err := opts.Overdue.Overdue(ctx, today)
+		if err != nil {
+			slog.Warn("failed to get unacknowledged events", slog.Any("error", err))
+			d.Failures = append(d.Failures, err)
+		}
+		d.Overdue = overdue
+		d.Escalate = escalate
+	}
+
+	// 今後のイベント情報を取得する
+	if opts.UpcomingDays > 0 {
+		var days []time.Time
+		for i := 0; i < opts.UpcomingDays; i++ {
+			days = append(days, today.AddDate(0, 0, i))
+		}
+		upcoming, errs := a.FetchSchedules(ctx, days)
+		if len(errs) > 0 {
+			slog.Warn("failed to get upcoming events", slog.Any("error", errors.Join(errs...)))
+		}
+		d.Upcoming = upcoming
+		d.Failures = append(d.Failures, errs...)
+	}
+
+	return d, nil
+}
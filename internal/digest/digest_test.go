@@ -0,0 +1,81 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeOverdue struct {
+	events   []event.Event
+	escalate bool
+	err      error
+}
+
+func (f fakeOverdue) Overdue(ctx context.Context, today time.Time) ([]event.Event, bool, error) {
+	return f.events, f.escalate, f.err
+}
+
+func TestBuild(t *testing.T) {
+	today := time.Date(2025, 3, 2, 0, 0, 0, 0, tz)
+	tests := []struct {
+		name             string
+		dates            []time.Time
+		opts             Options
+		expectError      bool
+		expectSchedules  int
+		expectOverdue    []event.Event
+		expectEscalate   bool
+		expectUpcoming   int
+		expectedFailures int
+	}{
+		{
+			name:            "正常系/未対応のイベントと今後のイベントを加える",
+			dates:           []time.Time{today},
+			opts:            Options{Overdue: fakeOverdue{events: []event.Event{{Name: "Bills"}}, escalate: true}, UpcomingDays: 3},
+			expectSchedules: 1,
+			expectOverdue:   []event.Event{{Name: "Bills"}},
+			expectEscalate:  true,
+			expectUpcoming:  3,
+		},
+		{
+			name:             "正常系/未対応のイベントの取得に失敗しても投稿内容を作成する",
+			dates:            []time.Time{today},
+			opts:             Options{Overdue: fakeOverdue{err: errors.New("timeout")}},
+			expectSchedules:  1,
+			expectedFailures: 1,
+		},
+		{
+			name:             "正常系/一部の日付の取得に失敗した場合は失敗を記録する",
+			dates:            []time.Time{time.Date(2025, 3, 1, 0, 0, 0, 0, tz), today},
+			expectSchedules:  1,
+			expectedFailures: 1,
+		},
+		{
+			name:        "異常系/全ての日付の取得に失敗した場合",
+			dates:       []time.Time{time.Date(2025, 3, 1, 0, 0, 0, 0, tz)},
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			d, err := Build(context.Background(), NewApp(panicSource{}), today, tt.dates, tt.opts)
+			if tt.expectError {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Len(d.Schedules, tt.expectSchedules)
+			ta.Equal(tt.expectOverdue, d.Overdue)
+			ta.Equal(tt.expectEscalate, d.Escalate)
+			ta.Len(d.Upcoming, tt.expectUpcoming)
+			ta.Len(d.Failures, tt.expectedFailures)
+		})
+	}
+}
@@ -0,0 +1,286 @@
+// Package discord は hello と remind で共通の Discord の REST API クライアントと型を提供する
+// 使用する API が限られているため、ライブラリを使わずに必要なエンドポイントのみを実装する
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	apiBase      = "https://discord.com/api/v10"
+	maxRetries   = 3
+	maxRetryWait = 30 * time.Second // これより長く待つ必要がある場合は再送せずにエラーを返す
+)
+
+// Bot のトークンで REST API を呼び出すクライアント
+// レート制限に達した場合は指定された時間だけ待ってから再送する
+// 残りの回数が 0 になったエンドポイントは、リセットされるまで待ってから次のリクエストを送る
+type Client struct {
+	http    *http.Client
+	token   string
+	baseURL string
+
+	mu      sync.Mutex
+	resetAt map[string]time.Time // エンドポイントごとのレート制限がリセットされる時刻
+}
+
+// Webhook の実行のみに使う場合、token は空でもよい
+func NewClient(client *http.Client, token string) *Client {
+	return &Client{http: client, token: token, baseURL: apiBase, resetAt: map[string]time.Time{}}
+}
+
+// Discord が返したエラー
+type APIError struct {
+	StatusCode int
+	Code       int    `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("discord returned status %d: %s (code %d)", e.StatusCode, e.Message, e.Code)
+}
+
+type Channel struct {
+	ID       string `json:"id"`
+	GuildID  string `json:"guild_id,omitempty"`
+	ParentID string `json:"parent_id,omitempty"`
+	Name     string `json:"name"`
+	Type     int    `json:"type"`
+}
+
+const channelTypePublicThread = 11
+
+type Webhook struct {
+	ID    string `json:"id"`
+	Token string `json:"token"`
+}
+
+// サーバーのスケジュールイベント、場所を指定する外部のイベントのみを扱う
+type ScheduledEvent struct {
+	ID                 string                  `json:"id,omitempty"`
+	Name               string                  `json:"name"`
+	Description        string                  `json:"description,omitempty"`
+	ScheduledStartTime time.Time               `json:"scheduled_start_time"`
+	ScheduledEndTime   *time.Time              `json:"scheduled_end_time,omitempty"`
+	PrivacyLevel       int                     `json:"privacy_level"`
+	EntityType         int                     `json:"entity_type"`
+	EntityMetadata     *ScheduledEventMetadata `json:"entity_metadata,omitempty"`
+}
+
+type ScheduledEventMetadata struct {
+	Location string `json:"location,omitempty"`
+}
+
+const (
+	PrivacyLevelGuildOnly = 2
+	EntityTypeExternal    = 3
+)
+
+func (c *Client) Channel(ctx context.Context, channelID string) (*Channel, error) {
+	var ch Channel
+	if err := c.call(ctx, http.MethodGet, "/channels/"+channelID, nil, &ch); err != nil {
+		return nil, err
+	}
+
+	return &ch, nil
+}
+
+func (c *Client) ActiveThreads(ctx context.Context, guildID string) ([]Channel, error) {
+	var resp struct {
+		Threads []Channel `json:"threads"`
+	}
+	if err := c.call(ctx, http.MethodGet, "/guilds/"+guildID+"/threads/active", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Threads, nil
+}
+
+// 公開スレッドを作成する、archiveMinutes 分操作がなければ自動でアーカイブされる
+func (c *Client) StartThread(ctx context.Context, channelID, name string, archiveMinutes int) (*Channel, error) {
+	body := map[string]any{
+		"name":                  name,
+		"type":                  channelTypePublicThread,
+		"auto_archive_duration": archiveMinutes,
+	}
+	var ch Channel
+	if err := c.call(ctx, http.MethodPost, "/channels/"+channelID+"/threads", body, &ch); err != nil {
+		return nil, err
+	}
+
+	return &ch, nil
+}
+
+func (c *Client) CreateWebhook(ctx context.Context, channelID, name string) (*Webhook, error) {
+	var w Webhook
+	if err := c.call(ctx, http.MethodPost, "/channels/"+channelID+"/webhooks", map[string]string{"name": name}, &w); err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, webhookID string) error {
+	return c.call(ctx, http.MethodDelete, "/webhooks/"+webhookID, nil, nil)
+}
+
+func (c *Client) ScheduledEvents(ctx context.Context, guildID string) ([]ScheduledEvent, error) {
+	var events []ScheduledEvent
+	if err := c.call(ctx, http.MethodGet, "/guilds/"+guildID+"/scheduled-events", nil, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (c *Client) CreateScheduledEvent(ctx context.Context, guildID string, e *ScheduledEvent) (*ScheduledEvent, error) {
+	var created ScheduledEvent
+	if err := c.call(ctx, http.MethodPost, "/guilds/"+guildID+"/scheduled-events", e, &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// 遅延させた Interaction の応答の内容を設定する、Bot のトークンは不要
+func (c *Client) EditOriginalResponse(ctx context.Context, applicationID, token string, data *InteractionResponseData) error {
+	return c.call(ctx, http.MethodPatch, "/webhooks/"+applicationID+"/"+token+"/messages/@original", data, nil)
+}
+
+// JSON のリクエストを送り、レスポンスを out に格納する
+func (c *Client) call(ctx context.Context, method, path string, body, out any) error {
+	var b []byte
+	if body != nil {
+		var err error
+		if b, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	return c.do(ctx, method, c.baseURL+path, "application/json", b, out)
+}
+
+// レート制限に達した場合は retry_after 秒待ってから、maxRetries 回まで再送する
+func (c *Client) do(ctx context.Context, method, url, contentType string, body []byte, out any) error {
+	bucket, _, _ := strings.Cut(url, "?")
+	for attempt := 0; ; attempt++ {
+		if err := c.waitForReset(ctx, bucket); err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bot "+c.token)
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return err
+		}
+		b, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		c.observeRateLimit(bucket, resp.Header)
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRetries {
+			wait := retryAfter(resp.Header, b)
+			if wait <= maxRetryWait && withinDeadline(ctx, wait) {
+				slog.Warn("rate limited by discord", slog.String("method", method), slog.Duration("retry_after", wait))
+				if err := sleep(ctx, wait); err != nil {
+					return err
+				}
+				continue
+			}
+			slog.Warn("rate limited by discord for too long to retry", slog.String("method", method), slog.Duration("retry_after", wait))
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			apiErr := &APIError{StatusCode: resp.StatusCode}
+			if err := json.Unmarshal(b, apiErr); err != nil {
+				apiErr.Message = string(b)
+			}
+			return apiErr
+		}
+		if out == nil || len(b) == 0 {
+			return nil
+		}
+
+		return json.Unmarshal(b, out)
+	}
+}
+
+// 残りの回数が 0 になった場合は、X-RateLimit-Reset-After 秒後まで次のリクエストを待たせる
+func (c *Client) observeRateLimit(bucket string, h http.Header) {
+	if h.Get("X-RateLimit-Remaining") != "0" {
+		return
+	}
+	s, err := strconv.ParseFloat(h.Get("X-RateLimit-Reset-After"), 64)
+	if err != nil || s <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resetAt[bucket] = time.Now().Add(time.Duration(s * float64(time.Second)))
+}
+
+func (c *Client) waitForReset(ctx context.Context, bucket string) error {
+	c.mu.Lock()
+	wait := time.Until(c.resetAt[bucket])
+	c.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	slog.Debug("waiting for discord rate limit reset", slog.Duration("wait", wait))
+
+	return sleep(ctx, wait)
+}
+
+// 待った後に再送する時間が ctx の期限までに残っているか
+func withinDeadline(ctx context.Context, wait time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+
+	return !ok || time.Until(deadline) > wait
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// レスポンスのボディの retry_after を優先し、なければ Retry-After ヘッダーを使う
+func retryAfter(h http.Header, body []byte) time.Duration {
+	var v struct {
+		RetryAfter float64 `json:"retry_after"`
+	}
+	if err := json.Unmarshal(body, &v); err == nil && v.RetryAfter > 0 {
+		return time.Duration(v.RetryAfter * float64(time.Second))
+	}
+	if s, err := strconv.ParseFloat(h.Get("Retry-After"), 64); err == nil && s > 0 {
+		return time.Duration(s * float64(time.Second))
+	}
+
+	return time.Second
+}
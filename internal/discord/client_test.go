@@ -0,0 +1,143 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannel(t *testing.T) {
+	tests := []struct {
+		name          string
+		statuses      []int
+		expectError   bool
+		expectedCalls int
+	}{
+		{
+			name:          "正常系/レート制限に達しなかった場合",
+			statuses:      []int{200},
+			expectedCalls: 1,
+		},
+		{
+			name:          "正常系/レート制限に達した後に成功した場合",
+			statuses:      []int{429, 429, 200},
+			expectedCalls: 3,
+		},
+		{
+			name:          "異常系/レート制限に達し続けた場合",
+			statuses:      []int{429, 429, 429, 429, 200},
+			expectError:   true,
+			expectedCalls: 4,
+		},
+		{
+			name:          "異常系/チャンネルが存在しない場合",
+			statuses:      []int{404},
+			expectError:   true,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ta.Equal("/channels/123", r.URL.Path)
+				ta.Equal("Bot abc", r.Header.Get("Authorization"))
+				status := tt.statuses[calls]
+				calls++
+				w.WriteHeader(status)
+				switch status {
+				case 200:
+					_, _ = w.Write([]byte(`{"id": "123", "guild_id": "456", "name": "general", "type": 0}`))
+				case 429:
+					_, _ = w.Write([]byte(`{"message": "You are being rate limited.", "retry_after": 0.01, "global": false}`))
+				default:
+					_, _ = w.Write([]byte(`{"message": "Unknown Channel", "code": 10003}`))
+				}
+			}))
+			defer srv.Close()
+
+			c := NewClient(srv.Client(), "abc")
+			c.baseURL = srv.URL
+			ch, err := c.Channel(context.Background(), "123")
+
+			ta.Equal(tt.expectedCalls, calls)
+			if tt.expectError {
+				ta.Error(err)
+			} else {
+				ta.NoError(err)
+				ta.Equal(&Channel{ID: "123", GuildID: "456", Name: "general"}, ch)
+			}
+		})
+	}
+}
+
+func TestRetryAfterTooLong(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message": "You are being rate limited.", "retry_after": 120, "global": true}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), "abc")
+	c.baseURL = srv.URL
+	_, err := c.Channel(context.Background(), "123")
+
+	// 待つ時間が長すぎる場合は再送しない
+	ta.Equal(1, calls)
+	var apiErr *APIError
+	tr.ErrorAs(err, &apiErr)
+	ta.Equal(http.StatusTooManyRequests, apiErr.StatusCode)
+}
+
+func TestEditOriginalResponse(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ta.Equal(http.MethodPatch, r.Method)
+		ta.Equal("/webhooks/app/tok/messages/@original", r.URL.Path)
+		ta.Empty(r.Header.Get("Authorization"))
+		ta.NoError(json.NewDecoder(r.Body).Decode(&got))
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), "")
+	c.baseURL = srv.URL
+	tr.NoError(c.EditOriginalResponse(context.Background(), "app", "tok", &InteractionResponseData{Content: "✅ Marked as done", Flags: FlagEphemeral}))
+
+	ta.Equal(map[string]any{"content": "✅ Marked as done", "flags": float64(FlagEphemeral)}, got)
+}
+
+func TestAPIError(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message": "Missing Permissions", "code": 50013}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), "abc")
+	c.baseURL = srv.URL
+	err := c.DeleteWebhook(context.Background(), "123")
+
+	var apiErr *APIError
+	tr.ErrorAs(err, &apiErr)
+	ta.Equal(&APIError{StatusCode: 403, Code: 50013, Message: "Missing Permissions"}, apiErr)
+}
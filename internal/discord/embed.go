@@ -0,0 +1,151 @@
+package discord
+
+import "slices"
+
+// Discord が受け付ける長さの上限
+const (
+	titleLimit       = 256
+	fieldNameLimit   = 256
+	fieldValueLimit  = 1024
+	fieldsLimit      = 25
+	footerLimit      = 2048
+	buttonLabelLimit = 80
+)
+
+// 1 件のメッセージに含められる Embed の上限
+const EmbedsLimit = 10
+
+// 各コマンドで共通して使う Embed の色
+const (
+	ColorBlue   = 0x3498db
+	ColorGreen  = 0x2ecc71
+	ColorYellow = 0xf1c40f
+	ColorOrange = 0xe67e22
+	ColorRed    = 0xe74c3c
+)
+
+type Embed struct {
+	Title       string        `json:"title,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Color       int           `json:"color,omitempty"`
+	Fields      []*EmbedField `json:"fields"`
+	Footer      *EmbedFooter  `json:"footer,omitempty"`
+}
+
+type EmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+type EmbedFooter struct {
+	Text string `json:"text"`
+}
+
+// フィールドが空でも投稿できるように、Fields は空のスライスで初期化する
+func NewEmbed(title string, color int) *Embed {
+	return &Embed{Title: Truncate(title, titleLimit), Color: color, Fields: []*EmbedField{}}
+}
+
+// フィールドの数の上限を超えても追加し、投稿する前に Split で続きの Embed に分ける
+func (e *Embed) AddField(name, value string) *Embed {
+	e.Fields = append(e.Fields, &EmbedField{
+		Name:  Truncate(name, fieldNameLimit),
+		Value: Truncate(value, fieldValueLimit),
+	})
+
+	return e
+}
+
+// フィールドの数が上限を超える場合は、同じタイトルと色の続きの Embed に分ける
+// 説明は最初の Embed に、フッターは最後の Embed にのみ付ける
+func (e *Embed) Split() []*Embed {
+	if len(e.Fields) <= fieldsLimit {
+		return []*Embed{e}
+	}
+
+	var embeds []*Embed
+	for fields := range slices.Chunk(e.Fields, fieldsLimit) {
+		embeds = append(embeds, &Embed{Title: e.Title, Color: e.Color, Fields: fields})
+	}
+	embeds[0].Description = e.Description
+	embeds[len(embeds)-1].Footer = e.Footer
+
+	return embeds
+}
+
+// Embed をフィールドの数の上限で分けたうえで、1 件のメッセージに含められる数ごとにまとめる
+func ChunkEmbeds(embeds []*Embed) [][]*Embed {
+	var split []*Embed
+	for _, e := range embeds {
+		split = append(split, e.Split()...)
+	}
+
+	return slices.Collect(slices.Chunk(split, EmbedsLimit))
+}
+
+// Embed のみのメッセージを、1 件のメッセージに含められる数ごとに作成する
+func NewEmbedMessages(embeds []*Embed) []*WebhookMessage {
+	var messages []*WebhookMessage
+	for _, chunk := range ChunkEmbeds(embeds) {
+		messages = append(messages, &WebhookMessage{Embeds: chunk})
+	}
+
+	return messages
+}
+
+func (e *Embed) SetFooter(text string) *Embed {
+	e.Footer = &EmbedFooter{Text: Truncate(text, footerLimit)}
+
+	return e
+}
+
+type ButtonStyle int
+
+const (
+	PrimaryButton   ButtonStyle = 1
+	SecondaryButton ButtonStyle = 2
+	SuccessButton   ButtonStyle = 3
+	DangerButton    ButtonStyle = 4
+)
+
+const (
+	componentTypeActionRow = 1
+	componentTypeButton    = 2
+)
+
+// メッセージに付けるボタンと、ボタンを並べる Action Row
+type Component struct {
+	Type       int         `json:"type"`
+	Style      ButtonStyle `json:"style,omitempty"`
+	Label      string      `json:"label,omitempty"`
+	CustomID   string      `json:"custom_id,omitempty"`
+	Disabled   bool        `json:"disabled,omitempty"`
+	Components []Component `json:"components,omitempty"`
+}
+
+func ActionRow(components ...Component) Component {
+	return Component{Type: componentTypeActionRow, Components: components}
+}
+
+func Button(style ButtonStyle, label, customID string) Component {
+	return Component{Type: componentTypeButton, Style: style, Label: Truncate(label, buttonLabelLimit), CustomID: customID}
+}
+
+// 押せないボタン、操作が終わったボタンを置き換える
+func DisabledButton(style ButtonStyle, label, customID string) Component {
+	b := Button(style, label, customID)
+	b.Disabled = true
+
+	return b
+}
+
+// 文字数が n を超える場合は末尾を省略する
+func Truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,170 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+type InteractionType int
+
+const (
+	InteractionPing               InteractionType = 1
+	InteractionApplicationCommand InteractionType = 2
+	InteractionMessageComponent   InteractionType = 3
+)
+
+// Interactions Endpoint で受け取るリクエスト
+type Interaction struct {
+	Type          InteractionType `json:"type"`
+	Data          InteractionData `json:"data"`
+	ApplicationID string          `json:"application_id"`
+	Token         string          `json:"token"`  // 応答を後から編集する場合に使う、15 分間有効
+	Member        *Member         `json:"member"` // サーバー内で操作された場合に設定される
+	User          *User           `json:"user"`   // DM で操作された場合に設定される
+}
+
+type InteractionData struct {
+	Name     string              `json:"name"`
+	CustomID string              `json:"custom_id"` // MessageComponent の場合に押されたボタンの ID
+	Options  []InteractionOption `json:"options"`   // ApplicationCommand の場合の引数
+}
+
+// スラッシュコマンドの引数、サブコマンドの場合は Options にサブコマンドの引数を持つ
+type InteractionOption struct {
+	Name    string              `json:"name"`
+	Type    int                 `json:"type"`
+	Value   any                 `json:"value"` // 文字列、数値 (float64)、真偽値のいずれか
+	Options []InteractionOption `json:"options"`
+}
+
+type Member struct {
+	User *User  `json:"user"`
+	Nick string `json:"nick"`
+}
+
+type User struct {
+	ID         string `json:"id"`
+	Username   string `json:"username"`
+	GlobalName string `json:"global_name"`
+}
+
+// 名前が一致する引数を返す
+func FindOption(options []InteractionOption, name string) (InteractionOption, bool) {
+	for _, o := range options {
+		if o.Name == name {
+			return o, true
+		}
+	}
+
+	return InteractionOption{}, false
+}
+
+// 文字列の引数の値を返す、文字列でない場合は空文字列を返す
+func (o InteractionOption) String() string {
+	s, _ := o.Value.(string)
+
+	return s
+}
+
+// 数値の引数の値を返す、数値でない場合は false を返す
+func (o InteractionOption) Number() (float64, bool) {
+	n, ok := o.Value.(float64)
+
+	return n, ok
+}
+
+// 操作したユーザーの表示名を返す、サーバーのニックネーム、表示名、ユーザー名の順に使う
+func (i Interaction) UserName() string {
+	u := i.User
+	if i.Member != nil {
+		if i.Member.Nick != "" {
+			return i.Member.Nick
+		}
+		u = i.Member.User
+	}
+	if u == nil {
+		return ""
+	}
+	if u.GlobalName != "" {
+		return u.GlobalName
+	}
+
+	return u.Username
+}
+
+// 操作したユーザーの ID を返す
+func (i Interaction) UserID() string {
+	u := i.User
+	if i.Member != nil {
+		u = i.Member.User
+	}
+	if u == nil {
+		return ""
+	}
+
+	return u.ID
+}
+
+type InteractionResponseType int
+
+const (
+	ResponsePong                     InteractionResponseType = 1
+	ResponseChannelMessageWithSource InteractionResponseType = 4
+	ResponseDeferredChannelMessage   InteractionResponseType = 5 // 処理中と表示し、後から EditOriginalResponse で内容を設定する
+	ResponseUpdateMessage            InteractionResponseType = 7 // ボタンが押されたメッセージを更新する
+)
+
+type InteractionResponse struct {
+	Type InteractionResponseType  `json:"type"`
+	Data *InteractionResponseData `json:"data,omitempty"`
+}
+
+type InteractionResponseData struct {
+	Content    string      `json:"content"`
+	Flags      int         `json:"flags,omitempty"`
+	Components []Component `json:"components,omitempty"`
+}
+
+// Interactions Endpoint で受け取ったボディをパースする、対応していない種類の場合はエラーを返す
+func ParseInteraction(body string) (Interaction, error) {
+	var i Interaction
+	if err := json.Unmarshal([]byte(body), &i); err != nil {
+		return Interaction{}, fmt.Errorf("failed to parse request body")
+	}
+	switch i.Type {
+	case InteractionPing, InteractionApplicationCommand, InteractionMessageComponent:
+		return i, nil
+	default:
+		return Interaction{}, fmt.Errorf("unknown interaction type: %d", i.Type)
+	}
+}
+
+// 操作したユーザーにのみ表示する
+const FlagEphemeral = 1 << 6
+
+// Discord による Interaction の署名を検証する
+// publicKey と signature は 16 進数の文字列で、timestamp とボディを連結した値に対する署名を検証する
+func VerifySignature(publicKey, signature, timestamp, body string) error {
+	key, err := hex.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("public key format is invalid")
+	}
+	if signature == "" {
+		return fmt.Errorf("signature is blank")
+	}
+	if timestamp == "" {
+		return fmt.Errorf("timestamp is blank")
+	}
+
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(key, []byte(timestamp+body), sig) {
+		return fmt.Errorf("signature format is invalid")
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	body := `{"type":1}`
+	timestamp := "1736899200"
+	signature := hex.EncodeToString(ed25519.Sign(priv, []byte(timestamp+body)))
+
+	tests := []struct {
+		name        string
+		publicKey   string
+		signature   string
+		timestamp   string
+		body        string
+		expectError bool
+	}{
+		{
+			name:      "正常系/署名が正しい場合",
+			publicKey: hex.EncodeToString(pub),
+			signature: signature,
+			timestamp: timestamp,
+			body:      body,
+		},
+		{
+			name:        "異常系/ボディが改ざんされた場合",
+			publicKey:   hex.EncodeToString(pub),
+			signature:   signature,
+			timestamp:   timestamp,
+			body:        `{"type":2}`,
+			expectError: true,
+		},
+		{
+			name:        "異常系/署名がない場合",
+			publicKey:   hex.EncodeToString(pub),
+			timestamp:   timestamp,
+			body:        body,
+			expectError: true,
+		},
+		{
+			name:        "異常系/公開鍵の長さが不正な場合",
+			publicKey:   "abcd",
+			signature:   signature,
+			timestamp:   timestamp,
+			body:        body,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			err := VerifySignature(tt.publicKey, tt.signature, tt.timestamp, tt.body)
+
+			if tt.expectError {
+				ta.Error(err)
+			} else {
+				ta.NoError(err)
+			}
+		})
+	}
+}
+
+func TestParseInteraction(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		expected    Interaction
+		expectError bool
+	}{
+		{
+			name:     "正常系/ボタンが押された場合",
+			body:     `{"type":3,"data":{"custom_id":"ack:done:20250301:abc"},"application_id":"1","token":"tok"}`,
+			expected: Interaction{Type: InteractionMessageComponent, Data: InteractionData{CustomID: "ack:done:20250301:abc"}, ApplicationID: "1", Token: "tok"},
+		},
+		{
+			name: "正常系/引数付きのコマンドが実行された場合",
+			body: `{"type":2,"data":{"name":"expense","options":[{"name":"amount","type":4,"value":1200},{"name":"category","type":3,"value":"食費"}]},"member":{"user":{"id":"1","username":"alice"}}}`,
+			expected: Interaction{
+				Type: InteractionApplicationCommand,
+				Data: InteractionData{Name: "expense", Options: []InteractionOption{
+					{Name: "amount", Type: 4, Value: float64(1200)},
+					{Name: "category", Type: 3, Value: "食費"},
+				}},
+				Member: &Member{User: &User{ID: "1", Username: "alice"}},
+			},
+		},
+		{
+			name:        "異常系/JSON でない場合",
+			body:        "type=1",
+			expectError: true,
+		},
+		{
+			name:        "異常系/種類が不明な場合",
+			body:        `{"type":9}`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			actual, err := ParseInteraction(tt.body)
+			if tt.expectError {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, actual)
+		})
+	}
+}
+
+func TestFindOption(t *testing.T) {
+	ta := assert.New(t)
+
+	options := []InteractionOption{
+		{Name: "amount", Value: float64(1200)},
+		{Name: "memo", Value: "ランチ"},
+	}
+
+	o, ok := FindOption(options, "amount")
+	ta.True(ok)
+	n, ok := o.Number()
+	ta.True(ok)
+	ta.Equal(1200.0, n)
+	ta.Empty(o.String())
+
+	o, ok = FindOption(options, "memo")
+	ta.True(ok)
+	ta.Equal("ランチ", o.String())
+	_, ok = o.Number()
+	ta.False(ok)
+
+	_, ok = FindOption(options, "category")
+	ta.False(ok)
+}
+
+func TestInteractionUserName(t *testing.T) {
+	tests := []struct {
+		name        string
+		interaction Interaction
+		expected    string
+	}{
+		{
+			name:        "正常系/サーバーのニックネームを優先する場合",
+			interaction: Interaction{Member: &Member{Nick: "お母さん", User: &User{Username: "alice", GlobalName: "Alice"}}},
+			expected:    "お母さん",
+		},
+		{
+			name:        "正常系/表示名を使う場合",
+			interaction: Interaction{Member: &Member{User: &User{Username: "alice", GlobalName: "Alice"}}},
+			expected:    "Alice",
+		},
+		{
+			name:        "正常系/DM で操作された場合",
+			interaction: Interaction{User: &User{Username: "bob"}},
+			expected:    "bob",
+		},
+		{
+			name:        "正常系/ユーザーが不明な場合",
+			interaction: Interaction{},
+			expected:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.interaction.UserName())
+		})
+	}
+}
+
+func TestInteractionUserID(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.Equal("123", Interaction{Member: &Member{Nick: "お母さん", User: &User{ID: "123"}}}.UserID())
+	ta.Equal("456", Interaction{User: &User{ID: "456"}}.UserID())
+	ta.Equal("", Interaction{Member: &Member{}}.UserID())
+}
+
+// 外部から受け取る値のため、どのような入力でも panic せずにエラーを返すことを確認する
+func FuzzParseInteraction(f *testing.F) {
+	f.Add(`{"type":1}`)
+	f.Add(`{"type":3,"data":{"custom_id":"ack:done:20250301:abc"}}`)
+	f.Add(`{"type":"1"}`)
+	f.Add(`[]`)
+
+	f.Fuzz(func(t *testing.T, body string) {
+		i, err := ParseInteraction(body)
+		if err != nil && !reflect.DeepEqual(i, Interaction{}) {
+			t.Fatalf("returned %+v with error %v", i, err)
+		}
+	})
+}
+
+func FuzzVerifySignature(f *testing.F) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(f, err)
+	publicKey := hex.EncodeToString(pub)
+
+	f.Add(publicKey, "", "1736899200", `{"type":1}`)
+	f.Add(publicKey, "zz", "1736899200", `{"type":1}`)
+	f.Add("abcd", "00", "", "")
+
+	f.Fuzz(func(t *testing.T, key, signature, timestamp, body string) {
+		// 不正な公開鍵や署名でも panic しない
+		_ = VerifySignature(key, signature, timestamp, body)
+
+		// 正しく署名したリクエストは検証できる
+		if timestamp == "" {
+			return
+		}
+		sig := hex.EncodeToString(ed25519.Sign(priv, []byte(timestamp+body)))
+		if err := VerifySignature(publicKey, sig, timestamp, body); err != nil {
+			t.Fatalf("failed to verify valid signature: %v", err)
+		}
+	})
+}
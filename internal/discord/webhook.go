@@ -0,0 +1,79 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+)
+
+// Webhook で投稿するメッセージ
+type WebhookMessage struct {
+	Content    string      `json:"content,omitempty"`
+	Username   string      `json:"username,omitempty"`
+	Embeds     []*Embed    `json:"embeds,omitempty"`
+	Components []Component `json:"components,omitempty"`
+	Files      []*File     `json:"-"`
+}
+
+// メッセージに添付するファイル
+type File struct {
+	Name        string
+	ContentType string
+	Reader      io.Reader
+}
+
+// Webhook の URL を返す、threadID を指定するとスレッドに投稿する
+func (c *Client) WebhookURL(w *Webhook, threadID string) string {
+	u := fmt.Sprintf("%s/webhooks/%s/%s", c.baseURL, w.ID, w.Token)
+	if threadID != "" {
+		u += "?thread_id=" + url.QueryEscape(threadID)
+	}
+
+	return u
+}
+
+// Webhook でメッセージを投稿する、ファイルを添付する場合は multipart/form-data で送る
+func (c *Client) ExecuteWebhook(ctx context.Context, webhookURL string, msg *WebhookMessage) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if len(msg.Files) == 0 {
+		return c.do(ctx, http.MethodPost, webhookURL, "application/json", payload, nil)
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	if err := mw.WriteField("payload_json", string(payload)); err != nil {
+		return err
+	}
+	for i, f := range msg.Files {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="files[%d]"; filename="%s"`, i, f.Name))
+		h.Set("Content-Type", f.ContentType)
+		part, err := mw.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		// 再送時にも先頭から読めるように巻き戻す
+		if s, ok := f.Reader.(io.Seeker); ok {
+			if _, err := s.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+		}
+		if _, err := io.Copy(part, f.Reader); err != nil {
+			return err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	return c.do(ctx, http.MethodPost, webhookURL, mw.FormDataContentType(), buf.Bytes(), nil)
+}
@@ -0,0 +1,191 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteWebhook(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	type received struct {
+		query       string
+		contentType string
+		payload     map[string]any
+		files       map[string]string
+	}
+	var got []received
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ta.Equal("/webhooks/1/tok", r.URL.Path)
+		ta.Empty(r.Header.Get("Authorization"))
+		rv := received{query: r.URL.RawQuery, contentType: r.Header.Get("Content-Type"), files: map[string]string{}}
+		if strings.HasPrefix(rv.contentType, "multipart/form-data") {
+			tr.NoError(r.ParseMultipartForm(1 << 20))
+			ta.NoError(json.Unmarshal([]byte(r.FormValue("payload_json")), &rv.payload))
+			for name, headers := range r.MultipartForm.File {
+				f, err := headers[0].Open()
+				tr.NoError(err)
+				b, err := io.ReadAll(f)
+				tr.NoError(err)
+				rv.files[name+"/"+headers[0].Filename] = string(b)
+			}
+		} else {
+			ta.NoError(json.NewDecoder(r.Body).Decode(&rv.payload))
+		}
+		got = append(got, rv)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), "")
+	c.baseURL = srv.URL
+	url := c.WebhookURL(&Webhook{ID: "1", Token: "tok"}, "789")
+
+	tr.NoError(c.ExecuteWebhook(context.Background(), url, &WebhookMessage{Content: "hello"}))
+	tr.NoError(c.ExecuteWebhook(context.Background(), url, &WebhookMessage{
+		Embeds: []*Embed{NewEmbed("Today", 0x3fb950).AddField("Garbage", "Interval: Weekly")},
+		Files:  []*File{{Name: "events.ics", ContentType: "text/calendar", Reader: strings.NewReader("BEGIN:VCALENDAR")}},
+	}))
+
+	tr.Len(got, 2)
+	ta.Equal("thread_id=789", got[0].query)
+	ta.Equal("application/json", got[0].contentType)
+	ta.Equal(map[string]any{"content": "hello"}, got[0].payload)
+
+	ta.Equal(map[string]any{
+		"embeds": []any{map[string]any{
+			"title":  "Today",
+			"color":  float64(0x3fb950),
+			"fields": []any{map[string]any{"name": "Garbage", "value": "Interval: Weekly"}},
+		}},
+	}, got[1].payload)
+	ta.Equal(map[string]string{"files[0]/events.ics": "BEGIN:VCALENDAR"}, got[1].files)
+}
+
+func TestExecuteWebhookWaitsForReset(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	var times []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset-After", "0.1")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.Client(), "")
+	c.baseURL = srv.URL
+	url := c.WebhookURL(&Webhook{ID: "1", Token: "tok"}, "")
+
+	// 分割したメッセージを続けて投稿しても、レート制限がリセットされるまで待ってから送る
+	tr.NoError(c.ExecuteWebhook(context.Background(), url, &WebhookMessage{Content: "1/2"}))
+	tr.NoError(c.ExecuteWebhook(context.Background(), url, &WebhookMessage{Content: "2/2"}))
+
+	tr.Len(times, 2)
+	ta.GreaterOrEqual(times[1].Sub(times[0]), 100*time.Millisecond)
+}
+
+func TestEmbedLimits(t *testing.T) {
+	ta := assert.New(t)
+
+	e := NewEmbed(strings.Repeat("あ", 300), 0)
+	for range 30 {
+		e.AddField("name", strings.Repeat("a", 2000))
+	}
+	ta.Len([]rune(e.Title), titleLimit)
+	ta.Len(e.Fields[0].Value, fieldValueLimit-1+len("…"))
+
+	b := Button(SuccessButton, strings.Repeat("a", 100), "ack:done")
+	ta.Len([]rune(b.Label), buttonLabelLimit)
+}
+
+func TestEmbedSplit(t *testing.T) {
+	t.Run("正常系/上限以下の場合は分けない", func(t *testing.T) {
+		e := NewEmbed("Today", 0x3fb950).AddField("a", "b")
+		assert.Equal(t, []*Embed{e}, e.Split())
+	})
+
+	t.Run("正常系/上限を超えるフィールドを続きの Embed に分ける", func(t *testing.T) {
+		ta := assert.New(t)
+
+		e := NewEmbed("Today", 0x3fb950)
+		e.Description = "description"
+		for i := range 30 {
+			e.AddField(fmt.Sprintf("Event %02d", i+1), "value")
+		}
+		e.SetFooter("footer")
+
+		embeds := e.Split()
+		ta.Len(embeds, 2)
+		ta.Len(embeds[0].Fields, fieldsLimit)
+		ta.Len(embeds[1].Fields, 5)
+		ta.Equal("Event 26", embeds[1].Fields[0].Name)
+		ta.Equal("Today", embeds[1].Title)
+		ta.Equal(0x3fb950, embeds[1].Color)
+		ta.Equal("description", embeds[0].Description)
+		ta.Empty(embeds[1].Description)
+		ta.Nil(embeds[0].Footer)
+		ta.Equal("footer", embeds[1].Footer.Text)
+	})
+}
+
+func TestChunkEmbeds(t *testing.T) {
+	cases := []struct {
+		name   string
+		embeds int
+		fields int
+		want   []int
+	}{
+		{name: "正常系/上限以下の場合は 1 件にまとめる", embeds: EmbedsLimit, fields: 1, want: []int{EmbedsLimit}},
+		{name: "正常系/上限を超える Embed を分ける", embeds: 12, fields: 1, want: []int{EmbedsLimit, 2}},
+		{name: "正常系/フィールドで分けた Embed も数える", embeds: 6, fields: 30, want: []int{EmbedsLimit, 2}},
+		{name: "正常系/Embed がない場合", embeds: 0, fields: 0, want: nil},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var embeds []*Embed
+			for range tt.embeds {
+				e := NewEmbed("Today", 0x3fb950)
+				for i := range tt.fields {
+					e.AddField(fmt.Sprintf("Event %02d", i+1), "value")
+				}
+				embeds = append(embeds, e)
+			}
+
+			var got []int
+			for _, chunk := range ChunkEmbeds(embeds) {
+				got = append(got, len(chunk))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewEmbedMessages(t *testing.T) {
+	ta := assert.New(t)
+
+	var embeds []*Embed
+	for i := range 12 {
+		embeds = append(embeds, NewEmbed(fmt.Sprintf("Host %02d", i+1), ColorRed))
+	}
+
+	messages := NewEmbedMessages(embeds)
+	ta.Len(messages, 2)
+	ta.Len(messages[0].Embeds, EmbedsLimit)
+	ta.Equal("Host 11", messages[1].Embeds[0].Title)
+	ta.Empty(NewEmbedMessages(nil))
+}
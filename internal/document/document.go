@@ -0,0 +1,138 @@
+// Package document はスプレッドシートに記録したパスポートや在留カード、運転免許証などの有効期限と、更新の通知を提供する
+package document
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 書類を記録するシートの列、1 行目はヘッダーとする
+// A: 書類, B: 名義人, C: 有効期限 (2006-01-02), D: 更新の準備期間 (e.g. 3m, 6ヶ月, 90d)、空の場合は通知の段階のみ
+const Columns = "A:D"
+
+// 有効期限のある書類
+type Document struct {
+	Row      int // シートの行番号 (1 始まり)
+	Name     string
+	Holder   string
+	Expiry   time.Time
+	LeadTime LeadTime
+}
+
+// 表示用の名前、e.g. パスポート (はな)
+func (d Document) Label() string {
+	if d.Holder == "" {
+		return d.Name
+	}
+
+	return fmt.Sprintf("%s (%s)", d.Name, d.Holder)
+}
+
+// 更新の手続きを始める日、準備期間がない場合はゼロ値を返す
+func (d Document) RenewalStart() time.Time {
+	if d.LeadTime.IsZero() {
+		return time.Time{}
+	}
+
+	return d.LeadTime.Before(d.Expiry)
+}
+
+// 更新の準備期間、月と日のいずれかで指定する
+type LeadTime struct {
+	Months int
+	Days   int
+}
+
+func (l LeadTime) IsZero() bool {
+	return l.Months == 0 && l.Days == 0
+}
+
+// t から準備期間だけ前の日を返す
+func (l LeadTime) Before(t time.Time) time.Time {
+	return t.AddDate(0, -l.Months, -l.Days)
+}
+
+func (l LeadTime) String() string {
+	if l.Months > 0 {
+		return fmt.Sprintf("%d か月", l.Months)
+	}
+
+	return fmt.Sprintf("%d 日", l.Days)
+}
+
+var leadTimePattern = regexp.MustCompile(`^(\d+)\s*(d|w|m|日|週|週間|ヶ月|か月|カ月|ケ月)$`)
+
+// e.g. 90d, 2w, 3m, 6ヶ月
+func ParseLeadTime(s string) (LeadTime, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return LeadTime{}, nil
+	}
+	m := leadTimePattern.FindStringSubmatch(s)
+	if m == nil {
+		return LeadTime{}, fmt.Errorf("invalid lead time: %s", s)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n <= 0 {
+		return LeadTime{}, fmt.Errorf("invalid lead time: %s", s)
+	}
+	switch m[2] {
+	case "d", "日":
+		return LeadTime{Days: n}, nil
+	case "w", "週", "週間":
+		return LeadTime{Days: n * 7}, nil
+	default:
+		return LeadTime{Months: n}, nil
+	}
+}
+
+// シートの行から書類を読み込む、1 行目はヘッダーとして読み飛ばす
+// 書類が空の行は読み飛ばし、形式が不正な行はエラーを返す
+func ParseRows(rows [][]interface{}, loc *time.Location) ([]Document, error) {
+	var docs []Document
+	for i := 1; i < len(rows); i++ {
+		r := rows[i]
+		if cell(r, 0) == "" {
+			continue
+		}
+		expiry, err := time.ParseInLocation("2006/1/2", strings.ReplaceAll(cell(r, 2), "-", "/"), loc)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid expiry: %s", i+1, cell(r, 2))
+		}
+		lead, err := ParseLeadTime(cell(r, 3))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		docs = append(docs, Document{
+			Row:      i + 1,
+			Name:     cell(r, 0),
+			Holder:   cell(r, 1),
+			Expiry:   expiry,
+			LeadTime: lead,
+		})
+	}
+
+	return docs, nil
+}
+
+// 通知する月数が正の整数であることを確認する
+func ValidateMonths(months []int) error {
+	for _, v := range months {
+		if v <= 0 {
+			return fmt.Errorf("invalid months: %d", v)
+		}
+	}
+
+	return nil
+}
+
+func cell(r []interface{}, i int) string {
+	if i >= len(r) {
+		return ""
+	}
+
+	return strings.TrimSpace(fmt.Sprint(r[i]))
+}
@@ -0,0 +1,119 @@
+package document
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseLeadTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected LeadTime
+		wantErr  bool
+	}{
+		{name: "正常系/空の場合", input: "", expected: LeadTime{}},
+		{name: "正常系/日", input: "90d", expected: LeadTime{Days: 90}},
+		{name: "正常系/週", input: "2週間", expected: LeadTime{Days: 14}},
+		{name: "正常系/月", input: "3M", expected: LeadTime{Months: 3}},
+		{name: "正常系/日本語の月", input: "6ヶ月", expected: LeadTime{Months: 6}},
+		{name: "異常系/単位がない", input: "90", wantErr: true},
+		{name: "異常系/0 の場合", input: "0m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			l, err := ParseLeadTime(tt.input)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, l)
+		})
+	}
+}
+
+func TestParseRows(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	docs, err := ParseRows([][]interface{}{
+		{"書類", "名義人", "有効期限", "更新の準備期間"},
+		{"パスポート", "はな", "2026-03-15", "6ヶ月"},
+		{},
+		{"運転免許証", "", "2026/8/1"},
+	}, time.UTC)
+	tr.NoError(err)
+	ta.Equal([]Document{
+		{Row: 2, Name: "パスポート", Holder: "はな", Expiry: date(2026, 3, 15), LeadTime: LeadTime{Months: 6}},
+		{Row: 4, Name: "運転免許証", Expiry: date(2026, 8, 1)},
+	}, docs)
+	ta.Equal("パスポート (はな)", docs[0].Label())
+	ta.Equal("運転免許証", docs[1].Label())
+
+	_, err = ParseRows([][]interface{}{{"書類"}, {"パスポート", "はな", "来年"}}, time.UTC)
+	ta.Error(err)
+	_, err = ParseRows([][]interface{}{{"書類"}, {"パスポート", "はな", "2026-03-15", "半年"}}, time.UTC)
+	ta.Error(err)
+}
+
+func TestSourceFetch(t *testing.T) {
+	docs := []Document{
+		{Name: "在留カード", Holder: "そら", Expiry: date(2026, 6, 30), LeadTime: LeadTime{Months: 3}},
+		{Name: "運転免許証", Holder: "はな", Expiry: date(2026, 9, 20)},
+	}
+	s := NewSource(docs, []int{1, 6, 3}, map[string]string{"そら": "<@123>"})
+
+	tests := []struct {
+		name     string
+		date     time.Time
+		expected []string // 絵文字と名前
+		assignee string
+		priority int
+		renewal  bool
+	}{
+		{name: "正常系/6 か月前", date: date(2025, 12, 30), expected: []string{"📄 在留カード (そら) の有効期限まで 182 日"}, assignee: "<@123>"},
+		{name: "正常系/3 か月前と更新の手続きを始める日", date: date(2026, 3, 30), expected: []string{"⚠️ 在留カード (そら) の有効期限まで 92 日"}, assignee: "<@123>", renewal: true},
+		{name: "正常系/1 か月前からは毎日", date: date(2026, 6, 10), expected: []string{"🚨 在留カード (そら) の有効期限まで 20 日"}, assignee: "<@123>", priority: 1},
+		{name: "正常系/期限が切れても通知する", date: date(2026, 7, 5), expected: []string{"🚨 在留カード (そら) の有効期限が切れています"}, assignee: "<@123>", priority: 1},
+		{name: "正常系/名義人のメンションがない場合", date: date(2026, 3, 20), expected: []string{"📄 運転免許証 (はな) の有効期限まで 184 日"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			tr := require.New(t)
+
+			events, err := s.Fetch(context.Background(), tt.date)
+			tr.NoError(err)
+			tr.Len(events, len(tt.expected))
+			for i, e := range events {
+				ta.Equal(tt.expected[i], e.Emoji+" "+e.Name)
+				ta.Equal([]string{Tag}, e.Tags)
+			}
+			if len(events) == 0 {
+				return
+			}
+			ta.Equal(tt.assignee, events[0].Assignee)
+			ta.Equal(tt.priority, events[0].Priority)
+			ta.Equal(tt.renewal, strings.Contains(events[0].Description, "今日から更新の手続きを始めましょう"))
+		})
+	}
+
+	// 通知する日でない場合
+	events, err := s.Fetch(context.Background(), date(2025, 11, 1))
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
@@ -0,0 +1,104 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// イベントに付けるタグ
+const Tag = "document"
+
+// 有効期限が近い書類を返す取得元
+// 期限の months か月前のそれぞれの日と更新の手続きを始める日に通知し、最も短い月数を下回ってからは期限を過ぎても毎日通知する
+// 期限が近づくほど目立つように表示を変え、名義人ごとに指定した宛先にメンションする
+type Source struct {
+	docs     []Document
+	months   []int             // 降順
+	mentions map[string]string // 名義人ごとのメンション、e.g. {"はな": "<@123456789>"}
+}
+
+func NewSource(docs []Document, months []int, mentions map[string]string) *Source {
+	sorted := slices.Clone(months)
+	slices.Sort(sorted)
+	slices.Reverse(sorted)
+
+	return &Source{docs: docs, months: sorted, mentions: mentions}
+}
+
+func (s *Source) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	var events []event.Event
+	for _, d := range s.docs {
+		expiry := d.Expiry.In(t.Location())
+		stage, ok := s.stage(expiry, t)
+		renewal := !d.RenewalStart().IsZero() && d.RenewalStart().Equal(t)
+		if !ok && !renewal {
+			continue
+		}
+
+		days := event.DaysBetween(t, expiry)
+		name := fmt.Sprintf("%s の有効期限まで %d 日", d.Label(), days)
+		if days < 0 {
+			name = fmt.Sprintf("%s の有効期限が切れています", d.Label())
+		}
+		lines := []string{fmt.Sprintf("期限: %s", expiry.Format("2006-01-02"))}
+		if !d.LeadTime.IsZero() {
+			lines = append(lines, fmt.Sprintf("更新の準備期間: %s (%s から)", d.LeadTime, d.RenewalStart().Format("2006-01-02")))
+		}
+		if renewal {
+			lines = append(lines, "今日から更新の手続きを始めましょう")
+		}
+
+		e := event.Event{
+			Name:        name,
+			Interval:    event.Onetime,
+			Emoji:       s.emoji(stage),
+			Description: strings.Join(lines, "\n"),
+			Tags:        []string{Tag},
+			Assignee:    s.mentions[d.Holder],
+		}
+		// 最も短い月数を下回ったものは先に表示する
+		if ok && stage == len(s.months)-1 {
+			e.Priority = 1
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
+
+// 期限が近づくにつれて目立つようにする
+func (s *Source) emoji(stage int) string {
+	switch {
+	case stage < 0:
+		return "📄"
+	case stage == len(s.months)-1:
+		return "🚨"
+	case stage > 0:
+		return "⚠️"
+	default:
+		return "📄"
+	}
+}
+
+// t が通知する日であれば、何番目の段階かを返す、通知する日でなければ -1 を返す
+func (s *Source) stage(expiry, t time.Time) (int, bool) {
+	if len(s.months) == 0 {
+		return -1, false
+	}
+	last := len(s.months) - 1
+	if !t.Before(expiry.AddDate(0, -s.months[last], 0)) {
+		return last, true
+	}
+	for i, m := range s.months {
+		if expiry.AddDate(0, -m, 0).Equal(t) {
+			return i, true
+		}
+	}
+
+	return -1, false
+}
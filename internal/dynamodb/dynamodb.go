@@ -0,0 +1,153 @@
+// Package dynamodb は DynamoDB の API を直接呼び出す最小限のクライアントを提供する
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/mami0tsu/homeops/internal/awsjson"
+)
+
+// DynamoDB の属性値 (文字列と数値のみ扱う)
+type AttributeValue struct {
+	S string `json:"S,omitempty"`
+	N string `json:"N,omitempty"`
+}
+
+type Item map[string]AttributeValue
+
+func S(v string) AttributeValue {
+	return AttributeValue{S: v}
+}
+
+func N(v int64) AttributeValue {
+	return AttributeValue{N: strconv.FormatInt(v, 10)}
+}
+
+func (i Item) Str(name string) string {
+	return i[name].S
+}
+
+func (i Item) Num(name string) int64 {
+	n, _ := strconv.ParseInt(i[name].N, 10, 64)
+
+	return n
+}
+
+// DynamoDB が返すエラー
+type Error = awsjson.Error
+
+func IsConditionalCheckFailed(err error) bool {
+	var e *Error
+
+	return errors.As(err, &e) && e.Type == "ConditionalCheckFailedException"
+}
+
+// DynamoDB の API を直接呼び出すクライアント
+// 必要な操作が限られているため、SDK のクライアントではなく awsjson のクライアントで必要な操作のみを呼び出す
+type Client struct {
+	client *awsjson.Client
+}
+
+func NewClient(ctx context.Context, client *http.Client) (*Client, error) {
+	c, err := awsjson.New(ctx, "dynamodb", "DynamoDB_20120810", "application/x-amz-json-1.0")
+	if err != nil {
+		return nil, err
+	}
+	c.HTTPClient = client
+
+	return &Client{client: c}, nil
+}
+
+// condition が空でなければ条件付きで書き込む、values は condition で参照する値
+func (c *Client) PutItem(ctx context.Context, table string, item Item, condition string, values Item) error {
+	in := map[string]any{
+		"TableName": table,
+		"Item":      item,
+	}
+	if condition != "" {
+		in["ConditionExpression"] = condition
+	}
+	if len(values) > 0 {
+		in["ExpressionAttributeValues"] = values
+	}
+
+	return c.client.Do(ctx, "PutItem", in, nil)
+}
+
+// 項目が存在しない場合は nil を返す
+func (c *Client) GetItem(ctx context.Context, table string, key Item) (Item, error) {
+	var out struct {
+		Item Item `json:"Item"`
+	}
+	in := map[string]any{
+		"TableName":      table,
+		"Key":            key,
+		"ConsistentRead": true,
+	}
+	if err := c.client.Do(ctx, "GetItem", in, &out); err != nil {
+		return nil, err
+	}
+
+	return out.Item, nil
+}
+
+func (c *Client) UpdateItem(ctx context.Context, table string, key Item, update string, values Item) error {
+	return c.UpdateItemIf(ctx, table, key, update, "", values)
+}
+
+// condition が空でなければ条件付きで更新する、values は update と condition で参照する値
+func (c *Client) UpdateItemIf(ctx context.Context, table string, key Item, update, condition string, values Item) error {
+	in := map[string]any{
+		"TableName":                 table,
+		"Key":                       key,
+		"UpdateExpression":          update,
+		"ExpressionAttributeValues": values,
+	}
+	if condition != "" {
+		in["ConditionExpression"] = condition
+	}
+
+	return c.client.Do(ctx, "UpdateItem", in, nil)
+}
+
+func (c *Client) DeleteItem(ctx context.Context, table string, key Item) error {
+	in := map[string]any{
+		"TableName": table,
+		"Key":       key,
+	}
+
+	return c.client.Do(ctx, "DeleteItem", in, nil)
+}
+
+// ページングしながら条件に一致する全ての項目を返す
+func (c *Client) Query(ctx context.Context, table string, keyCondition string, values Item) ([]Item, error) {
+	var items []Item
+	var startKey Item
+	for {
+		in := map[string]any{
+			"TableName":                 table,
+			"KeyConditionExpression":    keyCondition,
+			"ExpressionAttributeValues": values,
+		}
+		if startKey != nil {
+			in["ExclusiveStartKey"] = startKey
+		}
+
+		var out struct {
+			Items            []Item `json:"Items"`
+			LastEvaluatedKey Item   `json:"LastEvaluatedKey"`
+		}
+		if err := c.client.Do(ctx, "Query", in, &out); err != nil {
+			return nil, err
+		}
+		items = append(items, out.Items...)
+
+		if len(out.LastEvaluatedKey) == 0 {
+			return items, nil
+		}
+		startKey = out.LastEvaluatedKey
+	}
+}
@@ -0,0 +1,159 @@
+// Package electricity はスマートメーターの積算電力量を日ごとに記録し、電気の使用量と料金の目安を集計する
+package electricity
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+)
+
+const partitionKey = "electricity"
+
+// 先月の同じ期間と比べられるように、記録を残す期間
+const retention = 70 * 24 * time.Hour
+
+const dateFormat = "20060102"
+
+// 日付が変わった後に記録した積算電力量
+type Reading struct {
+	Date time.Time
+	Wh   int64
+}
+
+// 記録した積算電力量を DynamoDB に保存する
+// テーブルのキーは pk (パーティションキー) と sk (ソートキー、日付) とし、ACK_TABLE_NAME と同じテーブルでもよい
+type Store struct {
+	client *dynamodb.Client
+	table  string
+}
+
+func NewStore(client *dynamodb.Client, table string) *Store {
+	return &Store{client: client, table: table}
+}
+
+// 日付ごとに最初に記録した値を残す、記録済みの場合は false を返す
+// 期限が過ぎた項目は TTL で削除する
+func (s *Store) Put(ctx context.Context, r Reading) (bool, error) {
+	item := dynamodb.Item{
+		"pk":         dynamodb.S(partitionKey),
+		"sk":         dynamodb.S(r.Date.Format(dateFormat)),
+		"wh":         dynamodb.N(r.Wh),
+		"expires_at": dynamodb.N(r.Date.Add(retention).Unix()),
+	}
+	if err := s.client.PutItem(ctx, s.table, item, "attribute_not_exists(pk)", nil); err != nil {
+		if dynamodb.IsConditionalCheckFailed(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// 日付の昇順で返す
+func (s *Store) Readings(ctx context.Context, loc *time.Location) ([]Reading, error) {
+	items, err := s.client.Query(ctx, s.table, "pk = :pk", dynamodb.Item{":pk": dynamodb.S(partitionKey)})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseReadings(items, loc), nil
+}
+
+func parseReadings(items []dynamodb.Item, loc *time.Location) []Reading {
+	readings := make([]Reading, 0, len(items))
+	for _, item := range items {
+		date, err := time.ParseInLocation(dateFormat, item.Str("sk"), loc)
+		if err != nil {
+			continue
+		}
+		readings = append(readings, Reading{Date: date, Wh: item.Num("wh")})
+	}
+	sort.Slice(readings, func(i, j int) bool { return readings[i].Date.Before(readings[j].Date) })
+
+	return readings
+}
+
+// 期間の使用量
+type Usage struct {
+	From, To time.Time // To は含む
+	Wh       int64
+}
+
+func (u Usage) KWh() float64 {
+	return float64(u.Wh) / 1000
+}
+
+// 1 kWh あたりの料金 (円) から料金の目安を返す
+func (u Usage) Cost(unitPrice float64) int {
+	return int(math.Round(u.KWh() * unitPrice))
+}
+
+// 前日の使用量と、前日を含む月の初めからの使用量、先月の同じ期間の使用量
+// 記録がなく集計できない期間は nil とする
+type Report struct {
+	Day       Usage
+	Month     *Usage
+	LastMonth *Usage
+}
+
+// today に記録した値までの使用量を集計する、前日の記録がない場合は false を返す
+// 月の初めの記録がない場合は、記録を始めた日からの使用量とする
+func NewReport(readings []Reading, today time.Time) (Report, bool) {
+	byDate := make(map[string]Reading, len(readings))
+	for _, r := range readings {
+		byDate[r.Date.Format(dateFormat)] = r
+	}
+	usage := func(from, to time.Time) (Usage, bool) {
+		start, ok := byDate[from.Format(dateFormat)]
+		if !ok {
+			return Usage{}, false
+		}
+		end, ok := byDate[to.Format(dateFormat)]
+		if !ok || end.Wh < start.Wh {
+			return Usage{}, false
+		}
+		return Usage{From: from, To: to.AddDate(0, 0, -1), Wh: end.Wh - start.Wh}, true
+	}
+
+	yesterday := today.AddDate(0, 0, -1)
+	day, ok := usage(yesterday, today)
+	if !ok {
+		return Report{}, false
+	}
+	report := Report{Day: day}
+
+	monthStart := time.Date(yesterday.Year(), yesterday.Month(), 1, 0, 0, 0, 0, yesterday.Location())
+	for from := monthStart; !from.After(yesterday); from = from.AddDate(0, 0, 1) {
+		if u, ok := usage(from, today); ok {
+			report.Month = &u
+			break
+		}
+	}
+	if report.Month == nil {
+		return report, true
+	}
+
+	// 先月の同じ日数、先月の方が短い場合は月末までとする
+	from := report.Month.From.AddDate(0, -1, 0)
+	if from.Month() == monthStart.Month() {
+		return report, true
+	}
+	to := from.AddDate(0, 0, days(report.Month.From, today))
+	if to.After(monthStart) {
+		to = monthStart
+	}
+	if u, ok := usage(from, to); ok {
+		report.LastMonth = &u
+	}
+
+	return report, true
+}
+
+// from から to までの日数
+func days(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
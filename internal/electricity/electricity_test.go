@@ -0,0 +1,90 @@
+package electricity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(month time.Month, day int) time.Time {
+	return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseReadings(t *testing.T) {
+	ta := assert.New(t)
+
+	readings := parseReadings([]dynamodb.Item{
+		{"pk": dynamodb.S("electricity"), "sk": dynamodb.S("20250302"), "wh": dynamodb.N(1008000)},
+		{"pk": dynamodb.S("electricity"), "sk": dynamodb.S("20250301"), "wh": dynamodb.N(1000000)},
+		{"pk": dynamodb.S("electricity"), "sk": dynamodb.S("invalid"), "wh": dynamodb.N(0)},
+	}, time.UTC)
+
+	ta.Equal([]Reading{{Date: date(3, 1), Wh: 1000000}, {Date: date(3, 2), Wh: 1008000}}, readings)
+}
+
+func TestNewReport(t *testing.T) {
+	// 2/1 から 1 日 10kWh、3/1 から 1 日 8kWh を使った記録
+	var readings []Reading
+	wh := int64(1000000)
+	for d := date(2, 1); !d.After(date(3, 5)); d = d.AddDate(0, 0, 1) {
+		readings = append(readings, Reading{Date: d, Wh: wh})
+		if d.Month() == time.February {
+			wh += 10000
+		} else {
+			wh += 8000
+		}
+	}
+
+	t.Run("正常系/前日と今月と先月の同じ期間を集計する", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		r, ok := NewReport(readings, date(3, 5))
+		tr.True(ok)
+		ta.Equal(Usage{From: date(3, 4), To: date(3, 4), Wh: 8000}, r.Day)
+		tr.NotNil(r.Month)
+		ta.Equal(Usage{From: date(3, 1), To: date(3, 4), Wh: 32000}, *r.Month)
+		tr.NotNil(r.LastMonth)
+		ta.Equal(Usage{From: date(2, 1), To: date(2, 4), Wh: 40000}, *r.LastMonth)
+	})
+
+	t.Run("正常系/月の初めは前月の合計を集計する", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		r, ok := NewReport(readings, date(3, 1))
+		tr.True(ok)
+		ta.Equal(Usage{From: date(2, 28), To: date(2, 28), Wh: 10000}, r.Day)
+		tr.NotNil(r.Month)
+		ta.Equal(Usage{From: date(2, 1), To: date(2, 28), Wh: 280000}, *r.Month)
+		// 1 月の記録はない
+		ta.Nil(r.LastMonth)
+	})
+
+	t.Run("正常系/月の途中から記録を始めた場合", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		r, ok := NewReport(readings[10:], date(2, 15))
+		tr.True(ok)
+		tr.NotNil(r.Month)
+		ta.Equal(Usage{From: date(2, 11), To: date(2, 14), Wh: 40000}, *r.Month)
+		ta.Nil(r.LastMonth)
+	})
+
+	t.Run("異常系/前日の記録がない場合", func(t *testing.T) {
+		_, ok := NewReport(readings, date(3, 7))
+		assert.False(t, ok)
+	})
+}
+
+func TestUsageCost(t *testing.T) {
+	ta := assert.New(t)
+
+	u := Usage{Wh: 8450}
+	ta.Equal(8.45, u.KWh())
+	ta.Equal(262, u.Cost(31))
+}
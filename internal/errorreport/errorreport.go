@@ -0,0 +1,192 @@
+// Package errorreport は各関数で共通のエラーの通知を提供する
+// Sentry の Envelope 形式で送信するため、Sentry 互換のサービス (Sentry, GlitchTip など) で受け取れる
+package errorreport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/httpclient"
+)
+
+const sendTimeout = 5 * time.Second
+
+// エラーの通知先、nil の場合は何も送信しない
+type Reporter struct {
+	client      *http.Client
+	dsn         string
+	endpoint    string
+	publicKey   string
+	environment string
+}
+
+// DSN は "https://<公開鍵>@<ホスト>/<プロジェクト ID>" の形式で指定する
+func New(client *http.Client, dsn, environment string) (*Reporter, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil || u.User.Username() == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid dsn: %s", dsn)
+	}
+	dir, project := path.Split(strings.TrimSuffix(u.Path, "/"))
+	if project == "" {
+		return nil, fmt.Errorf("invalid dsn: %s", dsn)
+	}
+
+	return &Reporter{
+		client:      client,
+		dsn:         dsn,
+		endpoint:    fmt.Sprintf("%s://%s%sapi/%s/envelope/", u.Scheme, u.Host, dir, project),
+		publicKey:   u.User.Username(),
+		environment: environment,
+	}, nil
+}
+
+// 環境変数 SENTRY_DSN が設定されていれば通知先を作成する、未設定もしくは不正な値の場合は nil を返す
+// SSM パラメータから展開される場合があるため、設定を読み込んだ後に呼び出す
+func FromEnv() *Reporter {
+	dsn := os.Getenv("SENTRY_DSN")
+	if dsn == "" {
+		return nil
+	}
+	r, err := New(httpclient.Default, dsn, os.Getenv("APP_ENV"))
+	if err != nil {
+		slog.Warn("failed to init error reporter", slog.Any("error", err))
+		return nil
+	}
+
+	return r
+}
+
+// エラーを通知する、tags には失敗した取得元の名前などを指定する
+func (r *Reporter) Capture(ctx context.Context, err error, tags map[string]string) {
+	if r == nil || err == nil {
+		return
+	}
+
+	r.send(ctx, event{
+		Level: "error",
+		Tags:  tags,
+		Exception: exceptions{Values: []exception{
+			{Type: fmt.Sprintf("%T", err), Value: err.Error()},
+		}},
+	})
+}
+
+// panic を通知する、呼び出し元で recover した値を渡す
+func (r *Reporter) CapturePanic(ctx context.Context, v any, tags map[string]string) {
+	if r == nil {
+		return
+	}
+
+	r.send(ctx, event{
+		Level: "fatal",
+		Tags:  tags,
+		Exception: exceptions{Values: []exception{
+			{Type: "panic", Value: fmt.Sprint(v)},
+		}},
+		Extra: map[string]string{"stack": string(debug.Stack())},
+	})
+}
+
+type event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   float64           `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Environment string            `json:"environment,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Exception   exceptions        `json:"exception"`
+	Extra       map[string]string `json:"extra,omitempty"`
+}
+
+type exceptions struct {
+	Values []exception `json:"values"`
+}
+
+type exception struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// 通知に失敗しても本来の処理には影響させず、ログに残すのみとする
+func (r *Reporter) send(ctx context.Context, e event) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+	defer cancel()
+
+	e.EventID = newEventID()
+	e.Timestamp = float64(time.Now().UnixMilli()) / 1000
+	e.Platform = "go"
+	e.Environment = r.environment
+	body, err := createEnvelope(r.dsn, e)
+	if err != nil {
+		slog.Error("failed to create error report", slog.Any("error", err))
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		slog.Error("failed to create error report", slog.Any("error", err))
+		return
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_key=%s, sentry_client=homeops/1.0", r.publicKey))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		slog.Error("failed to send error report", slog.Any("error", err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		slog.Error("failed to send error report", slog.Int("status", resp.StatusCode), slog.String("body", string(b)))
+		return
+	}
+	slog.Info("sent error report", slog.String("event_id", e.EventID))
+}
+
+// Envelope はヘッダー、アイテムのヘッダー、アイテムを改行区切りで並べる
+func createEnvelope(dsn string, e event) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	items := []any{
+		map[string]string{"event_id": e.EventID, "dsn": dsn},
+		map[string]string{"type": "event"},
+		e,
+	}
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// 呼び出し時のペイロードを特定するためのハッシュ、秘匿情報を含む場合があるため内容そのものは送らない
+func PayloadHash(payload []byte) string {
+	h := sha256.Sum256(payload)
+
+	return hex.EncodeToString(h[:8])
+}
@@ -0,0 +1,96 @@
+package errorreport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name             string
+		dsn              string
+		expectError      bool
+		expectedEndpoint string
+	}{
+		{
+			name:             "正常系/DSN を指定した場合",
+			dsn:              "https://abc123@o1.ingest.sentry.io/456",
+			expectedEndpoint: "https://o1.ingest.sentry.io/api/456/envelope/",
+		},
+		{
+			name:             "正常系/パスを含む DSN を指定した場合",
+			dsn:              "https://abc123@example.com/glitchtip/7",
+			expectedEndpoint: "https://example.com/glitchtip/api/7/envelope/",
+		},
+		{
+			name:        "異常系/公開鍵が含まれていない場合",
+			dsn:         "https://o1.ingest.sentry.io/456",
+			expectError: true,
+		},
+		{
+			name:        "異常系/プロジェクト ID が含まれていない場合",
+			dsn:         "https://abc123@o1.ingest.sentry.io/",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			r, err := New(http.DefaultClient, tt.dsn, "prd")
+
+			if tt.expectError {
+				ta.Error(err)
+			} else {
+				ta.NoError(err)
+				ta.Equal(tt.expectedEndpoint, r.endpoint)
+			}
+		})
+	}
+}
+
+func TestCapture(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	var lines []string
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("X-Sentry-Auth")
+		s := bufio.NewScanner(r.Body)
+		for s.Scan() {
+			lines = append(lines, s.Text())
+		}
+	}))
+	defer srv.Close()
+
+	dsn := strings.Replace(srv.URL, "http://", "http://abc123@", 1) + "/456"
+	r, err := New(srv.Client(), dsn, "prd")
+	tr.NoError(err)
+	r.Capture(context.Background(), errors.New("sheet: source unavailable"), map[string]string{"component": "sheet"})
+
+	ta.Contains(auth, "sentry_key=abc123")
+	tr.Len(lines, 3)
+	ta.JSONEq(`{"type": "event"}`, lines[1])
+
+	var e event
+	tr.NoError(json.Unmarshal([]byte(lines[2]), &e))
+	ta.Len(e.EventID, 32)
+	ta.Equal("error", e.Level)
+	ta.Equal("prd", e.Environment)
+	ta.Equal(map[string]string{"component": "sheet"}, e.Tags)
+	ta.Equal([]exception{{Type: "*errors.errorString", Value: "sheet: source unavailable"}}, e.Exception.Values)
+
+	// 通知先が設定されていなくても呼び出せる
+	var nop *Reporter
+	nop.Capture(context.Background(), errors.New("error"), nil)
+}
@@ -0,0 +1,108 @@
+package event
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// イベントの対応状況
+type AckStatus string
+
+const (
+	AckPending AckStatus = "pending" // 未対応
+	AckDone    AckStatus = "done"    // 完了
+	AckSnoozed AckStatus = "snoozed" // 翌日に再通知する
+)
+
+// 対応状況のキーやボタンの custom_id に含める日付の形式
+const AckDateFormat = "20060102"
+
+// 対応状況を保存するテーブルのパーティションキー、ボタンの custom_id の接頭辞にも使う
+const AckPartitionKey = "ack"
+
+// 名前からイベントを識別するキーを作成する
+// ボタンの custom_id は 100 文字までなので、ハッシュを短くして使う
+func Key(e Event) string {
+	h := sha1.Sum([]byte(e.Name))
+
+	return hex.EncodeToString(h[:6])
+}
+
+// 対応状況を更新するボタンの custom_id
+// 単発のイベントの完了ボタンにはスプレッドシートの行番号を付け、完了のチェックボックスも更新させる
+// e.g. "ack:done:20250114:0123456789ab", "ack:done:20250114:0123456789ab:12"
+func AckCustomID(status AckStatus, date time.Time, e Event) string {
+	action := "done"
+	if status == AckSnoozed {
+		action = "snooze"
+	}
+	id := fmt.Sprintf("%s:%s:%s:%s", AckPartitionKey, action, date.Format(AckDateFormat), Key(e))
+	if status == AckDone && e.Interval == Onetime && e.Row > 0 {
+		id += fmt.Sprintf(":%d", e.Row)
+	}
+
+	return id
+}
+
+// 対応状況を更新するボタンが押された場合の操作
+type AckAction struct {
+	Status AckStatus // AckDone または AckSnoozed
+	Date   time.Time
+	Key    string
+	Row    int // 単発のイベントの完了ボタンの場合はスプレッドシートの行番号、それ以外は 0
+}
+
+// AckCustomID で作成した custom_id を解析する、日付は loc の日付として扱う
+func ParseAckCustomID(id string, loc *time.Location) (AckAction, error) {
+	parts := strings.Split(id, ":")
+	if (len(parts) != 4 && len(parts) != 5) || parts[0] != AckPartitionKey {
+		return AckAction{}, fmt.Errorf("invalid custom id: %s", id)
+	}
+
+	var a AckAction
+	switch parts[1] {
+	case "done":
+		a.Status = AckDone
+	case "snooze":
+		a.Status = AckSnoozed
+	default:
+		return AckAction{}, fmt.Errorf("invalid custom id: %s", id)
+	}
+	date, err := time.ParseInLocation(AckDateFormat, parts[2], loc)
+	if err != nil {
+		return AckAction{}, fmt.Errorf("invalid custom id: %s", id)
+	}
+	a.Date, a.Key = date, parts[3]
+	if len(parts) == 5 {
+		row, err := strconv.Atoi(parts[4])
+		if err != nil || row < 2 || a.Status != AckDone {
+			return AckAction{}, fmt.Errorf("invalid custom id: %s", id)
+		}
+		a.Row = row
+	}
+
+	return a, nil
+}
+
+// 対応状況を保存するテーブルのソートキー、e.g. "20250114#0123456789ab"
+func AckSortKey(date time.Time, key string) string {
+	return date.Format(AckDateFormat) + "#" + key
+}
+
+// AckSortKey で作成したソートキーを解析する、日付は loc の日付として扱う
+func ParseAckSortKey(sk string, loc *time.Location) (time.Time, string, error) {
+	date, key, ok := strings.Cut(sk, "#")
+	if !ok {
+		return time.Time{}, "", fmt.Errorf("invalid ack sort key: %s", sk)
+	}
+	d, err := time.ParseInLocation(AckDateFormat, date, loc)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid ack date: %s", date)
+	}
+
+	return d, key, nil
+}
@@ -0,0 +1,78 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAckCustomID(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	date := time.Date(2025, 1, 14, 0, 0, 0, 0, tz)
+	weekly := Event{Name: "Garbage", Interval: Weekly}
+	onetime := Event{Name: "Dentist", Interval: Onetime, Row: 12}
+
+	tests := []struct {
+		name     string
+		id       string
+		expected AckAction
+		wantErr  bool
+	}{
+		{
+			name:     "正常系/完了ボタン",
+			id:       AckCustomID(AckDone, date, weekly),
+			expected: AckAction{Status: AckDone, Date: date, Key: Key(weekly)},
+		},
+		{
+			name:     "正常系/翌日に再通知するボタン",
+			id:       AckCustomID(AckSnoozed, date, weekly),
+			expected: AckAction{Status: AckSnoozed, Date: date, Key: Key(weekly)},
+		},
+		{
+			name:     "正常系/単発のイベントの完了ボタンは行番号を含む",
+			id:       AckCustomID(AckDone, date, onetime),
+			expected: AckAction{Status: AckDone, Date: date, Key: Key(onetime), Row: 12},
+		},
+		{name: "異常系/接頭辞が異なる場合", id: "meal:done:20250114:0123456789ab", wantErr: true},
+		{name: "異常系/不明な操作", id: "ack:skip:20250114:0123456789ab", wantErr: true},
+		{name: "異常系/日付の形式が異なる場合", id: "ack:done:2025-01-14:0123456789ab", wantErr: true},
+		{name: "異常系/行番号が見出しの行の場合", id: "ack:done:20250114:0123456789ab:1", wantErr: true},
+		{name: "異常系/再通知するボタンに行番号がある場合", id: "ack:snooze:20250114:0123456789ab:12", wantErr: true},
+		{name: "異常系/要素が足りない場合", id: "ack:done:20250114", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			a, err := ParseAckCustomID(tt.id, tz)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, a)
+		})
+	}
+}
+
+func TestParseAckSortKey(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	tz := time.FixedZone("JST", 9*60*60)
+	date := time.Date(2025, 1, 14, 0, 0, 0, 0, tz)
+	sk := AckSortKey(date, "0123456789ab")
+	ta.Equal("20250114#0123456789ab", sk)
+
+	d, key, err := ParseAckSortKey(sk, tz)
+	tr.NoError(err)
+	ta.True(date.Equal(d))
+	ta.Equal("0123456789ab", key)
+
+	_, _, err = ParseAckSortKey("20250114", tz)
+	ta.Error(err)
+	_, _, err = ParseAckSortKey("2025-01-14#0123456789ab", tz)
+	ta.Error(err)
+}
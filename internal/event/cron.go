@@ -0,0 +1,160 @@
+package event
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 標準的な cron 式 (分 時 日 月 曜日)
+// 日単位でリマインドするため、イベントの日付の判定には日・月・曜日のみを使う
+// 常駐して実行する場合の定期実行の判定には、分と時も含めて使う
+type CronSchedule struct {
+	Expr     string
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	dayStar  bool
+	wdayStar bool
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinutes  = cronField{min: 0, max: 59}
+	cronHours    = cronField{min: 0, max: 23}
+	cronDays     = cronField{min: 1, max: 31}
+	cronMonths   = cronField{min: 1, max: 12, names: map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}}
+	cronWeekdays = cronField{min: 0, max: 7, names: map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}}
+)
+
+func isCronExpr(s string) bool {
+	return len(strings.Fields(s)) == 5
+}
+
+func ParseCron(expr string) (*CronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression: %s", expr)
+	}
+
+	c := &CronSchedule{
+		Expr:     strings.Join(fields, " "),
+		dayStar:  fields[2] == "*",
+		wdayStar: fields[4] == "*",
+	}
+	var err error
+	if c.minutes, err = cronMinutes.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if c.hours, err = cronHours.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if c.days, err = cronDays.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if c.months, err = cronMonths.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if c.weekdays, err = cronWeekdays.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	// 日曜日は 0 と 7 のどちらでも指定できる
+	if c.weekdays&(1<<7) != 0 {
+		c.weekdays |= 1
+	}
+
+	return c, nil
+}
+
+// "1,15", "1-5", "*/2", "mon-fri" のような指定をビット列に変換する
+func (f cronField) parse(s string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(s, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			v, err := strconv.Atoi(stepStr)
+			if err != nil || v <= 0 {
+				return 0, fmt.Errorf("invalid cron step: %s", part)
+			}
+			step = v
+		}
+
+		var lo, hi int
+		switch {
+		case rng == "*":
+			lo, hi = f.min, f.max
+		case strings.Contains(rng, "-"):
+			l, h, _ := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(l); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(h); err != nil {
+				return 0, err
+			}
+		default:
+			v, err := f.value(rng)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = v, v
+			// "5/10" は 5 から最大値までを 10 刻みで指定したものとして扱う
+			if hasStep {
+				hi = f.max
+			}
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("invalid cron range: %s", part)
+		}
+
+		for i := lo; i <= hi; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid cron value: %s", s)
+	}
+
+	return v, nil
+}
+
+// 日と曜日の両方が指定されている場合は、どちらかに一致すれば対象とする (標準的な cron と同じ)
+func (c *CronSchedule) matchDate(t time.Time) bool {
+	if c.months&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	day := c.days&(1<<uint(t.Day())) != 0
+	wday := c.weekdays&(1<<uint(t.Weekday())) != 0
+	if c.dayStar || c.wdayStar {
+		return day && wday
+	}
+
+	return day || wday
+}
+
+// 分単位で一致するかを返す、常駐して実行する場合の定期実行に使う
+func (c *CronSchedule) Match(t time.Time) bool {
+	if c.minutes&(1<<uint(t.Minute())) == 0 || c.hours&(1<<uint(t.Hour())) == 0 {
+		return false
+	}
+
+	return c.matchDate(t)
+}
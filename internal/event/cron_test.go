@@ -0,0 +1,106 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronMatchDate(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name        string
+		expr        string
+		target      time.Time
+		expectError bool
+		expected    bool
+	}{
+		{
+			name:     "正常系/日のリストに含まれる場合",
+			expr:     "0 0 1,15 * *",
+			target:   time.Date(2025, 1, 15, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/日のリストに含まれない場合",
+			expr:     "0 0 1,15 * *",
+			target:   time.Date(2025, 1, 16, 0, 0, 0, 0, tz),
+			expected: false,
+		},
+		{
+			name:     "正常系/曜日の範囲に含まれる場合",
+			expr:     "0 9 * * mon-fri",
+			target:   time.Date(2025, 1, 17, 0, 0, 0, 0, tz), // 金曜日
+			expected: true,
+		},
+		{
+			name:     "正常系/曜日に 7 (日曜日) が指定されている場合",
+			expr:     "0 9 * * 7",
+			target:   time.Date(2025, 1, 19, 0, 0, 0, 0, tz), // 日曜日
+			expected: true,
+		},
+		{
+			name:     "正常系/日と曜日の両方が指定されている場合はどちらかに一致すればよい",
+			expr:     "0 0 1 * sat",
+			target:   time.Date(2025, 1, 18, 0, 0, 0, 0, tz), // 土曜日
+			expected: true,
+		},
+		{
+			name:     "正常系/月がステップで指定されている場合",
+			expr:     "0 0 1 */3 *",
+			target:   time.Date(2025, 4, 1, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:        "異常系/フィールド数が足りない場合",
+			expr:        "0 0 1 *",
+			expectError: true,
+		},
+		{
+			name:        "異常系/範囲外の値が指定されている場合",
+			expr:        "0 0 32 * *",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			tr := require.New(t)
+			c, err := ParseCron(tt.expr)
+
+			if tt.expectError {
+				tr.Error(err)
+				return
+			}
+			tr.NoError(err)
+			ta.Equal(tt.expected, c.matchDate(tt.target))
+		})
+	}
+}
+
+func TestCronMatch(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	c, err := ParseCron("*/15 8-18 * * mon-fri")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		target   time.Time
+		expected bool
+	}{
+		{name: "正常系/分と時と曜日が一致する場合", target: time.Date(2025, 3, 3, 8, 45, 0, 0, tz), expected: true},
+		{name: "正常系/分が一致しない場合", target: time.Date(2025, 3, 3, 8, 50, 0, 0, tz), expected: false},
+		{name: "正常系/時が一致しない場合", target: time.Date(2025, 3, 3, 19, 0, 0, 0, tz), expected: false},
+		{name: "正常系/曜日が一致しない場合", target: time.Date(2025, 3, 1, 8, 45, 0, 0, tz), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.New(t).Equal(tt.expected, c.Match(tt.target))
+		})
+	}
+}
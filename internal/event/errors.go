@@ -0,0 +1,64 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 処理のどこで失敗したかを表すエラーの種類
+var (
+	ErrSourceUnavailable = errors.New("source unavailable") // 取得元からイベント情報を取得できない
+	ErrParse             = errors.New("parse error")        // 取得したイベント情報を解釈できない
+	ErrNotify            = errors.New("notify failed")      // 投稿先への投稿に失敗した
+)
+
+// 失敗した取得元や投稿先の名前を付けたエラー
+// 種類は errors.Is で、名前は errors.As で取り出せる
+type PipelineError struct {
+	Kind      error  // ErrSourceUnavailable, ErrParse, ErrNotify のいずれか
+	Component string // e.g. "sheet", "discord"
+	Detail    string // e.g. "row 12"
+	Err       error
+}
+
+func (e *PipelineError) Error() string {
+	s := fmt.Sprintf("%s: %s", e.Component, e.Kind)
+	if e.Detail != "" {
+		s += fmt.Sprintf(" (%s)", e.Detail)
+	}
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+
+	return s
+}
+
+func (e *PipelineError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+
+	return []error{e.Kind, e.Err}
+}
+
+func NewSourceUnavailableError(component string, err error) error {
+	return &PipelineError{Kind: ErrSourceUnavailable, Component: component, Err: err}
+}
+
+func NewParseError(component, detail string, err error) error {
+	return &PipelineError{Kind: ErrParse, Component: component, Detail: detail, Err: err}
+}
+
+func NewNotifyError(component string, err error) error {
+	return &PipelineError{Kind: ErrNotify, Component: component, Err: err}
+}
+
+// エラーの原因となった取得元や投稿先の名前を返す、特定できない場合は空文字を返す
+func FailedComponent(err error) string {
+	var pe *PipelineError
+	if errors.As(err, &pe) {
+		return pe.Component
+	}
+
+	return ""
+}
@@ -0,0 +1,53 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineError(t *testing.T) {
+	cause := errors.New("connection refused")
+
+	tests := []struct {
+		name      string
+		err       error
+		kind      error
+		component string
+		expected  string
+	}{
+		{
+			name:      "正常系/取得元が利用できない場合",
+			err:       NewSourceUnavailableError("sheet", cause),
+			kind:      ErrSourceUnavailable,
+			component: "sheet",
+			expected:  "sheet: source unavailable: connection refused",
+		},
+		{
+			name:      "正常系/パースに失敗した場合",
+			err:       NewParseError("sheet", "row 12", cause),
+			kind:      ErrParse,
+			component: "sheet",
+			expected:  "sheet: parse error (row 12): connection refused",
+		},
+		{
+			name:      "正常系/ラップされた投稿先のエラーの場合",
+			err:       fmt.Errorf("failed to post: %w", NewNotifyError("discord", cause)),
+			kind:      ErrNotify,
+			component: "discord",
+			expected:  "failed to post: discord: notify failed: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			ta.ErrorIs(tt.err, tt.kind)
+			ta.ErrorIs(tt.err, cause)
+			ta.Equal(tt.component, FailedComponent(tt.err))
+			ta.EqualError(tt.err, tt.expected)
+		})
+	}
+}
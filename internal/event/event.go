@@ -0,0 +1,480 @@
+// Package event はイベントの定義と、通知日の判定を提供する
+package event
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type Interval int
+
+const (
+	Onetime Interval = iota
+	Weekly
+	Monthly
+	Yearly
+	Cron
+	Quarterly
+	Semiannual
+	Daily
+	Weekdays
+	Relative
+)
+
+func (i Interval) String() string {
+	switch i {
+	case Onetime:
+		return "Onetime"
+	case Weekly:
+		return "Weekly"
+	case Monthly:
+		return "Monthly"
+	case Yearly:
+		return "Yearly"
+	case Cron:
+		return "Cron"
+	case Quarterly:
+		return "Quarterly"
+	case Semiannual:
+		return "Semiannual"
+	case Daily:
+		return "Daily"
+	case Weekdays:
+		return "Weekdays"
+	case Relative:
+		return "Relative"
+	default:
+		return "Unknown"
+	}
+}
+
+func ParseInterval(s string) (Interval, error) {
+	switch strings.ToLower(s) {
+	case "onetime":
+		return Onetime, nil
+	case "weekly":
+		return Weekly, nil
+	case "monthly":
+		return Monthly, nil
+	case "yearly":
+		return Yearly, nil
+	case "cron":
+		return Cron, nil
+	case "quarterly":
+		return Quarterly, nil
+	case "semiannual", "half-yearly":
+		return Semiannual, nil
+	case "daily", "everyday":
+		return Daily, nil
+	case "weekdays":
+		return Weekdays, nil
+	case "relative":
+		return Relative, nil
+	default:
+		return -1, fmt.Errorf("invalid interval: %s", s)
+	}
+}
+
+// 間隔は "<interval>" もしくは "<interval>:<option>" の形式で指定する
+// cron 式や月初・月末からの相対的な指定はそのまま指定することもできる
+// e.g. "weekly", "weekly:mon,thu", "0 0 1,15 * *", "first business day of month"
+func SplitIntervalSpec(s string) (string, string) {
+	// "first business day of month" も 5 語になるため、cron 式より先に判定する
+	if isRelativeExpr(s) {
+		return "relative", s
+	}
+	if isCronExpr(s) {
+		return "cron", s
+	}
+	interval, option, _ := strings.Cut(s, ":")
+
+	return strings.TrimSpace(interval), strings.TrimSpace(option)
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+	"日":   time.Sunday,
+	"月":   time.Monday,
+	"火":   time.Tuesday,
+	"水":   time.Wednesday,
+	"木":   time.Thursday,
+	"金":   time.Friday,
+	"土":   time.Saturday,
+}
+
+// "mon,thu" のようなカンマ区切りの曜日をパースする
+func ParseWeekdays(s string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, v := range strings.Split(s, ",") {
+		v = strings.ToLower(strings.TrimSpace(v))
+		// "monday" のような曜日の完全な名前も受け付ける
+		if len(v) > 3 && v[0] < 0x80 {
+			v = v[:3]
+		}
+		w, ok := weekdayNames[v]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday: %s", v)
+		}
+		days = append(days, w)
+	}
+
+	return days, nil
+}
+
+// Monthly の場合の日付の決め方
+type MonthlyRule int
+
+const (
+	monthlyByDate    MonthlyRule = iota // 開始日と同じ日、その日が存在しない月はスキップする
+	monthlyClamp                        // 開始日と同じ日、その日が存在しない月は月末にする
+	monthlyLastDay                      // 月末
+	monthlyByWeekday                    // 開始日と同じ第 N 何曜日、開始日が第 5 週の場合は最終週にする
+)
+
+func parseMonthlyRule(s string) (MonthlyRule, error) {
+	switch strings.ToLower(s) {
+	case "date":
+		return monthlyByDate, nil
+	case "clamp":
+		return monthlyClamp, nil
+	case "last":
+		return monthlyLastDay, nil
+	case "weekday":
+		return monthlyByWeekday, nil
+	default:
+		return -1, fmt.Errorf("invalid monthly rule: %s", s)
+	}
+}
+
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+}
+
+// その月の第何週の曜日か (1 始まり)
+func weekOfMonth(t time.Time) int {
+	return (t.Day()-1)/7 + 1
+}
+
+// その月の最後の同じ曜日かどうか
+func isLastWeekdayOfMonth(t time.Time) bool {
+	return t.Day()+7 > daysInMonth(t)
+}
+
+// 発生日の調整方法
+type Modifier int
+
+const (
+	skipWeekend        Modifier = iota // 土日に当たる場合は除外する
+	shiftBeforeWeekend                 // 土日に当たる場合は直前の平日に移動する
+	shiftAfterWeekend                  // 土日に当たる場合は直後の平日に移動する
+	skipHoliday                        // 祝日に当たる場合は除外する
+	shiftBeforeHoliday                 // 祝日に当たる場合は直前の祝日でない日に移動する
+	shiftAfterHoliday                  // 祝日に当たる場合は直後の祝日でない日に移動する
+)
+
+func parseModifier(s string) (Modifier, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "skip-weekend", "weekday-only":
+		return skipWeekend, nil
+	case "shift-before-weekend":
+		return shiftBeforeWeekend, nil
+	case "shift-after-weekend":
+		return shiftAfterWeekend, nil
+	case "skip-holiday":
+		return skipHoliday, nil
+	case "shift-before-holiday":
+		return shiftBeforeHoliday, nil
+	case "shift-after-holiday":
+		return shiftAfterHoliday, nil
+	default:
+		return -1, fmt.Errorf("invalid modifier: %s", s)
+	}
+}
+
+// "skip-weekend,shift-after-holiday" のようなカンマ区切りの調整方法をパースする
+func ParseModifiers(s string) ([]Modifier, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var modifiers []Modifier
+	for _, v := range strings.Split(s, ",") {
+		m, err := parseModifier(v)
+		if err != nil {
+			return nil, err
+		}
+		modifiers = append(modifiers, m)
+	}
+
+	return modifiers, nil
+}
+
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+// 期間 (開始日と終了日を含む)
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (r DateRange) contains(t time.Time) bool {
+	return !t.Before(r.Start) && !t.After(r.End)
+}
+
+type Event struct {
+	Name        string
+	Interval    Interval       // e.g. Onetime, Weekly, Monthly, Yearly
+	Weekdays    []time.Weekday // Weekly の場合に対象とする曜日、未指定の場合は StartDate の曜日
+	Monthly     MonthlyRule    // Monthly の場合の日付の決め方
+	Cron        *CronSchedule  // Cron の場合の cron 式
+	Relative    *RelativeRule  // Relative の場合の月初・月末からの相対的な指定
+	StartDate   time.Time      // e.g. 2025/01/01
+	EndDate     time.Time      // e.g. 2025/12/31
+	Emoji       string         // e.g. 🗑️
+	Color       int            // e.g. 0x3fb950, 未指定の場合は 0
+	URL         string         // e.g. https://example.com
+	Description string
+	Modifiers   []Modifier // e.g. skip-weekend, shift-after-weekend
+
+	Count        int         // N 回発生したら終了する、0 の場合は EndDate まで繰り返す
+	Exceptions   []DateRange // 発生させない日付もしくは期間
+	Time         *TimeOfDay  // 時刻が指定されている場合は intraday モードでその時刻にも通知する
+	NotifyBefore int         // 発生日の N 日前にも通知する、0 の場合は発生日のみ
+	LeadDays     int         // 事前の通知の場合は発生日までの日数、発生日当日の場合は 0
+	Origin       time.Time   // 未対応のまま繰り越された場合は元の発生日
+	Done         bool        // 完了済みの場合は true、単発のイベントは通知しない
+	Row          int         // イベントを取得したスプレッドシートの行番号
+	Tags         []string    // e.g. morning, evening
+	DaysLeft     int         // 期限のあるイベントの場合は EndDate までの日数
+	Priority     int         // 数値が小さいほど先に表示する、0 の場合は未指定
+	Assignee     string      // 持ち回りで割り当てた家事の担当者、e.g. <@123456789>
+}
+
+type Schedule struct {
+	Date   time.Time
+	Events []Event
+}
+
+// 投稿先に渡すイベント情報
+type Digest struct {
+	Schedules []Schedule // 投稿対象の日付ごとのイベント
+	Upcoming  []Schedule // 今後 UpcomingDays 日分のイベント
+	Overdue   []Event    // 対応されないまま発生日を過ぎたイベント
+	Escalate  bool       // 長期間未対応のイベントがあればメンションする
+	Failures  []error    // 取得に失敗した取得元、投稿内容に注記する
+	CatchUp   bool       // 投稿できなかった日の分を後から投稿する場合は true、投稿内容に明記する
+}
+
+// 間隔のオプションを Event に反映する
+func (e *Event) ApplyIntervalOption(option string) error {
+	if option == "" {
+		switch e.Interval {
+		case Cron:
+			return fmt.Errorf("cron expression is required")
+		case Relative:
+			return fmt.Errorf("relative expression is required")
+		}
+		return nil
+	}
+
+	switch e.Interval {
+	case Weekly:
+		days, err := ParseWeekdays(option)
+		if err != nil {
+			return err
+		}
+		e.Weekdays = days
+	case Monthly, Quarterly, Semiannual:
+		rule, err := parseMonthlyRule(option)
+		if err != nil {
+			return err
+		}
+		e.Monthly = rule
+	case Cron:
+		c, err := ParseCron(option)
+		if err != nil {
+			return err
+		}
+		e.Cron = c
+	case Relative:
+		r, err := parseRelative(option)
+		if err != nil {
+			return err
+		}
+		e.Relative = r
+	default:
+		return fmt.Errorf("interval %s does not accept option: %s", e.Interval, option)
+	}
+
+	return nil
+}
+
+func (e *Event) isContain(t time.Time) bool {
+	// t < e.Start もしくは e.End < t なら除外する
+	if t.Before(e.StartDate) || t.After(e.EndDate) {
+		return false
+	}
+
+	return true
+}
+
+func (e *Event) isMatch(t time.Time) bool {
+	switch e.Interval {
+	case Onetime:
+		return t.Year() == e.StartDate.Year() && t.Month() == e.StartDate.Month() && t.Day() == e.StartDate.Day()
+	case Weekly:
+		if len(e.Weekdays) > 0 {
+			return slices.Contains(e.Weekdays, t.Weekday())
+		}
+		return t.Weekday() == e.StartDate.Weekday()
+	case Monthly:
+		return e.isMatchMonthly(t)
+	case Yearly:
+		return t.Month() == e.StartDate.Month() && t.Day() == e.StartDate.Day()
+	case Cron:
+		return e.Cron != nil && e.Cron.matchDate(t)
+	case Quarterly:
+		return monthsBetween(e.StartDate, t)%3 == 0 && e.isMatchMonthly(t)
+	case Semiannual:
+		return monthsBetween(e.StartDate, t)%6 == 0 && e.isMatchMonthly(t)
+	case Daily:
+		return true
+	case Weekdays:
+		// 祝日も除く場合は skip-holiday を指定する
+		return !isWeekend(t)
+	case Relative:
+		return e.Relative != nil && e.Relative.matchDate(t)
+	default:
+		return false
+	}
+}
+
+// 開始日の月から数えた月数
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+}
+
+func (e *Event) isMatchMonthly(t time.Time) bool {
+	switch e.Monthly {
+	case monthlyClamp:
+		return t.Day() == min(e.StartDate.Day(), daysInMonth(t))
+	case monthlyLastDay:
+		return t.Day() == daysInMonth(t)
+	case monthlyByWeekday:
+		if t.Weekday() != e.StartDate.Weekday() {
+			return false
+		}
+		if n := weekOfMonth(e.StartDate); n < 5 {
+			return weekOfMonth(t) == n
+		}
+		return isLastWeekdayOfMonth(t)
+	default:
+		return t.Day() == e.StartDate.Day()
+	}
+}
+
+// 期限が近いことを知らせるため、事前に通知するイベントのうち終了日が指定されているものは残り日数を表示する
+func (e *Event) HasDeadline(t time.Time) bool {
+	return e.NotifyBefore > 0 && e.EndDate.Year() < 9999 && !e.EndDate.Before(t)
+}
+
+// from から to までの日数
+func DaysBetween(from, to time.Time) int {
+	f := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	t := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+
+	return int(t.Sub(f).Hours() / 24)
+}
+
+// 本来の発生日
+func (e *Event) occursOn(t time.Time) bool {
+	if !e.isContain(t) || !e.isMatch(t) {
+		return false
+	}
+	if e.Count > 0 && e.countOccurrences(t) > e.Count {
+		return false
+	}
+	for _, r := range e.Exceptions {
+		if r.contains(t) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// 開始日から指定した日付までの発生回数を数える
+func (e *Event) countOccurrences(t time.Time) int {
+	var n int
+	for d := e.StartDate; !d.After(t); d = d.AddDate(0, 0, 1) {
+		if e.isMatch(d) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// 指定した調整方法のいずれかの対象となる日かどうか
+func (e *Event) isAdjusted(t time.Time, holidays Holidays, modifiers ...Modifier) bool {
+	for _, m := range modifiers {
+		if !slices.Contains(e.Modifiers, m) {
+			continue
+		}
+		switch m {
+		case skipWeekend, shiftBeforeWeekend, shiftAfterWeekend:
+			if isWeekend(t) {
+				return true
+			}
+		case skipHoliday, shiftBeforeHoliday, shiftAfterHoliday:
+			if holidays.IsHoliday(t) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// 調整方法を反映した上で、指定した日付が発生日かどうかを判定する
+func (e *Event) IsScheduled(t time.Time, holidays Holidays) bool {
+	isShiftedBefore := func(d time.Time) bool {
+		return e.isAdjusted(d, holidays, shiftBeforeWeekend, shiftBeforeHoliday)
+	}
+	isShiftedAfter := func(d time.Time) bool {
+		return e.isAdjusted(d, holidays, shiftAfterWeekend, shiftAfterHoliday)
+	}
+
+	// 除外もしくは移動の対象となる日は発生日にならない
+	if e.isAdjusted(t, holidays, skipWeekend, skipHoliday) || isShiftedBefore(t) || isShiftedAfter(t) {
+		return false
+	}
+	if e.occursOn(t) {
+		return true
+	}
+
+	// 直前の土日・祝日の発生日を移動してきたものかどうか
+	for d := t.AddDate(0, 0, -1); isShiftedAfter(d); d = d.AddDate(0, 0, -1) {
+		if e.occursOn(d) {
+			return true
+		}
+	}
+	// 直後の土日・祝日の発生日を移動してきたものかどうか
+	for d := t.AddDate(0, 0, 1); isShiftedBefore(d); d = d.AddDate(0, 0, 1) {
+		if e.occursOn(d) {
+			return true
+		}
+	}
+
+	return false
+}
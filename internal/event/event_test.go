@@ -0,0 +1,342 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsMatch(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	// 2025/01/01 は水曜日
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, tz)
+
+	tests := []struct {
+		name     string
+		event    Event
+		target   time.Time
+		expected bool
+	}{
+		{
+			name:     "正常系/Weekly で開始日と同じ曜日の場合",
+			event:    Event{Interval: Weekly, StartDate: start},
+			target:   time.Date(2025, 1, 8, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/Weekly で開始日と異なる曜日の場合",
+			event:    Event{Interval: Weekly, StartDate: start},
+			target:   time.Date(2025, 1, 9, 0, 0, 0, 0, tz),
+			expected: false,
+		},
+		{
+			name:     "正常系/Weekly で指定した曜日に含まれる場合",
+			event:    Event{Interval: Weekly, StartDate: start, Weekdays: []time.Weekday{time.Monday, time.Thursday}},
+			target:   time.Date(2025, 1, 9, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/Weekly で指定した曜日に含まれない場合",
+			event:    Event{Interval: Weekly, StartDate: start, Weekdays: []time.Weekday{time.Monday, time.Thursday}},
+			target:   time.Date(2025, 1, 8, 0, 0, 0, 0, tz),
+			expected: false,
+		},
+		{
+			name:     "正常系/Daily の場合",
+			event:    Event{Interval: Daily, StartDate: start},
+			target:   time.Date(2025, 1, 4, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/Weekdays で平日の場合",
+			event:    Event{Interval: Weekdays, StartDate: start},
+			target:   time.Date(2025, 1, 3, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/Weekdays で土曜日の場合",
+			event:    Event{Interval: Weekdays, StartDate: start},
+			target:   time.Date(2025, 1, 4, 0, 0, 0, 0, tz),
+			expected: false,
+		},
+		{
+			name:     "正常系/Quarterly で開始日から 3 か月後の場合",
+			event:    Event{Interval: Quarterly, StartDate: start},
+			target:   time.Date(2025, 4, 1, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/Quarterly で開始日から 2 か月後の場合",
+			event:    Event{Interval: Quarterly, StartDate: start},
+			target:   time.Date(2025, 3, 1, 0, 0, 0, 0, tz),
+			expected: false,
+		},
+		{
+			name:     "正常系/Semiannual で開始日が存在しない月の場合",
+			event:    Event{Interval: Semiannual, StartDate: time.Date(2025, 8, 31, 0, 0, 0, 0, tz)},
+			target:   time.Date(2026, 2, 28, 0, 0, 0, 0, tz),
+			expected: false,
+		},
+		{
+			name:     "正常系/Semiannual で開始日が存在しない月を clamp で調整する場合",
+			event:    Event{Interval: Semiannual, Monthly: monthlyClamp, StartDate: time.Date(2025, 8, 31, 0, 0, 0, 0, tz)},
+			target:   time.Date(2026, 2, 28, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/Monthly で開始日が存在しない月の場合",
+			event:    Event{Interval: Monthly, StartDate: time.Date(2025, 1, 31, 0, 0, 0, 0, tz)},
+			target:   time.Date(2025, 2, 28, 0, 0, 0, 0, tz),
+			expected: false,
+		},
+		{
+			name:     "正常系/Monthly で開始日が存在しない月を月末にする場合",
+			event:    Event{Interval: Monthly, Monthly: monthlyClamp, StartDate: time.Date(2025, 1, 31, 0, 0, 0, 0, tz)},
+			target:   time.Date(2025, 2, 28, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/Monthly で開始日が存在する月を月末にしない場合",
+			event:    Event{Interval: Monthly, Monthly: monthlyClamp, StartDate: time.Date(2025, 1, 30, 0, 0, 0, 0, tz)},
+			target:   time.Date(2025, 3, 31, 0, 0, 0, 0, tz),
+			expected: false,
+		},
+		{
+			name:     "正常系/Monthly で開始日と同じ第 N 曜日の場合",
+			event:    Event{Interval: Monthly, Monthly: monthlyByWeekday, StartDate: time.Date(2025, 1, 14, 0, 0, 0, 0, tz)}, // 第 2 火曜日
+			target:   time.Date(2025, 2, 11, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/Monthly で開始日と同じ日付だが第 N 曜日ではない場合",
+			event:    Event{Interval: Monthly, Monthly: monthlyByWeekday, StartDate: time.Date(2025, 1, 14, 0, 0, 0, 0, tz)},
+			target:   time.Date(2025, 2, 14, 0, 0, 0, 0, tz),
+			expected: false,
+		},
+		{
+			name:     "正常系/Monthly で開始日が第 5 週の場合は最終週にする",
+			event:    Event{Interval: Monthly, Monthly: monthlyByWeekday, StartDate: time.Date(2025, 1, 29, 0, 0, 0, 0, tz)}, // 第 5 水曜日
+			target:   time.Date(2025, 2, 26, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/Monthly で月末を指定した場合",
+			event:    Event{Interval: Monthly, Monthly: monthlyLastDay, StartDate: start},
+			target:   time.Date(2024, 2, 29, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			ta.Equal(tt.expected, tt.event.isMatch(tt.target))
+		})
+	}
+}
+
+func TestApplyIntervalOption(t *testing.T) {
+	tests := []struct {
+		name        string
+		event       Event
+		option      string
+		expectError bool
+		expected    Event
+	}{
+		{
+			name:     "正常系/オプションが指定されていない場合",
+			event:    Event{Interval: Weekly},
+			option:   "",
+			expected: Event{Interval: Weekly},
+		},
+		{
+			name:     "正常系/Weekly に曜日が指定されている場合",
+			event:    Event{Interval: Weekly},
+			option:   "mon, Thursday,土",
+			expected: Event{Interval: Weekly, Weekdays: []time.Weekday{time.Monday, time.Thursday, time.Saturday}},
+		},
+		{
+			name:     "正常系/Monthly に月末が指定されている場合",
+			event:    Event{Interval: Monthly},
+			option:   "last",
+			expected: Event{Interval: Monthly, Monthly: monthlyLastDay},
+		},
+		{
+			name:        "異常系/不正な曜日が指定されている場合",
+			event:       Event{Interval: Weekly},
+			option:      "mon,xyz",
+			expectError: true,
+		},
+		{
+			name:        "異常系/オプションを受け付けない間隔の場合",
+			event:       Event{Interval: Onetime},
+			option:      "mon",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			err := tt.event.ApplyIntervalOption(tt.option)
+
+			if tt.expectError {
+				ta.Error(err)
+			} else {
+				ta.NoError(err)
+				ta.Equal(tt.expected, tt.event)
+			}
+		})
+	}
+}
+
+func TestIsScheduled(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	// 2025/01/04 は土曜日
+	saturday := time.Date(2025, 1, 4, 0, 0, 0, 0, tz)
+	end := time.Date(2025, 12, 31, 0, 0, 0, 0, tz)
+	// 2025/01/13 (月) は成人の日
+	holidays := Holidays{"2025-01-13": "成人の日"}
+	monday := time.Date(2025, 1, 13, 0, 0, 0, 0, tz)
+
+	tests := []struct {
+		name     string
+		event    Event
+		target   time.Time
+		expected bool
+	}{
+		{
+			name:     "正常系/調整方法が指定されていない場合",
+			event:    Event{Interval: Monthly, StartDate: saturday, EndDate: end},
+			target:   saturday,
+			expected: true,
+		},
+		{
+			name:     "正常系/土日を除外する場合",
+			event:    Event{Interval: Monthly, StartDate: saturday, EndDate: end, Modifiers: []Modifier{skipWeekend}},
+			target:   saturday,
+			expected: false,
+		},
+		{
+			name:     "正常系/直後の平日に移動する場合",
+			event:    Event{Interval: Monthly, StartDate: saturday, EndDate: end, Modifiers: []Modifier{shiftAfterWeekend}},
+			target:   time.Date(2025, 1, 6, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/直前の平日に移動する場合",
+			event:    Event{Interval: Monthly, StartDate: saturday, EndDate: end, Modifiers: []Modifier{shiftBeforeWeekend}},
+			target:   time.Date(2025, 1, 3, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/移動した結果の日付ではない場合",
+			event:    Event{Interval: Monthly, StartDate: saturday, EndDate: end, Modifiers: []Modifier{shiftAfterWeekend}},
+			target:   time.Date(2025, 1, 7, 0, 0, 0, 0, tz),
+			expected: false,
+		},
+		{
+			name:     "正常系/平日の発生日は移動しない場合",
+			event:    Event{Interval: Monthly, StartDate: time.Date(2025, 1, 6, 0, 0, 0, 0, tz), EndDate: end, Modifiers: []Modifier{shiftAfterWeekend}},
+			target:   time.Date(2025, 2, 6, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/回数の上限に達するまでの場合",
+			event:    Event{Interval: Weekly, StartDate: monday, EndDate: end, Count: 3},
+			target:   time.Date(2025, 1, 27, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/回数の上限を超えた場合",
+			event:    Event{Interval: Weekly, StartDate: monday, EndDate: end, Count: 3},
+			target:   time.Date(2025, 2, 3, 0, 0, 0, 0, tz),
+			expected: false,
+		},
+		{
+			name:     "正常系/祝日を除外する場合",
+			event:    Event{Interval: Weekly, StartDate: monday, EndDate: end, Modifiers: []Modifier{skipHoliday}},
+			target:   monday,
+			expected: false,
+		},
+		{
+			name:     "正常系/祝日の直後の日に移動する場合",
+			event:    Event{Interval: Weekly, StartDate: monday, EndDate: end, Modifiers: []Modifier{shiftAfterHoliday}},
+			target:   time.Date(2025, 1, 14, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/土日と祝日をまたいで直前の平日に移動する場合",
+			event:    Event{Interval: Weekly, StartDate: monday, EndDate: end, Modifiers: []Modifier{shiftBeforeWeekend, shiftBeforeHoliday}},
+			target:   time.Date(2025, 1, 10, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			ta.Equal(tt.expected, tt.event.IsScheduled(tt.target, holidays))
+		})
+	}
+}
+
+func TestHasDeadline(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	target := time.Date(2025, 3, 1, 0, 0, 0, 0, tz)
+
+	tests := []struct {
+		name     string
+		event    Event
+		expected bool
+	}{
+		{
+			name:     "正常系/事前通知と終了日が指定されている場合",
+			event:    Event{NotifyBefore: 7, EndDate: time.Date(2025, 3, 10, 0, 0, 0, 0, tz)},
+			expected: true,
+		},
+		{
+			name:     "正常系/終了日が指定されていない場合",
+			event:    Event{NotifyBefore: 7, EndDate: time.Date(9999, 12, 31, 0, 0, 0, 0, tz)},
+			expected: false,
+		},
+		{
+			name:     "正常系/事前通知が指定されていない場合",
+			event:    Event{EndDate: time.Date(2025, 3, 10, 0, 0, 0, 0, tz)},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			ta.Equal(tt.expected, tt.event.HasDeadline(target))
+		})
+	}
+	assert.Equal(t, 9, DaysBetween(target, time.Date(2025, 3, 10, 0, 0, 0, 0, tz)))
+}
+
+// シートに入力された間隔は不正な値を含みうるため、どのような入力でも panic しないことを確認する
+func FuzzIntervalSpec(f *testing.F) {
+	for _, s := range []string{"weekly", "weekly:mon,thu", "monthly:last-weekday", "0 0 1,15 * *", "first business day of month", "daily:2", "yearly:", "cron:*/0 * * * *"} {
+		f.Add(s)
+	}
+	tz := time.FixedZone("JST", 9*60*60)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, tz)
+
+	f.Fuzz(func(t *testing.T, spec string) {
+		name, option := SplitIntervalSpec(spec)
+		interval, err := ParseInterval(name)
+		if err != nil {
+			return
+		}
+		e := Event{Interval: interval, StartDate: start, EndDate: start.AddDate(1, 0, 0)}
+		if err := e.ApplyIntervalOption(option); err != nil {
+			return
+		}
+		for d := 0; d < 62; d++ {
+			e.IsScheduled(start.AddDate(0, 0, d), nil)
+		}
+	})
+}
@@ -0,0 +1,12 @@
+package event
+
+import "time"
+
+// 祝日の一覧 (key: 2006-01-02, value: 祝日名)
+type Holidays map[string]string
+
+func (h Holidays) IsHoliday(t time.Time) bool {
+	_, ok := h[t.Format("2006-01-02")]
+
+	return ok
+}
@@ -0,0 +1,67 @@
+package event
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 時刻 (e.g. 20:00)
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+}
+
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", t.Hour, t.Minute)
+}
+
+// 指定した日付におけるその時刻を返す
+func (t TimeOfDay) On(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour, t.Minute, 0, 0, date.Location())
+}
+
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	h, m, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return TimeOfDay{}, fmt.Errorf("invalid time: %s", s)
+	}
+	hour, err := strconv.Atoi(h)
+	if err != nil || hour < 0 || hour > 23 {
+		return TimeOfDay{}, fmt.Errorf("invalid time: %s", s)
+	}
+	minute, err := strconv.Atoi(m)
+	if err != nil || minute < 0 || minute > 59 {
+		return TimeOfDay{}, fmt.Errorf("invalid time: %s", s)
+	}
+
+	return TimeOfDay{Hour: hour, Minute: minute}, nil
+}
+
+// 現在時刻を含む枠 [start, start+window) に時刻が含まれるイベントのみを抽出する
+// 呼び出し間隔と window を揃えることで、各イベントを 1 回ずつ投稿する
+func CreateIntradayDigest(schedules []Schedule, now time.Time, window time.Duration) Digest {
+	start := now.Truncate(window)
+	end := start.Add(window)
+
+	var d Digest
+	for _, s := range schedules {
+		var events []Event
+		for _, e := range s.Events {
+			// 事前の通知は朝の通知のみで行う
+			if e.Time == nil || e.LeadDays > 0 {
+				continue
+			}
+			at := e.Time.On(s.Date)
+			if !at.Before(start) && at.Before(end) {
+				events = append(events, e)
+			}
+		}
+		if len(events) > 0 {
+			d.Schedules = append(d.Schedules, Schedule{Date: s.Date, Events: events})
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,60 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateIntradayDigest(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	today := time.Date(2025, 1, 15, 0, 0, 0, 0, tz)
+	schedules := []Schedule{
+		{
+			Date: today,
+			Events: []Event{
+				{Name: "Medicine", Time: &TimeOfDay{Hour: 20, Minute: 0}},
+				{Name: "Lunch", Time: &TimeOfDay{Hour: 12, Minute: 10}},
+				{Name: "Garbage"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		expectedNames []string
+	}{
+		{
+			name:          "正常系/枠の開始時刻にイベントがある場合",
+			now:           time.Date(2025, 1, 15, 20, 5, 0, 0, tz),
+			expectedNames: []string{"Medicine"},
+		},
+		{
+			name:          "正常系/枠の途中にイベントがある場合",
+			now:           time.Date(2025, 1, 15, 12, 0, 0, 0, tz),
+			expectedNames: []string{"Lunch"},
+		},
+		{
+			name:          "正常系/枠内にイベントがない場合",
+			now:           time.Date(2025, 1, 15, 12, 15, 0, 0, tz),
+			expectedNames: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			d := CreateIntradayDigest(schedules, tt.now, 15*time.Minute)
+
+			var names []string
+			for _, s := range d.Schedules {
+				for _, e := range s.Events {
+					names = append(names, e.Name)
+				}
+			}
+			ta.Equal(tt.expectedNames, names)
+		})
+	}
+}
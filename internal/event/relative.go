@@ -0,0 +1,121 @@
+package event
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 月初もしくは月末を基準にした相対的な日付の指定
+// e.g. "first business day of month", "3rd business day of month", "last business day of month",
+// "3 days before end of month", "2 business days after start of month"
+type RelativeRule struct {
+	Expr     string
+	fromEnd  bool // 月末を基準にする
+	offset   int  // 基準日からの日数、負の値は基準日より前を表す
+	business bool // 土日・祝日を除いて数える
+
+	Holidays Holidays // 営業日を数える際に除く祝日
+}
+
+var (
+	relativeNthPattern    = regexp.MustCompile(`^(first|last|\d+)(?:st|nd|rd|th)? business day of (?:the )?month$`)
+	relativeOffsetPattern = regexp.MustCompile(`^(\d+) (business )?days? (before|after) (end|start) of (?:the )?month$`)
+)
+
+func normalizeRelativeExpr(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+func isRelativeExpr(s string) bool {
+	s = normalizeRelativeExpr(s)
+
+	return relativeNthPattern.MatchString(s) || relativeOffsetPattern.MatchString(s)
+}
+
+func parseRelative(expr string) (*RelativeRule, error) {
+	s := normalizeRelativeExpr(expr)
+	r := &RelativeRule{Expr: s}
+
+	if m := relativeNthPattern.FindStringSubmatch(s); m != nil {
+		r.business = true
+		switch m[1] {
+		case "first":
+		case "last":
+			r.fromEnd = true
+		default:
+			n, err := strconv.Atoi(m[1])
+			if err != nil || n < 1 || n > 23 {
+				return nil, fmt.Errorf("invalid relative expression: %s", expr)
+			}
+			r.offset = n - 1
+		}
+		return r, nil
+	}
+
+	if m := relativeOffsetPattern.FindStringSubmatch(s); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n > 27 {
+			return nil, fmt.Errorf("invalid relative expression: %s", expr)
+		}
+		r.business = m[2] != ""
+		r.offset = n
+		if m[3] == "before" {
+			r.offset = -n
+		}
+		r.fromEnd = m[4] == "end"
+		return r, nil
+	}
+
+	return nil, fmt.Errorf("invalid relative expression: %s", expr)
+}
+
+func (r *RelativeRule) isBusinessDay(t time.Time) bool {
+	return !isWeekend(t) && !r.Holidays.IsHoliday(t)
+}
+
+// 指定した月における発生日を返す
+func (r *RelativeRule) date(year int, month time.Month, loc *time.Location) time.Time {
+	d := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	inward := 1
+	if r.fromEnd {
+		d = d.AddDate(0, 1, -1)
+		inward = -1
+	}
+	if !r.business {
+		return d.AddDate(0, 0, r.offset)
+	}
+
+	// 基準日から月の内側に向かって最初の営業日を探し、そこから営業日を数える
+	for !r.isBusinessDay(d) {
+		d = d.AddDate(0, 0, inward)
+	}
+	step := 1
+	if r.offset < 0 {
+		step = -1
+	}
+	for n := r.offset * step; n > 0; {
+		d = d.AddDate(0, 0, step)
+		if r.isBusinessDay(d) {
+			n--
+		}
+	}
+
+	return d
+}
+
+// 基準日の前後で月をまたぐ場合があるため、前後の月の発生日も確認する
+func (r *RelativeRule) matchDate(t time.Time) bool {
+	first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	for _, m := range []int{-1, 0, 1} {
+		month := first.AddDate(0, m, 0)
+		d := r.date(month.Year(), month.Month(), t.Location())
+		if d.Year() == t.Year() && d.Month() == t.Month() && d.Day() == t.Day() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,85 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelativeMatchDate(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	// 2025/03/20 (木) は春分の日
+	holidays := Holidays{"2025-03-20": "春分の日"}
+
+	tests := []struct {
+		name     string
+		expr     string
+		target   time.Time
+		expected bool
+	}{
+		{
+			name:     "正常系/月初の営業日が土日をまたぐ場合",
+			expr:     "first business day of month",
+			target:   time.Date(2025, 3, 3, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/月初の営業日ではない場合",
+			expr:     "first business day of month",
+			target:   time.Date(2025, 3, 1, 0, 0, 0, 0, tz),
+			expected: false,
+		},
+		{
+			name:     "正常系/月末の営業日が土日をまたぐ場合",
+			expr:     "last business day of month",
+			target:   time.Date(2025, 5, 30, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/N 番目の営業日の場合",
+			expr:     "3rd business day of month",
+			target:   time.Date(2025, 3, 5, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/月末の N 日前の場合",
+			expr:     "3 days before end of month",
+			target:   time.Date(2025, 2, 25, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/月末の N 営業日前が祝日をまたぐ場合",
+			expr:     "8 business days before end of month",
+			target:   time.Date(2025, 3, 18, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+		{
+			name:     "正常系/月初の N 日前が前月になる場合",
+			expr:     "2 days before start of month",
+			target:   time.Date(2025, 2, 27, 0, 0, 0, 0, tz),
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			r, err := parseRelative(tt.expr)
+			ta.NoError(err)
+			r.Holidays = holidays
+			ta.Equal(tt.expected, r.matchDate(tt.target))
+		})
+	}
+}
+
+func TestSplitIntervalSpecRelative(t *testing.T) {
+	ta := assert.New(t)
+
+	interval, option := SplitIntervalSpec("First Business Day of Month")
+	ta.Equal("relative", interval)
+	ta.Equal("First Business Day of Month", option)
+
+	interval, _ = SplitIntervalSpec("0 0 1,15 * *")
+	ta.Equal("cron", interval)
+}
@@ -0,0 +1,88 @@
+// Package expiry はドメインの登録と TLS 証明書の有効期限を確認し、期限が近いものを remind のイベントとして返す
+package expiry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// 取得元の名前、イベントに付けるタグにも使う
+const Tag = "expiry"
+
+type Kind int
+
+const (
+	KindDomain      Kind = iota // ドメインの登録
+	KindCertificate             // TLS 証明書
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindDomain:
+		return "ドメイン"
+	case KindCertificate:
+		return "TLS 証明書"
+	default:
+		return "Unknown"
+	}
+}
+
+// 確認した有効期限
+type Result struct {
+	Target string // e.g. example.com, example.com:8443
+	Kind   Kind
+	Expiry time.Time
+}
+
+// domains のドメインの登録 (RDAP) と hosts の TLS 証明書の有効期限を確認する
+// 一部の確認に失敗した場合も、確認できたものは返す
+func Check(ctx context.Context, client *http.Client, rdapURL string, domains, hosts []string) ([]Result, error) {
+	var results []Result
+	var errs []error
+	for _, d := range domains {
+		t, err := DomainExpiry(ctx, client, rdapURL, d)
+		if err != nil {
+			slog.Warn("failed to check domain expiry", slog.String("domain", d), slog.Any("error", err))
+			errs = append(errs, fmt.Errorf("%s: %w", d, err))
+			continue
+		}
+		results = append(results, Result{Target: d, Kind: KindDomain, Expiry: t})
+	}
+	for _, h := range hosts {
+		t, err := CertificateExpiry(ctx, h)
+		if err != nil {
+			slog.Warn("failed to check certificate expiry", slog.String("host", h), slog.Any("error", err))
+			errs = append(errs, fmt.Errorf("%s: %w", h, err))
+			continue
+		}
+		results = append(results, Result{Target: h, Kind: KindCertificate, Expiry: t})
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// 残り日数がいずれかの閾値と一致する日と、最も小さい閾値を下回ってからは毎日通知する
+// e.g. 30,14,7 の場合は 30 日前、14 日前と、7 日前から期限を過ぎるまで毎日
+func Due(days int, thresholds []int) bool {
+	if len(thresholds) == 0 {
+		return false
+	}
+
+	return slices.Contains(thresholds, days) || days <= slices.Min(thresholds)
+}
+
+// 閾値が正の整数であることを確認する
+func ValidateThresholds(thresholds []int) error {
+	for _, v := range thresholds {
+		if v <= 0 {
+			return fmt.Errorf("invalid threshold: %d", v)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package expiry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDue(t *testing.T) {
+	thresholds := []int{30, 14, 7}
+
+	tests := []struct {
+		name     string
+		days     int
+		expected bool
+	}{
+		{name: "正常系/閾値と一致する場合", days: 14, expected: true},
+		{name: "正常系/最も小さい閾値を下回った場合", days: 3, expected: true},
+		{name: "正常系/期限を過ぎた場合", days: -1, expected: true},
+		{name: "正常系/閾値の間の場合", days: 20},
+		{name: "正常系/最も大きい閾値より前の場合", days: 31},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			ta.Equal(tt.expected, Due(tt.days, thresholds))
+		})
+	}
+}
+
+func TestValidateThresholds(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.NoError(ValidateThresholds([]int{30, 14, 7}))
+	ta.Error(ValidateThresholds([]int{30, 0}))
+}
+
+func TestSource(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	tz := time.FixedZone("JST", 9*60*60)
+	today := time.Date(2025, 3, 1, 0, 0, 0, 0, tz)
+	results := []Result{
+		{Target: "example.com", Kind: KindDomain, Expiry: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{Target: "www.example.com", Kind: KindCertificate, Expiry: time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC)},
+		{Target: "old.example.com", Kind: KindCertificate, Expiry: time.Date(2025, 2, 27, 0, 0, 0, 0, time.UTC)},
+		{Target: "example.net", Kind: KindDomain, Expiry: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	events, err := NewSource(results, []int{30, 14, 7}).Fetch(context.Background(), today)
+	tr.NoError(err)
+	tr.Len(events, 3)
+	ta.Equal("example.com のドメインの期限まで 30 日", events[0].Name)
+	ta.Equal(0, events[0].Priority)
+	ta.Equal("www.example.com のTLS 証明書の期限まで 4 日", events[1].Name)
+	ta.Equal("期限: 2025-03-05 21:00", events[1].Description)
+	ta.Equal(1, events[1].Priority)
+	ta.Equal("old.example.com のTLS 証明書の期限が切れています", events[2].Name)
+	ta.Equal([]string{Tag}, events[2].Tags)
+}
+
+func TestDomainExpiry(t *testing.T) {
+	t.Run("正常系/有効期限を返す", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ta.Equal("/domain/example.com", r.URL.Path)
+			w.Write([]byte(`{"events":[{"eventAction":"registration","eventDate":"1995-08-14T04:00:00Z"},{"eventAction":"expiration","eventDate":"2025-08-13T04:00:00Z"}]}`))
+		}))
+		defer srv.Close()
+
+		got, err := DomainExpiry(context.Background(), srv.Client(), srv.URL+"/", "example.com")
+		tr.NoError(err)
+		ta.Equal(time.Date(2025, 8, 13, 4, 0, 0, 0, time.UTC), got.UTC())
+	})
+
+	t.Run("異常系/有効期限が含まれない場合", func(t *testing.T) {
+		ta := assert.New(t)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"events":[]}`))
+		}))
+		defer srv.Close()
+
+		_, err := DomainExpiry(context.Background(), srv.Client(), srv.URL, "example.com")
+		ta.Error(err)
+	})
+
+	t.Run("異常系/見つからない場合", func(t *testing.T) {
+		ta := assert.New(t)
+
+		srv := httptest.NewServer(http.NotFoundHandler())
+		defer srv.Close()
+
+		_, err := DomainExpiry(context.Background(), srv.Client(), srv.URL, "example.invalid")
+		ta.Error(err)
+	})
+}
+
+func TestCertificateExpiry(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	got, err := CertificateExpiry(context.Background(), strings.TrimPrefix(srv.URL, "https://"))
+	tr.NoError(err)
+	ta.Equal(srv.Certificate().NotAfter, got)
+}
+
+func TestCheck(t *testing.T) {
+	ta := assert.New(t)
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	tlsSrv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsSrv.Close()
+
+	// 一部の確認に失敗しても、確認できたものは返す
+	results, err := Check(context.Background(), srv.Client(), srv.URL, []string{"example.com"}, []string{strings.TrimPrefix(tlsSrv.URL, "https://")})
+	ta.ErrorContains(err, "example.com")
+	ta.Len(results, 1)
+	ta.Equal(KindCertificate, results[0].Kind)
+}
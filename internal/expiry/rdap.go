@@ -0,0 +1,55 @@
+package expiry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/logging"
+)
+
+// RDAP のドメインのレスポンスのうち使用する部分
+type rdapDomainResponse struct {
+	Events []struct {
+		Action string    `json:"eventAction"`
+		Date   time.Time `json:"eventDate"`
+	} `json:"events"`
+}
+
+// WHOIS の代わりに、レジストリの RDAP からドメインの登録の有効期限を取得する
+// baseURL は e.g. https://rdap.org で、TLD ごとのレジストリにリダイレクトされる
+func DomainExpiry(ctx context.Context, client *http.Client, baseURL, domain string) (time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/domain/%s", strings.TrimSuffix(baseURL, "/"), url.PathEscape(domain)), nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}, fmt.Errorf("returned status %d", resp.StatusCode)
+	}
+
+	var r rdapDomainResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return time.Time{}, err
+	}
+	logging.DebugPayload(ctx, "fetched rdap domain", r)
+
+	for _, e := range r.Events {
+		if e.Action == "expiration" {
+			return e.Date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("expiration event not found")
+}
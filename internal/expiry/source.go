@@ -0,0 +1,49 @@
+package expiry
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// 確認した有効期限のうち、期限が近いものを t のイベントとして返す取得元
+type Source struct {
+	results    []Result
+	thresholds []int
+}
+
+func NewSource(results []Result, thresholds []int) *Source {
+	return &Source{results: results, thresholds: thresholds}
+}
+
+func (s *Source) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	var events []event.Event
+	for _, r := range s.results {
+		days := event.DaysBetween(t, r.Expiry.In(t.Location()))
+		if !Due(days, s.thresholds) {
+			continue
+		}
+
+		name := fmt.Sprintf("%s の%sの期限まで %d 日", r.Target, r.Kind, days)
+		if days < 0 {
+			name = fmt.Sprintf("%s の%sの期限が切れています", r.Target, r.Kind)
+		}
+		e := event.Event{
+			Name:        name,
+			Interval:    event.Onetime,
+			Emoji:       "🔐",
+			Description: fmt.Sprintf("期限: %s", r.Expiry.In(t.Location()).Format("2006-01-02 15:04")),
+			Tags:        []string{Tag},
+		}
+		// 最も小さい閾値を下回ったものは先に表示する
+		if len(s.thresholds) > 0 && days <= slices.Min(s.thresholds) {
+			e.Priority = 1
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
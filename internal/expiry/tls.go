@@ -0,0 +1,39 @@
+package expiry
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+)
+
+const dialTimeout = 10 * time.Second
+
+// host に TLS で接続し、サーバー証明書の有効期限を返す
+// host にポートが含まれない場合は 443 に接続する
+func CertificateExpiry(ctx context.Context, host string) (time.Time, error) {
+	addr := host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		addr = net.JoinHostPort(host, "443")
+	}
+	name, _, _ := net.SplitHostPort(addr)
+
+	// 期限切れの証明書も有効期限を読み取れるように、証明書の検証は行わない
+	d := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: dialTimeout},
+		Config:    &tls.Config{ServerName: name, InsecureSkipVerify: true},
+	}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return time.Time{}, fmt.Errorf("no peer certificate")
+	}
+
+	return certs[0].NotAfter, nil
+}
@@ -0,0 +1,94 @@
+// Package flags は環境ごとに切り替える機能フラグを提供する
+// フラグは SSM パラメータ /<APP_ENV>/<app>/flags/<name> に true/false で保存し、
+// config.Load に "flags" グループを渡して FLAGS_<NAME> の環境変数に展開しておく
+// e.g. /prd/remind/flags/new_sink -> FLAGS_NEW_SINK
+package flags
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const envPrefix = "FLAGS_"
+
+// 有効なフラグの一覧、nil の場合は全て無効として扱う
+type Set struct {
+	enabled map[string]bool
+}
+
+func New(names ...string) *Set {
+	s := &Set{enabled: make(map[string]bool)}
+	for _, n := range names {
+		s.enabled[strings.ToLower(n)] = true
+	}
+
+	return s
+}
+
+// FLAGS_ を接頭辞とした環境変数からフラグを読み込む
+// 真偽値として解釈できない値は、誤って有効にしないように無効として扱う
+func FromEnv() *Set {
+	return parse(os.Environ())
+}
+
+func parse(environ []string) *Set {
+	s := New()
+	for _, kv := range environ {
+		k, v, _ := strings.Cut(kv, "=")
+		name, ok := strings.CutPrefix(k, envPrefix)
+		if !ok || name == "" {
+			continue
+		}
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			slog.Warn("invalid flag value, treating as disabled", slog.String("flag", k), slog.String("value", v))
+			continue
+		}
+		if enabled {
+			s.enabled[strings.ToLower(name)] = true
+		}
+	}
+
+	return s
+}
+
+// name は小文字のスネークケースで指定する、e.g. "new_sink"
+func (s *Set) Enabled(name string) bool {
+	if s == nil {
+		return false
+	}
+
+	return s.enabled[name]
+}
+
+// 有効なフラグの名前を昇順で返す
+func (s *Set) Names() []string {
+	if s == nil {
+		return nil
+	}
+
+	return slices.Sorted(maps.Keys(s.enabled))
+}
+
+type contextKey struct{}
+
+func WithSet(ctx context.Context, s *Set) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+// ctx にフラグが設定されていなければ nil を返す
+func FromContext(ctx context.Context) *Set {
+	s, _ := ctx.Value(contextKey{}).(*Set)
+
+	return s
+}
+
+// 指定したフラグが ctx で有効かどうか
+func Enabled(ctx context.Context, name string) bool {
+	return FromContext(ctx).Enabled(name)
+}
@@ -0,0 +1,50 @@
+package flags
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		environ  []string
+		expected []string
+	}{
+		{
+			name:     "正常系/有効なフラグのみ読み込む場合",
+			environ:  []string{"FLAGS_NEW_SINK=true", "FLAGS_NEW_ENGINE=false", "APP_ENV=prd"},
+			expected: []string{"new_sink"},
+		},
+		{
+			name:     "正常系/真偽値の表記が異なる場合",
+			environ:  []string{"FLAGS_B=1", "FLAGS_A=TRUE"},
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "異常系/真偽値として解釈できない場合",
+			environ:  []string{"FLAGS_NEW_SINK=yes"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			ta.Equal(tt.expected, parse(tt.environ).Names())
+		})
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	ta := assert.New(t)
+
+	ctx := context.Background()
+	ta.False(Enabled(ctx, "new_sink"))
+
+	ctx = WithSet(ctx, New("NEW_SINK"))
+	ta.True(Enabled(ctx, "new_sink"))
+	ta.False(Enabled(ctx, "new_engine"))
+}
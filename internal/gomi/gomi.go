@@ -0,0 +1,205 @@
+// Package gomi は自治体のゴミの収集日を扱う
+// 種類ごとに収集する曜日と第 N 週の規則を定義し、前日の夕方に翌日収集するゴミを通知する
+package gomi
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// ゴミの種類ごとの収集の規則
+type Rule struct {
+	Type         string   `json:"type"`          // e.g. 燃えるゴミ
+	Weekdays     string   `json:"weekdays"`      // e.g. mon,thu
+	Weeks        []int    `json:"weeks"`         // 第 N 曜日のみ収集する場合に指定する、e.g. [2, 4]、未指定の場合は毎週
+	Emoji        string   `json:"emoji"`         // e.g. 🔥
+	SkipHolidays bool     `json:"skip_holidays"` // 祝日は収集しない
+	Except       []string `json:"except"`        // 収集しない日もしくは期間、e.g. ["2025-12-31", "2025-12-29/2026-01-03"]
+
+	weekdays []time.Weekday
+	except   []dateRange
+}
+
+// タイムゾーンによらず比較できるように、日付は 2006-01-02 の形式で保持する
+type dateRange struct {
+	start, end string
+}
+
+// 収集の規則の一覧
+// e.g. [{"type": "燃えるゴミ", "weekdays": "mon,thu", "emoji": "🔥"}, {"type": "資源ゴミ", "weekdays": "wed", "weeks": [2, 4]}]
+type Calendar struct {
+	Rules []Rule
+}
+
+// JSON で定義した収集の規則を読み込む
+func Parse(b []byte) (*Calendar, error) {
+	var rules []Rule
+	if err := json.Unmarshal(b, &rules); err != nil {
+		return nil, fmt.Errorf("invalid gomi schedule: %w", err)
+	}
+	for i := range rules {
+		if err := rules[i].init(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Calendar{Rules: rules}, nil
+}
+
+// シートの行から収集の規則を読み込む、1 行目はヘッダーとして読み飛ばす
+// 列は A: 種類, B: 曜日, C: 第 N 週 (e.g. 2,4), D: 絵文字, E: 祝日は収集しない, F: 収集しない日 (カンマ区切り)
+func ParseRows(rows [][]interface{}) (*Calendar, error) {
+	var rules []Rule
+	for i := 1; i < len(rows); i++ {
+		r := rows[i]
+		if cell(r, 0) == "" {
+			continue
+		}
+		weeks, err := parseWeeks(cell(r, 2))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		rule := Rule{
+			Type:         cell(r, 0),
+			Weekdays:     cell(r, 1),
+			Weeks:        weeks,
+			Emoji:        cell(r, 3),
+			SkipHolidays: strings.EqualFold(cell(r, 4), "true"),
+			Except:       splitList(cell(r, 5)),
+		}
+		if err := rule.init(); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return &Calendar{Rules: rules}, nil
+}
+
+func (r *Rule) init() error {
+	if r.Type == "" {
+		return fmt.Errorf("gomi type is blank")
+	}
+	days, err := event.ParseWeekdays(r.Weekdays)
+	if err != nil {
+		return fmt.Errorf("%s: %w", r.Type, err)
+	}
+	r.weekdays = days
+	for _, w := range r.Weeks {
+		if w < 1 || w > 5 {
+			return fmt.Errorf("%s: invalid week: %d", r.Type, w)
+		}
+	}
+	for _, s := range r.Except {
+		dr, err := parseDateRange(s)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Type, err)
+		}
+		r.except = append(r.except, dr)
+	}
+
+	return nil
+}
+
+// t に収集するかを返す
+func (r *Rule) collects(t time.Time, holidays event.Holidays) bool {
+	if !slices.Contains(r.weekdays, t.Weekday()) {
+		return false
+	}
+	// 第 N 曜日は月の N 回目のその曜日を指す
+	if len(r.Weeks) > 0 && !slices.Contains(r.Weeks, (t.Day()-1)/7+1) {
+		return false
+	}
+	if r.SkipHolidays && holidays.IsHoliday(t) {
+		return false
+	}
+	day := t.Format("2006-01-02")
+	for _, dr := range r.except {
+		if dr.start <= day && day <= dr.end {
+			return false
+		}
+	}
+
+	return true
+}
+
+// t に収集するゴミの規則を、定義した順に返す
+func (c *Calendar) Collections(t time.Time, holidays event.Holidays) []Rule {
+	var rules []Rule
+	for _, r := range c.Rules {
+		if r.collects(t, holidays) {
+			rules = append(rules, r)
+		}
+	}
+
+	return rules
+}
+
+// 収集するゴミを 1 行で表す、e.g. 🔥 燃えるゴミ、資源ゴミ
+func Describe(rules []Rule) string {
+	if len(rules) == 0 {
+		return "なし"
+	}
+	names := make([]string, 0, len(rules))
+	for _, r := range rules {
+		names = append(names, strings.TrimSpace(r.Emoji+" "+r.Type))
+	}
+
+	return strings.Join(names, "、")
+}
+
+func parseWeeks(s string) ([]int, error) {
+	var weeks []int
+	for _, v := range splitList(s) {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid week: %s", v)
+		}
+		weeks = append(weeks, n)
+	}
+
+	return weeks, nil
+}
+
+// "2025-12-31" もしくは "2025-12-29/2026-01-03" の形式の日付もしくは期間をパースする
+func parseDateRange(s string) (dateRange, error) {
+	from, to, isRange := strings.Cut(s, "/")
+	if !isRange {
+		to = from
+	}
+	start, err := time.Parse("2006-01-02", strings.TrimSpace(from))
+	if err != nil {
+		return dateRange{}, fmt.Errorf("invalid date: %s", s)
+	}
+	end, err := time.Parse("2006-01-02", strings.TrimSpace(to))
+	if err != nil || end.Before(start) {
+		return dateRange{}, fmt.Errorf("invalid date: %s", s)
+	}
+
+	return dateRange{start: start.Format("2006-01-02"), end: end.Format("2006-01-02")}, nil
+}
+
+func cell(r []interface{}, i int) string {
+	if i >= len(r) {
+		return ""
+	}
+
+	return strings.TrimSpace(fmt.Sprint(r[i]))
+}
+
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,108 @@
+package gomi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tz = time.FixedZone("JST", 9*60*60)
+
+const schedule = `[
+	{"type": "燃えるゴミ", "weekdays": "mon,thu", "emoji": "🔥", "except": ["2025-12-29/2026-01-03"]},
+	{"type": "資源ゴミ", "weekdays": "wed", "weeks": [2, 4], "emoji": "♻", "skip_holidays": true},
+	{"type": "粗大ゴミ", "weekdays": "金", "weeks": [1]}
+]`
+
+func TestCollections(t *testing.T) {
+	c, err := Parse([]byte(schedule))
+	require.NoError(t, err)
+	holidays := event.Holidays{"2025-01-22": "テスト用の祝日"}
+
+	tests := []struct {
+		name     string
+		target   time.Time
+		expected []string
+	}{
+		{name: "正常系/毎週の曜日", target: time.Date(2025, 1, 6, 0, 0, 0, 0, tz), expected: []string{"燃えるゴミ"}},
+		{name: "正常系/第 2 水曜日", target: time.Date(2025, 1, 8, 0, 0, 0, 0, tz), expected: []string{"資源ゴミ"}},
+		{name: "正常系/第 3 水曜日は収集しない", target: time.Date(2025, 1, 15, 0, 0, 0, 0, tz), expected: nil},
+		{name: "正常系/祝日は収集しない", target: time.Date(2025, 1, 22, 0, 0, 0, 0, tz), expected: nil},
+		{name: "正常系/第 1 金曜日", target: time.Date(2025, 1, 3, 0, 0, 0, 0, tz), expected: []string{"粗大ゴミ"}},
+		{name: "正常系/年末年始は収集しない", target: time.Date(2026, 1, 1, 0, 0, 0, 0, tz), expected: nil},
+		{name: "正常系/年末年始の前は収集する", target: time.Date(2025, 12, 25, 0, 0, 0, 0, tz), expected: []string{"燃えるゴミ"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actual []string
+			for _, r := range c.Collections(tt.target, holidays) {
+				actual = append(actual, r.Type)
+			}
+			assert.New(t).Equal(tt.expected, actual)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "異常系/JSON でない場合", input: "燃えるゴミ"},
+		{name: "異常系/種類がない場合", input: `[{"weekdays": "mon"}]`},
+		{name: "異常系/曜日が不正な場合", input: `[{"type": "燃えるゴミ", "weekdays": "someday"}]`},
+		{name: "異常系/週が範囲外の場合", input: `[{"type": "資源ゴミ", "weekdays": "wed", "weeks": [6]}]`},
+		{name: "異常系/収集しない日が不正な場合", input: `[{"type": "燃えるゴミ", "weekdays": "mon", "except": ["12/31"]}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.input))
+			assert.New(t).Error(err)
+		})
+	}
+}
+
+func TestParseRows(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	c, err := ParseRows([][]interface{}{
+		{"Type", "Weekdays", "Weeks", "Emoji", "SkipHolidays", "Except"},
+		{"燃えるゴミ", "mon,thu", "", "🔥", "FALSE", "2025-12-31, 2026-01-01/2026-01-03"},
+		{},
+		{"資源ゴミ", "wed", "2,4", "♻", "TRUE"},
+	})
+	tr.NoError(err)
+	tr.Len(c.Rules, 2)
+	ta.Equal([]int{2, 4}, c.Rules[1].Weeks)
+	ta.True(c.Rules[1].SkipHolidays)
+	ta.Empty(c.Collections(time.Date(2026, 1, 1, 0, 0, 0, 0, tz), nil))
+
+	_, err = ParseRows([][]interface{}{{"Type"}, {"資源ゴミ", "wed", "second"}})
+	ta.ErrorContains(err, "row 2")
+}
+
+func TestSource(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	c, err := Parse([]byte(schedule))
+	tr.NoError(err)
+
+	// 前日に翌日のゴミを通知する
+	events, err := NewSource(c, nil).Fetch(context.Background(), time.Date(2025, 1, 7, 0, 0, 0, 0, tz))
+	tr.NoError(err)
+	tr.Len(events, 1)
+	ta.Equal("明日は資源ゴミ", events[0].Name)
+	ta.Equal(event.Monthly, events[0].Interval)
+	ta.Equal([]string{Tag}, events[0].Tags)
+
+	ta.Equal("🔥 燃えるゴミ、資源ゴミ", Describe([]Rule{{Type: "燃えるゴミ", Emoji: "🔥"}, {Type: "資源ゴミ"}}))
+	ta.Equal("なし", Describe(nil))
+}
@@ -0,0 +1,40 @@
+package gomi
+
+import (
+	"context"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// イベントに付けるタグ、夕方のスケジュールのみで通知する場合はプロファイルの tags に指定する
+const Tag = "gomi"
+
+// 前日の夕方に通知するため、翌日に収集するゴミを t のイベントとして返す取得元
+type Source struct {
+	calendar *Calendar
+	holidays event.Holidays
+}
+
+func NewSource(c *Calendar, holidays event.Holidays) *Source {
+	return &Source{calendar: c, holidays: holidays}
+}
+
+func (s *Source) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	rules := s.calendar.Collections(t.AddDate(0, 0, 1), s.holidays)
+	events := make([]event.Event, 0, len(rules))
+	for _, r := range rules {
+		interval := event.Weekly
+		if len(r.Weeks) > 0 {
+			interval = event.Monthly
+		}
+		events = append(events, event.Event{
+			Name:     "明日は" + r.Type,
+			Interval: interval,
+			Emoji:    r.Emoji,
+			Tags:     []string{Tag},
+		})
+	}
+
+	return events, nil
+}
@@ -0,0 +1,93 @@
+// Package guest は来客の予定を、前の日に分けた準備の項目に展開する
+// スプレッドシートに来客のタグを付けたイベントを登録すると、テンプレートの項目を来客の N 日前に通知する
+package guest
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// 準備の項目
+type Task struct {
+	Name       string `json:"name"`        // e.g. お風呂掃除
+	DaysBefore int    `json:"days_before"` // 来客の N 日前に通知する、0 の場合は当日
+}
+
+// タグごとの準備の項目
+type Template struct {
+	Tag   string `json:"tag"` // 展開するイベントのタグ、e.g. guest
+	Tasks []Task `json:"tasks"`
+}
+
+// e.g. [{"tag": "guest", "tasks": [{"name": "お菓子を買う", "days_before": 2}, {"name": "お風呂掃除", "days_before": 1}]}]
+type Templates []Template
+
+func (t *Templates) UnmarshalText(text []byte) error {
+	// Templates のまま読み込むと UnmarshalText が再度呼び出されるため、スライスとして読み込む
+	var templates []Template
+	if err := json.Unmarshal(text, &templates); err != nil {
+		return fmt.Errorf("invalid guest templates: %w", err)
+	}
+	tags := map[string]bool{}
+	for i := range templates {
+		if err := templates[i].init(); err != nil {
+			return fmt.Errorf("invalid guest templates: %w", err)
+		}
+		if tags[templates[i].Tag] {
+			return fmt.Errorf("invalid guest templates: duplicate tag: %s", templates[i].Tag)
+		}
+		tags[templates[i].Tag] = true
+	}
+	*t = templates
+
+	return nil
+}
+
+func (t *Template) init() error {
+	// スプレッドシートのタグと同じく小文字で比較する
+	t.Tag = strings.ToLower(strings.TrimSpace(t.Tag))
+	if t.Tag == "" {
+		return fmt.Errorf("tag is blank")
+	}
+	if len(t.Tasks) == 0 {
+		return fmt.Errorf("%s: tasks are empty", t.Tag)
+	}
+	for i := range t.Tasks {
+		t.Tasks[i].Name = strings.TrimSpace(t.Tasks[i].Name)
+		if t.Tasks[i].Name == "" {
+			return fmt.Errorf("%s: task name is blank", t.Tag)
+		}
+		if t.Tasks[i].DaysBefore < 0 {
+			return fmt.Errorf("%s: invalid days_before: %d", t.Tag, t.Tasks[i].DaysBefore)
+		}
+	}
+
+	return nil
+}
+
+// 項目を通知する日が来客の何日前かを、重複を除いて昇順に返す
+func (t Templates) daysBefore() []int {
+	var days []int
+	for _, tmpl := range t {
+		for _, task := range tmpl.Tasks {
+			if !slices.Contains(days, task.DaysBefore) {
+				days = append(days, task.DaysBefore)
+			}
+		}
+	}
+	slices.Sort(days)
+
+	return days
+}
+
+// イベントのタグに一致するテンプレートを返す
+func (t Templates) find(tags []string) (Template, bool) {
+	i := slices.IndexFunc(t, func(tmpl Template) bool { return slices.Contains(tags, tmpl.Tag) })
+	if i < 0 {
+		return Template{}, false
+	}
+
+	return t[i], true
+}
@@ -0,0 +1,153 @@
+package guest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, jst)
+}
+
+func TestTemplatesUnmarshalText(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		want    Templates
+		wantErr bool
+	}{
+		{
+			name:  "正常系/タグを小文字にする",
+			input: `[{"tag": " Guest ", "tasks": [{"name": "お風呂掃除", "days_before": 1}, {"name": "玄関の掃除"}]}]`,
+			want:  Templates{{Tag: "guest", Tasks: []Task{{Name: "お風呂掃除", DaysBefore: 1}, {Name: "玄関の掃除"}}}},
+		},
+		{name: "異常系/タグが空", input: `[{"tasks": [{"name": "お風呂掃除"}]}]`, wantErr: true},
+		{name: "異常系/項目が空", input: `[{"tag": "guest", "tasks": []}]`, wantErr: true},
+		{name: "異常系/項目の名前が空", input: `[{"tag": "guest", "tasks": [{"days_before": 1}]}]`, wantErr: true},
+		{name: "異常系/負の日数", input: `[{"tag": "guest", "tasks": [{"name": "お風呂掃除", "days_before": -1}]}]`, wantErr: true},
+		{name: "異常系/タグが重複している", input: `[{"tag": "guest", "tasks": [{"name": "a"}]}, {"tag": "GUEST", "tasks": [{"name": "b"}]}]`, wantErr: true},
+		{name: "異常系/JSON ではない", input: `guest`, wantErr: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			var got Templates
+			err := got.UnmarshalText([]byte(tt.input))
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.want, got)
+		})
+	}
+}
+
+type fakeFetcher struct {
+	events map[time.Time][]event.Event
+	err    error
+	calls  int
+}
+
+func (f *fakeFetcher) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	f.calls++
+	return f.events[t], f.err
+}
+
+// 複数の日付をまとめて取得できる取得元
+type fakeDatesFetcher struct {
+	fakeFetcher
+	dateCalls int
+}
+
+func (f *fakeDatesFetcher) FetchDates(ctx context.Context, dates []time.Time) ([][]event.Event, error) {
+	f.dateCalls++
+	events := make([][]event.Event, len(dates))
+	for i, t := range dates {
+		events[i] = f.events[t]
+	}
+	return events, f.err
+}
+
+func TestSourceFetch(t *testing.T) {
+	var templates Templates
+	require.NoError(t, templates.UnmarshalText([]byte(`[{"tag": "guest", "tasks": [
+		{"name": "お菓子を買う", "days_before": 2},
+		{"name": "お風呂掃除", "days_before": 1},
+		{"name": "布団を干す", "days_before": 2},
+		{"name": "玄関の掃除"}
+	]}]`)))
+	visit := event.Event{Name: "田中さん来訪", Interval: event.Onetime, Tags: []string{"guest", "morning"}}
+	names := func(events []event.Event) []string {
+		var s []string
+		for _, e := range events {
+			s = append(s, e.Name)
+		}
+		return s
+	}
+
+	t.Run("正常系/来客の 2 日前の項目を返す", func(t *testing.T) {
+		ta := assert.New(t)
+
+		f := &fakeFetcher{events: map[time.Time][]event.Event{
+			date(2025, 3, 13): {{Name: "燃えるゴミ"}},
+			date(2025, 3, 15): {visit},
+		}}
+		events, err := NewSource(f, templates).Fetch(context.Background(), date(2025, 3, 13))
+		ta.NoError(err)
+		ta.Equal([]string{"燃えるゴミ", "田中さん来訪: お菓子を買う", "田中さん来訪: 布団を干す"}, names(events))
+		ta.Equal("3/15 田中さん来訪 (あと 2 日)", events[1].Description)
+		ta.Equal([]string{"guest", "morning"}, events[1].Tags)
+		// 当日と 1 日後と 2 日後の 3 回取得する
+		ta.Equal(3, f.calls)
+	})
+
+	t.Run("正常系/まとめて取得できる取得元は一度だけ取得する", func(t *testing.T) {
+		ta := assert.New(t)
+
+		f := &fakeDatesFetcher{fakeFetcher: fakeFetcher{events: map[time.Time][]event.Event{
+			date(2025, 3, 13): {{Name: "燃えるゴミ"}},
+			date(2025, 3, 15): {visit},
+		}}}
+		events, err := NewSource(f, templates).Fetch(context.Background(), date(2025, 3, 13))
+		ta.NoError(err)
+		ta.Equal([]string{"燃えるゴミ", "田中さん来訪: お菓子を買う", "田中さん来訪: 布団を干す"}, names(events))
+		ta.Equal(1, f.dateCalls)
+		ta.Zero(f.calls)
+	})
+
+	t.Run("正常系/当日の項目は来客のイベントの後に返す", func(t *testing.T) {
+		f := &fakeFetcher{events: map[time.Time][]event.Event{date(2025, 3, 15): {visit}}}
+		events, err := NewSource(f, templates).Fetch(context.Background(), date(2025, 3, 15))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"田中さん来訪", "田中さん来訪: 玄関の掃除"}, names(events))
+		assert.Equal(t, "3/15 田中さん来訪", events[1].Description)
+	})
+
+	t.Run("正常系/事前の通知とタグのないイベントは展開しない", func(t *testing.T) {
+		notice := visit
+		notice.LeadDays = 3
+		f := &fakeFetcher{events: map[time.Time][]event.Event{
+			date(2025, 3, 14): {notice, {Name: "歯医者"}},
+		}}
+		events, err := NewSource(f, templates).Fetch(context.Background(), date(2025, 3, 13))
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("異常系/取得に失敗した場合", func(t *testing.T) {
+		f := &fakeFetcher{err: errors.New("unavailable")}
+		_, err := NewSource(f, templates).Fetch(context.Background(), date(2025, 3, 13))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,106 @@
+package guest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// 展開する前のイベントを取得する
+type Fetcher interface {
+	Fetch(ctx context.Context, t time.Time) ([]event.Event, error)
+}
+
+// 複数の日付のイベントを一度の読み込みで取得できる取得元、e.g. sources.SheetSource
+type DatesFetcher interface {
+	// dates と同じ順で、それぞれの日付のイベントを返す
+	FetchDates(ctx context.Context, dates []time.Time) ([][]event.Event, error)
+}
+
+// 取得元のイベントに加えて、N 日後の来客の準備の項目を返すデータソース
+// 来客の日のイベントを取得するため、当日に加えて days_before の種類の数の日付のイベントを取得する
+// 取得元が DatesFetcher を実装している場合は、全ての日付をまとめて一度だけ取得する
+type Source struct {
+	source    Fetcher
+	templates Templates
+}
+
+func NewSource(source Fetcher, templates Templates) *Source {
+	return &Source{source: source, templates: templates}
+}
+
+func (s *Source) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	days := s.templates.daysBefore()
+	dates := []time.Time{t}
+	for _, n := range days {
+		if n > 0 {
+			dates = append(dates, t.AddDate(0, 0, n))
+		}
+	}
+	fetched, err := s.fetchDates(ctx, dates)
+	if err != nil {
+		return nil, err
+	}
+	events := fetched[0]
+
+	var tasks []event.Event
+	for _, n := range days {
+		date := t.AddDate(0, 0, n)
+		visits := events
+		if n > 0 {
+			visits = fetched[slices.IndexFunc(dates, date.Equal)]
+		}
+		for _, e := range visits {
+			// 事前の通知のイベントからは展開しない
+			if e.LeadDays > 0 {
+				continue
+			}
+			tmpl, ok := s.templates.find(e.Tags)
+			if !ok {
+				continue
+			}
+			for _, task := range tmpl.Tasks {
+				if task.DaysBefore == n {
+					tasks = append(tasks, createTaskEvent(e, task, date))
+				}
+			}
+		}
+	}
+
+	return append(events, tasks...), nil
+}
+
+func (s *Source) fetchDates(ctx context.Context, dates []time.Time) ([][]event.Event, error) {
+	if f, ok := s.source.(DatesFetcher); ok {
+		return f.FetchDates(ctx, dates)
+	}
+
+	fetched := make([][]event.Event, len(dates))
+	for i, date := range dates {
+		events, err := s.source.Fetch(ctx, date)
+		if err != nil {
+			return nil, err
+		}
+		fetched[i] = events
+	}
+
+	return fetched, nil
+}
+
+func createTaskEvent(visit event.Event, task Task, date time.Time) event.Event {
+	description := fmt.Sprintf("%s %s", date.Format("1/2"), visit.Name)
+	if task.DaysBefore > 0 {
+		description += fmt.Sprintf(" (あと %d 日)", task.DaysBefore)
+	}
+
+	return event.Event{
+		Name:        fmt.Sprintf("%s: %s", visit.Name, task.Name),
+		Interval:    event.Onetime,
+		Emoji:       "📝",
+		Description: description,
+		Tags:        visit.Tags,
+	}
+}
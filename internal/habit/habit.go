@@ -0,0 +1,178 @@
+// Package habit は毎日続ける習慣の記録と、連続して記録した日数を提供する
+// Discord のコマンドで習慣を登録して記録し、remind の投稿で週ごとに記録をまとめる
+package habit
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+)
+
+// remind の対応状況と同じ日付の形式
+const dateFormat = "20060102"
+
+const partitionKey = "habit"
+
+// 登録した習慣
+type Habit struct {
+	Name    string
+	AddedBy string
+}
+
+// 習慣と記録を DynamoDB に保存する
+// テーブルのキーは pk (パーティションキー) と sk (ソートキー) とし、
+// 習慣は "habit" に名前で、記録は "habit#<名前>" に記録した日付でそれぞれ保存する
+// キーの形式が同じため、remind の対応状況を記録するテーブルと共有できる
+type Store struct {
+	client *dynamodb.Client
+	table  string
+}
+
+func NewStore(client *dynamodb.Client, table string) *Store {
+	return &Store{client: client, table: table}
+}
+
+func checkInPartitionKey(name string) string {
+	return partitionKey + "#" + name
+}
+
+// 習慣を登録する、登録済みの場合は false を返す
+func (s *Store) Add(ctx context.Context, name, user string, now time.Time) (bool, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return false, fmt.Errorf("habit name is blank")
+	}
+	item := dynamodb.Item{
+		"pk":       dynamodb.S(partitionKey),
+		"sk":       dynamodb.S(name),
+		"added_by": dynamodb.S(user),
+		"added_at": dynamodb.N(now.Unix()),
+	}
+	if err := s.client.PutItem(ctx, s.table, item, "attribute_not_exists(pk)", nil); err != nil {
+		if dynamodb.IsConditionalCheckFailed(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// 登録した習慣を名前の順に返す
+func (s *Store) List(ctx context.Context) ([]Habit, error) {
+	items, err := s.client.Query(ctx, s.table, "pk = :pk", dynamodb.Item{":pk": dynamodb.S(partitionKey)})
+	if err != nil {
+		return nil, err
+	}
+
+	habits := make([]Habit, 0, len(items))
+	for _, item := range items {
+		habits = append(habits, Habit{Name: item.Str("sk"), AddedBy: item.Str("added_by")})
+	}
+
+	return habits, nil
+}
+
+// 習慣の登録を削除する、記録は残すため同じ名前で登録し直すと続きから数える
+func (s *Store) Remove(ctx context.Context, h Habit) error {
+	return s.client.DeleteItem(ctx, s.table, dynamodb.Item{
+		"pk": dynamodb.S(partitionKey),
+		"sk": dynamodb.S(h.Name),
+	})
+}
+
+// date の日に習慣を記録する、記録済みの場合は false を返す
+func (s *Store) CheckIn(ctx context.Context, h Habit, date time.Time, user string) (bool, error) {
+	item := dynamodb.Item{
+		"pk":   dynamodb.S(checkInPartitionKey(h.Name)),
+		"sk":   dynamodb.S(date.Format(dateFormat)),
+		"user": dynamodb.S(user),
+	}
+	if err := s.client.PutItem(ctx, s.table, item, "attribute_not_exists(pk)", nil); err != nil {
+		if dynamodb.IsConditionalCheckFailed(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// 記録した日付を古い順に返す
+func (s *Store) CheckIns(ctx context.Context, h Habit, loc *time.Location) ([]time.Time, error) {
+	items, err := s.client.Query(ctx, s.table, "pk = :pk", dynamodb.Item{":pk": dynamodb.S(checkInPartitionKey(h.Name))})
+	if err != nil {
+		return nil, err
+	}
+
+	dates := make([]time.Time, 0, len(items))
+	for _, item := range items {
+		d, err := time.ParseInLocation(dateFormat, item.Str("sk"), loc)
+		if err != nil {
+			continue
+		}
+		dates = append(dates, d)
+	}
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+
+	return dates, nil
+}
+
+// 名前で習慣を探す、大文字と小文字を区別しない
+func Find(habits []Habit, name string) (Habit, bool) {
+	name = strings.TrimSpace(name)
+	for _, h := range habits {
+		if strings.EqualFold(h.Name, name) {
+			return h, true
+		}
+	}
+
+	return Habit{}, false
+}
+
+// 習慣ごとの記録の集計
+type Summary struct {
+	Habit  Habit
+	Streak int // 連続して記録した日数
+	Week   int // today までの 7 日間に記録した日数
+}
+
+// 記録した日付から連続して記録した日数と、直近 7 日間に記録した日数を数える
+// today の記録がまだない場合も、前日まで続いていれば途切れていないものとする
+func Summarize(h Habit, dates []time.Time, today time.Time) Summary {
+	days := map[string]bool{}
+	for _, d := range dates {
+		days[d.Format(dateFormat)] = true
+	}
+
+	s := Summary{Habit: h}
+	day := today
+	if !days[day.Format(dateFormat)] {
+		day = day.AddDate(0, 0, -1)
+	}
+	for days[day.Format(dateFormat)] {
+		s.Streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	for i := 0; i < 7; i++ {
+		if days[today.AddDate(0, 0, -i).Format(dateFormat)] {
+			s.Week++
+		}
+	}
+
+	return s
+}
+
+// e.g. "筋トレ: 🔥 5 日連続 (7 日中 6 日)"
+func (s Summary) String() string {
+	streak := "記録なし"
+	if s.Streak > 0 {
+		streak = fmt.Sprintf("🔥 %d 日連続", s.Streak)
+	}
+
+	return fmt.Sprintf("%s: %s (7 日中 %d 日)", s.Habit.Name, streak, s.Week)
+}
@@ -0,0 +1,123 @@
+package habit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tz = time.FixedZone("JST", 9*60*60)
+
+// 2025-03-01 を基準に days 日前の日付を返す
+func daysAgo(days ...int) []time.Time {
+	var dates []time.Time
+	for _, d := range days {
+		dates = append(dates, time.Date(2025, 3, 1, 0, 0, 0, 0, tz).AddDate(0, 0, -d))
+	}
+
+	return dates
+}
+
+func TestSummarize(t *testing.T) {
+	today := time.Date(2025, 3, 1, 0, 0, 0, 0, tz)
+	h := Habit{Name: "筋トレ"}
+
+	tests := []struct {
+		name     string
+		dates    []time.Time
+		expected Summary
+	}{
+		{name: "正常系/今日まで続いている場合", dates: daysAgo(0, 1, 2, 4), expected: Summary{Habit: h, Streak: 3, Week: 4}},
+		{name: "正常系/今日の記録がまだない場合は前日まで数える", dates: daysAgo(1, 2, 9), expected: Summary{Habit: h, Streak: 2, Week: 2}},
+		{name: "正常系/前日に途切れた場合", dates: daysAgo(2, 3), expected: Summary{Habit: h, Week: 2}},
+		{name: "正常系/7 日より前の記録は週に含めない", dates: daysAgo(0, 1, 2, 3, 4, 5, 6, 7), expected: Summary{Habit: h, Streak: 8, Week: 7}},
+		{name: "正常系/記録がない場合", expected: Summary{Habit: h}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Summarize(h, tt.dates, today))
+		})
+	}
+}
+
+func TestSummaryString(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.Equal("筋トレ: 🔥 5 日連続 (7 日中 6 日)", Summary{Habit: Habit{Name: "筋トレ"}, Streak: 5, Week: 6}.String())
+	ta.Equal("読書: 記録なし (7 日中 0 日)", Summary{Habit: Habit{Name: "読書"}}.String())
+}
+
+func TestFind(t *testing.T) {
+	ta := assert.New(t)
+	habits := []Habit{{Name: "筋トレ"}, {Name: "Reading"}}
+
+	h, ok := Find(habits, " reading ")
+	ta.True(ok)
+	ta.Equal(habits[1], h)
+
+	_, ok = Find(habits, "散歩")
+	ta.False(ok)
+}
+
+type fakeReader struct {
+	habits   []Habit
+	checkIns map[string][]time.Time
+	err      error
+}
+
+func (r fakeReader) List(ctx context.Context) ([]Habit, error) {
+	return r.habits, r.err
+}
+
+func (r fakeReader) CheckIns(ctx context.Context, h Habit, loc *time.Location) ([]time.Time, error) {
+	return r.checkIns[h.Name], nil
+}
+
+func TestSource(t *testing.T) {
+	sunday := time.Date(2025, 3, 2, 0, 0, 0, 0, tz)
+	reader := fakeReader{
+		habits:   []Habit{{Name: "筋トレ"}, {Name: "読書"}},
+		checkIns: map[string][]time.Time{"筋トレ": daysAgo(0, 1, 3)},
+	}
+
+	t.Run("正常系/指定した曜日は習慣ごとの記録を返す", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		events, err := NewSource(reader, []time.Weekday{time.Sunday}).Fetch(context.Background(), sunday)
+		tr.NoError(err)
+		tr.Len(events, 1)
+		ta.Equal("今週の習慣の記録", events[0].Name)
+		ta.Equal("筋トレ: 🔥 2 日連続 (7 日中 3 日)\n読書: 記録なし (7 日中 0 日)", events[0].Description)
+		ta.Equal([]string{Tag}, events[0].Tags)
+	})
+
+	t.Run("正常系/指定した曜日以外は返さない", func(t *testing.T) {
+		ta := assert.New(t)
+
+		events, err := NewSource(reader, []time.Weekday{time.Sunday}).Fetch(context.Background(), sunday.AddDate(0, 0, 1))
+		ta.NoError(err)
+		ta.Empty(events)
+	})
+
+	t.Run("正常系/習慣が登録されていない場合は返さない", func(t *testing.T) {
+		ta := assert.New(t)
+
+		events, err := NewSource(fakeReader{}, []time.Weekday{time.Sunday}).Fetch(context.Background(), sunday)
+		ta.NoError(err)
+		ta.Empty(events)
+	})
+
+	t.Run("異常系/取得に失敗した場合", func(t *testing.T) {
+		ta := assert.New(t)
+
+		_, err := NewSource(fakeReader{err: errors.New("throttled")}, []time.Weekday{time.Sunday}).Fetch(context.Background(), sunday)
+		ta.Equal(Tag, event.FailedComponent(err))
+	})
+}
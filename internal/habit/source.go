@@ -0,0 +1,59 @@
+package habit
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// イベントに付けるタグ、朝のスケジュールのみで通知する場合はプロファイルの tags に指定する
+const Tag = "habit"
+
+// 習慣と記録を取得する
+type Reader interface {
+	List(ctx context.Context) ([]Habit, error)
+	CheckIns(ctx context.Context, h Habit, loc *time.Location) ([]time.Time, error)
+}
+
+// 指定した曜日に、登録した習慣ごとの 1 週間の記録をイベントとして返す取得元
+type Source struct {
+	reader   Reader
+	weekdays []time.Weekday
+}
+
+func NewSource(r Reader, weekdays []time.Weekday) *Source {
+	return &Source{reader: r, weekdays: weekdays}
+}
+
+func (s *Source) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	if !slices.Contains(s.weekdays, t.Weekday()) {
+		return nil, nil
+	}
+	habits, err := s.reader.List(ctx)
+	if err != nil {
+		return nil, event.NewSourceUnavailableError(Tag, err)
+	}
+	if len(habits) == 0 {
+		return nil, nil
+	}
+
+	lines := make([]string, 0, len(habits))
+	for _, h := range habits {
+		dates, err := s.reader.CheckIns(ctx, h, t.Location())
+		if err != nil {
+			return nil, event.NewSourceUnavailableError(Tag, err)
+		}
+		lines = append(lines, Summarize(h, dates, t).String())
+	}
+
+	return []event.Event{{
+		Name:        "今週の習慣の記録",
+		Interval:    event.Weekly,
+		Emoji:       "📈",
+		Description: strings.Join(lines, "\n"),
+		Tags:        []string{Tag},
+	}}, nil
+}
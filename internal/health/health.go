@@ -0,0 +1,75 @@
+// Package health は自宅のサーバーや NAS から送られるハートビートとディスクの状態を評価する
+package health
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 自宅のマシンが定期的に送る状態、e.g. smartctl と df の結果を整形して送る
+type Report struct {
+	Host  string    `json:"host"`
+	Time  time.Time `json:"time"` // 受け付けた日時、送信元の時刻は使わない
+	Disks []Disk    `json:"disks,omitempty"`
+}
+
+type Disk struct {
+	Device             string `json:"device"`                        // e.g. /dev/sda, /
+	SMARTPassed        *bool  `json:"smart_passed,omitempty"`        // SMART の自己診断の結果、取得できない場合は省略する
+	Temperature        int    `json:"temperature,omitempty"`         // ℃
+	ReallocatedSectors int    `json:"reallocated_sectors,omitempty"` // 代替処理済みのセクタ数
+	PendingSectors     int    `json:"pending_sectors,omitempty"`     // 代替処理待ちのセクタ数
+	UsagePercent       int    `json:"usage_percent,omitempty"`       // 使用率
+}
+
+func (r Report) Validate() error {
+	if r.Host == "" {
+		return errors.New("host is required")
+	}
+	for _, d := range r.Disks {
+		if d.Device == "" {
+			return fmt.Errorf("device is required: %s", r.Host)
+		}
+		if d.UsagePercent < 0 || d.UsagePercent > 100 {
+			return fmt.Errorf("invalid usage percent: %d", d.UsagePercent)
+		}
+	}
+
+	return nil
+}
+
+// 異常として扱う閾値、0 の場合はその項目を評価しない
+type Thresholds struct {
+	UsagePercent int
+	Temperature  int
+}
+
+// ディスクの異常を返す、異常がない場合は空のスライスを返す
+func (r Report) Problems(th Thresholds) []string {
+	problems := []string{}
+	for _, d := range r.Disks {
+		if d.SMARTPassed != nil && !*d.SMARTPassed {
+			problems = append(problems, fmt.Sprintf("%s: SMART の自己診断に失敗しました", d.Device))
+		}
+		if d.ReallocatedSectors > 0 {
+			problems = append(problems, fmt.Sprintf("%s: 代替処理済みのセクタが %d 個あります", d.Device, d.ReallocatedSectors))
+		}
+		if d.PendingSectors > 0 {
+			problems = append(problems, fmt.Sprintf("%s: 代替処理待ちのセクタが %d 個あります", d.Device, d.PendingSectors))
+		}
+		if th.Temperature > 0 && d.Temperature >= th.Temperature {
+			problems = append(problems, fmt.Sprintf("%s: 温度が %d℃ です", d.Device, d.Temperature))
+		}
+		if th.UsagePercent > 0 && d.UsagePercent >= th.UsagePercent {
+			problems = append(problems, fmt.Sprintf("%s: 使用率が %d%% です", d.Device, d.UsagePercent))
+		}
+	}
+
+	return problems
+}
+
+// 最後のハートビートから timeout を過ぎた場合は true を返す
+func (r Report) Missed(now time.Time, timeout time.Duration) bool {
+	return now.Sub(r.Time) > timeout
+}
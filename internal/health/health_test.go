@@ -0,0 +1,83 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		report  Report
+		wantErr bool
+	}{
+		{name: "正常系/ディスクの状態を含む場合", report: Report{Host: "nas", Disks: []Disk{{Device: "/dev/sda", UsagePercent: 50}}}},
+		{name: "正常系/ハートビートのみの場合", report: Report{Host: "nas"}},
+		{name: "異常系/ホスト名がない場合", report: Report{}, wantErr: true},
+		{name: "異常系/デバイス名がない場合", report: Report{Host: "nas", Disks: []Disk{{}}}, wantErr: true},
+		{name: "異常系/使用率が範囲外の場合", report: Report{Host: "nas", Disks: []Disk{{Device: "/", UsagePercent: 101}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			err := tt.report.Validate()
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+		})
+	}
+}
+
+func TestReportProblems(t *testing.T) {
+	passed, failed := true, false
+	th := Thresholds{UsagePercent: 90, Temperature: 60}
+
+	tests := []struct {
+		name     string
+		disks    []Disk
+		expected []string
+	}{
+		{
+			name:     "正常系/異常がない場合",
+			disks:    []Disk{{Device: "/dev/sda", SMARTPassed: &passed, Temperature: 40, UsagePercent: 89}},
+			expected: []string{},
+		},
+		{
+			name:  "正常系/SMART とセクタの異常",
+			disks: []Disk{{Device: "/dev/sda", SMARTPassed: &failed, ReallocatedSectors: 8, PendingSectors: 1}},
+			expected: []string{
+				"/dev/sda: SMART の自己診断に失敗しました",
+				"/dev/sda: 代替処理済みのセクタが 8 個あります",
+				"/dev/sda: 代替処理待ちのセクタが 1 個あります",
+			},
+		},
+		{
+			name:     "正常系/温度と使用率が閾値以上",
+			disks:    []Disk{{Device: "/dev/sdb", Temperature: 60}, {Device: "/", UsagePercent: 95}},
+			expected: []string{"/dev/sdb: 温度が 60℃ です", "/: 使用率が 95% です"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			ta.Equal(tt.expected, Report{Host: "nas", Disks: tt.disks}.Problems(th))
+		})
+	}
+}
+
+func TestReportMissed(t *testing.T) {
+	ta := assert.New(t)
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := Report{Host: "nas", Time: now.Add(-15 * time.Minute)}
+	ta.False(r.Missed(now, 15*time.Minute))
+	ta.True(r.Missed(now.Add(time.Second), 15*time.Minute))
+}
@@ -0,0 +1,168 @@
+// Package httpclient は各連携先で共有する HTTP クライアントを提供する
+// タイムアウトを設定し、冪等なリクエストは一時的な失敗に対して間隔を空けて再試行する
+// Lambda 以外のネットワークで動かす場合に備えて、HTTPS_PROXY などのプロキシの指定と追加の CA 証明書に対応する
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	timeout     = 30 * time.Second // 再試行を含めたリクエスト全体のタイムアウト
+	maxAttempts = 3
+	baseDelay   = 200 * time.Millisecond
+	maxDelay    = 2 * time.Second
+)
+
+// 追加で信頼する CA 証明書 (PEM) のパス、e.g. 通信を検査するプロキシの CA
+// システムの証明書に加えて信頼する
+const caBundleEnv = "HTTP_CA_BUNDLE"
+
+// 各連携先で共有するクライアント、接続を使い回すため連携先ごとに作らない
+var Default = New()
+
+func New() *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &retryTransport{base: newTransport(), sleep: sleep},
+	}
+}
+
+func newTransport() *http.Transport {
+	t := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	if path := os.Getenv(caBundleEnv); path != "" {
+		cfg, err := newTLSConfig(path)
+		if err != nil {
+			// 読み込めない場合もシステムの証明書で接続できる連携先があるため、起動は続ける
+			slog.Error("failed to load CA bundle", slog.String("path", path), slog.Any("error", err))
+		} else {
+			t.TLSClientConfig = cfg
+		}
+	}
+
+	return t
+}
+
+func newTLSConfig(caBundle string) (*tls.Config, error) {
+	b, err := os.ReadFile(caBundle)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("invalid CA bundle: %s", caBundle)
+	}
+
+	return &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}, nil
+}
+
+// 冪等なリクエストのみ、接続エラーと一時的なサーバーエラーの場合に再試行する
+// 429 は連携先ごとに待つ時間の指定方法が異なるため、各クライアントに任せる
+type retryTransport struct {
+	base  http.RoundTripper
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isIdempotent(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= maxAttempts || !shouldRetry(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		if err := t.sleep(req.Context(), backoff(attempt)); err != nil {
+			return nil, err
+		}
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	// ボディを読み直せない場合は再送できない
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// attempt 回目の失敗の後に待つ時間、同時に失敗したリクエストが揃って再試行しないように揺らぎを加える
+func backoff(attempt int) time.Duration {
+	d := min(baseDelay<<(attempt-1), maxDelay)
+
+	return d/2 + rand.N(d/2+1)
+}
+
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Body = body
+
+	return req, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,147 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		statuses       []int
+		expectedStatus int
+		expectedCalls  int
+	}{
+		{
+			name:           "正常系/一時的なエラーの後に成功した場合",
+			method:         http.MethodGet,
+			statuses:       []int{503, 502, 200},
+			expectedStatus: 200,
+			expectedCalls:  3,
+		},
+		{
+			name:           "正常系/ボディを持つ冪等なリクエストを再送する場合",
+			method:         http.MethodPut,
+			body:           `{"value": 1}`,
+			statuses:       []int{504, 200},
+			expectedStatus: 200,
+			expectedCalls:  2,
+		},
+		{
+			name:           "正常系/クライアントエラーは再試行しない場合",
+			method:         http.MethodGet,
+			statuses:       []int{404, 200},
+			expectedStatus: 404,
+			expectedCalls:  1,
+		},
+		{
+			name:           "正常系/レート制限は再試行しない場合",
+			method:         http.MethodGet,
+			statuses:       []int{429, 200},
+			expectedStatus: 429,
+			expectedCalls:  1,
+		},
+		{
+			name:           "異常系/冪等でないリクエストは再試行しない場合",
+			method:         http.MethodPost,
+			body:           `{"value": 1}`,
+			statuses:       []int{503, 200},
+			expectedStatus: 503,
+			expectedCalls:  1,
+		},
+		{
+			name:           "異常系/上限まで失敗し続けた場合",
+			method:         http.MethodGet,
+			statuses:       []int{503, 503, 503, 200},
+			expectedStatus: 503,
+			expectedCalls:  maxAttempts,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			tr := require.New(t)
+
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				ta.Equal(tt.body, string(b))
+				w.WriteHeader(tt.statuses[calls])
+				calls++
+			}))
+			defer srv.Close()
+
+			c := &http.Client{Transport: &retryTransport{
+				base:  http.DefaultTransport,
+				sleep: func(context.Context, time.Duration) error { return nil },
+			}}
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req, err := http.NewRequest(tt.method, srv.URL, body)
+			tr.NoError(err)
+			resp, err := c.Do(req)
+			tr.NoError(err)
+			resp.Body.Close()
+
+			ta.Equal(tt.expectedStatus, resp.StatusCode)
+			ta.Equal(tt.expectedCalls, calls)
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	ta := assert.New(t)
+
+	for attempt := 1; attempt <= 6; attempt++ {
+		d := min(baseDelay<<(attempt-1), maxDelay)
+		for range 100 {
+			got := backoff(attempt)
+			ta.GreaterOrEqual(got, d/2)
+			ta.LessOrEqual(got, d)
+		}
+	}
+}
+
+func TestCABundle(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	tr.NoError(os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600))
+
+	// 指定しない場合は自己署名の証明書を信頼しない
+	_, err := (&http.Client{Transport: newTransport()}).Get(srv.URL)
+	ta.Error(err)
+
+	t.Setenv(caBundleEnv, path)
+	resp, err := (&http.Client{Transport: newTransport()}).Get(srv.URL)
+	tr.NoError(err)
+	resp.Body.Close()
+	ta.Equal(http.StatusNoContent, resp.StatusCode)
+
+	_, err = newTLSConfig(filepath.Join(t.TempDir(), "missing.pem"))
+	ta.Error(err)
+	tr.NoError(os.WriteFile(path, []byte("not a certificate"), 0o600))
+	_, err = newTLSConfig(path)
+	ta.Error(err)
+}
@@ -0,0 +1,104 @@
+// Package indoor は Nature Remo や SwitchBot の温湿度計から取得した室内の環境をまとめる
+package indoor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+const Tag = "indoor"
+
+// 機器ごとのセンサーの値、センサーを持たない機種の値は nil とする
+type Reading struct {
+	Device      string   // e.g. リビング
+	Temperature *float64 // ℃
+	Humidity    *float64 // %
+	Illuminance *float64 // lx
+	Time        time.Time
+}
+
+// 快適とする室温 (℃) と湿度 (%) の範囲
+const (
+	minTemperature = 18
+	maxTemperature = 28
+	minHumidity    = 40
+	maxHumidity    = 60
+)
+
+// 快適な範囲を外れた値があれば、その内容を返す
+func Advice(r Reading) []string {
+	var advice []string
+	if t := r.Temperature; t != nil {
+		switch {
+		case *t < minTemperature:
+			advice = append(advice, "室温が低いです")
+		case *t > maxTemperature:
+			advice = append(advice, "室温が高いです")
+		}
+	}
+	if h := r.Humidity; h != nil {
+		switch {
+		case *h < minHumidity:
+			advice = append(advice, "乾燥しています")
+		case *h > maxHumidity:
+			advice = append(advice, "湿度が高いです")
+		}
+	}
+
+	return advice
+}
+
+// e.g. 24.5℃ / 55% / 120 lx
+func Format(r Reading) string {
+	var values []string
+	if r.Temperature != nil {
+		values = append(values, fmt.Sprintf("%.1f℃", *r.Temperature))
+	}
+	if r.Humidity != nil {
+		values = append(values, fmt.Sprintf("%.0f%%", *r.Humidity))
+	}
+	if r.Illuminance != nil {
+		values = append(values, fmt.Sprintf("%.0f lx", *r.Illuminance))
+	}
+
+	return strings.Join(values, " / ")
+}
+
+// 取得したセンサーの値を、当日のイベントとして返す取得元
+// センサーの値は取得した時点のものなので、当日以外の日付では何も返さない
+type Source struct {
+	readings []Reading
+	today    time.Time
+}
+
+func NewSource(readings []Reading, today time.Time) *Source {
+	return &Source{readings: readings, today: today}
+}
+
+func (s *Source) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	if len(s.readings) == 0 || !t.Equal(s.today) {
+		return nil, nil
+	}
+
+	var lines []string
+	for _, r := range s.readings {
+		lines = append(lines, fmt.Sprintf("%s: %s", r.Device, Format(r)))
+	}
+	for _, r := range s.readings {
+		for _, a := range Advice(r) {
+			lines = append(lines, fmt.Sprintf("⚠ %s: %s", r.Device, a))
+		}
+	}
+
+	return []event.Event{{
+		Name:        "室内環境",
+		Interval:    event.Onetime,
+		Emoji:       "🌡️",
+		Description: strings.Join(lines, "\n"),
+		Tags:        []string{Tag},
+	}}, nil
+}
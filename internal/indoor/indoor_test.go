@@ -0,0 +1,43 @@
+package indoor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr(v float64) *float64 {
+	return &v
+}
+
+func TestAdvice(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.Empty(Advice(Reading{Temperature: ptr(22), Humidity: ptr(50)}))
+	ta.Equal([]string{"室温が高いです", "湿度が高いです"}, Advice(Reading{Temperature: ptr(29), Humidity: ptr(70)}))
+	ta.Equal([]string{"乾燥しています"}, Advice(Reading{Humidity: ptr(30)}))
+}
+
+func TestSource(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	today := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	s := NewSource([]Reading{
+		{Device: "リビング", Temperature: ptr(24.5), Humidity: ptr(35), Illuminance: ptr(120)},
+		{Device: "寝室", Temperature: ptr(17.2)},
+	}, today)
+
+	events, err := s.Fetch(context.Background(), today)
+	tr.NoError(err)
+	tr.Len(events, 1)
+	ta.Equal("室内環境", events[0].Name)
+	ta.Equal("リビング: 24.5℃ / 35% / 120 lx\n寝室: 17.2℃\n⚠ リビング: 乾燥しています\n⚠ 寝室: 室温が低いです", events[0].Description)
+
+	events, err = s.Fetch(context.Background(), today.AddDate(0, 0, 1))
+	tr.NoError(err)
+	ta.Empty(events)
+}
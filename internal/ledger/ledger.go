@@ -0,0 +1,181 @@
+// Package ledger はスプレッドシートに記録した家計の支出と、月ごとの集計を提供する
+package ledger
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 支出を記録するシートの列、1 行目はヘッダーとする
+// A: 日付 (2006-01-02), B: 金額 (円), C: カテゴリ, D: メモ, E: 記録した人
+const Columns = "A:E"
+
+const dateFormat = "2006-01-02"
+
+// 1 件の支出
+type Entry struct {
+	Date     time.Time
+	Amount   int // 円、返金などは負の値
+	Category string
+	Memo     string
+	User     string
+}
+
+// 入力された支出を検証する
+func (e Entry) Validate() error {
+	if e.Amount == 0 {
+		return fmt.Errorf("amount is zero")
+	}
+	if strings.TrimSpace(e.Category) == "" {
+		return fmt.Errorf("category is blank")
+	}
+
+	return nil
+}
+
+// シートに追加する行に変換する
+func (e Entry) Row() []interface{} {
+	return []interface{}{e.Date.Format(dateFormat), e.Amount, e.Category, e.Memo, e.User}
+}
+
+// シートの行から支出を読み込む、1 行目はヘッダーとして読み飛ばす
+// 日付と金額が空の行は読み飛ばし、形式が不正な行はエラーを返す
+func ParseRows(rows [][]interface{}, loc *time.Location) ([]Entry, error) {
+	var entries []Entry
+	for i := 1; i < len(rows); i++ {
+		r := rows[i]
+		if cell(r, 0) == "" && cell(r, 1) == "" {
+			continue
+		}
+		date, err := time.ParseInLocation(dateFormat, cell(r, 0), loc)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid date: %s", i+1, cell(r, 0))
+		}
+		// 通貨の書式が設定されたセルも読み込めるように、桁区切りと円記号を取り除く
+		amount, err := strconv.Atoi(strings.NewReplacer(",", "", "¥", "", "￥", "").Replace(cell(r, 1)))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid amount: %s", i+1, cell(r, 1))
+		}
+		entries = append(entries, Entry{
+			Date:     date,
+			Amount:   amount,
+			Category: cell(r, 2),
+			Memo:     cell(r, 3),
+			User:     cell(r, 4),
+		})
+	}
+
+	return entries, nil
+}
+
+// カテゴリごとの前月との比較
+type CategoryTotal struct {
+	Category string
+	Total    int
+	Previous int // 前月の合計
+}
+
+// 前月との差額
+func (c CategoryTotal) Delta() int {
+	return c.Total - c.Previous
+}
+
+// 月ごとの集計
+type Summary struct {
+	Month      time.Time // 集計した月の 1 日
+	Categories []CategoryTotal
+}
+
+func (s Summary) Total() int {
+	var n int
+	for _, c := range s.Categories {
+		n += c.Total
+	}
+
+	return n
+}
+
+func (s Summary) Previous() int {
+	var n int
+	for _, c := range s.Categories {
+		n += c.Previous
+	}
+
+	return n
+}
+
+// month を含む月と前月の支出をカテゴリごとに集計する
+// 前月にのみ支出があったカテゴリも含め、合計の多い順に並べる
+func Summarize(entries []Entry, month time.Time) Summary {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	prev := start.AddDate(0, -1, 0)
+	end := start.AddDate(0, 1, 0)
+
+	totals := map[string]*CategoryTotal{}
+	get := func(category string) *CategoryTotal {
+		if totals[category] == nil {
+			totals[category] = &CategoryTotal{Category: category}
+		}
+		return totals[category]
+	}
+	for _, e := range entries {
+		switch {
+		case !e.Date.Before(start) && e.Date.Before(end):
+			get(e.Category).Total += e.Amount
+		case !e.Date.Before(prev) && e.Date.Before(start):
+			get(e.Category).Previous += e.Amount
+		}
+	}
+
+	s := Summary{Month: start}
+	for _, c := range totals {
+		s.Categories = append(s.Categories, *c)
+	}
+	slices.SortFunc(s.Categories, func(a, b CategoryTotal) int {
+		return cmp.Or(cmp.Compare(b.Total, a.Total), cmp.Compare(b.Previous, a.Previous), strings.Compare(a.Category, b.Category))
+	})
+
+	return s
+}
+
+// 金額を桁区切りの円で表す、e.g. ¥12,345
+func FormatYen(n int) string {
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	s := strconv.Itoa(n)
+	var b strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+
+	return sign + "¥" + b.String()
+}
+
+// 前月との差額を符号付きで表す、e.g. +¥1,200, -¥300, ±¥0
+func FormatDelta(n int) string {
+	switch {
+	case n > 0:
+		return "+" + FormatYen(n)
+	case n < 0:
+		return FormatYen(n)
+	default:
+		return "±" + FormatYen(0)
+	}
+}
+
+func cell(r []interface{}, i int) string {
+	if i >= len(r) {
+		return ""
+	}
+
+	return strings.TrimSpace(fmt.Sprint(r[i]))
+}
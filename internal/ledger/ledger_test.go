@@ -0,0 +1,115 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var tz = time.FixedZone("JST", 9*60*60)
+
+func TestParseRows(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows        [][]interface{}
+		expected    []Entry
+		expectError bool
+	}{
+		{
+			name: "正常系/ヘッダーと空行を読み飛ばす場合",
+			rows: [][]interface{}{
+				{"日付", "金額", "カテゴリ", "メモ", "記録した人"},
+				{"2025-03-01", "1,200", "食費", "ランチ", "alice"},
+				{},
+				{"2025-03-02", "¥-300", "食費"},
+			},
+			expected: []Entry{
+				{Date: time.Date(2025, 3, 1, 0, 0, 0, 0, tz), Amount: 1200, Category: "食費", Memo: "ランチ", User: "alice"},
+				{Date: time.Date(2025, 3, 2, 0, 0, 0, 0, tz), Amount: -300, Category: "食費"},
+			},
+		},
+		{
+			name: "異常系/日付が不正な形式である場合",
+			rows: [][]interface{}{
+				{"日付", "金額"},
+				{"2025/03/01", "1200"},
+			},
+			expectError: true,
+		},
+		{
+			name: "異常系/金額が数値でない場合",
+			rows: [][]interface{}{
+				{"日付", "金額"},
+				{"2025-03-01", "千円"},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			actual, err := ParseRows(tt.rows, tz)
+			if tt.expectError {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, actual)
+		})
+	}
+}
+
+func TestEntry(t *testing.T) {
+	ta := assert.New(t)
+
+	e := Entry{Date: time.Date(2025, 3, 1, 21, 0, 0, 0, tz), Amount: 1200, Category: "食費", Memo: "ランチ", User: "alice"}
+	ta.NoError(e.Validate())
+	ta.Equal([]interface{}{"2025-03-01", 1200, "食費", "ランチ", "alice"}, e.Row())
+
+	ta.Error(Entry{Category: "食費"}.Validate())
+	ta.Error(Entry{Amount: 1200, Category: " "}.Validate())
+}
+
+func TestSummarize(t *testing.T) {
+	ta := assert.New(t)
+
+	entries := []Entry{
+		{Date: time.Date(2025, 1, 31, 0, 0, 0, 0, tz), Amount: 9999, Category: "食費"},
+		{Date: time.Date(2025, 2, 1, 0, 0, 0, 0, tz), Amount: 3000, Category: "食費"},
+		{Date: time.Date(2025, 2, 28, 0, 0, 0, 0, tz), Amount: 500, Category: "日用品"},
+		{Date: time.Date(2025, 3, 1, 0, 0, 0, 0, tz), Amount: 2000, Category: "食費"},
+		{Date: time.Date(2025, 3, 15, 0, 0, 0, 0, tz), Amount: 1500, Category: "食費"},
+		{Date: time.Date(2025, 3, 31, 0, 0, 0, 0, tz), Amount: 800, Category: "外食"},
+		{Date: time.Date(2025, 4, 1, 0, 0, 0, 0, tz), Amount: 9999, Category: "食費"},
+	}
+
+	s := Summarize(entries, time.Date(2025, 3, 20, 0, 0, 0, 0, tz))
+
+	ta.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, tz), s.Month)
+	ta.Equal([]CategoryTotal{
+		{Category: "食費", Total: 3500, Previous: 3000},
+		{Category: "外食", Total: 800},
+		{Category: "日用品", Previous: 500},
+	}, s.Categories)
+	ta.Equal(4300, s.Total())
+	ta.Equal(3500, s.Previous())
+	ta.Equal(500, s.Categories[0].Delta())
+	ta.Equal(-500, s.Categories[2].Delta())
+}
+
+func TestFormatYen(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.Equal("¥0", FormatYen(0))
+	ta.Equal("¥999", FormatYen(999))
+	ta.Equal("¥1,000", FormatYen(1000))
+	ta.Equal("¥1,234,567", FormatYen(1234567))
+	ta.Equal("-¥1,200", FormatYen(-1200))
+
+	ta.Equal("+¥1,200", FormatDelta(1200))
+	ta.Equal("-¥300", FormatDelta(-300))
+	ta.Equal("±¥0", FormatDelta(0))
+}
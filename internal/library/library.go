@@ -0,0 +1,211 @@
+// Package library は図書館で借りた本の返却期限を提供する
+// Discord のコマンドで登録・返却し、remind の投稿で返却期限が近い本と過ぎた本を知らせる
+package library
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+)
+
+const partitionKey = "library"
+
+// ソートキーを登録した日時にして、返却期限が同じ本は登録した順に並べる
+const sortKeyFormat = "20060102T150405.000000000"
+
+const dateFormat = "2006-01-02"
+
+// 借りている本
+type Book struct {
+	Key        string // ソートキー
+	Title      string
+	ISBN       string // 登録時に指定した場合のみ
+	Due        time.Time
+	BorrowedBy string
+}
+
+// 返却期限を表す、e.g. 三体 (3/15 まで)
+func (b Book) String() string {
+	return fmt.Sprintf("%s (%s まで)", b.Title, b.Due.Format("1/2"))
+}
+
+// 借りている本を DynamoDB に保存する
+// テーブルのキーは pk (パーティションキー) と sk (ソートキー、登録した日時) とし、返却した本は削除する
+// キーの形式が同じため、remind の対応状況を記録するテーブルと共有できる
+type Store struct {
+	client *dynamodb.Client
+	table  string
+}
+
+func NewStore(client *dynamodb.Client, table string) *Store {
+	return &Store{client: client, table: table}
+}
+
+// 本を登録する、同じタイトルの本を返却せずに借りている場合は登録せずに false を返す
+func (s *Store) Add(ctx context.Context, b Book, now time.Time) (bool, error) {
+	b.Title = strings.TrimSpace(b.Title)
+	if b.Title == "" {
+		return false, fmt.Errorf("book title is blank")
+	}
+	books, err := s.List(ctx, b.Due.Location())
+	if err != nil {
+		return false, err
+	}
+	if _, ok := Find(books, b.Title); ok {
+		return false, nil
+	}
+
+	item := dynamodb.Item{
+		"pk":          dynamodb.S(partitionKey),
+		"sk":          dynamodb.S(now.UTC().Format(sortKeyFormat)),
+		"title":       dynamodb.S(b.Title),
+		"isbn":        dynamodb.S(b.ISBN),
+		"due":         dynamodb.S(b.Due.Format(dateFormat)),
+		"borrowed_by": dynamodb.S(b.BorrowedBy),
+	}
+	if err := s.client.PutItem(ctx, s.table, item, "attribute_not_exists(pk)", nil); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// 借りている本を返却期限の近い順に返す
+func (s *Store) List(ctx context.Context, loc *time.Location) ([]Book, error) {
+	items, err := s.client.Query(ctx, s.table, "pk = :pk", dynamodb.Item{":pk": dynamodb.S(partitionKey)})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseBooks(items, loc), nil
+}
+
+// 本を返却済みにする
+func (s *Store) Return(ctx context.Context, b Book) error {
+	return s.client.DeleteItem(ctx, s.table, dynamodb.Item{
+		"pk": dynamodb.S(partitionKey),
+		"sk": dynamodb.S(b.Key),
+	})
+}
+
+func parseBooks(items []dynamodb.Item, loc *time.Location) []Book {
+	var books []Book
+	for _, v := range items {
+		// 返却期限を読み込めない本も返却できるように一覧に含める
+		due, _ := time.ParseInLocation(dateFormat, v.Str("due"), loc)
+		books = append(books, Book{
+			Key:        v.Str("sk"),
+			Title:      v.Str("title"),
+			ISBN:       v.Str("isbn"),
+			Due:        due,
+			BorrowedBy: v.Str("borrowed_by"),
+		})
+	}
+	slices.SortStableFunc(books, func(a, b Book) int {
+		return cmp.Compare(a.Due.Unix(), b.Due.Unix())
+	})
+
+	return books
+}
+
+// 一覧の番号 (1 始まり) もしくはタイトルで本を探す、タイトルは大文字と小文字を区別しない
+func Find(books []Book, target string) (Book, bool) {
+	target = strings.TrimSpace(target)
+	if n, err := strconv.Atoi(target); err == nil {
+		if n < 1 || n > len(books) {
+			return Book{}, false
+		}
+		return books[n-1], true
+	}
+	for _, b := range books {
+		if strings.EqualFold(b.Title, target) {
+			return b, true
+		}
+	}
+
+	return Book{}, false
+}
+
+// 番号付きの一覧を返す、e.g. "1. 三体 (3/15 まで)\n2. 火車 (3/20 まで)"
+func Format(books []Book) string {
+	lines := make([]string, 0, len(books))
+	for i, b := range books {
+		lines = append(lines, fmt.Sprintf("%d. %s", i+1, b))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// 返却期限が today から days 日以内の本を返す、期限を過ぎたものも含める
+func DueWithin(books []Book, today time.Time, days int) []Book {
+	limit := today.AddDate(0, 0, days)
+	var due []Book
+	for _, b := range books {
+		if !b.Due.After(limit) {
+			due = append(due, b)
+		}
+	}
+
+	return due
+}
+
+var loanPeriodPattern = regexp.MustCompile(`^(\d+)\s*(d|w|日|週|週間)$`)
+
+// 返却期限を読み込む、e.g. 2025-03-15, 2025/3/15, 3/15, 14d, 2週間
+// 年を省略した場合は today 以降で最も近い日とし、日数もしくは週数の場合は today からの貸出期間とする
+func ParseDue(s string, today time.Time) (time.Time, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if m := loanPeriodPattern.FindStringSubmatch(s); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n <= 0 {
+			return time.Time{}, fmt.Errorf("invalid due date: %s", s)
+		}
+		if m[2] != "d" && m[2] != "日" {
+			n *= 7
+		}
+		return today.AddDate(0, 0, n), nil
+	}
+
+	s = strings.ReplaceAll(s, "-", "/")
+	for _, layout := range []string{"2006/1/2", "1/2"} {
+		t, err := time.ParseInLocation(layout, s, today.Location())
+		if err != nil {
+			continue
+		}
+		if layout == "1/2" {
+			t = time.Date(today.Year(), t.Month(), t.Day(), 0, 0, 0, 0, today.Location())
+			if t.Before(today) {
+				t = t.AddDate(1, 0, 0)
+			}
+		}
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid due date: %s", s)
+}
+
+// ISBN のハイフンと空白を除き、10 桁もしくは 13 桁であることを確認する
+func NormalizeISBN(s string) (string, error) {
+	n := strings.ToUpper(strings.NewReplacer("-", "", " ", "", "　", "").Replace(strings.TrimSpace(s)))
+	if len(n) != 10 && len(n) != 13 {
+		return "", fmt.Errorf("invalid ISBN: %s", s)
+	}
+	for i, r := range n {
+		// ISBN-10 のチェックディジットは X の場合がある
+		if r == 'X' && len(n) == 10 && i == 9 {
+			continue
+		}
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("invalid ISBN: %s", s)
+		}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,211 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+func date(month time.Month, day int) time.Time {
+	return time.Date(2025, month, day, 0, 0, 0, 0, jst)
+}
+
+func TestParseBooks(t *testing.T) {
+	ta := assert.New(t)
+
+	books := parseBooks([]dynamodb.Item{
+		{"pk": dynamodb.S("library"), "sk": dynamodb.S("20250301T090000.000000000"), "title": dynamodb.S("火車"), "due": dynamodb.S("2025-03-20"), "borrowed_by": dynamodb.S("alice")},
+		{"pk": dynamodb.S("library"), "sk": dynamodb.S("20250302T090000.000000000"), "title": dynamodb.S("三体"), "isbn": dynamodb.S("9784152098702"), "due": dynamodb.S("2025-03-15")},
+	}, jst)
+
+	ta.Equal([]Book{
+		{Key: "20250302T090000.000000000", Title: "三体", ISBN: "9784152098702", Due: date(3, 15)},
+		{Key: "20250301T090000.000000000", Title: "火車", Due: date(3, 20), BorrowedBy: "alice"},
+	}, books)
+	ta.Equal("1. 三体 (3/15 まで)\n2. 火車 (3/20 まで)", Format(books))
+}
+
+func TestFind(t *testing.T) {
+	books := []Book{{Key: "1", Title: "三体"}, {Key: "2", Title: "Dune"}}
+
+	tests := []struct {
+		name     string
+		target   string
+		expected Book
+		found    bool
+	}{
+		{name: "正常系/番号で指定した場合", target: "2", expected: books[1], found: true},
+		{name: "正常系/タイトルで指定した場合", target: " 三体 ", expected: books[0], found: true},
+		{name: "正常系/大文字と小文字を区別しない場合", target: "dune", expected: books[1], found: true},
+		{name: "異常系/番号が範囲外の場合", target: "3"},
+		{name: "異常系/タイトルが一致しない場合", target: "火車"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			b, ok := Find(books, tt.target)
+			ta.Equal(tt.found, ok)
+			ta.Equal(tt.expected, b)
+		})
+	}
+}
+
+func TestParseDue(t *testing.T) {
+	today := date(12, 20)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{name: "正常系/年を含む場合", input: "2026-01-05", expected: time.Date(2026, 1, 5, 0, 0, 0, 0, jst)},
+		{name: "正常系/年を省略した場合は翌年とする", input: "1/5", expected: time.Date(2026, 1, 5, 0, 0, 0, 0, jst)},
+		{name: "正常系/貸出期間の日数", input: "14d", expected: time.Date(2026, 1, 3, 0, 0, 0, 0, jst)},
+		{name: "正常系/貸出期間の週数", input: "2週間", expected: time.Date(2026, 1, 3, 0, 0, 0, 0, jst)},
+		{name: "異常系/形式が不正な場合", input: "来週", wantErr: true},
+		{name: "異常系/日数が 0 の場合", input: "0d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			due, err := ParseDue(tt.input, today)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, due)
+		})
+	}
+}
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "正常系/ISBN-13", input: "978-4-15-209870-2", expected: "9784152098702"},
+		{name: "正常系/チェックディジットが X の ISBN-10", input: "4-06-263766-x", expected: "406263766X"},
+		{name: "異常系/桁数が不正な場合", input: "978415209870", wantErr: true},
+		{name: "異常系/数字以外を含む場合", input: "97841520987X2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			isbn, err := NormalizeISBN(tt.input)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, isbn)
+		})
+	}
+}
+
+func TestLookupTitle(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("isbn") {
+		case "9784152098702":
+			w.Write([]byte(`[{"summary": {"isbn": "9784152098702", "title": "三体", "volume": ""}}]`))
+		case "9784150120382":
+			w.Write([]byte(`[{"summary": {"title": "三体Ⅲ 死神永生", "volume": "上"}}]`))
+		case "0000000000":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.Write([]byte(`[null]`))
+		}
+	}))
+	defer srv.Close()
+	l := NewLookup(srv.Client(), srv.URL+"/v1/get")
+
+	t.Run("正常系/書名を返す", func(t *testing.T) {
+		ta := assert.New(t)
+
+		title, err := l.Title(context.Background(), "9784152098702")
+		ta.NoError(err)
+		ta.Equal("三体", title)
+
+		title, err = l.Title(context.Background(), "9784150120382")
+		ta.NoError(err)
+		ta.Equal("三体Ⅲ 死神永生 上", title)
+	})
+
+	t.Run("異常系/見つからない場合", func(t *testing.T) {
+		_, err := l.Title(context.Background(), "9780000000000")
+		assert.ErrorIs(t, err, ErrBookNotFound)
+	})
+
+	t.Run("異常系/API がエラーを返した場合", func(t *testing.T) {
+		_, err := l.Title(context.Background(), "0000000000")
+		assert.Error(t, err)
+	})
+}
+
+type fakeLister struct {
+	books []Book
+	err   error
+}
+
+func (l fakeLister) List(ctx context.Context, loc *time.Location) ([]Book, error) {
+	return l.books, l.err
+}
+
+func TestSource(t *testing.T) {
+	today := date(3, 15)
+	books := []Book{
+		{Title: "火車", Due: date(3, 12)},
+		{Title: "三体", Due: date(3, 15)},
+		{Title: "Dune", Due: date(3, 17)},
+		{Title: "砂の女", Due: date(3, 30)},
+	}
+
+	t.Run("正常系/返却期限が近い本と過ぎた本を返す", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		events, err := NewSource(fakeLister{books: books}, 2, today).Fetch(context.Background(), today)
+		tr.NoError(err)
+		tr.Len(events, 1)
+		ta.Equal("返却期限が近い本 (3 冊)", events[0].Name)
+		ta.Equal("⚠ 火車: 返却期限を 3 日過ぎています\n三体: 今日まで\nDune: あと 2 日", events[0].Description)
+		ta.Equal(1, events[0].Priority)
+		ta.Equal([]string{Tag}, events[0].Tags)
+	})
+
+	t.Run("正常系/当日以外は返さない", func(t *testing.T) {
+		events, err := NewSource(fakeLister{books: books}, 2, today).Fetch(context.Background(), today.AddDate(0, 0, 1))
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("正常系/返却期限が近い本がない場合は返さない", func(t *testing.T) {
+		events, err := NewSource(fakeLister{books: books[3:]}, 2, today).Fetch(context.Background(), today)
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("異常系/取得に失敗した場合", func(t *testing.T) {
+		_, err := NewSource(fakeLister{err: errors.New("throttled")}, 2, today).Fetch(context.Background(), today)
+		assert.Equal(t, Tag, event.FailedComponent(err))
+	})
+}
@@ -0,0 +1,72 @@
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var ErrBookNotFound = errors.New("book not found")
+
+// ISBN から書名を取得する
+// カーリルと版元ドットコムが運営する openBD の API を使う、API キーは不要
+type Lookup struct {
+	client  *http.Client
+	baseURL string // e.g. https://api.openbd.jp/v1/get
+}
+
+func NewLookup(client *http.Client, baseURL string) *Lookup {
+	return &Lookup{client: client, baseURL: baseURL}
+}
+
+type openBDBook struct {
+	Summary struct {
+		Title  string `json:"title"`
+		Volume string `json:"volume"`
+	} `json:"summary"`
+}
+
+func (l *Lookup) Title(ctx context.Context, isbn string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.baseURL+"?isbn="+url.QueryEscape(isbn), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("returned status %d", resp.StatusCode)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+
+	return parseTitle(b)
+}
+
+// 見つからない ISBN は null として返る
+func parseTitle(b []byte) (string, error) {
+	var books []*openBDBook
+	if err := json.Unmarshal(b, &books); err != nil {
+		return "", err
+	}
+	if len(books) == 0 || books[0] == nil || strings.TrimSpace(books[0].Summary.Title) == "" {
+		return "", ErrBookNotFound
+	}
+	s := books[0].Summary
+	title := strings.TrimSpace(s.Title)
+	if v := strings.TrimSpace(s.Volume); v != "" {
+		title += " " + v
+	}
+
+	return title, nil
+}
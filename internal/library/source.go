@@ -0,0 +1,68 @@
+package library
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// イベントに付けるタグ、朝のスケジュールのみで通知する場合はプロファイルの tags に指定する
+const Tag = "library"
+
+// 借りている本を取得する
+type Lister interface {
+	List(ctx context.Context, loc *time.Location) ([]Book, error)
+}
+
+// 返却期限が近い本と過ぎた本を、当日のイベントとしてまとめて返す取得元
+// 返却すると記録から削除されるため、今後のイベントには含めない
+type Source struct {
+	lister Lister
+	days   int
+	today  time.Time
+}
+
+func NewSource(l Lister, days int, today time.Time) *Source {
+	return &Source{lister: l, days: days, today: today}
+}
+
+func (s *Source) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	if !t.Equal(s.today) {
+		return nil, nil
+	}
+	books, err := s.lister.List(ctx, t.Location())
+	if err != nil {
+		return nil, event.NewSourceUnavailableError(Tag, err)
+	}
+	due := DueWithin(books, t, s.days)
+	if len(due) == 0 {
+		return nil, nil
+	}
+
+	e := event.Event{
+		Name:     fmt.Sprintf("返却期限が近い本 (%d 冊)", len(due)),
+		Interval: event.Onetime,
+		Emoji:    "📚",
+		Tags:     []string{Tag},
+	}
+	lines := make([]string, 0, len(due))
+	for _, b := range due {
+		days := event.DaysBetween(t, b.Due)
+		switch {
+		case days < 0:
+			lines = append(lines, fmt.Sprintf("⚠ %s: 返却期限を %d 日過ぎています", b.Title, -days))
+			// 延滞している本があれば先に表示する
+			e.Priority = 1
+		case days == 0:
+			lines = append(lines, fmt.Sprintf("%s: 今日まで", b.Title))
+		default:
+			lines = append(lines, fmt.Sprintf("%s: あと %d 日", b.Title, days))
+		}
+	}
+	e.Description = strings.Join(lines, "\n")
+
+	return []event.Event{e}, nil
+}
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+// 同じ実行環境で既に呼び出されたかどうか、コールドスタートの判定に使う
+var invoked atomic.Bool
+
+// 全てのログに Lambda の呼び出しの情報を付ける
+type lambdaHandler struct {
+	slog.Handler
+	attrs []slog.Attr
+}
+
+func (h lambdaHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.AddAttrs(h.attrs...)
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h lambdaHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return lambdaHandler{Handler: h.Handler.WithAttrs(attrs), attrs: h.attrs}
+}
+
+// グループの中に入らないように、呼び出しの情報を先に付けておく
+func (h lambdaHandler) WithGroup(name string) slog.Handler {
+	return h.Handler.WithAttrs(h.attrs).WithGroup(name)
+}
+
+// ctx が Lambda の呼び出しであれば、リクエスト ID、関数のバージョン、コールドスタートかどうかを
+// 全てのログに付けるロガーを返す、CloudWatch Logs Insights で 1 回の呼び出しのログをまとめて調べるために使う
+func WithLambdaContext(ctx context.Context, l *slog.Logger) *slog.Logger {
+	lc, ok := lambdacontext.FromContext(ctx)
+	if !ok {
+		return l
+	}
+
+	return slog.New(lambdaHandler{
+		Handler: l.Handler(),
+		attrs: []slog.Attr{
+			slog.String("aws_request_id", lc.AwsRequestID),
+			slog.String("function_version", lambdacontext.FunctionVersion),
+			slog.Bool("cold_start", !invoked.Swap(true)),
+		},
+	})
+}
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLambdaContext(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+	invoked.Store(false)
+
+	type output struct {
+		RequestID string `json:"aws_request_id"`
+		ColdStart *bool  `json:"cold_start"`
+		Group     struct {
+			Key string `json:"key"`
+		} `json:"group"`
+	}
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "req-1"})
+
+	// 最初の呼び出しはコールドスタートとして扱う
+	var buf bytes.Buffer
+	WithLambdaContext(ctx, New(&buf, Options{})).WithGroup("group").Info("hello", slog.String("key", "value"))
+	var first output
+	tr.NoError(json.Unmarshal(buf.Bytes(), &first))
+	ta.Equal("req-1", first.RequestID)
+	tr.NotNil(first.ColdStart)
+	ta.True(*first.ColdStart)
+	ta.Equal("value", first.Group.Key)
+
+	buf.Reset()
+	WithLambdaContext(ctx, New(&buf, Options{})).Info("hello")
+	var second output
+	tr.NoError(json.Unmarshal(buf.Bytes(), &second))
+	tr.NotNil(second.ColdStart)
+	ta.False(*second.ColdStart)
+
+	// Lambda の呼び出しでない場合は何も付けない
+	buf.Reset()
+	WithLambdaContext(context.Background(), New(&buf, Options{})).Info("hello")
+	var local output
+	tr.NoError(json.Unmarshal(buf.Bytes(), &local))
+	ta.Empty(local.RequestID)
+	ta.Nil(local.ColdStart)
+}
@@ -0,0 +1,85 @@
+// Package logging は各関数で共通の slog の設定を提供する
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type Options struct {
+	Level      slog.Level // 出力する最小のレベル
+	TrimSource bool       // 出力元のファイルをフルパスではなくファイル名のみにする
+	Debug      bool       // 取得元から受け取った生のデータも出力する、Level は debug になる
+}
+
+// 環境変数 LOG_LEVEL (debug, info, warn, error)、LOG_TRIM_SOURCE と DEBUG から設定を読み込む
+// 不正な値の場合は既定値を使う
+func OptionsFromEnv() Options {
+	opts := Options{Level: slog.LevelInfo}
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(strings.ToUpper(v))); err == nil {
+			opts.Level = level
+		}
+	}
+	if v, err := strconv.ParseBool(os.Getenv("LOG_TRIM_SOURCE")); err == nil {
+		opts.TrimSource = v
+	}
+	if v, err := strconv.ParseBool(os.Getenv("DEBUG")); err == nil && v {
+		opts.Debug = true
+		opts.Level = slog.LevelDebug
+	}
+
+	return opts
+}
+
+// 最後に作成したロガーで DEBUG が有効かどうか
+var debug bool
+
+func New(w io.Writer, opts Options) *slog.Logger {
+	handlerOpts := slog.HandlerOptions{
+		AddSource: true,
+		Level:     opts.Level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) == 0 {
+				switch attr.Key {
+				case slog.MessageKey:
+					return slog.Attr{Key: "message", Value: attr.Value}
+				case slog.SourceKey:
+					if src, ok := attr.Value.Any().(*slog.Source); ok && opts.TrimSource {
+						src.File = filepath.Base(src.File)
+					}
+					return attr
+				case slog.TimeKey, slog.LevelKey:
+					return attr
+				}
+			}
+			// 出力する全ての値から秘匿情報を伏せる
+			return redactAttr(attr)
+		},
+	}
+
+	debug = opts.Debug
+
+	return slog.New(slog.NewJSONHandler(w, &handlerOpts))
+}
+
+// 環境変数の設定で標準出力に JSON 形式で出力するロガーを作成する
+func NewFromEnv() *slog.Logger {
+	return New(os.Stdout, OptionsFromEnv())
+}
+
+// DEBUG が有効な場合のみ、取得元から受け取った生のデータを秘匿情報を伏せた上で出力する
+// 列の対応付けの誤りなどを、コードを変更せずに調べるために使う
+func DebugPayload(ctx context.Context, msg string, payload any) {
+	if !debug {
+		return
+	}
+
+	slog.DebugContext(ctx, msg, slog.Any("payload", Redact(payload)))
+}
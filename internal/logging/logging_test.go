@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         Options
+		log          func(l *slog.Logger)
+		expectOutput bool
+		expectedFile string
+	}{
+		{
+			name:         "正常系/メッセージを message キーで出力する場合",
+			opts:         Options{Level: slog.LevelInfo, TrimSource: true},
+			log:          func(l *slog.Logger) { l.Info("hello") },
+			expectOutput: true,
+			expectedFile: "logging_test.go",
+		},
+		{
+			name:         "正常系/指定したレベル未満のログの場合",
+			opts:         Options{Level: slog.LevelWarn},
+			log:          func(l *slog.Logger) { l.Info("hello") },
+			expectOutput: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			tr := require.New(t)
+
+			var buf bytes.Buffer
+			tt.log(New(&buf, tt.opts))
+			if !tt.expectOutput {
+				ta.Empty(buf.String())
+				return
+			}
+
+			var out struct {
+				Message string `json:"message"`
+				Source  struct {
+					File string `json:"file"`
+				} `json:"source"`
+			}
+			tr.NoError(json.Unmarshal(buf.Bytes(), &out))
+			ta.Equal("hello", out.Message)
+			ta.Equal(tt.expectedFile, out.Source.File)
+		})
+	}
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	ta := assert.New(t)
+
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("LOG_TRIM_SOURCE", "true")
+	ta.Equal(Options{Level: slog.LevelDebug, TrimSource: true}, OptionsFromEnv())
+
+	t.Setenv("LOG_LEVEL", "verbose")
+	t.Setenv("LOG_TRIM_SOURCE", "")
+	ta.Equal(Options{Level: slog.LevelInfo}, OptionsFromEnv())
+
+	// DEBUG が有効な場合は LOG_LEVEL より優先する
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("DEBUG", "true")
+	ta.Equal(Options{Level: slog.LevelDebug, Debug: true}, OptionsFromEnv())
+}
+
+func TestDebugPayload(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+	t.Cleanup(func() { debug = false })
+
+	var buf bytes.Buffer
+	slog.SetDefault(New(&buf, Options{Level: slog.LevelDebug}))
+	DebugPayload(context.Background(), "fetched values", map[string]string{"token": "abc"})
+	ta.Empty(buf.String())
+
+	slog.SetDefault(New(&buf, Options{Level: slog.LevelDebug, Debug: true}))
+	DebugPayload(context.Background(), "fetched values", map[string]string{"token": "abc"})
+	var out struct {
+		Payload map[string]string `json:"payload"`
+	}
+	tr.NoError(json.Unmarshal(buf.Bytes(), &out))
+	ta.Equal(map[string]string{"token": redacted}, out.Payload)
+}
@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// キーの名前にこれらを含む値は伏せる
+var sensitiveKeys = []string{
+	"token",
+	"secret",
+	"password",
+	"credential",
+	"private_key",
+	"signature",
+	"authorization",
+	"api_key",
+	"apikey",
+}
+
+var (
+	// Discord の Webhook の URL に含まれるトークン
+	webhookTokenPattern = regexp.MustCompile(`(discord(?:app)?\.com/api/webhooks/\d+/)[\w-]+`)
+	// Google Chat の Webhook の URL などのクエリパラメータに含まれる鍵
+	queryKeyPattern = regexp.MustCompile(`([?&](?:key|token)=)[^&\s"]+`)
+	// JSON の文字列に含まれる秘匿情報、e.g. リクエストのボディ
+	jsonSecretPattern = regexp.MustCompile(`(?i)("[^"]*(?:token|secret|password|credential|private_key|signature|authorization|api_?key)[^"]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	// サービスアカウントの秘密鍵など
+	privateKeyPattern = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)
+)
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range sensitiveKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ログに出力する値から、トークンや秘密鍵などの秘匿情報を伏せた値を返す
+// 構造体などは JSON に変換した上で、キーと文字列の値を調べる
+func Redact(v any) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return redacted
+	}
+	var decoded any
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return redacted
+	}
+
+	return redactValue(decoded)
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			if isSensitiveKey(k) {
+				v[k] = redacted
+				continue
+			}
+			v[k] = redactValue(val)
+		}
+		return v
+	case []any:
+		for i, val := range v {
+			v[i] = redactValue(val)
+		}
+		return v
+	case string:
+		return RedactString(v)
+	default:
+		return v
+	}
+}
+
+// 文字列に含まれる Webhook のトークンや秘密鍵を伏せる
+func RedactString(s string) string {
+	s = webhookTokenPattern.ReplaceAllString(s, "${1}"+redacted)
+	s = queryKeyPattern.ReplaceAllString(s, "${1}"+redacted)
+	s = privateKeyPattern.ReplaceAllString(s, redacted)
+	s = jsonSecretPattern.ReplaceAllString(s, `${1}"`+redacted+`"`)
+
+	return s
+}
+
+// ログの属性から秘匿情報を伏せる、エラーはメッセージのみを調べる
+func redactAttr(attr slog.Attr) slog.Attr {
+	if isSensitiveKey(attr.Key) {
+		return slog.String(attr.Key, redacted)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, RedactString(attr.Value.String()))
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			return slog.String(attr.Key, RedactString(err.Error()))
+		}
+		return slog.Any(attr.Key, Redact(attr.Value.Any()))
+	default:
+		return attr
+	}
+}
@@ -0,0 +1,102 @@
+package meal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/discord"
+)
+
+// ボタンの custom_id は "meal:<shuffle|veto|accept>:<yyyymmdd>[:<何日目か>]" の形式とする
+const customIDPrefix = "meal:"
+
+const (
+	ActionShuffle = "shuffle" // すべての料理を選び直す
+	ActionVeto    = "veto"    // 指定した日の料理を入れ替える
+	ActionAccept  = "accept"  // 献立を採用する
+)
+
+// 献立のボタンが押された場合は true を返す
+func IsCustomID(customID string) bool {
+	return strings.HasPrefix(customID, customIDPrefix)
+}
+
+func CustomID(action string, start time.Time, day int) string {
+	id := customIDPrefix + action + ":" + start.Format(dateFormat)
+	if action == ActionVeto {
+		id += ":" + strconv.Itoa(day)
+	}
+
+	return id
+}
+
+// 押されたボタンの操作と献立の初日、入れ替える日を返す
+func ParseCustomID(customID string, loc *time.Location) (string, time.Time, int, error) {
+	rest, ok := strings.CutPrefix(customID, customIDPrefix)
+	parts := strings.Split(rest, ":")
+	if !ok || len(parts) < 2 {
+		return "", time.Time{}, 0, fmt.Errorf("invalid custom id: %s", customID)
+	}
+	start, err := time.ParseInLocation(dateFormat, parts[1], loc)
+	if err != nil {
+		return "", time.Time{}, 0, fmt.Errorf("invalid custom id: %s", customID)
+	}
+	switch parts[0] {
+	case ActionShuffle, ActionAccept:
+		if len(parts) != 2 {
+			return "", time.Time{}, 0, fmt.Errorf("invalid custom id: %s", customID)
+		}
+		return parts[0], start, 0, nil
+	case ActionVeto:
+		if len(parts) != 3 {
+			return "", time.Time{}, 0, fmt.Errorf("invalid custom id: %s", customID)
+		}
+		day, err := strconv.Atoi(parts[2])
+		if err != nil || day < 0 || day >= Days {
+			return "", time.Time{}, 0, fmt.Errorf("invalid custom id: %s", customID)
+		}
+		return parts[0], start, day, nil
+	default:
+		return "", time.Time{}, 0, fmt.Errorf("invalid custom id: %s", customID)
+	}
+}
+
+// 献立の本文、採用済みの場合は見出しを変える
+func (p Plan) Content() string {
+	title := fmt.Sprintf("🍽 %s からの献立の提案", p.DateLabel(0))
+	if p.Accepted {
+		title = fmt.Sprintf("🍽 %s からの献立", p.DateLabel(0))
+	}
+
+	return title + "\n" + p.Format()
+}
+
+// 日ごとの入れ替えのボタンと、選び直しと採用のボタンを並べる
+// Action Row には 5 個までしかボタンを置けないため、日ごとのボタンは 2 行に分ける
+// 採用済みの場合はボタンを無効にする
+func (p Plan) Components() []discord.Component {
+	if p.Accepted {
+		return []discord.Component{discord.ActionRow(discord.DisabledButton(discord.SuccessButton, "採用済み", CustomID(ActionAccept, p.Start, 0)))}
+	}
+
+	var rows []discord.Component
+	var buttons []discord.Component
+	for i := range p.Dinners {
+		buttons = append(buttons, discord.Button(discord.SecondaryButton, "✖ "+p.DateLabel(i), CustomID(ActionVeto, p.Start, i)))
+		if len(buttons) == 4 {
+			rows = append(rows, discord.ActionRow(buttons...))
+			buttons = nil
+		}
+	}
+	if len(buttons) > 0 {
+		rows = append(rows, discord.ActionRow(buttons...))
+	}
+	rows = append(rows, discord.ActionRow(
+		discord.Button(discord.PrimaryButton, "🔀 選び直す", CustomID(ActionShuffle, p.Start, 0)),
+		discord.Button(discord.SuccessButton, "✅ 採用する", CustomID(ActionAccept, p.Start, 0)),
+	))
+
+	return rows
+}
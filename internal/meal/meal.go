@@ -0,0 +1,163 @@
+// Package meal はスプレッドシートに記録したレシピから 1 週間の夕食の献立を提案する
+// remind が日曜日に献立を投稿し、hello がボタンの操作で入れ替えと採用を行う
+package meal
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+)
+
+// レシピを記録するシートの列、1 行目はヘッダーとする
+// A: 料理, B: 材料 (カンマもしくは読点区切り)
+const RecipeColumns = "A:B"
+
+// 採用した献立を書き込むシートの列
+// A: 日付 (2006-01-02), B: 料理
+const PlanColumns = "A:B"
+
+// 1 週間の日数
+const Days = 7
+
+var weekdays = [...]string{"日", "月", "火", "水", "木", "金", "土"}
+
+type Recipe struct {
+	Name        string
+	Ingredients []string
+}
+
+// シートの行からレシピを読み込む、1 行目はヘッダーとして読み飛ばす
+// 料理が空の行は読み飛ばす
+func ParseRecipes(rows [][]interface{}) []Recipe {
+	var recipes []Recipe
+	for i := 1; i < len(rows); i++ {
+		r := rows[i]
+		name := cell(r, 0)
+		if name == "" {
+			continue
+		}
+		recipes = append(recipes, Recipe{Name: name, Ingredients: splitIngredients(cell(r, 1))})
+	}
+
+	return recipes
+}
+
+func splitIngredients(s string) []string {
+	var items []string
+	for _, v := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '、' || r == '\n' }) {
+		if v = strings.TrimSpace(v); v != "" {
+			items = append(items, v)
+		}
+	}
+
+	return items
+}
+
+func cell(r []interface{}, i int) string {
+	if i >= len(r) {
+		return ""
+	}
+
+	return strings.TrimSpace(fmt.Sprint(r[i]))
+}
+
+// 1 週間の夕食の献立
+type Plan struct {
+	Start    time.Time // 献立の初日
+	Dinners  []string  // 初日から順に料理の名前
+	Accepted bool
+}
+
+// 初日から Days 日分の献立を提案する
+// 同じ料理は続けて使わないように並べ、レシピが Days 件に満たない場合は繰り返す
+func Propose(recipes []Recipe, start time.Time, rng *rand.Rand) Plan {
+	p := Plan{Start: start}
+	if len(recipes) == 0 {
+		return p
+	}
+	order := rng.Perm(len(recipes))
+	for i := range Days {
+		p.Dinners = append(p.Dinners, recipes[order[i%len(order)]].Name)
+	}
+
+	return p
+}
+
+// i 日目の料理を献立にない別の料理に入れ替える、候補がない場合は false を返す
+func (p *Plan) Replace(i int, recipes []Recipe, rng *rand.Rand) bool {
+	if i < 0 || i >= len(p.Dinners) {
+		return false
+	}
+	var candidates []string
+	for _, r := range recipes {
+		if !slices.Contains(p.Dinners, r.Name) {
+			candidates = append(candidates, r.Name)
+		}
+	}
+	// すべてのレシピを使っている場合は、同じ料理でなければよい
+	if len(candidates) == 0 {
+		for _, r := range recipes {
+			if r.Name != p.Dinners[i] {
+				candidates = append(candidates, r.Name)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return false
+	}
+	p.Dinners[i] = candidates[rng.IntN(len(candidates))]
+
+	return true
+}
+
+// i 日目の日付
+func (p Plan) Date(i int) time.Time {
+	return p.Start.AddDate(0, 0, i)
+}
+
+// 表示用の日付、e.g. 3/3(月)
+func (p Plan) DateLabel(i int) string {
+	d := p.Date(i)
+
+	return fmt.Sprintf("%s(%s)", d.Format("1/2"), weekdays[d.Weekday()])
+}
+
+// 献立の一覧、e.g. "3/3(月) カレー\n3/4(火) 焼き魚"
+func (p Plan) Format() string {
+	lines := make([]string, 0, len(p.Dinners))
+	for i, d := range p.Dinners {
+		lines = append(lines, fmt.Sprintf("%s %s", p.DateLabel(i), d))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// 献立を書き込むシートの行
+func (p Plan) Rows() [][]interface{} {
+	rows := make([][]interface{}, 0, len(p.Dinners))
+	for i, d := range p.Dinners {
+		rows = append(rows, []interface{}{p.Date(i).Format("2006-01-02"), d})
+	}
+
+	return rows
+}
+
+// 献立の料理に必要な材料を重複なく返す、レシピにない料理は無視する
+func (p Plan) Ingredients(recipes []Recipe) []string {
+	var items []string
+	for _, d := range p.Dinners {
+		i := slices.IndexFunc(recipes, func(r Recipe) bool { return r.Name == d })
+		if i < 0 {
+			continue
+		}
+		for _, v := range recipes[i].Ingredients {
+			if !slices.ContainsFunc(items, func(s string) bool { return strings.EqualFold(s, v) }) {
+				items = append(items, v)
+			}
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,181 @@
+package meal
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+// 2025-03-03 は月曜日
+var monday = time.Date(2025, 3, 3, 0, 0, 0, 0, jst)
+
+func newRand() *rand.Rand {
+	return rand.New(rand.NewPCG(1, 2))
+}
+
+func names(recipes []Recipe) []string {
+	var s []string
+	for _, r := range recipes {
+		s = append(s, r.Name)
+	}
+
+	return s
+}
+
+func TestParseRecipes(t *testing.T) {
+	ta := assert.New(t)
+
+	recipes := ParseRecipes([][]interface{}{
+		{"料理", "材料"},
+		{"カレー", "じゃがいも、にんじん, 玉ねぎ,カレールー"},
+		{},
+		{"焼き魚"},
+	})
+	ta.Equal([]Recipe{
+		{Name: "カレー", Ingredients: []string{"じゃがいも", "にんじん", "玉ねぎ", "カレールー"}},
+		{Name: "焼き魚"},
+	}, recipes)
+}
+
+func TestPropose(t *testing.T) {
+	t.Run("正常系/同じ料理を使わずに 7 日分を提案する", func(t *testing.T) {
+		ta := assert.New(t)
+
+		recipes := []Recipe{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}, {Name: "f"}, {Name: "g"}, {Name: "h"}}
+		p := Propose(recipes, monday, newRand())
+		ta.Equal(monday, p.Start)
+		ta.Len(p.Dinners, Days)
+		ta.Subset(names(recipes), p.Dinners)
+		seen := map[string]bool{}
+		for _, d := range p.Dinners {
+			ta.False(seen[d], d)
+			seen[d] = true
+		}
+	})
+
+	t.Run("正常系/レシピが足りない場合は繰り返す", func(t *testing.T) {
+		ta := assert.New(t)
+
+		p := Propose([]Recipe{{Name: "a"}, {Name: "b"}, {Name: "c"}}, monday, newRand())
+		ta.Len(p.Dinners, Days)
+		ta.Equal(p.Dinners[0], p.Dinners[3])
+		ta.NotEqual(p.Dinners[0], p.Dinners[1])
+	})
+
+	t.Run("正常系/同じ乱数なら同じ献立になる", func(t *testing.T) {
+		recipes := []Recipe{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}, {Name: "f"}, {Name: "g"}, {Name: "h"}}
+		assert.Equal(t, Propose(recipes, monday, newRand()), Propose(recipes, monday, newRand()))
+	})
+}
+
+func TestReplace(t *testing.T) {
+	t.Run("正常系/献立にない料理に入れ替える", func(t *testing.T) {
+		ta := assert.New(t)
+
+		p := Plan{Start: monday, Dinners: []string{"a", "b", "c", "d", "e", "f", "g"}}
+		ta.True(p.Replace(2, []Recipe{{Name: "a"}, {Name: "c"}, {Name: "x"}}, newRand()))
+		ta.Equal([]string{"a", "b", "x", "d", "e", "f", "g"}, p.Dinners)
+	})
+
+	t.Run("正常系/すべて使っている場合は別の料理に入れ替える", func(t *testing.T) {
+		ta := assert.New(t)
+
+		p := Plan{Start: monday, Dinners: []string{"a", "b", "a", "b", "a", "b", "a"}}
+		ta.True(p.Replace(0, []Recipe{{Name: "a"}, {Name: "b"}}, newRand()))
+		ta.Equal("b", p.Dinners[0])
+	})
+
+	t.Run("異常系/入れ替えられる料理がない場合", func(t *testing.T) {
+		ta := assert.New(t)
+
+		p := Plan{Start: monday, Dinners: []string{"a", "a", "a", "a", "a", "a", "a"}}
+		ta.False(p.Replace(0, []Recipe{{Name: "a"}}, newRand()))
+		ta.False(p.Replace(Days, []Recipe{{Name: "b"}}, newRand()))
+	})
+}
+
+func TestPlan(t *testing.T) {
+	ta := assert.New(t)
+
+	p := Plan{Start: monday, Dinners: []string{"カレー", "焼き魚", "肉じゃが"}}
+	ta.Equal("3/3(月) カレー\n3/4(火) 焼き魚\n3/5(水) 肉じゃが", p.Format())
+	ta.Equal([][]interface{}{{"2025-03-03", "カレー"}, {"2025-03-04", "焼き魚"}, {"2025-03-05", "肉じゃが"}}, p.Rows())
+	ta.Equal([]string{"じゃがいも", "にんじん", "カレールー", "牛肉"}, p.Ingredients([]Recipe{
+		{Name: "カレー", Ingredients: []string{"じゃがいも", "にんじん", "カレールー"}},
+		{Name: "肉じゃが", Ingredients: []string{"じゃがいも", "牛肉", "にんじん"}},
+	}))
+}
+
+func TestCustomID(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		action  string
+		day     int
+		wantErr bool
+	}{
+		{name: "正常系/選び直す", input: CustomID(ActionShuffle, monday, 0), action: ActionShuffle},
+		{name: "正常系/入れ替える", input: CustomID(ActionVeto, monday, 6), action: ActionVeto, day: 6},
+		{name: "正常系/採用する", input: "meal:accept:20250303", action: ActionAccept},
+		{name: "異常系/日付が不正", input: "meal:accept:2025-03-03", wantErr: true},
+		{name: "異常系/日が範囲外", input: "meal:veto:20250303:7", wantErr: true},
+		{name: "異常系/操作が不正", input: "meal:skip:20250303", wantErr: true},
+		{name: "異常系/献立のボタンでない", input: "ack:done:20250303:key", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			action, start, day, err := ParseCustomID(tt.input, jst)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.action, action)
+			ta.Equal(monday, start)
+			ta.Equal(tt.day, day)
+		})
+	}
+}
+
+func TestComponents(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	p := Plan{Start: monday, Dinners: []string{"a", "b", "c", "d", "e", "f", "g"}}
+	rows := p.Components()
+	tr.Len(rows, 3)
+	ta.Len(rows[0].Components, 4)
+	ta.Len(rows[1].Components, 3)
+	ta.Equal("✖ 3/9(日)", rows[1].Components[2].Label)
+	ta.Equal("meal:veto:20250303:6", rows[1].Components[2].CustomID)
+	ta.Equal("meal:accept:20250303", rows[2].Components[1].CustomID)
+	ta.Contains(p.Content(), "献立の提案")
+
+	p.Accepted = true
+	rows = p.Components()
+	tr.Len(rows, 1)
+	ta.True(rows[0].Components[0].Disabled)
+	ta.NotContains(p.Content(), "提案")
+}
+
+func TestParsePlan(t *testing.T) {
+	ta := assert.New(t)
+
+	p := parsePlan(dynamodb.Item{
+		"pk":          dynamodb.S("meal"),
+		"sk":          dynamodb.S("20250303"),
+		"dinners":     dynamodb.S("カレー\n焼き魚"),
+		"plan_status": dynamodb.S("accepted"),
+	}, monday)
+	ta.Equal(Plan{Start: monday, Dinners: []string{"カレー", "焼き魚"}, Accepted: true}, p)
+	ta.Equal(dynamodb.S("カレー\n焼き魚"), planItem(p, statusAccepted)["dinners"])
+}
@@ -0,0 +1,91 @@
+package meal
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+)
+
+const partitionKey = "meal"
+
+// ボタンを押せる期間を過ぎた献立は TTL で削除する
+const retention = 14 * 24 * time.Hour
+
+const dateFormat = "20060102"
+
+const (
+	statusProposed = "proposed"
+	statusAccepted = "accepted"
+)
+
+// 提案した献立を DynamoDB に保存する
+// テーブルのキーは pk (パーティションキー) と sk (ソートキー、献立の初日) とし、ACK_TABLE_NAME と同じテーブルでもよい
+type Store struct {
+	client *dynamodb.Client
+	table  string
+}
+
+func NewStore(client *dynamodb.Client, table string) *Store {
+	return &Store{client: client, table: table}
+}
+
+func planItem(p Plan, status string) dynamodb.Item {
+	return dynamodb.Item{
+		"pk":          dynamodb.S(partitionKey),
+		"sk":          dynamodb.S(p.Start.Format(dateFormat)),
+		"dinners":     dynamodb.S(strings.Join(p.Dinners, "\n")),
+		"plan_status": dynamodb.S(status),
+		"expires_at":  dynamodb.N(p.Start.Add(retention).Unix()),
+	}
+}
+
+// 提案した献立を保存する、採用済みの献立は上書きせずに false を返す
+func (s *Store) Put(ctx context.Context, p Plan) (bool, error) {
+	return s.put(ctx, p, statusProposed)
+}
+
+// 献立を採用する、採用済みの場合は false を返す
+func (s *Store) Accept(ctx context.Context, p Plan) (bool, error) {
+	return s.put(ctx, p, statusAccepted)
+}
+
+func (s *Store) put(ctx context.Context, p Plan, status string) (bool, error) {
+	err := s.client.PutItem(ctx, s.table, planItem(p, status), "attribute_not_exists(pk) OR plan_status <> :accepted", dynamodb.Item{
+		":accepted": dynamodb.S(statusAccepted),
+	})
+	if err != nil {
+		if dynamodb.IsConditionalCheckFailed(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// 献立を取得する、存在しない場合は false を返す
+func (s *Store) Get(ctx context.Context, start time.Time) (Plan, bool, error) {
+	item, err := s.client.GetItem(ctx, s.table, dynamodb.Item{
+		"pk": dynamodb.S(partitionKey),
+		"sk": dynamodb.S(start.Format(dateFormat)),
+	})
+	if err != nil {
+		return Plan{}, false, err
+	}
+	if item == nil {
+		return Plan{}, false, nil
+	}
+
+	return parsePlan(item, start), true, nil
+}
+
+func parsePlan(item dynamodb.Item, start time.Time) Plan {
+	p := Plan{Start: start, Accepted: item.Str("plan_status") == statusAccepted}
+	if s := item.Str("dinners"); s != "" {
+		p.Dinners = strings.Split(s, "\n")
+	}
+
+	return p
+}
@@ -0,0 +1,134 @@
+// Package medication は家族ごとの服薬の予定から、服薬を促す時刻と確認できない場合に知らせる時刻を決める
+package medication
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+const Emoji = "💊"
+
+// 服薬する時刻と薬
+type Dose struct {
+	Time     string `json:"time"`     // e.g. 08:00
+	Medicine string `json:"medicine"` // e.g. 血圧の薬
+
+	at event.TimeOfDay
+}
+
+// 服薬する人
+type Person struct {
+	Name    string `json:"name"`    // e.g. alice
+	Mention string `json:"mention"` // e.g. <@123456789>、未指定の場合は名前を表示する
+	Doses   []Dose `json:"doses"`
+}
+
+// 投稿に表示する名前
+func (p Person) Display() string {
+	if p.Mention != "" {
+		return p.Mention
+	}
+
+	return p.Name
+}
+
+// e.g. [{"name": "alice", "mention": "<@123>", "doses": [{"time": "08:00", "medicine": "血圧の薬"}, {"time": "20:00", "medicine": "血圧の薬"}]}]
+type Schedule []Person
+
+func (s *Schedule) UnmarshalText(text []byte) error {
+	// Schedule のまま読み込むと UnmarshalText が再度呼び出されるため、スライスとして読み込む
+	var people []Person
+	if err := json.Unmarshal(text, &people); err != nil {
+		return fmt.Errorf("invalid medication schedule: %w", err)
+	}
+	for i := range people {
+		if err := people[i].init(); err != nil {
+			return fmt.Errorf("invalid medication schedule: %w", err)
+		}
+	}
+	*s = people
+
+	return nil
+}
+
+func (p *Person) init() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is blank")
+	}
+	if len(p.Doses) == 0 {
+		return fmt.Errorf("%s: doses are empty", p.Name)
+	}
+	for i := range p.Doses {
+		d := &p.Doses[i]
+		if d.Medicine == "" {
+			return fmt.Errorf("%s: medicine is blank", p.Name)
+		}
+		at, err := event.ParseTimeOfDay(d.Time)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.Name, err)
+		}
+		d.at = at
+	}
+
+	return nil
+}
+
+// 特定の日の服薬
+type Occurrence struct {
+	Person Person
+	Dose   Dose
+	At     time.Time
+}
+
+// 対応状況を記録するためのイベント
+// 同じ人が同じ薬を複数回服薬する場合も区別できるように、名前に時刻を含める
+func (o Occurrence) Event() event.Event {
+	at := o.Dose.at
+
+	return event.Event{
+		Name:     fmt.Sprintf("%s の%s (%s)", o.Person.Name, o.Dose.Medicine, at),
+		Emoji:    Emoji,
+		Time:     &at,
+		Assignee: o.Person.Display(),
+	}
+}
+
+// 服薬の予定がある日付
+func (o Occurrence) Date() time.Time {
+	return time.Date(o.At.Year(), o.At.Month(), o.At.Day(), 0, 0, 0, 0, o.At.Location())
+}
+
+// 現在時刻を含む枠 [start, start+window) に時刻が含まれる服薬を返す
+// intraday モードの呼び出し間隔と window を揃えることで、各服薬を 1 回ずつ通知する
+func (s Schedule) Due(now time.Time, window time.Duration) []Occurrence {
+	return s.occurrences(now, window, 0)
+}
+
+// 服薬の時刻から after が経過した時刻が、現在時刻を含む枠に含まれる服薬を返す
+// 日付をまたぐ場合に備えて、前日の服薬も対象とする
+func (s Schedule) Escalations(now time.Time, window, after time.Duration) []Occurrence {
+	return s.occurrences(now, window, after)
+}
+
+func (s Schedule) occurrences(now time.Time, window, after time.Duration) []Occurrence {
+	start := now.Truncate(window)
+	end := start.Add(window)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var occurrences []Occurrence
+	for _, date := range []time.Time{today.AddDate(0, 0, -1), today} {
+		for _, p := range s {
+			for _, d := range p.Doses {
+				at := time.Date(date.Year(), date.Month(), date.Day(), d.at.Hour, d.at.Minute, 0, 0, date.Location())
+				if t := at.Add(after); !t.Before(start) && t.Before(end) {
+					occurrences = append(occurrences, Occurrence{Person: p, Dose: d, At: at})
+				}
+			}
+		}
+	}
+
+	return occurrences
+}
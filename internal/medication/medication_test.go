@@ -0,0 +1,113 @@
+package medication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tz = time.FixedZone("JST", 9*60*60)
+
+func mustParse(t *testing.T, s string) Schedule {
+	t.Helper()
+
+	var schedule Schedule
+	require.NoError(t, schedule.UnmarshalText([]byte(s)))
+
+	return schedule
+}
+
+func TestScheduleUnmarshalText(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "正常系/複数の時刻を指定する", input: `[{"name": "alice", "mention": "<@1>", "doses": [{"time": "08:00", "medicine": "血圧の薬"}, {"time": "20:00", "medicine": "血圧の薬"}]}]`},
+		{name: "異常系/名前が空", input: `[{"doses": [{"time": "08:00", "medicine": "血圧の薬"}]}]`, wantErr: true},
+		{name: "異常系/服薬の予定が空", input: `[{"name": "alice", "doses": []}]`, wantErr: true},
+		{name: "異常系/薬の名前が空", input: `[{"name": "alice", "doses": [{"time": "08:00"}]}]`, wantErr: true},
+		{name: "異常系/時刻が不正", input: `[{"name": "alice", "doses": [{"time": "25:00", "medicine": "血圧の薬"}]}]`, wantErr: true},
+		{name: "異常系/JSON ではない", input: `alice`, wantErr: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			var s Schedule
+			err := s.UnmarshalText([]byte(tt.input))
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Len(s, 1)
+		})
+	}
+}
+
+func TestOccurrenceEvent(t *testing.T) {
+	ta := assert.New(t)
+
+	s := mustParse(t, `[{"name": "alice", "mention": "<@1>", "doses": [{"time": "08:00", "medicine": "血圧の薬"}]}, {"name": "bob", "doses": [{"time": "08:00", "medicine": "胃薬"}]}]`)
+	occurrences := s.Due(time.Date(2025, 3, 1, 8, 5, 0, 0, tz), 15*time.Minute)
+	ta.Len(occurrences, 2)
+
+	e := occurrences[0].Event()
+	ta.Equal("alice の血圧の薬 (08:00)", e.Name)
+	ta.Equal(Emoji, e.Emoji)
+	ta.Equal(&event.TimeOfDay{Hour: 8}, e.Time)
+	ta.Equal("<@1>", e.Assignee)
+	ta.Equal("bob", occurrences[1].Event().Assignee)
+	ta.NotEqual(event.Key(e), event.Key(occurrences[1].Event()))
+}
+
+func TestDue(t *testing.T) {
+	s := mustParse(t, `[{"name": "alice", "doses": [{"time": "08:00", "medicine": "血圧の薬"}, {"time": "08:15", "medicine": "胃薬"}, {"time": "20:00", "medicine": "血圧の薬"}]}]`)
+
+	cases := []struct {
+		name string
+		now  time.Time
+		want []string
+	}{
+		{name: "正常系/枠の始まりに含まれる場合", now: time.Date(2025, 3, 1, 8, 0, 0, 0, tz), want: []string{"血圧の薬"}},
+		{name: "正常系/枠の途中で呼び出された場合", now: time.Date(2025, 3, 1, 8, 14, 59, 0, tz), want: []string{"血圧の薬"}},
+		{name: "正常系/次の枠の場合", now: time.Date(2025, 3, 1, 8, 15, 0, 0, tz), want: []string{"胃薬"}},
+		{name: "正常系/服薬の予定がない場合", now: time.Date(2025, 3, 1, 12, 0, 0, 0, tz)},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			var got []string
+			for _, o := range s.Due(tt.now, 15*time.Minute) {
+				got = append(got, o.Dose.Medicine)
+				ta.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, tz), o.Date())
+			}
+			ta.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestEscalations(t *testing.T) {
+	ta := assert.New(t)
+
+	s := mustParse(t, `[{"name": "alice", "doses": [{"time": "08:00", "medicine": "血圧の薬"}, {"time": "23:30", "medicine": "睡眠薬"}]}]`)
+
+	occurrences := s.Escalations(time.Date(2025, 3, 1, 9, 5, 0, 0, tz), 15*time.Minute, time.Hour)
+	ta.Len(occurrences, 1)
+	ta.Equal(time.Date(2025, 3, 1, 8, 0, 0, 0, tz), occurrences[0].At)
+
+	ta.Empty(s.Escalations(time.Date(2025, 3, 1, 8, 45, 0, 0, tz), 15*time.Minute, time.Hour))
+
+	// 日付をまたぐ場合は前日の服薬を返す
+	occurrences = s.Escalations(time.Date(2025, 3, 2, 0, 30, 0, 0, tz), 15*time.Minute, time.Hour)
+	ta.Len(occurrences, 1)
+	ta.Equal("睡眠薬", occurrences[0].Dose.Medicine)
+	ta.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, tz), occurrences[0].Date())
+}
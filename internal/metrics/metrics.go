@@ -0,0 +1,173 @@
+// Package metrics は各関数で共通の CloudWatch のメトリクスの出力を提供する
+// メトリクスは Embedded Metric Format (EMF) のログとして出力し、CloudWatch Logs 側でメトリクスに変換させる
+package metrics
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Unit string
+
+const (
+	Count        Unit = "Count"
+	Milliseconds Unit = "Milliseconds"
+)
+
+type point struct {
+	name  string
+	value float64
+	unit  Unit
+	dims  []string // キーと値を交互に並べる
+}
+
+// 処理中に記録したメトリクスをまとめて出力する
+// nil の場合は何も記録しない
+type Recorder struct {
+	w          io.Writer
+	namespace  string
+	dimensions map[string]string // 全てのメトリクスに付けるディメンション
+	now        func() time.Time
+
+	mu     sync.Mutex
+	points []point
+}
+
+func New(w io.Writer, namespace string, dimensions map[string]string) *Recorder {
+	return &Recorder{
+		w:          w,
+		namespace:  namespace,
+		dimensions: dimensions,
+		now:        time.Now,
+	}
+}
+
+// 名前空間は METRICS_NAMESPACE (既定値は homeops) とし、
+// ディメンションには関数名と APP_ENV を付けて標準出力に出力する
+func NewFromEnv(service string) *Recorder {
+	dims := map[string]string{"Service": service}
+	if v := os.Getenv("APP_ENV"); v != "" {
+		dims["Environment"] = v
+	}
+
+	return New(os.Stdout, cmp.Or(os.Getenv("METRICS_NAMESPACE"), "homeops"), dims)
+}
+
+// dims はディメンションのキーと値を交互に指定する、e.g. Add("EventsFetched", 3, Count, "Source", "sheet")
+// 同じ名前とディメンションの値は合計する
+func (r *Recorder) Add(name string, value float64, unit Unit, dims ...string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, p := range r.points {
+		if p.name == name && slices.Equal(p.dims, dims) {
+			r.points[i].value += value
+			return
+		}
+	}
+	r.points = append(r.points, point{name: name, value: value, unit: unit, dims: dims})
+}
+
+// ディメンションの組み合わせごとに 1 行の EMF のログを出力する
+func (r *Recorder) Flush() error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	points := r.points
+	r.points = nil
+	r.mu.Unlock()
+
+	var keys []string
+	groups := map[string][]point{}
+	for _, p := range points {
+		k := strings.Join(p.dims, "\x00")
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], p)
+	}
+
+	for _, k := range keys {
+		b, err := json.Marshal(r.createLog(groups[k]))
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(r.w, string(b)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type metricDefinition struct {
+	Name string `json:"Name"`
+	Unit Unit   `json:"Unit"`
+}
+
+type metricDirective struct {
+	Namespace  string             `json:"Namespace"`
+	Dimensions [][]string         `json:"Dimensions"`
+	Metrics    []metricDefinition `json:"Metrics"`
+}
+
+type metadata struct {
+	Timestamp         int64             `json:"Timestamp"`
+	CloudWatchMetrics []metricDirective `json:"CloudWatchMetrics"`
+}
+
+// 同じディメンションを持つメトリクスを 1 つのログにまとめる
+func (r *Recorder) createLog(points []point) map[string]any {
+	log := map[string]any{}
+	dims := slices.Sorted(maps.Keys(r.dimensions))
+	for _, k := range dims {
+		log[k] = r.dimensions[k]
+	}
+	extra := points[0].dims
+	for i := 0; i+1 < len(extra); i += 2 {
+		dims = append(dims, extra[i])
+		log[extra[i]] = extra[i+1]
+	}
+
+	directive := metricDirective{
+		Namespace:  r.namespace,
+		Dimensions: [][]string{dims},
+	}
+	for _, p := range points {
+		directive.Metrics = append(directive.Metrics, metricDefinition{Name: p.name, Unit: p.unit})
+		log[p.name] = p.value
+	}
+	log["_aws"] = metadata{
+		Timestamp:         r.now().UnixMilli(),
+		CloudWatchMetrics: []metricDirective{directive},
+	}
+
+	return log
+}
+
+type contextKey struct{}
+
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+// 記録先が設定されていない場合は nil を返す
+func FromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(contextKey{}).(*Recorder)
+
+	return r
+}
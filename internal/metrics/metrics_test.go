@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlush(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	var b bytes.Buffer
+	r := New(&b, "homeops", map[string]string{"Service": "remind", "Environment": "prd"})
+	r.now = func() time.Time { return time.UnixMilli(1736899200000) }
+
+	r.Add("EventsFetched", 2, Count, "Source", "sheet")
+	r.Add("EventsFetched", 1, Count, "Source", "sheet")
+	r.Add("SourceErrors", 0, Count, "Source", "sheet")
+	r.Add("Duration", 1500, Milliseconds)
+	tr.NoError(r.Flush())
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	tr.Len(lines, 2)
+	ta.JSONEq(`{
+		"_aws": {
+			"Timestamp": 1736899200000,
+			"CloudWatchMetrics": [{
+				"Namespace": "homeops",
+				"Dimensions": [["Environment", "Service", "Source"]],
+				"Metrics": [{"Name": "EventsFetched", "Unit": "Count"}, {"Name": "SourceErrors", "Unit": "Count"}]
+			}]
+		},
+		"Environment": "prd",
+		"Service": "remind",
+		"Source": "sheet",
+		"EventsFetched": 3,
+		"SourceErrors": 0
+	}`, lines[0])
+	ta.JSONEq(`{
+		"_aws": {
+			"Timestamp": 1736899200000,
+			"CloudWatchMetrics": [{
+				"Namespace": "homeops",
+				"Dimensions": [["Environment", "Service"]],
+				"Metrics": [{"Name": "Duration", "Unit": "Milliseconds"}]
+			}]
+		},
+		"Environment": "prd",
+		"Service": "remind",
+		"Duration": 1500
+	}`, lines[1])
+
+	// 出力したメトリクスは破棄される
+	b.Reset()
+	tr.NoError(r.Flush())
+	ta.Empty(b.String())
+}
+
+func TestFromContext(t *testing.T) {
+	ta := assert.New(t)
+
+	r := New(&bytes.Buffer{}, "homeops", nil)
+	ta.Same(r, FromContext(WithRecorder(context.Background(), r)))
+
+	// 記録先がなくても呼び出せる
+	nop := FromContext(context.Background())
+	ta.Nil(nop)
+	nop.Add("EventsPosted", 1, Count)
+	ta.NoError(nop.Flush())
+}
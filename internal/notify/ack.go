@@ -0,0 +1,28 @@
+package notify
+
+import (
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+const ackButtonsLimit = 5 // 1 メッセージに付けられる Action Row の上限
+
+// イベントごとに「完了」と「1 日後に再通知」のボタンを並べる
+func createAckComponents(s event.Schedule) []discord.Component {
+	var rows []discord.Component
+	for _, e := range s.Events {
+		if e.LeadDays > 0 {
+			continue
+		}
+		date := s.Date
+		if !e.Origin.IsZero() {
+			date = e.Origin
+		}
+		rows = append(rows, discord.ActionRow(
+			discord.Button(discord.SuccessButton, "Done: "+createFieldName(e), event.AckCustomID(event.AckDone, date, e)),
+			discord.Button(discord.SecondaryButton, "Snooze 1 day", event.AckCustomID(event.AckSnoozed, date, e)),
+		))
+	}
+
+	return rows
+}
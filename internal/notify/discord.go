@@ -0,0 +1,422 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+const (
+	green int = 0x3fb950
+	gray  int = 0xcccccc
+	red   int = 0xf85149
+)
+
+// イベントが存在しない日の投稿方法
+type NoEventsMode string
+
+const (
+	noEventsEmpty    NoEventsMode = "empty"    // フィールドが空の Embed を投稿する
+	noEventsSuppress NoEventsMode = "suppress" // 投稿しない
+	noEventsNotice   NoEventsMode = "notice"   // イベントがない旨を投稿する
+)
+
+func (m *NoEventsMode) UnmarshalText(text []byte) error {
+	switch v := NoEventsMode(strings.ToLower(string(text))); v {
+	case noEventsEmpty, noEventsSuppress, noEventsNotice:
+		*m = v
+		return nil
+	default:
+		return fmt.Errorf("invalid no events mode: %s", text)
+	}
+}
+
+// Webhook 経由でチャンネルに投稿する
+type DiscordWebhookSink struct {
+	config *Config
+	format OutputFormat
+}
+
+func NewDiscordWebhookSink(cfg *Config, format OutputFormat) *DiscordWebhookSink {
+	return &DiscordWebhookSink{config: cfg, format: format}
+}
+
+func (s *DiscordWebhookSink) Name() string {
+	return "discord"
+}
+
+func (s *DiscordWebhookSink) Post(ctx context.Context, d event.Digest) error {
+	if !s.config.ICSAttachment {
+		d.Upcoming = nil
+	}
+
+	return postScheduleToDiscord(ctx, s.config, s.format, d)
+}
+
+// 投稿せずに、投稿するメッセージを返す
+func (s *DiscordWebhookSink) Messages(d event.Digest) []*discord.WebhookMessage {
+	if !s.config.ICSAttachment {
+		d.Upcoming = nil
+	}
+
+	return CreateWebhookMessages(s.config, s.format, d)
+}
+
+func postScheduleToDiscord(ctx context.Context, cfg *Config, format OutputFormat, d event.Digest) error {
+	messages := CreateWebhookMessages(cfg, format, d)
+	if len(messages) == 0 {
+		return nil
+	}
+	date := clock.Today(cfg.Clock)
+	if len(d.Schedules) > 0 {
+		date = d.Schedules[0].Date
+	}
+	if err := postWebhookMessages(ctx, cfg, date, messages); err != nil {
+		return err
+	}
+	slog.Info("succeeded to post events")
+
+	return nil
+}
+
+// イベント情報以外のメッセージを、イベント情報と同じ Webhook とスレッドの設定で投稿する
+func PostDiscordMessages(ctx context.Context, cfg *Config, messages ...*discord.WebhookMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return postWebhookMessages(ctx, cfg, clock.Today(cfg.Clock), messages)
+}
+
+// スレッドを使う場合は date のスレッドに投稿する
+func postWebhookMessages(ctx context.Context, cfg *Config, date time.Time, messages []*discord.WebhookMessage) error {
+	dc := discord.NewClient(cfg.HTTPClient, cfg.DiscordBotToken)
+	webhook, err := acquireWebhook(ctx, dc, cfg)
+	if err != nil {
+		return err
+	}
+	// 保存していない Webhook は投稿後に削除する
+	defer func() {
+		if webhook.stored {
+			return
+		}
+		if err := dc.DeleteWebhook(ctx, webhook.ID); err != nil {
+			slog.Error("failed to delete Webhook", "error", err)
+		}
+	}()
+
+	// スレッドを使わない場合はチャンネルに直接投稿する
+	var threadID string
+	if cfg.DiscordUseThread {
+		thread, err := findOrCreateThread(ctx, dc, cfg.DiscordChannelID, createThreadName(date))
+		if err != nil {
+			return err
+		}
+		threadID = thread.ID
+	}
+
+	url := dc.WebhookURL(webhook.Webhook, threadID)
+	for _, msg := range messages {
+		err := dc.ExecuteWebhook(ctx, url, msg)
+		// 保存していた Webhook が削除されていた場合は、作り直して 1 度だけ再送する
+		if webhook.reused && isUnknownWebhook(err) {
+			slog.Warn("stored Webhook is not found, recreating", slog.String("channel_id", cfg.DiscordChannelID))
+			if webhook, err = createWebhook(ctx, dc, cfg); err != nil {
+				return err
+			}
+			url = dc.WebhookURL(webhook.Webhook, threadID)
+			err = dc.ExecuteWebhook(ctx, url, msg)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 投稿に使う Webhook
+type webhookLease struct {
+	*discord.Webhook
+	reused bool // 保存していた Webhook を再利用している
+	stored bool // 保存済みのため、投稿後に削除しない
+}
+
+// 保存した Webhook があれば再利用し、なければ作成する
+func acquireWebhook(ctx context.Context, dc *discord.Client, cfg *Config) (*webhookLease, error) {
+	if cfg.WebhookStore != nil {
+		w, err := cfg.WebhookStore.Load(ctx, cfg.DiscordChannelID)
+		if err != nil {
+			slog.Warn("failed to load stored Webhook", slog.Any("error", err))
+		}
+		if w != nil {
+			return &webhookLease{Webhook: w, reused: true, stored: true}, nil
+		}
+	}
+
+	return createWebhook(ctx, dc, cfg)
+}
+
+// Webhook を作成し、保存先が指定されていれば保存する
+// 保存できなかった場合は、残り続けないように投稿後に削除する
+func createWebhook(ctx context.Context, dc *discord.Client, cfg *Config) (*webhookLease, error) {
+	w, err := dc.CreateWebhook(ctx, cfg.DiscordChannelID, cfg.DiscordBotName)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.WebhookStore == nil {
+		return &webhookLease{Webhook: w}, nil
+	}
+	if err := cfg.WebhookStore.Save(ctx, cfg.DiscordChannelID, w); err != nil {
+		slog.Warn("failed to store Webhook", slog.Any("error", err))
+		return &webhookLease{Webhook: w}, nil
+	}
+
+	return &webhookLease{Webhook: w, stored: true}, nil
+}
+
+func isUnknownWebhook(err error) bool {
+	var apiErr *discord.APIError
+
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Webhook で投稿するメッセージを作成する、投稿する内容がなければ nil を返す
+// ボタンの数が上限を超える場合は複数のメッセージに分ける
+func CreateWebhookMessages(cfg *Config, format OutputFormat, d event.Digest) []*discord.WebhookMessage {
+	schedules := d.Schedules
+	if cfg.NoEventsMode == noEventsSuppress {
+		schedules = filterEmptySchedules(schedules)
+	}
+	if len(schedules) == 0 && len(d.Overdue) == 0 && len(d.Failures) == 0 {
+		return nil
+	}
+	params := &discord.WebhookMessage{
+		Files: createICSFiles(d.Upcoming, cfg.Clock.Now()),
+	}
+	// 対応状況を記録する場合は当日と未対応のイベントにボタンを付ける
+	var rows []discord.Component
+	if format == FormatRich {
+		if len(d.Overdue) > 0 {
+			params.Embeds = append(params.Embeds, createOverdueEmbed(d.Overdue, cfg.Locale))
+			rows = append(rows, createAckComponents(event.Schedule{Events: d.Overdue})...)
+		}
+		for _, s := range schedules {
+			today := clock.IsToday(cfg.Clock, s.Date)
+			params.Embeds = append(params.Embeds, createMessageEmbed(s, today, cfg.NoEventsMode, cfg.Locale))
+			if cfg.AckEnabled && today {
+				rows = append(rows, createAckComponents(s)...)
+			}
+		}
+		// 取得に失敗した取得元は最後の Embed のフッターに記載する
+		if notes := CreateFailureNotes(d.Failures); len(notes) > 0 {
+			if n := len(params.Embeds); n > 0 {
+				params.Embeds[n-1].SetFooter(strings.Join(notes, "\n"))
+			} else {
+				params.Content = strings.Join(notes, "\n")
+			}
+		}
+	} else {
+		params.Content = RenderDigest(format, event.Digest{Schedules: schedules, Overdue: d.Overdue, Failures: d.Failures, CatchUp: d.CatchUp}, cfg.NoEventsMode, cfg.Locale)
+	}
+	if d.CatchUp && format == FormatRich {
+		params.Content = strings.TrimSpace(cfg.Locale.catchUpNotice() + "\n" + params.Content)
+	}
+	// Embed 内のメンションは通知されないため、当日の家事の担当者は本文でメンションする
+	if mentions := createAssigneeMentions(cfg, schedules); len(mentions) > 0 && format == FormatRich {
+		params.Content = strings.TrimSpace(strings.Join(mentions, " ") + "\n" + params.Content)
+	}
+	if d.Escalate && cfg.OverdueMention != "" {
+		params.Content = strings.TrimSpace(cfg.OverdueMention + "\n" + params.Content)
+	}
+
+	// Embed とボタンはそれぞれの上限ごとに続きのメッセージに分け、本文と添付ファイルは最初のメッセージにのみ付ける
+	messages := []*discord.WebhookMessage{params}
+	for i, embeds := range discord.ChunkEmbeds(params.Embeds) {
+		if i > 0 {
+			messages = append(messages, &discord.WebhookMessage{})
+		}
+		messages[i].Embeds = embeds
+	}
+	for i, components := range slices.Collect(slices.Chunk(rows, ackButtonsLimit)) {
+		if i >= len(messages) {
+			messages = append(messages, &discord.WebhookMessage{})
+		}
+		messages[i].Components = components
+	}
+
+	return messages
+}
+
+// 当日のイベントの担当者を重複を除いて返す
+func createAssigneeMentions(cfg *Config, schedules []event.Schedule) []string {
+	var mentions []string
+	for _, s := range schedules {
+		if !clock.IsToday(cfg.Clock, s.Date) {
+			continue
+		}
+		for _, e := range s.Events {
+			if e.Assignee != "" && !slices.Contains(mentions, e.Assignee) {
+				mentions = append(mentions, e.Assignee)
+			}
+		}
+	}
+
+	return mentions
+}
+
+func createThreadName(t time.Time) string {
+	return fmt.Sprintf("%s のイベント", t.Format("2006-01-02"))
+}
+
+// 同名のスレッドがチャンネル内に存在すれば再利用し、なければ作成する
+func findOrCreateThread(ctx context.Context, dc *discord.Client, channelID, name string) (*discord.Channel, error) {
+	ch, err := dc.Channel(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	threads, err := dc.ActiveThreads(ctx, ch.GuildID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range threads {
+		if t.ParentID == channelID && t.Name == name {
+			return &t, nil
+		}
+	}
+
+	// 24 時間操作がなければ自動でアーカイブされる
+	thread, err := dc.StartThread(ctx, channelID, name, 1440)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("created thread", slog.String("name", name), slog.String("id", thread.ID))
+
+	return thread, nil
+}
+
+func createICSFiles(upcoming []event.Schedule, now time.Time) []*discord.File {
+	if len(upcoming) == 0 {
+		return nil
+	}
+
+	return []*discord.File{
+		{
+			Name:        "events.ics",
+			ContentType: "text/calendar",
+			Reader:      bytes.NewReader(createICS(upcoming, now)),
+		},
+	}
+}
+
+func filterEmptySchedules(schedules []event.Schedule) []event.Schedule {
+	var filtered []event.Schedule
+	for _, s := range schedules {
+		if len(s.Events) > 0 {
+			filtered = append(filtered, s)
+		}
+	}
+
+	return filtered
+}
+
+func createMessageEmbed(s event.Schedule, today bool, mode NoEventsMode, loc Locale) *discord.Embed {
+	embed := discord.NewEmbed(loc.scheduleTitle(s.Date), getColorCode(s, today))
+	if len(s.Events) == 0 && mode == noEventsNotice {
+		embed.Description = loc.emptyDayNotice()
+	}
+	for _, e := range s.Events {
+		embed.AddField(createFieldName(e), createFieldValue(e, loc))
+	}
+
+	return embed
+}
+
+func createOverdueEmbed(events []event.Event, loc Locale) *discord.Embed {
+	embed := discord.NewEmbed(loc.overdueTitle(), red)
+	for _, e := range events {
+		embed.AddField(createFieldName(e), loc.overdueOrigin(e.Origin))
+	}
+
+	return embed
+}
+
+func createFieldName(e event.Event) string {
+	name := e.Name
+	if e.Emoji != "" {
+		name = fmt.Sprintf("%s %s", e.Emoji, e.Name)
+	}
+	if e.Time != nil {
+		name = fmt.Sprintf("%s %s", e.Time, name)
+	}
+	if e.LeadDays > 0 {
+		name = fmt.Sprintf("%s (in %d days)", name, e.LeadDays)
+	}
+	if !e.Origin.IsZero() {
+		name = fmt.Sprintf("%s (from %s)", name, e.Origin.Format("01/02"))
+	}
+
+	return name
+}
+
+func createFieldValue(e event.Event, loc Locale) string {
+	var lines []string
+	if e.Description != "" {
+		lines = append(lines, e.Description)
+	}
+	lines = append(lines, loc.intervalLabel(e.Interval))
+	if e.Assignee != "" {
+		lines = append(lines, loc.assigneeLabel(e.Assignee))
+	}
+	if e.DaysLeft > 0 {
+		lines = append(lines, loc.daysLeftLabel(e.DaysLeft))
+	}
+	if e.URL != "" {
+		lines = append(lines, e.URL)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// イベントに色が指定されていればそれを優先し、なければ当日かどうかに応じた色を使う
+// 色付きのイベントが複数ある場合は優先度が最も高い (Priority が最小の) イベントの色を使う
+// 優先度が未指定のイベントは指定済みのイベントより後に扱い、同じ優先度ではシートの並び順で先のものを使う
+func getColorCode(s event.Schedule, today bool) int {
+	var picked *event.Event
+	for i := range s.Events {
+		e := &s.Events[i]
+		if e.Color == 0 {
+			continue
+		}
+		if picked == nil || colorRank(*e) < colorRank(*picked) {
+			picked = e
+		}
+	}
+	if picked != nil {
+		return picked.Color
+	}
+	if today {
+		return green
+	}
+	return gray
+}
+
+func colorRank(e event.Event) int {
+	if e.Priority == 0 {
+		return math.MaxInt
+	}
+
+	return e.Priority
+}
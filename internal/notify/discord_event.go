@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+const scheduledEventLocation = "remind"
+
+// Discord が受け付けるスケジュールイベントの長さの上限
+const (
+	scheduledEventNameLimit        = 100
+	scheduledEventDescriptionLimit = 1000
+)
+
+// 時刻が指定されたイベントの終了時刻、外部のスケジュールイベントは終了時刻が必須のため開始時刻からの長さで決める
+const scheduledEventDuration = time.Hour
+
+// 今後のイベントをサーバーのスケジュールイベントとして登録する
+type DiscordScheduledEventSink struct {
+	config *Config
+}
+
+func NewDiscordScheduledEventSink(cfg *Config) *DiscordScheduledEventSink {
+	return &DiscordScheduledEventSink{config: cfg}
+}
+
+func (s *DiscordScheduledEventSink) Name() string {
+	return "discord_scheduled_event"
+}
+
+func (s *DiscordScheduledEventSink) Post(ctx context.Context, d event.Digest) error {
+	if len(d.Upcoming) == 0 {
+		return nil
+	}
+
+	dc := discord.NewClient(s.config.HTTPClient, s.config.DiscordBotToken)
+	ch, err := dc.Channel(ctx, s.config.DiscordChannelID)
+	if err != nil {
+		return err
+	}
+
+	// 登録済みのスケジュールイベントは作成しない
+	existing, err := dc.ScheduledEvents(ctx, ch.GuildID)
+	if err != nil {
+		return err
+	}
+	registered := make(map[string]bool)
+	for _, e := range existing {
+		registered[scheduledEventKey(e.Name, e.ScheduledStartTime)] = true
+	}
+
+	// 一部のスケジュールイベントの作成に失敗しても、残りのイベントの作成は続ける
+	now := s.config.Clock.Now()
+	var errs []error
+	for _, sc := range d.Upcoming {
+		for _, e := range sc.Events {
+			// 繰り返しのイベントを登録すると一覧が埋まるため、単発のイベントの発生日のみを対象にする
+			if e.Interval != event.Onetime || e.LeadDays > 0 {
+				continue
+			}
+
+			params := createScheduledEventParams(sc.Date, e)
+			// 開始時刻が過去のスケジュールイベントは作成できない
+			if !params.ScheduledStartTime.After(now) {
+				continue
+			}
+			if registered[scheduledEventKey(params.Name, params.ScheduledStartTime)] {
+				continue
+			}
+			if _, err := dc.CreateScheduledEvent(ctx, ch.GuildID, params); err != nil {
+				slog.Error("failed to create scheduled event", slog.String("name", params.Name), slog.Any("error", err))
+				errs = append(errs, fmt.Errorf("%s: %w", params.Name, err))
+				continue
+			}
+			slog.Info("created scheduled event", slog.String("name", params.Name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// 時刻が指定されている場合はその時刻から、指定されていない場合は終日のイベントとして作成する
+func createScheduledEventParams(date time.Time, e event.Event) *discord.ScheduledEvent {
+	start, end := date, date.AddDate(0, 0, 1)
+	if e.Time != nil {
+		start = e.Time.On(date)
+		end = start.Add(scheduledEventDuration)
+	}
+
+	var desc []string
+	if e.Description != "" {
+		desc = append(desc, e.Description)
+	}
+	if e.URL != "" {
+		desc = append(desc, e.URL)
+	}
+
+	return &discord.ScheduledEvent{
+		Name:               discord.Truncate(createFieldName(e), scheduledEventNameLimit),
+		Description:        discord.Truncate(strings.Join(desc, "\n"), scheduledEventDescriptionLimit),
+		ScheduledStartTime: start,
+		ScheduledEndTime:   &end,
+		PrivacyLevel:       discord.PrivacyLevelGuildOnly,
+		EntityType:         discord.EntityTypeExternal,
+		EntityMetadata: &discord.ScheduledEventMetadata{
+			Location: scheduledEventLocation,
+		},
+	}
+}
+
+func scheduledEventKey(name string, start time.Time) string {
+	return name + "/" + start.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,176 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestDiscordScheduledEventSinkPost(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	today := time.Date(2025, 3, 1, 0, 0, 0, 0, tz)
+	tomorrow := today.AddDate(0, 0, 1)
+	dentist := event.Event{Name: "Dentist", Interval: event.Onetime, Description: "Bring the card", URL: "https://example.com"}
+
+	cases := []struct {
+		name       string
+		upcoming   []event.Schedule
+		registered []discord.ScheduledEvent
+		want       []string
+	}{
+		{
+			name: "正常系/単発のイベントの発生日のみを登録する",
+			upcoming: []event.Schedule{
+				{Date: today, Events: []event.Event{{Name: "Today", Interval: event.Onetime}}},
+				{Date: tomorrow, Events: []event.Event{
+					dentist,
+					{Name: "Garbage", Interval: event.Weekly},
+					{Name: "Notice", Interval: event.Onetime, LeadDays: 3},
+				}},
+			},
+			want: []string{createFieldName(dentist)},
+		},
+		{
+			name:       "正常系/登録済みのスケジュールイベントは作成しない",
+			upcoming:   []event.Schedule{{Date: tomorrow, Events: []event.Event{dentist}}},
+			registered: []discord.ScheduledEvent{{ID: "1", Name: createFieldName(dentist), ScheduledStartTime: tomorrow.UTC()}},
+			want:       []string{createFieldName(dentist)},
+		},
+		{
+			name: "正常系/今後のイベントがない場合",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			srv := testsupport.NewServer(t)
+			for _, e := range tt.registered {
+				srv.Discord.AddScheduledEvent(e)
+			}
+			cfg := &Config{
+				HTTPClient:       srv.Client(),
+				Clock:            clock.Fixed(today.Add(8 * time.Hour)),
+				DiscordBotToken:  "token",
+				DiscordChannelID: "123",
+			}
+
+			ta.NoError(NewDiscordScheduledEventSink(cfg).Post(context.Background(), event.Digest{Upcoming: tt.upcoming}))
+
+			var names []string
+			for _, e := range srv.Discord.ScheduledEvents() {
+				names = append(names, e.Name)
+			}
+			ta.Equal(tt.want, names)
+		})
+	}
+
+	t.Run("正常系/終日のイベントとして登録する", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		srv := testsupport.NewServer(t)
+		cfg := &Config{HTTPClient: srv.Client(), Clock: clock.Fixed(today), DiscordBotToken: "token", DiscordChannelID: "123"}
+		tr.NoError(NewDiscordScheduledEventSink(cfg).Post(context.Background(), event.Digest{
+			Upcoming: []event.Schedule{{Date: tomorrow, Events: []event.Event{dentist}}},
+		}))
+
+		events := srv.Discord.ScheduledEvents()
+		tr.Len(events, 1)
+		ta.Equal("Bring the card\nhttps://example.com", events[0].Description)
+		ta.True(tomorrow.Equal(events[0].ScheduledStartTime))
+		ta.True(tomorrow.AddDate(0, 0, 1).Equal(*events[0].ScheduledEndTime))
+		ta.Equal(discord.EntityTypeExternal, events[0].EntityType)
+		ta.Equal(scheduledEventLocation, events[0].EntityMetadata.Location)
+	})
+
+	t.Run("正常系/時刻が指定されたイベントはその時刻から登録する", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		srv := testsupport.NewServer(t)
+		cfg := &Config{HTTPClient: srv.Client(), Clock: clock.Fixed(today), DiscordBotToken: "token", DiscordChannelID: "123"}
+		meeting := event.Event{Name: "Meeting", Interval: event.Onetime, Time: &event.TimeOfDay{Hour: 10, Minute: 30}}
+		tr.NoError(NewDiscordScheduledEventSink(cfg).Post(context.Background(), event.Digest{
+			Upcoming: []event.Schedule{{Date: tomorrow, Events: []event.Event{meeting}}},
+		}))
+
+		events := srv.Discord.ScheduledEvents()
+		tr.Len(events, 1)
+		ta.True(tomorrow.Add(10*time.Hour + 30*time.Minute).Equal(events[0].ScheduledStartTime))
+		ta.True(tomorrow.Add(11*time.Hour + 30*time.Minute).Equal(*events[0].ScheduledEndTime))
+	})
+
+	t.Run("正常系/長い名前と説明は上限に合わせて省略する", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		srv := testsupport.NewServer(t)
+		cfg := &Config{HTTPClient: srv.Client(), Clock: clock.Fixed(today), DiscordBotToken: "token", DiscordChannelID: "123"}
+		long := event.Event{Name: strings.Repeat("長", 150), Interval: event.Onetime, Description: strings.Repeat("説", 1500)}
+		tr.NoError(NewDiscordScheduledEventSink(cfg).Post(context.Background(), event.Digest{
+			Upcoming: []event.Schedule{{Date: tomorrow, Events: []event.Event{long}}},
+		}))
+
+		events := srv.Discord.ScheduledEvents()
+		tr.Len(events, 1)
+		ta.Equal(100, utf8.RuneCountInString(events[0].Name))
+		ta.Equal(1000, utf8.RuneCountInString(events[0].Description))
+	})
+
+	t.Run("異常系/一部のイベントの作成に失敗した場合も残りは作成する", func(t *testing.T) {
+		ta := assert.New(t)
+
+		srv := testsupport.NewServer(t)
+		base := srv.Client().Transport
+		failed := false
+		client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/scheduled-events") && !failed {
+				failed = true
+				return &http.Response{StatusCode: http.StatusInternalServerError, Body: io.NopCloser(strings.NewReader(`{"message": "500: Internal Server Error"}`)), Header: http.Header{}, Request: r}, nil
+			}
+			return base.RoundTrip(r)
+		})}
+		cfg := &Config{HTTPClient: client, Clock: clock.Fixed(today), DiscordBotToken: "token", DiscordChannelID: "123"}
+		err := NewDiscordScheduledEventSink(cfg).Post(context.Background(), event.Digest{
+			Upcoming: []event.Schedule{{Date: tomorrow, Events: []event.Event{dentist, {Name: "Haircut", Interval: event.Onetime}}}},
+		})
+		ta.ErrorContains(err, createFieldName(dentist))
+
+		var names []string
+		for _, e := range srv.Discord.ScheduledEvents() {
+			names = append(names, e.Name)
+		}
+		ta.Equal([]string{createFieldName(event.Event{Name: "Haircut", Interval: event.Onetime})}, names)
+	})
+
+	t.Run("異常系/Discord に接続できない場合", func(t *testing.T) {
+		client := &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		})}
+		cfg := &Config{HTTPClient: client, Clock: clock.Fixed(today), DiscordBotToken: "token", DiscordChannelID: "123"}
+		err := NewDiscordScheduledEventSink(cfg).Post(context.Background(), event.Digest{
+			Upcoming: []event.Schedule{{Date: tomorrow, Events: []event.Event{dentist}}},
+		})
+		assert.Error(t, err)
+	})
+}
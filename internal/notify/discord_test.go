@@ -0,0 +1,278 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateWebhookMessages(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	tz := time.FixedZone("JST", 9*60*60)
+	cfg := &Config{
+		Clock:      clock.Fixed(time.Date(2025, 3, 1, 8, 0, 0, 0, tz)),
+		AckEnabled: true,
+	}
+	d := event.Digest{Schedules: []event.Schedule{
+		{Date: time.Date(2025, 3, 1, 0, 0, 0, 0, tz), Events: []event.Event{{Name: "Garbage"}}},
+		{Date: time.Date(2025, 3, 2, 0, 0, 0, 0, tz), Events: []event.Event{{Name: "Recycle"}}},
+	}}
+
+	messages := CreateWebhookMessages(cfg, FormatRich, d)
+
+	tr.Len(messages, 1)
+	tr.Len(messages[0].Embeds, 2)
+	ta.Equal(green, messages[0].Embeds[0].Color)
+	ta.Equal(gray, messages[0].Embeds[1].Color)
+	// 当日のイベントにのみボタンを付ける
+	tr.Len(messages[0].Components, 1)
+	ta.Contains(messages[0].Components[0].Components[0].Label, "Garbage")
+}
+
+// Embed の数が上限を超える場合は続きのメッセージに分け、本文と添付ファイルは最初のメッセージにのみ付ける
+func TestCreateWebhookMessagesEmbedsLimit(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	tz := time.FixedZone("JST", 9*60*60)
+	today := time.Date(2025, 3, 1, 0, 0, 0, 0, tz)
+	cfg := &Config{Clock: clock.Fixed(today.Add(8 * time.Hour)), AckEnabled: true, OverdueMention: "<@&1>"}
+	d := event.Digest{
+		Overdue:  []event.Event{{Name: "Bills"}},
+		Upcoming: []event.Schedule{{Date: today, Events: []event.Event{{Name: "Garbage"}}}},
+		Escalate: true,
+	}
+	for i := range 14 {
+		d.Schedules = append(d.Schedules, event.Schedule{Date: today.AddDate(0, 0, i), Events: []event.Event{{Name: fmt.Sprintf("Event %02d", i+1)}}})
+	}
+
+	messages := CreateWebhookMessages(cfg, FormatRich, d)
+
+	// 未対応の 1 件と 14 日分の 15 件を 10 件ごとに分ける
+	tr.Len(messages, 2)
+	tr.Len(messages[0].Embeds, discord.EmbedsLimit)
+	tr.Len(messages[1].Embeds, 5)
+	ta.Equal("Event 14", messages[1].Embeds[4].Fields[0].Name)
+	ta.Equal("<@&1>", messages[0].Content)
+	ta.Len(messages[0].Files, 1)
+	ta.Empty(messages[1].Content)
+	ta.Empty(messages[1].Files)
+	// ボタンは最初のメッセージに付ける
+	ta.Len(messages[0].Components, 2)
+	ta.Empty(messages[1].Components)
+}
+
+// 当日の家事の担当者は本文でメンションし、翌日以降の担当者は Embed にのみ表示する
+func TestCreateWebhookMessagesAssignee(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	tz := time.FixedZone("JST", 9*60*60)
+	cfg := &Config{Clock: clock.Fixed(time.Date(2025, 3, 1, 8, 0, 0, 0, tz))}
+	d := event.Digest{Schedules: []event.Schedule{
+		{Date: time.Date(2025, 3, 1, 0, 0, 0, 0, tz), Events: []event.Event{
+			{Name: "Bath", Assignee: "<@1>"},
+			{Name: "Dishes", Assignee: "<@1>"},
+			{Name: "Laundry", Assignee: "<@2>"},
+		}},
+		{Date: time.Date(2025, 3, 2, 0, 0, 0, 0, tz), Events: []event.Event{{Name: "Bath", Assignee: "<@3>"}}},
+	}}
+
+	messages := CreateWebhookMessages(cfg, FormatRich, d)
+
+	tr.Len(messages, 1)
+	ta.Equal("<@1> <@2>", messages[0].Content)
+	ta.Contains(messages[0].Embeds[1].Fields[0].Value, "担当: <@3>")
+}
+
+// 色付きのイベントが複数ある日は優先度が最も高いイベントの色を使う
+func TestGetColorCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []event.Event
+		today  bool
+		want   int
+	}{
+		{
+			name:  "正常系/色指定なしの当日",
+			today: true,
+			want:  green,
+		},
+		{
+			name:   "正常系/色指定なしの翌日以降",
+			events: []event.Event{{Name: "Garbage"}},
+			want:   gray,
+		},
+		{
+			name:   "正常系/色付きのイベントが 1 件",
+			events: []event.Event{{Name: "Garbage"}, {Name: "Bills", Color: red}},
+			today:  true,
+			want:   red,
+		},
+		{
+			name: "正常系/優先度が最も高いイベントの色を使う",
+			events: []event.Event{
+				{Name: "Garbage", Color: 0x0000ff, Priority: 3},
+				{Name: "Bills", Color: red, Priority: 1},
+				{Name: "Recycle", Color: 0x00ff00, Priority: 2},
+			},
+			want: red,
+		},
+		{
+			name: "正常系/優先度未指定のイベントは指定済みのイベントより後に扱う",
+			events: []event.Event{
+				{Name: "Garbage", Color: 0x0000ff},
+				{Name: "Bills", Color: red, Priority: 5},
+			},
+			want: red,
+		},
+		{
+			name: "正常系/同じ優先度では先のイベントの色を使う",
+			events: []event.Event{
+				{Name: "Garbage", Color: 0x0000ff},
+				{Name: "Bills", Color: red},
+			},
+			want: 0x0000ff,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			ta.Equal(tt.want, getColorCode(event.Schedule{Events: tt.events}, tt.today))
+		})
+	}
+}
+
+func TestIsUnknownWebhook(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.True(isUnknownWebhook(fmt.Errorf("execute: %w", &discord.APIError{StatusCode: 404, Code: 10015})))
+	ta.False(isUnknownWebhook(&discord.APIError{StatusCode: 403}))
+	ta.False(isUnknownWebhook(errors.New("timeout")))
+	ta.False(isUnknownWebhook(nil))
+}
+
+func TestDiscordWebhookSinkPostThread(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	today := time.Date(2025, 3, 1, 0, 0, 0, 0, tz)
+	d := event.Digest{Schedules: []event.Schedule{{Date: today, Events: []event.Event{{Name: "Garbage"}}}}}
+
+	cases := []struct {
+		name        string
+		useThread   bool
+		existing    []discord.Channel
+		wantThread  string // 投稿先のスレッドの名前、空の場合はチャンネルに直接投稿する
+		wantThreads int
+	}{
+		{
+			name:       "正常系/スレッドを使わない場合はチャンネルに投稿する",
+			useThread:  false,
+			wantThread: "",
+		},
+		{
+			name:        "正常系/日付のスレッドがなければ作成する",
+			useThread:   true,
+			wantThread:  "2025-03-01 のイベント",
+			wantThreads: 1,
+		},
+		{
+			name:      "正常系/同じチャンネルに同名のスレッドがあれば再利用する",
+			useThread: true,
+			existing: []discord.Channel{
+				{ID: "1", ParentID: "999", Name: "2025-03-01 のイベント"},
+				{ID: "2", ParentID: "123", Name: "2025-02-28 のイベント"},
+				{ID: "3", ParentID: "123", Name: "2025-03-01 のイベント"},
+			},
+			wantThread:  "2025-03-01 のイベント",
+			wantThreads: 3,
+		},
+		{
+			name:      "正常系/他のチャンネルに同名のスレッドがあっても作成する",
+			useThread: true,
+			existing: []discord.Channel{
+				{ID: "1", ParentID: "999", Name: "2025-03-01 のイベント"},
+			},
+			wantThread:  "2025-03-01 のイベント",
+			wantThreads: 2,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			tr := require.New(t)
+
+			srv := testsupport.NewServer(t)
+			for _, c := range tt.existing {
+				srv.Discord.AddThread(c)
+			}
+			cfg := &Config{
+				HTTPClient:       srv.Client(),
+				Clock:            clock.Fixed(today.Add(8 * time.Hour)),
+				DiscordBotToken:  "token",
+				DiscordChannelID: "123",
+				DiscordUseThread: tt.useThread,
+				NoEventsMode:     noEventsEmpty,
+			}
+
+			tr.NoError(NewDiscordWebhookSink(cfg, FormatRich).Post(context.Background(), d))
+
+			messages := srv.Discord.Messages()
+			tr.Len(messages, 1)
+			threads := srv.Discord.Threads()
+			ta.Len(threads, tt.wantThreads)
+			if tt.wantThread == "" {
+				ta.Empty(messages[0].ThreadID)
+				return
+			}
+			// 投稿先のスレッドは設定したチャンネルの、日付の名前のスレッドである
+			idx := slices.IndexFunc(threads, func(c discord.Channel) bool { return c.ID == messages[0].ThreadID })
+			tr.NotEqual(-1, idx)
+			ta.Equal("123", threads[idx].ParentID)
+			ta.Equal(tt.wantThread, threads[idx].Name)
+		})
+	}
+
+	t.Run("異常系/スレッドを作成できない場合は投稿しない", func(t *testing.T) {
+		ta := assert.New(t)
+
+		srv := testsupport.NewServer(t)
+		base := srv.Client()
+		client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/threads") {
+				return &http.Response{StatusCode: http.StatusForbidden, Body: io.NopCloser(strings.NewReader(`{"code":50013,"message":"Missing Permissions"}`)), Header: http.Header{}}, nil
+			}
+			return base.Transport.RoundTrip(r)
+		})}
+		cfg := &Config{
+			HTTPClient:       client,
+			Clock:            clock.Fixed(today.Add(8 * time.Hour)),
+			DiscordBotToken:  "token",
+			DiscordChannelID: "123",
+			DiscordUseThread: true,
+			NoEventsMode:     noEventsEmpty,
+		}
+
+		ta.Error(NewDiscordWebhookSink(cfg, FormatRich).Post(context.Background(), d))
+		ta.Empty(srv.Discord.Messages())
+		// 投稿できなくても、作成した Webhook は削除する
+		created, deleted := srv.Discord.WebhookCount()
+		ta.Equal(created, deleted)
+	})
+}
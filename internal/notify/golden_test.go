@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 投稿内容を意図して変更した場合は go test ./internal/notify -update で golden ファイルを更新する
+var update = flag.Bool("update", false, "update golden files")
+
+func TestWebhookMessagesGolden(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	today := time.Date(2025, 3, 1, 0, 0, 0, 0, tz)
+	at := func(h, m int) *event.TimeOfDay {
+		return &event.TimeOfDay{Hour: h, Minute: m}
+	}
+
+	var many []event.Event
+	for i := 1; i <= 30; i++ {
+		many = append(many, event.Event{Name: fmt.Sprintf("Event %02d", i), Interval: event.Daily})
+	}
+
+	tests := []struct {
+		name   string
+		locale Locale
+		mode   NoEventsMode
+		ack    bool
+		digest event.Digest
+	}{
+		{
+			name:   "empty_day",
+			locale: localeEn,
+			mode:   noEventsEmpty,
+			digest: event.Digest{Schedules: []event.Schedule{{Date: today}}},
+		},
+		{
+			name:   "empty_day_notice",
+			locale: localeEn,
+			mode:   noEventsNotice,
+			digest: event.Digest{Schedules: []event.Schedule{{Date: today}}},
+		},
+		{
+			name:   "many_events",
+			locale: localeEn,
+			mode:   noEventsEmpty,
+			digest: event.Digest{Schedules: []event.Schedule{{Date: today, Events: many}}},
+		},
+		{
+			name:   "long_names",
+			locale: localeEn,
+			mode:   noEventsEmpty,
+			ack:    true,
+			digest: event.Digest{Schedules: []event.Schedule{{Date: today, Events: []event.Event{
+				{Name: strings.Repeat("Very long event name ", 20), Interval: event.Weekly, Description: strings.Repeat("description ", 120)},
+				{Name: "Garbage", Interval: event.Daily, Emoji: "🗑", Time: at(7, 30), URL: "https://example.com/garbage"},
+			}}}},
+		},
+		{
+			name:   "catch_up",
+			locale: localeEn,
+			mode:   noEventsEmpty,
+			digest: event.Digest{
+				Schedules: []event.Schedule{{Date: today.AddDate(0, 0, -2), Events: []event.Event{{Name: "Garbage", Interval: event.Weekly}}}},
+				CatchUp:   true,
+			},
+		},
+		{
+			name:   "japanese",
+			locale: localeJa,
+			mode:   noEventsEmpty,
+			ack:    true,
+			digest: event.Digest{
+				Schedules: []event.Schedule{
+					{Date: today, Events: []event.Event{
+						{Name: "燃えるゴミ", Interval: event.Weekly, Emoji: "🔥", Time: at(8, 0), Description: "8 時までに出す"},
+						{Name: "ピアノ教室", Interval: event.Monthly, LeadDays: 2},
+					}},
+					{Date: today.AddDate(0, 0, 1), Events: []event.Event{{Name: "資源ゴミ", Interval: event.Weekly}}},
+				},
+				Overdue:  []event.Event{{Name: "プランターの水やり", Interval: event.Daily, Origin: today.AddDate(0, 0, -3)}},
+				Failures: []error{event.NewSourceUnavailableError("holidays", fmt.Errorf("timeout"))},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			tr := require.New(t)
+
+			cfg := &Config{
+				Clock:        clock.Fixed(today.Add(8 * time.Hour)),
+				NoEventsMode: tt.mode,
+				Locale:       tt.locale,
+				AckEnabled:   tt.ack,
+			}
+			got, err := json.MarshalIndent(CreateWebhookMessages(cfg, FormatRich, tt.digest), "", "  ")
+			tr.NoError(err)
+			got = append(got, '\n')
+
+			path := filepath.Join("testdata", "golden", tt.name+".json")
+			if *update {
+				tr.NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+				tr.NoError(os.WriteFile(path, got, 0o644))
+			}
+			want, err := os.ReadFile(path)
+			tr.NoError(err)
+			ta.Equal(string(want), string(got))
+		})
+	}
+}
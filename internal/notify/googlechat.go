@@ -0,0 +1,196 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+)
+
+// Google Chat のメッセージ (テキストもしくはカード形式)
+// https://developers.google.com/workspace/chat/api/reference/rest/v1/cards
+type chatMessage struct {
+	Text    string           `json:"text,omitempty"`
+	CardsV2 []chatCardWithID `json:"cardsV2,omitempty"`
+}
+
+type chatCardWithID struct {
+	CardID string   `json:"cardId"`
+	Card   chatCard `json:"card"`
+}
+
+type chatCard struct {
+	Header   chatCardHeader `json:"header"`
+	Sections []chatSection  `json:"sections"`
+}
+
+type chatCardHeader struct {
+	Title string `json:"title"`
+}
+
+type chatSection struct {
+	Widgets []chatWidget `json:"widgets"`
+}
+
+type chatWidget struct {
+	DecoratedText *chatDecoratedText `json:"decoratedText,omitempty"`
+	TextParagraph *chatTextParagraph `json:"textParagraph,omitempty"`
+}
+
+type chatDecoratedText struct {
+	TopLabel    string `json:"topLabel,omitempty"`
+	Text        string `json:"text"`
+	BottomLabel string `json:"bottomLabel,omitempty"`
+	WrapText    bool   `json:"wrapText"`
+}
+
+type chatTextParagraph struct {
+	Text string `json:"text"`
+}
+
+// Google Chat の Incoming Webhook に投稿する
+type GoogleChatSink struct {
+	config *Config
+	format OutputFormat
+	client *http.Client
+}
+
+func NewGoogleChatSink(cfg *Config, format OutputFormat) *GoogleChatSink {
+	// タイムアウトのない http.DefaultClient に頼らないよう、未指定の場合は共有のクライアントを使う
+	client := cfg.HTTPClient
+	if client == nil {
+		client = httpclient.Default
+	}
+
+	return &GoogleChatSink{
+		config: cfg,
+		format: format,
+		client: client,
+	}
+}
+
+func (s *GoogleChatSink) Name() string {
+	return "google_chat"
+}
+
+func (s *GoogleChatSink) Post(ctx context.Context, d event.Digest) error {
+	schedules := d.Schedules
+	if s.config.NoEventsMode == noEventsSuppress {
+		schedules = filterEmptySchedules(schedules)
+	}
+	if len(schedules) == 0 && len(d.Overdue) == 0 && len(d.Failures) == 0 {
+		return nil
+	}
+
+	msg := chatMessage{Text: RenderDigest(s.format, event.Digest{Schedules: schedules, Overdue: d.Overdue, Failures: d.Failures, CatchUp: d.CatchUp}, s.config.NoEventsMode, s.config.Locale)}
+	if s.format == FormatRich {
+		msg = createChatMessage(d.Overdue, schedules, s.config.NoEventsMode, s.config.Locale)
+		// 取得に失敗した取得元はカードの下にテキストで記載する
+		notes := CreateFailureNotes(d.Failures)
+		if d.CatchUp {
+			notes = append([]string{s.config.Locale.catchUpNotice()}, notes...)
+		}
+		msg.Text = strings.Join(notes, "\n")
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.GoogleChatWebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("google chat returned status %d: %s", resp.StatusCode, b)
+	}
+	slog.Info("succeeded to post events to Google Chat")
+
+	return nil
+}
+
+// 日付ごとに 1 枚のカードを作成する
+// 未対応のイベントがあれば先頭にカードを追加する
+func createChatMessage(overdue []event.Event, schedules []event.Schedule, mode NoEventsMode, loc Locale) chatMessage {
+	var msg chatMessage
+	if len(overdue) > 0 {
+		var widgets []chatWidget
+		for _, e := range overdue {
+			widgets = append(widgets, chatWidget{
+				DecoratedText: &chatDecoratedText{
+					Text:     createChatEventText(e),
+					WrapText: true,
+				},
+			})
+		}
+		msg.CardsV2 = append(msg.CardsV2, chatCardWithID{
+			CardID: "overdue",
+			Card: chatCard{
+				Header:   chatCardHeader{Title: loc.overdueTitle()},
+				Sections: []chatSection{{Widgets: widgets}},
+			},
+		})
+	}
+	for _, s := range schedules {
+		card := chatCard{
+			Header: chatCardHeader{
+				Title: loc.scheduleTitle(s.Date),
+			},
+		}
+
+		var widgets []chatWidget
+		for _, e := range s.Events {
+			widgets = append(widgets, chatWidget{
+				DecoratedText: &chatDecoratedText{
+					TopLabel:    e.Interval.String(),
+					Text:        createChatEventText(e),
+					BottomLabel: e.Description,
+					WrapText:    true,
+				},
+			})
+		}
+		if len(s.Events) == 0 && mode == noEventsNotice {
+			widgets = append(widgets, chatWidget{
+				TextParagraph: &chatTextParagraph{Text: loc.emptyDayNotice()},
+			})
+		}
+		if len(widgets) > 0 {
+			card.Sections = []chatSection{{Widgets: widgets}}
+		}
+
+		msg.CardsV2 = append(msg.CardsV2, chatCardWithID{
+			CardID: s.Date.Format("20060102"),
+			Card:   card,
+		})
+	}
+
+	return msg
+}
+
+// URL が指定されていればイベント名をリンクにする
+// Google Chat は HTML の一部のタグを解釈するため、URL とイベント名をエスケープする
+func createChatEventText(e event.Event) string {
+	name := html.EscapeString(createFieldName(e))
+	if e.URL == "" {
+		return name
+	}
+
+	return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(e.URL), name)
+}
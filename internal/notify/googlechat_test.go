@@ -0,0 +1,179 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateChatEventText(t *testing.T) {
+	cases := []struct {
+		name  string
+		event event.Event
+		want  string
+	}{
+		{
+			name:  "正常系/URL がない場合はイベント名のみ",
+			event: event.Event{Name: "Garbage"},
+			want:  "Garbage",
+		},
+		{
+			name:  "正常系/URL がある場合はリンクにする",
+			event: event.Event{Name: "Dentist", URL: "https://example.com/booking"},
+			want:  `<a href="https://example.com/booking">Dentist</a>`,
+		},
+		{
+			name:  "正常系/URL がない場合もイベント名をエスケープする",
+			event: event.Event{Name: "<Tom & Jerry>"},
+			want:  "&lt;Tom &amp; Jerry&gt;",
+		},
+		{
+			name:  "正常系/URL とイベント名をエスケープする",
+			event: event.Event{Name: "<Tom & Jerry>", URL: `https://example.com/?a=1&b="x"`},
+			want:  `<a href="https://example.com/?a=1&amp;b=&#34;x&#34;">&lt;Tom &amp; Jerry&gt;</a>`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, createChatEventText(tt.event))
+		})
+	}
+}
+
+func TestGoogleChatSinkPost(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, tz)
+
+	cases := []struct {
+		name     string
+		format   OutputFormat
+		mode     NoEventsMode
+		digest   event.Digest
+		status   int
+		want     *chatMessage // nil の場合は投稿しない
+		wantText string
+		wantErr  bool
+	}{
+		{
+			name:   "正常系/日付ごとのカードを投稿する",
+			format: FormatRich,
+			mode:   noEventsNotice,
+			digest: event.Digest{
+				Schedules: []event.Schedule{
+					{Date: date, Events: []event.Event{{Name: "Garbage", Interval: event.Weekly, Description: "Burnable"}}},
+					{Date: date.AddDate(0, 0, 1)},
+				},
+				Overdue:  []event.Event{{Name: "Piano", Interval: event.Onetime}},
+				Failures: []error{errors.New("holidays: unavailable")},
+			},
+			status: http.StatusOK,
+			want: &chatMessage{CardsV2: []chatCardWithID{
+				{CardID: "overdue", Card: chatCard{
+					Header:   chatCardHeader{Title: "⚠ Overdue"},
+					Sections: []chatSection{{Widgets: []chatWidget{{DecoratedText: &chatDecoratedText{Text: "Piano", WrapText: true}}}}},
+				}},
+				{CardID: "20250301", Card: chatCard{
+					Header: chatCardHeader{Title: "2025-03-01 (Sat) のイベント"},
+					Sections: []chatSection{{Widgets: []chatWidget{
+						{DecoratedText: &chatDecoratedText{TopLabel: "Weekly", Text: "Garbage", BottomLabel: "Burnable", WrapText: true}},
+					}}},
+				}},
+				{CardID: "20250302", Card: chatCard{
+					Header:   chatCardHeader{Title: "2025-03-02 (Sun) のイベント"},
+					Sections: []chatSection{{Widgets: []chatWidget{{TextParagraph: &chatTextParagraph{Text: "No events today 🎉"}}}}},
+				}},
+			}},
+			wantText: "⚠ some source unavailable — events may be missing",
+		},
+		{
+			name:   "正常系/URL がないイベントの名前をエスケープする",
+			format: FormatRich,
+			mode:   noEventsEmpty,
+			digest: event.Digest{Schedules: []event.Schedule{{Date: date, Events: []event.Event{{Name: "<b>Tom & Jerry</b>", Interval: event.Onetime}}}}},
+			status: http.StatusOK,
+			want: &chatMessage{CardsV2: []chatCardWithID{
+				{CardID: "20250301", Card: chatCard{
+					Header: chatCardHeader{Title: "2025-03-01 (Sat) のイベント"},
+					Sections: []chatSection{{Widgets: []chatWidget{
+						{DecoratedText: &chatDecoratedText{TopLabel: "Onetime", Text: "&lt;b&gt;Tom &amp; Jerry&lt;/b&gt;", WrapText: true}},
+					}}},
+				}},
+			}},
+		},
+		{
+			name:   "正常系/テキスト形式で投稿する",
+			format: FormatText,
+			mode:   noEventsEmpty,
+			digest: event.Digest{Schedules: []event.Schedule{{Date: date, Events: []event.Event{{Name: "Garbage"}}}}},
+			status: http.StatusOK,
+			want:   &chatMessage{},
+		},
+		{
+			name:   "正常系/イベントがなく投稿しない設定の場合",
+			format: FormatRich,
+			mode:   noEventsSuppress,
+			digest: event.Digest{Schedules: []event.Schedule{{Date: date}}},
+		},
+		{
+			name:    "異常系/Google Chat がエラーを返した場合",
+			format:  FormatRich,
+			mode:    noEventsEmpty,
+			digest:  event.Digest{Schedules: []event.Schedule{{Date: date}}},
+			status:  http.StatusBadRequest,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			tr := require.New(t)
+
+			var got *chatMessage
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ta.Equal("application/json; charset=UTF-8", r.Header.Get("Content-Type"))
+				got = &chatMessage{}
+				ta.NoError(json.NewDecoder(r.Body).Decode(got))
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			cfg := &Config{HTTPClient: srv.Client(), GoogleChatWebhookURL: srv.URL, NoEventsMode: tt.mode, Locale: localeEn}
+			err := NewGoogleChatSink(cfg, tt.format).Post(context.Background(), tt.digest)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			tr.NoError(err)
+			if tt.want == nil {
+				ta.Nil(got)
+				return
+			}
+			tr.NotNil(got)
+			if tt.format == FormatRich {
+				ta.Equal(tt.want.CardsV2, got.CardsV2)
+				ta.Equal(tt.wantText, got.Text)
+				return
+			}
+			ta.Empty(got.CardsV2)
+			ta.Contains(got.Text, "Garbage")
+		})
+	}
+}
+
+func TestNewGoogleChatSinkClient(t *testing.T) {
+	// HTTP クライアントを指定しない場合もタイムアウトのあるクライアントを使う
+	s := NewGoogleChatSink(&Config{}, FormatRich)
+	require.NotNil(t, s.client)
+	assert.NotSame(t, http.DefaultClient, s.client)
+	assert.NotZero(t, s.client.Timeout)
+}
@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+const icsLineLimit = 75
+
+// スケジュールを iCalendar (RFC 5545) 形式に変換する
+func createICS(schedules []event.Schedule, now time.Time) []byte {
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//mami0tsu//homeops remind//JA")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	for _, s := range schedules {
+		for _, e := range s.Events {
+			// 事前の通知は発生日の予定と重複するため出力しない
+			if e.LeadDays > 0 {
+				continue
+			}
+			writeICSEvent(&b, s.Date, e, now)
+		}
+	}
+	writeICSLine(&b, "END:VCALENDAR")
+
+	return []byte(b.String())
+}
+
+// イベントは終日の予定として出力する
+func writeICSEvent(b *strings.Builder, date time.Time, e event.Event, now time.Time) {
+	writeICSLine(b, "BEGIN:VEVENT")
+	writeICSLine(b, "UID:"+createICSUID(date, e))
+	writeICSLine(b, "DTSTAMP:"+now.UTC().Format("20060102T150405Z"))
+	writeICSLine(b, "DTSTART;VALUE=DATE:"+date.Format("20060102"))
+	writeICSLine(b, "DTEND;VALUE=DATE:"+date.AddDate(0, 0, 1).Format("20060102"))
+	writeICSLine(b, "SUMMARY:"+escapeICSText(createFieldName(e)))
+	if e.Description != "" {
+		writeICSLine(b, "DESCRIPTION:"+escapeICSText(e.Description))
+	}
+	if e.URL != "" {
+		writeICSLine(b, "URL:"+e.URL)
+	}
+	writeICSLine(b, "END:VEVENT")
+}
+
+// 同じイベントを取り込み直しても重複しないように、日付と名前から UID を決める
+func createICSUID(date time.Time, e event.Event) string {
+	h := sha1.Sum([]byte(date.Format("20060102") + e.Name))
+
+	return fmt.Sprintf("%s@remind.homeops", hex.EncodeToString(h[:]))
+}
+
+func escapeICSText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+
+	return r.Replace(s)
+}
+
+// 1 行が 75 オクテットを超える場合は折り返す
+func writeICSLine(b *strings.Builder, line string) {
+	limit := icsLineLimit
+	for len(line) > limit {
+		i := limit
+		// マルチバイト文字の途中で折り返さないようにする
+		for i > 0 && !utf8.RuneStart(line[i]) {
+			i--
+		}
+		b.WriteString(line[:i])
+		b.WriteString("\r\n ")
+		line = line[i:]
+		// 継続行は先頭の空白の分だけ短くなる
+		limit = icsLineLimit - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
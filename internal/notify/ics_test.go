@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"github.com/mami0tsu/homeops/internal/event"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateICS(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, tz)
+
+	tests := []struct {
+		name      string
+		schedules []event.Schedule
+		contains  []string
+	}{
+		{
+			name: "正常系/イベントが終日の予定として出力される場合",
+			schedules: []event.Schedule{
+				{
+					Date:   time.Date(2025, 1, 15, 0, 0, 0, 0, tz),
+					Events: []event.Event{{Name: "Garbage", Description: "燃える, ゴミ"}},
+				},
+			},
+			contains: []string{
+				"BEGIN:VEVENT\r\n",
+				"DTSTART;VALUE=DATE:20250115\r\n",
+				"DTEND;VALUE=DATE:20250116\r\n",
+				"SUMMARY:Garbage\r\n",
+				`DESCRIPTION:燃える\, ゴミ` + "\r\n",
+			},
+		},
+		{
+			name: "正常系/イベントが存在しない場合",
+			schedules: []event.Schedule{
+				{Date: time.Date(2025, 1, 15, 0, 0, 0, 0, tz)},
+			},
+			contains: []string{
+				"BEGIN:VCALENDAR\r\n",
+				"END:VCALENDAR\r\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			ics := string(createICS(tt.schedules, now))
+
+			for _, c := range tt.contains {
+				ta.Contains(ics, c)
+			}
+			for _, l := range strings.Split(ics, "\r\n") {
+				ta.LessOrEqual(len(l), icsLineLimit, "Each line should be folded")
+			}
+		})
+	}
+}
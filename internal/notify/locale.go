@@ -0,0 +1,97 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// 投稿する際の表記
+type Locale string
+
+const (
+	localeEn Locale = "en" // e.g. 2025-03-01 (Sat) のイベント
+	localeJa Locale = "ja" // e.g. 3月1日(土) の予定
+)
+
+var jaWeekdays = [...]string{"日", "月", "火", "水", "木", "金", "土"}
+
+func (l *Locale) UnmarshalText(text []byte) error {
+	switch v := Locale(strings.ToLower(string(text))); v {
+	case localeEn, localeJa:
+		*l = v
+		return nil
+	default:
+		return fmt.Errorf("invalid locale: %s", text)
+	}
+}
+
+func (l Locale) weekday(t time.Time) string {
+	if l == localeJa {
+		return jaWeekdays[t.Weekday()]
+	}
+
+	return t.Weekday().String()[:3]
+}
+
+func (l Locale) scheduleTitle(t time.Time) string {
+	if l == localeJa {
+		return fmt.Sprintf("%d月%d日(%s) の予定", t.Month(), t.Day(), l.weekday(t))
+	}
+
+	return fmt.Sprintf("%s (%s) のイベント", t.Format("2006-01-02"), l.weekday(t))
+}
+
+// 後から投稿する場合に、当日の通知ではないことを伝える
+func (l Locale) catchUpNotice() string {
+	if l == localeJa {
+		return "⏪ 投稿できなかった日の分を後から投稿しています"
+	}
+
+	return "⏪ Catch-up: these reminders were not posted on time"
+}
+
+// NO_EVENTS_MODE が notice の場合に、イベントがない日に表示する
+func (l Locale) emptyDayNotice() string {
+	if l == localeJa {
+		return "今日の予定はありません 🎉"
+	}
+
+	return "No events today 🎉"
+}
+
+func (l Locale) overdueTitle() string {
+	if l == localeJa {
+		return "⚠ 未対応の予定"
+	}
+
+	return "⚠ Overdue"
+}
+
+// 未対応のイベントの元の発生日
+func (l Locale) overdueOrigin(t time.Time) string {
+	if l == localeJa {
+		return fmt.Sprintf("%d月%d日(%s) の予定", t.Month(), t.Day(), l.weekday(t))
+	}
+
+	return fmt.Sprintf("Scheduled on %s (%s)", t.Format("2006-01-02"), l.weekday(t))
+}
+
+func (l Locale) intervalLabel(i event.Interval) string {
+	if l == localeJa {
+		return fmt.Sprintf("繰り返し: %s", i)
+	}
+
+	return fmt.Sprintf("Interval: %s", i)
+}
+
+// 担当者と残りの日数は、英語の場合も家族向けの表記のままとする
+func (l Locale) assigneeLabel(name string) string {
+	return fmt.Sprintf("担当: %s", name)
+}
+
+func (l Locale) daysLeftLabel(days int) string {
+	return fmt.Sprintf("あと %d 日", days)
+}
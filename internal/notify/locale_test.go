@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleTitle(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	// 2025/03/01 は土曜日
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, tz)
+
+	tests := []struct {
+		name     string
+		locale   Locale
+		expected string
+	}{
+		{
+			name:     "正常系/英語の場合",
+			locale:   localeEn,
+			expected: "2025-03-01 (Sat) のイベント",
+		},
+		{
+			name:     "正常系/日本語の場合",
+			locale:   localeJa,
+			expected: "3月1日(土) の予定",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			ta.Equal(tt.expected, tt.locale.scheduleTitle(date))
+		})
+	}
+}
+
+func TestLocaleLabels(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	// 2025/02/26 は水曜日
+	origin := time.Date(2025, 2, 26, 0, 0, 0, 0, tz)
+
+	tests := []struct {
+		name           string
+		locale         Locale
+		emptyDayNotice string
+		overdueTitle   string
+		overdueOrigin  string
+		interval       string
+	}{
+		{
+			name:           "正常系/英語の場合",
+			locale:         localeEn,
+			emptyDayNotice: "No events today 🎉",
+			overdueTitle:   "⚠ Overdue",
+			overdueOrigin:  "Scheduled on 2025-02-26 (Wed)",
+			interval:       "Interval: Weekly",
+		},
+		{
+			name:           "正常系/日本語の場合",
+			locale:         localeJa,
+			emptyDayNotice: "今日の予定はありません 🎉",
+			overdueTitle:   "⚠ 未対応の予定",
+			overdueOrigin:  "2月26日(水) の予定",
+			interval:       "繰り返し: Weekly",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			ta.Equal(tt.emptyDayNotice, tt.locale.emptyDayNotice())
+			ta.Equal(tt.overdueTitle, tt.locale.overdueTitle())
+			ta.Equal(tt.overdueOrigin, tt.locale.overdueOrigin(origin))
+			ta.Equal(tt.interval, tt.locale.intervalLabel(event.Weekly))
+			ta.Equal("担当: <@1>", tt.locale.assigneeLabel("<@1>"))
+			ta.Equal("あと 3 日", tt.locale.daysLeftLabel(3))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Package notify はイベント情報の投稿先と、投稿内容の組み立てを提供する
+package notify
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// イベント情報の投稿先
+type Sink interface {
+	Name() string
+	Post(ctx context.Context, d event.Digest) error
+}
+
+// 作成した Webhook の保存先、チャンネルごとに保存する
+type WebhookStore interface {
+	Load(ctx context.Context, channelID string) (*discord.Webhook, error) // 保存されていない場合は nil を返す
+	Save(ctx context.Context, channelID string, w *discord.Webhook) error
+}
+
+// 投稿先に共通の設定
+type Config struct {
+	HTTPClient *http.Client
+	Clock      clock.Clock
+
+	DiscordBotName   string
+	DiscordBotToken  string
+	DiscordChannelID string
+	DiscordUseThread bool         // 日付ごとのスレッドに投稿する
+	WebhookStore     WebhookStore // 指定した場合は Webhook を削除せずに保存し、次回以降の投稿で再利用する
+
+	GoogleChatWebhookURL string
+
+	NoEventsMode   NoEventsMode
+	Locale         Locale
+	ICSAttachment  bool
+	AckEnabled     bool   // 当日と未対応のイベントに対応状況を更新するボタンを付ける
+	OverdueMention string // 長期間未対応のイベントがある場合のメンション
+}
@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// 投稿する際の出力形式
+type OutputFormat string
+
+const (
+	FormatRich     OutputFormat = "rich"     // 投稿先ごとの既定の形式 (Discord の Embed, Google Chat のカード)
+	FormatMarkdown OutputFormat = "markdown" // Markdown の表
+	FormatText     OutputFormat = "text"     // プレーンテキスト
+)
+
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	switch f := OutputFormat(strings.ToLower(strings.TrimSpace(s))); f {
+	case "":
+		return FormatRich, nil
+	case FormatRich, FormatMarkdown, FormatText:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid output format: %s", s)
+	}
+}
+
+// Embed などを表示できない投稿先向けに、スケジュールを文字列に変換する
+func RenderSchedules(f OutputFormat, schedules []event.Schedule, mode NoEventsMode, loc Locale) string {
+	var blocks []string
+	for _, s := range schedules {
+		blocks = append(blocks, renderBlock(f, loc.scheduleTitle(s.Date), s.Events, mode, loc))
+	}
+
+	return strings.Join(blocks, "\n\n")
+}
+
+// 未対応のイベントがあれば先頭に表示する
+func RenderDigest(f OutputFormat, d event.Digest, mode NoEventsMode, loc Locale) string {
+	var blocks []string
+	if d.CatchUp {
+		blocks = append(blocks, loc.catchUpNotice())
+	}
+	if len(d.Overdue) > 0 {
+		blocks = append(blocks, renderBlock(f, loc.overdueTitle(), d.Overdue, mode, loc))
+	}
+	if len(d.Schedules) > 0 {
+		blocks = append(blocks, RenderSchedules(f, d.Schedules, mode, loc))
+	}
+	if notes := CreateFailureNotes(d.Failures); len(notes) > 0 {
+		blocks = append(blocks, strings.Join(notes, "\n"))
+	}
+
+	return strings.Join(blocks, "\n\n")
+}
+
+// 取得に失敗した取得元ごとに、イベントが欠けている可能性を伝える注記を返す
+func CreateFailureNotes(failures []error) []string {
+	var notes []string
+	seen := map[string]bool{}
+	for _, err := range failures {
+		name := event.FailedComponent(err)
+		if name == "" {
+			name = "some"
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		notes = append(notes, fmt.Sprintf("⚠ %s source unavailable — events may be missing", name))
+	}
+
+	return notes
+}
+
+func renderBlock(f OutputFormat, title string, events []event.Event, mode NoEventsMode, loc Locale) string {
+	switch f {
+	case FormatMarkdown:
+		return renderMarkdown(title, events, mode, loc)
+	default:
+		return renderText(title, events, mode, loc)
+	}
+}
+
+func renderMarkdown(title string, events []event.Event, mode NoEventsMode, loc Locale) string {
+	lines := []string{fmt.Sprintf("**%s**", title)}
+	if len(events) == 0 {
+		if mode == noEventsNotice {
+			lines = append(lines, loc.emptyDayNotice())
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, "| Event | Interval | Note |", "| --- | --- | --- |")
+	for _, e := range events {
+		name := escapeMarkdownCell(createFieldName(e))
+		if e.URL != "" {
+			name = fmt.Sprintf("[%s](%s)", name, e.URL)
+		}
+		note := e.Description
+		if e.Assignee != "" {
+			note = strings.TrimSpace(note + " " + loc.assigneeLabel(e.Assignee))
+		}
+		lines = append(lines, fmt.Sprintf("| %s | %s | %s |", name, e.Interval, escapeMarkdownCell(note)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func renderText(title string, events []event.Event, mode NoEventsMode, loc Locale) string {
+	lines := []string{title}
+	if len(events) == 0 && mode == noEventsNotice {
+		lines = append(lines, loc.emptyDayNotice())
+	}
+	for _, e := range events {
+		line := fmt.Sprintf("- %s (%s)", createFieldName(e), e.Interval)
+		if e.Description != "" {
+			line += ": " + e.Description
+		}
+		if e.URL != "" {
+			line += " " + e.URL
+		}
+		if e.Assignee != "" {
+			line += " " + loc.assigneeLabel(e.Assignee)
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func escapeMarkdownCell(s string) string {
+	r := strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ")
+
+	return r.Replace(s)
+}
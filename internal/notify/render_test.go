@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateFailureNotes(t *testing.T) {
+	cause := errors.New("connection refused")
+
+	tests := []struct {
+		name     string
+		failures []error
+		expected []string
+	}{
+		{
+			name:     "正常系/失敗した取得元がない場合",
+			failures: nil,
+			expected: nil,
+		},
+		{
+			name: "正常系/同じ取得元が複数回失敗した場合",
+			failures: []error{
+				event.NewSourceUnavailableError("sheet", cause),
+				event.NewSourceUnavailableError("sheet", cause),
+				event.NewSourceUnavailableError("holidays", cause),
+			},
+			expected: []string{
+				"⚠ sheet source unavailable — events may be missing",
+				"⚠ holidays source unavailable — events may be missing",
+			},
+		},
+		{
+			name:     "正常系/取得元を特定できない場合",
+			failures: []error{cause},
+			expected: []string{"⚠ some source unavailable — events may be missing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			ta.Equal(tt.expected, CreateFailureNotes(tt.failures))
+		})
+	}
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected OutputFormat
+		wantErr  bool
+	}{
+		{
+			name:     "正常系/指定しない場合は投稿先ごとの既定の形式",
+			input:    "",
+			expected: FormatRich,
+		},
+		{
+			name:     "正常系/大文字と前後の空白を無視する",
+			input:    " Markdown ",
+			expected: FormatMarkdown,
+		},
+		{
+			name:     "正常系/プレーンテキスト",
+			input:    "text",
+			expected: FormatText,
+		},
+		{
+			name:    "異常系/未対応の形式",
+			input:   "html",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			got, err := ParseOutputFormat(tt.input)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, got)
+		})
+	}
+}
+
+func TestRenderDigest(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, tz)
+	events := []event.Event{
+		{Name: "Garbage", Interval: event.Weekly, Description: "Burnable", Assignee: "<@1>"},
+		{Name: "A|B", Interval: event.Onetime, URL: "https://example.com"},
+	}
+
+	tests := []struct {
+		name     string
+		format   OutputFormat
+		digest   event.Digest
+		mode     NoEventsMode
+		expected string
+	}{
+		{
+			name:   "正常系/Markdown の表",
+			format: FormatMarkdown,
+			digest: event.Digest{Schedules: []event.Schedule{{Date: date, Events: events}}},
+			mode:   noEventsEmpty,
+			expected: "**2025-03-01 (Sat) のイベント**\n" +
+				"| Event | Interval | Note |\n" +
+				"| --- | --- | --- |\n" +
+				"| Garbage | Weekly | Burnable 担当: <@1> |\n" +
+				"| [A\\|B](https://example.com) | Onetime |  |",
+		},
+		{
+			name:   "正常系/プレーンテキスト",
+			format: FormatText,
+			digest: event.Digest{Schedules: []event.Schedule{{Date: date, Events: events}}},
+			mode:   noEventsEmpty,
+			expected: "2025-03-01 (Sat) のイベント\n" +
+				"- Garbage (Weekly): Burnable 担当: <@1>\n" +
+				"- A|B (Onetime) https://example.com",
+		},
+		{
+			name:   "正常系/未対応のイベントと取得に失敗した取得元を表示する",
+			format: FormatText,
+			digest: event.Digest{
+				Overdue:   []event.Event{{Name: "Bills", Interval: event.Monthly}},
+				Schedules: []event.Schedule{{Date: date}},
+				Failures:  []error{event.NewSourceUnavailableError("sheet", errors.New("timeout"))},
+				CatchUp:   true,
+			},
+			mode: noEventsNotice,
+			expected: "⏪ Catch-up: these reminders were not posted on time\n\n" +
+				"⚠ Overdue\n- Bills (Monthly)\n\n" +
+				"2025-03-01 (Sat) のイベント\nNo events today 🎉\n\n" +
+				"⚠ sheet source unavailable — events may be missing",
+		},
+		{
+			name:     "正常系/イベントがない日は Markdown でも見出しのみ",
+			format:   FormatMarkdown,
+			digest:   event.Digest{Schedules: []event.Schedule{{Date: date}}},
+			mode:     noEventsEmpty,
+			expected: "**2025-03-01 (Sat) のイベント**",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			ta.Equal(tt.expected, RenderDigest(tt.format, tt.digest, tt.mode, localeEn))
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Package pet はペットごとのごはん、トイレ掃除、ノミ・ダニの薬、動物病院、ワクチン接種の予定を提供する
+// 世話の担当者は chore の持ち回りで割り当て、ワクチン接種などは事前にも通知する
+package pet
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/chore"
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+const dateFormat = "2006-01-02"
+
+// 世話の種類
+type Kind string
+
+const (
+	Feeding     Kind = "feeding"     // ごはん
+	Litter      Kind = "litter"      // トイレ掃除
+	Flea        Kind = "flea"        // ノミ・ダニの薬
+	Vet         Kind = "vet"         // 動物病院
+	Vaccination Kind = "vaccination" // ワクチン接種
+)
+
+// 種類ごとの既定値
+type kindDefault struct {
+	name         string
+	emoji        string
+	every        int // 0 の場合は dates の指定が必要
+	notifyBefore int
+}
+
+var kinds = map[Kind]kindDefault{
+	Feeding:     {name: "ごはん", emoji: "🍚", every: 1},
+	Litter:      {name: "トイレ掃除", emoji: "🧹", every: 1},
+	Flea:        {name: "ノミ・ダニの薬", emoji: "💊", every: 30, notifyBefore: 1},
+	Vet:         {name: "動物病院", emoji: "🏥", notifyBefore: 1},
+	Vaccination: {name: "ワクチン接種", emoji: "💉", every: 365, notifyBefore: 14},
+}
+
+// ペットの世話
+type Task struct {
+	Kind         Kind           `json:"kind"`
+	Name         string         `json:"name,omitempty"`          // 未指定の場合は種類ごとの名前、e.g. トイレ掃除
+	Every        int            `json:"every,omitempty"`         // 間隔 (日)、未指定の場合は種類ごとの既定値
+	Start        string         `json:"start,omitempty"`         // 間隔を数え始める日、間隔が 2 日以上の場合に指定する
+	Dates        []string       `json:"dates,omitempty"`         // 予約した日、指定した場合は間隔の代わりに使う、e.g. ["2025-04-10"]
+	NotifyBefore *int           `json:"notify_before,omitempty"` // N 日前にも通知する、未指定の場合は種類ごとの既定値 (ワクチン接種は 14 日前)
+	Strategy     chore.Strategy `json:"strategy,omitempty"`      // 担当者の決め方、CHORE_ROTATIONS と同じ
+	Members      []chore.Member `json:"members,omitempty"`       // 指定した場合は担当者を持ち回りで割り当てる
+
+	start    time.Time
+	dates    []time.Time
+	rotation *chore.Rotation
+}
+
+// ペットと世話の一覧
+type Pet struct {
+	Name  string `json:"name"` // タグとしても付ける、e.g. ミケ
+	Tasks []Task `json:"tasks"`
+}
+
+// e.g. [{"name": "ミケ", "tasks": [{"kind": "feeding"}, {"kind": "litter", "members": [{"name": "alice"}, {"name": "bob"}]}, {"kind": "vaccination", "dates": ["2025-04-10"]}]}]
+type Pets []Pet
+
+func (p *Pets) UnmarshalText(text []byte) error {
+	// Pets のまま読み込むと UnmarshalText が再度呼び出されるため、スライスとして読み込む
+	var pets []Pet
+	if err := json.Unmarshal(text, &pets); err != nil {
+		return fmt.Errorf("invalid pets: %w", err)
+	}
+	names := map[string]bool{}
+	for i := range pets {
+		if err := pets[i].init(); err != nil {
+			return fmt.Errorf("invalid pets: %w", err)
+		}
+		if names[pets[i].Name] {
+			return fmt.Errorf("invalid pets: duplicate name: %s", pets[i].Name)
+		}
+		names[pets[i].Name] = true
+	}
+	*p = pets
+
+	return nil
+}
+
+func (p *Pet) init() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return fmt.Errorf("name is blank")
+	}
+	if len(p.Tasks) == 0 {
+		return fmt.Errorf("%s: tasks are empty", p.Name)
+	}
+	seen := map[string]bool{}
+	for i := range p.Tasks {
+		t := &p.Tasks[i]
+		if err := t.init(p.Name); err != nil {
+			return fmt.Errorf("%s: %w", p.Name, err)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("%s: duplicate task: %s", p.Name, t.Name)
+		}
+		seen[t.Name] = true
+	}
+
+	return nil
+}
+
+func (t *Task) init(pet string) error {
+	d, ok := kinds[t.Kind]
+	if !ok {
+		return fmt.Errorf("invalid kind: %s", t.Kind)
+	}
+	if t.Name = strings.TrimSpace(t.Name); t.Name == "" {
+		t.Name = d.name
+	}
+	if t.NotifyBefore == nil {
+		t.NotifyBefore = &d.notifyBefore
+	}
+	if *t.NotifyBefore < 0 {
+		return fmt.Errorf("%s: invalid notify_before: %d", t.Name, *t.NotifyBefore)
+	}
+
+	if len(t.Dates) > 0 {
+		for _, s := range t.Dates {
+			date, err := time.Parse(dateFormat, s)
+			if err != nil {
+				return fmt.Errorf("%s: invalid date: %s", t.Name, s)
+			}
+			t.dates = append(t.dates, date)
+		}
+	} else {
+		if t.Every == 0 {
+			t.Every = d.every
+		}
+		if t.Every <= 0 {
+			return fmt.Errorf("%s: every or dates is required", t.Name)
+		}
+		if t.Start != "" {
+			start, err := time.Parse(dateFormat, t.Start)
+			if err != nil {
+				return fmt.Errorf("%s: invalid start: %s", t.Name, t.Start)
+			}
+			t.start = start
+		} else if t.Every > 1 {
+			return fmt.Errorf("%s: start is required when every is more than 1", t.Name)
+		}
+	}
+
+	if len(t.Members) > 0 {
+		r, err := chore.NewRotation(t.EventName(pet), t.Strategy, t.Members)
+		if err != nil {
+			return err
+		}
+		t.rotation = &r
+	}
+
+	return nil
+}
+
+// 投稿するイベントの名前、持ち回りの設定もこの名前で探す、e.g. ミケ: トイレ掃除
+func (t Task) EventName(pet string) string {
+	return fmt.Sprintf("%s: %s", pet, t.Name)
+}
+
+// t が世話をする日かどうか
+func (t Task) On(date time.Time) bool {
+	if len(t.dates) > 0 {
+		return slices.ContainsFunc(t.dates, func(d time.Time) bool { return event.DaysBetween(d, date) == 0 })
+	}
+	if t.start.IsZero() {
+		return true
+	}
+	days := event.DaysBetween(t.start, date)
+
+	return days >= 0 && days%t.Every == 0
+}
+
+// 担当者を持ち回りで割り当てる世話の設定を返す、CHORE_ROTATIONS と合わせて使う
+func (p Pets) Rotations() chore.Rotations {
+	var rotations chore.Rotations
+	for _, pet := range p {
+		for _, t := range pet.Tasks {
+			if t.rotation != nil {
+				rotations = append(rotations, *t.rotation)
+			}
+		}
+	}
+
+	return rotations
+}
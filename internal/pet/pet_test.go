@@ -0,0 +1,124 @@
+package pet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/chore"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, jst)
+}
+
+func TestPetsUnmarshalText(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "正常系/既定値を使う", input: `[{"name": "ミケ", "tasks": [{"kind": "feeding"}, {"kind": "litter"}]}]`},
+		{name: "正常系/予約した日を指定する", input: `[{"name": "ミケ", "tasks": [{"kind": "vet", "dates": ["2025-04-10"]}]}]`},
+		{name: "異常系/名前が空", input: `[{"tasks": [{"kind": "feeding"}]}]`, wantErr: true},
+		{name: "異常系/世話が空", input: `[{"name": "ミケ", "tasks": []}]`, wantErr: true},
+		{name: "異常系/不明な種類", input: `[{"name": "ミケ", "tasks": [{"kind": "walk"}]}]`, wantErr: true},
+		{name: "異常系/動物病院の日がない", input: `[{"name": "ミケ", "tasks": [{"kind": "vet"}]}]`, wantErr: true},
+		{name: "異常系/開始日がない", input: `[{"name": "ミケ", "tasks": [{"kind": "flea"}]}]`, wantErr: true},
+		{name: "異常系/日付が不正", input: `[{"name": "ミケ", "tasks": [{"kind": "vaccination", "dates": ["4/10"]}]}]`, wantErr: true},
+		{name: "異常系/負の事前通知", input: `[{"name": "ミケ", "tasks": [{"kind": "feeding", "notify_before": -1}]}]`, wantErr: true},
+		{name: "異常系/世話が重複している", input: `[{"name": "ミケ", "tasks": [{"kind": "feeding"}, {"kind": "feeding"}]}]`, wantErr: true},
+		{name: "異常系/ペットが重複している", input: `[{"name": "ミケ", "tasks": [{"kind": "feeding"}]}, {"name": "ミケ", "tasks": [{"kind": "litter"}]}]`, wantErr: true},
+		{name: "異常系/担当者が重複している", input: `[{"name": "ミケ", "tasks": [{"kind": "litter", "members": [{"name": "alice"}, {"name": "alice"}]}]}]`, wantErr: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Pets
+			err := got.UnmarshalText([]byte(tt.input))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPetsRotations(t *testing.T) {
+	ta := assert.New(t)
+
+	var pets Pets
+	require.NoError(t, pets.UnmarshalText([]byte(`[
+		{"name": "ミケ", "tasks": [{"kind": "feeding"}, {"kind": "litter", "members": [{"name": "alice", "mention": "<@1>"}, {"name": "bob"}]}]},
+		{"name": "ポチ", "tasks": [{"kind": "feeding", "name": "朝ごはん", "strategy": "weighted", "members": [{"name": "alice", "weight": 2}, {"name": "bob"}]}]}
+	]`)))
+	ta.Equal(chore.Rotations{
+		{Event: "ミケ: トイレ掃除", Strategy: chore.RoundRobin, Members: []chore.Member{{Name: "alice", Mention: "<@1>", Weight: 1}, {Name: "bob", Weight: 1}}},
+		{Event: "ポチ: 朝ごはん", Strategy: chore.Weighted, Members: []chore.Member{{Name: "alice", Weight: 2}, {Name: "bob", Weight: 1}}},
+	}, pets.Rotations())
+}
+
+func TestSourceFetch(t *testing.T) {
+	var pets Pets
+	require.NoError(t, pets.UnmarshalText([]byte(`[
+		{"name": "ミケ", "tasks": [
+			{"kind": "litter"},
+			{"kind": "flea", "start": "2025-03-01"},
+			{"kind": "vaccination", "dates": ["2025-04-10"]},
+			{"kind": "vet", "dates": ["2025-03-20"], "notify_before": 0}
+		]}
+	]`)))
+	fetch := func(t *testing.T, d time.Time) []event.Event {
+		events, err := NewSource(pets).Fetch(context.Background(), d)
+		require.NoError(t, err)
+		return events
+	}
+	names := func(events []event.Event) []string {
+		var s []string
+		for _, e := range events {
+			s = append(s, e.Name)
+		}
+		return s
+	}
+
+	t.Run("正常系/毎日の世話に種類とペットのタグを付ける", func(t *testing.T) {
+		ta := assert.New(t)
+
+		events := fetch(t, date(2025, 3, 2))
+		ta.Equal([]string{"ミケ: トイレ掃除"}, names(events))
+		ta.Equal(event.Daily, events[0].Interval)
+		ta.Equal([]string{Tag, "ミケ"}, events[0].Tags)
+	})
+
+	t.Run("正常系/間隔ごとの世話と前日の通知", func(t *testing.T) {
+		ta := assert.New(t)
+
+		ta.Equal([]string{"ミケ: トイレ掃除", "ミケ: ノミ・ダニの薬"}, names(fetch(t, date(2025, 3, 31))))
+		events := fetch(t, date(2025, 3, 30))
+		ta.Equal([]string{"ミケ: トイレ掃除", "ミケ: ノミ・ダニの薬"}, names(events))
+		ta.Equal(1, events[1].LeadDays)
+	})
+
+	t.Run("正常系/ワクチン接種は 14 日前にも通知する", func(t *testing.T) {
+		ta := assert.New(t)
+
+		events := fetch(t, date(2025, 3, 27))
+		ta.Equal([]string{"ミケ: トイレ掃除", "ミケ: ワクチン接種"}, names(events))
+		ta.Equal(14, events[1].LeadDays)
+		ta.Equal(event.Onetime, events[1].Interval)
+		ta.Equal([]string{"ミケ: トイレ掃除", "ミケ: ワクチン接種"}, names(fetch(t, date(2025, 4, 10))))
+	})
+
+	t.Run("正常系/事前通知を無効にする", func(t *testing.T) {
+		ta := assert.New(t)
+
+		ta.Equal([]string{"ミケ: トイレ掃除"}, names(fetch(t, date(2025, 3, 19))))
+		ta.Equal([]string{"ミケ: トイレ掃除", "ミケ: 動物病院"}, names(fetch(t, date(2025, 3, 20))))
+	})
+}
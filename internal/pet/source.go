@@ -0,0 +1,58 @@
+package pet
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// イベントに付けるタグ、ペットの名前もタグとして付ける
+const Tag = "pet"
+
+// ペットごとの世話を返す取得元
+// notify_before を指定した世話は、N 日前にも通知する
+type Source struct {
+	pets Pets
+}
+
+func NewSource(pets Pets) *Source {
+	return &Source{pets: pets}
+}
+
+func (s *Source) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	var events []event.Event
+	for _, p := range s.pets {
+		for _, task := range p.Tasks {
+			if task.On(t) {
+				events = append(events, task.event(p.Name, 0))
+			}
+			// N 日後の世話も事前に通知する
+			if n := *task.NotifyBefore; n > 0 && task.On(t.AddDate(0, 0, n)) {
+				events = append(events, task.event(p.Name, n))
+			}
+		}
+	}
+
+	return events, nil
+}
+
+func (t Task) event(pet string, leadDays int) event.Event {
+	interval := event.Onetime
+	switch {
+	case len(t.dates) > 0:
+	case t.Every == 1:
+		interval = event.Daily
+	case t.Every == 7:
+		interval = event.Weekly
+	}
+
+	return event.Event{
+		Name:     t.EventName(pet),
+		Interval: interval,
+		Emoji:    kinds[t.Kind].emoji,
+		LeadDays: leadDays,
+		Tags:     []string{Tag, strings.ToLower(pet)},
+	}
+}
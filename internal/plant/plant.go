@@ -0,0 +1,98 @@
+// Package plant は観葉植物ごとの間隔で水やりを知らせる
+// 土壌水分センサーの値を記録している場合は、土が湿っている植物の水やりを知らせない
+package plant
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+const dateFormat = "2006-01-02"
+
+// 水やりをする植物
+type Plant struct {
+	Name     string  `json:"name"`                // e.g. モンステラ
+	Every    int     `json:"every"`               // 水やりの間隔 (日)
+	Start    string  `json:"start"`               // 間隔を数え始める日、e.g. 2025-03-01
+	Sensor   string  `json:"sensor,omitempty"`    // 土壌水分センサーの ID、指定した場合は土が湿っている日は知らせない
+	DryBelow float64 `json:"dry_below,omitempty"` // 土壌水分 (%) がこの値を下回ったら乾いているとみなす、未指定の場合は 30
+
+	start time.Time
+}
+
+// 乾いているとみなす土壌水分 (%) の既定値
+const defaultDryBelow = 30
+
+// t が水やりの日かどうか
+func (p Plant) IsDue(t time.Time) bool {
+	days := event.DaysBetween(p.start, t)
+
+	return days >= 0 && days%p.Every == 0
+}
+
+// 土壌水分から土が湿っているかどうかを返す
+func (p Plant) IsWet(moisture float64) bool {
+	return moisture >= p.DryBelow
+}
+
+func (p *Plant) init() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return fmt.Errorf("name is blank")
+	}
+	if p.Every <= 0 {
+		return fmt.Errorf("%s: invalid every: %d", p.Name, p.Every)
+	}
+	start, err := time.Parse(dateFormat, p.Start)
+	if err != nil {
+		return fmt.Errorf("%s: invalid start: %s", p.Name, p.Start)
+	}
+	p.start = start
+	if p.DryBelow < 0 || p.DryBelow > 100 {
+		return fmt.Errorf("%s: invalid dry_below: %g", p.Name, p.DryBelow)
+	}
+	if p.DryBelow == 0 {
+		p.DryBelow = defaultDryBelow
+	}
+
+	return nil
+}
+
+// e.g. [{"name": "モンステラ", "every": 7, "start": "2025-03-01", "sensor": "living-1"}, {"name": "サボテン", "every": 21, "start": "2025-03-01"}]
+type Plants []Plant
+
+func (p *Plants) UnmarshalText(text []byte) error {
+	// Plants のまま読み込むと UnmarshalText が再度呼び出されるため、スライスとして読み込む
+	var plants []Plant
+	if err := json.Unmarshal(text, &plants); err != nil {
+		return fmt.Errorf("invalid plants: %w", err)
+	}
+	names := map[string]bool{}
+	for i := range plants {
+		if err := plants[i].init(); err != nil {
+			return fmt.Errorf("invalid plants: %w", err)
+		}
+		if names[plants[i].Name] {
+			return fmt.Errorf("invalid plants: duplicate name: %s", plants[i].Name)
+		}
+		names[plants[i].Name] = true
+	}
+	*p = plants
+
+	return nil
+}
+
+// センサーを指定した植物があるかどうか
+func (p Plants) HasSensor() bool {
+	for _, v := range p {
+		if v.Sensor != "" {
+			return true
+		}
+	}
+
+	return false
+}
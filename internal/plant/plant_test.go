@@ -0,0 +1,143 @@
+package plant
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, jst)
+}
+
+func TestPlantsUnmarshalText(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "正常系/センサーあり", input: `[{"name": "モンステラ", "every": 7, "start": "2025-03-01", "sensor": "living-1", "dry_below": 25}]`},
+		{name: "正常系/センサーなし", input: `[{"name": "サボテン", "every": 21, "start": "2025-03-01"}]`},
+		{name: "異常系/名前が空", input: `[{"every": 7, "start": "2025-03-01"}]`, wantErr: true},
+		{name: "異常系/間隔が 0", input: `[{"name": "モンステラ", "start": "2025-03-01"}]`, wantErr: true},
+		{name: "異常系/開始日が不正", input: `[{"name": "モンステラ", "every": 7, "start": "3/1"}]`, wantErr: true},
+		{name: "異常系/閾値が範囲外", input: `[{"name": "モンステラ", "every": 7, "start": "2025-03-01", "dry_below": 120}]`, wantErr: true},
+		{name: "異常系/名前が重複している", input: `[{"name": "モンステラ", "every": 7, "start": "2025-03-01"}, {"name": "モンステラ", "every": 3, "start": "2025-03-01"}]`, wantErr: true},
+		{name: "異常系/JSON ではない", input: `モンステラ`, wantErr: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Plants
+			err := got.UnmarshalText([]byte(tt.input))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, got, 1)
+		})
+	}
+
+	t.Run("正常系/閾値の既定値を補う", func(t *testing.T) {
+		var got Plants
+		require.NoError(t, got.UnmarshalText([]byte(`[{"name": "サボテン", "every": 21, "start": "2025-03-01"}]`)))
+		assert.Equal(t, float64(defaultDryBelow), got[0].DryBelow)
+		assert.False(t, got.HasSensor())
+	})
+}
+
+func TestPlantIsDue(t *testing.T) {
+	ta := assert.New(t)
+
+	var plants Plants
+	require.NoError(t, plants.UnmarshalText([]byte(`[{"name": "モンステラ", "every": 7, "start": "2025-03-01"}]`)))
+	p := plants[0]
+	ta.True(p.IsDue(date(2025, 3, 1)))
+	ta.True(p.IsDue(date(2025, 3, 8)))
+	ta.True(p.IsDue(date(2025, 4, 5)))
+	ta.False(p.IsDue(date(2025, 3, 7)))
+	ta.False(p.IsDue(date(2025, 2, 22)))
+}
+
+type fakeReader struct {
+	readings []Reading
+	err      error
+}
+
+func (r fakeReader) Readings(ctx context.Context) ([]Reading, error) {
+	return r.readings, r.err
+}
+
+func TestSourceFetch(t *testing.T) {
+	var plants Plants
+	require.NoError(t, plants.UnmarshalText([]byte(`[
+		{"name": "モンステラ", "every": 7, "start": "2025-03-01", "sensor": "living-1"},
+		{"name": "サボテン", "every": 7, "start": "2025-03-01"}
+	]`)))
+	today := date(2025, 3, 8)
+	since := today.Add(-24 * time.Hour)
+	names := func(t *testing.T, r Reader, d time.Time) []string {
+		events, err := NewSource(plants, r, since, today).Fetch(context.Background(), d)
+		require.NoError(t, err)
+		var s []string
+		for _, e := range events {
+			s = append(s, e.Name)
+		}
+		return s
+	}
+
+	t.Run("正常系/土が湿っている植物を除く", func(t *testing.T) {
+		r := fakeReader{readings: []Reading{{Sensor: "living-1", Moisture: 45, MeasuredAt: today.Add(-time.Hour)}}}
+		assert.Equal(t, []string{"サボテンの水やり"}, names(t, r, today))
+	})
+
+	t.Run("正常系/土が乾いている場合は値を表示する", func(t *testing.T) {
+		r := fakeReader{readings: []Reading{{Sensor: "living-1", Moisture: 12.5, MeasuredAt: today.Add(-time.Hour)}}}
+		events, err := NewSource(plants, r, since, today).Fetch(context.Background(), today)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, "土壌水分 12.5% (3/7 23:00 時点)", events[0].Description)
+		assert.Equal(t, []string{Tag}, events[0].Tags)
+	})
+
+	t.Run("正常系/古い値は使わない", func(t *testing.T) {
+		r := fakeReader{readings: []Reading{{Sensor: "living-1", Moisture: 45, MeasuredAt: since.Add(-time.Minute)}}}
+		assert.Equal(t, []string{"モンステラの水やり", "サボテンの水やり"}, names(t, r, today))
+	})
+
+	t.Run("正常系/今後のイベントはセンサーの値で除かない", func(t *testing.T) {
+		r := fakeReader{readings: []Reading{{Sensor: "living-1", Moisture: 45, MeasuredAt: today}}}
+		assert.Equal(t, []string{"モンステラの水やり", "サボテンの水やり"}, names(t, r, date(2025, 3, 15)))
+		assert.Empty(t, names(t, r, date(2025, 3, 9)))
+	})
+
+	t.Run("正常系/センサーの値を取得できない場合も知らせる", func(t *testing.T) {
+		r := fakeReader{err: errors.New("unavailable")}
+		assert.Equal(t, []string{"モンステラの水やり", "サボテンの水やり"}, names(t, r, today))
+		assert.Equal(t, []string{"モンステラの水やり", "サボテンの水やり"}, names(t, nil, today))
+	})
+}
+
+func TestReading(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.NoError(Reading{Sensor: "living-1", Moisture: 42.5}.Validate())
+	ta.Error(Reading{Moisture: 42.5}.Validate())
+	ta.Error(Reading{Sensor: "living-1", Moisture: -1}.Validate())
+
+	r := Reading{Sensor: "living-1", Moisture: 42.5, MeasuredAt: time.Unix(1740787200, 0)}
+	got, err := parseReading(readingItem(r))
+	ta.NoError(err)
+	ta.Equal(r, got)
+
+	_, err = parseReading(dynamodb.Item{"sk": dynamodb.S("living-1"), "moisture": dynamodb.S("wet")})
+	ta.Error(err)
+}
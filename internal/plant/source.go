@@ -0,0 +1,72 @@
+package plant
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// イベントに付けるタグ、朝のスケジュールのみで通知する場合はプロファイルの tags に指定する
+const Tag = "plant"
+
+// センサーごとの最新の値を取得する
+type Reader interface {
+	Readings(ctx context.Context) ([]Reading, error)
+}
+
+// 植物ごとの間隔で水やりのイベントを返す取得元
+// 当日のイベントはセンサーの値を確認し、since 以降に土が湿っていると記録された植物は除く
+// 今後のイベントは土の乾き具合がわからないため、間隔のみで返す
+type Source struct {
+	plants Plants
+	reader Reader // センサーの値を記録しない場合は nil
+	since  time.Time
+	today  time.Time
+}
+
+func NewSource(plants Plants, r Reader, since, today time.Time) *Source {
+	return &Source{plants: plants, reader: r, since: since, today: today}
+}
+
+func (s *Source) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	var readings map[string]Reading
+	if t.Equal(s.today) && s.reader != nil && s.plants.HasSensor() {
+		// センサーの値を取得できない場合も、水やりを忘れないように知らせる
+		rs, err := s.reader.Readings(ctx)
+		if err != nil {
+			slog.Warn("failed to get soil moisture", slog.Any("error", err))
+		}
+		readings = make(map[string]Reading, len(rs))
+		for _, r := range rs {
+			if !r.MeasuredAt.Before(s.since) {
+				readings[r.Sensor] = r
+			}
+		}
+	}
+
+	var events []event.Event
+	for _, p := range s.plants {
+		if !p.IsDue(t) {
+			continue
+		}
+		e := event.Event{
+			Name:     fmt.Sprintf("%sの水やり", p.Name),
+			Interval: event.Onetime,
+			Emoji:    "🪴",
+			Tags:     []string{Tag},
+		}
+		if r, ok := readings[p.Sensor]; ok && p.Sensor != "" {
+			if p.IsWet(r.Moisture) {
+				slog.Info("skipped watering", slog.String("plant", p.Name), slog.Float64("moisture", r.Moisture))
+				continue
+			}
+			e.Description = fmt.Sprintf("土壌水分 %g%% (%s 時点)", r.Moisture, r.MeasuredAt.In(t.Location()).Format("1/2 15:04"))
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,92 @@
+package plant
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+)
+
+const partitionKey = "plant"
+
+// 古い値で判定しないように、更新されなくなったセンサーの値は TTL で削除する
+const retention = 7 * 24 * time.Hour
+
+// 土壌水分センサーから受け付けた値
+type Reading struct {
+	Sensor     string    `json:"sensor"`   // e.g. living-1
+	Moisture   float64   `json:"moisture"` // 土壌水分 (%)
+	MeasuredAt time.Time `json:"-"`        // 受け付けた日時
+}
+
+func (r Reading) Validate() error {
+	if strings.TrimSpace(r.Sensor) == "" {
+		return fmt.Errorf("sensor is blank")
+	}
+	if r.Moisture < 0 || r.Moisture > 100 {
+		return fmt.Errorf("invalid moisture: %g", r.Moisture)
+	}
+
+	return nil
+}
+
+// センサーごとに最新の値を DynamoDB に保存する
+// テーブルのキーは pk (パーティションキー) と sk (ソートキー、センサーの ID) とし、ACK_TABLE_NAME と同じテーブルでもよい
+type Store struct {
+	client *dynamodb.Client
+	table  string
+}
+
+func NewStore(client *dynamodb.Client, table string) *Store {
+	return &Store{client: client, table: table}
+}
+
+func readingItem(r Reading) dynamodb.Item {
+	return dynamodb.Item{
+		"pk":          dynamodb.S(partitionKey),
+		"sk":          dynamodb.S(r.Sensor),
+		"moisture":    dynamodb.S(strconv.FormatFloat(r.Moisture, 'f', -1, 64)),
+		"measured_at": dynamodb.N(r.MeasuredAt.Unix()),
+		"expires_at":  dynamodb.N(r.MeasuredAt.Add(retention).Unix()),
+	}
+}
+
+// センサーの値を上書きする
+func (s *Store) Put(ctx context.Context, r Reading) error {
+	return s.client.PutItem(ctx, s.table, readingItem(r), "", nil)
+}
+
+// センサーごとの最新の値を返す
+func (s *Store) Readings(ctx context.Context) ([]Reading, error) {
+	items, err := s.client.Query(ctx, s.table, "pk = :pk", dynamodb.Item{":pk": dynamodb.S(partitionKey)})
+	if err != nil {
+		return nil, err
+	}
+
+	readings := make([]Reading, 0, len(items))
+	for _, item := range items {
+		r, err := parseReading(item)
+		if err != nil {
+			return nil, err
+		}
+		readings = append(readings, r)
+	}
+
+	return readings, nil
+}
+
+func parseReading(item dynamodb.Item) (Reading, error) {
+	moisture, err := strconv.ParseFloat(item.Str("moisture"), 64)
+	if err != nil {
+		return Reading{}, fmt.Errorf("invalid moisture: %s", item.Str("moisture"))
+	}
+
+	return Reading{
+		Sensor:     item.Str("sk"),
+		Moisture:   moisture,
+		MeasuredAt: time.Unix(item.Num("measured_at"), 0),
+	}, nil
+}
@@ -0,0 +1,76 @@
+package pricewatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var ErrPriceNotFound = errors.New("price not found")
+
+// 商品ページに含まれる価格の表記、先に一致したものを使う
+// 構造化データを優先し、ない場合は Amazon の表示価格を使う
+var pricePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)<meta[^>]+(?:property="product:price:amount"|itemprop="price")[^>]+content="([0-9.,]+)"`),
+	regexp.MustCompile(`(?i)<meta[^>]+content="([0-9.,]+)"[^>]+(?:property="product:price:amount"|itemprop="price")`),
+	regexp.MustCompile(`"price"\s*:\s*"?([0-9.,]+)"?`),
+	regexp.MustCompile(`class="a-price-whole">([0-9,]+)<`),
+}
+
+// 商品ページから価格を取得する
+type Fetcher struct {
+	client *http.Client
+}
+
+func NewFetcher(client *http.Client) *Fetcher {
+	return &Fetcher{client: client}
+}
+
+func (f *Fetcher) Price(ctx context.Context, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	// ブラウザ以外からのアクセスを拒否するサイトがあるため、日本語のページを要求する
+	req.Header.Set("Accept-Language", "ja-JP,ja;q=0.9")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; homeops-pricewatch)")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("returned status %d", resp.StatusCode)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return 0, err
+	}
+
+	return parsePrice(string(b))
+}
+
+// 円の価格を返す、小数点以下は切り捨てる
+func parsePrice(page string) (int, error) {
+	for _, p := range pricePatterns {
+		m := p.FindStringSubmatch(page)
+		if m == nil {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+		if err != nil || v <= 0 {
+			continue
+		}
+		return int(math.Floor(v)), nil
+	}
+
+	return 0, ErrPriceNotFound
+}
@@ -0,0 +1,168 @@
+// Package pricewatch は欲しいものの商品ページを登録し、価格が下がったことを判定する
+package pricewatch
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+)
+
+// ASIN もしくは ISBN-10、e.g. B0ABCDEFGH, 4101010013
+var asinPattern = regexp.MustCompile(`^(B0[0-9A-Z]{8}|[0-9]{9}[0-9X])$`)
+
+const amazonURL = "https://www.amazon.co.jp/dp/"
+
+// 商品ページの URL を返す、ASIN を指定した場合は Amazon の商品ページとする
+func NormalizeURL(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if asin := strings.ToUpper(s); asinPattern.MatchString(asin) {
+		return amazonURL + asin, nil
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid url: %s", s)
+	}
+	// 追跡用のパラメータなどで同じ商品が重複して登録されないように、Amazon の商品ページは ASIN のみにする
+	if strings.Contains(u.Host, "amazon.") {
+		for _, p := range strings.Split(u.Path, "/") {
+			if asinPattern.MatchString(p) {
+				return amazonURL + p, nil
+			}
+		}
+	}
+	u.Fragment = ""
+
+	return u.String(), nil
+}
+
+// 登録した商品
+type Watch struct {
+	URL       string
+	Name      string // e.g. ヘッドホン
+	Target    int    // 目標の価格 (円)、下回ったら登録した人にメンションする
+	LastPrice int    // 最後に取得した価格 (円)、未取得の場合は 0
+	AddedBy   string
+	AddedByID string // 登録した人の Discord のユーザー ID
+}
+
+// 表示用の名前、名前がなければ URL とする
+func (w Watch) Label() string {
+	if w.Name != "" {
+		return w.Name
+	}
+
+	return w.URL
+}
+
+// 価格の変化
+type Change int
+
+const (
+	Unchanged Change = iota
+	Dropped          // 前回より下がった
+	Reached          // 目標の価格以下になった
+)
+
+// 前回の価格と比べて価格の変化を返す
+// 目標の価格以下になった場合は、前回の価格が目標を上回っていたときのみ Reached とする
+func (w Watch) Evaluate(price int) Change {
+	switch {
+	case price <= w.Target && (w.LastPrice == 0 || w.LastPrice > w.Target):
+		return Reached
+	case w.LastPrice != 0 && price < w.LastPrice:
+		return Dropped
+	default:
+		return Unchanged
+	}
+}
+
+const partitionKey = "pricewatch"
+
+// 登録した商品を DynamoDB に保存する
+// テーブルのキーは pk (パーティションキー) と sk (ソートキー、商品ページの URL) とする
+type Store struct {
+	client *dynamodb.Client
+	table  string
+}
+
+func NewStore(client *dynamodb.Client, table string) *Store {
+	return &Store{client: client, table: table}
+}
+
+// 商品を登録する、登録済みの場合は false を返す
+func (s *Store) Add(ctx context.Context, w Watch, now time.Time) (bool, error) {
+	item := dynamodb.Item{
+		"pk":          dynamodb.S(partitionKey),
+		"sk":          dynamodb.S(w.URL),
+		"name":        dynamodb.S(w.Name),
+		"target":      dynamodb.N(int64(w.Target)),
+		"added_by":    dynamodb.S(w.AddedBy),
+		"added_by_id": dynamodb.S(w.AddedByID),
+		"added_at":    dynamodb.N(now.Unix()),
+	}
+	if err := s.client.PutItem(ctx, s.table, item, "attribute_not_exists(pk)", nil); err != nil {
+		if dynamodb.IsConditionalCheckFailed(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *Store) List(ctx context.Context) ([]Watch, error) {
+	items, err := s.client.Query(ctx, s.table, "pk = :pk", dynamodb.Item{":pk": dynamodb.S(partitionKey)})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseWatches(items), nil
+}
+
+func (s *Store) UpdatePrice(ctx context.Context, w Watch, price int) error {
+	return s.client.UpdateItem(ctx, s.table, dynamodb.Item{
+		"pk": dynamodb.S(partitionKey),
+		"sk": dynamodb.S(w.URL),
+	}, "SET last_price = :p", dynamodb.Item{":p": dynamodb.N(int64(price))})
+}
+
+func (s *Store) Delete(ctx context.Context, w Watch) error {
+	return s.client.DeleteItem(ctx, s.table, dynamodb.Item{
+		"pk": dynamodb.S(partitionKey),
+		"sk": dynamodb.S(w.URL),
+	})
+}
+
+func parseWatches(items []dynamodb.Item) []Watch {
+	watches := make([]Watch, 0, len(items))
+	for _, item := range items {
+		watches = append(watches, Watch{
+			URL:       item.Str("sk"),
+			Name:      item.Str("name"),
+			Target:    int(item.Num("target")),
+			LastPrice: int(item.Num("last_price")),
+			AddedBy:   item.Str("added_by"),
+			AddedByID: item.Str("added_by_id"),
+		})
+	}
+
+	return watches
+}
+
+// URL、ASIN もしくは名前で商品を探す
+func Find(watches []Watch, target string) (Watch, bool) {
+	target = strings.TrimSpace(target)
+	u, _ := NormalizeURL(target)
+	for _, w := range watches {
+		if (u != "" && w.URL == u) || strings.EqualFold(w.Name, target) {
+			return w, true
+		}
+	}
+
+	return Watch{}, false
+}
@@ -0,0 +1,147 @@
+package pricewatch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "正常系/ASIN を指定した場合", input: " b0abcdefgh ", expected: "https://www.amazon.co.jp/dp/B0ABCDEFGH"},
+		{name: "正常系/Amazon の商品ページは ASIN のみにする", input: "https://www.amazon.co.jp/Headphones/dp/B0ABCDEFGH/ref=sr_1_1?keywords=x", expected: "https://www.amazon.co.jp/dp/B0ABCDEFGH"},
+		{name: "正常系/その他の URL はフラグメントを除く", input: "https://shop.example.com/items/1?color=black#reviews", expected: "https://shop.example.com/items/1?color=black"},
+		{name: "異常系/URL ではない場合", input: "ヘッドホン", wantErr: true},
+		{name: "異常系/http 以外の場合", input: "ftp://example.com/items/1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			u, err := NormalizeURL(tt.input)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, u)
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		watch    Watch
+		price    int
+		expected Change
+	}{
+		{name: "正常系/初めて取得して目標以下の場合", watch: Watch{Target: 10000}, price: 9800, expected: Reached},
+		{name: "正常系/初めて取得して目標を上回る場合", watch: Watch{Target: 10000}, price: 12000, expected: Unchanged},
+		{name: "正常系/目標以下になった場合", watch: Watch{Target: 10000, LastPrice: 12000}, price: 10000, expected: Reached},
+		{name: "正常系/目標以下のまま下がった場合", watch: Watch{Target: 10000, LastPrice: 9800}, price: 9500, expected: Dropped},
+		{name: "正常系/目標を上回ったまま下がった場合", watch: Watch{Target: 10000, LastPrice: 12000}, price: 11000, expected: Dropped},
+		{name: "正常系/上がった場合", watch: Watch{Target: 10000, LastPrice: 11000}, price: 12000, expected: Unchanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.watch.Evaluate(tt.price))
+		})
+	}
+}
+
+func TestParseWatches(t *testing.T) {
+	ta := assert.New(t)
+
+	watches := parseWatches([]dynamodb.Item{{
+		"pk":          dynamodb.S("pricewatch"),
+		"sk":          dynamodb.S("https://www.amazon.co.jp/dp/B0ABCDEFGH"),
+		"name":        dynamodb.S("ヘッドホン"),
+		"target":      dynamodb.N(10000),
+		"last_price":  dynamodb.N(12000),
+		"added_by":    dynamodb.S("alice"),
+		"added_by_id": dynamodb.S("123"),
+	}})
+
+	ta.Equal([]Watch{{URL: "https://www.amazon.co.jp/dp/B0ABCDEFGH", Name: "ヘッドホン", Target: 10000, LastPrice: 12000, AddedBy: "alice", AddedByID: "123"}}, watches)
+}
+
+func TestFind(t *testing.T) {
+	ta := assert.New(t)
+
+	watches := []Watch{{URL: "https://www.amazon.co.jp/dp/B0ABCDEFGH", Name: "ヘッドホン"}, {URL: "https://shop.example.com/items/1"}}
+
+	w, ok := Find(watches, "B0ABCDEFGH")
+	ta.True(ok)
+	ta.Equal(watches[0], w)
+
+	w, ok = Find(watches, "ヘッドホン")
+	ta.True(ok)
+	ta.Equal(watches[0], w)
+
+	_, ok = Find(watches, "イヤホン")
+	ta.False(ok)
+}
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     string
+		expected int
+		wantErr  bool
+	}{
+		{name: "正常系/OGP の価格", page: `<meta property="product:price:amount" content="12,800">`, expected: 12800},
+		{name: "正常系/属性の順序が逆の場合", page: `<meta content="9800.0" itemprop="price">`, expected: 9800},
+		{name: "正常系/JSON-LD の価格", page: `<script type="application/ld+json">{"offers": {"price": 4980, "priceCurrency": "JPY"}}</script>`, expected: 4980},
+		{name: "正常系/Amazon の表示価格", page: `<span class="a-price-whole">3,480</span>`, expected: 3480},
+		{name: "異常系/価格がない場合", page: `<html>在庫切れ</html>`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			price, err := parsePrice(tt.page)
+			if tt.wantErr {
+				ta.ErrorIs(err, ErrPriceNotFound)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, price)
+		})
+	}
+}
+
+func TestFetcherPrice(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/items/1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`<meta property="product:price:amount" content="12800">`))
+	}))
+	defer srv.Close()
+	f := NewFetcher(srv.Client())
+
+	price, err := f.Price(context.Background(), srv.URL+"/items/1")
+	tr.NoError(err)
+	ta.Equal(12800, price)
+
+	_, err = f.Price(context.Background(), srv.URL+"/items/2")
+	ta.Error(err)
+}
@@ -0,0 +1,155 @@
+// Package probe は自宅のサービスに HTTP や TCP で接続し、応答するかを確認する
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type Type string
+
+const (
+	TypeHTTP Type = "http" // URL に GET し、ステータスコードを確認する
+	TypeTCP  Type = "tcp"  // Address に接続できるかを確認する
+	// Lambda からは ICMP を送れないため、自宅のネットワークで ping を代行する HTTP のエンドポイントを呼び出す
+	// URL に GET <url>?host=<Address> を送り、2xx が返されたら応答したとみなす
+	TypeICMP Type = "icmp"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// 確認する対象
+type Target struct {
+	Name    string `json:"name"`
+	Type    Type   `json:"type"`
+	URL     string `json:"url,omitempty"`     // http と icmp の場合に指定する
+	Address string `json:"address,omitempty"` // tcp の場合は host:port、icmp の場合は host
+	Status  int    `json:"status,omitempty"`  // http の場合に期待するステータスコード、0 の場合は 2xx
+	Timeout string `json:"timeout,omitempty"` // e.g. 5s、未指定の場合は 10 秒
+}
+
+func (t Target) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("name is blank")
+	}
+	if t.Timeout != "" {
+		if d, err := time.ParseDuration(t.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout: %s", t.Timeout)
+		}
+	}
+	switch t.Type {
+	case TypeHTTP:
+		if t.URL == "" {
+			return fmt.Errorf("url is required: %s", t.Name)
+		}
+	case TypeTCP:
+		if _, _, err := net.SplitHostPort(t.Address); err != nil {
+			return fmt.Errorf("invalid address: %s", t.Address)
+		}
+	case TypeICMP:
+		if t.URL == "" || t.Address == "" {
+			return fmt.Errorf("url and address are required: %s", t.Name)
+		}
+	default:
+		return fmt.Errorf("invalid type: %s", t.Type)
+	}
+
+	return nil
+}
+
+type Targets []Target
+
+func (t *Targets) UnmarshalText(text []byte) error {
+	// Targets のまま読み込むと UnmarshalText が再度呼び出されるため、スライスとして読み込む
+	var targets []Target
+	if err := json.Unmarshal(text, &targets); err != nil {
+		return fmt.Errorf("invalid probe targets: %w", err)
+	}
+	names := map[string]bool{}
+	for _, target := range targets {
+		if err := target.Validate(); err != nil {
+			return fmt.Errorf("invalid probe targets: %w", err)
+		}
+		if names[target.Name] {
+			return fmt.Errorf("invalid probe targets: duplicate name: %s", target.Name)
+		}
+		names[target.Name] = true
+	}
+	*t = targets
+
+	return nil
+}
+
+// 確認した結果
+type Result struct {
+	OK      bool
+	Detail  string // 応答しなかった場合の理由
+	Latency time.Duration
+}
+
+// target に接続して応答するかを確認する
+// client は http と icmp の場合に使う
+func Check(ctx context.Context, client *http.Client, target Target) Result {
+	timeout := defaultTimeout
+	if d, err := time.ParseDuration(target.Timeout); err == nil && d > 0 {
+		timeout = d
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var err error
+	switch target.Type {
+	case TypeHTTP:
+		err = checkHTTP(ctx, client, target.URL, target.Status)
+	case TypeTCP:
+		err = checkTCP(ctx, target.Address)
+	case TypeICMP:
+		err = checkHTTP(ctx, client, target.URL+"?host="+url.QueryEscape(target.Address), 0)
+	default:
+		err = fmt.Errorf("invalid type: %s", target.Type)
+	}
+	if err != nil {
+		return Result{Detail: err.Error(), Latency: time.Since(start)}
+	}
+
+	return Result{OK: true, Latency: time.Since(start)}
+}
+
+func checkHTTP(ctx context.Context, client *http.Client, u string, status int) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if status != 0 && resp.StatusCode != status {
+		return fmt.Errorf("returned status %d", resp.StatusCode)
+	}
+	if status == 0 && (resp.StatusCode < 200 || resp.StatusCode >= 300) {
+		return fmt.Errorf("returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func checkTCP(ctx context.Context, address string) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
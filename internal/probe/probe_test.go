@@ -0,0 +1,113 @@
+package probe
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetsUnmarshalText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected Targets
+		wantErr  bool
+	}{
+		{
+			name: "正常系/各種類の対象",
+			text: `[
+				{"name": "nas", "type": "http", "url": "https://nas.example.com", "status": 401},
+				{"name": "ssh", "type": "tcp", "address": "home.example.com:22", "timeout": "5s"},
+				{"name": "router", "type": "icmp", "url": "https://relay.example.com/ping", "address": "192.168.1.1"}
+			]`,
+			expected: Targets{
+				{Name: "nas", Type: TypeHTTP, URL: "https://nas.example.com", Status: 401},
+				{Name: "ssh", Type: TypeTCP, Address: "home.example.com:22", Timeout: "5s"},
+				{Name: "router", Type: TypeICMP, URL: "https://relay.example.com/ping", Address: "192.168.1.1"},
+			},
+		},
+		{name: "異常系/JSON ではない", text: `nas`, wantErr: true},
+		{name: "異常系/名前がない", text: `[{"type": "http", "url": "https://example.com"}]`, wantErr: true},
+		{name: "異常系/名前が重複している", text: `[{"name": "a", "type": "http", "url": "https://example.com"}, {"name": "a", "type": "tcp", "address": "example.com:22"}]`, wantErr: true},
+		{name: "異常系/タイムアウトを解釈できない", text: `[{"name": "a", "type": "http", "url": "https://example.com", "timeout": "5"}]`, wantErr: true},
+		{name: "異常系/不明な種類", text: `[{"name": "a", "type": "udp", "address": "example.com:53"}]`, wantErr: true},
+		{name: "異常系/ポートがない", text: `[{"name": "a", "type": "tcp", "address": "example.com"}]`, wantErr: true},
+		{name: "異常系/代行するエンドポイントがない", text: `[{"name": "a", "type": "icmp", "address": "192.168.1.1"}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			var targets Targets
+			err := targets.UnmarshalText([]byte(tt.text))
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, targets)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/ok":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/auth":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/ping" && r.URL.Query().Get("host") == "192.168.1.1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	// 接続を受け付けないアドレスを作るため、待ち受けを閉じる
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closed := l.Addr().String()
+	l.Close()
+
+	tests := []struct {
+		name       string
+		target     Target
+		wantOK     bool
+		wantDetail string
+	}{
+		{name: "正常系/http で 2xx が返された場合", target: Target{Type: TypeHTTP, URL: srv.URL + "/ok"}, wantOK: true},
+		{name: "正常系/http で期待したステータスコードが返された場合", target: Target{Type: TypeHTTP, URL: srv.URL + "/auth", Status: 401}, wantOK: true},
+		{name: "正常系/tcp で接続できた場合", target: Target{Type: TypeTCP, Address: strings.TrimPrefix(srv.URL, "http://")}, wantOK: true},
+		{name: "正常系/icmp で応答した場合", target: Target{Type: TypeICMP, URL: srv.URL + "/ping", Address: "192.168.1.1"}, wantOK: true},
+		{name: "異常系/http でエラーが返された場合", target: Target{Type: TypeHTTP, URL: srv.URL + "/down"}, wantDetail: "returned status 503"},
+		{name: "異常系/http で期待と異なるステータスコードが返された場合", target: Target{Type: TypeHTTP, URL: srv.URL + "/ok", Status: 401}, wantDetail: "returned status 200"},
+		{name: "異常系/icmp で応答しなかった場合", target: Target{Type: TypeICMP, URL: srv.URL + "/ping", Address: "192.168.1.2"}, wantDetail: "returned status 503"},
+		{name: "異常系/tcp で接続できない場合", target: Target{Type: TypeTCP, Address: closed}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			r := Check(context.Background(), srv.Client(), tt.target)
+			ta.Equal(tt.wantOK, r.OK)
+			if tt.wantOK {
+				ta.Empty(r.Detail)
+				return
+			}
+			ta.NotEmpty(r.Detail)
+			if tt.wantDetail != "" {
+				ta.Equal(tt.wantDetail, r.Detail)
+			}
+		})
+	}
+}
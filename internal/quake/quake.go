@@ -0,0 +1,211 @@
+// Package quake は P2P地震情報 API から気象庁の地震情報を取得し、地域ごとの震度を判定する
+// https://www.p2pquake.net/develop/json_api_v2/
+package quake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/logging"
+)
+
+const SourceName = "p2pquake"
+
+// 震度、P2P地震情報 API の値をそのまま使う
+type Scale int
+
+const (
+	ScaleUnknown Scale = -1
+	Scale1       Scale = 10
+	Scale2       Scale = 20
+	Scale3       Scale = 30
+	Scale4       Scale = 40
+	Scale5Lower  Scale = 45
+	Scale5Upper  Scale = 50
+	Scale6Lower  Scale = 55
+	Scale6Upper  Scale = 60
+	Scale7       Scale = 70
+)
+
+var scaleNames = map[Scale]string{
+	Scale1:      "1",
+	Scale2:      "2",
+	Scale3:      "3",
+	Scale4:      "4",
+	Scale5Lower: "5弱",
+	Scale5Upper: "5強",
+	Scale6Lower: "6弱",
+	Scale6Upper: "6強",
+	Scale7:      "7",
+}
+
+func (s Scale) String() string {
+	if name, ok := scaleNames[s]; ok {
+		return "震度" + name
+	}
+
+	return "震度不明"
+}
+
+// e.g. 3, 5弱, 震度5強
+func ParseScale(s string) (Scale, error) {
+	name := strings.TrimPrefix(strings.TrimSpace(s), "震度")
+	for scale, n := range scaleNames {
+		if n == name {
+			return scale, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid scale: %s", s)
+}
+
+// API の値は数値のため、UnmarshalText ではなく数値として読み込む
+func (s *Scale) UnmarshalJSON(b []byte) error {
+	var n int
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	*s = Scale(n)
+
+	return nil
+}
+
+// 設定値は e.g. 5弱 のように指定する
+func (s *Scale) UnmarshalText(text []byte) error {
+	scale, err := ParseScale(string(text))
+	if err != nil {
+		return err
+	}
+	*s = scale
+
+	return nil
+}
+
+// 震度を観測した地点
+type Point struct {
+	Pref  string `json:"pref"` // e.g. 東京都
+	Addr  string `json:"addr"` // e.g. 千代田区
+	Scale Scale  `json:"scale"`
+}
+
+// 地震情報
+type Report struct {
+	ID         string
+	Type       string // e.g. ScalePrompt (震度速報), DetailScale (各地の震度に関する情報)
+	Time       time.Time
+	Hypocenter string  // 震源、速報の場合は空
+	Magnitude  float64 // 不明な場合は -1
+	Depth      int     // km、不明な場合は -1
+	MaxScale   Scale
+	Tsunami    string // 国内の津波の有無、e.g. None, Checking, Warning
+	Points     []Point
+}
+
+// 震源のみの情報など、震度を含まない情報は除く
+var scaleTypes = map[string]bool{"ScalePrompt": true, "ScaleAndDestination": true, "DetailScale": true}
+
+// prefs に含まれる都道府県で観測した最大の震度と、その地点を返す
+func (r Report) ScaleIn(prefs []string) (Scale, []Point) {
+	highest := ScaleUnknown
+	var points []Point
+	for _, p := range r.Points {
+		if !slices.Contains(prefs, p.Pref) {
+			continue
+		}
+		points = append(points, p)
+		highest = max(highest, p.Scale)
+	}
+
+	return highest, points
+}
+
+// 国内の津波の有無
+func (r Report) TsunamiText() string {
+	switch r.Tsunami {
+	case "None":
+		return "この地震による津波の心配はありません"
+	case "NonEffective":
+		return "若干の海面変動が予想されますが、被害の心配はありません"
+	case "Watch":
+		return "津波注意報が発表されています"
+	case "Warning":
+		return "津波警報等が発表されています"
+	case "Checking":
+		return "津波の有無を調査中です"
+	default:
+		return ""
+	}
+}
+
+type quakeResponse struct {
+	ID    string `json:"id"`
+	Issue struct {
+		Type string `json:"type"`
+	} `json:"issue"`
+	Earthquake struct {
+		Time       string `json:"time"` // e.g. 2025/03/01 12:34:00
+		Hypocenter struct {
+			Name      string  `json:"name"`
+			Depth     int     `json:"depth"`
+			Magnitude float64 `json:"magnitude"`
+		} `json:"hypocenter"`
+		MaxScale        Scale  `json:"maxScale"`
+		DomesticTsunami string `json:"domesticTsunami"`
+	} `json:"earthquake"`
+	Points []Point `json:"points"`
+}
+
+// baseURL は e.g. https://api.p2pquake.net/v2/jma/quake で、新しいものから limit 件の震度を含む地震情報を返す
+func Fetch(ctx context.Context, client *http.Client, baseURL string, limit int, tz *time.Location) ([]Report, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s?limit=%d&order=-1", baseURL, limit), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, event.NewSourceUnavailableError(SourceName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, event.NewSourceUnavailableError(SourceName, fmt.Errorf("returned status %d", resp.StatusCode))
+	}
+
+	var items []quakeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, event.NewParseError(SourceName, "quake", err)
+	}
+	logging.DebugPayload(ctx, "fetched quakes", items)
+
+	var reports []Report
+	for _, item := range items {
+		if !scaleTypes[item.Issue.Type] {
+			continue
+		}
+		e := item.Earthquake
+		t, err := time.ParseInLocation("2006/01/02 15:04:05", e.Time, tz)
+		if err != nil {
+			return nil, event.NewParseError(SourceName, item.ID, fmt.Errorf("invalid time: %s", e.Time))
+		}
+		reports = append(reports, Report{
+			ID:         item.ID,
+			Type:       item.Issue.Type,
+			Time:       t,
+			Hypocenter: e.Hypocenter.Name,
+			Magnitude:  e.Hypocenter.Magnitude,
+			Depth:      e.Hypocenter.Depth,
+			MaxScale:   e.MaxScale,
+			Tsunami:    e.DomesticTsunami,
+			Points:     item.Points,
+		})
+	}
+
+	return reports, nil
+}
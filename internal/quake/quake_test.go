@@ -0,0 +1,99 @@
+package quake
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tz = time.FixedZone("JST", 9*60*60)
+
+func TestParseScale(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Scale
+		wantErr  bool
+	}{
+		{name: "正常系/数字のみの場合", input: "3", expected: Scale3},
+		{name: "正常系/弱と強を含む場合", input: "5弱", expected: Scale5Lower},
+		{name: "正常系/震度を含む場合", input: "震度6強", expected: Scale6Upper},
+		{name: "異常系/存在しない震度の場合", input: "8", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			s, err := ParseScale(tt.input)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, s)
+		})
+	}
+}
+
+func TestScaleString(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.Equal("震度5弱", Scale5Lower.String())
+	ta.Equal("震度不明", ScaleUnknown.String())
+}
+
+func TestReportScaleIn(t *testing.T) {
+	ta := assert.New(t)
+
+	r := Report{Points: []Point{
+		{Pref: "千葉県", Addr: "千葉中央区", Scale: Scale5Lower},
+		{Pref: "東京都", Addr: "千代田区", Scale: Scale4},
+		{Pref: "東京都", Addr: "八王子市", Scale: Scale3},
+	}}
+
+	scale, points := r.ScaleIn([]string{"東京都", "神奈川県"})
+	ta.Equal(Scale4, scale)
+	ta.Len(points, 2)
+
+	scale, points = r.ScaleIn([]string{"大阪府"})
+	ta.Equal(ScaleUnknown, scale)
+	ta.Empty(points)
+}
+
+func TestFetch(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") != "10" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`[
+			{"id": "q2", "issue": {"type": "DetailScale"}, "earthquake": {"time": "2025/03/01 12:34:00", "hypocenter": {"name": "千葉県北西部", "depth": 60, "magnitude": 5.2}, "maxScale": 45, "domesticTsunami": "None"}, "points": [{"pref": "東京都", "addr": "千代田区", "scale": 40}]},
+			{"id": "q1", "issue": {"type": "Destination"}, "earthquake": {"time": "2025/03/01 12:34:00", "hypocenter": {"name": "千葉県北西部", "depth": 60, "magnitude": 5.2}, "maxScale": -1}}
+		]`))
+	}))
+	defer srv.Close()
+
+	reports, err := Fetch(context.Background(), srv.Client(), srv.URL, 10, tz)
+	tr.NoError(err)
+	ta.Equal([]Report{{
+		ID:         "q2",
+		Type:       "DetailScale",
+		Time:       time.Date(2025, 3, 1, 12, 34, 0, 0, tz),
+		Hypocenter: "千葉県北西部",
+		Magnitude:  5.2,
+		Depth:      60,
+		MaxScale:   Scale5Lower,
+		Tsunami:    "None",
+		Points:     []Point{{Pref: "東京都", Addr: "千代田区", Scale: Scale4}},
+	}}, reports)
+	ta.Equal("この地震による津波の心配はありません", reports[0].TsunamiText())
+}
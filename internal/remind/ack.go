@@ -0,0 +1,177 @@
+package remind
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+const (
+	ackRetention  = 30 * 24 * time.Hour
+	ackSourceName = "ack"
+)
+
+// 日付ごとのイベントの対応状況
+type AckRecord struct {
+	Date        time.Time
+	Key         string
+	Name        string
+	Emoji       string
+	Status      event.AckStatus
+	SnoozeUntil time.Time
+}
+
+// イベントの対応状況を DynamoDB に保存する
+// テーブルのキーは pk (パーティションキー) と sk (ソートキー、"<yyyymmdd>#<イベントのキー>") とする
+type AckStore struct {
+	client *dynamodb.Client
+	table  string
+}
+
+func NewAckStore(client *dynamodb.Client, table string) *AckStore {
+	return &AckStore{client: client, table: table}
+}
+
+// 対応が必要なイベントを未対応として登録する
+// 既に登録されているイベントは対応状況を上書きしない
+func (s *AckStore) register(ctx context.Context, date time.Time, events []event.Event) error {
+	for _, e := range events {
+		// 事前通知と繰り越されたイベントは元の発生日で登録済み
+		if e.LeadDays > 0 || !e.Origin.IsZero() {
+			continue
+		}
+
+		item := dynamodb.Item{
+			"pk":         dynamodb.S(event.AckPartitionKey),
+			"sk":         dynamodb.S(event.AckSortKey(date, event.Key(e))),
+			"name":       dynamodb.S(e.Name),
+			"emoji":      dynamodb.S(e.Emoji),
+			"ack_status": dynamodb.S(string(event.AckPending)),
+			"expires_at": dynamodb.N(date.Add(ackRetention).Unix()),
+		}
+		err := s.client.PutItem(ctx, s.table, item, "attribute_not_exists(pk)", nil)
+		if err != nil && !dynamodb.IsConditionalCheckFailed(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// イベントの対応状況を返す、登録されていない場合は空を返す
+func (s *AckStore) status(ctx context.Context, date time.Time, key string) (event.AckStatus, error) {
+	item, err := s.client.GetItem(ctx, s.table, dynamodb.Item{
+		"pk": dynamodb.S(event.AckPartitionKey),
+		"sk": dynamodb.S(event.AckSortKey(date, key)),
+	})
+	if err != nil {
+		return "", err
+	}
+	if item == nil {
+		return "", nil
+	}
+
+	return event.AckStatus(item.Str("ack_status")), nil
+}
+
+// today より前の lookback 日間で、未対応もしくは再通知の時期が来たイベントを返す
+func (s *AckStore) listOpen(ctx context.Context, today time.Time, lookback int) ([]AckRecord, error) {
+	items, err := s.client.Query(ctx, s.table, "pk = :pk AND sk BETWEEN :from AND :to", dynamodb.Item{
+		":pk":   dynamodb.S(event.AckPartitionKey),
+		":from": dynamodb.S(today.AddDate(0, 0, -lookback).Format(event.AckDateFormat)),
+		// "<today>" は "<today>#..." より前に並ぶため、当日のイベントは含まれない
+		":to": dynamodb.S(today.Format(event.AckDateFormat)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var records []AckRecord
+	for _, item := range items {
+		r, err := parseAckRecord(item, today.Location())
+		if err != nil {
+			return nil, err
+		}
+		switch r.Status {
+		case event.AckPending:
+			records = append(records, r)
+		case event.AckSnoozed:
+			if !r.SnoozeUntil.After(today) {
+				records = append(records, r)
+			}
+		}
+	}
+
+	return records, nil
+}
+
+func parseAckRecord(item dynamodb.Item, loc *time.Location) (AckRecord, error) {
+	d, key, err := event.ParseAckSortKey(item.Str("sk"), loc)
+	if err != nil {
+		return AckRecord{}, err
+	}
+
+	r := AckRecord{
+		Date:   d,
+		Key:    key,
+		Name:   item.Str("name"),
+		Emoji:  item.Str("emoji"),
+		Status: event.AckStatus(item.Str("ack_status")),
+	}
+	if v := item.Str("snooze_until"); v != "" {
+		if r.SnoozeUntil, err = time.ParseInLocation(event.AckDateFormat, v, loc); err != nil {
+			return AckRecord{}, fmt.Errorf("invalid snooze date: %s", v)
+		}
+	}
+
+	return r, nil
+}
+
+// 対応されないまま発生日を過ぎたイベントを古い順に返す
+func createOverdueEvents(records []AckRecord) []event.Event {
+	var events []event.Event
+	for _, r := range records {
+		events = append(events, event.Event{
+			Name:   r.Name,
+			Emoji:  r.Emoji,
+			Origin: r.Date,
+		})
+	}
+	slices.SortStableFunc(events, func(a, b event.Event) int {
+		return a.Origin.Compare(b.Origin)
+	})
+
+	return events
+}
+
+// 発生日から after 日以上経過したイベントがあればメンションで知らせる
+func needsEscalation(overdue []event.Event, today time.Time, after int) bool {
+	for _, e := range overdue {
+		if !e.Origin.AddDate(0, 0, after).After(today) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 対応状況の記録から未対応のイベントを取得する
+type overdueSource struct {
+	acks     *AckStore
+	lookback int
+	after    int
+}
+
+func (s overdueSource) Overdue(ctx context.Context, today time.Time) ([]event.Event, bool, error) {
+	records, err := s.acks.listOpen(ctx, today, s.lookback)
+	if err != nil {
+		return nil, false, event.NewSourceUnavailableError(ackSourceName, err)
+	}
+	overdue := createOverdueEvents(records)
+
+	return overdue, needsEscalation(overdue, today, s.after), nil
+}
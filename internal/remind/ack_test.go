@@ -0,0 +1,112 @@
+package remind
+
+import (
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/event"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var tz = time.FixedZone("JST", 9*60*60)
+
+func TestParseAckRecord(t *testing.T) {
+	tests := []struct {
+		name        string
+		item        dynamodb.Item
+		expectError bool
+		expected    AckRecord
+	}{
+		{
+			name: "正常系/再通知する日付が指定されている場合",
+			item: dynamodb.Item{
+				"pk":           dynamodb.S("ack"),
+				"sk":           dynamodb.S("20250114#0123456789ab"),
+				"name":         dynamodb.S("Garbage"),
+				"emoji":        dynamodb.S("🗑️"),
+				"ack_status":   dynamodb.S("snoozed"),
+				"snooze_until": dynamodb.S("20250115"),
+			},
+			expected: AckRecord{
+				Date:        time.Date(2025, 1, 14, 0, 0, 0, 0, tz),
+				Key:         "0123456789ab",
+				Name:        "Garbage",
+				Emoji:       "🗑️",
+				Status:      event.AckSnoozed,
+				SnoozeUntil: time.Date(2025, 1, 15, 0, 0, 0, 0, tz),
+			},
+		},
+		{
+			name: "異常系/ソートキーが不正な形式である場合",
+			item: dynamodb.Item{
+				"pk": dynamodb.S("ack"),
+				"sk": dynamodb.S("20250114"),
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			r, err := parseAckRecord(tt.item, tz)
+
+			if tt.expectError {
+				ta.Error(err)
+			} else {
+				ta.NoError(err)
+				ta.Equal(tt.expected, r)
+			}
+		})
+	}
+}
+
+func TestCreateOverdueEvents(t *testing.T) {
+	ta := assert.New(t)
+	records := []AckRecord{
+		{Date: time.Date(2025, 1, 14, 0, 0, 0, 0, tz), Name: "Piano", Status: event.AckSnoozed},
+		{Date: time.Date(2025, 1, 12, 0, 0, 0, 0, tz), Name: "Garbage", Emoji: "🗑️", Status: event.AckPending},
+	}
+
+	ta.Equal([]event.Event{
+		{Name: "Garbage", Emoji: "🗑️", Origin: time.Date(2025, 1, 12, 0, 0, 0, 0, tz)},
+		{Name: "Piano", Origin: time.Date(2025, 1, 14, 0, 0, 0, 0, tz)},
+	}, createOverdueEvents(records))
+}
+
+func TestNeedsEscalation(t *testing.T) {
+	today := time.Date(2025, 1, 15, 0, 0, 0, 0, tz)
+
+	tests := []struct {
+		name     string
+		overdue  []event.Event
+		after    int
+		expected bool
+	}{
+		{
+			name:     "正常系/指定した日数以上経過している場合",
+			overdue:  []event.Event{{Name: "Garbage", Origin: time.Date(2025, 1, 13, 0, 0, 0, 0, tz)}},
+			after:    2,
+			expected: true,
+		},
+		{
+			name:     "正常系/指定した日数が経過していない場合",
+			overdue:  []event.Event{{Name: "Garbage", Origin: time.Date(2025, 1, 14, 0, 0, 0, 0, tz)}},
+			after:    2,
+			expected: false,
+		},
+		{
+			name:     "正常系/未対応のイベントがない場合",
+			after:    2,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			ta.Equal(tt.expected, needsEscalation(tt.overdue, today, tt.after))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package remind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/buildinfo"
+	"github.com/mami0tsu/homeops/internal/digest"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/s3"
+)
+
+const modeDaily = "daily"
+
+// 投稿した内容の記録、後から投稿の有無を確認したり同じ内容を再投稿したりするために使う
+type archiveRecord struct {
+	RunAt      time.Time         `json:"run_at"`
+	Mode       string            `json:"mode"` // e.g. "daily", "intraday"
+	Build      string            `json:"build"`
+	Sources    []string          `json:"sources"`
+	Digest     Output            `json:"digest"` // remind-cli の出力と同じ形式
+	Deliveries []digest.Delivery `json:"deliveries"`
+}
+
+func newArchiveRecord(cfg *Config, runAt time.Time, mode string, d event.Digest, deliveries []digest.Delivery) archiveRecord {
+	return archiveRecord{
+		RunAt:      runAt,
+		Mode:       mode,
+		Build:      buildinfo.Get().String(),
+		Sources:    usedSources(cfg),
+		Digest:     NewOutput(d),
+		Deliveries: deliveries,
+	}
+}
+
+func usedSources(cfg *Config) []string {
+	names := []string{"sheet"}
+	if cfg.HolidaysURL != "" {
+		names = append(names, "holidays")
+	}
+	if cfg.AckTableName != "" {
+		names = append(names, ackSourceName)
+	}
+
+	return names
+}
+
+// 実行日ごとに分けて保存する、e.g. remind/2025/03/01/080000-daily.json
+func archiveKey(runAt time.Time, mode string) string {
+	return fmt.Sprintf("remind/%s/%s-%s.json", runAt.Format("2006/01/02"), runAt.Format("150405"), mode)
+}
+
+// ARCHIVE_BUCKET が指定されていれば投稿内容を保存する
+// 保存に失敗しても投稿は済んでいるため、警告を出力するのみとする
+func archive(ctx context.Context, cfg *Config, rec archiveRecord) {
+	if cfg.ArchiveBucket == "" {
+		return
+	}
+
+	if err := putArchive(ctx, cfg.ArchiveBucket, rec); err != nil {
+		slog.Warn("failed to archive digest", slog.String("bucket", cfg.ArchiveBucket), slog.Any("error", err))
+		return
+	}
+	slog.Info("archived digest", slog.String("key", archiveKey(rec.RunAt, rec.Mode)))
+}
+
+func putArchive(ctx context.Context, bucket string, rec archiveRecord) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	client, err := s3.NewClient(ctx, httpclient.Default)
+	if err != nil {
+		return err
+	}
+
+	return client.PutObject(ctx, bucket, archiveKey(rec.RunAt, rec.Mode), "application/json", b)
+}
@@ -0,0 +1,16 @@
+package remind
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchiveKey(t *testing.T) {
+	ta := assert.New(t)
+
+	runAt := time.Date(2025, 3, 1, 8, 0, 5, 0, tz)
+	ta.Equal("remind/2025/03/01/080005-daily.json", archiveKey(runAt, modeDaily))
+	ta.Equal("remind/2025/03/01/080005-intraday.json", archiveKey(runAt, modeIntraday))
+}
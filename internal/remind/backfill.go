@@ -0,0 +1,86 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/digest"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/notify"
+)
+
+// 障害などで投稿できなかった日の分を、日ごとに後から投稿するモード
+// e.g. {"mode": "backfill", "from": "2025-03-01", "to": "2025-03-03"}
+const modeBackfill = "backfill"
+
+// 誤った期間の指定で大量に投稿しないように、1 度に投稿し直す日数を制限する
+const maxBackfillDays = 31
+
+// from から to までの日付を返す、to が未指定の場合は実行日の前日までとする
+func backfillDates(today time.Time, from, to string) ([]time.Time, error) {
+	if from == "" {
+		return nil, fmt.Errorf("from is required for backfill mode")
+	}
+	start, err := time.ParseInLocation("2006-01-02", from, today.Location())
+	if err != nil {
+		return nil, fmt.Errorf("invalid from: %s", from)
+	}
+	end := today.AddDate(0, 0, -1)
+	if to != "" {
+		if end, err = time.ParseInLocation("2006-01-02", to, today.Location()); err != nil {
+			return nil, fmt.Errorf("invalid to: %s", to)
+		}
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("invalid range: %s to %s", from, to)
+	}
+	if end.After(today) {
+		return nil, fmt.Errorf("to must not be after today: %s", to)
+	}
+
+	var dates []time.Time
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+	if len(dates) > maxBackfillDays {
+		return nil, fmt.Errorf("backfill range must be within %d days: %s to %s", maxBackfillDays, from, to)
+	}
+
+	return dates, nil
+}
+
+// 日ごとにその日のイベントを取得し、後から投稿したことを明記して投稿する
+// 日ごとの通知と同じ実行 ID で記録するため、その日の通知が投稿済みであれば投稿しない
+// 一部の日の投稿に失敗しても残りの日の投稿は続ける
+func runBackfill(ctx context.Context, a *digest.App, guard *RunGuard, runID string, cfg *Config, dates []time.Time, dryRun bool) error {
+	var errs []error
+	for _, day := range dates {
+		schedules, fetchErrs := a.FetchSchedules(ctx, []time.Time{day})
+		if len(schedules) == 0 {
+			err := errors.Join(fetchErrs...)
+			slog.Error("failed to get any events", slog.String("date", day.Format("2006-01-02")), slog.Any("error", err))
+			errs = append(errs, err)
+			continue
+		}
+		d := event.Digest{Schedules: schedules, Failures: fetchErrs, CatchUp: true}
+		if dryRun {
+			slog.Info("dry run", slog.String("digest", notify.RenderDigest(notify.FormatText, d, cfg.NoEventsMode, cfg.Locale)))
+			continue
+		}
+
+		deliveries, err := a.PostOnce(ctx, guard, day, runID, d)
+		if err != nil {
+			slog.Error("failed to post events", slog.String("date", day.Format("2006-01-02")), slog.Any("error", err))
+			errs = append(errs, err)
+			continue
+		}
+		if len(deliveries) > 0 {
+			slog.Info("posted missed events", slog.String("date", day.Format("2006-01-02")))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,117 @@
+package remind
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/digest"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackfillDates(t *testing.T) {
+	today := time.Date(2025, 3, 10, 0, 0, 0, 0, tz)
+
+	tests := []struct {
+		name        string
+		from        string
+		to          string
+		expected    []string
+		expectError bool
+	}{
+		{
+			name:     "正常系/期間を指定した場合",
+			from:     "2025-03-01",
+			to:       "2025-03-03",
+			expected: []string{"2025-03-01", "2025-03-02", "2025-03-03"},
+		},
+		{
+			name:     "正常系/終了日を省略した場合は前日までとする",
+			from:     "2025-03-08",
+			expected: []string{"2025-03-08", "2025-03-09"},
+		},
+		{
+			name:        "異常系/開始日がない場合",
+			expectError: true,
+		},
+		{
+			name:        "異常系/開始日が終了日より後の場合",
+			from:        "2025-03-05",
+			to:          "2025-03-01",
+			expectError: true,
+		},
+		{
+			name:        "異常系/終了日が実行日より後の場合",
+			from:        "2025-03-08",
+			to:          "2025-03-11",
+			expectError: true,
+		},
+		{
+			name:        "異常系/期間が長すぎる場合",
+			from:        "2025-01-01",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			dates, err := backfillDates(today, tt.from, tt.to)
+			if tt.expectError {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			var actual []string
+			for _, d := range dates {
+				actual = append(actual, d.Format("2006-01-02"))
+			}
+			ta.Equal(tt.expected, actual)
+		})
+	}
+}
+
+type panicSource struct{}
+
+func (panicSource) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	if t.Day() == 1 {
+		var e *event.Event
+		_ = e.Name
+	}
+
+	return []event.Event{{Name: "Garbage"}}, nil
+}
+
+type recordSink struct {
+	name   string
+	posted int
+}
+
+func (s *recordSink) Name() string {
+	return s.name
+}
+
+func (s *recordSink) Post(ctx context.Context, d event.Digest) error {
+	s.posted++
+
+	return nil
+}
+
+func TestRunBackfill(t *testing.T) {
+	ta := assert.New(t)
+
+	// 1 日の取得は失敗するが、残りの日は投稿を続ける
+	sink := &recordSink{name: "discord"}
+	a := digest.NewApp(panicSource{}, sink)
+	dates := []time.Time{
+		time.Date(2025, 3, 1, 0, 0, 0, 0, tz),
+		time.Date(2025, 3, 2, 0, 0, 0, 0, tz),
+		time.Date(2025, 3, 3, 0, 0, 0, 0, tz),
+	}
+
+	err := runBackfill(context.Background(), a, nil, "daily#test", &Config{}, dates, false)
+	ta.Error(err)
+	ta.Equal(2, sink.posted)
+}
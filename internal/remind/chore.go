@@ -0,0 +1,161 @@
+package remind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/chore"
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+const (
+	chorePartitionKey = "chore"
+	choreRetention    = 30 * 24 * time.Hour
+	choreSourceName   = "chore"
+)
+
+// 家事の担当者の割り当てを DynamoDB に保存する
+// テーブルのキーは pk (パーティションキー) と sk (ソートキー) とする
+// 家事ごとに割り当てた回数を "<イベントのキー>" に、発生日ごとの担当者を "<イベントのキー>#<yyyymmdd>" に保存する
+type ChoreStore struct {
+	client *dynamodb.Client
+	table  string
+}
+
+func NewChoreStore(client *dynamodb.Client, table string) *ChoreStore {
+	return &ChoreStore{client: client, table: table}
+}
+
+// CHORE_ROTATIONS にペットの世話の持ち回りを加える
+func (c *Config) choreRotations() chore.Rotations {
+	return append(slices.Clone(c.ChoreRotations), c.Pets.Rotations()...)
+}
+
+// 持ち回りの設定がない場合は nil を返す
+func newChoreStore(client *dynamodb.Client, cfg *Config) *ChoreStore {
+	if len(cfg.choreRotations()) == 0 {
+		return nil
+	}
+
+	return NewChoreStore(client, cfg.ChoreTableName)
+}
+
+func choreSortKey(key string, date time.Time) string {
+	if date.IsZero() {
+		return key
+	}
+
+	return key + "#" + date.Format(event.AckDateFormat)
+}
+
+// 発生日の担当者を返す
+// 割り当て済みであれば同じ担当者を返すため、再試行や前日の事前通知で担当者が変わらない
+func (s *ChoreStore) assign(ctx context.Context, r chore.Rotation, key string, date time.Time) (chore.Member, error) {
+	occurrence := dynamodb.Item{"pk": dynamodb.S(chorePartitionKey), "sk": dynamodb.S(choreSortKey(key, date))}
+	item, err := s.client.GetItem(ctx, s.table, occurrence)
+	if err != nil {
+		return chore.Member{}, err
+	}
+	if item != nil {
+		return assignedMember(r, item), nil
+	}
+
+	state := dynamodb.Item{"pk": dynamodb.S(chorePartitionKey), "sk": dynamodb.S(choreSortKey(key, time.Time{}))}
+	item, err = s.client.GetItem(ctx, s.table, state)
+	if err != nil {
+		return chore.Member{}, err
+	}
+	counts, err := parseChoreCounts(item)
+	if err != nil {
+		return chore.Member{}, err
+	}
+
+	// 同時に割り当てた場合は、先に保存された担当者を使う
+	m := r.Next(counts)
+	occurrence["name"] = dynamodb.S(m.Name)
+	occurrence["mention"] = dynamodb.S(m.Mention)
+	occurrence["expires_at"] = dynamodb.N(date.Add(choreRetention).Unix())
+	if err := s.client.PutItem(ctx, s.table, occurrence, "attribute_not_exists(pk)", nil); err != nil {
+		if !dynamodb.IsConditionalCheckFailed(err) {
+			return chore.Member{}, err
+		}
+		item, err := s.client.GetItem(ctx, s.table, occurrence)
+		if err != nil {
+			return chore.Member{}, err
+		}
+		return assignedMember(r, item), nil
+	}
+
+	counts[m.Name]++
+	b, err := json.Marshal(counts)
+	if err != nil {
+		return chore.Member{}, err
+	}
+	state["counts"] = dynamodb.S(string(b))
+	if err := s.client.PutItem(ctx, s.table, state, "", nil); err != nil {
+		return chore.Member{}, err
+	}
+
+	return m, nil
+}
+
+// 割り当て後にメンションが変更された場合は、現在の設定のメンションを使う
+func assignedMember(r chore.Rotation, item dynamodb.Item) chore.Member {
+	if m, ok := r.Member(item.Str("name")); ok {
+		return m
+	}
+
+	return chore.Member{Name: item.Str("name"), Mention: item.Str("mention")}
+}
+
+func parseChoreCounts(item dynamodb.Item) (map[string]int, error) {
+	counts := map[string]int{}
+	if v := item.Str("counts"); v != "" {
+		if err := json.Unmarshal([]byte(v), &counts); err != nil {
+			return nil, fmt.Errorf("invalid chore counts: %s", v)
+		}
+	}
+
+	return counts, nil
+}
+
+// 持ち回りの対象のイベントに担当者を設定する
+// 事前通知のイベントは発生日の担当者を、繰り越されたイベントは元の発生日の担当者を設定する
+// 割り当てに失敗した場合は担当者を設定せずに投稿を続け、失敗した取得元を Failures に記録する
+func assignChores(ctx context.Context, rotations chore.Rotations, d *event.Digest, assign func(ctx context.Context, r chore.Rotation, key string, date time.Time) (chore.Member, error)) {
+	var failed error
+	apply := func(e *event.Event, date time.Time) {
+		r, ok := rotations.Find(e.Name)
+		if !ok {
+			return
+		}
+		m, err := assign(ctx, r, event.Key(*e), date)
+		if err != nil {
+			slog.Warn("failed to assign chore", slog.String("event", e.Name), slog.Any("error", err))
+			failed = err
+			return
+		}
+		e.Assignee = m.Display()
+	}
+
+	for _, s := range d.Schedules {
+		for i := range s.Events {
+			date := s.Date.AddDate(0, 0, s.Events[i].LeadDays)
+			if origin := s.Events[i].Origin; !origin.IsZero() {
+				date = origin
+			}
+			apply(&s.Events[i], date)
+		}
+	}
+	for i := range d.Overdue {
+		apply(&d.Overdue[i], d.Overdue[i].Origin)
+	}
+	if failed != nil {
+		d.Failures = append(d.Failures, event.NewSourceUnavailableError(choreSourceName, failed))
+	}
+}
@@ -0,0 +1,110 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/chore"
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChoreSortKey(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.Equal("0123456789ab", choreSortKey("0123456789ab", time.Time{}))
+	ta.Equal("0123456789ab#20250114", choreSortKey("0123456789ab", time.Date(2025, 1, 14, 0, 0, 0, 0, tz)))
+}
+
+func TestParseChoreCounts(t *testing.T) {
+	tests := []struct {
+		name        string
+		item        dynamodb.Item
+		expectError bool
+		expected    map[string]int
+	}{
+		{
+			name:     "正常系/まだ割り当てていない場合",
+			item:     nil,
+			expected: map[string]int{},
+		},
+		{
+			name:     "正常系/割り当てた回数を読み込む",
+			item:     dynamodb.Item{"counts": dynamodb.S(`{"alice": 2, "bob": 1}`)},
+			expected: map[string]int{"alice": 2, "bob": 1},
+		},
+		{
+			name:        "異常系/不正な形式である場合",
+			item:        dynamodb.Item{"counts": dynamodb.S(`alice`)},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			counts, err := parseChoreCounts(tt.item)
+
+			if tt.expectError {
+				ta.Error(err)
+			} else {
+				ta.NoError(err)
+				ta.Equal(tt.expected, counts)
+			}
+		})
+	}
+}
+
+func TestAssignedMember(t *testing.T) {
+	ta := assert.New(t)
+
+	r := chore.Rotation{Members: []chore.Member{{Name: "alice", Mention: "<@10>"}}}
+	// 設定に残っているメンバーは現在のメンションを使う
+	ta.Equal(chore.Member{Name: "alice", Mention: "<@10>"}, assignedMember(r, dynamodb.Item{"name": dynamodb.S("alice"), "mention": dynamodb.S("<@1>")}))
+	// 設定から削除されたメンバーは保存したメンションを使う
+	ta.Equal(chore.Member{Name: "bob", Mention: "<@2>"}, assignedMember(r, dynamodb.Item{"name": dynamodb.S("bob"), "mention": dynamodb.S("<@2>")}))
+}
+
+func TestAssignChores(t *testing.T) {
+	ta := assert.New(t)
+
+	today := time.Date(2025, 1, 14, 0, 0, 0, 0, tz)
+	rotations := chore.Rotations{
+		{Event: "Bath", Members: []chore.Member{{Name: "alice", Mention: "<@1>"}}},
+		{Event: "Dishes", Members: []chore.Member{{Name: "bob"}}},
+	}
+	d := event.Digest{
+		Schedules: []event.Schedule{{Date: today, Events: []event.Event{
+			{Name: "Bath"},
+			{Name: "Bath", LeadDays: 2},
+			{Name: "Garbage"},
+			{Name: "Dishes"},
+		}}},
+		Overdue: []event.Event{{Name: "Bath", Origin: today.AddDate(0, 0, -3)}},
+	}
+
+	var dates []time.Time
+	assign := func(ctx context.Context, r chore.Rotation, key string, date time.Time) (chore.Member, error) {
+		if r.Event == "Dishes" {
+			return chore.Member{}, errors.New("throttled")
+		}
+		dates = append(dates, date)
+		return r.Members[0], nil
+	}
+	assignChores(context.Background(), rotations, &d, assign)
+
+	// 事前通知は発生日、繰り越されたイベントは元の発生日で割り当てる
+	ta.Equal([]time.Time{today, today.AddDate(0, 0, 2), today.AddDate(0, 0, -3)}, dates)
+	events := d.Schedules[0].Events
+	ta.Equal("<@1>", events[0].Assignee)
+	ta.Equal("<@1>", events[1].Assignee)
+	ta.Empty(events[2].Assignee)
+	ta.Empty(events[3].Assignee)
+	ta.Equal("<@1>", d.Overdue[0].Assignee)
+	// 割り当てに失敗した場合は投稿内容に注記する
+	ta.Len(d.Failures, 1)
+	ta.Equal(choreSourceName, event.FailedComponent(d.Failures[0]))
+}
@@ -0,0 +1,58 @@
+package remind
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/config"
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/sources"
+)
+
+// 呼び出しの間で再利用する設定とクライアント
+type clients struct {
+	cfg      *Config // 作成時の設定、Lambda では運用上の設定を呼び出しごとに読み込み直す
+	sheets   sources.SheetDataReader
+	dynamodb *dynamodb.Client // テーブルを使う処理で共有する、テーブル名の設定がなければ呼び出さない
+}
+
+// SSM パラメータや認証情報の更新を反映するため、一定時間が経過したら次の呼び出しで作り直す
+const clientsTTL = 15 * time.Minute
+
+var cachedClients = config.NewCache(clientsTTL, newClients)
+
+// SSM パラメータを展開して設定を読み込み、スプレッドシートと DynamoDB のクライアントを作成する
+func newClients(ctx context.Context) (*clients, error) {
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 呼び出しが終わった後もトークンを更新できるように、呼び出しのキャンセルを引き継がない
+	srv, err := sources.NewSheetsService(context.WithoutCancel(ctx), []byte(cfg.GoogleCredentials))
+	if err != nil {
+		slog.Error("failed to init Sheets service", slog.Any("error", err))
+		return nil, err
+	}
+
+	db, err := dynamodb.NewClient(ctx, httpclient.Default)
+	if err != nil {
+		slog.Error("failed to init DynamoDB client", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &clients{cfg: cfg, sheets: &sources.GoogleSheetReader{Service: srv}, dynamodb: db}, nil
+}
+
+// SSM パラメータは作成時に展開した値を使い、S3 の運用上の設定のみを読み込み直す
+func refreshConfig(ctx context.Context) (*Config, error) {
+	var cfg Config
+	if err := config.Refresh(ctx, &cfg); err != nil {
+		slog.Error("failed to refresh config", slog.Any("error", err))
+		return nil, err
+	}
+	cfg.applyDeprecated()
+
+	return &cfg, nil
+}
@@ -0,0 +1,145 @@
+package remind
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/mami0tsu/homeops/internal/civic"
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/config"
+	"github.com/mami0tsu/homeops/internal/document"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/expiry"
+	"github.com/mami0tsu/homeops/internal/gomi"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/notify"
+)
+
+// 廃止した設定値を、置き換えた設定値に読み替える
+// ICS_DAYS は UPCOMING_DAYS と ICS_ATTACHMENT に分けたため、UPCOMING_DAYS が未指定の場合のみ読み替える
+func (c *Config) applyDeprecated() {
+	if c.ICSDays <= 0 {
+		return
+	}
+	slog.Warn("ICS_DAYS is deprecated, use UPCOMING_DAYS and ICS_ATTACHMENT instead")
+	if c.UpcomingDays == 0 {
+		c.UpcomingDays = c.ICSDays
+		c.ICSAttachment = true
+	}
+}
+
+// 実行中に失敗しないように、設定値の形式を起動時にまとめて検証する
+func (c *Config) Validate() error {
+	errs := []error{
+		config.CheckSnowflake("DISCORD_CHANNEL_ID", c.DiscordChannelID),
+		config.CheckGoogleCredentials("GOOGLE_CREDENTIALS", c.GoogleCredentials),
+		config.CheckSpreadsheetID("GOOGLE_SPREADSHEET_ID", c.GoogleSpreadsheetID),
+	}
+	if c.GoogleChatWebhookURL != "" {
+		errs = append(errs, config.CheckURL("GOOGLE_CHAT_WEBHOOK_URL", c.GoogleChatWebhookURL))
+	}
+	if c.HolidaysURL != "" {
+		errs = append(errs, config.CheckURL("HOLIDAYS_URL", c.HolidaysURL))
+	}
+	if c.GomiSchedule != "" {
+		if _, err := gomi.Parse([]byte(c.GomiSchedule)); err != nil {
+			errs = append(errs, fmt.Errorf("invalid GOMI_SCHEDULE: %w", err))
+		}
+	}
+	if c.ShoppingRemindWeekdays != "" {
+		if _, err := event.ParseWeekdays(c.ShoppingRemindWeekdays); err != nil {
+			errs = append(errs, fmt.Errorf("invalid SHOPPING_REMIND_WEEKDAYS: %w", err))
+		}
+		if c.ShoppingTableName == "" {
+			errs = append(errs, errors.New("SHOPPING_TABLE_NAME is required to remind of the shopping list"))
+		}
+	}
+	if c.StockSheetTab != "" && c.StockExpiryDays < 0 {
+		errs = append(errs, errors.New("STOCK_EXPIRY_DAYS must not be negative"))
+	}
+	if c.LibraryTableName != "" && c.LibraryDueDays < 0 {
+		errs = append(errs, errors.New("LIBRARY_DUE_DAYS must not be negative"))
+	}
+	if c.PlantTableName != "" && c.PlantSensorMaxAge <= 0 {
+		errs = append(errs, errors.New("PLANT_SENSOR_MAX_AGE must be positive"))
+	}
+	for _, cal := range c.SchoolCalendars {
+		if cal.ICSURL != "" {
+			errs = append(errs, config.CheckURL("SCHOOL_CALENDARS", cal.ICSURL))
+		}
+	}
+	if c.HabitSummaryWeekdays != "" {
+		if _, err := event.ParseWeekdays(c.HabitSummaryWeekdays); err != nil {
+			errs = append(errs, fmt.Errorf("invalid HABIT_SUMMARY_WEEKDAYS: %w", err))
+		}
+	}
+	if err := civic.Validate(c.CivicDeadlines); err != nil {
+		errs = append(errs, fmt.Errorf("invalid CIVIC_DEADLINES: %w", err))
+	}
+	if c.CivicNotifyBefore < 0 {
+		errs = append(errs, errors.New("CIVIC_NOTIFY_BEFORE must not be negative"))
+	}
+	if err := document.ValidateMonths(c.DocumentRemindMonths); err != nil {
+		errs = append(errs, fmt.Errorf("invalid DOCUMENT_REMIND_MONTHS: %w", err))
+	}
+	if err := expiry.ValidateThresholds(c.ExpiryThresholds); err != nil {
+		errs = append(errs, fmt.Errorf("invalid EXPIRY_THRESHOLDS: %w", err))
+	}
+	if len(c.ExpiryDomains) > 0 {
+		errs = append(errs, config.CheckURL("RDAP_URL", c.RDAPURL))
+	}
+	if c.RemoToken != "" {
+		errs = append(errs, config.CheckURL("REMO_URL", c.RemoURL))
+	}
+	if c.SwitchBotToken != "" {
+		errs = append(errs, config.CheckURL("SWITCHBOT_URL", c.SwitchBotURL))
+		if c.SwitchBotSecret == "" {
+			errs = append(errs, errors.New("SWITCHBOT_SECRET is required to read SwitchBot meters"))
+		}
+	}
+	if c.ElectricityTableName != "" && c.ElectricityUnitPrice <= 0 {
+		errs = append(errs, errors.New("ELECTRICITY_UNIT_PRICE must be positive"))
+	}
+	if len(c.MedicationSchedule) > 0 {
+		if c.AckTableName == "" {
+			errs = append(errs, errors.New("ACK_TABLE_NAME is required to confirm medication"))
+		}
+		if c.MedicationEscalateAfter < c.IntradayEvery {
+			errs = append(errs, errors.New("MEDICATION_ESCALATE_AFTER must be at least INTRADAY_EVERY"))
+		}
+	}
+	if len(c.choreRotations()) > 0 && c.ChoreTableName == "" {
+		errs = append(errs, errors.New("CHORE_TABLE_NAME is required to rotate chores"))
+	}
+	if c.SentryDSN != "" {
+		errs = append(errs, config.CheckURL("SENTRY_DSN", c.SentryDSN))
+	}
+
+	return errors.Join(errs...)
+}
+
+// 投稿先が参照する設定を取り出す
+func (c *Config) notifyConfig(clk clock.Clock) *notify.Config {
+	var store notify.WebhookStore
+	if c.DiscordWebhookCache {
+		store = &ssmWebhookStore{appEnv: os.Getenv("APP_ENV")}
+	}
+
+	return &notify.Config{
+		HTTPClient:           httpclient.Default,
+		Clock:                clk,
+		DiscordBotName:       c.DiscordBotName,
+		DiscordBotToken:      c.DiscordBotToken,
+		DiscordChannelID:     c.DiscordChannelID,
+		DiscordUseThread:     c.DiscordUseThread,
+		WebhookStore:         store,
+		GoogleChatWebhookURL: c.GoogleChatWebhookURL,
+		NoEventsMode:         c.NoEventsMode,
+		Locale:               c.Locale,
+		ICSAttachment:        c.ICSAttachment,
+		AckEnabled:           c.AckTableName != "",
+		OverdueMention:       c.OverdueMention,
+	}
+}
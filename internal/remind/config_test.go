@@ -0,0 +1,60 @@
+package remind
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigApplyDeprecated(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  Config
+		want Config
+	}{
+		{
+			name: "正常系/ICS_DAYS を UPCOMING_DAYS と ICS_ATTACHMENT に読み替える",
+			cfg:  Config{ICSDays: 7},
+			want: Config{ICSDays: 7, UpcomingDays: 7, ICSAttachment: true},
+		},
+		{
+			name: "正常系/UPCOMING_DAYS を指定した場合は優先する",
+			cfg:  Config{ICSDays: 7, UpcomingDays: 14},
+			want: Config{ICSDays: 7, UpcomingDays: 14},
+		},
+		{
+			name: "正常系/ICS_DAYS が未指定の場合",
+			cfg:  Config{UpcomingDays: 14, ICSAttachment: true},
+			want: Config{UpcomingDays: 14, ICSAttachment: true},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.applyDeprecated()
+			assert.Equal(t, tt.want, tt.cfg)
+		})
+	}
+}
+
+// Lambda と常駐モードで呼び出しごとに読み込み直す設定でも、廃止した設定値を読み替える
+func TestRefreshConfigAppliesDeprecated(t *testing.T) {
+	for k, v := range map[string]string{
+		"DISCORD_BOT_NAME":      "remind",
+		"DISCORD_BOT_TOKEN":     "token",
+		"DISCORD_CHANNEL_ID":    "123456789012345678",
+		"GOOGLE_CREDENTIALS":    `{"type": "service_account", "client_email": "remind@example.com", "private_key": "key"}`,
+		"GOOGLE_SPREADSHEET_ID": "1AbCdEfGhIjKlMnOpQrStUvWxYz0123456789",
+		"CONFIG_S3_URI":         "",
+		"ICS_DAYS":              "7",
+	} {
+		t.Setenv(k, v)
+	}
+
+	cfg, err := refreshConfig(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 7, cfg.UpcomingDays)
+	assert.True(t, cfg.ICSAttachment)
+}
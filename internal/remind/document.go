@@ -0,0 +1,32 @@
+package remind
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/document"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/sources"
+)
+
+// パスポートや在留カードなどの有効期限を記録したシートを読み込む、シートが指定されていない場合は nil を返す
+// シートから読み込めなかった場合も他のイベントは投稿できるように、取得元のエラーとして返す
+func loadDocuments(ctx context.Context, r sources.SheetDataReader, cfg *Config, loc *time.Location) ([]document.Document, error) {
+	if cfg.DocumentSheetTab == "" {
+		return nil, nil
+	}
+	resp, err := r.GetValues(ctx, cfg.GoogleSpreadsheetID, fmt.Sprintf("%s!%s", cfg.DocumentSheetTab, document.Columns))
+	if err != nil {
+		slog.Warn("failed to get documents", slog.Any("error", err))
+		return nil, event.NewSourceUnavailableError(document.Tag, err)
+	}
+	docs, err := document.ParseRows(resp.Values, loc)
+	if err != nil {
+		slog.Warn("failed to parse documents", slog.Any("error", err))
+		return nil, event.NewParseError(document.Tag, cfg.DocumentSheetTab, err)
+	}
+
+	return docs, nil
+}
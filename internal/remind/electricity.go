@@ -0,0 +1,118 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/electricity"
+	"github.com/mami0tsu/homeops/internal/ledger"
+	"github.com/mami0tsu/homeops/internal/notify"
+)
+
+// Nature Remo E で取得したスマートメーターの積算電力量を記録し、前日の使用量を投稿する、e.g. {"mode": "electricity"}
+// 日付が変わった後に 1 日 1 回呼び出す
+const modeElectricity = "electricity"
+
+const electricityColor = 0xf1c40f
+
+type readingStore interface {
+	Put(ctx context.Context, r electricity.Reading) (bool, error)
+	Readings(ctx context.Context, loc *time.Location) ([]electricity.Reading, error)
+}
+
+type energyMeter interface {
+	CumulativeEnergy(ctx context.Context) (float64, time.Time, error)
+}
+
+func newReadingStore(client *dynamodb.Client, cfg *Config) (*electricity.Store, error) {
+	if cfg.ElectricityTableName == "" {
+		return nil, errors.New("ELECTRICITY_TABLE_NAME is not set")
+	}
+	if cfg.RemoToken == "" {
+		return nil, errors.New("REMO_TOKEN is not set")
+	}
+
+	return electricity.NewStore(client, cfg.ElectricityTableName), nil
+}
+
+// 当日の積算電力量を記録して、前日までの記録と合わせて使用量を投稿する
+// 前日の記録がない場合は記録のみ行う
+func runElectricity(ctx context.Context, clk clock.Clock, store readingStore, meter energyMeter, unitPrice float64, nc *notify.Config, dryRun bool) error {
+	kwh, _, err := meter.CumulativeEnergy(ctx)
+	if err != nil {
+		slog.Error("failed to get cumulative energy", slog.Any("error", err))
+		return err
+	}
+	now := clk.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, clk.Location())
+
+	// 再試行された場合は最初に記録した値で集計する
+	reading := electricity.Reading{Date: today, Wh: int64(math.Round(kwh * 1000))}
+	if !dryRun {
+		if _, err := store.Put(ctx, reading); err != nil {
+			slog.Error("failed to put reading", slog.Any("error", err))
+			return err
+		}
+	}
+	readings, err := store.Readings(ctx, clk.Location())
+	if err != nil {
+		slog.Error("failed to list readings", slog.Any("error", err))
+		return err
+	}
+	if !slices.ContainsFunc(readings, func(r electricity.Reading) bool { return r.Date.Equal(today) }) {
+		readings = append(readings, reading)
+	}
+	report, ok := electricity.NewReport(readings, today)
+	if !ok {
+		slog.Info("no reading for yesterday", slog.Float64("kwh", kwh))
+		return nil
+	}
+	embed := createElectricityEmbed(report, unitPrice)
+
+	// 投稿せずに投稿内容を確認する
+	if dryRun {
+		slog.Info("dry run", slog.String("title", embed.Title), slog.Int64("wh", report.Day.Wh))
+		return nil
+	}
+
+	if err := notify.PostDiscordMessages(ctx, nc, &discord.WebhookMessage{Embeds: []*discord.Embed{embed}}); err != nil {
+		slog.Error("failed to post electricity report", slog.Any("error", err))
+		return err
+	}
+	slog.Info("succeeded to post electricity report", slog.Int64("wh", report.Day.Wh))
+
+	return nil
+}
+
+func createElectricityEmbed(r electricity.Report, unitPrice float64) *discord.Embed {
+	embed := discord.NewEmbed(fmt.Sprintf("⚡ %s の電気の使用量", r.Day.From.Format("1/2")), electricityColor)
+	embed.AddField("使用量", formatUsage(r.Day, unitPrice))
+	if r.Month == nil {
+		return embed
+	}
+
+	// 月の初めは前月の合計になる
+	name := "今月"
+	if r.Month.To.AddDate(0, 0, 1).Day() == 1 {
+		name = fmt.Sprintf("%d 月の合計", r.Month.From.Month())
+	}
+	embed.AddField(name, fmt.Sprintf("%s〜%s %s", r.Month.From.Format("1/2"), r.Month.To.Format("1/2"), formatUsage(*r.Month, unitPrice)))
+	if r.LastMonth != nil {
+		delta := r.Month.Cost(unitPrice) - r.LastMonth.Cost(unitPrice)
+		embed.AddField("前月の同じ期間", fmt.Sprintf("%s〜%s %s (%s)", r.LastMonth.From.Format("1/2"), r.LastMonth.To.Format("1/2"), formatUsage(*r.LastMonth, unitPrice), ledger.FormatDelta(delta)))
+	}
+
+	return embed
+}
+
+func formatUsage(u electricity.Usage, unitPrice float64) string {
+	return fmt.Sprintf("%.1fkWh (約 %s)", u.KWh(), ledger.FormatYen(u.Cost(unitPrice)))
+}
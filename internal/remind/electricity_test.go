@@ -0,0 +1,100 @@
+package remind
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/electricity"
+	"github.com/mami0tsu/homeops/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReadingStore struct {
+	readings []electricity.Reading
+}
+
+func (s *fakeReadingStore) Put(ctx context.Context, r electricity.Reading) (bool, error) {
+	for _, v := range s.readings {
+		if v.Date.Equal(r.Date) {
+			return false, nil
+		}
+	}
+	s.readings = append(s.readings, r)
+	return true, nil
+}
+
+func (s *fakeReadingStore) Readings(ctx context.Context, loc *time.Location) ([]electricity.Reading, error) {
+	return s.readings, nil
+}
+
+type fakeEnergyMeter float64
+
+func (m fakeEnergyMeter) CumulativeEnergy(ctx context.Context) (float64, time.Time, error) {
+	return float64(m), time.Time{}, nil
+}
+
+func TestRunElectricity(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	jst := clock.JST()
+	srv := testsupport.NewServer(t)
+	clk := clock.Fixed(time.Date(2025, 3, 3, 0, 10, 0, 0, jst))
+	cfg := &Config{DiscordBotName: "remind", DiscordBotToken: "token", DiscordChannelID: "123"}
+	nc := cfg.notifyConfig(clk)
+	nc.HTTPClient = srv.Client()
+	store := &fakeReadingStore{readings: []electricity.Reading{
+		{Date: time.Date(2025, 2, 1, 0, 0, 0, 0, jst), Wh: 900000},
+		{Date: time.Date(2025, 2, 3, 0, 0, 0, 0, jst), Wh: 920500},
+		{Date: time.Date(2025, 3, 1, 0, 0, 0, 0, jst), Wh: 1000000},
+		{Date: time.Date(2025, 3, 2, 0, 0, 0, 0, jst), Wh: 1008000},
+	}}
+
+	tr.NoError(runElectricity(context.Background(), clk, store, fakeEnergyMeter(1017.45), 31, nc, false))
+
+	// 当日の値を記録して、前日と今月と前月の同じ期間の使用量を投稿する
+	ta.Equal(electricity.Reading{Date: time.Date(2025, 3, 3, 0, 0, 0, 0, jst), Wh: 1017450}, store.readings[4])
+	msgs := srv.Discord.Messages()
+	tr.Len(msgs, 1)
+	embed := msgs[0].Embeds[0]
+	ta.Equal("⚡ 3/2 の電気の使用量", embed.Title)
+	tr.Len(embed.Fields, 3)
+	ta.Equal("9.4kWh (約 ¥293)", embed.Fields[0].Value)
+	ta.Equal("今月", embed.Fields[1].Name)
+	ta.Equal("3/1〜3/2 17.4kWh (約 ¥541)", embed.Fields[1].Value)
+	ta.Equal("2/1〜2/2 20.5kWh (約 ¥636) (-¥95)", embed.Fields[2].Value)
+}
+
+func TestRunElectricityFirstReading(t *testing.T) {
+	ta := assert.New(t)
+
+	srv := testsupport.NewServer(t)
+	clk := clock.Fixed(time.Date(2025, 3, 3, 0, 10, 0, 0, clock.JST()))
+	cfg := &Config{DiscordBotName: "remind", DiscordBotToken: "token", DiscordChannelID: "123"}
+	nc := cfg.notifyConfig(clk)
+	nc.HTTPClient = srv.Client()
+	store := &fakeReadingStore{}
+
+	// 前日の記録がない場合は記録のみ行う
+	ta.NoError(runElectricity(context.Background(), clk, store, fakeEnergyMeter(1000), 31, nc, false))
+	ta.Len(store.readings, 1)
+	ta.Empty(srv.Discord.Messages())
+}
+
+func TestCreateElectricityEmbedMonthTotal(t *testing.T) {
+	ta := assert.New(t)
+
+	date := func(month time.Month, day int) time.Time { return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC) }
+	r := electricity.Report{
+		Day:   electricity.Usage{From: date(2, 28), To: date(2, 28), Wh: 10000},
+		Month: &electricity.Usage{From: date(2, 1), To: date(2, 28), Wh: 280000},
+	}
+
+	embed := createElectricityEmbed(r, 31)
+	ta.Equal("⚡ 2/28 の電気の使用量", embed.Title)
+	ta.Equal("2 月の合計", embed.Fields[1].Name)
+	ta.Equal("2/1〜2/28 280.0kWh (約 ¥8,680)", embed.Fields[1].Value)
+}
@@ -0,0 +1,81 @@
+package remind
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/ledger"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/sources"
+)
+
+// 前月の支出をカテゴリごとに集計して投稿する、e.g. {"mode": "expense"}
+const modeExpense = "expense"
+
+const (
+	expenseSourceName = "expense"
+	expenseColor      = 0xf1c40f
+)
+
+// 実行日の前月の支出を、前々月との差額とあわせて投稿する
+// 月初に実行することを想定し、date を指定した場合はその日付の前月を集計する
+func runExpenseSummary(ctx context.Context, r sources.SheetDataReader, guard *RunGuard, runID string, cfg *Config, nc *notify.Config, today time.Time, dryRun bool) error {
+	resp, err := r.GetValues(ctx, cfg.GoogleSpreadsheetID, fmt.Sprintf("%s!%s", cfg.ExpenseSheetTab, ledger.Columns))
+	if err != nil {
+		slog.Error("failed to get expenses", slog.Any("error", err))
+		return event.NewSourceUnavailableError(expenseSourceName, err)
+	}
+	entries, err := ledger.ParseRows(resp.Values, today.Location())
+	if err != nil {
+		slog.Error("failed to parse expenses", slog.Any("error", err))
+		return event.NewParseError(expenseSourceName, cfg.ExpenseSheetTab, err)
+	}
+
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location()).AddDate(0, -1, 0)
+	s := ledger.Summarize(entries, month)
+	embed := createExpenseEmbed(s)
+
+	// 投稿せずに投稿内容を確認する
+	if dryRun {
+		slog.Info("dry run", slog.String("summary", renderExpenseSummary(s)))
+		return nil
+	}
+
+	_, err = guard.Once(ctx, today, runID, func() error {
+		return notify.PostDiscordMessages(ctx, nc, &discord.WebhookMessage{Embeds: []*discord.Embed{embed}})
+	})
+	if err != nil {
+		slog.Error("failed to post expense summary", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func createExpenseEmbed(s ledger.Summary) *discord.Embed {
+	embed := discord.NewEmbed(fmt.Sprintf("💴 %s の支出", s.Month.Format("2006年1月")), expenseColor)
+	embed.Description = fmt.Sprintf("合計 %s (前月比 %s)", ledger.FormatYen(s.Total()), ledger.FormatDelta(s.Total()-s.Previous()))
+	if len(s.Categories) == 0 {
+		embed.Description = "記録された支出はありません"
+	}
+	for _, c := range s.Categories {
+		embed.AddField(c.Category, fmt.Sprintf("%s (前月比 %s)", ledger.FormatYen(c.Total), ledger.FormatDelta(c.Delta())))
+	}
+
+	return embed
+}
+
+// dry run で出力する
+func renderExpenseSummary(s ledger.Summary) string {
+	e := createExpenseEmbed(s)
+	text := e.Title + "\n" + e.Description
+	for _, f := range e.Fields {
+		text += fmt.Sprintf("\n- %s: %s", f.Name, f.Value)
+	}
+
+	return text
+}
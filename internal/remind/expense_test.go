@@ -0,0 +1,74 @@
+package remind
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/ledger"
+	"github.com/mami0tsu/homeops/internal/sources"
+	"github.com/mami0tsu/homeops/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateExpenseEmbed(t *testing.T) {
+	ta := assert.New(t)
+
+	s := ledger.Summary{
+		Month: time.Date(2025, 2, 1, 0, 0, 0, 0, tz),
+		Categories: []ledger.CategoryTotal{
+			{Category: "食費", Total: 32000, Previous: 30000},
+			{Category: "日用品", Previous: 1500},
+		},
+	}
+	e := createExpenseEmbed(s)
+
+	ta.Equal("💴 2025年2月 の支出", e.Title)
+	ta.Equal("合計 ¥32,000 (前月比 +¥500)", e.Description)
+	ta.Len(e.Fields, 2)
+	ta.Equal("¥32,000 (前月比 +¥2,000)", e.Fields[0].Value)
+	ta.Equal("¥0 (前月比 -¥1,500)", e.Fields[1].Value)
+
+	ta.Equal("記録された支出はありません", createExpenseEmbed(ledger.Summary{Month: s.Month}).Description)
+}
+
+// 月初に実行した場合は前月の支出を集計して投稿する
+func TestRunExpenseSummary(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	s := testsupport.NewServer(t)
+	s.Sheets.SetValues("spreadsheet", "expense", [][]any{
+		{"日付", "金額", "カテゴリ", "メモ", "記録した人"},
+		{"2025-01-20", "1000", "食費", "", "alice"},
+		{"2025-02-03", "1200", "食費", "ランチ", "alice"},
+		{"2025-02-27", "800", "外食", "", "bob"},
+		{"2025-03-01", "5000", "食費", "", "bob"},
+	})
+
+	ctx := context.Background()
+	clk := clock.Fixed(time.Date(2025, 3, 1, 9, 0, 0, 0, clock.JST()))
+	cfg := &Config{
+		DiscordBotName:      "remind",
+		DiscordBotToken:     "token",
+		DiscordChannelID:    "123",
+		GoogleSpreadsheetID: "spreadsheet",
+		ExpenseSheetTab:     "expense",
+	}
+	srv, err := s.SheetsService(ctx)
+	tr.NoError(err)
+
+	nc := cfg.notifyConfig(clk)
+	nc.HTTPClient = s.Client()
+
+	err = runExpenseSummary(ctx, &sources.GoogleSheetReader{Service: srv}, nil, "run", cfg, nc, clock.Today(clk), false)
+	tr.NoError(err)
+
+	msgs := s.Discord.Messages()
+	tr.Len(msgs, 1)
+	tr.Len(msgs[0].Embeds, 1)
+	ta.Equal("💴 2025年2月 の支出", msgs[0].Embeds[0].Title)
+	ta.Equal("合計 ¥2,000 (前月比 +¥1,000)", msgs[0].Embeds[0].Description)
+}
@@ -0,0 +1,23 @@
+package remind
+
+import (
+	"context"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/expiry"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+)
+
+// ドメインの登録と TLS 証明書の有効期限を確認する、確認する対象がない場合は nil を返す
+// 一部の確認に失敗した場合も他のイベントは投稿できるように、取得元のエラーとして返す
+func checkExpiry(ctx context.Context, cfg *Config) ([]expiry.Result, error) {
+	if len(cfg.ExpiryDomains) == 0 && len(cfg.ExpiryCertHosts) == 0 {
+		return nil, nil
+	}
+	results, err := expiry.Check(ctx, httpclient.Default, cfg.RDAPURL, cfg.ExpiryDomains, cfg.ExpiryCertHosts)
+	if err != nil {
+		return results, event.NewSourceUnavailableError(expiry.Tag, err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,37 @@
+package remind
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/buildinfo"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+)
+
+const failureNotifyTimeout = 10 * time.Second
+
+// 実行そのものが失敗したことを、投稿先とは独立した Webhook に通知する
+func notifyFailure(ctx context.Context, url string, cause error) {
+	if url == "" {
+		return
+	}
+
+	content := fmt.Sprintf("⚠ remind (%s, %s) failed: %s", os.Getenv("APP_ENV"), buildinfo.Get(), cause)
+	if err := sendWebhook(ctx, url, content); err != nil {
+		slog.Error("failed to send failure notification", slog.Any("error", err))
+		return
+	}
+	slog.Info("sent failure notification")
+}
+
+// Discord の Webhook 形式で送る、ctx がキャンセルされていても送れるように独立したタイムアウトを使う
+func sendWebhook(ctx context.Context, url, content string) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), failureNotifyTimeout)
+	defer cancel()
+
+	return discord.NewClient(httpclient.Default, "").ExecuteWebhook(ctx, url, &discord.WebhookMessage{Content: content})
+}
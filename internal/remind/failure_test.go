@@ -0,0 +1,84 @@
+package remind
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyFailure(t *testing.T) {
+	cause := errors.New("sheet: connection refused")
+
+	cases := []struct {
+		name      string
+		url       bool // 通知先を指定する
+		status    int
+		canceled  bool
+		wantCalls int
+	}{
+		{
+			name:      "正常系/通知先に失敗の原因を送る",
+			url:       true,
+			status:    http.StatusNoContent,
+			wantCalls: 1,
+		},
+		{
+			name:      "正常系/呼び出しがキャンセルされていても送る",
+			url:       true,
+			status:    http.StatusNoContent,
+			canceled:  true,
+			wantCalls: 1,
+		},
+		{
+			name:      "正常系/通知先を指定していない場合は送らない",
+			url:       false,
+			wantCalls: 0,
+		},
+		{
+			name:      "異常系/通知先がエラーを返しても処理を続ける",
+			url:       true,
+			status:    http.StatusNotFound,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			t.Setenv("APP_ENV", "prd")
+
+			var got []discord.WebhookMessage
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var msg discord.WebhookMessage
+				ta.NoError(json.NewDecoder(r.Body).Decode(&msg))
+				got = append(got, msg)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			url := ""
+			if tt.url {
+				url = srv.URL
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.canceled {
+				cancel()
+			}
+			defer cancel()
+
+			notifyFailure(ctx, url, cause)
+
+			ta.Len(got, tt.wantCalls)
+			for _, msg := range got {
+				ta.Contains(msg.Content, "⚠ remind (prd, ")
+				ta.Contains(msg.Content, "failed: sheet: connection refused")
+			}
+		})
+	}
+}
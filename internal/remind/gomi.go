@@ -0,0 +1,34 @@
+package remind
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/gomi"
+	"github.com/mami0tsu/homeops/internal/sources"
+)
+
+// ゴミの収集日の規則を読み込む、指定されていない場合は nil を返す
+// シートから読み込めなかった場合も他のイベントは投稿できるように、取得元のエラーとして返す
+func loadGomi(ctx context.Context, r sources.SheetDataReader, cfg *Config) (*gomi.Calendar, error) {
+	switch {
+	case cfg.GomiSheetTab != "":
+		resp, err := r.GetValues(ctx, cfg.GoogleSpreadsheetID, fmt.Sprintf("%s!A:F", cfg.GomiSheetTab))
+		if err != nil {
+			slog.Warn("failed to get gomi schedule", slog.Any("error", err))
+			return nil, event.NewSourceUnavailableError(gomi.Tag, err)
+		}
+		c, err := gomi.ParseRows(resp.Values)
+		if err != nil {
+			slog.Warn("failed to parse gomi schedule", slog.Any("error", err))
+			return nil, event.NewParseError(gomi.Tag, cfg.GomiSheetTab, err)
+		}
+		return c, nil
+	case cfg.GomiSchedule != "":
+		return gomi.Parse([]byte(cfg.GomiSchedule))
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,138 @@
+package remind
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+const (
+	runPartitionKey = "run"
+	runRetention    = 7 * 24 * time.Hour
+	runDefaultLease = 15 * time.Minute // 呼び出しに期限がない場合に、投稿中の記録を有効とする時間
+)
+
+// 実行ごとの投稿状況
+type runStatus string
+
+const (
+	runStarted runStatus = "started"
+	runPosted  runStatus = "posted"
+)
+
+// EventBridge による再試行などで、同じ実行の投稿が重複しないように投稿状況を DynamoDB に記録する
+// テーブルのキーは pk (パーティションキー) と sk (ソートキー、"<yyyymmdd>#<実行 ID>") とする
+// 複数の投稿先に投稿する場合は、実行 ID に投稿先の名前を付けて投稿先ごとに記録する
+type RunGuard struct {
+	client runTable
+	table  string
+	now    func() time.Time
+}
+
+// 投稿状況を記録するテーブルの操作
+type runTable interface {
+	PutItem(ctx context.Context, table string, item dynamodb.Item, condition string, values dynamodb.Item) error
+	UpdateItem(ctx context.Context, table string, key dynamodb.Item, update string, values dynamodb.Item) error
+	DeleteItem(ctx context.Context, table string, key dynamodb.Item) error
+}
+
+func NewRunGuard(client *dynamodb.Client, table string) *RunGuard {
+	return &RunGuard{client: client, table: table, now: time.Now}
+}
+
+// 投稿状況を記録しない場合は nil を返す
+func newRunGuard(client *dynamodb.Client, cfg *Config) *RunGuard {
+	if cfg.IdempotencyTableName == "" {
+		return nil
+	}
+
+	return NewRunGuard(client, cfg.IdempotencyTableName)
+}
+
+func runSortKey(date time.Time, runID string) string {
+	return date.Format(event.AckDateFormat) + "#" + runID
+}
+
+// 呼び出しの内容から実行 ID を作成する、同じ内容で再試行された呼び出しは同じ実行として扱う
+// 同じ内容で再度投稿したい場合は、Payload の RunID に別の値を指定する
+func createRunID(p Payload) string {
+	if p.RunID != "" {
+		return p.RunID
+	}
+	b, _ := json.Marshal(Payload{Mode: p.Mode, Dates: p.Dates, Tags: p.Tags, ExcludeTags: p.ExcludeTags, Channel: p.Channel, LookaheadDays: p.LookaheadDays})
+	h := sha256.Sum256(b)
+	mode := p.Mode
+	if mode == "" {
+		mode = modeDaily
+	}
+
+	return mode + "#" + hex.EncodeToString(h[:8])
+}
+
+// post を実行日と実行 ID ごとに 1 度だけ実行する
+// 既に投稿済み、もしくは他の呼び出しが投稿中であれば post を実行せずに false を返す
+// post が失敗した場合は、再試行で投稿できるように記録を削除する
+// g が nil の場合は常に post を実行する
+func (g *RunGuard) Once(ctx context.Context, date time.Time, runID string, post func() error) (bool, error) {
+	if g == nil {
+		return true, post()
+	}
+
+	claimed, err := g.claim(ctx, date, runID)
+	if err != nil {
+		return false, err
+	}
+	if !claimed {
+		slog.Info("skipped already posted run", slog.String("run_id", runID))
+		return false, nil
+	}
+
+	key := dynamodb.Item{"pk": dynamodb.S(runPartitionKey), "sk": dynamodb.S(runSortKey(date, runID))}
+	if err := post(); err != nil {
+		if err := g.client.DeleteItem(ctx, g.table, key); err != nil {
+			slog.Warn("failed to release run", slog.String("run_id", runID), slog.Any("error", err))
+		}
+		return true, err
+	}
+	if err := g.client.UpdateItem(ctx, g.table, key, "SET run_status = :s", dynamodb.Item{":s": dynamodb.S(string(runPosted))}); err != nil {
+		// 投稿は完了しているため、記録に失敗しても呼び出しは失敗させない
+		slog.Warn("failed to record posted run", slog.String("run_id", runID), slog.Any("error", err))
+	}
+
+	return true, nil
+}
+
+// 投稿中として記録する、投稿中のまま期限を過ぎた記録 (タイムアウトなど) は上書きする
+func (g *RunGuard) claim(ctx context.Context, date time.Time, runID string) (bool, error) {
+	now := g.now()
+	leaseUntil, ok := ctx.Deadline()
+	if !ok {
+		leaseUntil = now.Add(runDefaultLease)
+	}
+
+	item := dynamodb.Item{
+		"pk":          dynamodb.S(runPartitionKey),
+		"sk":          dynamodb.S(runSortKey(date, runID)),
+		"run_status":  dynamodb.S(string(runStarted)),
+		"lease_until": dynamodb.N(leaseUntil.Unix()),
+		"expires_at":  dynamodb.N(date.Add(runRetention).Unix()),
+	}
+	err := g.client.PutItem(ctx, g.table, item, "attribute_not_exists(pk) OR (run_status = :started AND lease_until < :now)", dynamodb.Item{
+		":started": dynamodb.S(string(runStarted)),
+		":now":     dynamodb.N(now.Unix()),
+	})
+	if dynamodb.IsConditionalCheckFailed(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
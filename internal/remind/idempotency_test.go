@@ -0,0 +1,110 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/digest"
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRunID(t *testing.T) {
+	ta := assert.New(t)
+
+	// 実行日や投稿しない指定によらず、同じ内容の呼び出しは同じ実行として扱う
+	ta.Equal(createRunID(Payload{}), createRunID(Payload{Date: "2025-03-01", DryRun: true}))
+	ta.Regexp(`^daily#[0-9a-f]{16}$`, createRunID(Payload{}))
+	ta.Regexp(`^intraday#[0-9a-f]{16}$`, createRunID(Payload{Mode: modeIntraday}))
+
+	// 絞り込みが異なる呼び出しは別の実行として扱う
+	ta.NotEqual(createRunID(Payload{Tags: []string{"evening"}}), createRunID(Payload{Tags: []string{"morning"}}))
+	ta.NotEqual(createRunID(Payload{}), createRunID(Payload{ExcludeTags: []string{"school"}}))
+
+	// 指定された実行 ID を優先する
+	ta.Equal("rerun-1", createRunID(Payload{RunID: "rerun-1"}))
+}
+
+func TestRunSortKey(t *testing.T) {
+	ta := assert.New(t)
+	ta.Equal("20250301#daily#0123456789abcdef", runSortKey(time.Date(2025, 3, 1, 0, 0, 0, 0, tz), "daily#0123456789abcdef"))
+}
+
+// 投稿状況を記録するテーブルを模した実装
+// RunGuard の条件付き書き込みと同じく、投稿済みの記録と期限内の投稿中の記録は上書きしない
+type fakeRunTable struct {
+	items map[string]dynamodb.Item
+}
+
+func (f *fakeRunTable) PutItem(ctx context.Context, table string, item dynamodb.Item, condition string, values dynamodb.Item) error {
+	if cur, ok := f.items[item.Str("sk")]; ok {
+		if cur.Str("run_status") != string(runStarted) || cur.Num("lease_until") >= values.Num(":now") {
+			return &dynamodb.Error{Type: "ConditionalCheckFailedException"}
+		}
+	}
+	f.items[item.Str("sk")] = item
+
+	return nil
+}
+
+func (f *fakeRunTable) UpdateItem(ctx context.Context, table string, key dynamodb.Item, update string, values dynamodb.Item) error {
+	f.items[key.Str("sk")]["run_status"] = values[":s"]
+
+	return nil
+}
+
+func (f *fakeRunTable) DeleteItem(ctx context.Context, table string, key dynamodb.Item) error {
+	delete(f.items, key.Str("sk"))
+
+	return nil
+}
+
+type failingSink struct {
+	recordSink
+	fails int
+}
+
+func (s *failingSink) Post(ctx context.Context, d event.Digest) error {
+	if s.fails > 0 {
+		s.fails--
+		return errors.New("webhook is unavailable")
+	}
+
+	return s.recordSink.Post(ctx, d)
+}
+
+// 一部の投稿先のみ失敗した場合、再試行では投稿済みの投稿先に重複して投稿しない
+func TestPostOnceRetriesFailedSinks(t *testing.T) {
+	ta := assert.New(t)
+	ctx := context.Background()
+
+	table := &fakeRunTable{items: map[string]dynamodb.Item{}}
+	guard := &RunGuard{client: table, table: "remind", now: time.Now}
+	discord := &recordSink{name: "discord"}
+	chat := &failingSink{recordSink: recordSink{name: "google_chat"}, fails: 1}
+	a := digest.NewApp(nil, discord, chat)
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, tz)
+
+	deliveries, err := a.PostOnce(ctx, guard, date, "daily#test", event.Digest{})
+	ta.ErrorContains(err, "webhook is unavailable")
+	ta.Equal([]digest.Delivery{{Sink: "discord"}, {Sink: "google_chat", Error: "webhook is unavailable"}}, deliveries)
+	ta.Equal(1, discord.posted)
+	ta.Equal(0, chat.posted)
+
+	// 再試行では失敗した投稿先にのみ投稿する
+	deliveries, err = a.PostOnce(ctx, guard, date, "daily#test", event.Digest{})
+	ta.NoError(err)
+	ta.Equal([]digest.Delivery{{Sink: "google_chat"}}, deliveries)
+	ta.Equal(1, discord.posted)
+	ta.Equal(1, chat.posted)
+
+	// 全ての投稿先に投稿済みの場合は投稿しない
+	deliveries, err = a.PostOnce(ctx, guard, date, "daily#test", event.Digest{})
+	ta.NoError(err)
+	ta.Empty(deliveries)
+	ta.Equal(1, discord.posted)
+	ta.Equal(1, chat.posted)
+}
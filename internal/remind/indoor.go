@@ -0,0 +1,30 @@
+package remind
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/indoor"
+	"github.com/mami0tsu/homeops/internal/remo"
+	"github.com/mami0tsu/homeops/internal/switchbot"
+)
+
+// Nature Remo と SwitchBot の温湿度計の値を取得する、トークンが未設定のものは取得しない
+// 一方の取得に失敗した場合も他のイベントは投稿できるように、取得できた値と取得元のエラーを返す
+func loadIndoorReadings(ctx context.Context, cfg *Config) ([]indoor.Reading, error) {
+	var readings []indoor.Reading
+	var errs []error
+	if cfg.RemoToken != "" {
+		r, err := remo.NewClient(httpclient.Default, cfg.RemoURL, cfg.RemoToken).Readings(ctx)
+		readings = append(readings, r...)
+		errs = append(errs, err)
+	}
+	if cfg.SwitchBotToken != "" {
+		r, err := switchbot.NewClient(httpclient.Default, cfg.SwitchBotURL, cfg.SwitchBotToken, cfg.SwitchBotSecret).Readings(ctx)
+		readings = append(readings, r...)
+		errs = append(errs, err)
+	}
+
+	return readings, errors.Join(errs...)
+}
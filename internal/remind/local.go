@@ -0,0 +1,89 @@
+package remind
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/digest"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/flags"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/sources"
+)
+
+// Lambda を呼び出さずにローカルで実行するための、Lambda と同じ取得元と投稿先
+// 投稿内容の作成と書き出しは cmd/remind-cli で行う
+type Local struct {
+	Source   sources.Source
+	Sink     *notify.DiscordWebhookSink
+	Options  digest.Options
+	Dates    []time.Time // 投稿対象の日付
+	Failures []error     // 取得に失敗したが処理は続けられる取得元
+	acks     *AckStore
+	flags    *flags.Set
+}
+
+// 設定を読み込み、today を実行日とした取得元と Discord の投稿先を作成する
+func NewLocal(ctx context.Context, clk clock.Clock, today time.Time) (*Local, error) {
+	c, err := newClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cfg := c.cfg
+	dates, err := targetDates(today, cfg.LookaheadDays, nil)
+	if err != nil {
+		slog.Error("failed to parse target dates", slog.Any("error", err))
+		return nil, err
+	}
+
+	ctx = withFlags(ctx)
+	var failures []error
+	holidays, err := loadHolidays(ctx, cfg)
+	if err != nil {
+		failures = append(failures, err)
+	}
+	calendar, err := loadGomi(ctx, c.sheets, cfg)
+	if err != nil {
+		failures = append(failures, err)
+	}
+	in, loadErrs := loadDigestInputs(ctx, c.sheets, cfg, today.Location())
+	failures = append(failures, loadErrs...)
+	extras, err := newExtraSources(c.dynamodb, cfg, holidays, calendar, in, today)
+	if err != nil {
+		slog.Error("failed to init source", slog.Any("error", err))
+		return nil, err
+	}
+	src, err := newSource(c.sheets, cfg, holidays, nil, nil, extras...)
+	if err != nil {
+		slog.Error("failed to init source", slog.Any("error", err))
+		return nil, err
+	}
+	format, err := notify.ParseOutputFormat(cfg.SinkFormats["discord"])
+	if err != nil {
+		slog.Error("failed to init sinks", slog.Any("error", err))
+		return nil, err
+	}
+	acks := newAckStore(c.dynamodb, cfg)
+
+	return &Local{
+		Source:   src,
+		Sink:     notify.NewDiscordWebhookSink(cfg.notifyConfig(clk), format),
+		Options:  digestOptions(acks, cfg),
+		Dates:    dates,
+		Failures: failures,
+		acks:     acks,
+		flags:    flags.FromContext(ctx),
+	}, nil
+}
+
+// Lambda と同じく、読み込んだ機能フラグを ctx に設定する
+func (l *Local) Context(ctx context.Context) context.Context {
+	return flags.WithSet(ctx, l.flags)
+}
+
+// Lambda と同じく、投稿した当日のイベントを未対応として記録する
+func (l *Local) RegisterAcks(ctx context.Context, today time.Time, d event.Digest) error {
+	return registerAcks(ctx, l.acks, today, d)
+}
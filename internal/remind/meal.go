@@ -0,0 +1,83 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/meal"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/sources"
+)
+
+// レシピから翌日からの 1 週間の夕食の献立を提案して投稿する、e.g. {"mode": "meal"}
+// 日曜日に呼び出し、入れ替えと採用は hello がボタンの操作で行う
+const modeMeal = "meal"
+
+const mealSourceName = "meal"
+
+type planStore interface {
+	Put(ctx context.Context, p meal.Plan) (bool, error)
+}
+
+func newPlanStore(client *dynamodb.Client, cfg *Config) (*meal.Store, error) {
+	if cfg.MealTableName == "" {
+		return nil, errors.New("MEAL_TABLE_NAME is not set")
+	}
+	if cfg.MealRecipeSheetTab == "" {
+		return nil, errors.New("MEAL_RECIPE_SHEET_TAB is not set")
+	}
+
+	return meal.NewStore(client, cfg.MealTableName), nil
+}
+
+// 提案した献立を保存して、ボタンとともに投稿する
+// 再試行された場合も同じ献立になるように、献立の初日から乱数を作る
+func runMeal(ctx context.Context, r sources.SheetDataReader, store planStore, guard *RunGuard, runID string, cfg *Config, nc *notify.Config, today time.Time, dryRun bool) error {
+	resp, err := r.GetValues(ctx, cfg.GoogleSpreadsheetID, fmt.Sprintf("%s!%s", cfg.MealRecipeSheetTab, meal.RecipeColumns))
+	if err != nil {
+		slog.Error("failed to get recipes", slog.Any("error", err))
+		return event.NewSourceUnavailableError(mealSourceName, err)
+	}
+	recipes := meal.ParseRecipes(resp.Values)
+	if len(recipes) == 0 {
+		slog.Error("no recipes found", slog.String("sheet_tab", cfg.MealRecipeSheetTab))
+		return event.NewParseError(mealSourceName, cfg.MealRecipeSheetTab, errors.New("no recipes"))
+	}
+
+	start := today.AddDate(0, 0, 1)
+	p := meal.Propose(recipes, start, rand.New(rand.NewPCG(uint64(start.Unix()), 0)))
+	msg := &discord.WebhookMessage{Content: p.Content(), Components: p.Components()}
+
+	// 投稿せずに投稿内容を確認する
+	if dryRun {
+		slog.Info("dry run", slog.String("meal", msg.Content))
+		return nil
+	}
+
+	// 投稿済みの場合は、ボタンで入れ替えた献立を上書きしないように保存もしない
+	_, err = guard.Once(ctx, today, runID, func() error {
+		// 採用済みの献立がある場合は提案し直さない
+		proposed, err := store.Put(ctx, p)
+		if err != nil {
+			return err
+		}
+		if !proposed {
+			slog.Info("meal plan is already accepted", slog.String("start", start.Format("2006-01-02")))
+			return nil
+		}
+		return notify.PostDiscordMessages(ctx, nc, msg)
+	})
+	if err != nil {
+		slog.Error("failed to post meal plan", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package remind
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/meal"
+	"github.com/mami0tsu/homeops/internal/sources"
+	"github.com/mami0tsu/homeops/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePlanStore struct {
+	plans    []meal.Plan
+	accepted bool
+}
+
+func (s *fakePlanStore) Put(ctx context.Context, p meal.Plan) (bool, error) {
+	if s.accepted {
+		return false, nil
+	}
+	s.plans = append(s.plans, p)
+	return true, nil
+}
+
+func TestRunMeal(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.Fixed(time.Date(2025, 3, 2, 17, 0, 0, 0, clock.JST()))
+	cfg := &Config{
+		DiscordBotName:      "remind",
+		DiscordBotToken:     "token",
+		DiscordChannelID:    "123",
+		GoogleSpreadsheetID: "spreadsheet",
+		MealRecipeSheetTab:  "recipes",
+	}
+	setup := func(t *testing.T) (*testsupport.Server, sources.SheetDataReader) {
+		s := testsupport.NewServer(t)
+		s.Sheets.SetValues("spreadsheet", "recipes", [][]any{
+			{"料理", "材料"},
+			{"カレー", "じゃがいも、にんじん"},
+			{"焼き魚", "鮭"},
+			{"肉じゃが", "じゃがいも、牛肉"},
+		})
+		srv, err := s.SheetsService(ctx)
+		require.NoError(t, err)
+		return s, &sources.GoogleSheetReader{Service: srv}
+	}
+
+	t.Run("正常系/翌日からの献立をボタンとともに投稿する", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		s, r := setup(t)
+		nc := cfg.notifyConfig(clk)
+		nc.HTTPClient = s.Client()
+		store := &fakePlanStore{}
+
+		tr.NoError(runMeal(ctx, r, store, nil, "run", cfg, nc, clock.Today(clk), false))
+		tr.Len(store.plans, 1)
+		ta.Equal(time.Date(2025, 3, 3, 0, 0, 0, 0, clock.JST()), store.plans[0].Start)
+		ta.Len(store.plans[0].Dinners, meal.Days)
+
+		msgs := s.Discord.Messages()
+		tr.Len(msgs, 1)
+		ta.Equal(store.plans[0].Content(), msgs[0].Content)
+		ta.Len(msgs[0].Components, 3)
+	})
+
+	t.Run("正常系/採用済みの場合は投稿しない", func(t *testing.T) {
+		s, r := setup(t)
+		nc := cfg.notifyConfig(clk)
+		nc.HTTPClient = s.Client()
+
+		require.NoError(t, runMeal(ctx, r, &fakePlanStore{accepted: true}, nil, "run", cfg, nc, clock.Today(clk), false))
+		assert.Empty(t, s.Discord.Messages())
+	})
+
+	t.Run("異常系/レシピがない場合", func(t *testing.T) {
+		s := testsupport.NewServer(t)
+		s.Sheets.SetValues("spreadsheet", "recipes", [][]any{{"料理", "材料"}})
+		srv, err := s.SheetsService(ctx)
+		require.NoError(t, err)
+		nc := cfg.notifyConfig(clk)
+		nc.HTTPClient = s.Client()
+
+		err = runMeal(ctx, &sources.GoogleSheetReader{Service: srv}, &fakePlanStore{}, nil, "run", cfg, nc, clock.Today(clk), false)
+		assert.Error(t, err)
+		assert.Empty(t, s.Discord.Messages())
+	})
+}
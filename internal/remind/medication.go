@@ -0,0 +1,105 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/medication"
+	"github.com/mami0tsu/homeops/internal/notify"
+)
+
+const medicationColor = 0xe91e63
+
+// 服薬の対応状況を記録する、AckStore を使う
+type doseAckStore interface {
+	register(ctx context.Context, date time.Time, events []event.Event) error
+	status(ctx context.Context, date time.Time, key string) (event.AckStatus, error)
+}
+
+// intraday モードの呼び出しごとに、服薬の時刻になった人に完了のボタンを付けてメンションする
+// 服薬の時刻から MEDICATION_ESCALATE_AFTER が経過しても完了のボタンが押されていなければ、MEDICATION_ESCALATION_MENTION も含めて再度メンションする
+func runMedication(ctx context.Context, acks doseAckStore, guard *RunGuard, runID string, cfg *Config, nc *notify.Config, now time.Time, dryRun bool) error {
+	if len(cfg.MedicationSchedule) == 0 {
+		return nil
+	}
+
+	var messages []*discord.WebhookMessage
+	for _, o := range cfg.MedicationSchedule.Due(now, cfg.IntradayEvery) {
+		// ボタンが押される前に、未対応として登録しておく
+		if !dryRun {
+			if err := acks.register(ctx, o.Date(), []event.Event{o.Event()}); err != nil {
+				slog.Error("failed to register dose", slog.String("name", o.Event().Name), slog.Any("error", err))
+				return err
+			}
+		}
+		messages = append(messages, createDoseMessage(o))
+	}
+
+	// 一部の確認に失敗しても他の服薬は知らせられるように、失敗した場合は続ける
+	var errs []error
+	for _, o := range cfg.MedicationSchedule.Escalations(now, cfg.IntradayEvery, cfg.MedicationEscalateAfter) {
+		status, err := acks.status(ctx, o.Date(), event.Key(o.Event()))
+		if err != nil {
+			slog.Error("failed to get dose status", slog.String("name", o.Event().Name), slog.Any("error", err))
+			errs = append(errs, err)
+			continue
+		}
+		// 登録されていない服薬は、知らせる前に設定が変わったものとして扱う
+		if status != event.AckPending {
+			continue
+		}
+		messages = append(messages, createMissedDoseMessage(o, cfg.MedicationEscalationMention, cfg.MedicationEscalateAfter))
+	}
+	if len(messages) == 0 {
+		return errors.Join(errs...)
+	}
+
+	// 投稿せずに投稿内容を確認する
+	if dryRun {
+		for _, m := range messages {
+			slog.Info("dry run", slog.String("medication", m.Content))
+		}
+		return errors.Join(errs...)
+	}
+
+	// 同じ時間帯の intraday モードの投稿と区別する
+	runID += "#medication#" + now.Truncate(cfg.IntradayEvery).Format("1504")
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	_, err := guard.Once(ctx, today, runID, func() error {
+		return notify.PostDiscordMessages(ctx, nc, messages...)
+	})
+	if err != nil {
+		slog.Error("failed to post medication reminders", slog.Any("error", err))
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// メンションで知らせるため、本文に名前を含める
+func createDoseMessage(o medication.Occurrence) *discord.WebhookMessage {
+	e := o.Event()
+	embed := discord.NewEmbed(fmt.Sprintf("%s %s", medication.Emoji, e.Name), medicationColor)
+	embed.Description = "飲んだらボタンを押してください"
+
+	return &discord.WebhookMessage{
+		Content: fmt.Sprintf("%s %sを飲む時間です", o.Person.Display(), o.Dose.Medicine),
+		Embeds:  []*discord.Embed{embed},
+		Components: []discord.Component{discord.ActionRow(
+			discord.Button(discord.SuccessButton, "飲みました", event.AckCustomID(event.AckDone, o.Date(), e)),
+		)},
+	}
+}
+
+func createMissedDoseMessage(o medication.Occurrence, mention string, after time.Duration) *discord.WebhookMessage {
+	m := createDoseMessage(o)
+	m.Content = strings.TrimSpace(fmt.Sprintf("%s %s ⚠ %sを飲んだことが %d 分経っても確認できていません", mention, o.Person.Display(), o.Dose.Medicine, int(after.Minutes())))
+
+	return m
+}
@@ -0,0 +1,99 @@
+package remind
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/medication"
+	"github.com/mami0tsu/homeops/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDoseAckStore struct {
+	statuses map[string]event.AckStatus
+}
+
+func (s *fakeDoseAckStore) register(ctx context.Context, date time.Time, events []event.Event) error {
+	for _, e := range events {
+		sk := event.AckSortKey(date, event.Key(e))
+		if _, ok := s.statuses[sk]; !ok {
+			s.statuses[sk] = event.AckPending
+		}
+	}
+	return nil
+}
+
+func (s *fakeDoseAckStore) status(ctx context.Context, date time.Time, key string) (event.AckStatus, error) {
+	return s.statuses[event.AckSortKey(date, key)], nil
+}
+
+func TestRunMedication(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	var schedule medication.Schedule
+	tr.NoError(schedule.UnmarshalText([]byte(`[
+		{"name": "alice", "mention": "<@1>", "doses": [{"time": "08:00", "medicine": "血圧の薬"}]},
+		{"name": "bob", "mention": "<@2>", "doses": [{"time": "08:00", "medicine": "胃薬"}]}
+	]`)))
+	cfg := &Config{
+		DiscordBotName:              "remind",
+		DiscordBotToken:             "token",
+		DiscordChannelID:            "123",
+		IntradayEvery:               15 * time.Minute,
+		MedicationSchedule:          schedule,
+		MedicationEscalateAfter:     30 * time.Minute,
+		MedicationEscalationMention: "<@3>",
+	}
+	srv := testsupport.NewServer(t)
+	acks := &fakeDoseAckStore{statuses: map[string]event.AckStatus{}}
+	post := func(now time.Time) []string {
+		nc := cfg.notifyConfig(clock.Fixed(now))
+		nc.HTTPClient = srv.Client()
+		before := len(srv.Discord.Messages())
+		tr.NoError(runMedication(context.Background(), acks, nil, "run", cfg, nc, now, false))
+		var contents []string
+		for _, m := range srv.Discord.Messages()[before:] {
+			contents = append(contents, m.Content)
+		}
+		return contents
+	}
+
+	// 服薬の時刻になったら完了のボタンを付けて知らせる
+	ta.Equal([]string{"<@1> 血圧の薬を飲む時間です", "<@2> 胃薬を飲む時間です"}, post(time.Date(2025, 3, 1, 8, 0, 0, 0, clock.JST())))
+	msgs := srv.Discord.Messages()
+	tr.Len(msgs[0].Components, 1)
+	ta.Equal("飲みました", msgs[0].Components[0].Components[0].Label)
+	e := event.Event{Name: "alice の血圧の薬 (08:00)"}
+	ta.Equal("ack:done:20250301:"+event.Key(e), msgs[0].Components[0].Components[0].CustomID)
+	ta.Len(acks.statuses, 2)
+
+	// 服薬の時刻から時間が経っていなければ知らせない
+	ta.Empty(post(time.Date(2025, 3, 1, 8, 15, 0, 0, clock.JST())))
+
+	// 完了のボタンが押されていない人のみ再度知らせる
+	acks.statuses[event.AckSortKey(time.Date(2025, 3, 1, 0, 0, 0, 0, clock.JST()), event.Key(e))] = event.AckDone
+	ta.Equal([]string{"<@3> <@2> ⚠ 胃薬を飲んだことが 30 分経っても確認できていません"}, post(time.Date(2025, 3, 1, 8, 30, 0, 0, clock.JST())))
+}
+
+func TestRunMedicationDryRun(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	var schedule medication.Schedule
+	tr.NoError(schedule.UnmarshalText([]byte(`[{"name": "alice", "doses": [{"time": "08:00", "medicine": "血圧の薬"}]}]`)))
+	cfg := &Config{IntradayEvery: 15 * time.Minute, MedicationSchedule: schedule, MedicationEscalateAfter: 30 * time.Minute}
+	srv := testsupport.NewServer(t)
+	now := time.Date(2025, 3, 1, 8, 0, 0, 0, clock.JST())
+	nc := cfg.notifyConfig(clock.Fixed(now))
+	nc.HTTPClient = srv.Client()
+	acks := &fakeDoseAckStore{statuses: map[string]event.AckStatus{}}
+
+	ta.NoError(runMedication(context.Background(), acks, nil, "run", cfg, nc, now, true))
+	ta.Empty(srv.Discord.Messages())
+	ta.Empty(acks.statuses)
+}
@@ -0,0 +1,80 @@
+package remind
+
+import (
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/notify"
+)
+
+// remind-cli が書き出す投稿内容、アーカイブにも同じ形式で保存する
+type Output struct {
+	Schedules []outputSchedule `json:"schedules"`
+	Upcoming  []outputSchedule `json:"upcoming,omitempty"`
+	Overdue   []outputEvent    `json:"overdue,omitempty"`
+	Escalate  bool             `json:"escalate,omitempty"`
+	Failures  []string         `json:"failures,omitempty"`
+}
+
+type outputSchedule struct {
+	Date   string        `json:"date"`
+	Events []outputEvent `json:"events"`
+}
+
+type outputEvent struct {
+	Name     string   `json:"name"`
+	Emoji    string   `json:"emoji,omitempty"`
+	Interval string   `json:"interval"`
+	Origin   string   `json:"origin,omitempty"`
+	LeadDays int      `json:"lead_days,omitempty"`
+	DaysLeft int      `json:"days_left,omitempty"`
+	Priority int      `json:"priority,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+func NewOutput(d event.Digest) Output {
+	c := Output{
+		Schedules: newOutputSchedules(d.Schedules),
+		Upcoming:  newOutputSchedules(d.Upcoming),
+		Overdue:   newOutputEvents(d.Overdue),
+		Escalate:  d.Escalate,
+		Failures:  notify.CreateFailureNotes(d.Failures),
+	}
+	if c.Schedules == nil {
+		c.Schedules = []outputSchedule{}
+	}
+
+	return c
+}
+
+func newOutputSchedules(schedules []event.Schedule) []outputSchedule {
+	var s []outputSchedule
+	for _, v := range schedules {
+		events := newOutputEvents(v.Events)
+		if events == nil {
+			events = []outputEvent{}
+		}
+		s = append(s, outputSchedule{Date: v.Date.Format("2006-01-02"), Events: events})
+	}
+
+	return s
+}
+
+func newOutputEvents(events []event.Event) []outputEvent {
+	var s []outputEvent
+	for _, e := range events {
+		c := outputEvent{
+			Name:     e.Name,
+			Emoji:    e.Emoji,
+			Interval: e.Interval.String(),
+			LeadDays: e.LeadDays,
+			DaysLeft: e.DaysLeft,
+			Priority: e.Priority,
+			Tags:     e.Tags,
+		}
+		if !e.Origin.IsZero() {
+			c.Origin = e.Origin.Format("2006-01-02")
+		}
+		s = append(s, c)
+	}
+
+	return s
+}
@@ -0,0 +1,41 @@
+package remind
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOutput(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	d := event.Digest{
+		Schedules: []event.Schedule{
+			{
+				Date: time.Date(2025, 1, 15, 0, 0, 0, 0, tz),
+				Events: []event.Event{
+					{Name: "Garbage", Emoji: "🗑️", Interval: event.Weekly, Tags: []string{"morning"}},
+				},
+			},
+			{Date: time.Date(2025, 1, 16, 0, 0, 0, 0, tz)},
+		},
+		Overdue: []event.Event{
+			{Name: "Piano", Origin: time.Date(2025, 1, 14, 0, 0, 0, 0, tz)},
+		},
+	}
+
+	b, err := json.Marshal(NewOutput(d))
+	tr.NoError(err)
+	ta.JSONEq(`{
+		"schedules": [
+			{"date": "2025-01-15", "events": [{"name": "Garbage", "emoji": "🗑️", "interval": "Weekly", "tags": ["morning"]}]},
+			{"date": "2025-01-16", "events": []}
+		],
+		"overdue": [{"name": "Piano", "interval": "Onetime", "origin": "2025-01-14"}]
+	}`, string(b))
+}
@@ -0,0 +1,101 @@
+package remind
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/digest"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/sources"
+	"github.com/mami0tsu/homeops/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// スプレッドシートからの取得、タグによる絞り込み、投稿内容の作成、Discord への投稿をまとめて確認する
+func TestPipeline(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	s := testsupport.NewServer(t)
+	s.Sheets.SetValues("spreadsheet", "remind", [][]any{
+		{"Name", "Interval", "StartDate", "EndDate", "Emoji", "Color", "URL", "Description", "Modifiers", "NotifyBefore", "Time", "Count", "Exceptions", "Done", "Tags"},
+		{"Garbage", "Daily", "2025/01/01", "2025/01/31", "", "", "", "", "", "", "", "", "", "", "evening"},
+		{"Piano", "Daily", "2025/01/01", "2025/01/31", "", "", "", "", "", "", "", "", "", "", "morning"},
+	})
+
+	ctx := context.Background()
+	clk := clock.Fixed(time.Date(2025, 1, 10, 18, 0, 0, 0, clock.JST()))
+	cfg := &Config{
+		DiscordBotName:      "remind",
+		DiscordBotToken:     "token",
+		DiscordChannelID:    "123",
+		GoogleSpreadsheetID: "spreadsheet",
+		SheetTab:            "remind",
+		SheetChunkRows:      2,
+		LookaheadDays:       1,
+		NoEventsMode:        notify.NoEventsMode("empty"),
+		Locale:              notify.Locale("en"),
+	}
+
+	srv, err := s.SheetsService(ctx)
+	tr.NoError(err)
+	src, err := newSource(&sources.GoogleSheetReader{Service: srv}, cfg, nil, []string{"evening"}, nil)
+	tr.NoError(err)
+	nc := cfg.notifyConfig(clk)
+	nc.HTTPClient = s.Client()
+	a := digest.NewApp(src, notify.NewDiscordWebhookSink(nc, notify.FormatRich))
+
+	today := clock.Today(clk)
+	d, err := digest.Build(ctx, a, today, []time.Time{today}, digestOptions(nil, cfg))
+	tr.NoError(err)
+	deliveries, err := a.Post(ctx, d)
+	tr.NoError(err)
+	ta.Equal([]digest.Delivery{{Sink: "discord"}}, deliveries)
+
+	// 行数を指定した場合は分けて読み込む
+	ta.Equal([]string{"remind!A2:Q3", "remind!A4:Q5"}, s.Sheets.Ranges())
+
+	// タグで絞り込んだイベントのみを投稿し、投稿後に Webhook を削除する
+	msgs := s.Discord.Messages()
+	tr.Len(msgs, 1)
+	tr.NotEmpty(msgs[0].Embeds)
+	ta.Contains(msgs[0].Embeds[0].Fields[0].Name, "Garbage")
+	for _, e := range msgs[0].Embeds {
+		for _, f := range e.Fields {
+			ta.NotContains(f.Name, "Piano")
+		}
+	}
+	created, deleted := s.Discord.WebhookCount()
+	ta.Equal(1, created)
+	ta.Equal(1, deleted)
+}
+
+// 日ごとの通知でのみ使うデータを読み込み、失敗した取得元をまとめて返す
+func TestLoadDigestInputs(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("正常系/指定がない場合は何も読み込まない", func(t *testing.T) {
+		in, errs := loadDigestInputs(ctx, nil, &Config{}, clock.JST())
+		assert.Equal(t, digestInputs{}, in)
+		assert.Empty(t, errs)
+	})
+
+	t.Run("異常系/有効期限を確認できない場合", func(t *testing.T) {
+		var calls int
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		in, errs := loadDigestInputs(ctx, nil, &Config{ExpiryDomains: []string{"example.com"}, RDAPURL: srv.URL}, clock.JST())
+		assert.Empty(t, in.expiries)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,135 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/ledger"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/pricewatch"
+)
+
+// hello の /price で登録した商品の価格を取得し、下がった商品を投稿する、e.g. {"mode": "pricewatch"}
+const modePriceWatch = "pricewatch"
+
+const (
+	priceDropColor    = 0x3498db
+	priceReachedColor = 0x2ecc71
+)
+
+type watchStore interface {
+	List(ctx context.Context) ([]pricewatch.Watch, error)
+	UpdatePrice(ctx context.Context, w pricewatch.Watch, price int) error
+}
+
+type priceFetcher interface {
+	Price(ctx context.Context, url string) (int, error)
+}
+
+func newWatchStore(client *dynamodb.Client, cfg *Config) (*pricewatch.Store, error) {
+	if cfg.PriceWatchTableName == "" {
+		return nil, errors.New("PRICE_WATCH_TABLE_NAME is not set")
+	}
+
+	return pricewatch.NewStore(client, cfg.PriceWatchTableName), nil
+}
+
+// 価格が変わった商品
+type priceChange struct {
+	Watch  pricewatch.Watch
+	Price  int
+	Change pricewatch.Change
+}
+
+// 登録した商品の価格を取得して、前回より下がった商品と目標の価格以下になった商品を投稿する
+// 目標の価格以下になった商品は、スマートフォンに通知されるように登録した人にメンションして個別に投稿する
+// 一部の商品の取得に失敗しても他の商品は投稿できるように、取得のエラーはログに出力して次回の呼び出しで再度取得する
+func runPriceWatch(ctx context.Context, store watchStore, fetcher priceFetcher, nc *notify.Config, dryRun bool) error {
+	watches, err := store.List(ctx)
+	if err != nil {
+		slog.Error("failed to list watches", slog.Any("error", err))
+		return err
+	}
+
+	var changes, updates []priceChange
+	for _, w := range watches {
+		price, err := fetcher.Price(ctx, w.URL)
+		if err != nil {
+			slog.Error("failed to get price", slog.String("url", w.URL), slog.Any("error", err))
+			continue
+		}
+		if price == w.LastPrice {
+			continue
+		}
+		c := priceChange{Watch: w, Price: price, Change: w.Evaluate(price)}
+		updates = append(updates, c)
+		if c.Change != pricewatch.Unchanged {
+			changes = append(changes, c)
+		}
+	}
+
+	var messages []*discord.WebhookMessage
+	var drops []*discord.Embed
+	for _, c := range changes {
+		embed := createPriceEmbed(c)
+		if c.Change != pricewatch.Reached {
+			drops = append(drops, embed)
+			continue
+		}
+		msg := &discord.WebhookMessage{Embeds: []*discord.Embed{embed}}
+		if c.Watch.AddedByID != "" {
+			msg.Content = fmt.Sprintf("<@%s>", c.Watch.AddedByID)
+		}
+		messages = append(messages, msg)
+	}
+	if len(drops) > 0 {
+		messages = append(messages, &discord.WebhookMessage{Embeds: drops})
+	}
+
+	// 投稿せずに投稿内容を確認する
+	if dryRun {
+		for _, c := range changes {
+			slog.Info("dry run", slog.String("watch", c.Watch.Label()), slog.Int("price", c.Price))
+		}
+		return nil
+	}
+
+	if err := notify.PostDiscordMessages(ctx, nc, messages...); err != nil {
+		slog.Error("failed to post price changes", slog.Any("error", err))
+		return err
+	}
+
+	// 投稿した後に記録して、投稿に失敗した場合は次回の呼び出しで再度投稿する
+	var errs []error
+	for _, c := range updates {
+		if err := store.UpdatePrice(ctx, c.Watch, c.Price); err != nil {
+			slog.Error("failed to update price", slog.String("url", c.Watch.URL), slog.Any("error", err))
+			errs = append(errs, err)
+		}
+	}
+	slog.Info("succeeded to watch prices", slog.Int("watches", len(watches)), slog.Int("changes", len(changes)))
+
+	return errors.Join(errs...)
+}
+
+func createPriceEmbed(c priceChange) *discord.Embed {
+	title := fmt.Sprintf("📉 %s が %s に下がりました", c.Watch.Label(), ledger.FormatYen(c.Price))
+	color := priceDropColor
+	if c.Change == pricewatch.Reached {
+		title = fmt.Sprintf("🎯 %s が目標の %s 以下になりました", c.Watch.Label(), ledger.FormatYen(c.Watch.Target))
+		color = priceReachedColor
+	}
+	embed := discord.NewEmbed(title, color)
+
+	description := fmt.Sprintf("現在 %s", ledger.FormatYen(c.Price))
+	if c.Watch.LastPrice != 0 {
+		description += fmt.Sprintf(" (前回 %s、%s)", ledger.FormatYen(c.Watch.LastPrice), ledger.FormatDelta(c.Price-c.Watch.LastPrice))
+	}
+	embed.Description = description + "\n" + c.Watch.URL
+
+	return embed
+}
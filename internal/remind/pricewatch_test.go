@@ -0,0 +1,101 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/pricewatch"
+	"github.com/mami0tsu/homeops/internal/testsupport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWatchStore struct {
+	watches []pricewatch.Watch
+	updated map[string]int
+}
+
+func (s *fakeWatchStore) List(ctx context.Context) ([]pricewatch.Watch, error) {
+	return s.watches, nil
+}
+
+func (s *fakeWatchStore) UpdatePrice(ctx context.Context, w pricewatch.Watch, price int) error {
+	s.updated[w.URL] = price
+	return nil
+}
+
+type fakePriceFetcher map[string]int
+
+func (f fakePriceFetcher) Price(ctx context.Context, url string) (int, error) {
+	price, ok := f[url]
+	if !ok {
+		return 0, errors.New("returned status 503")
+	}
+
+	return price, nil
+}
+
+func TestRunPriceWatch(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	srv := testsupport.NewServer(t)
+	cfg := &Config{DiscordBotName: "remind", DiscordBotToken: "token", DiscordChannelID: "123"}
+	nc := cfg.notifyConfig(clock.Fixed(time.Date(2025, 3, 1, 12, 0, 0, 0, clock.JST())))
+	nc.HTTPClient = srv.Client()
+	store := &fakeWatchStore{
+		watches: []pricewatch.Watch{
+			{URL: "https://example.com/1", Name: "ヘッドホン", Target: 10000, LastPrice: 12000, AddedByID: "42"},
+			{URL: "https://example.com/2", Name: "椅子", Target: 30000, LastPrice: 45000},
+			{URL: "https://example.com/3", Name: "本棚", Target: 5000, LastPrice: 6000},
+			{URL: "https://example.com/4", Name: "机", Target: 20000},
+			{URL: "https://example.com/5", Name: "照明", Target: 3000, LastPrice: 4000},
+		},
+		updated: map[string]int{},
+	}
+	fetcher := fakePriceFetcher{
+		"https://example.com/1": 9800,
+		"https://example.com/2": 42000,
+		"https://example.com/3": 6500,
+		"https://example.com/4": 25000,
+	}
+
+	tr.NoError(runPriceWatch(context.Background(), store, fetcher, nc, false))
+
+	// 目標の価格以下になった商品はメンションして個別に投稿し、下がった商品はまとめて投稿する
+	msgs := srv.Discord.Messages()
+	tr.Len(msgs, 2)
+	ta.Equal("<@42>", msgs[0].Content)
+	ta.Equal("🎯 ヘッドホン が目標の ¥10,000 以下になりました", msgs[0].Embeds[0].Title)
+	ta.Equal("現在 ¥9,800 (前回 ¥12,000、-¥2,200)\nhttps://example.com/1", msgs[0].Embeds[0].Description)
+	ta.Empty(msgs[1].Content)
+	tr.Len(msgs[1].Embeds, 1)
+	ta.Equal("📉 椅子 が ¥42,000 に下がりました", msgs[1].Embeds[0].Title)
+	// 上がった商品と初めて取得した商品は投稿せずに価格を記録する
+	ta.Equal(map[string]int{
+		"https://example.com/1": 9800,
+		"https://example.com/2": 42000,
+		"https://example.com/3": 6500,
+		"https://example.com/4": 25000,
+	}, store.updated)
+}
+
+func TestRunPriceWatchDryRun(t *testing.T) {
+	ta := assert.New(t)
+
+	srv := testsupport.NewServer(t)
+	cfg := &Config{DiscordBotName: "remind", DiscordBotToken: "token", DiscordChannelID: "123"}
+	nc := cfg.notifyConfig(clock.Fixed(time.Date(2025, 3, 1, 12, 0, 0, 0, clock.JST())))
+	nc.HTTPClient = srv.Client()
+	store := &fakeWatchStore{
+		watches: []pricewatch.Watch{{URL: "https://example.com/1", Target: 10000, LastPrice: 12000}},
+		updated: map[string]int{},
+	}
+
+	ta.NoError(runPriceWatch(context.Background(), store, fakePriceFetcher{"https://example.com/1": 9800}, nc, true))
+	ta.Empty(srv.Discord.Messages())
+	ta.Empty(store.updated)
+}
@@ -0,0 +1,743 @@
+// Package remind はスプレッドシートなどから取得したイベントを Discord などに通知する
+// Lambda と常駐モードは cmd/remind、ローカルでの実行は cmd/remind-cli から呼び出す
+package remind
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/budget"
+	"github.com/mami0tsu/homeops/internal/buildinfo"
+	"github.com/mami0tsu/homeops/internal/chore"
+	"github.com/mami0tsu/homeops/internal/civic"
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/config"
+	"github.com/mami0tsu/homeops/internal/digest"
+	"github.com/mami0tsu/homeops/internal/document"
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/errorreport"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/expiry"
+	"github.com/mami0tsu/homeops/internal/flags"
+	"github.com/mami0tsu/homeops/internal/gomi"
+	"github.com/mami0tsu/homeops/internal/guest"
+	"github.com/mami0tsu/homeops/internal/habit"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/indoor"
+	"github.com/mami0tsu/homeops/internal/library"
+	"github.com/mami0tsu/homeops/internal/logging"
+	"github.com/mami0tsu/homeops/internal/medication"
+	"github.com/mami0tsu/homeops/internal/metrics"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/pet"
+	"github.com/mami0tsu/homeops/internal/plant"
+	"github.com/mami0tsu/homeops/internal/pricewatch"
+	"github.com/mami0tsu/homeops/internal/remo"
+	"github.com/mami0tsu/homeops/internal/school"
+	"github.com/mami0tsu/homeops/internal/shopping"
+	"github.com/mami0tsu/homeops/internal/sources"
+	"github.com/mami0tsu/homeops/internal/stock"
+	"github.com/mami0tsu/homeops/internal/tracing"
+	"github.com/mami0tsu/homeops/internal/tracking"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type Config struct {
+	DiscordBotName      string `env:"DISCORD_BOT_NAME,required" ssm:"discord"`
+	DiscordBotToken     string `env:"DISCORD_BOT_TOKEN,required" ssm:"discord"`
+	DiscordChannelID    string `env:"DISCORD_CHANNEL_ID,required" ssm:"discord"`
+	DiscordUseThread    bool   `env:"DISCORD_USE_THREAD" envDefault:"false" ssm:"discord"` // 日付ごとのスレッドに投稿する
+	DiscordWebhookCache bool   `env:"DISCORD_WEBHOOK_CACHE" envDefault:"false"`            // 作成した Webhook を SSM に保存して再利用する
+
+	GoogleCredentials   string `env:"GOOGLE_CREDENTIALS,required" ssm:"google"`
+	GoogleSpreadsheetID string `env:"GOOGLE_SPREADSHEET_ID,required" ssm:"google"`
+
+	GoogleChatWebhookURL string `env:"GOOGLE_CHAT_WEBHOOK_URL" ssm:"google"`
+
+	SheetTab       string `env:"SHEET_TAB" envDefault:"remind"`   // e.g. 終了日が過ぎていない行のみを抽出した active シート
+	SheetChunkRows int    `env:"SHEET_CHUNK_ROWS" envDefault:"0"` // 1 回に読み込む行数、0 の場合はシート全体を 1 度に読み込む
+
+	Sinks         []string            `env:"SINKS" envDefault:"discord"` // e.g. discord,discord_scheduled_event,google_chat
+	SinkFormats   map[string]string   `env:"SINK_FORMATS"`               // e.g. discord:markdown,google_chat:text
+	NoEventsMode  notify.NoEventsMode `env:"NO_EVENTS_MODE" envDefault:"empty"`
+	Locale        notify.Locale       `env:"LOCALE" envDefault:"en"` // en もしくは ja
+	HolidaysURL   string              `env:"HOLIDAYS_URL" envDefault:"https://holidays-jp.github.io/api/v1/date.json"`
+	LookaheadDays int                 `env:"LOOKAHEAD_DAYS" envDefault:"2"` // 実行日から N 日分のイベントを投稿する
+	UpcomingDays  int                 `env:"UPCOMING_DAYS" envDefault:"0"`  // 今後 N 日分のイベントを取得する
+	ICSAttachment bool                `env:"ICS_ATTACHMENT" envDefault:"false"`
+	ICSDays       int                 `env:"ICS_DAYS" envDefault:"0"`                    // Deprecated: UPCOMING_DAYS と ICS_ATTACHMENT を使う
+	IntradayEvery time.Duration       `env:"INTRADAY_EVERY" envDefault:"15m"`            // intraday モードで呼び出される間隔
+	SortOrder     []string            `env:"SORT_ORDER" envDefault:"priority,time,name"` // 空の場合はシートの順に表示する
+	Collation     string              `env:"COLLATION"`                                  // e.g. ja、未指定の場合はバイト順に並べる
+
+	Profiles       ScheduleProfiles `env:"SCHEDULE_PROFILES"` // ペイロードの profile で指定するスケジュールごとの挙動、JSON で指定する
+	ServeSchedules ServeSchedules   `env:"SERVE_SCHEDULES"`   // serve モードで常駐して実行する場合のスケジュール、JSON で指定する
+
+	GomiSchedule string `env:"GOMI_SCHEDULE"`  // ゴミの収集日の規則、JSON で指定する
+	GomiSheetTab string `env:"GOMI_SHEET_TAB"` // 指定した場合は GOMI_SCHEDULE の代わりにスプレッドシートのシートから読み込む
+
+	ExpenseSheetTab string `env:"EXPENSE_SHEET_TAB" envDefault:"expense"` // expense モードで集計する支出を記録したシート
+
+	ShoppingTableName      string `env:"SHOPPING_TABLE_NAME"`      // hello の /buy で記録した買い物リストのテーブル
+	ShoppingRemindWeekdays string `env:"SHOPPING_REMIND_WEEKDAYS"` // 未購入の品目を通知する曜日、e.g. sat
+
+	ExpiryDomains    []string `env:"EXPIRY_DOMAINS"`                         // 登録の有効期限を確認するドメイン、e.g. example.com
+	ExpiryCertHosts  []string `env:"EXPIRY_CERT_HOSTS"`                      // TLS 証明書の有効期限を確認するホスト、e.g. example.com,example.com:8443
+	ExpiryThresholds []int    `env:"EXPIRY_THRESHOLDS" envDefault:"30,14,7"` // 期限まで N 日になったら通知する、最も小さい値を下回ってからは毎日通知する
+	RDAPURL          string   `env:"RDAP_URL" envDefault:"https://rdap.org"`
+
+	MedicationSchedule          medication.Schedule `env:"MEDICATION_SCHEDULE"`                        // intraday モードで服薬を知らせる、JSON で指定する
+	MedicationEscalateAfter     time.Duration       `env:"MEDICATION_ESCALATE_AFTER" envDefault:"30m"` // 服薬の時刻から経過しても完了のボタンが押されていなければ再度知らせる
+	MedicationEscalationMention string              `env:"MEDICATION_ESCALATION_MENTION"`              // 再度知らせる場合に本人に加えてメンションする、e.g. <@123456789>
+
+	// 室内のセンサーの値を当日のイベントとして投稿する
+	RemoToken       string `env:"REMO_TOKEN" ssm:"remo"`
+	RemoURL         string `env:"REMO_URL" envDefault:"https://api.nature.global"`
+	SwitchBotToken  string `env:"SWITCHBOT_TOKEN" ssm:"switchbot"`
+	SwitchBotSecret string `env:"SWITCHBOT_SECRET" ssm:"switchbot"`
+	SwitchBotURL    string `env:"SWITCHBOT_URL" envDefault:"https://api.switch-bot.com"`
+
+	ElectricityTableName string  `env:"ELECTRICITY_TABLE_NAME"`                 // electricity モードで Nature Remo E の積算電力量を記録するテーブル、ACK_TABLE_NAME と同じテーブルでもよい
+	ElectricityUnitPrice float64 `env:"ELECTRICITY_UNIT_PRICE" envDefault:"31"` // 料金の目安に使う 1 kWh あたりの料金 (円)
+
+	TrackingTableName   string `env:"TRACKING_TABLE_NAME"`    // tracking モードで配送状況を取得する、hello の /track で記録した荷物のテーブル
+	PriceWatchTableName string `env:"PRICE_WATCH_TABLE_NAME"` // pricewatch モードで価格を取得する、hello の /price で登録した商品のテーブル
+
+	ChoreRotations chore.Rotations `env:"CHORE_ROTATIONS"`  // 家事の担当者を持ち回りで割り当てる、JSON で指定する
+	ChoreTableName string          `env:"CHORE_TABLE_NAME"` // 担当者の割り当てを記録するテーブル、ACK_TABLE_NAME と同じテーブルでもよい
+
+	GuestTemplates guest.Templates `env:"GUEST_TEMPLATES"` // 来客のタグを付けたイベントの N 日前に通知する準備の項目、JSON で指定する
+
+	Pets pet.Pets `env:"PETS"` // ペットごとの世話、JSON で指定する、members を指定した世話は CHORE_TABLE_NAME で担当者を持ち回りで割り当てる
+
+	StockSheetTab   string `env:"STOCK_SHEET_TAB"`                  // hello の /stock で食品の在庫を記録したシート、指定した場合は期限が近い食品を投稿する
+	StockExpiryDays int    `env:"STOCK_EXPIRY_DAYS" envDefault:"3"` // 期限まで N 日以内の食品を投稿する
+
+	LibraryTableName string `env:"LIBRARY_TABLE_NAME"`              // hello の /library で記録した借りている本のテーブル、指定した場合は返却期限が近い本を投稿する
+	LibraryDueDays   int    `env:"LIBRARY_DUE_DAYS" envDefault:"2"` // 返却期限まで N 日以内の本を投稿する
+
+	DocumentSheetTab     string            `env:"DOCUMENT_SHEET_TAB"`                        // パスポートや在留カードなどの有効期限を記録したシート、指定した場合は期限が近い書類を投稿する
+	DocumentRemindMonths []int             `env:"DOCUMENT_REMIND_MONTHS" envDefault:"6,3,1"` // 期限の N か月前に通知する、最も小さい値を下回ってからは毎日通知する
+	DocumentMentions     map[string]string `env:"DOCUMENT_MENTIONS"`                         // 名義人ごとにメンションする、e.g. はな:<@123456789>,そら:<@&987654321>
+
+	CivicDeadlines    []string `env:"CIVIC_DEADLINES"`                    // 世帯に当てはまる税金や公共料金の期限、e.g. kakutei,juminzei,nhk,jidosha
+	CivicNotifyBefore int      `env:"CIVIC_NOTIFY_BEFORE" envDefault:"7"` // 納付などの期限の N 日前にも通知する、0 の場合は当日のみ
+
+	Plants            plant.Plants  `env:"PLANTS"`                                // 植物ごとの水やりの間隔、JSON で指定する
+	PlantTableName    string        `env:"PLANT_TABLE_NAME"`                      // monitor が受け付けた土壌水分センサーの値のテーブル、指定した場合は土が湿っている植物の水やりを知らせない
+	PlantSensorMaxAge time.Duration `env:"PLANT_SENSOR_MAX_AGE" envDefault:"24h"` // 当日の 0 時から遡ってこの期間内に受け付けた値のみを使う
+
+	// meal モードで 1 週間の夕食の献立を提案する、hello でも同じ値を指定する
+	MealTableName      string `env:"MEAL_TABLE_NAME"`       // 提案した献立を記録するテーブル、ACK_TABLE_NAME と同じテーブルでもよい
+	MealRecipeSheetTab string `env:"MEAL_RECIPE_SHEET_TAB"` // レシピを記録したシート
+
+	SchoolCalendars school.Calendars `env:"SCHOOL_CALENDARS"` // 子どもごとの学校や保育園の年間行事予定、JSON で指定する、school タグと子どもの名前のタグを付ける
+
+	HabitSummaryWeekdays string `env:"HABIT_SUMMARY_WEEKDAYS" envDefault:"sun"` // hello の /habit で記録した習慣の 1 週間の記録を投稿する曜日、ACK_TABLE_NAME から読み込む
+
+	AckTableName    string `env:"ACK_TABLE_NAME"`                   // 指定した場合はイベントの対応状況を記録する
+	AckLookbackDays int    `env:"ACK_LOOKBACK_DAYS" envDefault:"7"` // 過去 N 日分の未対応のイベントを再通知する
+
+	OverdueMention      string `env:"OVERDUE_MENTION"`                      // e.g. <@123456789>, <@&987654321>
+	OverdueMentionAfter int    `env:"OVERDUE_MENTION_AFTER" envDefault:"2"` // 発生日から N 日以上未対応のイベントがあればメンションする
+
+	SentryDSN string `env:"SENTRY_DSN" ssm:"sentry"` // 指定した場合はエラーを Sentry に通知する
+
+	ArchiveBucket string `env:"ARCHIVE_BUCKET"` // 指定した場合は投稿内容を S3 に保存する
+
+	IdempotencyTableName string `env:"IDEMPOTENCY_TABLE_NAME"` // 指定した場合は再試行された呼び出しで重複して投稿しない、ACK_TABLE_NAME と同じテーブルでもよい
+
+	DryRun bool `env:"DRY_RUN"` // true の場合はペイロードの指定によらず投稿しない、既定値は APP_ENV による
+}
+
+// 取得元が遅い場合でも投稿できるように、残りの実行時間を取得と投稿に配分する
+// 失敗の通知と対応状況の記録のために reserve の分は残しておく
+const budgetReserve = 3 * time.Second
+
+var budgetPhases = []budget.Phase{
+	{Name: "fetch", Weight: 3},
+	{Name: "notify", Weight: 2},
+}
+
+// 一定間隔で呼び出され、時刻が指定されたイベントをその時刻に合わせて投稿するモード
+const modeIntraday = "intraday"
+
+// Lambda の呼び出し時に渡される値
+type Payload struct {
+	Mode   string   `json:"mode"`    // e.g. "intraday", "selftest", "backfill", "expense", "tracking", "electricity"、未指定の場合は日ごとの通知
+	Date   string   `json:"date"`    // 実行日として扱う日付、e.g. "2025-03-01"
+	Dates  []string `json:"dates"`   // 投稿対象の日付、e.g. ["2025-03-01", "2025-03-03"]
+	DryRun bool     `json:"dry_run"` // true の場合は投稿せずにログへ出力する
+	Tags   []string `json:"tags"`    // 指定したタグのいずれかを持つイベントのみを投稿する、e.g. ["evening"]
+	RunID  string   `json:"run_id"`  // 同じ内容で再度投稿する場合に指定する、未指定の場合は呼び出しの内容から作成する
+
+	// スケジュールごとの挙動、e.g. {"profile": "evening", "channel": "123456789", "tags": ["evening"]}
+	Profile       string   `json:"profile"`        // SCHEDULE_PROFILES で定義したプロファイルの名前
+	Channel       string   `json:"channel"`        // 投稿先の Discord のチャンネル ID、未指定の場合は DISCORD_CHANNEL_ID
+	LookaheadDays int      `json:"lookahead_days"` // 未指定の場合は LOOKAHEAD_DAYS
+	ExcludeTags   []string `json:"exclude_tags"`   // 指定したタグのいずれかを持つイベントを除いて投稿する、e.g. ["school"]
+
+	// backfill モードで後から投稿する期間、e.g. {"mode": "backfill", "from": "2025-03-01", "to": "2025-03-03"}
+	From string `json:"from"`
+	To   string `json:"to"` // 未指定の場合は実行日の前日まで
+}
+
+func loadConfig(ctx context.Context) (*Config, error) {
+	var cfg Config
+	if err := config.Load(ctx, "remind", &cfg, "flags"); err != nil {
+		slog.Error("failed to load config", slog.Any("error", err))
+		return nil, err
+	}
+	cfg.applyDeprecated()
+
+	return &cfg, nil
+}
+
+// 環境ごとに有効にした機能フラグを、以降の処理から参照できるようにする
+func withFlags(ctx context.Context) context.Context {
+	f := flags.FromEnv()
+	if names := f.Names(); len(names) > 0 {
+		slog.Info("enabled flags", slog.Any("flags", names))
+	}
+
+	return flags.WithSet(ctx, f)
+}
+
+// コールドスタート時に作成し、呼び出しごとにリクエスト ID を付けて使う
+var logger = slog.Default()
+
+func handleRequest(ctx context.Context, p Payload) error {
+	slog.SetDefault(logging.WithLambdaContext(ctx, logger))
+	defer tracing.Flush(ctx)
+
+	// 処理全体の所要時間と、処理中に記録したメトリクスを出力する
+	m := metrics.NewFromEnv("remind")
+	ctx = metrics.WithRecorder(ctx, m)
+	start := time.Now()
+	defer func() {
+		m.Add("Duration", float64(time.Since(start).Milliseconds()), metrics.Milliseconds)
+		if err := m.Flush(); err != nil {
+			slog.Warn("failed to write metrics", slog.Any("error", err))
+		}
+	}()
+
+	// 同じ呼び出しのエラーをまとめられるように、ペイロードのハッシュを付けて通知する
+	b, _ := json.Marshal(p)
+	tags := map[string]string{"mode": p.Mode, "profile": p.Profile, "payload_hash": errorreport.PayloadHash(b)}
+	defer func() {
+		if v := recover(); v != nil {
+			errorreport.FromEnv().CapturePanic(ctx, v, tags)
+			panic(v)
+		}
+	}()
+
+	ctx, span := tracing.Start(ctx, "remind", attribute.String("mode", p.Mode), attribute.String("profile", p.Profile), attribute.Bool("dry_run", p.DryRun))
+	err := run(ctx, clock.System(), p)
+	tracing.End(span, err)
+	if err != nil {
+		// 認証情報の期限切れなどに備えて、次の呼び出しでクライアントを作り直す
+		cachedClients.Invalidate()
+		// 設定の読み込みに失敗した場合でも通知できるように、通知先は環境変数から直接読み込む
+		if !errors.Is(err, errSelftestFailed) {
+			notifyFailure(ctx, os.Getenv("FAILURE_WEBHOOK_URL"), err)
+		}
+		tags["component"] = event.FailedComponent(err)
+		errorreport.FromEnv().Capture(ctx, err, tags)
+		return err
+	}
+
+	return nil
+}
+
+func run(ctx context.Context, clk clock.Clock, p Payload) error {
+	// 連携先のクライアントを取得する、作成済みであれば再利用する
+	c, err := cachedClients.Get(ctx)
+	if err != nil {
+		slog.Error("failed to init clients", slog.Any("error", err))
+		return err
+	}
+
+	// 設定を読み込む
+	cfg, err := refreshConfig(ctx)
+	if err != nil {
+		return err
+	}
+	ctx = withFlags(ctx)
+
+	// スケジュールごとの挙動を反映する
+	p, err = resolvePayload(p, cfg.Profiles)
+	if err != nil {
+		slog.Error("failed to resolve payload", slog.Any("error", err))
+		return err
+	}
+	applyPayload(cfg, p)
+
+	// 連携先の認証情報などを確認する
+	if p.Mode == modeSelftest {
+		return runSelftest(ctx, c.dynamodb, cfg, clk)
+	}
+
+	// 登録した荷物の配送状況を投稿する
+	if p.Mode == modeTracking {
+		store, err := newPackageStore(c.dynamodb, cfg)
+		if err != nil {
+			slog.Error("failed to init package store", slog.Any("error", err))
+			return err
+		}
+		return runTracking(ctx, store, tracking.NewTracker(httpclient.Default), cfg.notifyConfig(clk), p.DryRun || cfg.DryRun)
+	}
+
+	// 登録した商品の価格が下がったことを投稿する
+	if p.Mode == modePriceWatch {
+		store, err := newWatchStore(c.dynamodb, cfg)
+		if err != nil {
+			slog.Error("failed to init price watch store", slog.Any("error", err))
+			return err
+		}
+		return runPriceWatch(ctx, store, pricewatch.NewFetcher(httpclient.Default), cfg.notifyConfig(clk), p.DryRun || cfg.DryRun)
+	}
+
+	// 前日の電気の使用量を投稿する
+	if p.Mode == modeElectricity {
+		store, err := newReadingStore(c.dynamodb, cfg)
+		if err != nil {
+			slog.Error("failed to init reading store", slog.Any("error", err))
+			return err
+		}
+		meter := remo.NewClient(httpclient.Default, cfg.RemoURL, cfg.RemoToken)
+		return runElectricity(ctx, clk, store, meter, cfg.ElectricityUnitPrice, cfg.notifyConfig(clk), p.DryRun || cfg.DryRun)
+	}
+
+	// 対象とする日付情報を作成する
+	today, err := ResolveToday(clk, p.Date)
+	if err != nil {
+		slog.Error("failed to parse date", slog.Any("error", err))
+		return err
+	}
+	dates, err := targetDates(today, cfg.LookaheadDays, p.Dates)
+	if err != nil {
+		slog.Error("failed to parse target dates", slog.Any("error", err))
+		return err
+	}
+
+	// イベント情報の取得元を作成する
+	// 日ごとの通知でのみ使う有効期限などは、日ごとの通知の取得の際に読み込む
+	holidays, holidaysErr := loadHolidays(ctx, cfg)
+	calendar, gomiErr := loadGomi(ctx, c.sheets, cfg)
+
+	// イベント情報の投稿先を作成する
+	sinks, err := newSinks(cfg, clk)
+	if err != nil {
+		slog.Error("failed to init sinks", slog.Any("error", err))
+		return err
+	}
+	// 支出の集計と献立の提案はイベント情報を使わないため、取得元はそれ以外のモードでのみ作成する
+	newApp := func(in digestInputs) (*digest.App, error) {
+		extras, err := newExtraSources(c.dynamodb, cfg, holidays, calendar, in, today)
+		if err != nil {
+			return nil, err
+		}
+		src, err := newSource(c.sheets, cfg, holidays, p.Tags, p.ExcludeTags, extras...)
+		if err != nil {
+			return nil, err
+		}
+		return digest.NewApp(src, sinks...), nil
+	}
+
+	// 再試行された呼び出しで重複して投稿しないように、投稿状況を記録する
+	guard := newRunGuard(c.dynamodb, cfg)
+	runID := createRunID(p)
+
+	dryRun := p.DryRun || cfg.DryRun
+
+	// 前月の支出を集計して投稿する
+	if p.Mode == modeExpense {
+		return runExpenseSummary(ctx, c.sheets, guard, runID, cfg, cfg.notifyConfig(clk), today, dryRun)
+	}
+
+	// 翌日からの 1 週間の献立を提案する
+	if p.Mode == modeMeal {
+		store, err := newPlanStore(c.dynamodb, cfg)
+		if err != nil {
+			slog.Error("failed to init meal plan store", slog.Any("error", err))
+			return err
+		}
+		return runMeal(ctx, c.sheets, store, guard, runID, cfg, cfg.notifyConfig(clk), today, dryRun)
+	}
+
+	// 投稿できなかった日の分を後から投稿する
+	if p.Mode == modeBackfill {
+		days, err := backfillDates(today, p.From, p.To)
+		if err != nil {
+			slog.Error("failed to parse backfill range", slog.Any("error", err))
+			return err
+		}
+		a, err := newApp(digestInputs{})
+		if err != nil {
+			slog.Error("failed to init source", slog.Any("error", err))
+			return err
+		}
+		// その日の通知が投稿済みであれば投稿しないように、日ごとの通知と同じ実行 ID を使う
+		daily := p
+		daily.Mode = ""
+		return runBackfill(ctx, a, guard, createRunID(daily), cfg, days, dryRun)
+	}
+
+	// 時刻が指定されたイベントを投稿する
+	if p.Mode == modeIntraday {
+		a, err := newApp(digestInputs{})
+		if err != nil {
+			slog.Error("failed to init source", slog.Any("error", err))
+			return err
+		}
+		acks := newAckStore(c.dynamodb, cfg)
+		// 服薬の確認は、イベントの投稿に失敗しても行う
+		medicationErr := runMedication(ctx, acks, guard, runID, cfg, cfg.notifyConfig(clk), clk.Now(), dryRun)
+		return errors.Join(medicationErr, runIntraday(ctx, a, guard, runID, cfg, clk.Now(), dryRun))
+	}
+
+	// イベント情報を取得する
+	acks := newAckStore(c.dynamodb, cfg)
+	b := budget.New(ctx, budgetReserve, budgetPhases...)
+	fetchCtx, cancel := b.Start(ctx, "fetch")
+	in, loadErrs := loadDigestInputs(fetchCtx, c.sheets, cfg, today.Location())
+	a, err := newApp(in)
+	if err != nil {
+		cancel()
+		slog.Error("failed to init source", slog.Any("error", err))
+		return err
+	}
+	d, err := digest.Build(fetchCtx, a, today, dates, digestOptions(acks, cfg))
+	cancel()
+	if err != nil {
+		slog.Error("failed to get any events", slog.Any("error", err))
+		return err
+	}
+	if holidaysErr != nil {
+		d.Failures = append(d.Failures, holidaysErr)
+	}
+	if gomiErr != nil {
+		d.Failures = append(d.Failures, gomiErr)
+	}
+	d.Failures = append(d.Failures, loadErrs...)
+
+	// 投稿せずに投稿内容を確認する
+	// 担当者の割り当てを進めないように、家事の担当者は割り当てない
+	if dryRun {
+		slog.Info("dry run", slog.String("digest", notify.RenderDigest(notify.FormatText, d, cfg.NoEventsMode, cfg.Locale)))
+		return nil
+	}
+
+	// 家事の担当者を割り当てる
+	if chores := newChoreStore(c.dynamodb, cfg); chores != nil {
+		assignChores(ctx, cfg.choreRotations(), &d, chores.assign)
+	}
+
+	// イベント情報を投稿する
+	// 投稿済みの場合も、前回の呼び出しで記録できなかった場合に備えて対応状況は記録する
+	notifyCtx, cancel := b.Start(ctx, "notify")
+	deliveries, err := a.PostOnce(notifyCtx, guard, today, runID, d)
+	cancel()
+	if len(deliveries) > 0 {
+		archive(ctx, cfg, newArchiveRecord(cfg, clk.Now(), modeDaily, d, deliveries))
+	}
+	if err != nil {
+		slog.Error("failed to post events", slog.Any("error", err))
+		return err
+	}
+
+	// 当日のイベントを未対応として記録する
+	if err := registerAcks(ctx, acks, today, d); err != nil {
+		slog.Error("failed to register events", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+// 実行日を返す、date が指定されていればその日付を実行日として扱う
+func ResolveToday(clk clock.Clock, date string) (time.Time, error) {
+	if date != "" {
+		return time.ParseInLocation("2006-01-02", date, clk.Location())
+	}
+
+	return clock.Today(clk), nil
+}
+
+// 祝日を取得できなくても、祝日による調整をせずに処理を続ける
+// 取得に失敗した場合は投稿内容に注記するためにエラーを返す
+func loadHolidays(ctx context.Context, cfg *Config) (event.Holidays, error) {
+	holidays, err := sources.FetchHolidays(ctx, httpclient.Default, cfg.HolidaysURL)
+	failed := 0
+	if err != nil {
+		slog.Warn("failed to get holidays", slog.Any("error", err))
+		failed = 1
+	}
+	metrics.FromContext(ctx).Add("SourceErrors", float64(failed), metrics.Count, "Source", sources.HolidaysSourceName)
+
+	return holidays, err
+}
+
+// スプレッドシートからイベント情報を取得し、タグによる絞り込みと並べ替えを行う取得元を作成する
+// extras を指定した場合は、スプレッドシートのイベントとあわせて返す
+func newSource(r sources.SheetDataReader, cfg *Config, holidays event.Holidays, tags, excludeTags []string, extras ...sources.Source) (sources.Source, error) {
+	var src sources.Source = sources.NewSheetSource(r, cfg.GoogleSpreadsheetID, holidays, sources.SheetOptions{Tab: cfg.SheetTab, ChunkRows: cfg.SheetChunkRows})
+	// 来客のイベントを準備の項目に展開する
+	if len(cfg.GuestTemplates) > 0 {
+		src = guest.NewSource(src, cfg.GuestTemplates)
+	}
+	if len(extras) > 0 {
+		src = sources.NewMultiSource(append([]sources.Source{src}, extras...)...)
+	}
+	if len(tags) > 0 {
+		src = sources.NewTagFilterSource(src, tags)
+	}
+	if len(excludeTags) > 0 {
+		src = sources.NewTagExcludeSource(src, excludeTags)
+	}
+	if len(cfg.SortOrder) > 0 {
+		var err error
+		if src, err = sources.NewSortedSource(src, cfg.SortOrder, cfg.Collation); err != nil {
+			return nil, err
+		}
+	}
+
+	return src, nil
+}
+
+// 日ごとの通知でのみ使う、取得元の作成前に読み込むデータ
+type digestInputs struct {
+	expiries      []expiry.Result
+	readings      []indoor.Reading
+	stocks        []stock.Item
+	schoolEntries []school.Entry
+	docs          []document.Document
+}
+
+// 有効期限の確認や在庫の読み込みなどを行う、失敗した場合は読み込めたものを返し、失敗した取得元をエラーとして返す
+// ドメインや TLS 証明書の確認で外部に接続するため、日ごとの通知の場合のみ呼び出す
+func loadDigestInputs(ctx context.Context, r sources.SheetDataReader, cfg *Config, loc *time.Location) (digestInputs, []error) {
+	var in digestInputs
+	var errs []error
+	collect := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	var err error
+	in.expiries, err = checkExpiry(ctx, cfg)
+	collect(err)
+	in.readings, err = loadIndoorReadings(ctx, cfg)
+	collect(err)
+	in.stocks, err = loadStock(ctx, r, cfg, loc)
+	collect(err)
+	in.schoolEntries, err = loadSchool(ctx, r, cfg, loc)
+	collect(err)
+	in.docs, err = loadDocuments(ctx, r, cfg, loc)
+	collect(err)
+
+	return in, errs
+}
+
+// スプレッドシート以外の取得元を作成する
+// ゴミの収集日の規則を指定した場合は翌日に収集するゴミを、買い物リストの通知を指定した場合は未購入の品目を、
+// 借りている本を記録している場合は返却期限が近い本を、税金や公共料金の期限を指定した場合は期限を、
+// 有効期限を確認した場合は期限が近いドメインと TLS 証明書を返す
+func newExtraSources(client *dynamodb.Client, cfg *Config, holidays event.Holidays, calendar *gomi.Calendar, in digestInputs, today time.Time) ([]sources.Source, error) {
+	var extras []sources.Source
+	if calendar != nil {
+		extras = append(extras, gomi.NewSource(calendar, holidays))
+	}
+	if len(in.expiries) > 0 {
+		extras = append(extras, expiry.NewSource(in.expiries, cfg.ExpiryThresholds))
+	}
+	if len(in.readings) > 0 {
+		extras = append(extras, indoor.NewSource(in.readings, today))
+	}
+	if len(in.stocks) > 0 {
+		extras = append(extras, stock.NewSource(in.stocks, cfg.StockExpiryDays, today))
+	}
+	if len(in.schoolEntries) > 0 {
+		extras = append(extras, school.NewSource(in.schoolEntries))
+	}
+	if len(cfg.CivicDeadlines) > 0 {
+		extras = append(extras, civic.NewSource(cfg.CivicDeadlines, cfg.CivicNotifyBefore, holidays))
+	}
+	if len(in.docs) > 0 {
+		extras = append(extras, document.NewSource(in.docs, cfg.DocumentRemindMonths, cfg.DocumentMentions))
+	}
+	if cfg.ShoppingTableName != "" && cfg.ShoppingRemindWeekdays != "" {
+		weekdays, err := event.ParseWeekdays(cfg.ShoppingRemindWeekdays)
+		if err != nil {
+			return nil, err
+		}
+		extras = append(extras, shopping.NewSource(shopping.NewStore(client, cfg.ShoppingTableName), weekdays))
+	}
+	if cfg.LibraryTableName != "" {
+		extras = append(extras, library.NewSource(library.NewStore(client, cfg.LibraryTableName), cfg.LibraryDueDays, today))
+	}
+	if len(cfg.Pets) > 0 {
+		extras = append(extras, pet.NewSource(cfg.Pets))
+	}
+	if len(cfg.Plants) > 0 {
+		var r plant.Reader
+		if cfg.PlantTableName != "" {
+			r = plant.NewStore(client, cfg.PlantTableName)
+		}
+		extras = append(extras, plant.NewSource(cfg.Plants, r, today.Add(-cfg.PlantSensorMaxAge), today))
+	}
+	if cfg.AckTableName != "" && cfg.HabitSummaryWeekdays != "" {
+		weekdays, err := event.ParseWeekdays(cfg.HabitSummaryWeekdays)
+		if err != nil {
+			return nil, err
+		}
+		extras = append(extras, habit.NewSource(habit.NewStore(client, cfg.AckTableName), weekdays))
+	}
+
+	return extras, nil
+}
+
+// 対応状況を記録しない場合は nil を返す
+func newAckStore(client *dynamodb.Client, cfg *Config) *AckStore {
+	if cfg.AckTableName == "" {
+		return nil
+	}
+
+	return NewAckStore(client, cfg.AckTableName)
+}
+
+// 対応状況を記録する場合は、未対応のイベントを投稿内容に加える
+func digestOptions(acks *AckStore, cfg *Config) digest.Options {
+	opts := digest.Options{UpcomingDays: cfg.UpcomingDays}
+	if acks != nil {
+		opts.Overdue = overdueSource{acks: acks, lookback: cfg.AckLookbackDays, after: cfg.OverdueMentionAfter}
+	}
+
+	return opts
+}
+
+func registerAcks(ctx context.Context, acks *AckStore, today time.Time, d event.Digest) error {
+	if acks == nil {
+		return nil
+	}
+	for _, s := range d.Schedules {
+		if !s.Date.Equal(today) {
+			continue
+		}
+		if err := acks.register(ctx, today, s.Events); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runIntraday(ctx context.Context, a *digest.App, guard *RunGuard, runID string, cfg *Config, now time.Time, dryRun bool) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	schedules, errs := a.FetchSchedules(ctx, []time.Time{today})
+	if len(schedules) == 0 {
+		err := errors.Join(errs...)
+		slog.Error("failed to get any events", slog.Any("error", err))
+		return err
+	}
+
+	d := event.CreateIntradayDigest(schedules, now, cfg.IntradayEvery)
+	if len(d.Schedules) == 0 {
+		slog.Info("no events in this slot")
+		return nil
+	}
+	if dryRun {
+		slog.Info("dry run", slog.String("digest", notify.RenderSchedules(notify.FormatText, d.Schedules, cfg.NoEventsMode, cfg.Locale)))
+		return nil
+	}
+
+	// 同じ日の呼び出しを区別するため、投稿する時間帯を実行 ID に含める
+	runID += "#" + now.Truncate(cfg.IntradayEvery).Format("1504")
+	deliveries, err := a.PostOnce(ctx, guard, today, runID, d)
+	if len(deliveries) > 0 {
+		archive(ctx, cfg, newArchiveRecord(cfg, now, modeIntraday, d, deliveries))
+	}
+	if err != nil {
+		slog.Error("failed to post events", slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+// 呼び出し時に日付が指定されていればそれを使い、なければ実行日から days 日分の日付を返す
+func targetDates(today time.Time, days int, specified []string) ([]time.Time, error) {
+	var dates []time.Time
+	if len(specified) > 0 {
+		for _, s := range specified {
+			d, err := time.ParseInLocation("2006-01-02", s, today.Location())
+			if err != nil {
+				return nil, fmt.Errorf("invalid date: %s", s)
+			}
+			dates = append(dates, d)
+		}
+		return dates, nil
+	}
+
+	for i := 0; i < days; i++ {
+		dates = append(dates, today.AddDate(0, 0, i))
+	}
+
+	return dates, nil
+}
+
+func newSinks(cfg *Config, clk clock.Clock) ([]notify.Sink, error) {
+	var sinks []notify.Sink
+	for _, name := range cfg.Sinks {
+		name = strings.ToLower(strings.TrimSpace(name))
+		format, err := notify.ParseOutputFormat(cfg.SinkFormats[name])
+		if err != nil {
+			return nil, err
+		}
+
+		switch name {
+		case "discord":
+			sinks = append(sinks, notify.NewDiscordWebhookSink(cfg.notifyConfig(clk), format))
+		case "discord_scheduled_event":
+			sinks = append(sinks, notify.NewDiscordScheduledEventSink(cfg.notifyConfig(clk)))
+		case "google_chat":
+			if cfg.GoogleChatWebhookURL == "" {
+				return nil, fmt.Errorf("GOOGLE_CHAT_WEBHOOK_URL is required for google_chat sink")
+			}
+			sinks = append(sinks, notify.NewGoogleChatSink(cfg.notifyConfig(clk), format))
+		default:
+			return nil, fmt.Errorf("invalid sink: %s", name)
+		}
+	}
+
+	return sinks, nil
+}
+
+// プロファイルを適用し、ロガーとトレースを初期化する
+// Lambda、常駐モード、ローカルでの実行のいずれも最初に呼び出す
+func Setup() error {
+	if _, err := config.ApplyProfile(); err != nil {
+		slog.Error("failed to apply profile", slog.Any("error", err))
+		return err
+	}
+	logger = logging.NewFromEnv()
+	logger.Info("starting remind", buildinfo.Get().Attr())
+	tracing.Setup("remind")
+	slog.SetDefault(logger)
+
+	return nil
+}
+
+// 連携先のクライアントを作成しておく、Lambda のコールドスタート時に呼び出す
+// 失敗した場合は最初の呼び出しで再度作成する
+func Warmup(ctx context.Context) {
+	if _, err := cachedClients.Get(ctx); err != nil {
+		slog.Warn("failed to init clients on cold start", slog.Any("error", err))
+	}
+}
@@ -0,0 +1,151 @@
+package remind
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      *Config
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name: "正常系/投稿先ごとに出力形式を指定する",
+			cfg: &Config{
+				Sinks:                []string{"discord", " Google_Chat "},
+				SinkFormats:          map[string]string{"discord": "markdown", "google_chat": "text"},
+				GoogleChatWebhookURL: "https://chat.googleapis.com/v1/spaces/x/messages",
+			},
+			expected: []string{"discord", "google_chat"},
+		},
+		{
+			name:     "正常系/出力形式を指定しない投稿先",
+			cfg:      &Config{Sinks: []string{"discord", "discord_scheduled_event"}},
+			expected: []string{"discord", "discord_scheduled_event"},
+		},
+		{
+			name:    "異常系/未対応の出力形式",
+			cfg:     &Config{Sinks: []string{"discord"}, SinkFormats: map[string]string{"discord": "html"}},
+			wantErr: true,
+		},
+		{
+			name:    "異常系/未対応の投稿先",
+			cfg:     &Config{Sinks: []string{"line"}},
+			wantErr: true,
+		},
+		{
+			name:    "異常系/Google Chat の Webhook URL がない場合",
+			cfg:     &Config{Sinks: []string{"google_chat"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			sinks, err := newSinks(tt.cfg, clock.System())
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			var names []string
+			for _, s := range sinks {
+				names = append(names, s.Name())
+			}
+			ta.Equal(tt.expected, names)
+		})
+	}
+}
+
+func TestPayloadUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Payload
+		wantErr  bool
+	}{
+		{
+			name:     "正常系/実行日と投稿しない指定",
+			input:    `{"date":"2025-03-01","dry_run":true}`,
+			expected: Payload{Date: "2025-03-01", DryRun: true},
+		},
+		{
+			name:     "正常系/空のペイロード",
+			input:    `{}`,
+			expected: Payload{},
+		},
+		{
+			name:    "異常系/dry_run が真偽値でない場合",
+			input:   `{"dry_run":"yes"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			var p Payload
+			err := json.Unmarshal([]byte(tt.input), &p)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, p)
+		})
+	}
+}
+
+func TestResolveToday(t *testing.T) {
+	clk := clock.Fixed(time.Date(2025, 3, 10, 23, 30, 0, 0, clock.JST()))
+
+	tests := []struct {
+		name     string
+		date     string
+		expected time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "正常系/指定しない場合は現在の日付",
+			date:     "",
+			expected: time.Date(2025, 3, 10, 0, 0, 0, 0, clock.JST()),
+		},
+		{
+			name:     "正常系/過去の日付を指定した場合",
+			date:     "2025-03-01",
+			expected: time.Date(2025, 3, 1, 0, 0, 0, 0, clock.JST()),
+		},
+		{
+			name:     "正常系/未来の日付を指定した場合",
+			date:     "2025-12-31",
+			expected: time.Date(2025, 12, 31, 0, 0, 0, 0, clock.JST()),
+		},
+		{
+			name:    "異常系/日付の形式が異なる場合",
+			date:    "2025/03/01",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			got, err := ResolveToday(clk, tt.date)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.True(tt.expected.Equal(got), "expected %s, got %s", tt.expected, got)
+			ta.Equal(clock.JST().String(), got.Location().String())
+		})
+	}
+}
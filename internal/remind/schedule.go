@@ -0,0 +1,69 @@
+package remind
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// スケジュールごとの挙動、EventBridge Scheduler のペイロードの profile で指定する
+// 1 つの関数で朝の通知、夕方のチェックリスト、週末の予告などの複数のスケジュールに対応する
+type ScheduleProfile struct {
+	Mode          string   `json:"mode"`
+	Tags          []string `json:"tags"`
+	ExcludeTags   []string `json:"exclude_tags"`   // e.g. 子ども用のチャンネルに投稿する ["school"] を朝の通知から除く
+	Channel       string   `json:"channel"`        // 投稿先の Discord のチャンネル ID
+	LookaheadDays int      `json:"lookahead_days"` // 未指定の場合は LOOKAHEAD_DAYS を使う
+}
+
+// e.g. {"evening": {"tags": ["evening"], "lookahead_days": 1}, "weekly": {"lookahead_days": 7}}
+type ScheduleProfiles map[string]ScheduleProfile
+
+func (p *ScheduleProfiles) UnmarshalText(text []byte) error {
+	// ScheduleProfiles のまま読み込むと UnmarshalText が再度呼び出されるため、map として読み込む
+	profiles := map[string]ScheduleProfile{}
+	if err := json.Unmarshal(text, &profiles); err != nil {
+		return fmt.Errorf("invalid schedule profiles: %w", err)
+	}
+	*p = profiles
+
+	return nil
+}
+
+// ペイロードで指定したプロファイルの値を、ペイロードで指定されていない項目に補う
+func resolvePayload(p Payload, profiles ScheduleProfiles) (Payload, error) {
+	if p.Profile == "" {
+		return p, nil
+	}
+	sp, ok := profiles[p.Profile]
+	if !ok {
+		return Payload{}, fmt.Errorf("invalid profile: %s", p.Profile)
+	}
+
+	if p.Mode == "" {
+		p.Mode = sp.Mode
+	}
+	if len(p.Tags) == 0 {
+		p.Tags = sp.Tags
+	}
+	if len(p.ExcludeTags) == 0 {
+		p.ExcludeTags = sp.ExcludeTags
+	}
+	if p.Channel == "" {
+		p.Channel = sp.Channel
+	}
+	if p.LookaheadDays == 0 {
+		p.LookaheadDays = sp.LookaheadDays
+	}
+
+	return p, nil
+}
+
+// ペイロードで指定された投稿先と日数で設定を上書きする
+func applyPayload(cfg *Config, p Payload) {
+	if p.Channel != "" {
+		cfg.DiscordChannelID = p.Channel
+	}
+	if p.LookaheadDays > 0 {
+		cfg.LookaheadDays = p.LookaheadDays
+	}
+}
@@ -0,0 +1,87 @@
+package remind
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleProfilesUnmarshalText(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	var p ScheduleProfiles
+	tr.NoError(p.UnmarshalText([]byte(`{"evening": {"tags": ["evening"], "channel": "123"}, "weekly": {"lookahead_days": 7}}`)))
+	ta.Equal(ScheduleProfiles{
+		"evening": {Tags: []string{"evening"}, Channel: "123"},
+		"weekly":  {LookaheadDays: 7},
+	}, p)
+
+	ta.Error(p.UnmarshalText([]byte(`evening:123`)))
+}
+
+func TestResolvePayload(t *testing.T) {
+	profiles := ScheduleProfiles{
+		"evening": {Tags: []string{"evening"}, Channel: "123"},
+		"weekly":  {LookaheadDays: 7},
+		"morning": {ExcludeTags: []string{"school"}},
+	}
+
+	tests := []struct {
+		name        string
+		payload     Payload
+		expectError bool
+		expected    Payload
+	}{
+		{
+			name:     "正常系/プロファイルが指定されていない場合",
+			payload:  Payload{Tags: []string{"morning"}},
+			expected: Payload{Tags: []string{"morning"}},
+		},
+		{
+			name:     "正常系/プロファイルの値で補う場合",
+			payload:  Payload{Profile: "evening"},
+			expected: Payload{Profile: "evening", Tags: []string{"evening"}, Channel: "123"},
+		},
+		{
+			name:     "正常系/ペイロードの値を優先する場合",
+			payload:  Payload{Profile: "evening", Channel: "456", LookaheadDays: 3},
+			expected: Payload{Profile: "evening", Tags: []string{"evening"}, Channel: "456", LookaheadDays: 3},
+		},
+		{
+			name:     "正常系/除くタグをプロファイルの値で補う場合",
+			payload:  Payload{Profile: "morning"},
+			expected: Payload{Profile: "morning", ExcludeTags: []string{"school"}},
+		},
+		{
+			name:        "異常系/未定義のプロファイルが指定された場合",
+			payload:     Payload{Profile: "sunday"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			p, err := resolvePayload(tt.payload, profiles)
+			if tt.expectError {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, p)
+		})
+	}
+}
+
+func TestApplyPayload(t *testing.T) {
+	ta := assert.New(t)
+
+	cfg := &Config{DiscordChannelID: "123", LookaheadDays: 2}
+	applyPayload(cfg, Payload{})
+	ta.Equal(&Config{DiscordChannelID: "123", LookaheadDays: 2}, cfg)
+
+	applyPayload(cfg, Payload{Channel: "456", LookaheadDays: 7})
+	ta.Equal(&Config{DiscordChannelID: "456", LookaheadDays: 7}, cfg)
+}
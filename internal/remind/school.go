@@ -0,0 +1,76 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/school"
+	"github.com/mami0tsu/homeops/internal/sources"
+)
+
+// 子どもごとの学校や保育園の年間行事予定を ICS とシートから読み込む
+// 一部の読み込みに失敗した場合も他のイベントは投稿できるように、読み込めた行事と取得元のエラーを返す
+func loadSchool(ctx context.Context, r sources.SheetDataReader, cfg *Config, loc *time.Location) ([]school.Entry, error) {
+	var entries []school.Entry
+	var errs []error
+	for _, cal := range cfg.SchoolCalendars {
+		if cal.ICSURL != "" {
+			e, err := fetchSchoolICS(ctx, httpclient.Default, cal, loc)
+			if err != nil {
+				slog.Warn("failed to load school calendar", slog.String("child", cal.Child), slog.Any("error", err))
+				errs = append(errs, err)
+			}
+			entries = append(entries, e...)
+		}
+		if cal.SheetTab != "" {
+			resp, err := r.GetValues(ctx, cfg.GoogleSpreadsheetID, fmt.Sprintf("%s!%s", cal.SheetTab, school.Columns))
+			if err != nil {
+				slog.Warn("failed to get school calendar", slog.String("child", cal.Child), slog.Any("error", err))
+				errs = append(errs, event.NewSourceUnavailableError(school.Tag, err))
+				continue
+			}
+			e, err := school.ParseRows(cal.Child, resp.Values, loc)
+			if err != nil {
+				slog.Warn("failed to parse school calendar", slog.String("child", cal.Child), slog.Any("error", err))
+				errs = append(errs, event.NewParseError(school.Tag, cal.SheetTab, err))
+				continue
+			}
+			entries = append(entries, e...)
+		}
+	}
+
+	return entries, errors.Join(errs...)
+}
+
+func fetchSchoolICS(ctx context.Context, client *http.Client, cal school.Calendar, loc *time.Location) ([]school.Entry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cal.ICSURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, event.NewSourceUnavailableError(school.Tag, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, event.NewSourceUnavailableError(school.Tag, fmt.Errorf("returned status %d", resp.StatusCode))
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, event.NewSourceUnavailableError(school.Tag, err)
+	}
+	entries, err := school.ParseICS(cal.Child, b, loc)
+	if err != nil {
+		return nil, event.NewParseError(school.Tag, cal.Child, err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,110 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/buildinfo"
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/sources"
+)
+
+// 連携先に読み取りのみの呼び出しを行い、認証情報の期限切れなどを検知するモード
+// 設定 (SSM パラメータ) の読み込みに失敗した場合は、通常の失敗として通知される
+const modeSelftest = "selftest"
+
+// 結果を通知済みのため、失敗の通知を重ねて送らない
+var errSelftestFailed = errors.New("selftest failed")
+
+type selftestCheck struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+type selftestResult struct {
+	name string
+	err  error
+}
+
+// 設定されている連携先のみを確認する
+func newSelftestChecks(db *dynamodb.Client, cfg *Config, clk clock.Clock) []selftestCheck {
+	checks := []selftestCheck{
+		{name: "sheets", run: func(ctx context.Context) error {
+			srv, err := sources.NewSheetsService(ctx, []byte(cfg.GoogleCredentials))
+			if err != nil {
+				return err
+			}
+			_, err = (&sources.GoogleSheetReader{Service: srv}).GetValues(ctx, cfg.GoogleSpreadsheetID, cfg.SheetTab+"!A1:Q1")
+			return err
+		}},
+		{name: "discord", run: func(ctx context.Context) error {
+			_, err := discord.NewClient(httpclient.Default, cfg.DiscordBotToken).Channel(ctx, cfg.DiscordChannelID)
+			return err
+		}},
+	}
+	if cfg.HolidaysURL != "" {
+		checks = append(checks, selftestCheck{name: "holidays", run: func(ctx context.Context) error {
+			_, err := sources.FetchHolidays(ctx, httpclient.Default, cfg.HolidaysURL)
+			return err
+		}})
+	}
+	if cfg.AckTableName != "" {
+		checks = append(checks, selftestCheck{name: "dynamodb", run: func(ctx context.Context) error {
+			_, err := newAckStore(db, cfg).listOpen(ctx, clock.Today(clk), 1)
+			return err
+		}})
+	}
+
+	return checks
+}
+
+// 全ての確認を行い、結果を失敗の通知先に投稿する、1 つでも失敗した場合はエラーを返す
+func runSelftest(ctx context.Context, db *dynamodb.Client, cfg *Config, clk clock.Clock) error {
+	var results []selftestResult
+	var errs []error
+	for _, c := range newSelftestChecks(db, cfg, clk) {
+		err := c.run(ctx)
+		if err != nil {
+			slog.Error("selftest failed", slog.String("check", c.name), slog.Any("error", err))
+			errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+		}
+		results = append(results, selftestResult{name: c.name, err: err})
+	}
+
+	report := createSelftestReport(os.Getenv("APP_ENV"), buildinfo.Get().String(), results)
+	slog.Info("selftest finished", slog.String("report", report))
+	if url := os.Getenv("FAILURE_WEBHOOK_URL"); url != "" {
+		if err := sendWebhook(ctx, url, report); err != nil {
+			slog.Error("failed to send selftest report", slog.Any("error", err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", errSelftestFailed, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func createSelftestReport(env, build string, results []selftestResult) string {
+	passed := 0
+	var lines []string
+	for _, r := range results {
+		if r.err != nil {
+			lines = append(lines, fmt.Sprintf("❌ %s: %s", r.name, discord.Truncate(r.err.Error(), 200)))
+			continue
+		}
+		passed++
+		lines = append(lines, fmt.Sprintf("✅ %s", r.name))
+	}
+	header := fmt.Sprintf("🩺 remind selftest (%s, %s): %d/%d passed", env, build, passed, len(results))
+
+	return strings.Join(append([]string{header}, lines...), "\n")
+}
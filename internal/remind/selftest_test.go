@@ -0,0 +1,19 @@
+package remind
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSelftestReport(t *testing.T) {
+	ta := assert.New(t)
+
+	report := createSelftestReport("prd", "dev (abc1234)", []selftestResult{
+		{name: "sheets"},
+		{name: "discord", err: errors.New("401 Unauthorized")},
+	})
+
+	ta.Equal("🩺 remind selftest (prd, dev (abc1234)): 1/2 passed\n✅ sheets\n❌ discord: 401 Unauthorized", report)
+}
@@ -0,0 +1,108 @@
+package remind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// Lambda の代わりに常駐して実行する場合の定期実行、EventBridge Scheduler のスケジュールに相当する
+type ServeSchedule struct {
+	Cron    string  `json:"cron"`    // e.g. "0 8 * * *"、TZ で指定したタイムゾーンで判定する
+	Payload Payload `json:"payload"` // e.g. {"profile": "evening"}
+
+	schedule *event.CronSchedule
+}
+
+// e.g. [{"cron": "0 8 * * *", "payload": {}}, {"cron": "*/15 * * * *", "payload": {"mode": "intraday"}}]
+type ServeSchedules []ServeSchedule
+
+func (s *ServeSchedules) UnmarshalText(text []byte) error {
+	// ServeSchedules のまま読み込むと UnmarshalText が再度呼び出されるため、スライスとして読み込む
+	var schedules []ServeSchedule
+	if err := json.Unmarshal(text, &schedules); err != nil {
+		return fmt.Errorf("invalid serve schedules: %w", err)
+	}
+	for i := range schedules {
+		c, err := event.ParseCron(schedules[i].Cron)
+		if err != nil {
+			return fmt.Errorf("invalid serve schedules: %w", err)
+		}
+		schedules[i].schedule = c
+	}
+	*s = schedules
+
+	return nil
+}
+
+// 1 回の実行の上限、Lambda のタイムアウトに相当する
+const serveTimeout = 5 * time.Minute
+
+// コンテナや Kubernetes で常駐し、毎分 0 秒に一致したスケジュールを順に実行する
+// ctx がキャンセルされたら、実行中の処理を終えてから戻る
+// e.g. go run . serve
+func Serve(ctx context.Context, clk clock.Clock) error {
+	c, err := cachedClients.Get(ctx)
+	if err != nil {
+		slog.Error("failed to init clients", slog.Any("error", err))
+		return err
+	}
+	schedules := c.cfg.ServeSchedules
+	if len(schedules) == 0 {
+		err := fmt.Errorf("SERVE_SCHEDULES is required for serve mode")
+		slog.Error("failed to start server", slog.Any("error", err))
+		return err
+	}
+	slog.Info("started serve mode", slog.Int("schedules", len(schedules)))
+
+	for {
+		next := clk.Now().Truncate(time.Minute).Add(time.Minute)
+		if err := sleepUntil(ctx, clk, next); err != nil {
+			slog.Info("stopped serve mode")
+			return nil
+		}
+		for _, p := range dueSchedules(schedules, next.In(clk.Location())) {
+			runScheduled(context.WithoutCancel(ctx), p)
+		}
+	}
+}
+
+// t に一致するスケジュールのペイロードを返す
+func dueSchedules(schedules ServeSchedules, t time.Time) []Payload {
+	var payloads []Payload
+	for _, s := range schedules {
+		if s.schedule.Match(t) {
+			payloads = append(payloads, s.Payload)
+		}
+	}
+
+	return payloads
+}
+
+// 停止の指示を受けても実行中の処理は終えられるように、キャンセルを引き継がずに実行する
+func runScheduled(ctx context.Context, p Payload) {
+	ctx, cancel := context.WithTimeout(ctx, serveTimeout)
+	defer cancel()
+
+	// 失敗は handleRequest が通知するため、次のスケジュールの実行を続ける
+	if err := handleRequest(ctx, p); err != nil {
+		slog.Warn("scheduled run failed", slog.String("mode", p.Mode), slog.String("profile", p.Profile), slog.Any("error", err))
+	}
+}
+
+func sleepUntil(ctx context.Context, clk clock.Clock, t time.Time) error {
+	timer := time.NewTimer(t.Sub(clk.Now()))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
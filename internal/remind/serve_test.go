@@ -0,0 +1,28 @@
+package remind
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeSchedules(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	var s ServeSchedules
+	tr.NoError(s.UnmarshalText([]byte(`[{"cron": "0 8 * * *", "payload": {}}, {"cron": "*/15 * * * *", "payload": {"mode": "intraday"}}, {"cron": "0 18 * * fri", "payload": {"profile": "weekly"}}]`)))
+	tr.Len(s, 3)
+
+	jst := clock.JST()
+	ta.Equal([]Payload{{}, {Mode: modeIntraday}}, dueSchedules(s, time.Date(2025, 3, 7, 8, 0, 0, 0, jst)))
+	ta.Equal([]Payload{{Mode: modeIntraday}}, dueSchedules(s, time.Date(2025, 3, 7, 9, 45, 0, 0, jst)))
+	ta.Equal([]Payload{{Mode: modeIntraday}, {Profile: "weekly"}}, dueSchedules(s, time.Date(2025, 3, 7, 18, 0, 0, 0, jst)))
+	ta.Empty(dueSchedules(s, time.Date(2025, 3, 7, 18, 1, 0, 0, jst)))
+
+	ta.Error(s.UnmarshalText([]byte(`[{"cron": "0 8 * *"}]`)))
+	ta.Error(s.UnmarshalText([]byte(`{"cron": "0 8 * * *"}`)))
+}
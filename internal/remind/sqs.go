@@ -0,0 +1,81 @@
+package remind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const sqsEventSource = "aws:sqs"
+
+// 処理に失敗したメッセージの一覧
+type sqsBatchResponse struct {
+	BatchItemFailures []sqsBatchItemFailure `json:"batchItemFailures"`
+}
+
+type sqsBatchItemFailure struct {
+	ItemIdentifier string `json:"itemIdentifier"`
+}
+
+// Lambda のハンドラー、呼び出し元に応じて SQS のメッセージの一括処理か、Payload による通常の処理を行う
+func HandleInvocation(ctx context.Context, raw json.RawMessage) (any, error) {
+	if evt, ok := parseSQSEvent(raw); ok {
+		return handleSQS(ctx, evt, handleRequest), nil
+	}
+
+	var p Payload
+	if err := json.Unmarshal(raw, &p); err != nil {
+		slog.Error("failed to parse payload", slog.Any("error", err))
+		return nil, err
+	}
+
+	return nil, handleRequest(ctx, p)
+}
+
+// SQS から呼び出された場合のみ true を返す
+func parseSQSEvent(raw json.RawMessage) (events.SQSEvent, bool) {
+	var evt events.SQSEvent
+	if err := json.Unmarshal(raw, &evt); err != nil || len(evt.Records) == 0 || evt.Records[0].EventSource != sqsEventSource {
+		return events.SQSEvent{}, false
+	}
+
+	return evt, true
+}
+
+// 他のシステムから SQS 経由で送られた、臨時の通知の依頼を 1 件ずつ処理し、失敗したメッセージの ID を返す
+// メッセージの本文は Payload の JSON とする、e.g. {"dates": ["2025-03-01"], "tags": ["evening"]}
+// イベントソースマッピングで ReportBatchItemFailures を有効にし、失敗したメッセージのみを再試行させる
+func handleSQS(ctx context.Context, evt events.SQSEvent, handle func(ctx context.Context, p Payload) error) sqsBatchResponse {
+	resp := sqsBatchResponse{BatchItemFailures: []sqsBatchItemFailure{}}
+	for _, m := range evt.Records {
+		if err := handleSQSMessage(ctx, m, handle); err != nil {
+			slog.Error("failed to process SQS message", slog.String("message_id", m.MessageId), slog.Any("error", err))
+			resp.BatchItemFailures = append(resp.BatchItemFailures, sqsBatchItemFailure{ItemIdentifier: m.MessageId})
+		}
+	}
+
+	return resp
+}
+
+// パニックした場合も、他のメッセージの処理を続けられるようにエラーとして返す
+func handleSQSMessage(ctx context.Context, m events.SQSMessage, handle func(ctx context.Context, p Payload) error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("recovered from panic: %v", v)
+		}
+	}()
+
+	var p Payload
+	if err := json.Unmarshal([]byte(m.Body), &p); err != nil {
+		return fmt.Errorf("invalid message body: %w", err)
+	}
+	// 同じメッセージが再配信された場合に重複して投稿しないように、メッセージの ID を実行 ID として使う
+	if p.RunID == "" {
+		p.RunID = "sqs#" + m.MessageId
+	}
+
+	return handle(ctx, p)
+}
@@ -0,0 +1,61 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSQSEvent(t *testing.T) {
+	ta := assert.New(t)
+
+	evt, ok := parseSQSEvent([]byte(`{"Records": [{"messageId": "m1", "body": "{}", "eventSource": "aws:sqs"}]}`))
+	ta.True(ok)
+	ta.Equal("m1", evt.Records[0].MessageId)
+
+	_, ok = parseSQSEvent([]byte(`{"mode": "intraday"}`))
+	ta.False(ok)
+	_, ok = parseSQSEvent([]byte(`{"Records": [{"eventSource": "aws:sns"}]}`))
+	ta.False(ok)
+}
+
+func TestHandleSQS(t *testing.T) {
+	ta := assert.New(t)
+
+	var handled []Payload
+	handle := func(ctx context.Context, p Payload) error {
+		handled = append(handled, p)
+		switch p.Mode {
+		case "fail":
+			return errors.New("failed to post events")
+		case "panic":
+			panic("unexpected")
+		}
+		return nil
+	}
+	evt := events.SQSEvent{Records: []events.SQSMessage{
+		{MessageId: "m1", Body: `{"tags": ["evening"]}`},
+		{MessageId: "m2", Body: `{"mode": "fail"}`},
+		{MessageId: "m3", Body: `not json`},
+		{MessageId: "m4", Body: `{"mode": "panic"}`},
+		{MessageId: "m5", Body: `{"run_id": "request-1"}`},
+	}}
+
+	resp := handleSQS(context.Background(), evt, handle)
+
+	// 失敗したメッセージのみを再試行させる
+	ta.Equal(sqsBatchResponse{BatchItemFailures: []sqsBatchItemFailure{
+		{ItemIdentifier: "m2"},
+		{ItemIdentifier: "m3"},
+		{ItemIdentifier: "m4"},
+	}}, resp)
+	ta.Equal([]Payload{
+		{Tags: []string{"evening"}, RunID: "sqs#m1"},
+		{Mode: "fail", RunID: "sqs#m2"},
+		{Mode: "panic", RunID: "sqs#m4"},
+		{RunID: "request-1"},
+	}, handled)
+}
@@ -0,0 +1,32 @@
+package remind
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/sources"
+	"github.com/mami0tsu/homeops/internal/stock"
+)
+
+// hello の /stock で記録した食品の在庫を読み込む、シートが指定されていない場合は nil を返す
+// シートから読み込めなかった場合も他のイベントは投稿できるように、取得元のエラーとして返す
+func loadStock(ctx context.Context, r sources.SheetDataReader, cfg *Config, loc *time.Location) ([]stock.Item, error) {
+	if cfg.StockSheetTab == "" {
+		return nil, nil
+	}
+	resp, err := r.GetValues(ctx, cfg.GoogleSpreadsheetID, fmt.Sprintf("%s!%s", cfg.StockSheetTab, stock.Columns))
+	if err != nil {
+		slog.Warn("failed to get food stock", slog.Any("error", err))
+		return nil, event.NewSourceUnavailableError(stock.Tag, err)
+	}
+	items, err := stock.ParseRows(resp.Values, loc)
+	if err != nil {
+		slog.Warn("failed to parse food stock", slog.Any("error", err))
+		return nil, event.NewParseError(stock.Tag, cfg.StockSheetTab, err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,117 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/notify"
+	"github.com/mami0tsu/homeops/internal/tracking"
+)
+
+// hello の /track で登録した荷物の配送状況を取得し、変わった荷物を投稿する、e.g. {"mode": "tracking"}
+const modeTracking = "tracking"
+
+const trackingColor = 0x3498db
+
+type packageStore interface {
+	List(ctx context.Context) ([]tracking.Package, error)
+	UpdateStatus(ctx context.Context, p tracking.Package, status tracking.Status) error
+	Delete(ctx context.Context, p tracking.Package) error
+}
+
+type statusTracker interface {
+	Status(ctx context.Context, c tracking.Carrier, number string) (tracking.Status, error)
+}
+
+func newPackageStore(client *dynamodb.Client, cfg *Config) (*tracking.Store, error) {
+	if cfg.TrackingTableName == "" {
+		return nil, errors.New("TRACKING_TABLE_NAME is not set")
+	}
+
+	return tracking.NewStore(client, cfg.TrackingTableName), nil
+}
+
+// 配送状況が変わった荷物
+type trackingChange struct {
+	Package tracking.Package
+	Status  tracking.Status
+}
+
+// 登録した荷物の配送状況を取得して、変わった荷物をまとめて投稿する
+// 配達が完了した荷物は投稿した後に削除する
+// 一部の荷物の取得に失敗しても他の荷物は投稿できるように、取得のエラーはログに出力して次回の呼び出しで再度取得する
+func runTracking(ctx context.Context, store packageStore, tracker statusTracker, nc *notify.Config, dryRun bool) error {
+	packages, err := store.List(ctx)
+	if err != nil {
+		slog.Error("failed to list packages", slog.Any("error", err))
+		return err
+	}
+
+	var changes []trackingChange
+	for _, p := range packages {
+		status, err := tracker.Status(ctx, p.Carrier, p.Number)
+		if errors.Is(err, tracking.ErrNotFound) {
+			// 発送前は配送業者に登録されていないことがある
+			slog.Info("package is not registered yet", slog.String("carrier", string(p.Carrier)), slog.String("number", p.Number))
+			continue
+		}
+		if err != nil {
+			slog.Error("failed to get package status", slog.String("carrier", string(p.Carrier)), slog.String("number", p.Number), slog.Any("error", err))
+			continue
+		}
+		if status != p.Status {
+			changes = append(changes, trackingChange{Package: p, Status: status})
+		}
+	}
+	if len(changes) == 0 {
+		slog.Info("no package status changed", slog.Int("packages", len(packages)))
+		return nil
+	}
+
+	embeds := make([]*discord.Embed, 0, len(changes))
+	for _, c := range changes {
+		embeds = append(embeds, createTrackingEmbed(c))
+	}
+
+	// 投稿せずに投稿内容を確認する
+	if dryRun {
+		for _, e := range embeds {
+			slog.Info("dry run", slog.String("change", e.Title))
+		}
+		return nil
+	}
+
+	if err := notify.PostDiscordMessages(ctx, nc, &discord.WebhookMessage{Embeds: embeds}); err != nil {
+		slog.Error("failed to post package status", slog.Any("error", err))
+		return err
+	}
+
+	// 投稿した後に記録して、投稿に失敗した場合は次回の呼び出しで再度投稿する
+	var errs []error
+	for _, c := range changes {
+		if c.Status == tracking.StatusDelivered {
+			err = store.Delete(ctx, c.Package)
+		} else {
+			err = store.UpdateStatus(ctx, c.Package, c.Status)
+		}
+		if err != nil {
+			slog.Error("failed to update package", slog.String("number", c.Package.Number), slog.Any("error", err))
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func createTrackingEmbed(c trackingChange) *discord.Embed {
+	embed := discord.NewEmbed(fmt.Sprintf("%s %s: %s", c.Status.Emoji(), c.Package.Label(), c.Status), trackingColor)
+	if c.Package.AddedBy != "" {
+		embed.Description = fmt.Sprintf("登録: %s", c.Package.AddedBy)
+	}
+
+	return embed
+}
@@ -0,0 +1,99 @@
+package remind
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/clock"
+	"github.com/mami0tsu/homeops/internal/testsupport"
+	"github.com/mami0tsu/homeops/internal/tracking"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePackageStore struct {
+	packages []tracking.Package
+	updated  map[string]tracking.Status
+	deleted  []string
+}
+
+func (s *fakePackageStore) List(ctx context.Context) ([]tracking.Package, error) {
+	return s.packages, nil
+}
+
+func (s *fakePackageStore) UpdateStatus(ctx context.Context, p tracking.Package, status tracking.Status) error {
+	s.updated[p.Number] = status
+	return nil
+}
+
+func (s *fakePackageStore) Delete(ctx context.Context, p tracking.Package) error {
+	s.deleted = append(s.deleted, p.Number)
+	return nil
+}
+
+type fakeTracker map[string]tracking.Status
+
+func (t fakeTracker) Status(ctx context.Context, c tracking.Carrier, number string) (tracking.Status, error) {
+	switch number {
+	case "0000000000":
+		return "", tracking.ErrNotFound
+	case "9999999999":
+		return "", errors.New("returned status 503")
+	}
+
+	return t[number], nil
+}
+
+func TestRunTracking(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	srv := testsupport.NewServer(t)
+	cfg := &Config{DiscordBotName: "remind", DiscordBotToken: "token", DiscordChannelID: "123"}
+	nc := cfg.notifyConfig(clock.Fixed(time.Date(2025, 3, 1, 12, 0, 0, 0, clock.JST())))
+	nc.HTTPClient = srv.Client()
+	store := &fakePackageStore{
+		packages: []tracking.Package{
+			{Carrier: tracking.Yamato, Number: "1111111111", Memo: "本棚", Status: tracking.StatusInTransit},
+			{Carrier: tracking.Sagawa, Number: "2222222222", Status: tracking.StatusOut},
+			{Carrier: tracking.JapanPost, Number: "3333333333", Status: tracking.StatusInTransit},
+			{Carrier: tracking.JapanPost, Number: "0000000000"},
+			{Carrier: tracking.JapanPost, Number: "9999999999"},
+		},
+		updated: map[string]tracking.Status{},
+	}
+	tracker := fakeTracker{
+		"1111111111": tracking.StatusOut,
+		"2222222222": tracking.StatusDelivered,
+		"3333333333": tracking.StatusInTransit,
+	}
+
+	tr.NoError(runTracking(context.Background(), store, tracker, nc, false))
+
+	msgs := srv.Discord.Messages()
+	tr.Len(msgs, 1)
+	tr.Len(msgs[0].Embeds, 2)
+	ta.Equal("🚚 本棚 (ヤマト運輸 1111111111): 配達中", msgs[0].Embeds[0].Title)
+	ta.Equal("✅ 佐川急便 2222222222: 配達完了", msgs[0].Embeds[1].Title)
+	ta.Equal(map[string]tracking.Status{"1111111111": tracking.StatusOut}, store.updated)
+	ta.Equal([]string{"2222222222"}, store.deleted)
+}
+
+func TestRunTrackingDryRun(t *testing.T) {
+	ta := assert.New(t)
+
+	srv := testsupport.NewServer(t)
+	cfg := &Config{DiscordBotName: "remind", DiscordBotToken: "token", DiscordChannelID: "123"}
+	nc := cfg.notifyConfig(clock.Fixed(time.Date(2025, 3, 1, 12, 0, 0, 0, clock.JST())))
+	nc.HTTPClient = srv.Client()
+	store := &fakePackageStore{
+		packages: []tracking.Package{{Carrier: tracking.Yamato, Number: "1111111111"}},
+		updated:  map[string]tracking.Status{},
+	}
+
+	ta.NoError(runTracking(context.Background(), store, fakeTracker{"1111111111": tracking.StatusAccepted}, nc, true))
+	ta.Empty(srv.Discord.Messages())
+	ta.Empty(store.updated)
+}
@@ -0,0 +1,74 @@
+package remind
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/mami0tsu/homeops/internal/awsjson"
+	"github.com/mami0tsu/homeops/internal/config"
+	"github.com/mami0tsu/homeops/internal/discord"
+)
+
+// 作成した Webhook を SSM パラメータ /<APP_ENV>/remind/cache/discord_webhook_<channel_id> に保存する
+// 環境変数に展開しないように、cache は設定のグループとして使わない
+type ssmWebhookStore struct {
+	appEnv string
+}
+
+func webhookParameterName(appEnv, channelID string) string {
+	return config.ParameterPath{Env: appEnv, Function: "remind", Service: "cache", Name: "discord_webhook_" + channelID}.String()
+}
+
+func (s *ssmWebhookStore) Load(ctx context.Context, channelID string) (*discord.Webhook, error) {
+	client, err := awsjson.New(ctx, "ssm", "AmazonSSM", "application/x-amz-json-1.1")
+	if err != nil {
+		return nil, err
+	}
+
+	in := map[string]any{"Name": webhookParameterName(s.appEnv, channelID), "WithDecryption": true}
+	var out struct {
+		Parameter struct {
+			Value string `json:"Value"`
+		} `json:"Parameter"`
+	}
+	if err := client.Do(ctx, "GetParameter", in, &out); err != nil {
+		var awsErr *awsjson.Error
+		if errors.As(err, &awsErr) && awsErr.Type == "ParameterNotFound" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return decodeWebhook(out.Parameter.Value)
+}
+
+func (s *ssmWebhookStore) Save(ctx context.Context, channelID string, w *discord.Webhook) error {
+	client, err := awsjson.New(ctx, "ssm", "AmazonSSM", "application/x-amz-json-1.1")
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(w)
+	if err != nil {
+		return err
+	}
+	in := map[string]any{
+		"Name":      webhookParameterName(s.appEnv, channelID),
+		"Value":     string(value),
+		"Type":      "SecureString",
+		"Overwrite": true,
+	}
+
+	return client.Do(ctx, "PutParameter", in, nil)
+}
+
+// 保存した値が壊れている場合は、保存されていないものとして作り直す
+func decodeWebhook(s string) (*discord.Webhook, error) {
+	var w discord.Webhook
+	if err := json.Unmarshal([]byte(s), &w); err != nil || w.ID == "" || w.Token == "" {
+		return nil, nil
+	}
+
+	return &w, nil
+}
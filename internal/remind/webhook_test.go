@@ -0,0 +1,48 @@
+package remind
+
+import (
+	"testing"
+
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookParameterName(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.Equal("/prd/remind/cache/discord_webhook_123", webhookParameterName("prd", "123"))
+}
+
+func TestDecodeWebhook(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected *discord.Webhook
+	}{
+		{
+			name:     "正常系/保存した Webhook を読み込む",
+			value:    `{"id":"1","token":"t"}`,
+			expected: &discord.Webhook{ID: "1", Token: "t"},
+		},
+		{
+			name:     "正常系/JSON でない場合は保存されていないものとして扱う",
+			value:    "broken",
+			expected: nil,
+		},
+		{
+			name:     "正常系/トークンが空の場合は保存されていないものとして扱う",
+			value:    `{"id":"1"}`,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			w, err := decodeWebhook(tt.value)
+			ta.NoError(err)
+			ta.Equal(tt.expected, w)
+		})
+	}
+}
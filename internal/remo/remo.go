@@ -0,0 +1,261 @@
+// Package remo は Nature Remo の Cloud API から室内のセンサーの値を取得し、家電を操作する
+// https://developer.nature.global/
+package remo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/indoor"
+)
+
+const SourceName = "nature-remo"
+
+var ErrNoSmartMeter = errors.New("smart meter not found")
+
+type Client struct {
+	http    *http.Client
+	baseURL string
+	token   string
+}
+
+// baseURL は e.g. https://api.nature.global
+func NewClient(client *http.Client, baseURL, token string) *Client {
+	return &Client{http: client, baseURL: strings.TrimSuffix(baseURL, "/"), token: token}
+}
+
+type sensorValue struct {
+	Value     float64   `json:"val"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type device struct {
+	ID           string                 `json:"id"`
+	Name         string                 `json:"name"`
+	NewestEvents map[string]sensorValue `json:"newest_events"`
+}
+
+// 家電
+type Appliance struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"` // e.g. AC, TV, LIGHT, IR
+	Nickname   string      `json:"nickname"`
+	Signals    []Signal    `json:"signals"`
+	SmartMeter *SmartMeter `json:"smart_meter"` // Nature Remo E の場合のみ
+}
+
+// 赤外線の信号
+type Signal struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+const (
+	applianceTypeAircon     = "AC"
+	applianceTypeSmartMeter = "EL_SMART_METER"
+)
+
+// Nature Remo E が取得したスマートメーターの ECHONET Lite のプロパティ
+type SmartMeter struct {
+	Properties []EchonetProperty `json:"echonetlite_properties"`
+}
+
+type EchonetProperty struct {
+	EPC       int       `json:"epc"`
+	Value     string    `json:"val"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 低圧スマート電力量メーターのプロパティ
+const (
+	epcCoefficient      = 0xd3 // 係数
+	epcCumulativeEnergy = 0xe0 // 積算電力量計測値 (正方向)
+	epcEnergyUnit       = 0xe1 // 積算電力量単位
+)
+
+// 積算電力量単位のコードごとの kWh への倍率
+var energyUnits = map[int]float64{
+	0x00: 1, 0x01: 0.1, 0x02: 0.01, 0x03: 0.001, 0x04: 0.0001,
+	0x0a: 10, 0x0b: 100, 0x0c: 1000, 0x0d: 10000,
+}
+
+// 積算電力量 (kWh) と計測した時刻を返す
+// 係数が取得できていない場合は 1 とする
+func (m SmartMeter) CumulativeEnergy() (float64, time.Time, error) {
+	values := make(map[int]EchonetProperty, len(m.Properties))
+	for _, p := range m.Properties {
+		values[p.EPC] = p
+	}
+
+	energy, ok := values[epcCumulativeEnergy]
+	if !ok {
+		return 0, time.Time{}, fmt.Errorf("cumulative energy is missing")
+	}
+	v, err := strconv.ParseFloat(energy.Value, 64)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("invalid cumulative energy: %s", energy.Value)
+	}
+	coefficient := 1.0
+	if p, ok := values[epcCoefficient]; ok {
+		if coefficient, err = strconv.ParseFloat(p.Value, 64); err != nil {
+			return 0, time.Time{}, fmt.Errorf("invalid coefficient: %s", p.Value)
+		}
+	}
+	unit := 1.0
+	if p, ok := values[epcEnergyUnit]; ok {
+		code, err := strconv.Atoi(p.Value)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("invalid energy unit: %s", p.Value)
+		}
+		if unit, ok = energyUnits[code]; !ok {
+			return 0, time.Time{}, fmt.Errorf("invalid energy unit: %s", p.Value)
+		}
+	}
+
+	// 単位の倍率による誤差を丸める
+	return math.Round(v*coefficient*unit*10000) / 10000, energy.UpdatedAt, nil
+}
+
+func (a Appliance) IsAircon() bool {
+	return a.Type == applianceTypeAircon
+}
+
+func (a Appliance) IsSmartMeter() bool {
+	return a.Type == applianceTypeSmartMeter && a.SmartMeter != nil
+}
+
+// 名前が一致する信号を返す
+func (a Appliance) FindSignal(name string) (Signal, bool) {
+	for _, s := range a.Signals {
+		if strings.EqualFold(s.Name, name) {
+			return s, true
+		}
+	}
+
+	return Signal{}, false
+}
+
+// 機器ごとに最新のセンサーの値を返す
+func (c *Client) Readings(ctx context.Context) ([]indoor.Reading, error) {
+	var devices []device
+	if err := c.call(ctx, http.MethodGet, "/1/devices", nil, &devices); err != nil {
+		return nil, err
+	}
+
+	var readings []indoor.Reading
+	for _, d := range devices {
+		if len(d.NewestEvents) == 0 {
+			continue
+		}
+		r := indoor.Reading{Device: d.Name}
+		for key, v := range d.NewestEvents {
+			switch key {
+			case "te":
+				r.Temperature = &v.Value
+			case "hu":
+				r.Humidity = &v.Value
+			case "il":
+				r.Illuminance = &v.Value
+			default:
+				continue
+			}
+			if v.CreatedAt.After(r.Time) {
+				r.Time = v.CreatedAt
+			}
+		}
+		readings = append(readings, r)
+	}
+
+	return readings, nil
+}
+
+func (c *Client) Appliances(ctx context.Context) ([]Appliance, error) {
+	var appliances []Appliance
+	if err := c.call(ctx, http.MethodGet, "/1/appliances", nil, &appliances); err != nil {
+		return nil, err
+	}
+
+	return appliances, nil
+}
+
+// Nature Remo E に登録したスマートメーターの積算電力量 (kWh) と計測した時刻を返す
+// 複数ある場合は最初のものを使う
+func (c *Client) CumulativeEnergy(ctx context.Context) (float64, time.Time, error) {
+	appliances, err := c.Appliances(ctx)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	for _, a := range appliances {
+		if !a.IsSmartMeter() {
+			continue
+		}
+		v, t, err := a.SmartMeter.CumulativeEnergy()
+		if err != nil {
+			return 0, time.Time{}, event.NewParseError(SourceName, a.Nickname, err)
+		}
+		return v, t, nil
+	}
+
+	return 0, time.Time{}, ErrNoSmartMeter
+}
+
+// エアコンの電源を入れる、もしくは切る
+// 電源を入れる場合は、最後に設定した運転モードと温度で運転する
+func (c *Client) SetAircon(ctx context.Context, applianceID string, on bool) error {
+	form := url.Values{}
+	// 空の場合は電源を入れる
+	form.Set("button", "")
+	if !on {
+		form.Set("button", "power-off")
+	}
+
+	return c.call(ctx, http.MethodPost, "/1/appliances/"+url.PathEscape(applianceID)+"/aircon_settings", form, nil)
+}
+
+func (c *Client) SendSignal(ctx context.Context, signalID string) error {
+	return c.call(ctx, http.MethodPost, "/1/signals/"+url.PathEscape(signalID)+"/send", url.Values{}, nil)
+}
+
+// 更新系の API はフォームで値を受け取る
+func (c *Client) call(ctx context.Context, method, path string, form url.Values, out any) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return event.NewSourceUnavailableError(SourceName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return event.NewSourceUnavailableError(SourceName, fmt.Errorf("returned status %d", resp.StatusCode))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return event.NewParseError(SourceName, path, err)
+	}
+
+	return nil
+}
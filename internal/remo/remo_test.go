@@ -0,0 +1,161 @@
+package remo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/indoor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr(v float64) *float64 {
+	return &v
+}
+
+func TestClient(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(b))
+		switch r.URL.Path {
+		case "/1/devices":
+			w.Write([]byte(`[
+				{"id": "d1", "name": "リビング", "newest_events": {
+					"te": {"val": 24.5, "created_at": "2025-03-01T08:00:00Z"},
+					"hu": {"val": 35, "created_at": "2025-03-01T08:01:00Z"},
+					"il": {"val": 120, "created_at": "2025-03-01T07:59:00Z"},
+					"mo": {"val": 1, "created_at": "2025-03-01T08:05:00Z"}
+				}},
+				{"id": "d2", "name": "寝室", "newest_events": {"te": {"val": 17.2, "created_at": "2025-03-01T08:00:00Z"}}},
+				{"id": "d3", "name": "Remo mini", "newest_events": {}}
+			]`))
+		case "/1/appliances":
+			w.Write([]byte(`[{"id": "a1", "type": "AC", "nickname": "エアコン"}, {"id": "a2", "type": "IR", "nickname": "扇風機", "signals": [{"id": "s1", "name": "電源"}]}]`))
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(srv.Client(), srv.URL+"/", "token")
+
+	t.Run("正常系/センサーの値を取得する", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		readings, err := c.Readings(context.Background())
+		tr.NoError(err)
+		ta.Equal([]indoor.Reading{
+			{Device: "リビング", Temperature: ptr(24.5), Humidity: ptr(35), Illuminance: ptr(120), Time: time.Date(2025, 3, 1, 8, 1, 0, 0, time.UTC)},
+			{Device: "寝室", Temperature: ptr(17.2), Time: time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)},
+		}, readings)
+	})
+
+	t.Run("正常系/家電を操作する", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		appliances, err := c.Appliances(context.Background())
+		tr.NoError(err)
+		tr.Len(appliances, 2)
+		ta.True(appliances[0].IsAircon())
+		s, ok := appliances[1].FindSignal("電源")
+		ta.True(ok)
+
+		requests = nil
+		tr.NoError(c.SetAircon(context.Background(), appliances[0].ID, true))
+		tr.NoError(c.SetAircon(context.Background(), appliances[0].ID, false))
+		tr.NoError(c.SendSignal(context.Background(), s.ID))
+		ta.Equal([]string{
+			"POST /1/appliances/a1/aircon_settings button=",
+			"POST /1/appliances/a1/aircon_settings button=power-off",
+			"POST /1/signals/s1/send ",
+		}, requests)
+	})
+
+	t.Run("異常系/認証に失敗した場合", func(t *testing.T) {
+		_, err := NewClient(srv.Client(), srv.URL, "invalid").Readings(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+func TestSmartMeterCumulativeEnergy(t *testing.T) {
+	updated := time.Date(2025, 3, 1, 0, 5, 0, 0, time.UTC)
+	cases := []struct {
+		name       string
+		properties []EchonetProperty
+		expected   float64
+		wantErr    bool
+	}{
+		{
+			name: "正常系/係数と単位を掛ける",
+			properties: []EchonetProperty{
+				{EPC: 0xd3, Value: "1"},
+				{EPC: 0xe0, Value: "123456", UpdatedAt: updated},
+				{EPC: 0xe1, Value: "1"},
+				{EPC: 0xe7, Value: "512"},
+			},
+			expected: 12345.6,
+		},
+		{
+			name:       "正常系/係数と単位がない場合",
+			properties: []EchonetProperty{{EPC: 0xe0, Value: "5000", UpdatedAt: updated}},
+			expected:   5000,
+		},
+		{
+			name:       "異常系/積算電力量がない場合",
+			properties: []EchonetProperty{{EPC: 0xe7, Value: "512"}},
+			wantErr:    true,
+		},
+		{
+			name:       "異常系/単位が不明な場合",
+			properties: []EchonetProperty{{EPC: 0xe0, Value: "5000"}, {EPC: 0xe1, Value: "9"}},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			v, at, err := SmartMeter{Properties: tt.properties}.CumulativeEnergy()
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, v)
+			ta.Equal(updated, at)
+		})
+	}
+}
+
+func TestClientCumulativeEnergy(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	appliances := `[{"id": "a1", "type": "AC", "nickname": "エアコン"}]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(appliances))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.Client(), srv.URL, "token")
+
+	_, _, err := c.CumulativeEnergy(context.Background())
+	ta.ErrorIs(err, ErrNoSmartMeter)
+
+	appliances = `[{"id": "a1", "type": "AC", "nickname": "エアコン"}, {"id": "a2", "type": "EL_SMART_METER", "nickname": "スマートメーター", "smart_meter": {"echonetlite_properties": [
+		{"name": "normal_direction_cumulative_electric_energy", "epc": 224, "val": "43210", "updated_at": "2025-03-01T00:05:00Z"},
+		{"name": "cumulative_electric_energy_unit", "epc": 225, "val": "2"}
+	]}}]`
+	v, at, err := c.CumulativeEnergy(context.Background())
+	tr.NoError(err)
+	ta.Equal(432.1, v)
+	ta.Equal(time.Date(2025, 3, 1, 0, 5, 0, 0, time.UTC), at)
+}
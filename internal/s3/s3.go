@@ -0,0 +1,110 @@
+// Package s3 は S3 のオブジェクトの読み書きを行う最小限のクライアントを提供する
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/sigv4"
+)
+
+// S3 が返すエラー
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("s3: %s: %s", e.Code, e.Message)
+}
+
+func IsNotFound(err error) bool {
+	var e *Error
+
+	return errors.As(err, &e) && (e.Code == "NoSuchKey" || e.StatusCode == http.StatusNotFound)
+}
+
+// S3 の API を直接呼び出すクライアント
+// 必要な操作が限られているため、SDK のクライアントではなく署名付きの HTTP リクエストを送る
+type Client struct {
+	client   *http.Client
+	endpoint func(bucket string) string
+	signer   *sigv4.Signer
+}
+
+func NewClient(ctx context.Context, client *http.Client) (*Client, error) {
+	signer, err := sigv4.New(ctx, "s3", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		client: client,
+		endpoint: func(bucket string) string {
+			return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, signer.Region)
+		},
+		signer: signer,
+	}, nil
+}
+
+func (c *Client) PutObject(ctx context.Context, bucket, key, contentType string, body []byte) error {
+	_, err := c.do(ctx, http.MethodPut, bucket, key, contentType, body)
+
+	return err
+}
+
+func (c *Client) GetObject(ctx context.Context, bucket, key string) ([]byte, error) {
+	return c.do(ctx, http.MethodGet, bucket, key, "", nil)
+}
+
+func (c *Client) do(ctx context.Context, method, bucket, key, contentType string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint(bucket)+"/"+escapeKey(key), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if err := c.signer.Sign(ctx, req, body); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		}
+		_ = xml.Unmarshal(b, &e)
+		return nil, &Error{StatusCode: resp.StatusCode, Code: e.Code, Message: e.Message}
+	}
+
+	return b, nil
+}
+
+// "/" は区切りとして残し、それ以外をエスケープする
+func escapeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+
+	return strings.Join(parts, "/")
+}
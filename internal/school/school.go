@@ -0,0 +1,237 @@
+// Package school は子どもごとの学校や保育園の年間行事予定を、ICS もしくはスプレッドシートから読み込む
+package school
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// 子どもごとの年間行事予定の読み込み先、ICS とシートの両方を指定した場合は合わせて読み込む
+type Calendar struct {
+	Child    string `json:"child"`     // e.g. はな、イベントのタグにも使う
+	ICSURL   string `json:"ics_url"`   // e.g. https://example.com/school.ics
+	SheetTab string `json:"sheet_tab"` // e.g. school_hana
+}
+
+// e.g. [{"child": "はな", "ics_url": "https://example.com/school.ics"}, {"child": "そら", "sheet_tab": "daycare_sora"}]
+type Calendars []Calendar
+
+func (c *Calendars) UnmarshalText(text []byte) error {
+	// Calendars のまま読み込むと UnmarshalText が再度呼び出されるため、スライスとして読み込む
+	var calendars []Calendar
+	if err := json.Unmarshal(text, &calendars); err != nil {
+		return fmt.Errorf("invalid school calendars: %w", err)
+	}
+	for _, cal := range calendars {
+		if strings.TrimSpace(cal.Child) == "" {
+			return fmt.Errorf("invalid school calendars: child is blank")
+		}
+		if cal.ICSURL == "" && cal.SheetTab == "" {
+			return fmt.Errorf("invalid school calendars: %s: ics_url or sheet_tab is required", cal.Child)
+		}
+	}
+	*c = calendars
+
+	return nil
+}
+
+// 子どもごとの年間行事予定を記録するシートの列、日付、行事、持ち物の順
+const Columns = "A:C"
+
+// 行事
+type Entry struct {
+	Child   string
+	Name    string // e.g. 遠足
+	Date    time.Time
+	EndDate time.Time // 複数日にわたる場合の最終日、1 日の場合は Date と同じ
+	Time    string    // 時刻が決まっている場合の開始時刻、e.g. 09:30
+	Items   []string  // 持ち物、e.g. 弁当、水筒
+}
+
+// 指定した日付に行われるかを返す
+func (e Entry) On(t time.Time) bool {
+	return !t.Before(e.Date) && !t.After(e.EndDate)
+}
+
+// 持ち物は読点もしくはカンマで区切る
+var itemSeparator = regexp.MustCompile(`\s*[、,，]\s*`)
+
+func parseItems(s string) []string {
+	var items []string
+	for _, v := range itemSeparator.Split(strings.TrimSpace(s), -1) {
+		if v != "" {
+			items = append(items, v)
+		}
+	}
+
+	return items
+}
+
+// シートの行を読み込む、1 行目は見出しとして読み飛ばす
+// 日付と行事が空の行は読み飛ばす
+func ParseRows(child string, rows [][]interface{}, loc *time.Location) ([]Entry, error) {
+	var entries []Entry
+	for i, r := range rows {
+		if i == 0 {
+			continue
+		}
+		date, name := cell(r, 0), cell(r, 1)
+		if date == "" && name == "" {
+			continue
+		}
+		t, err := time.ParseInLocation("2006/1/2", strings.ReplaceAll(date, "-", "/"), loc)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid date: %s", i+1, date)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("row %d: name is blank", i+1)
+		}
+		entries = append(entries, Entry{Child: child, Name: name, Date: t, EndDate: t, Items: parseItems(cell(r, 2))})
+	}
+
+	return entries, nil
+}
+
+// ICS の VEVENT を読み込む
+// 持ち物は DESCRIPTION の「持ち物:」で始まる行から読み込む
+func ParseICS(child string, data []byte, loc *time.Location) ([]Entry, error) {
+	var entries []Entry
+	var e *Entry
+	for _, line := range unfoldLines(data) {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, params, _ := strings.Cut(name, ";")
+		switch strings.ToUpper(key) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				e = &Entry{Child: child}
+			}
+		case "END":
+			if !strings.EqualFold(value, "VEVENT") || e == nil {
+				continue
+			}
+			if e.Date.IsZero() || e.Name == "" {
+				return nil, fmt.Errorf("invalid event: DTSTART and SUMMARY are required")
+			}
+			if e.EndDate.Before(e.Date) {
+				e.EndDate = e.Date
+			}
+			entries = append(entries, *e)
+			e = nil
+		case "SUMMARY":
+			if e != nil {
+				e.Name = unescape(value)
+			}
+		case "DESCRIPTION":
+			if e == nil {
+				continue
+			}
+			for _, l := range strings.Split(unescape(value), "\n") {
+				if rest, ok := cutItemsLabel(l); ok {
+					e.Items = parseItems(rest)
+				}
+			}
+		case "DTSTART", "DTEND":
+			if e == nil {
+				continue
+			}
+			t, allDay, err := parseICSTime(value, params, loc)
+			if err != nil {
+				return nil, err
+			}
+			if strings.EqualFold(key, "DTSTART") {
+				e.Date = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+				if !allDay {
+					e.Time = t.Format("15:04")
+				}
+				continue
+			}
+			// 終日の場合の DTEND は翌日になる
+			if allDay {
+				t = t.AddDate(0, 0, -1)
+			}
+			e.EndDate = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}
+	}
+
+	return entries, nil
+}
+
+func cutItemsLabel(s string) (string, bool) {
+	for _, label := range []string{"持ち物:", "持ち物："} {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(s), label); ok {
+			return rest, true
+		}
+	}
+
+	return "", false
+}
+
+// 日付のみの場合は終日として扱う
+func parseICSTime(value, params string, loc *time.Location) (time.Time, bool, error) {
+	if len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date: %s", value)
+		}
+		return t, true, nil
+	}
+	if v, ok := strings.CutSuffix(value, "Z"); ok {
+		t, err := time.Parse("20060102T150405", v)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date: %s", value)
+		}
+		return t.In(loc), false, nil
+	}
+	tz := loc
+	for _, p := range strings.Split(params, ";") {
+		if name, ok := strings.CutPrefix(p, "TZID="); ok {
+			if l, err := time.LoadLocation(name); err == nil {
+				tz = l
+			}
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, tz)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date: %s", value)
+	}
+
+	return t.In(loc), false, nil
+}
+
+// 75 オクテットごとに折り返された行を連結する
+func unfoldLines(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+var icsEscaper = strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+func unescape(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+func cell(r []interface{}, i int) string {
+	if i >= len(r) {
+		return ""
+	}
+
+	return strings.TrimSpace(fmt.Sprint(r[i]))
+}
@@ -0,0 +1,161 @@
+package school
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
+func date(month time.Month, day int) time.Time {
+	return time.Date(2025, month, day, 0, 0, 0, 0, jst)
+}
+
+func TestCalendarsUnmarshalText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Calendars
+		wantErr  bool
+	}{
+		{
+			name:     "正常系/ICS とシート",
+			input:    `[{"child": "はな", "ics_url": "https://example.com/school.ics"}, {"child": "そら", "sheet_tab": "daycare_sora"}]`,
+			expected: Calendars{{Child: "はな", ICSURL: "https://example.com/school.ics"}, {Child: "そら", SheetTab: "daycare_sora"}},
+		},
+		{name: "異常系/子どもの名前がない", input: `[{"sheet_tab": "school"}]`, wantErr: true},
+		{name: "異常系/読み込み先がない", input: `[{"child": "はな"}]`, wantErr: true},
+		{name: "異常系/JSON でない", input: `はな`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			var c Calendars
+			err := c.UnmarshalText([]byte(tt.input))
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, c)
+		})
+	}
+}
+
+func TestParseRows(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	entries, err := ParseRows("そら", [][]interface{}{
+		{"日付", "行事", "持ち物"},
+		{"2025/4/25", "遠足", "弁当、水筒, レジャーシート"},
+		{},
+		{"2025-05-10", "運動会"},
+	}, jst)
+	tr.NoError(err)
+	ta.Equal([]Entry{
+		{Child: "そら", Name: "遠足", Date: date(4, 25), EndDate: date(4, 25), Items: []string{"弁当", "水筒", "レジャーシート"}},
+		{Child: "そら", Name: "運動会", Date: date(5, 10), EndDate: date(5, 10)},
+	}, entries)
+
+	_, err = ParseRows("そら", [][]interface{}{{"日付", "行事"}, {"4/25", "遠足"}}, jst)
+	ta.Error(err)
+	_, err = ParseRows("そら", [][]interface{}{{"日付", "行事"}, {"2025/4/25", ""}}, jst)
+	ta.Error(err)
+}
+
+func TestParseICS(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	ics := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20250425\r\n" +
+		"DTEND;VALUE=DATE:20250426\r\n" +
+		"SUMMARY:遠足\r\n" +
+		"DESCRIPTION:雨天の場合は 4/28\\n持ち物: 弁当、水筒\\, \r\n" +
+		" レジャーシート\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20250521\r\n" +
+		"DTEND;VALUE=DATE:20250524\r\n" +
+		"SUMMARY:林間学校\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART:20250610T003000Z\r\n" +
+		"DTEND:20250610T030000Z\r\n" +
+		"SUMMARY:授業参観\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;TZID=Asia/Tokyo:20250701T140000\r\n" +
+		"SUMMARY:個人面談\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	entries, err := ParseICS("はな", []byte(ics), jst)
+	tr.NoError(err)
+	ta.Equal([]Entry{
+		{Child: "はな", Name: "遠足", Date: date(4, 25), EndDate: date(4, 25), Items: []string{"弁当", "水筒", "レジャーシート"}},
+		{Child: "はな", Name: "林間学校", Date: date(5, 21), EndDate: date(5, 23)},
+		{Child: "はな", Name: "授業参観", Date: date(6, 10), EndDate: date(6, 10), Time: "09:30"},
+		{Child: "はな", Name: "個人面談", Date: date(7, 1), EndDate: date(7, 1), Time: "14:00"},
+	}, entries)
+
+	_, err = ParseICS("はな", []byte("BEGIN:VEVENT\r\nSUMMARY:遠足\r\nEND:VEVENT\r\n"), jst)
+	ta.Error(err)
+}
+
+func TestSourceFetch(t *testing.T) {
+	entries := []Entry{
+		{Child: "はな", Name: "遠足", Date: date(4, 25), EndDate: date(4, 25), Items: []string{"弁当", "水筒"}},
+		{Child: "はな", Name: "林間学校", Date: date(5, 21), EndDate: date(5, 23)},
+		{Child: "はな", Name: "授業参観", Date: date(6, 10), EndDate: date(6, 10), Time: "09:30"},
+	}
+	s := NewSource(entries)
+
+	t.Run("正常系/持ち物がある行事は前日にも返す", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		events, err := s.Fetch(context.Background(), date(4, 24))
+		tr.NoError(err)
+		tr.Len(events, 1)
+		ta.Equal("はな: 遠足", events[0].Name)
+		ta.Equal(1, events[0].LeadDays)
+		ta.Equal("持ち物: 弁当、水筒", events[0].Description)
+		ta.Equal([]string{"school", "はな"}, events[0].Tags)
+
+		events, err = s.Fetch(context.Background(), date(4, 25))
+		tr.NoError(err)
+		tr.Len(events, 1)
+		ta.Equal(0, events[0].LeadDays)
+	})
+
+	t.Run("正常系/複数日にわたる行事は期間中に返す", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		events, err := s.Fetch(context.Background(), date(5, 22))
+		tr.NoError(err)
+		tr.Len(events, 1)
+		ta.Equal("5/21〜5/23", events[0].Description)
+
+		// 持ち物がない行事は前日に返さない
+		events, err = s.Fetch(context.Background(), date(5, 20))
+		tr.NoError(err)
+		ta.Empty(events)
+	})
+
+	t.Run("正常系/時刻がある行事", func(t *testing.T) {
+		events, err := s.Fetch(context.Background(), date(6, 10))
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "09:30〜", events[0].Description)
+	})
+}
@@ -0,0 +1,63 @@
+package school
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// イベントに付けるタグ、子ども用のチャンネルに投稿する場合はプロファイルの tags に指定する
+// 子どもの名前もタグとして付ける
+const Tag = "school"
+
+// 年間行事予定の行事を返す取得元
+// 持ち物がある行事は、準備できるように前日にも返す
+type Source struct {
+	entries []Entry
+}
+
+func NewSource(entries []Entry) *Source {
+	return &Source{entries: entries}
+}
+
+func (s *Source) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	var events []event.Event
+	tomorrow := t.AddDate(0, 0, 1)
+	for _, e := range s.entries {
+		switch {
+		case e.On(t):
+			events = append(events, e.event(0))
+		case e.Date.Equal(tomorrow) && len(e.Items) > 0:
+			events = append(events, e.event(1))
+		}
+	}
+
+	return events, nil
+}
+
+func (e Entry) event(leadDays int) event.Event {
+	var lines []string
+	if e.Time != "" {
+		lines = append(lines, e.Time+"〜")
+	}
+	if !e.EndDate.Equal(e.Date) {
+		lines = append(lines, fmt.Sprintf("%s〜%s", e.Date.Format("1/2"), e.EndDate.Format("1/2")))
+	}
+	if len(e.Items) > 0 {
+		lines = append(lines, "持ち物: "+strings.Join(e.Items, "、"))
+	}
+
+	return event.Event{
+		Name:        fmt.Sprintf("%s: %s", e.Child, e.Name),
+		Interval:    event.Onetime,
+		StartDate:   e.Date,
+		EndDate:     e.EndDate,
+		Emoji:       "🎒",
+		Description: strings.Join(lines, "\n"),
+		LeadDays:    leadDays,
+		Tags:        []string{Tag, strings.ToLower(e.Child)},
+	}
+}
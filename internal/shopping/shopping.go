@@ -0,0 +1,126 @@
+// Package shopping は家族で共有する買い物リストを提供する
+// Discord のコマンドで追加・完了し、remind の投稿で未購入の品目を知らせる
+package shopping
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+)
+
+const partitionKey = "shopping"
+
+// ソートキーを追加した日時にして、追加した順に並べる
+const sortKeyFormat = "20060102T150405.000000000"
+
+// 未購入の品目
+type Item struct {
+	Key     string // ソートキー
+	Name    string
+	AddedBy string
+	AddedAt time.Time
+}
+
+// 買い物リストを DynamoDB に保存する
+// テーブルのキーは pk (パーティションキー) と sk (ソートキー、追加した日時) とし、購入済みの品目は削除する
+// キーの形式が同じため、remind の対応状況を記録するテーブルと共有できる
+type Store struct {
+	client *dynamodb.Client
+	table  string
+}
+
+func NewStore(client *dynamodb.Client, table string) *Store {
+	return &Store{client: client, table: table}
+}
+
+// 品目を追加する、同じ名前の品目が未購入のまま残っている場合は追加せずに false を返す
+func (s *Store) Add(ctx context.Context, name, user string, now time.Time) (bool, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return false, fmt.Errorf("item name is blank")
+	}
+	items, err := s.List(ctx)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := Find(items, name); ok {
+		return false, nil
+	}
+
+	item := dynamodb.Item{
+		"pk":       dynamodb.S(partitionKey),
+		"sk":       dynamodb.S(now.UTC().Format(sortKeyFormat)),
+		"name":     dynamodb.S(name),
+		"added_by": dynamodb.S(user),
+	}
+	if err := s.client.PutItem(ctx, s.table, item, "attribute_not_exists(pk)", nil); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// 未購入の品目を追加した順に返す
+func (s *Store) List(ctx context.Context) ([]Item, error) {
+	items, err := s.client.Query(ctx, s.table, "pk = :pk", dynamodb.Item{":pk": dynamodb.S(partitionKey)})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseItems(items), nil
+}
+
+// 品目を購入済みにする
+func (s *Store) Done(ctx context.Context, item Item) error {
+	return s.client.DeleteItem(ctx, s.table, dynamodb.Item{
+		"pk": dynamodb.S(partitionKey),
+		"sk": dynamodb.S(item.Key),
+	})
+}
+
+func parseItems(items []dynamodb.Item) []Item {
+	var list []Item
+	for _, v := range items {
+		added, _ := time.Parse(sortKeyFormat, v.Str("sk"))
+		list = append(list, Item{
+			Key:     v.Str("sk"),
+			Name:    v.Str("name"),
+			AddedBy: v.Str("added_by"),
+			AddedAt: added,
+		})
+	}
+
+	return list
+}
+
+// 一覧の番号 (1 始まり) もしくは名前で品目を探す、名前は大文字と小文字を区別しない
+func Find(items []Item, target string) (Item, bool) {
+	target = strings.TrimSpace(target)
+	if n, err := strconv.Atoi(target); err == nil {
+		if n < 1 || n > len(items) {
+			return Item{}, false
+		}
+		return items[n-1], true
+	}
+	for _, item := range items {
+		if strings.EqualFold(item.Name, target) {
+			return item, true
+		}
+	}
+
+	return Item{}, false
+}
+
+// 番号付きの一覧を返す、e.g. "1. 牛乳\n2. 卵"
+func Format(items []Item) string {
+	lines := make([]string, 0, len(items))
+	for i, item := range items {
+		lines = append(lines, fmt.Sprintf("%d. %s", i+1, item.Name))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,112 @@
+package shopping
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseItems(t *testing.T) {
+	ta := assert.New(t)
+
+	items := parseItems([]dynamodb.Item{
+		{"pk": dynamodb.S("shopping"), "sk": dynamodb.S("20250301T090000.000000000"), "name": dynamodb.S("牛乳"), "added_by": dynamodb.S("alice")},
+		{"pk": dynamodb.S("shopping"), "sk": dynamodb.S("invalid"), "name": dynamodb.S("卵")},
+	})
+
+	ta.Equal([]Item{
+		{Key: "20250301T090000.000000000", Name: "牛乳", AddedBy: "alice", AddedAt: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)},
+		{Key: "invalid", Name: "卵"},
+	}, items)
+}
+
+func TestFind(t *testing.T) {
+	items := []Item{{Key: "1", Name: "牛乳"}, {Key: "2", Name: "Bread"}}
+
+	tests := []struct {
+		name     string
+		target   string
+		expected Item
+		found    bool
+	}{
+		{name: "正常系/番号で指定した場合", target: "2", expected: items[1], found: true},
+		{name: "正常系/名前で指定した場合", target: " 牛乳 ", expected: items[0], found: true},
+		{name: "正常系/大文字と小文字を区別しない場合", target: "bread", expected: items[1], found: true},
+		{name: "異常系/番号が範囲外の場合", target: "3"},
+		{name: "異常系/番号が 0 の場合", target: "0"},
+		{name: "異常系/名前が一致しない場合", target: "卵"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			item, ok := Find(items, tt.target)
+			ta.Equal(tt.found, ok)
+			ta.Equal(tt.expected, item)
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.Equal("1. 牛乳\n2. 卵", Format([]Item{{Name: "牛乳"}, {Name: "卵"}}))
+	ta.Empty(Format(nil))
+}
+
+type fakeLister struct {
+	items []Item
+	err   error
+}
+
+func (l fakeLister) List(ctx context.Context) ([]Item, error) {
+	return l.items, l.err
+}
+
+func TestSource(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	saturday := time.Date(2025, 3, 1, 0, 0, 0, 0, tz)
+	items := []Item{{Name: "牛乳"}, {Name: "卵"}}
+
+	t.Run("正常系/指定した曜日は買い物リストを返す", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		events, err := NewSource(fakeLister{items: items}, []time.Weekday{time.Saturday}).Fetch(context.Background(), saturday)
+		tr.NoError(err)
+		tr.Len(events, 1)
+		ta.Equal("買い物リスト (2 件)", events[0].Name)
+		ta.Equal("1. 牛乳\n2. 卵", events[0].Description)
+		ta.Equal([]string{Tag}, events[0].Tags)
+	})
+
+	t.Run("正常系/指定した曜日以外は返さない", func(t *testing.T) {
+		ta := assert.New(t)
+
+		events, err := NewSource(fakeLister{items: items}, []time.Weekday{time.Saturday}).Fetch(context.Background(), saturday.AddDate(0, 0, 1))
+		ta.NoError(err)
+		ta.Empty(events)
+	})
+
+	t.Run("正常系/未購入の品目がない場合は返さない", func(t *testing.T) {
+		ta := assert.New(t)
+
+		events, err := NewSource(fakeLister{}, []time.Weekday{time.Saturday}).Fetch(context.Background(), saturday)
+		ta.NoError(err)
+		ta.Empty(events)
+	})
+
+	t.Run("異常系/取得に失敗した場合", func(t *testing.T) {
+		ta := assert.New(t)
+
+		_, err := NewSource(fakeLister{err: errors.New("throttled")}, []time.Weekday{time.Saturday}).Fetch(context.Background(), saturday)
+		ta.Equal(Tag, event.FailedComponent(err))
+	})
+}
@@ -0,0 +1,49 @@
+package shopping
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// イベントに付けるタグ、朝のスケジュールのみで通知する場合はプロファイルの tags に指定する
+const Tag = "shopping"
+
+// 買い物リストを取得する
+type Lister interface {
+	List(ctx context.Context) ([]Item, error)
+}
+
+// 指定した曜日に、未購入の品目があれば買い物リストをイベントとして返す取得元
+type Source struct {
+	lister   Lister
+	weekdays []time.Weekday
+}
+
+func NewSource(l Lister, weekdays []time.Weekday) *Source {
+	return &Source{lister: l, weekdays: weekdays}
+}
+
+func (s *Source) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	if !slices.Contains(s.weekdays, t.Weekday()) {
+		return nil, nil
+	}
+	items, err := s.lister.List(ctx)
+	if err != nil {
+		return nil, event.NewSourceUnavailableError(Tag, err)
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	return []event.Event{{
+		Name:        fmt.Sprintf("買い物リスト (%d 件)", len(items)),
+		Interval:    event.Weekly,
+		Emoji:       "🛒",
+		Description: Format(items),
+		Tags:        []string{Tag},
+	}}, nil
+}
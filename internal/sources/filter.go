@@ -0,0 +1,82 @@
+package sources
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// 指定したタグのいずれかを持つイベントのみを返すデータソース
+type TagFilterSource struct {
+	source Source
+	tags   []string
+}
+
+func NewTagFilterSource(source Source, tags []string) *TagFilterSource {
+	return &TagFilterSource{source: source, tags: normalizeTags(tags)}
+}
+
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	for _, t := range tags {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(t)))
+	}
+
+	return normalized
+}
+
+func (s *TagFilterSource) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	events, err := s.source.Fetch(ctx, t)
+	if events == nil && err != nil {
+		return nil, err
+	}
+
+	filtered := []event.Event{}
+	for _, e := range events {
+		if s.match(e) {
+			filtered = append(filtered, e)
+		}
+	}
+
+	return filtered, err
+}
+
+func (s *TagFilterSource) match(e event.Event) bool {
+	for _, t := range e.Tags {
+		if slices.Contains(s.tags, t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 指定したタグのいずれかを持つイベントを除いて返すデータソース
+// 子ども用のチャンネルなど、別のスケジュールで投稿するイベントを除く
+type TagExcludeSource struct {
+	source Source
+	tags   []string
+}
+
+func NewTagExcludeSource(source Source, tags []string) *TagExcludeSource {
+	return &TagExcludeSource{source: source, tags: normalizeTags(tags)}
+}
+
+func (s *TagExcludeSource) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	events, err := s.source.Fetch(ctx, t)
+	if events == nil && err != nil {
+		return nil, err
+	}
+
+	filtered := []event.Event{}
+	for _, e := range events {
+		if !slices.ContainsFunc(e.Tags, func(tag string) bool { return slices.Contains(s.tags, tag) }) {
+			filtered = append(filtered, e)
+		}
+	}
+
+	return filtered, err
+}
@@ -0,0 +1,151 @@
+package sources
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type MockEventSource struct {
+	MockEvents []event.Event
+	MockError  error
+}
+
+func (m *MockEventSource) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	return m.MockEvents, m.MockError
+}
+
+func TestTagFilterSource(t *testing.T) {
+	src := &MockEventSource{
+		MockEvents: []event.Event{
+			{Name: "Recycling", Tags: []string{"morning"}},
+			{Name: "Homework", Tags: []string{"evening", "kids"}},
+			{Name: "Untagged"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		tags          []string
+		expectedNames []string
+	}{
+		{
+			name:          "正常系/タグが一致するイベントのみを返す場合",
+			tags:          []string{"Evening"},
+			expectedNames: []string{"Homework"},
+		},
+		{
+			name:          "正常系/複数のタグが指定されている場合",
+			tags:          []string{"morning", "kids"},
+			expectedNames: []string{"Recycling", "Homework"},
+		},
+		{
+			name:          "正常系/一致するタグがない場合",
+			tags:          []string{"night"},
+			expectedNames: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			tr := require.New(t)
+
+			events, err := NewTagFilterSource(src, tt.tags).Fetch(context.Background(), time.Now())
+			tr.NoError(err)
+
+			names := []string{}
+			for _, e := range events {
+				names = append(names, e.Name)
+			}
+			ta.Equal(tt.expectedNames, names)
+		})
+	}
+}
+
+func TestMultiSource(t *testing.T) {
+	ta := assert.New(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	src := NewMultiSource(
+		&MockEventSource{MockEvents: []event.Event{{Name: "Garbage"}}},
+		&MockEventSource{MockEvents: []event.Event{{Name: "明日は燃えるゴミ"}}},
+	)
+	events, err := src.Fetch(ctx, now)
+	ta.NoError(err)
+	ta.Equal([]event.Event{{Name: "Garbage"}, {Name: "明日は燃えるゴミ"}}, events)
+
+	// 一部の取得元が失敗した場合は、取得できたイベントとエラーを返す
+	src = NewMultiSource(&MockEventSource{MockEvents: []event.Event{{Name: "Garbage"}}}, &MockEventSource{MockError: assert.AnError})
+	events, err = src.Fetch(ctx, now)
+	ta.ErrorIs(err, assert.AnError)
+	ta.ErrorIs(err, event.ErrSourceUnavailable)
+	ta.Equal([]event.Event{{Name: "Garbage"}}, events)
+
+	// パニックした取得元があっても、他の取得元のイベントを返す
+	src = NewMultiSource(&MockEventSource{MockEvents: []event.Event{{Name: "Garbage"}}}, panicEventSource{}, &MockEventSource{MockEvents: []event.Event{{Name: "Milk"}}})
+	events, err = src.Fetch(ctx, now)
+	ta.ErrorContains(err, "sources: source unavailable: recovered from panic: source is broken")
+	ta.Equal([]event.Event{{Name: "Garbage"}, {Name: "Milk"}}, events)
+
+	// 全ての取得元が失敗した場合
+	src = NewMultiSource(&MockEventSource{MockError: assert.AnError}, panicEventSource{})
+	events, err = src.Fetch(ctx, now)
+	ta.ErrorIs(err, assert.AnError)
+	ta.Nil(events)
+}
+
+type panicEventSource struct{}
+
+func (panicEventSource) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	panic("source is broken")
+}
+
+// 一部の取得元が失敗しても、絞り込みと並べ替えは取得できたイベントに対して行う
+func TestMultiSourceThroughFilters(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	var src Source = NewMultiSource(
+		&MockEventSource{MockEvents: []event.Event{{Name: "Piano", Tags: []string{"evening"}}, {Name: "Recycling", Tags: []string{"morning"}}}},
+		panicEventSource{},
+		&MockEventSource{MockEvents: []event.Event{{Name: "Garbage", Tags: []string{"evening"}}}},
+	)
+	src = NewTagFilterSource(src, []string{"evening"})
+	src = NewTagExcludeSource(src, []string{"school"})
+	src, err := NewSortedSource(src, []string{"name"}, "")
+	tr.NoError(err)
+
+	events, err := src.Fetch(context.Background(), time.Now())
+	ta.ErrorContains(err, "recovered from panic")
+	tr.Len(events, 2)
+	ta.Equal("Garbage", events[0].Name)
+	ta.Equal("Piano", events[1].Name)
+}
+
+func TestTagExcludeSource(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	src := &MockEventSource{
+		MockEvents: []event.Event{
+			{Name: "Recycling", Tags: []string{"morning"}},
+			{Name: "Field trip", Tags: []string{"school", "hana"}},
+			{Name: "Untagged"},
+		},
+	}
+
+	events, err := NewTagExcludeSource(src, []string{"School"}).Fetch(context.Background(), time.Now())
+	tr.NoError(err)
+
+	var names []string
+	for _, e := range events {
+		names = append(names, e.Name)
+	}
+	ta.Equal([]string{"Recycling", "Untagged"}, names)
+}
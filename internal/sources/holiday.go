@@ -0,0 +1,40 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/logging"
+)
+
+const HolidaysSourceName = "holidays"
+
+// 祝日の一覧を取得する
+// レスポンスは {"2025-01-01": "元日", ...} の形式を想定する (https://holidays-jp.github.io/)
+func FetchHolidays(ctx context.Context, client *http.Client, url string) (event.Holidays, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, event.NewSourceUnavailableError(HolidaysSourceName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, event.NewSourceUnavailableError(HolidaysSourceName, fmt.Errorf("returned status %d", resp.StatusCode))
+	}
+
+	var h event.Holidays
+	if err := json.NewDecoder(resp.Body).Decode(&h); err != nil {
+		return nil, event.NewParseError(HolidaysSourceName, "", err)
+	}
+	logging.DebugPayload(ctx, "fetched holidays", h)
+
+	return h, nil
+}
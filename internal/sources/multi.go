@@ -0,0 +1,67 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// 複数のデータソースのイベントを、指定した順に連結して返すデータソース
+// 一部の取得に失敗した場合は、取得できたイベントと失敗した取得元のエラーを返す
+// 全ての取得に失敗した場合のみ、その日付の取得を失敗として扱う
+type MultiSource struct {
+	sources []Source
+}
+
+func NewMultiSource(sources ...Source) *MultiSource {
+	return &MultiSource{sources: sources}
+}
+
+func (s *MultiSource) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	events := []event.Event{}
+	var errs []error
+	for _, src := range s.sources {
+		e, err := fetchSafely(ctx, src, t)
+		if err != nil {
+			slog.Warn("failed to get events from source", slog.String("component", event.FailedComponent(err)), slog.Any("error", err))
+			errs = append(errs, err)
+			continue
+		}
+		events = append(events, e...)
+	}
+	if len(errs) == len(s.sources) && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return events, errors.Join(errs...)
+}
+
+// 1 つの取得元のパニックで他の取得元のイベントを失わないように、エラーとして扱う
+// 失敗した取得元が分かるように、取得元の名前を付けたエラーを返す
+func fetchSafely(ctx context.Context, src Source, t time.Time) (events []event.Event, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			slog.Error("recovered from panic", slog.String("component", sourceName(src)), slog.Any("panic", v), slog.String("stack", string(debug.Stack())))
+			events, err = nil, fmt.Errorf("recovered from panic: %v", v)
+		}
+		var pe *event.PipelineError
+		if err != nil && !errors.As(err, &pe) {
+			err = event.NewSourceUnavailableError(sourceName(src), err)
+		}
+	}()
+
+	return src.Fetch(ctx, t)
+}
+
+// 取得元のパッケージ名を名前として使う、e.g. *shopping.Source -> shopping
+func sourceName(src Source) string {
+	name, _, _ := strings.Cut(strings.TrimPrefix(fmt.Sprintf("%T", src), "*"), ".")
+
+	return name
+}
@@ -0,0 +1,573 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"github.com/mami0tsu/homeops/internal/logging"
+	"github.com/mami0tsu/homeops/internal/metrics"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+const (
+	nameIdx      = 0
+	intervalIdx  = 1
+	startDateIdx = 2
+	endDateIdx   = 3
+	emojiIdx     = 4
+	colorIdx     = 5
+	urlIdx       = 6
+	descIdx      = 7
+	modifiersIdx = 8
+	notifyIdx    = 9
+	timeIdx      = 10
+	countIdx     = 11
+	exceptIdx    = 12
+	doneIdx      = 13
+	tagsIdx      = 14
+	priorityIdx  = 15
+	rowIdx       = 16 // 元のシートの行番号、別のシートから読み込む場合に指定する
+)
+
+// エラーで失敗した列を示すための名前
+var columnNames = map[int]string{
+	nameIdx:      "name",
+	intervalIdx:  "interval",
+	startDateIdx: "start date",
+	endDateIdx:   "end date",
+	emojiIdx:     "emoji",
+	colorIdx:     "color",
+	urlIdx:       "url",
+	descIdx:      "description",
+	modifiersIdx: "modifiers",
+	notifyIdx:    "notify before",
+	timeIdx:      "time",
+	countIdx:     "count",
+	exceptIdx:    "exceptions",
+	doneIdx:      "done",
+	tagsIdx:      "tags",
+	priorityIdx:  "priority",
+	rowIdx:       "row",
+}
+
+const (
+	sheetSourceName = "sheet"
+	defaultSheetTab = "remind"
+	lastColumn      = "Q"
+)
+
+// 読み込むシートと範囲の設定
+type SheetOptions struct {
+	// 読み込むシート、未指定の場合は remind
+	// e.g. 終了日が過ぎていない行のみを FILTER 関数で抽出した active シート
+	// remind 以外のシートから読み込む場合は、完了のチェックボックスを更新できるように Q 列に元の行番号を含める
+	Tab string
+	// 1 回に読み込む行数、行数の多いシートでメモリの使用量を抑える場合に指定する
+	// 0 の場合はシート全体を 1 度に読み込む
+	ChunkRows int
+}
+
+func (o SheetOptions) tab() string {
+	if o.Tab == "" {
+		return defaultSheetTab
+	}
+
+	return o.Tab
+}
+
+type SheetDataReader interface {
+	GetValues(ctx context.Context, spreadsheetID, readRange string) (*sheets.ValueRange, error)
+}
+
+func NewSheetsService(ctx context.Context, credentials []byte) (*sheets.Service, error) {
+	cfg, err := google.JWTConfigFromJSON(credentials, sheets.SpreadsheetsReadonlyScope)
+	if err != nil {
+		return nil, err
+	}
+	c := cfg.Client(context.WithValue(ctx, oauth2.HTTPClient, httpclient.Default))
+	srv, err := sheets.NewService(ctx, option.WithHTTPClient(c))
+	if err != nil {
+		return nil, err
+	}
+	return srv, nil
+}
+
+type GoogleSheetReader struct {
+	Service *sheets.Service
+}
+
+// クォータの超過や一時的なエラーで失敗した場合は、間隔を空けて再試行する
+func (gsr *GoogleSheetReader) GetValues(ctx context.Context, spreadsheetID, readRange string) (*sheets.ValueRange, error) {
+	return retrySheets(ctx, sleepContext, func() (*sheets.ValueRange, error) {
+		return gsr.Service.Spreadsheets.Values.Get(spreadsheetID, readRange).Context(ctx).Do()
+	})
+}
+
+const (
+	sheetsMaxAttempts  = 4
+	sheetsBaseBackoff  = time.Second
+	sheetsQuotaBackoff = 5 * time.Second // クォータは 1 分ごとに回復するため、429 の場合は長めに待つ
+	sheetsMaxBackoff   = 30 * time.Second
+)
+
+// 429、500 で失敗した場合に、指数的に間隔を空けて sheetsMaxAttempts 回まで試行する
+// 502、503、504 は httpclient のトランスポートが再試行するため、重ねて再試行しない
+// ctx の期限までに再試行できない場合は、待たずにエラーを返す
+func retrySheets(ctx context.Context, wait func(context.Context, time.Duration) error, fn func() (*sheets.ValueRange, error)) (*sheets.ValueRange, error) {
+	for attempt := 0; ; attempt++ {
+		v, err := fn()
+		if err == nil {
+			return v, nil
+		}
+		d, ok := sheetsBackoff(err, attempt)
+		if !ok || attempt+1 >= sheetsMaxAttempts {
+			return nil, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= d {
+			return nil, err
+		}
+		slog.Warn("retrying Sheets API request", slog.Int("attempt", attempt+1), slog.Duration("backoff", d), slog.Any("error", err))
+		if err := wait(ctx, d); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// 再試行までの時間を返す、再試行しないエラーの場合は false を返す
+// Retry-After ヘッダーがあればその値を優先する
+func sheetsBackoff(err error, attempt int) (time.Duration, bool) {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return 0, false
+	}
+
+	base := sheetsBaseBackoff
+	switch apiErr.Code {
+	case http.StatusTooManyRequests:
+		base = sheetsQuotaBackoff
+	case http.StatusInternalServerError:
+	default:
+		return 0, false
+	}
+	if s, err := strconv.Atoi(apiErr.Header.Get("Retry-After")); err == nil && s > 0 {
+		return time.Duration(s) * time.Second, true
+	}
+
+	d := min(base<<attempt, sheetsMaxBackoff)
+
+	return d/2 + rand.N(d/2+1), true
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+type SheetSource struct {
+	reader        SheetDataReader
+	spreadsheetID string
+	holidays      event.Holidays
+	opts          SheetOptions
+}
+
+// スプレッドシート用のデータソース
+func NewSheetSource(reader SheetDataReader, spreadsheetID string, holidays event.Holidays, opts SheetOptions) *SheetSource {
+	return &SheetSource{
+		reader:        reader,
+		spreadsheetID: spreadsheetID,
+		holidays:      holidays,
+		opts:          opts,
+	}
+}
+
+// スプレッドシートからデータを取得した上でパースして返却する
+func (s *SheetSource) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	events, err := s.FetchDates(ctx, []time.Time{t})
+	if err != nil {
+		return nil, err
+	}
+
+	return events[0], nil
+}
+
+// スプレッドシートを一度だけ読み込み、dates のそれぞれの日付に通知するイベントを dates と同じ順で返す
+func (s *SheetSource) FetchDates(ctx context.Context, dates []time.Time) ([][]event.Event, error) {
+	m := metrics.FromContext(ctx)
+	events := make([][]event.Event, len(dates))
+	for i := range events {
+		events[i] = []event.Event{}
+	}
+	fetched := 0
+	err := s.readRows(ctx, func(row int, r []interface{}) {
+		parsed, err := s.parseRow(r)
+		if err != nil {
+			// パースできない行はスキップする
+			slog.Warn("skipped invalid row", slog.Any("error", event.NewParseError(sheetSourceName, fmt.Sprintf("row %d", row), err)))
+			return
+		}
+		parsed.Row = s.sourceRow(r, row)
+		// 完了済みの単発のイベントは通知しない
+		if parsed.Interval == event.Onetime && parsed.Done {
+			return
+		}
+		for i, t := range dates {
+			e := parsed
+			if e.HasDeadline(t) {
+				e.DaysLeft = event.DaysBetween(t, e.EndDate)
+			}
+			if e.IsScheduled(t, s.holidays) {
+				events[i] = append(events[i], e)
+				fetched++
+			}
+			// N 日後に発生するイベントも事前に通知する
+			if e.NotifyBefore > 0 && e.IsScheduled(t.AddDate(0, 0, e.NotifyBefore), s.holidays) {
+				e.LeadDays = e.NotifyBefore
+				events[i] = append(events[i], e)
+				fetched++
+			}
+		}
+	})
+	if err != nil {
+		m.Add("SourceErrors", 1, metrics.Count, "Source", sheetSourceName)
+		return nil, event.NewSourceUnavailableError(sheetSourceName, err)
+	}
+	m.Add("SourceErrors", 0, metrics.Count, "Source", sheetSourceName)
+	m.Add("EventsFetched", float64(fetched), metrics.Count, "Source", sheetSourceName)
+
+	return events, nil
+}
+
+// ヘッダーを除いた各行を、シート上の行番号とともに fn に渡す
+// ChunkRows を指定した場合は指定した行数ずつ読み込み、読み込んだ値は次の読み込みの前に破棄する
+// 末尾の空行は返されず、途中の範囲でも ChunkRows に満たない場合があるため、値が返されなくなった場合に最後の行まで読み込んだとみなす
+func (s *SheetSource) readRows(ctx context.Context, fn func(row int, r []interface{})) error {
+	tab := s.opts.tab()
+	if s.opts.ChunkRows <= 0 {
+		resp, err := s.reader.GetValues(ctx, s.spreadsheetID, fmt.Sprintf("%s!A:%s", tab, lastColumn))
+		if err != nil {
+			return err
+		}
+		logging.DebugPayload(ctx, "fetched sheet values", resp.Values)
+		// ヘッダーの次の行が 2 行目になる
+		for i := 1; i < len(resp.Values); i++ {
+			fn(i+1, resp.Values[i])
+		}
+		return nil
+	}
+
+	for start := 2; ; start += s.opts.ChunkRows {
+		readRange := fmt.Sprintf("%s!A%d:%s%d", tab, start, lastColumn, start+s.opts.ChunkRows-1)
+		resp, err := s.reader.GetValues(ctx, s.spreadsheetID, readRange)
+		if err != nil {
+			return err
+		}
+		logging.DebugPayload(ctx, "fetched sheet values", map[string]any{"range": readRange, "rows": len(resp.Values)})
+		for i, r := range resp.Values {
+			fn(start+i, r)
+		}
+		if len(resp.Values) == 0 {
+			return nil
+		}
+	}
+}
+
+// 完了のチェックボックスを更新する remind シートの行番号を返す
+// 別のシートから読み込んだ場合は Q 列の行番号を使い、指定されていなければ 0 を返す
+func (s *SheetSource) sourceRow(r []interface{}, row int) int {
+	if n, err := s.parseNumber(r, rowIdx); err == nil && n > 0 {
+		return n
+	}
+	if s.opts.tab() != defaultSheetTab {
+		return 0
+	}
+
+	return row
+}
+
+func (s *SheetSource) parseRow(r []interface{}) (event.Event, error) {
+	name, err := s.parseName(r, nameIdx)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	interval, option, err := s.ParseInterval(r, intervalIdx)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	startDate, err := s.parseDate(r, startDateIdx)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	endDate, err := s.parseDate(r, endDateIdx)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	color, err := s.parseColor(r, colorIdx)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	modifiers, err := event.ParseModifiers(s.parseOptional(r, modifiersIdx))
+	if err != nil {
+		return event.Event{}, fmt.Errorf("%s: %w", columnNames[modifiersIdx], err)
+	}
+
+	notifyBefore, err := s.parseNumber(r, notifyIdx)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	tod, err := s.parseTime(r, timeIdx)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	count, err := s.parseNumber(r, countIdx)
+	if err != nil {
+		return event.Event{}, err
+	}
+	// 回数は開始日から数えるため、開始日の指定を必須にする
+	if count > 0 && s.parseOptional(r, startDateIdx) == "" {
+		return event.Event{}, fmt.Errorf("start date is required when count is specified")
+	}
+
+	exceptions, err := s.parseExceptions(r, exceptIdx)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	done, err := s.parseCheckbox(r, doneIdx)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	priority, err := s.parseNumber(r, priorityIdx)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	e := event.Event{
+		Name:         name,
+		Interval:     interval,
+		StartDate:    startDate,
+		EndDate:      endDate,
+		Emoji:        s.parseOptional(r, emojiIdx),
+		Color:        color,
+		URL:          s.parseOptional(r, urlIdx),
+		Description:  s.parseOptional(r, descIdx),
+		Modifiers:    modifiers,
+		Count:        count,
+		Exceptions:   exceptions,
+		Time:         tod,
+		NotifyBefore: notifyBefore,
+		Done:         done,
+		Tags:         s.parseTags(r, tagsIdx),
+		Priority:     priority,
+	}
+	if err := e.ApplyIntervalOption(option); err != nil {
+		return event.Event{}, err
+	}
+	// 営業日は祝日を除いて数える
+	if e.Relative != nil {
+		e.Relative.Holidays = s.holidays
+	}
+
+	return e, nil
+}
+
+func (s *SheetSource) parseName(r []interface{}, index int) (string, error) {
+	if len(r) <= index || fmt.Sprintf("%v", r[index]) == "" {
+		return "", columnError(index, "")
+	}
+
+	return fmt.Sprintf("%v", r[index]), nil
+}
+
+// 間隔とそのオプションを返す
+func (s *SheetSource) ParseInterval(r []interface{}, index int) (event.Interval, string, error) {
+	if len(r) <= index || fmt.Sprintf("%v", r[index]) == "" {
+		return -1, "", columnError(index, "")
+	}
+
+	v, option := event.SplitIntervalSpec(fmt.Sprintf("%v", r[index]))
+	interval, err := event.ParseInterval(v)
+	if err != nil {
+		return -1, "", fmt.Errorf("%s: %w", columnNames[index], err)
+	}
+
+	return interval, option, nil
+}
+
+// 任意の列は値が存在しなければ空文字を返す
+func (s *SheetSource) parseOptional(r []interface{}, index int) string {
+	if len(r) <= index {
+		return ""
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%v", r[index]))
+}
+
+// 色は "#3fb950" もしくは "3fb950" の形式で指定する
+func (s *SheetSource) parseColor(r []interface{}, index int) (int, error) {
+	v := strings.TrimPrefix(s.parseOptional(r, index), "#")
+	if v == "" {
+		return 0, nil
+	}
+
+	c, err := strconv.ParseInt(v, 16, 32)
+	if err != nil || len(v) != 6 {
+		return 0, columnError(index, v)
+	}
+
+	return int(c), nil
+}
+
+// 日数や回数は 0 以上の整数で指定する、未指定の場合は 0 とする
+func (s *SheetSource) parseNumber(r []interface{}, index int) (int, error) {
+	v := s.parseOptional(r, index)
+	if v == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, columnError(index, v)
+	}
+
+	return n, nil
+}
+
+// タグは "morning,evening" のようにカンマ区切りで指定する
+func (s *SheetSource) parseTags(r []interface{}, index int) []string {
+	v := s.parseOptional(r, index)
+	if v == "" {
+		return nil
+	}
+
+	parts := strings.Split(v, ",")
+	tags := make([]string, 0, len(parts))
+	for _, t := range parts {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	return tags
+}
+
+// チェックボックスの値は "TRUE" もしくは "FALSE" で返される、未指定の場合は false とする
+func (s *SheetSource) parseCheckbox(r []interface{}, index int) (bool, error) {
+	v := s.parseOptional(r, index)
+	if v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, columnError(index, v)
+	}
+
+	return b, nil
+}
+
+// 時刻は "20:00" の形式で指定する、未指定の場合は nil を返す
+func (s *SheetSource) parseTime(r []interface{}, index int) (*event.TimeOfDay, error) {
+	v := s.parseOptional(r, index)
+	if v == "" {
+		return nil, nil
+	}
+
+	t, err := event.ParseTimeOfDay(v)
+	if err != nil {
+		return nil, columnError(index, v)
+	}
+
+	return &t, nil
+}
+
+// 除外する日付は "2025/03/20,2025/03/25-2025/04/05" のようにカンマ区切りで指定する
+// "-" でつないだ場合は期間として扱う
+func (s *SheetSource) parseExceptions(r []interface{}, index int) ([]event.DateRange, error) {
+	v := s.parseOptional(r, index)
+	if v == "" {
+		return nil, nil
+	}
+
+	tz := time.FixedZone("JST", 9*60*60)
+	parts := strings.Split(v, ",")
+	ranges := make([]event.DateRange, 0, len(parts))
+	for _, p := range parts {
+		from, to, isRange := strings.Cut(strings.TrimSpace(p), "-")
+		if !isRange {
+			to = from
+		}
+		start, err := time.ParseInLocation("2006/01/02", strings.TrimSpace(from), tz)
+		if err != nil {
+			return nil, columnError(index, p)
+		}
+		end, err := time.ParseInLocation("2006/01/02", strings.TrimSpace(to), tz)
+		if err != nil || end.Before(start) {
+			return nil, columnError(index, p)
+		}
+		ranges = append(ranges, event.DateRange{Start: start, End: end})
+	}
+
+	return ranges, nil
+}
+
+func (s *SheetSource) parseDate(r []interface{}, index int) (time.Time, error) {
+	tz := time.FixedZone("JST", 9*60*60)
+
+	if len(r) <= index || fmt.Sprintf("%v", r[index]) == "" {
+		switch index {
+		case startDateIdx:
+			return time.Date(1, 1, 1, 0, 0, 0, 0, tz), nil
+		case endDateIdx:
+			return time.Date(9999, 12, 31, 0, 0, 0, 0, tz), nil
+		default:
+			return time.Time{}, columnError(index, "")
+		}
+	}
+
+	dateStr := fmt.Sprintf("%v", r[index])
+	t, err := time.ParseInLocation("2006/01/02", dateStr, tz)
+	if err != nil {
+		return time.Time{}, columnError(index, dateStr)
+	}
+
+	return t, nil
+}
+
+// 列の値を解釈できない場合のエラー、値が空の場合は列の名前のみを示す
+func columnError(index int, v string) error {
+	if v == "" {
+		return fmt.Errorf("%s is required", columnNames[index])
+	}
+
+	return fmt.Errorf("invalid %s: %s", columnNames[index], v)
+}
@@ -0,0 +1,490 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/sheets/v4"
+)
+
+type MockSheetReader struct {
+	MockResponse *sheets.ValueRange
+	MockError    error
+}
+
+func (m *MockSheetReader) GetValues(ctx context.Context, spreadsheetID string, readRange string) (*sheets.ValueRange, error) {
+	return m.MockResponse, m.MockError
+}
+
+var tz = time.FixedZone("JST", 9*60*60)
+
+var testEvents = []event.Event{
+	{Name: "Active", Interval: event.Daily, StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, tz), EndDate: time.Date(2025, 1, 30, 0, 0, 0, 0, tz)},
+	{Name: "On End", Interval: event.Daily, StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, tz), EndDate: time.Date(2025, 1, 10, 0, 0, 0, 0, tz)},
+	{Name: "On Start", Interval: event.Daily, StartDate: time.Date(2025, 1, 21, 0, 0, 0, 0, tz), EndDate: time.Date(2025, 1, 30, 0, 0, 0, 0, tz)},
+}
+
+func eventsToValueRange(events []event.Event) *sheets.ValueRange {
+	header := []string{"Name", "Interval", "StartDate", "EndDate"}
+	headerRow := make([]interface{}, len(header))
+	for i, h := range header {
+		headerRow[i] = h
+	}
+
+	values := make([][]interface{}, 0, len(events)+1)
+	values = append(values, headerRow)
+
+	for _, e := range events {
+		row := []interface{}{
+			e.Name,
+			e.Interval,
+			e.StartDate.Format("2006/01/02"),
+			e.EndDate.Format("2006/01/02"),
+		}
+		values = append(values, row)
+	}
+
+	return &sheets.ValueRange{Values: values}
+}
+
+func TestFetch(t *testing.T) {
+	mockData := eventsToValueRange(testEvents)
+
+	mockDataWithInvalidRow := eventsToValueRange(testEvents)
+	mockDataWithInvalidRow.Values = append(mockDataWithInvalidRow.Values, []interface{}{"Invalid Event", "Daily", "2025-01-01", "not-a-date"})
+
+	tests := []struct {
+		name          string
+		mockReader    *MockSheetReader
+		targetTime    time.Time
+		expectError   bool
+		expectedNames []string
+	}{
+		{
+			name: "正常系/期間内の日付が指定されている場合",
+			mockReader: &MockSheetReader{
+				MockResponse: mockData,
+			},
+			targetTime:    time.Date(2025, 1, 15, 0, 0, 0, 0, tz),
+			expectError:   false,
+			expectedNames: []string{"Active"},
+		},
+		{
+			name: "正常系/どの期間にも含まれない日付が指定されている場合",
+			mockReader: &MockSheetReader{
+				MockResponse: mockData,
+			},
+			targetTime:    time.Date(2025, 1, 31, 0, 0, 0, 0, tz),
+			expectError:   false,
+			expectedNames: []string{},
+		},
+		{
+			name: "正常系/不正な行が混在している場合",
+			mockReader: &MockSheetReader{
+				MockResponse: mockDataWithInvalidRow,
+			},
+			targetTime:    time.Date(2025, 1, 15, 0, 0, 0, 0, tz),
+			expectError:   false,
+			expectedNames: []string{"Active"},
+		},
+		{
+			name: "正常系/ヘッダー列が欠けている場合",
+			mockReader: &MockSheetReader{
+				MockResponse: &sheets.ValueRange{
+					Values: [][]interface{}{
+						{"Name", "Interval", "StartDate"}, // "EndDate" が欠けている
+					},
+				},
+			},
+			expectError:   false,
+			expectedNames: []string{},
+		},
+		{
+			name: "正常系/APIがエラーを返した場合",
+			mockReader: &MockSheetReader{
+				MockError: fmt.Errorf("API permission denied"),
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			tr := require.New(t)
+
+			src := NewSheetSource(tt.mockReader, "dummy", nil, SheetOptions{})
+			filtered, err := src.Fetch(context.Background(), tt.targetTime)
+
+			if tt.expectError {
+				tr.ErrorIs(err, event.ErrSourceUnavailable, "Expected a source unavailable error")
+				return
+			}
+			tr.NoError(err, "Did not expect an error")
+
+			var filteredNames []string
+			for _, e := range filtered {
+				filteredNames = append(filteredNames, e.Name)
+			}
+
+			ta.ElementsMatch(tt.expectedNames, filteredNames, "Filtered events should match expected names")
+		})
+	}
+}
+
+// 指定された範囲の行のみを返す
+type rangeSheetReader struct {
+	values [][]interface{} // ヘッダーを含むシート全体の値
+	ranges []string
+}
+
+func (r *rangeSheetReader) GetValues(ctx context.Context, spreadsheetID string, readRange string) (*sheets.ValueRange, error) {
+	r.ranges = append(r.ranges, readRange)
+	_, cells, _ := strings.Cut(readRange, "!")
+	var start, end int
+	if _, err := fmt.Sscanf(cells, "A%d:Q%d", &start, &end); err != nil {
+		return nil, err
+	}
+	var values [][]interface{}
+	for i := start; i <= end && i <= len(r.values); i++ {
+		values = append(values, r.values[i-1])
+	}
+	// Sheets API と同様に、範囲の末尾の空行は返さない
+	for len(values) > 0 && len(values[len(values)-1]) == 0 {
+		values = values[:len(values)-1]
+	}
+
+	return &sheets.ValueRange{Values: values}, nil
+}
+
+func TestFetchChunked(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	values := [][]interface{}{
+		{"Name", "Interval", "StartDate", "EndDate"},
+		{"Garbage", "Daily", "2025/01/01", "2025/01/31"},
+		{"Ended", "Daily", "2024/01/01", "2024/12/31"},
+		{"Piano", "Daily", "2025/01/01", "2025/01/31"},
+	}
+	reader := &rangeSheetReader{values: values}
+	src := NewSheetSource(reader, "dummy", nil, SheetOptions{ChunkRows: 2})
+	events, err := src.Fetch(context.Background(), time.Date(2025, 1, 10, 0, 0, 0, 0, tz))
+	tr.NoError(err)
+
+	// 値が返されなくなるまで読み込む
+	ta.Equal([]string{"remind!A2:Q3", "remind!A4:Q5", "remind!A6:Q7"}, reader.ranges)
+	tr.Len(events, 2)
+	ta.Equal("Garbage", events[0].Name)
+	ta.Equal(2, events[0].Row)
+	ta.Equal("Piano", events[1].Name)
+	ta.Equal(4, events[1].Row)
+}
+
+func TestFetchChunkedWithBlankRows(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	values := [][]interface{}{
+		{"Name", "Interval", "StartDate", "EndDate"},
+		{"Garbage", "Daily", "2025/01/01", "2025/01/31"},
+		{},
+		{"Piano", "Daily", "2025/01/01", "2025/01/31"},
+	}
+	reader := &rangeSheetReader{values: values}
+	src := NewSheetSource(reader, "dummy", nil, SheetOptions{ChunkRows: 2})
+	events, err := src.Fetch(context.Background(), time.Date(2025, 1, 10, 0, 0, 0, 0, tz))
+	tr.NoError(err)
+
+	// 末尾が空行の範囲は指定した行数に満たないが、続く行も読み込む
+	ta.Equal([]string{"remind!A2:Q3", "remind!A4:Q5", "remind!A6:Q7"}, reader.ranges)
+	tr.Len(events, 2)
+	ta.Equal("Garbage", events[0].Name)
+	ta.Equal("Piano", events[1].Name)
+	ta.Equal(4, events[1].Row)
+}
+
+func TestFetchFromOtherTab(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	values := [][]interface{}{
+		{"Name", "Interval", "StartDate", "EndDate"},
+		{"Garbage", "Daily", "2025/01/01", "2025/01/31", "", "", "", "", "", "", "", "", "", "", "", "", "12"},
+		{"Piano", "Daily", "2025/01/01", "2025/01/31"},
+	}
+	reader := &rangeSheetReader{values: values}
+	src := NewSheetSource(reader, "dummy", nil, SheetOptions{Tab: "active", ChunkRows: 10})
+	events, err := src.Fetch(context.Background(), time.Date(2025, 1, 10, 0, 0, 0, 0, tz))
+	tr.NoError(err)
+
+	ta.Equal([]string{"active!A2:Q11", "active!A12:Q21"}, reader.ranges)
+	tr.Len(events, 2)
+	// 元のシートの行番号が指定されていない行は、完了のチェックボックスを更新しない
+	ta.Equal(12, events[0].Row)
+	ta.Equal(0, events[1].Row)
+}
+
+func TestFetchDates(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	values := [][]interface{}{
+		{"Name", "Interval", "StartDate", "EndDate"},
+		{"Garbage", "Daily", "2025/01/01", "2025/01/10"},
+		{"Piano", "Daily", "2025/01/11", "2025/01/31"},
+	}
+	reader := &rangeSheetReader{values: values}
+	src := NewSheetSource(reader, "dummy", nil, SheetOptions{ChunkRows: 10})
+	events, err := src.FetchDates(context.Background(), []time.Time{
+		time.Date(2025, 1, 10, 0, 0, 0, 0, tz),
+		time.Date(2025, 1, 11, 0, 0, 0, 0, tz),
+		time.Date(2025, 2, 1, 0, 0, 0, 0, tz),
+	})
+	tr.NoError(err)
+
+	// 日付の数に関わらず、シートは一度だけ読み込む
+	ta.Equal([]string{"remind!A2:Q11", "remind!A12:Q21"}, reader.ranges)
+	tr.Len(events, 3)
+	tr.Len(events[0], 1)
+	ta.Equal("Garbage", events[0][0].Name)
+	tr.Len(events[1], 1)
+	ta.Equal("Piano", events[1][0].Name)
+	ta.Empty(events[2])
+}
+
+func TestRetrySheets(t *testing.T) {
+	quota := &googleapi.Error{Code: 429, Message: "Quota exceeded for quota metric 'Read requests'"}
+	internal := &googleapi.Error{Code: 500, Message: "Internal error encountered."}
+	unavailable := &googleapi.Error{Code: 503, Message: "The service is currently unavailable."}
+	retryAfter := &googleapi.Error{Code: 429, Header: http.Header{"Retry-After": []string{"2"}}}
+	forbidden := &googleapi.Error{Code: 403, Message: "The caller does not have permission"}
+
+	tests := []struct {
+		name          string
+		errs          []error
+		expectError   bool
+		expectedCalls int
+		expectedWaits []time.Duration // 揺らぎを除いた最大値
+	}{
+		{
+			name:          "正常系/一時的なエラーの後に成功した場合",
+			errs:          []error{internal, internal, nil},
+			expectedCalls: 3,
+			expectedWaits: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:          "正常系/クォータを超過した場合",
+			errs:          []error{quota, nil},
+			expectedCalls: 2,
+			expectedWaits: []time.Duration{5 * time.Second},
+		},
+		{
+			name:          "正常系/Retry-After が指定されている場合",
+			errs:          []error{retryAfter, nil},
+			expectedCalls: 2,
+			expectedWaits: []time.Duration{2 * time.Second},
+		},
+		{
+			name:          "異常系/再試行しないエラーの場合",
+			errs:          []error{forbidden},
+			expectError:   true,
+			expectedCalls: 1,
+		},
+		{
+			name:          "異常系/トランスポートが再試行するエラーの場合",
+			errs:          []error{unavailable},
+			expectError:   true,
+			expectedCalls: 1,
+		},
+		{
+			name:          "異常系/失敗し続けた場合",
+			errs:          []error{internal, internal, internal, internal, nil},
+			expectError:   true,
+			expectedCalls: 4,
+			expectedWaits: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			calls := 0
+			var waits []time.Duration
+			wait := func(ctx context.Context, d time.Duration) error {
+				waits = append(waits, d)
+				return nil
+			}
+			_, err := retrySheets(context.Background(), wait, func() (*sheets.ValueRange, error) {
+				err := tt.errs[calls]
+				calls++
+				if err != nil {
+					return nil, err
+				}
+				return &sheets.ValueRange{}, nil
+			})
+
+			ta.Equal(tt.expectedCalls, calls)
+			if tt.expectError {
+				ta.Error(err)
+			} else {
+				ta.NoError(err)
+			}
+			ta.Len(waits, len(tt.expectedWaits))
+			for i, d := range waits {
+				ta.LessOrEqual(d, tt.expectedWaits[i])
+				ta.GreaterOrEqual(d, tt.expectedWaits[i]/2)
+			}
+		})
+	}
+}
+
+func TestParseRow(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+	src := NewSheetSource(nil, "dummy", nil, SheetOptions{})
+
+	tests := []struct {
+		name        string
+		row         []interface{}
+		expectError bool
+		expected    *event.Event
+	}{
+		{
+			name:        "正常系/行が正常である場合",
+			row:         []interface{}{"Valid Event", "Weekly", "2025/01/01", "2025/01/31"},
+			expectError: false,
+			expected: &event.Event{
+				Name:      "Valid Event",
+				Interval:  event.Weekly,
+				StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, tz),
+				EndDate:   time.Date(2025, 1, 31, 0, 0, 0, 0, tz),
+			},
+		},
+		{
+			name:        "正常系/任意の列が指定されている場合",
+			row:         []interface{}{"Garbage", "Weekly", "2025/01/01", "2025/01/31", "🗑️", "#3fb950", "https://example.com", "燃えるゴミ"},
+			expectError: false,
+			expected: &event.Event{
+				Name:        "Garbage",
+				Interval:    event.Weekly,
+				StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, tz),
+				EndDate:     time.Date(2025, 1, 31, 0, 0, 0, 0, tz),
+				Emoji:       "🗑️",
+				Color:       0x3fb950,
+				URL:         "https://example.com",
+				Description: "燃えるゴミ",
+			},
+		},
+		{
+			name:        "正常系/間隔にオプションが指定されている場合",
+			row:         []interface{}{"Garbage", "weekly:mon,thu", "2025/01/01", "2025/01/31"},
+			expectError: false,
+			expected: &event.Event{
+				Name:      "Garbage",
+				Interval:  event.Weekly,
+				Weekdays:  []time.Weekday{time.Monday, time.Thursday},
+				StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, tz),
+				EndDate:   time.Date(2025, 1, 31, 0, 0, 0, 0, tz),
+			},
+		},
+		{
+			name:        "正常系/事前に通知する日数が指定されている場合",
+			row:         []interface{}{"Birthday", "Yearly", "2025/01/10", "", "", "", "", "", "", "7"},
+			expectError: false,
+			expected: &event.Event{
+				Name:         "Birthday",
+				Interval:     event.Yearly,
+				StartDate:    time.Date(2025, 1, 10, 0, 0, 0, 0, tz),
+				EndDate:      time.Date(9999, 12, 31, 0, 0, 0, 0, tz),
+				NotifyBefore: 7,
+			},
+		},
+		{
+			name:        "正常系/除外する日付が指定されている場合",
+			row:         []interface{}{"Piano", "Weekly", "2025/01/01", "", "", "", "", "", "", "", "", "", "2025/01/08, 2025/03/20-2025/04/05"},
+			expectError: false,
+			expected: &event.Event{
+				Name:      "Piano",
+				Interval:  event.Weekly,
+				StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, tz),
+				EndDate:   time.Date(9999, 12, 31, 0, 0, 0, 0, tz),
+				Exceptions: []event.DateRange{
+					{Start: time.Date(2025, 1, 8, 0, 0, 0, 0, tz), End: time.Date(2025, 1, 8, 0, 0, 0, 0, tz)},
+					{Start: time.Date(2025, 3, 20, 0, 0, 0, 0, tz), End: time.Date(2025, 4, 5, 0, 0, 0, 0, tz)},
+				},
+			},
+		},
+		{
+			name:        "正常系/完了のチェックボックスが指定されている場合",
+			row:         []interface{}{"Dentist", "Onetime", "2025/01/20", "2025/01/20", "", "", "", "", "", "", "", "", "", "TRUE"},
+			expectError: false,
+			expected: &event.Event{
+				Name:      "Dentist",
+				Interval:  event.Onetime,
+				StartDate: time.Date(2025, 1, 20, 0, 0, 0, 0, tz),
+				EndDate:   time.Date(2025, 1, 20, 0, 0, 0, 0, tz),
+				Done:      true,
+			},
+		},
+		{
+			name:        "異常系/色が不正な形式である場合",
+			row:         []interface{}{"Invalid Color Event", "Weekly", "2025/01/01", "2025/01/31", "", "green"},
+			expectError: true,
+		},
+		{
+			name:        "異常系/列数が足りない場合",
+			row:         []interface{}{"Invalid Event", "Daily", "2025-07-21"},
+			expectError: true,
+		},
+		{
+			name:        "異常系/開始日が不正な形式である場合",
+			row:         []interface{}{"Invalid StartDate Event", "Daily", "not-a-date", "2025-01-02"},
+			expectError: true,
+		},
+		{
+			name:        "異常系/終了日が不正な形式である場合",
+			row:         []interface{}{"Invalid EndDate Event", "Daily", "2025/01/01", "not-a-date"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			event, err := src.parseRow(tt.row)
+
+			if tt.expectError {
+				ta.Error(err)
+			} else {
+				ta.NoError(err)
+				ta.Equal(*tt.expected, event, "Parsed event should match expected value")
+			}
+		})
+	}
+}
+
+// シートには任意の値を入力できるため、どのような行でも panic せずにパースできるかエラーを返すことを確認する
+func FuzzParseRow(f *testing.F) {
+	f.Add("Garbage", "weekly:mon,thu", "2025/01/01", "2025/12/31", "#3fb950", "skip-weekend", "1", "07:30", "3", "2025/01/06-2025/01/10", "TRUE", "morning,evening", "1")
+	f.Add("Rent", "first business day of month", "2025/01/01", "", "", "", "", "", "", "", "", "", "")
+	f.Add("", "0 0 1,15 * *", "not-a-date", "", "zz", "", "-1", "25:00", "x", "2025/13/01", "maybe", ",", "-")
+
+	s := NewSheetSource(nil, "dummy", nil, SheetOptions{})
+	f.Fuzz(func(t *testing.T, name, interval, start, end, color, modifiers, notify, tod, count, except, done, tags, priority string) {
+		r := []interface{}{name, interval, start, end, "", color, "", "", modifiers, notify, tod, count, except, done, tags, priority}
+		_, _ = s.parseRow(r)
+		// 列が足りない行も扱える
+		for n := 0; n < len(r); n++ {
+			_, _ = s.parseRow(r[:n])
+		}
+	})
+}
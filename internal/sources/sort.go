@@ -0,0 +1,104 @@
+package sources
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// 1 日のイベントの並べ替えに使う項目
+type sortKey string
+
+const (
+	sortByPriority sortKey = "priority"
+	sortByTime     sortKey = "time"
+	sortByName     sortKey = "name"
+)
+
+// 指定した項目の順にイベントを並べ替えるデータソース
+type SortedSource struct {
+	source   Source
+	keys     []sortKey
+	collator *collate.Collator
+}
+
+// lang を指定した場合は、その言語の照合順序で名前を並べる (e.g. "ja")
+func NewSortedSource(source Source, keys []string, lang string) (*SortedSource, error) {
+	s := &SortedSource{source: source}
+	for _, k := range keys {
+		switch key := sortKey(strings.ToLower(strings.TrimSpace(k))); key {
+		case sortByPriority, sortByTime, sortByName:
+			s.keys = append(s.keys, key)
+		default:
+			return nil, fmt.Errorf("invalid sort key: %s", k)
+		}
+	}
+	if lang != "" {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return nil, fmt.Errorf("invalid collation: %s", lang)
+		}
+		s.collator = collate.New(tag)
+	}
+
+	return s, nil
+}
+
+func (s *SortedSource) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	events, err := s.source.Fetch(ctx, t)
+	if events == nil && err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(events, s.compare)
+
+	return events, err
+}
+
+func (s *SortedSource) compare(a, b event.Event) int {
+	for _, k := range s.keys {
+		var c int
+		switch k {
+		case sortByPriority:
+			c = cmp.Compare(priorityRank(a), priorityRank(b))
+		case sortByTime:
+			c = cmp.Compare(timeRank(a), timeRank(b))
+		case sortByName:
+			if s.collator != nil {
+				c = s.collator.CompareString(a.Name, b.Name)
+			} else {
+				c = strings.Compare(a.Name, b.Name)
+			}
+		}
+		if c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// 優先度が未指定のイベントは最後に並べる
+func priorityRank(e event.Event) int {
+	if e.Priority == 0 {
+		return math.MaxInt
+	}
+
+	return e.Priority
+}
+
+// 時刻が未指定のイベントは最後に並べる
+func timeRank(e event.Event) int {
+	if e.Time == nil {
+		return math.MaxInt
+	}
+
+	return e.Time.Hour*60 + e.Time.Minute
+}
@@ -0,0 +1,79 @@
+package sources
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortedSource(t *testing.T) {
+	events := []event.Event{
+		{Name: "Recycling"},
+		{Name: "Piano", Time: &event.TimeOfDay{Hour: 18}},
+		{Name: "Homework", Time: &event.TimeOfDay{Hour: 7, Minute: 30}},
+		{Name: "Rent", Priority: 1},
+		{Name: "Garbage"},
+	}
+
+	tests := []struct {
+		name          string
+		keys          []string
+		lang          string
+		events        []event.Event
+		expectError   bool
+		expectedNames []string
+	}{
+		{
+			name:          "正常系/優先度、時刻、名前の順に並べる場合",
+			keys:          []string{"priority", "time", "name"},
+			events:        events,
+			expectedNames: []string{"Rent", "Homework", "Piano", "Garbage", "Recycling"},
+		},
+		{
+			name:          "正常系/名前のみで並べる場合",
+			keys:          []string{"name"},
+			events:        events,
+			expectedNames: []string{"Garbage", "Homework", "Piano", "Recycling", "Rent"},
+		},
+		{
+			name:          "正常系/日本語の照合順序で並べる場合",
+			keys:          []string{"name"},
+			lang:          "ja",
+			events:        []event.Event{{Name: "ゴミ出し"}, {Name: "かいもの"}, {Name: "ガス代"}},
+			expectedNames: []string{"かいもの", "ガス代", "ゴミ出し"},
+		},
+		{
+			name:        "異常系/不正な項目が指定されている場合",
+			keys:        []string{"color"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			tr := require.New(t)
+
+			src, err := NewSortedSource(&MockEventSource{MockEvents: slices.Clone(tt.events)}, tt.keys, tt.lang)
+			if tt.expectError {
+				tr.Error(err)
+				return
+			}
+			tr.NoError(err)
+
+			sorted, err := src.Fetch(context.Background(), time.Now())
+			tr.NoError(err)
+
+			var names []string
+			for _, e := range sorted {
+				names = append(names, e.Name)
+			}
+			ta.Equal(tt.expectedNames, names)
+		})
+	}
+}
@@ -0,0 +1,15 @@
+// Package sources はイベント情報の取得元を提供する
+package sources
+
+import (
+	"context"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// 指定した日付に通知するイベントを返す取得元
+// 複数の取得元の一部のみ失敗した場合は、取得できたイベントとエラーを合わせて返す
+type Source interface {
+	Fetch(ctx context.Context, t time.Time) ([]event.Event, error)
+}
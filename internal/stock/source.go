@@ -0,0 +1,59 @@
+package stock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+)
+
+// イベントに付けるタグ、朝のスケジュールのみで通知する場合はプロファイルの tags に指定する
+const Tag = "stock"
+
+// 期限が近い食品を、当日のイベントとしてまとめて返す取得元
+// 投稿の前に読み込んだ在庫を使うため、今後のイベントには含めない
+type Source struct {
+	items []Item
+	days  int
+	today time.Time
+}
+
+func NewSource(items []Item, days int, today time.Time) *Source {
+	return &Source{items: items, days: days, today: today}
+}
+
+func (s *Source) Fetch(ctx context.Context, t time.Time) ([]event.Event, error) {
+	if !t.Equal(s.today) {
+		return nil, nil
+	}
+	expiring := ExpiringWithin(s.items, t, s.days)
+	if len(expiring) == 0 {
+		return nil, nil
+	}
+
+	e := event.Event{
+		Name:     fmt.Sprintf("期限が近い食品 (%d 件)", len(expiring)),
+		Interval: event.Onetime,
+		Emoji:    "🥫",
+		Tags:     []string{Tag},
+	}
+	lines := make([]string, 0, len(expiring))
+	for _, i := range expiring {
+		days := event.DaysBetween(t, i.Expiry)
+		switch {
+		case days < 0:
+			lines = append(lines, fmt.Sprintf("⚠ %s: 期限が切れています", i))
+			// 期限が切れたものがあれば先に表示する
+			e.Priority = 1
+		case days == 0:
+			lines = append(lines, fmt.Sprintf("%s: 今日まで", i))
+		default:
+			lines = append(lines, fmt.Sprintf("%s: あと %d 日", i, days))
+		}
+	}
+	e.Description = strings.Join(lines, "\n")
+
+	return []event.Event{e}, nil
+}
@@ -0,0 +1,152 @@
+// Package stock はスプレッドシートに記録した食品の在庫と、期限が近い食品の通知を提供する
+// Discord のコマンドで追加・消費し、remind の投稿で期限が近い食品を知らせる
+package stock
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 在庫を記録するシートの列、1 行目はヘッダーとする
+// A: 品名, B: 期限 (2006-01-02), C: 数量, D: 記録した人
+const Columns = "A:D"
+
+const dateFormat = "2006-01-02"
+
+// 在庫の食品
+type Item struct {
+	Row      int // シートの行番号 (1 始まり)、追加する場合は 0
+	Name     string
+	Expiry   time.Time
+	Quantity int
+	AddedBy  string
+}
+
+// 入力された食品を検証する
+func (i Item) Validate() error {
+	if strings.TrimSpace(i.Name) == "" {
+		return fmt.Errorf("name is blank")
+	}
+	if i.Quantity <= 0 {
+		return fmt.Errorf("quantity must be positive: %d", i.Quantity)
+	}
+
+	return nil
+}
+
+// シートに追加する行に変換する
+func (i Item) Values() []interface{} {
+	return []interface{}{i.Name, i.Expiry.Format(dateFormat), i.Quantity, i.AddedBy}
+}
+
+// 期限と数量を表す、e.g. 牛乳 ×2 (3/3 まで)
+func (i Item) String() string {
+	return fmt.Sprintf("%s ×%d (%s まで)", i.Name, i.Quantity, i.Expiry.Format("1/2"))
+}
+
+// シートの行から在庫を読み込む、1 行目はヘッダーとして読み飛ばす
+// 品名が空の行と数量が 0 の行は消費済みとして読み飛ばし、形式が不正な行はエラーを返す
+func ParseRows(rows [][]interface{}, loc *time.Location) ([]Item, error) {
+	var items []Item
+	for i := 1; i < len(rows); i++ {
+		r := rows[i]
+		if cell(r, 0) == "" {
+			continue
+		}
+		expiry, err := time.ParseInLocation(dateFormat, cell(r, 1), loc)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid expiry: %s", i+1, cell(r, 1))
+		}
+		quantity := 1
+		if s := cell(r, 2); s != "" {
+			quantity, err = strconv.Atoi(s)
+			if err != nil || quantity < 0 {
+				return nil, fmt.Errorf("row %d: invalid quantity: %s", i+1, s)
+			}
+		}
+		if quantity == 0 {
+			continue
+		}
+		items = append(items, Item{
+			Row:      i + 1,
+			Name:     cell(r, 0),
+			Expiry:   expiry,
+			Quantity: quantity,
+			AddedBy:  cell(r, 3),
+		})
+	}
+
+	return items, nil
+}
+
+// 期限を読み込む、e.g. 2025-03-05, 2025/3/5, 3/5
+// 年を省略した場合は today 以降で最も近い日とする
+func ParseDate(s string, today time.Time) (time.Time, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "-", "/")
+	for _, layout := range []string{"2006/1/2", "1/2"} {
+		t, err := time.ParseInLocation(layout, s, today.Location())
+		if err != nil {
+			continue
+		}
+		if layout == "1/2" {
+			t = time.Date(today.Year(), t.Month(), t.Day(), 0, 0, 0, 0, today.Location())
+			if t.Before(today) {
+				t = t.AddDate(1, 0, 0)
+			}
+		}
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date: %s", s)
+}
+
+// 名前が一致する食品のうち、期限が最も近いものを返す
+func Find(items []Item, name string) (Item, bool) {
+	name = strings.TrimSpace(name)
+	var found []Item
+	for _, i := range items {
+		if strings.EqualFold(i.Name, name) {
+			found = append(found, i)
+		}
+	}
+	if len(found) == 0 {
+		return Item{}, false
+	}
+
+	return slices.MinFunc(found, func(a, b Item) int {
+		return cmp.Or(a.Expiry.Compare(b.Expiry), cmp.Compare(a.Row, b.Row))
+	}), true
+}
+
+// 期限が today から days 日以内の食品を期限の近い順に返す、期限が切れたものも含める
+func ExpiringWithin(items []Item, today time.Time, days int) []Item {
+	limit := today.AddDate(0, 0, days)
+	var expiring []Item
+	for _, i := range items {
+		if !i.Expiry.After(limit) {
+			expiring = append(expiring, i)
+		}
+	}
+	SortByExpiry(expiring)
+
+	return expiring
+}
+
+// 期限の近い順に並べる、期限が同じ場合はシートの順にする
+func SortByExpiry(items []Item) {
+	slices.SortStableFunc(items, func(a, b Item) int {
+		return a.Expiry.Compare(b.Expiry)
+	})
+}
+
+func cell(r []interface{}, i int) string {
+	if i >= len(r) {
+		return ""
+	}
+
+	return strings.TrimSpace(fmt.Sprint(r[i]))
+}
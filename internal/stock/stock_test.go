@@ -0,0 +1,128 @@
+package stock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tz = time.FixedZone("JST", 9*60*60)
+
+func date(month time.Month, day int) time.Time {
+	return time.Date(2025, month, day, 0, 0, 0, 0, tz)
+}
+
+func TestParseRows(t *testing.T) {
+	t.Run("正常系/消費済みの行は読み飛ばす", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		items, err := ParseRows([][]interface{}{
+			{"品名", "期限", "数量", "記録した人"},
+			{"牛乳", "2025-03-03", "2", "alice"},
+			{"", "", "", ""},
+			{"卵", "2025-03-10", "0"},
+			{"豆腐", "2025-03-02"},
+		}, tz)
+		tr.NoError(err)
+		ta.Equal([]Item{
+			{Row: 2, Name: "牛乳", Expiry: date(3, 3), Quantity: 2, AddedBy: "alice"},
+			{Row: 5, Name: "豆腐", Expiry: date(3, 2), Quantity: 1},
+		}, items)
+	})
+
+	t.Run("異常系/期限の形式が不正な場合", func(t *testing.T) {
+		_, err := ParseRows([][]interface{}{{"品名"}, {"牛乳", "来週"}}, tz)
+		assert.Error(t, err)
+	})
+
+	t.Run("異常系/数量が負の場合", func(t *testing.T) {
+		_, err := ParseRows([][]interface{}{{"品名"}, {"牛乳", "2025-03-03", "-1"}}, tz)
+		assert.Error(t, err)
+	})
+}
+
+func TestParseDate(t *testing.T) {
+	today := date(3, 1)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{name: "正常系/年月日をハイフンで区切った場合", input: "2025-03-05", expected: date(3, 5)},
+		{name: "正常系/年月日をスラッシュで区切った場合", input: "2025/3/5", expected: date(3, 5)},
+		{name: "正常系/年を省略した場合", input: "3/5", expected: date(3, 5)},
+		{name: "正常系/年を省略して過ぎた日の場合は翌年とする", input: "2/28", expected: time.Date(2026, 2, 28, 0, 0, 0, 0, tz)},
+		{name: "異常系/日付ではない場合", input: "来週", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			got, err := ParseDate(tt.input, today)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, got)
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	ta := assert.New(t)
+	items := []Item{
+		{Row: 2, Name: "牛乳", Expiry: date(3, 5)},
+		{Row: 3, Name: "Milk", Expiry: date(3, 3)},
+		{Row: 4, Name: "牛乳", Expiry: date(3, 3)},
+	}
+
+	item, ok := Find(items, " 牛乳 ")
+	ta.True(ok)
+	ta.Equal(4, item.Row)
+
+	_, ok = Find(items, "卵")
+	ta.False(ok)
+}
+
+func TestSource(t *testing.T) {
+	today := date(3, 1)
+	items := []Item{
+		{Name: "卵", Expiry: date(3, 10), Quantity: 6},
+		{Name: "牛乳", Expiry: date(3, 3), Quantity: 1},
+		{Name: "豆腐", Expiry: date(2, 28), Quantity: 1},
+		{Name: "納豆", Expiry: date(3, 1), Quantity: 3},
+	}
+
+	t.Run("正常系/期限が近い食品を期限の近い順に返す", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		events, err := NewSource(items, 3, today).Fetch(context.Background(), today)
+		tr.NoError(err)
+		tr.Len(events, 1)
+		ta.Equal("期限が近い食品 (3 件)", events[0].Name)
+		ta.Equal("⚠ 豆腐 ×1 (2/28 まで): 期限が切れています\n納豆 ×3 (3/1 まで): 今日まで\n牛乳 ×1 (3/3 まで): あと 2 日", events[0].Description)
+		ta.Equal(1, events[0].Priority)
+		ta.Equal([]string{Tag}, events[0].Tags)
+	})
+
+	t.Run("正常系/当日以外は返さない", func(t *testing.T) {
+		events, err := NewSource(items, 3, today).Fetch(context.Background(), today.AddDate(0, 0, 1))
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("正常系/期限が近い食品がない場合は返さない", func(t *testing.T) {
+		events, err := NewSource(items[:1], 3, today).Fetch(context.Background(), today)
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	})
+}
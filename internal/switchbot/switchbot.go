@@ -0,0 +1,222 @@
+// Package switchbot は SwitchBot API から温湿度計の値を取得し、ボットとプラグを操作する
+// https://github.com/OpenWonderLabs/SwitchBotAPI
+package switchbot
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/indoor"
+)
+
+const SourceName = "switchbot"
+
+// 操作するコマンド
+type Command string
+
+const (
+	Press   Command = "press"   // ボットのボタンを押す
+	TurnOn  Command = "turnOn"  // ボットもしくはプラグの電源を入れる
+	TurnOff Command = "turnOff" // ボットもしくはプラグの電源を切る
+)
+
+func (c Command) String() string {
+	switch c {
+	case Press:
+		return "押す"
+	case TurnOn:
+		return "電源を入れる"
+	case TurnOff:
+		return "電源を切る"
+	default:
+		return string(c)
+	}
+}
+
+func ParseCommand(s string) (Command, error) {
+	switch c := Command(s); c {
+	case Press, TurnOn, TurnOff:
+		return c, nil
+	default:
+		return "", fmt.Errorf("invalid command: %s", s)
+	}
+}
+
+// 温湿度計とボット、プラグの種類
+var (
+	meterTypes      = []string{"Meter", "MeterPlus", "WoIOSensor", "Hub 2", "MeterPro", "MeterPro(CO2)"}
+	controllerTypes = []string{"Bot", "Plug", "Plug Mini (US)", "Plug Mini (JP)"}
+)
+
+type Device struct {
+	ID   string `json:"deviceId"`
+	Name string `json:"deviceName"`
+	Type string `json:"deviceType"`
+}
+
+func (d Device) IsMeter() bool {
+	return slices.Contains(meterTypes, d.Type)
+}
+
+// コマンドで操作できる場合は true を返す
+func (d Device) IsController() bool {
+	return slices.Contains(controllerTypes, d.Type)
+}
+
+// 名前が一致する機器を返す
+func FindDevice(devices []Device, name string) (Device, bool) {
+	for _, d := range devices {
+		if strings.EqualFold(d.Name, strings.TrimSpace(name)) {
+			return d, true
+		}
+	}
+
+	return Device{}, false
+}
+
+type Client struct {
+	http    *http.Client
+	baseURL string
+	token   string
+	secret  string
+	now     func() time.Time
+}
+
+// baseURL は e.g. https://api.switch-bot.com
+func NewClient(client *http.Client, baseURL, token, secret string) *Client {
+	return &Client{http: client, baseURL: strings.TrimSuffix(baseURL, "/"), token: token, secret: secret, now: time.Now}
+}
+
+func (c *Client) Devices(ctx context.Context) ([]Device, error) {
+	var body struct {
+		DeviceList []Device `json:"deviceList"`
+	}
+	if err := c.call(ctx, http.MethodGet, "/v1.1/devices", nil, &body); err != nil {
+		return nil, err
+	}
+
+	return body.DeviceList, nil
+}
+
+// 温湿度計ごとに最新の値を返す
+func (c *Client) Readings(ctx context.Context) ([]indoor.Reading, error) {
+	devices, err := c.Devices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var readings []indoor.Reading
+	for _, d := range devices {
+		if !d.IsMeter() {
+			continue
+		}
+		var status struct {
+			Temperature *float64 `json:"temperature"`
+			Humidity    *float64 `json:"humidity"`
+		}
+		if err := c.call(ctx, http.MethodGet, "/v1.1/devices/"+url.PathEscape(d.ID)+"/status", nil, &status); err != nil {
+			return nil, err
+		}
+		readings = append(readings, indoor.Reading{Device: d.Name, Temperature: status.Temperature, Humidity: status.Humidity, Time: c.now()})
+	}
+
+	return readings, nil
+}
+
+func (c *Client) Send(ctx context.Context, deviceID string, cmd Command) error {
+	return c.call(ctx, http.MethodPost, "/v1.1/devices/"+url.PathEscape(deviceID)+"/commands", map[string]string{
+		"command":     string(cmd),
+		"parameter":   "default",
+		"commandType": "command",
+	}, nil)
+}
+
+// SwitchBot API のレスポンス、HTTP のステータスが 200 でも statusCode が 100 以外の場合は失敗している
+type response struct {
+	StatusCode int             `json:"statusCode"`
+	Message    string          `json:"message"`
+	Body       json.RawMessage `json:"body"`
+}
+
+const statusSuccess = 100
+
+func (c *Client) call(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if err := c.sign(req.Header); err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return event.NewSourceUnavailableError(SourceName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return event.NewSourceUnavailableError(SourceName, fmt.Errorf("returned status %d", resp.StatusCode))
+	}
+	var r response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return event.NewParseError(SourceName, path, err)
+	}
+	if r.StatusCode != statusSuccess {
+		return event.NewSourceUnavailableError(SourceName, fmt.Errorf("returned status %d: %s", r.StatusCode, r.Message))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(r.Body, out); err != nil {
+		return event.NewParseError(SourceName, path, err)
+	}
+
+	return nil
+}
+
+// トークン、タイムスタンプ、nonce をシークレットで署名する
+func (c *Client) sign(h http.Header) error {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	nonce := hex.EncodeToString(b)
+	t := strconv.FormatInt(c.now().UnixMilli(), 10)
+
+	mac := hmac.New(sha256.New, []byte(c.secret))
+	mac.Write([]byte(c.token + t + nonce))
+
+	h.Set("Authorization", c.token)
+	h.Set("t", t)
+	h.Set("nonce", nonce)
+	h.Set("sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package switchbot
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/indoor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr(v float64) *float64 {
+	return &v
+}
+
+func TestParseCommand(t *testing.T) {
+	ta := assert.New(t)
+
+	c, err := ParseCommand("turnOn")
+	ta.NoError(err)
+	ta.Equal(TurnOn, c)
+	ta.Equal("電源を入れる", c.String())
+
+	_, err = ParseCommand("setColor")
+	ta.Error(err)
+}
+
+func TestClient(t *testing.T) {
+	now := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	var commands []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(r.Header.Get("Authorization") + r.Header.Get("t") + r.Header.Get("nonce")))
+		if r.Header.Get("Authorization") != "token" || r.Header.Get("t") != "1740816000000" || r.Header.Get("sign") != base64.StdEncoding.EncodeToString(mac.Sum(nil)) {
+			w.Write([]byte(`{"statusCode": 190, "message": "unauthorized", "body": {}}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1.1/devices":
+			w.Write([]byte(`{"statusCode": 100, "message": "success", "body": {"deviceList": [
+				{"deviceId": "m1", "deviceName": "寝室", "deviceType": "Meter"},
+				{"deviceId": "b1", "deviceName": "給湯器", "deviceType": "Bot"},
+				{"deviceId": "p1", "deviceName": "電気毛布", "deviceType": "Plug Mini (JP)"}
+			]}}`))
+		case "/v1.1/devices/m1/status":
+			w.Write([]byte(`{"statusCode": 100, "message": "success", "body": {"temperature": 17.5, "humidity": 45}}`))
+		case "/v1.1/devices/b1/commands":
+			b, _ := io.ReadAll(r.Body)
+			commands = append(commands, string(b))
+			w.Write([]byte(`{"statusCode": 100, "message": "success", "body": {}}`))
+		default:
+			w.Write([]byte(`{"statusCode": 161, "message": "device offline", "body": {}}`))
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(srv.Client(), srv.URL, "token", "secret")
+	c.now = func() time.Time { return now }
+
+	t.Run("正常系/温湿度計の値を取得する", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		readings, err := c.Readings(context.Background())
+		tr.NoError(err)
+		ta.Equal([]indoor.Reading{{Device: "寝室", Temperature: ptr(17.5), Humidity: ptr(45), Time: now}}, readings)
+	})
+
+	t.Run("正常系/ボットを操作する", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		devices, err := c.Devices(context.Background())
+		tr.NoError(err)
+		d, ok := FindDevice(devices, "給湯器")
+		tr.True(ok)
+		ta.True(d.IsController())
+		ta.False(d.IsMeter())
+
+		tr.NoError(c.Send(context.Background(), d.ID, Press))
+		ta.JSONEq(`{"command": "press", "parameter": "default", "commandType": "command"}`, commands[0])
+	})
+
+	t.Run("異常系/機器に接続できない場合", func(t *testing.T) {
+		assert.Error(t, c.Send(context.Background(), "p1", TurnOn))
+	})
+
+	t.Run("異常系/署名が一致しない場合", func(t *testing.T) {
+		invalid := NewClient(srv.Client(), srv.URL, "token", "invalid")
+		invalid.now = c.now
+		_, err := invalid.Devices(context.Background())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,282 @@
+package testsupport
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/mami0tsu/homeops/internal/discord"
+)
+
+const discordBase = "/api/v10"
+
+// チャンネルが属するサーバーの ID
+const DiscordGuildID = "900"
+
+// Webhook で投稿されたメッセージ
+type DiscordMessage struct {
+	WebhookID string
+	ThreadID  string
+	discord.WebhookMessage
+	Files map[string]string // 添付されたファイルの名前と内容
+}
+
+// インタラクションの応答の編集
+type DiscordEdit struct {
+	ApplicationID string
+	Token         string
+	Data          discord.InteractionResponseData
+}
+
+// Discord の Webhook とインタラクション、スレッド、スケジュールイベントの API を模したサーバー
+type Discord struct {
+	mu       sync.Mutex
+	nextID   int
+	webhooks map[string]string // Webhook の ID とトークン
+	messages []DiscordMessage
+	edits    []DiscordEdit
+	events   []discord.ScheduledEvent
+	threads  []discord.Channel
+	created  int
+	deleted  int
+}
+
+func newDiscord() *Discord {
+	return &Discord{nextID: 1000, webhooks: map[string]string{}}
+}
+
+func (d *Discord) register(mux *http.ServeMux) {
+	mux.HandleFunc("POST "+discordBase+"/channels/{channel}/webhooks", d.createWebhook)
+	mux.HandleFunc("DELETE "+discordBase+"/webhooks/{id}", d.deleteWebhook)
+	mux.HandleFunc("POST "+discordBase+"/webhooks/{id}/{token}", d.executeWebhook)
+	mux.HandleFunc("PATCH "+discordBase+"/webhooks/{app}/{token}/messages/@original", d.editOriginal)
+	mux.HandleFunc("GET "+discordBase+"/channels/{channel}", d.getChannel)
+	mux.HandleFunc("GET "+discordBase+"/guilds/{guild}/threads/active", d.listActiveThreads)
+	mux.HandleFunc("POST "+discordBase+"/channels/{channel}/threads", d.startThread)
+	mux.HandleFunc("GET "+discordBase+"/guilds/{guild}/scheduled-events", d.listScheduledEvents)
+	mux.HandleFunc("POST "+discordBase+"/guilds/{guild}/scheduled-events", d.createScheduledEvent)
+}
+
+// 投稿されたメッセージを投稿順に返す
+func (d *Discord) Messages() []DiscordMessage {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]DiscordMessage(nil), d.messages...)
+}
+
+// インタラクションの応答の編集を編集順に返す
+func (d *Discord) Edits() []DiscordEdit {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]DiscordEdit(nil), d.edits...)
+}
+
+// 作成された Webhook の数と削除された Webhook の数を返す
+func (d *Discord) WebhookCount() (created, deleted int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.created, d.deleted
+}
+
+// 作成されたスケジュールイベントを作成順に返す
+func (d *Discord) ScheduledEvents() []discord.ScheduledEvent {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]discord.ScheduledEvent(nil), d.events...)
+}
+
+// 登録済みのスケジュールイベントを追加する、重複して作成しないことを確認する場合に使う
+func (d *Discord) AddScheduledEvent(e discord.ScheduledEvent) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.events = append(d.events, e)
+}
+
+// 作成済みのスレッドを含め、アクティブなスレッドを作成順に返す
+func (d *Discord) Threads() []discord.Channel {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]discord.Channel(nil), d.threads...)
+}
+
+// アクティブなスレッドを追加する、既存のスレッドの再利用を確認する場合に使う
+func (d *Discord) AddThread(c discord.Channel) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.threads = append(d.threads, c)
+}
+
+// サーバー側で Webhook が削除された状態にする、保存した Webhook の再作成を確認する場合に使う
+func (d *Discord) RemoveWebhook(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.webhooks, id)
+}
+
+func (d *Discord) createWebhook(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.nextID++
+	id := strconv.Itoa(d.nextID)
+	token := "token-" + id
+	d.webhooks[id] = token
+	d.created++
+	writeJSON(w, http.StatusOK, discord.Webhook{ID: id, Token: token})
+}
+
+func (d *Discord) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	id := r.PathValue("id")
+	if _, ok := d.webhooks[id]; !ok {
+		unknownWebhook(w)
+		return
+	}
+	delete(d.webhooks, id)
+	d.deleted++
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (d *Discord) executeWebhook(w http.ResponseWriter, r *http.Request) {
+	msg, err := readWebhookMessage(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"code": 50035, "message": err.Error()})
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	id := r.PathValue("id")
+	if token, ok := d.webhooks[id]; !ok || token != r.PathValue("token") {
+		unknownWebhook(w)
+		return
+	}
+	msg.WebhookID = id
+	msg.ThreadID = r.URL.Query().Get("thread_id")
+	d.messages = append(d.messages, msg)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// どのチャンネルも DiscordGuildID のサーバーのテキストチャンネルとして返す
+func (d *Discord) getChannel(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("channel")
+	writeJSON(w, http.StatusOK, discord.Channel{ID: id, GuildID: DiscordGuildID, Name: "channel-" + id})
+}
+
+func (d *Discord) listActiveThreads(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{"threads": append([]discord.Channel{}, d.threads...)})
+}
+
+func (d *Discord) startThread(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name string `json:"name"`
+		Type int    `json:"type"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"code": 50035, "message": err.Error()})
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.nextID++
+	c := discord.Channel{ID: strconv.Itoa(d.nextID), GuildID: DiscordGuildID, ParentID: r.PathValue("channel"), Name: body.Name, Type: body.Type}
+	d.threads = append(d.threads, c)
+	writeJSON(w, http.StatusOK, c)
+}
+
+func (d *Discord) listScheduledEvents(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, append([]discord.ScheduledEvent{}, d.events...))
+}
+
+func (d *Discord) createScheduledEvent(w http.ResponseWriter, r *http.Request) {
+	var e discord.ScheduledEvent
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"code": 50035, "message": err.Error()})
+		return
+	}
+	// Discord と同様に、長すぎる名前と説明は受け付けない
+	if utf8.RuneCountInString(e.Name) > 100 || utf8.RuneCountInString(e.Description) > 1000 {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"code": 50035, "message": "Invalid Form Body"})
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.nextID++
+	e.ID = strconv.Itoa(d.nextID)
+	d.events = append(d.events, e)
+	writeJSON(w, http.StatusOK, e)
+}
+
+func (d *Discord) editOriginal(w http.ResponseWriter, r *http.Request) {
+	var data discord.InteractionResponseData
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"code": 50035, "message": err.Error()})
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.edits = append(d.edits, DiscordEdit{ApplicationID: r.PathValue("app"), Token: r.PathValue("token"), Data: data})
+	writeJSON(w, http.StatusOK, map[string]string{"id": "1"})
+}
+
+// JSON もしくは multipart/form-data で送られたメッセージを読み込む
+func readWebhookMessage(r *http.Request) (DiscordMessage, error) {
+	msg := DiscordMessage{Files: map[string]string{}}
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return msg, json.NewDecoder(r.Body).Decode(&msg.WebhookMessage)
+	}
+
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		return msg, err
+	}
+	if err := json.Unmarshal([]byte(r.FormValue("payload_json")), &msg.WebhookMessage); err != nil {
+		return msg, err
+	}
+	for _, headers := range r.MultipartForm.File {
+		for _, h := range headers {
+			f, err := h.Open()
+			if err != nil {
+				return msg, err
+			}
+			b, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				return msg, err
+			}
+			msg.Files[h.Filename] = string(b)
+		}
+	}
+
+	return msg, nil
+}
+
+func unknownWebhook(w http.ResponseWriter) {
+	writeJSON(w, http.StatusNotFound, map[string]any{"code": 10015, "message": "Unknown Webhook"})
+}
@@ -0,0 +1,115 @@
+package testsupport
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Google Sheets の values API を模したサーバー
+type Sheets struct {
+	mu     sync.Mutex
+	values map[string][][]any // スプレッドシートの ID とシート名ごとの値
+	ranges []string
+	fails  []int
+}
+
+func newSheets() *Sheets {
+	return &Sheets{values: map[string][][]any{}}
+}
+
+func (s *Sheets) register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v4/spreadsheets/{id}/values/{range}", s.getValues)
+}
+
+// シートの値を設定する、1 行目をヘッダーとして含める
+func (s *Sheets) SetValues(spreadsheetID, tab string, rows [][]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[spreadsheetID+"!"+tab] = rows
+}
+
+// 次の読み込みを指定したステータスコードで失敗させる、複数指定した場合は順に失敗させる
+func (s *Sheets) FailNext(statuses ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fails = append(s.fails, statuses...)
+}
+
+// 読み込まれた範囲を読み込み順に返す
+func (s *Sheets) Ranges() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.ranges...)
+}
+
+func (s *Sheets) getValues(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	readRange := r.PathValue("range")
+	s.ranges = append(s.ranges, readRange)
+	if len(s.fails) > 0 {
+		status := s.fails[0]
+		s.fails = s.fails[1:]
+		writeSheetsError(w, status, http.StatusText(status))
+		return
+	}
+
+	tab, first, last, err := parseRange(readRange)
+	if err != nil {
+		writeSheetsError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	rows, ok := s.values[r.PathValue("id")+"!"+tab]
+	if !ok {
+		writeSheetsError(w, http.StatusNotFound, "Requested entity was not found.")
+		return
+	}
+
+	// 範囲外の行は返さない
+	var values [][]any
+	for i := first; i <= last && i <= len(rows); i++ {
+		values = append(values, rows[i-1])
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"range": readRange, "majorDimension": "ROWS", "values": values})
+}
+
+// "remind!A:Q" や "remind!A2:Q101" の形式の範囲から、シート名と 1 始まりの行の範囲を返す
+// 行が指定されていない場合はシート全体を対象とする
+func parseRange(s string) (tab string, first, last int, err error) {
+	tab, cells, ok := strings.Cut(s, "!")
+	if !ok {
+		return "", 0, 0, fmt.Errorf("invalid range: %s", s)
+	}
+	start, end, _ := strings.Cut(cells, ":")
+	if first, err = parseRow(start, 1); err != nil {
+		return "", 0, 0, fmt.Errorf("invalid range: %s", s)
+	}
+	if last, err = parseRow(end, math.MaxInt); err != nil {
+		return "", 0, 0, fmt.Errorf("invalid range: %s", s)
+	}
+
+	return tab, first, last, nil
+}
+
+// "A2" のようなセルから行番号を返す、列のみの場合は def を返す
+func parseRow(cell string, def int) (int, error) {
+	digits := strings.TrimLeft(cell, "ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	if digits == "" {
+		return def, nil
+	}
+
+	return strconv.Atoi(digits)
+}
+
+// googleapi.Error として読み込まれる形式でエラーを返す
+func writeSheetsError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{"error": map[string]any{"code": status, "message": message}})
+}
@@ -0,0 +1,75 @@
+// Package testsupport は連携先のサービスを模したテスト用のサーバーを提供する
+// クラウドの連携先に接続せずに、取得から投稿までの処理をまとめてテストするために使う
+package testsupport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+// Discord と Google Sheets を模したサーバー
+// パスで連携先を振り分けるため、1 つのサーバーで両方の API を扱う
+type Server struct {
+	Discord *Discord
+	Sheets  *Sheets
+
+	srv *httptest.Server
+}
+
+// サーバーを起動する、テストの終了時に停止する
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{Discord: newDiscord(), Sheets: newSheets()}
+	mux := http.NewServeMux()
+	s.Discord.register(mux)
+	s.Sheets.register(mux)
+	s.srv = httptest.NewServer(mux)
+	t.Cleanup(s.srv.Close)
+
+	return s
+}
+
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// 宛先のホストによらず、すべてのリクエストをこのサーバーに送るクライアントを返す
+// 接続先を変更できないクライアントに渡して使う
+func (s *Server) Client() *http.Client {
+	target, _ := url.Parse(s.srv.URL)
+
+	return &http.Client{Transport: &rewriteTransport{target: target, base: s.srv.Client().Transport}}
+}
+
+// このサーバーに接続する Google Sheets のクライアントを返す
+func (s *Server) SheetsService(ctx context.Context) (*sheets.Service, error) {
+	return sheets.NewService(ctx, option.WithHTTPClient(s.Client()))
+}
+
+type rewriteTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	r.Host = ""
+
+	return t.base.RoundTrip(r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,93 @@
+package testsupport
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/mami0tsu/homeops/internal/discord"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		tab     string
+		first   int
+		last    int
+		wantErr bool
+	}{
+		{name: "正常系/シート全体", input: "remind!A:Q", tab: "remind", first: 1, last: 1<<63 - 1},
+		{name: "正常系/行を指定", input: "active!A2:Q101", tab: "active", first: 2, last: 101},
+		{name: "異常系/シート名がない", input: "A1:Q1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			tab, first, last, err := parseRange(tt.input)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.tab, tab)
+			ta.Equal(tt.first, first)
+			ta.Equal(tt.last, last)
+		})
+	}
+}
+
+func TestSheets(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	s := NewServer(t)
+	s.Sheets.SetValues("sheet", "remind", [][]any{{"Name"}, {"Garbage"}, {"Piano"}})
+	srv, err := s.SheetsService(context.Background())
+	tr.NoError(err)
+
+	resp, err := srv.Spreadsheets.Values.Get("sheet", "remind!A2:Q2").Do()
+	tr.NoError(err)
+	ta.Equal([][]any{{"Garbage"}}, resp.Values)
+
+	s.Sheets.FailNext(http.StatusTooManyRequests)
+	_, err = srv.Spreadsheets.Values.Get("sheet", "remind!A:Q").Do()
+	var apiErr *googleapi.Error
+	tr.ErrorAs(err, &apiErr)
+	ta.Equal(http.StatusTooManyRequests, apiErr.Code)
+
+	ta.Equal([]string{"remind!A2:Q2", "remind!A:Q"}, s.Sheets.Ranges())
+}
+
+func TestDiscord(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	s := NewServer(t)
+	ctx := context.Background()
+	c := discord.NewClient(s.Client(), "bot")
+
+	w, err := c.CreateWebhook(ctx, "123", "remind")
+	tr.NoError(err)
+	tr.NoError(c.ExecuteWebhook(ctx, c.WebhookURL(w, "456"), &discord.WebhookMessage{Content: "hello"}))
+	tr.NoError(c.DeleteWebhook(ctx, w.ID))
+
+	// 削除した Webhook には投稿できない
+	err = c.ExecuteWebhook(ctx, c.WebhookURL(w, ""), &discord.WebhookMessage{Content: "hello"})
+	var apiErr *discord.APIError
+	tr.ErrorAs(err, &apiErr)
+	ta.Equal(http.StatusNotFound, apiErr.StatusCode)
+
+	msgs := s.Discord.Messages()
+	tr.Len(msgs, 1)
+	ta.Equal("hello", msgs[0].Content)
+	ta.Equal("456", msgs[0].ThreadID)
+	created, deleted := s.Discord.WebhookCount()
+	ta.Equal(1, created)
+	ta.Equal(1, deleted)
+}
@@ -0,0 +1,196 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// スパンを OTLP/HTTP の JSON 形式で送信する
+// 依存を増やさないように、公式の exporter ではなく必要な項目のみを組み立てる
+type exporter struct {
+	client   *http.Client
+	endpoint string
+}
+
+func newExporter(client *http.Client, endpoint string) *exporter {
+	return &exporter{client: client, endpoint: endpoint}
+}
+
+func (e *exporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(createTracesRequest(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("otlp endpoint returned status %d: %s", resp.StatusCode, b)
+	}
+
+	return nil
+}
+
+func (e *exporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+type tracesRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   otlpResource `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []keyValue  `json:"attributes,omitempty"`
+	Events            []otlpEvent `json:"events,omitempty"`
+	Status            otlpStatus  `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string     `json:"timeUnixNano"`
+	Name         string     `json:"name"`
+	Attributes   []keyValue `json:"attributes,omitempty"`
+}
+
+// OTLP のステータスコードは 0: Unset, 1: Ok, 2: Error
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+// 64 ビット整数は JSON では文字列として送る
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// スパンを計装ライブラリごとにまとめる、リソースは全てのスパンで共通とする
+func createTracesRequest(spans []sdktrace.ReadOnlySpan) tracesRequest {
+	rs := resourceSpans{}
+	if r := spans[0].Resource(); r != nil {
+		rs.Resource.Attributes = createKeyValues(r.Attributes())
+	}
+
+	index := map[string]int{}
+	for _, s := range spans {
+		name := s.InstrumentationScope().Name
+		i, ok := index[name]
+		if !ok {
+			i = len(rs.ScopeSpans)
+			index[name] = i
+			rs.ScopeSpans = append(rs.ScopeSpans, scopeSpans{Scope: otlpScope{Name: name}})
+		}
+		rs.ScopeSpans[i].Spans = append(rs.ScopeSpans[i].Spans, createSpan(s))
+	}
+
+	return tracesRequest{ResourceSpans: []resourceSpans{rs}}
+}
+
+func createSpan(s sdktrace.ReadOnlySpan) otlpSpan {
+	span := otlpSpan{
+		TraceID:           s.SpanContext().TraceID().String(),
+		SpanID:            s.SpanContext().SpanID().String(),
+		Name:              s.Name(),
+		Kind:              int(s.SpanKind()),
+		StartTimeUnixNano: strconv.FormatInt(s.StartTime().UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.EndTime().UnixNano(), 10),
+		Attributes:        createKeyValues(s.Attributes()),
+	}
+	if s.Parent().HasSpanID() {
+		span.ParentSpanID = s.Parent().SpanID().String()
+	}
+	for _, e := range s.Events() {
+		span.Events = append(span.Events, otlpEvent{
+			TimeUnixNano: strconv.FormatInt(e.Time.UnixNano(), 10),
+			Name:         e.Name,
+			Attributes:   createKeyValues(e.Attributes),
+		})
+	}
+	switch s.Status().Code {
+	case codes.Ok:
+		span.Status.Code = 1
+	case codes.Error:
+		span.Status = otlpStatus{Code: 2, Message: s.Status().Description}
+	}
+
+	return span
+}
+
+// 配列などの値は文字列に変換して送る
+func createKeyValues(attrs []attribute.KeyValue) []keyValue {
+	var kvs []keyValue
+	for _, a := range attrs {
+		var v anyValue
+		switch a.Value.Type() {
+		case attribute.BOOL:
+			b := a.Value.AsBool()
+			v.BoolValue = &b
+		case attribute.INT64:
+			n := strconv.FormatInt(a.Value.AsInt64(), 10)
+			v.IntValue = &n
+		case attribute.FLOAT64:
+			f := a.Value.AsFloat64()
+			v.DoubleValue = &f
+		default:
+			s := a.Value.Emit()
+			v.StringValue = &s
+		}
+		kvs = append(kvs, keyValue{Key: string(a.Key), Value: v})
+	}
+
+	return kvs
+}
@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestExportSpans(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	// スパンごとに送信されるため、全てのリクエストを記録する
+	var got []tracesRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req tracesRequest
+		b, _ := io.ReadAll(r.Body)
+		ta.NoError(json.Unmarshal(b, &req))
+		ta.Equal("application/json", r.Header.Get("Content-Type"))
+		got = append(got, req)
+	}))
+	defer srv.Close()
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(newExporter(srv.Client(), srv.URL)),
+		sdktrace.WithIDGenerator(xrayIDGenerator{}),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "remind"))),
+	)
+	ctx, parent := tp.Tracer(scopeName).Start(context.Background(), "run")
+	_, child := tp.Tracer(scopeName).Start(ctx, "source.fetch")
+	child.SetAttributes(attribute.Int("events", 3))
+	End(child, errors.New("timeout"))
+	End(parent, nil)
+	tr.NoError(tp.Shutdown(context.Background()))
+
+	tr.Len(got, 2)
+	tr.Len(got[0].ResourceSpans, 1)
+	rs := got[0].ResourceSpans[0]
+	ta.Equal("service.name", rs.Resource.Attributes[0].Key)
+	ta.Equal("remind", *rs.Resource.Attributes[0].Value.StringValue)
+
+	tr.Len(rs.ScopeSpans, 1)
+	ta.Equal(scopeName, rs.ScopeSpans[0].Scope.Name)
+	span := rs.ScopeSpans[0].Spans[0]
+	ta.Equal("source.fetch", span.Name)
+	ta.Equal(parent.SpanContext().SpanID().String(), span.ParentSpanID)
+	ta.Equal(otlpStatus{Code: 2, Message: "timeout"}, span.Status)
+	ta.Equal("3", *span.Attributes[0].Value.IntValue)
+	ta.Equal("exception", span.Events[0].Name)
+}
+
+func TestTracesEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{
+			name:     "正常系/共通のエンドポイントが指定されている場合",
+			env:      map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318/"},
+			expected: "http://localhost:4318/v1/traces",
+		},
+		{
+			name: "正常系/トレース用のエンドポイントが指定されている場合",
+			env: map[string]string{
+				"OTEL_EXPORTER_OTLP_ENDPOINT":        "http://localhost:4318",
+				"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://collector:4318/traces",
+			},
+			expected: "http://collector:4318/traces",
+		},
+		{
+			name:     "正常系/指定されていない場合",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+			t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+			t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			ta.Equal(tt.expected, tracesEndpoint())
+		})
+	}
+}
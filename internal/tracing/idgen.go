@@ -0,0 +1,32 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// X-Ray はトレース ID の先頭 4 バイトを開始時刻 (Unix 秒) として扱うため、その形式で ID を作成する
+type xrayIDGenerator struct{}
+
+func (xrayIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	var tid trace.TraceID
+	binary.BigEndian.PutUint32(tid[:4], uint32(time.Now().Unix()))
+	_, _ = rand.Read(tid[4:])
+
+	return tid, newSpanID()
+}
+
+func (xrayIDGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	return newSpanID()
+}
+
+func newSpanID() trace.SpanID {
+	var sid trace.SpanID
+	_, _ = rand.Read(sid[:])
+
+	return sid
+}
@@ -0,0 +1,77 @@
+// Package tracing は各関数で共通の OpenTelemetry によるトレースの設定を提供する
+package tracing
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/httpclient"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const scopeName = "github.com/mami0tsu/homeops"
+
+var provider *sdktrace.TracerProvider
+
+// 環境変数 OTEL_EXPORTER_OTLP_ENDPOINT (e.g. http://localhost:4318) が設定されていれば、
+// OTLP/HTTP でスパンを送信するように設定する、未設定の場合はスパンを記録しない
+// X-Ray へは ADOT Collector の Lambda レイヤーを経由して送信する
+func Setup(service string) {
+	endpoint := tracesEndpoint()
+	if endpoint == "" {
+		return
+	}
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(newExporter(httpclient.Default, endpoint)),
+		sdktrace.WithIDGenerator(xrayIDGenerator{}),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", cmp.Or(os.Getenv("OTEL_SERVICE_NAME"), service)),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+}
+
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT が設定されていればそのまま使い、
+// なければ OTEL_EXPORTER_OTLP_ENDPOINT にトレースのパスを付けて使う
+func tracesEndpoint() string {
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); v != "" {
+		return v
+	}
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); v != "" {
+		return strings.TrimSuffix(v, "/") + "/v1/traces"
+	}
+
+	return ""
+}
+
+// Lambda は呼び出しが終わると停止するため、呼び出しごとに記録したスパンを送信する
+func Flush(ctx context.Context) {
+	if provider == nil {
+		return
+	}
+	if err := provider.ForceFlush(ctx); err != nil {
+		slog.Warn("failed to flush spans", slog.Any("error", err))
+	}
+}
+
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(scopeName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// エラーがあればスパンに記録してから終了する
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
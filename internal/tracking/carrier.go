@@ -0,0 +1,178 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// 配送状況、配送業者ごとの表記をまとめる
+type Status string
+
+const (
+	StatusAccepted  Status = "受付"
+	StatusInTransit Status = "輸送中"
+	StatusOut       Status = "配達中"
+	StatusAbsent    Status = "不在持ち戻り"
+	StatusDelivered Status = "配達完了"
+)
+
+func (s Status) Emoji() string {
+	switch s {
+	case StatusOut:
+		return "🚚"
+	case StatusAbsent:
+		return "📮"
+	case StatusDelivered:
+		return "✅"
+	default:
+		return "📦"
+	}
+}
+
+// 追跡ページに含まれる表記と配送状況の対応
+// 履歴は古いものから順に並ぶため、ページ内で最後に現れた表記をその時点の配送状況とする
+var statusPhrases = []struct {
+	phrase string
+	status Status
+}{
+	{"引受", StatusAccepted},
+	{"荷物受付", StatusAccepted},
+	{"集荷", StatusAccepted},
+	{"発送", StatusInTransit},
+	{"輸送中", StatusInTransit},
+	{"通過", StatusInTransit},
+	{"到着", StatusInTransit},
+	{"配達中", StatusOut},
+	{"持出中", StatusOut},
+	{"持ち出し中", StatusOut},
+	{"配達予定", StatusOut},
+	{"ご不在", StatusAbsent},
+	{"不在持戻", StatusAbsent},
+	{"お届け済み", StatusDelivered},
+	{"お届け完了", StatusDelivered},
+	{"配達完了", StatusDelivered},
+	{"配達済み", StatusDelivered},
+}
+
+var ErrNotFound = errors.New("tracking number not found")
+
+// 追跡番号が登録されていない場合の表記
+var notFoundPhrases = []string{"見つかりません", "お問い合わせ番号が存在しません", "該当するデータがありません", "伝票番号未登録"}
+
+// 配送業者ごとの追跡ページ
+type page struct {
+	method string
+	url    string // GET の場合は %s を追跡番号に置き換える
+	form   string // POST の場合のフォームの値、%s を追跡番号に置き換える
+	marker string // 履歴の表が始まる位置の表記、ページの案内文の表記を配送状況と誤認しないようにする
+}
+
+var pages = map[Carrier]page{
+	JapanPost: {
+		method: http.MethodGet,
+		url:    "https://trackings.post.japanpost.jp/services/srv/search/direct?reqCodeNo1=%s&searchKind=S002&locale=ja",
+		marker: "履歴情報",
+	},
+	Yamato: {
+		method: http.MethodPost,
+		url:    "https://toi.kuronekoyamato.co.jp/cgi-bin/tneko",
+		form:   "number00=1&number01=%s",
+		marker: "詳細",
+	},
+	Sagawa: {
+		method: http.MethodGet,
+		url:    "https://k2k.sagawa-exp.co.jp/p/web/okurijosearch.do?okurijoNo=%s",
+		marker: "詳細表示",
+	},
+}
+
+// 配送業者の追跡ページから配送状況を取得する
+// ページの構成が変わっても動くように、HTML の構造ではなく表記から配送状況を判定する
+type Tracker struct {
+	client *http.Client
+	pages  map[Carrier]page
+}
+
+func NewTracker(client *http.Client) *Tracker {
+	return &Tracker{client: client, pages: pages}
+}
+
+func (t *Tracker) Status(ctx context.Context, c Carrier, number string) (Status, error) {
+	p, ok := t.pages[c]
+	if !ok {
+		return "", fmt.Errorf("invalid carrier: %s", c)
+	}
+
+	var body io.Reader
+	if p.form != "" {
+		body = strings.NewReader(fmt.Sprintf(p.form, url.QueryEscape(number)))
+	}
+	u := p.url
+	if strings.Contains(u, "%s") {
+		u = fmt.Sprintf(u, url.QueryEscape(number))
+	}
+	req, err := http.NewRequestWithContext(ctx, p.method, u, body)
+	if err != nil {
+		return "", err
+	}
+	if p.form != "" {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("returned status %d", resp.StatusCode)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+	if err != nil {
+		return "", err
+	}
+
+	return parseStatus(string(b), p.marker)
+}
+
+var (
+	scriptPattern = regexp.MustCompile(`(?is)<(script|style)[^>]*>.*?</(script|style)>`)
+	tagPattern    = regexp.MustCompile(`<[^>]+>`)
+)
+
+// HTML からタグを除いたテキストのうち、marker 以降で最後に現れた表記を配送状況とする
+func parseStatus(page, marker string) (Status, error) {
+	text := html.UnescapeString(tagPattern.ReplaceAllString(scriptPattern.ReplaceAllString(page, ""), " "))
+	for _, p := range notFoundPhrases {
+		if strings.Contains(text, p) {
+			return "", ErrNotFound
+		}
+	}
+	if marker != "" {
+		if i := strings.Index(text, marker); i >= 0 {
+			text = text[i+len(marker):]
+		}
+	}
+
+	var status Status
+	last := -1
+	for _, p := range statusPhrases {
+		if i := strings.LastIndex(text, p.phrase); i > last {
+			last = i
+			status = p.status
+		}
+	}
+	if status == "" {
+		return "", fmt.Errorf("status not found")
+	}
+
+	return status, nil
+}
@@ -0,0 +1,165 @@
+// Package tracking は宅配便の荷物を登録し、配送業者の追跡ページから配送状況を取得する
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+)
+
+type Carrier string
+
+const (
+	JapanPost Carrier = "japanpost"
+	Yamato    Carrier = "yamato"
+	Sagawa    Carrier = "sagawa"
+)
+
+func (c Carrier) String() string {
+	switch c {
+	case JapanPost:
+		return "日本郵便"
+	case Yamato:
+		return "ヤマト運輸"
+	case Sagawa:
+		return "佐川急便"
+	default:
+		return string(c)
+	}
+}
+
+func ParseCarrier(s string) (Carrier, error) {
+	switch c := Carrier(strings.ToLower(strings.TrimSpace(s))); c {
+	case JapanPost, Yamato, Sagawa:
+		return c, nil
+	default:
+		return "", fmt.Errorf("invalid carrier: %s", s)
+	}
+}
+
+// 追跡番号のハイフンと空白を除き、数字のみであることを確認する
+func NormalizeNumber(s string) (string, error) {
+	n := strings.NewReplacer("-", "", " ", "", "　", "").Replace(strings.TrimSpace(s))
+	if len(n) < 10 || len(n) > 13 {
+		return "", fmt.Errorf("invalid tracking number: %s", s)
+	}
+	for _, r := range n {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("invalid tracking number: %s", s)
+		}
+	}
+
+	return n, nil
+}
+
+// 登録した荷物
+type Package struct {
+	Carrier Carrier
+	Number  string
+	Memo    string // e.g. 本棚
+	Status  Status // 最後に取得した配送状況、未取得の場合は空
+	AddedBy string
+}
+
+// 表示用の名前、メモがあればメモを含める
+func (p Package) Label() string {
+	if p.Memo != "" {
+		return fmt.Sprintf("%s (%s %s)", p.Memo, p.Carrier, p.Number)
+	}
+
+	return fmt.Sprintf("%s %s", p.Carrier, p.Number)
+}
+
+const partitionKey = "tracking"
+
+// 登録した荷物を DynamoDB に保存する
+// テーブルのキーは pk (パーティションキー) と sk (ソートキー、"<配送業者>#<追跡番号>") とし、配達が完了した荷物は削除する
+type Store struct {
+	client *dynamodb.Client
+	table  string
+}
+
+func NewStore(client *dynamodb.Client, table string) *Store {
+	return &Store{client: client, table: table}
+}
+
+func sortKey(c Carrier, number string) string {
+	return string(c) + "#" + number
+}
+
+// 荷物を登録する、登録済みの場合は false を返す
+func (s *Store) Add(ctx context.Context, p Package, now time.Time) (bool, error) {
+	item := dynamodb.Item{
+		"pk":       dynamodb.S(partitionKey),
+		"sk":       dynamodb.S(sortKey(p.Carrier, p.Number)),
+		"memo":     dynamodb.S(p.Memo),
+		"added_by": dynamodb.S(p.AddedBy),
+		"added_at": dynamodb.N(now.Unix()),
+	}
+	if err := s.client.PutItem(ctx, s.table, item, "attribute_not_exists(pk)", nil); err != nil {
+		if dynamodb.IsConditionalCheckFailed(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *Store) List(ctx context.Context) ([]Package, error) {
+	items, err := s.client.Query(ctx, s.table, "pk = :pk", dynamodb.Item{":pk": dynamodb.S(partitionKey)})
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePackages(items), nil
+}
+
+func (s *Store) UpdateStatus(ctx context.Context, p Package, status Status) error {
+	return s.client.UpdateItem(ctx, s.table, dynamodb.Item{
+		"pk": dynamodb.S(partitionKey),
+		"sk": dynamodb.S(sortKey(p.Carrier, p.Number)),
+	}, "SET tracking_status = :s", dynamodb.Item{":s": dynamodb.S(string(status))})
+}
+
+func (s *Store) Delete(ctx context.Context, p Package) error {
+	return s.client.DeleteItem(ctx, s.table, dynamodb.Item{
+		"pk": dynamodb.S(partitionKey),
+		"sk": dynamodb.S(sortKey(p.Carrier, p.Number)),
+	})
+}
+
+func parsePackages(items []dynamodb.Item) []Package {
+	var packages []Package
+	for _, item := range items {
+		carrier, number, ok := strings.Cut(item.Str("sk"), "#")
+		if !ok {
+			continue
+		}
+		packages = append(packages, Package{
+			Carrier: Carrier(carrier),
+			Number:  number,
+			Memo:    item.Str("memo"),
+			Status:  Status(item.Str("tracking_status")),
+			AddedBy: item.Str("added_by"),
+		})
+	}
+
+	return packages
+}
+
+// 追跡番号もしくはメモで荷物を探す
+func Find(packages []Package, target string) (Package, bool) {
+	target = strings.TrimSpace(target)
+	number, _ := NormalizeNumber(target)
+	for _, p := range packages {
+		if (number != "" && p.Number == number) || strings.EqualFold(p.Memo, target) {
+			return p, true
+		}
+	}
+
+	return Package{}, false
+}
@@ -0,0 +1,195 @@
+package tracking
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mami0tsu/homeops/internal/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCarrier(t *testing.T) {
+	ta := assert.New(t)
+
+	c, err := ParseCarrier(" Yamato ")
+	ta.NoError(err)
+	ta.Equal(Yamato, c)
+	ta.Equal("ヤマト運輸", c.String())
+
+	_, err = ParseCarrier("seino")
+	ta.Error(err)
+}
+
+func TestNormalizeNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		number   string
+		expected string
+		wantErr  bool
+	}{
+		{name: "正常系/ハイフンを含む場合", number: "1234-5678-9012", expected: "123456789012"},
+		{name: "正常系/空白を含む場合", number: " 1234 5678 90 ", expected: "1234567890"},
+		{name: "異常系/短すぎる場合", number: "123456789", wantErr: true},
+		{name: "異常系/数字以外を含む場合", number: "AB123456789JP", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			n, err := NormalizeNumber(tt.number)
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, n)
+		})
+	}
+}
+
+func TestPackageLabel(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.Equal("本棚 (佐川急便 123456789012)", Package{Carrier: Sagawa, Number: "123456789012", Memo: "本棚"}.Label())
+	ta.Equal("日本郵便 123456789012", Package{Carrier: JapanPost, Number: "123456789012"}.Label())
+}
+
+func TestParsePackages(t *testing.T) {
+	ta := assert.New(t)
+
+	packages := parsePackages([]dynamodb.Item{
+		{"pk": dynamodb.S("tracking"), "sk": dynamodb.S("yamato#123456789012"), "memo": dynamodb.S("本棚"), "tracking_status": dynamodb.S("配達中"), "added_by": dynamodb.S("alice")},
+		{"pk": dynamodb.S("tracking"), "sk": dynamodb.S("invalid")},
+	})
+
+	ta.Equal([]Package{{Carrier: Yamato, Number: "123456789012", Memo: "本棚", Status: StatusOut, AddedBy: "alice"}}, packages)
+}
+
+func TestFind(t *testing.T) {
+	ta := assert.New(t)
+
+	packages := []Package{{Carrier: Yamato, Number: "123456789012", Memo: "本棚"}, {Carrier: Sagawa, Number: "987654321098"}}
+
+	p, ok := Find(packages, "9876-5432-1098")
+	ta.True(ok)
+	ta.Equal(packages[1], p)
+
+	p, ok = Find(packages, "本棚")
+	ta.True(ok)
+	ta.Equal(packages[0], p)
+
+	_, ok = Find(packages, "椅子")
+	ta.False(ok)
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     string
+		marker   string
+		expected Status
+		err      error
+	}{
+		{
+			name:     "正常系/最後の履歴を配送状況とする",
+			page:     `<table><tr><td>引受</td></tr><tr><td>通過</td></tr><tr><td>お届け先にお届け済み</td></tr></table>`,
+			expected: StatusDelivered,
+		},
+		{
+			name:     "正常系/案内文の表記は除く",
+			page:     `<p>配達完了後の問い合わせは…</p><h2>履歴情報</h2><table><tr><td>荷物受付</td></tr><tr><td>配達店到着</td></tr><tr><td>配達中</td></tr></table>`,
+			marker:   "履歴情報",
+			expected: StatusOut,
+		},
+		{
+			name:     "正常系/スクリプトの表記は除く",
+			page:     `<td>ご不在のため持ち戻りました</td><script>var s = "配達完了";</script>`,
+			expected: StatusAbsent,
+		},
+		{
+			name: "異常系/追跡番号が見つからない場合",
+			page: `<p>お問い合わせ番号が見つかりません。</p>`,
+			err:  ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			status, err := parseStatus(tt.page, tt.marker)
+			if tt.err != nil {
+				ta.ErrorIs(err, tt.err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.expected, status)
+		})
+	}
+
+	t.Run("異常系/配送状況の表記がない場合", func(t *testing.T) {
+		ta := assert.New(t)
+
+		_, err := parseStatus(`<p>メンテナンス中です</p>`, "")
+		ta.Error(err)
+	})
+}
+
+func TestTrackerStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/get":
+			if r.URL.Query().Get("no") != "123456789012" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`<td>引受</td><td>到着</td>`))
+		case "/post":
+			b, _ := io.ReadAll(r.Body)
+			if string(b) != "number01=123456789012" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`<td>荷物受付</td><td>配達完了</td>`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	tracker := &Tracker{client: srv.Client(), pages: map[Carrier]page{
+		JapanPost: {method: http.MethodGet, url: srv.URL + "/get?no=%s"},
+		Yamato:    {method: http.MethodPost, url: srv.URL + "/post", form: "number01=%s"},
+		Sagawa:    {method: http.MethodGet, url: srv.URL + "/down/%s"},
+	}}
+
+	t.Run("正常系/GET で取得する場合", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		status, err := tracker.Status(context.Background(), JapanPost, "123456789012")
+		tr.NoError(err)
+		ta.Equal(StatusInTransit, status)
+	})
+
+	t.Run("正常系/POST で取得する場合", func(t *testing.T) {
+		ta := assert.New(t)
+		tr := require.New(t)
+
+		status, err := tracker.Status(context.Background(), Yamato, "123456789012")
+		tr.NoError(err)
+		ta.Equal(StatusDelivered, status)
+	})
+
+	t.Run("異常系/エラーが返された場合", func(t *testing.T) {
+		ta := assert.New(t)
+
+		_, err := tracker.Status(context.Background(), Sagawa, "123456789012")
+		ta.Error(err)
+	})
+}
@@ -0,0 +1,144 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/logging"
+)
+
+const WarningSourceName = "jma"
+
+// 気象庁の気象警報・注意報のコードと名称
+var warningNames = map[string]string{
+	"02": "暴風雪警報",
+	"03": "大雨警報",
+	"04": "洪水警報",
+	"05": "暴風警報",
+	"06": "大雪警報",
+	"07": "波浪警報",
+	"08": "高潮警報",
+	"10": "大雨注意報",
+	"12": "大雪注意報",
+	"13": "風雪注意報",
+	"14": "雷注意報",
+	"15": "強風注意報",
+	"16": "波浪注意報",
+	"17": "融雪注意報",
+	"18": "洪水注意報",
+	"19": "高潮注意報",
+	"20": "濃霧注意報",
+	"21": "乾燥注意報",
+	"22": "なだれ注意報",
+	"23": "低温注意報",
+	"24": "霜注意報",
+	"25": "着氷注意報",
+	"26": "着雪注意報",
+	"32": "暴風雪特別警報",
+	"33": "大雨特別警報",
+	"35": "暴風特別警報",
+	"36": "大雪特別警報",
+	"37": "波浪特別警報",
+	"38": "高潮特別警報",
+}
+
+// 発表中として扱う状態、解除されたものや発表がないものは除く
+var activeStatuses = map[string]bool{"発表": true, "継続": true}
+
+// 気象庁の気象警報・注意報のレスポンスのうち使用する部分
+type jmaWarningResponse struct {
+	AreaTypes []struct {
+		Areas []struct {
+			Code     string `json:"code"`
+			Warnings []struct {
+				Code   string `json:"code"`
+				Status string `json:"status"`
+			} `json:"warnings"`
+		} `json:"areas"`
+	} `json:"areaTypes"`
+}
+
+// 気象警報・注意報の程度
+type WarningLevel int
+
+const (
+	LevelAdvisory  WarningLevel = iota // 注意報
+	LevelWarning                       // 警報
+	LevelEmergency                     // 特別警報
+)
+
+// 名称から程度を返す
+func LevelOf(name string) WarningLevel {
+	switch {
+	case strings.HasSuffix(name, "特別警報"):
+		return LevelEmergency
+	case strings.HasSuffix(name, "警報"):
+		return LevelWarning
+	default:
+		return LevelAdvisory
+	}
+}
+
+// 気象庁から loc の市区町村に発表中の気象警報・注意報の名称を返す
+// baseURL は e.g. https://www.jma.go.jp/bosai/warning/data/warning で、気象台の地域コードのファイルを取得する
+func FetchWarnings(ctx context.Context, client *http.Client, baseURL string, loc Location) ([]string, error) {
+	if loc.Office == "" {
+		return nil, nil
+	}
+
+	return FetchOfficeWarnings(ctx, client, baseURL, loc.Office, loc.Area)
+}
+
+// 気象庁から office の気象台が area の地域に発表中の気象警報・注意報の名称を返す
+// area が空の場合は、気象台が受け持ついずれかの地域に発表中のものを返す
+func FetchOfficeWarnings(ctx context.Context, client *http.Client, baseURL, office, area string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%s.json", baseURL, office), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, event.NewSourceUnavailableError(WarningSourceName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, event.NewSourceUnavailableError(WarningSourceName, fmt.Errorf("returned status %d", resp.StatusCode))
+	}
+
+	var r jmaWarningResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, event.NewParseError(WarningSourceName, office, err)
+	}
+	logging.DebugPayload(ctx, "fetched warnings", r)
+
+	return r.warnings(area), nil
+}
+
+// 同じ地域が複数の区分に含まれる場合もあるため、名称の重複を除いて返す
+func (r jmaWarningResponse) warnings(area string) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, t := range r.AreaTypes {
+		for _, a := range t.Areas {
+			if area != "" && a.Code != area {
+				continue
+			}
+			for _, w := range a.Warnings {
+				name, ok := warningNames[w.Code]
+				if !ok || !activeStatuses[w.Status] || seen[name] {
+					continue
+				}
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,123 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/mami0tsu/homeops/internal/event"
+	"github.com/mami0tsu/homeops/internal/logging"
+)
+
+const ForecastSourceName = "open-meteo"
+
+const (
+	hourlyParams = "temperature_2m,relative_humidity_2m,wind_speed_10m,precipitation_probability"
+	dailyParams  = "weather_code,temperature_2m_max,temperature_2m_min"
+)
+
+// Open-Meteo のレスポンスのうち使用する部分
+// 欠損している値は null になるため、ゼロ値として扱う
+type openMeteoResponse struct {
+	Hourly struct {
+		Time          []string  `json:"time"`
+		Temperature   []float64 `json:"temperature_2m"`
+		Humidity      []float64 `json:"relative_humidity_2m"`
+		WindSpeed     []float64 `json:"wind_speed_10m"`
+		Precipitation []int     `json:"precipitation_probability"`
+	} `json:"hourly"`
+	Daily struct {
+		Time        []string  `json:"time"`
+		WeatherCode []int     `json:"weather_code"`
+		MaxTemp     []float64 `json:"temperature_2m_max"`
+		MinTemp     []float64 `json:"temperature_2m_min"`
+	} `json:"daily"`
+}
+
+// Open-Meteo から loc の当日の予報を取得する
+// 気象庁のモデルを使う場合は model に jma_seamless を指定する、空の場合は Open-Meteo が選択する
+// https://open-meteo.com/en/docs
+func FetchForecast(ctx context.Context, client *http.Client, baseURL, model string, loc Location, tz *time.Location) (Forecast, error) {
+	q := url.Values{}
+	q.Set("latitude", strconv.FormatFloat(loc.Latitude, 'f', -1, 64))
+	q.Set("longitude", strconv.FormatFloat(loc.Longitude, 'f', -1, 64))
+	q.Set("hourly", hourlyParams)
+	q.Set("daily", dailyParams)
+	q.Set("wind_speed_unit", "ms")
+	q.Set("timezone", tz.String())
+	q.Set("forecast_days", "1")
+	if model != "" {
+		q.Set("models", model)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return Forecast{}, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Forecast{}, event.NewSourceUnavailableError(ForecastSourceName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Forecast{}, event.NewSourceUnavailableError(ForecastSourceName, fmt.Errorf("returned status %d", resp.StatusCode))
+	}
+
+	var r openMeteoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return Forecast{}, event.NewParseError(ForecastSourceName, loc.Name, err)
+	}
+	logging.DebugPayload(ctx, "fetched forecast", r)
+
+	f, err := r.forecast(tz)
+	if err != nil {
+		return Forecast{}, event.NewParseError(ForecastSourceName, loc.Name, err)
+	}
+
+	return f, nil
+}
+
+// 時刻は指定したタイムゾーンの 2006-01-02T15:04 の形式で返される
+func (r openMeteoResponse) forecast(tz *time.Location) (Forecast, error) {
+	d := r.Daily
+	if len(d.Time) == 0 || len(d.WeatherCode) == 0 || len(d.MaxTemp) == 0 || len(d.MinTemp) == 0 {
+		return Forecast{}, fmt.Errorf("daily forecast is missing")
+	}
+	date, err := time.ParseInLocation("2006-01-02", d.Time[0], tz)
+	if err != nil {
+		return Forecast{}, fmt.Errorf("invalid date: %s", d.Time[0])
+	}
+	f := Forecast{Date: date, WeatherCode: d.WeatherCode[0], MaxTemp: d.MaxTemp[0], MinTemp: d.MinTemp[0]}
+
+	h := r.Hourly
+	for i, s := range h.Time {
+		t, err := time.ParseInLocation("2006-01-02T15:04", s, tz)
+		if err != nil {
+			return Forecast{}, fmt.Errorf("invalid time: %s", s)
+		}
+		f.Hours = append(f.Hours, Hourly{
+			Time:          t,
+			Temperature:   at(h.Temperature, i),
+			Humidity:      at(h.Humidity, i),
+			WindSpeed:     at(h.WindSpeed, i),
+			Precipitation: at(h.Precipitation, i),
+		})
+	}
+
+	return f, nil
+}
+
+func at[T any](values []T, i int) T {
+	var zero T
+	if i >= len(values) {
+		return zero
+	}
+
+	return values[i]
+}
@@ -0,0 +1,160 @@
+// Package weather は地点ごとの天気予報と気象警報・注意報の取得と、朝の天気の要約を提供する
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// 天気予報を取得する地点
+type Location struct {
+	Name      string  `json:"name"`      // e.g. 自宅
+	Latitude  float64 `json:"latitude"`  // e.g. 35.6812
+	Longitude float64 `json:"longitude"` // e.g. 139.7671
+	Office    string  `json:"office"`    // 気象警報・注意報を取得する気象台の地域コード、e.g. 130000
+	Area      string  `json:"area"`      // 気象警報・注意報を取得する市区町村の地域コード、e.g. 1310100
+}
+
+// e.g. [{"name": "自宅", "latitude": 35.6812, "longitude": 139.7671, "office": "130000", "area": "1310100"}]
+type Locations []Location
+
+func (l *Locations) UnmarshalText(text []byte) error {
+	// Locations のまま読み込むと UnmarshalText が再度呼び出されるため、スライスとして読み込む
+	var locations []Location
+	if err := json.Unmarshal(text, &locations); err != nil {
+		return fmt.Errorf("invalid weather locations: %w", err)
+	}
+	for _, loc := range locations {
+		if loc.Name == "" {
+			return fmt.Errorf("invalid weather locations: name is blank")
+		}
+		if loc.Latitude < -90 || loc.Latitude > 90 || loc.Longitude < -180 || loc.Longitude > 180 {
+			return fmt.Errorf("invalid weather locations: %s: invalid coordinates", loc.Name)
+		}
+		if (loc.Office == "") != (loc.Area == "") {
+			return fmt.Errorf("invalid weather locations: %s: office and area must be set together", loc.Name)
+		}
+	}
+	*l = locations
+
+	return nil
+}
+
+// 1 時間ごとの予報
+type Hourly struct {
+	Time          time.Time
+	Temperature   float64 // ℃
+	Humidity      float64 // %
+	WindSpeed     float64 // m/s
+	Precipitation int     // 降水確率 (%)
+}
+
+// 1 日分の予報
+type Forecast struct {
+	Date        time.Time
+	WeatherCode int // WMO の天気コード
+	MaxTemp     float64
+	MinTemp     float64
+	Hours       []Hourly
+}
+
+// 降水確率を表示する時間帯
+type Block struct {
+	Start, End    int // 時、End は含まない
+	Precipitation int // 時間帯の最大の降水確率 (%)
+}
+
+// 朝に表示する 6 時から 24 時までを 6 時間ごとに区切る
+var blockHours = [][2]int{{6, 12}, {12, 18}, {18, 24}}
+
+// 時間帯ごとの降水確率を返す
+func (f Forecast) Blocks() []Block {
+	blocks := make([]Block, 0, len(blockHours))
+	for _, h := range blockHours {
+		b := Block{Start: h[0], End: h[1]}
+		for _, v := range f.Hours {
+			if hour := v.Time.Hour(); hour >= b.Start && hour < b.End {
+				b.Precipitation = max(b.Precipitation, v.Precipitation)
+			}
+		}
+		blocks = append(blocks, b)
+	}
+
+	return blocks
+}
+
+// 洗濯物を外に干す 9 時から 15 時までの予報から、洗濯指数を 0 から 100 の 10 刻みで返す
+// 降水確率を最も重く見て、気温と風で乾きやすく、湿度で乾きにくくなるように加減する
+func (f Forecast) LaundryIndex() int {
+	var rain int
+	var temp, humidity, wind float64
+	var n int
+	for _, v := range f.Hours {
+		if hour := v.Time.Hour(); hour < 9 || hour >= 15 {
+			continue
+		}
+		rain = max(rain, v.Precipitation)
+		temp += v.Temperature
+		humidity += v.Humidity
+		wind += v.WindSpeed
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	temp, humidity, wind = temp/float64(n), humidity/float64(n), wind/float64(n)
+
+	score := 100 - float64(rain)
+	score += (temp - 20) * 1.5
+	score -= max(humidity-50, 0) * 0.8
+	score += min(wind, 5) * 2
+	score = min(max(score, 0), 100)
+
+	return int(score/10+0.5) * 10
+}
+
+// 洗濯指数の目安
+func LaundryLabel(index int) string {
+	switch {
+	case index >= 90:
+		return "大変よく乾く"
+	case index >= 70:
+		return "よく乾く"
+	case index >= 50:
+		return "乾く"
+	case index >= 30:
+		return "やや乾きにくい"
+	default:
+		return "部屋干し推奨"
+	}
+}
+
+// WMO の天気コードを天気の表記に変換する
+// https://open-meteo.com/en/docs#weathervariables
+func Describe(code int) string {
+	switch {
+	case code == 0:
+		return "☀️ 快晴"
+	case code <= 2:
+		return "🌤️ 晴れ"
+	case code == 3:
+		return "☁️ くもり"
+	case code == 45 || code == 48:
+		return "🌫️ 霧"
+	case code >= 51 && code <= 57:
+		return "🌦️ 霧雨"
+	case code >= 61 && code <= 67:
+		return "☔ 雨"
+	case code >= 71 && code <= 77:
+		return "❄️ 雪"
+	case code >= 80 && code <= 82:
+		return "🌧️ にわか雨"
+	case code == 85 || code == 86:
+		return "🌨️ にわか雪"
+	case code >= 95:
+		return "⛈️ 雷雨"
+	default:
+		return "❓ 不明"
+	}
+}
@@ -0,0 +1,357 @@
+package weather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocationsUnmarshalText(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		want    Locations
+		wantErr bool
+	}{
+		{
+			name:  "正常系/地点と地域コードを読み込む",
+			input: `[{"name": "自宅", "latitude": 35.6812, "longitude": 139.7671, "office": "130000", "area": "1310100"}]`,
+			want:  Locations{{Name: "自宅", Latitude: 35.6812, Longitude: 139.7671, Office: "130000", Area: "1310100"}},
+		},
+		{
+			name:  "正常系/地域コードは省略できる",
+			input: `[{"name": "実家", "latitude": 34.6937, "longitude": 135.5023}]`,
+			want:  Locations{{Name: "実家", Latitude: 34.6937, Longitude: 135.5023}},
+		},
+		{
+			name:    "異常系/地点の名前が空",
+			input:   `[{"latitude": 35.6812, "longitude": 139.7671}]`,
+			wantErr: true,
+		},
+		{
+			name:    "異常系/緯度が範囲外",
+			input:   `[{"name": "自宅", "latitude": 135.6812, "longitude": 139.7671}]`,
+			wantErr: true,
+		},
+		{
+			name:    "異常系/市区町村の地域コードのみ指定",
+			input:   `[{"name": "自宅", "latitude": 35.6812, "longitude": 139.7671, "area": "1310100"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "異常系/JSON でない",
+			input:   `home`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			var got Locations
+			err := got.UnmarshalText([]byte(tt.input))
+			if tt.wantErr {
+				ta.Error(err)
+				return
+			}
+			ta.NoError(err)
+			ta.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestForecastBlocks(t *testing.T) {
+	ta := assert.New(t)
+	tz := time.FixedZone("JST", 9*60*60)
+
+	f := Forecast{Hours: hours(tz, map[int]Hourly{
+		5:  {Precipitation: 90},
+		7:  {Precipitation: 10},
+		11: {Precipitation: 30},
+		12: {Precipitation: 50},
+		23: {Precipitation: 20},
+	})}
+
+	ta.Equal([]Block{
+		{Start: 6, End: 12, Precipitation: 30},
+		{Start: 12, End: 18, Precipitation: 50},
+		{Start: 18, End: 24, Precipitation: 20},
+	}, f.Blocks())
+}
+
+func TestForecastLaundryIndex(t *testing.T) {
+	tz := time.FixedZone("JST", 9*60*60)
+
+	cases := []struct {
+		name  string
+		hour  Hourly
+		want  int
+		label string
+	}{
+		{
+			name:  "正常系/晴れて暖かく乾燥している",
+			hour:  Hourly{Temperature: 25, Humidity: 40, WindSpeed: 3},
+			want:  100,
+			label: "大変よく乾く",
+		},
+		{
+			name:  "正常系/曇りで湿度が高い",
+			hour:  Hourly{Temperature: 18, Humidity: 75, WindSpeed: 1, Precipitation: 30},
+			want:  50,
+			label: "乾く",
+		},
+		{
+			name:  "正常系/雨が降る",
+			hour:  Hourly{Temperature: 15, Humidity: 90, Precipitation: 80},
+			want:  0,
+			label: "部屋干し推奨",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			f := Forecast{Hours: hours(tz, map[int]Hourly{9: tt.hour, 12: tt.hour, 14: tt.hour})}
+			ta.Equal(tt.want, f.LaundryIndex())
+			ta.Equal(tt.label, LaundryLabel(f.LaundryIndex()))
+		})
+	}
+}
+
+func TestFetchForecast(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+	tz := time.FixedZone("Asia/Tokyo", 9*60*60)
+
+	var query map[string][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{
+			"hourly": {
+				"time": ["2025-06-01T00:00", "2025-06-01T01:00"],
+				"temperature_2m": [18.2, 17.9],
+				"relative_humidity_2m": [80, 82],
+				"wind_speed_10m": [1.5, null],
+				"precipitation_probability": [10, null]
+			},
+			"daily": {
+				"time": ["2025-06-01"],
+				"weather_code": [61],
+				"temperature_2m_max": [24.1],
+				"temperature_2m_min": [17.5]
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	loc := Location{Name: "自宅", Latitude: 35.6812, Longitude: 139.7671}
+	f, err := FetchForecast(context.Background(), srv.Client(), srv.URL, "jma_seamless", loc, tz)
+	tr.NoError(err)
+
+	ta.Equal("35.6812", query["latitude"][0])
+	ta.Equal("jma_seamless", query["models"][0])
+	ta.Equal("Asia/Tokyo", query["timezone"][0])
+	ta.Equal(time.Date(2025, 6, 1, 0, 0, 0, 0, tz), f.Date)
+	ta.Equal(61, f.WeatherCode)
+	ta.Equal(24.1, f.MaxTemp)
+	ta.Equal([]Hourly{
+		{Time: time.Date(2025, 6, 1, 0, 0, 0, 0, tz), Temperature: 18.2, Humidity: 80, WindSpeed: 1.5, Precipitation: 10},
+		{Time: time.Date(2025, 6, 1, 1, 0, 0, 0, tz), Temperature: 17.9, Humidity: 82},
+	}, f.Hours)
+}
+
+func TestFetchForecastError(t *testing.T) {
+	cases := []struct {
+		name string
+		code int
+		body string
+	}{
+		{name: "異常系/エラーのステータスコード", code: http.StatusBadRequest, body: `{"error": true}`},
+		{name: "異常系/日ごとの予報がない", code: http.StatusOK, body: `{"hourly": {}, "daily": {}}`},
+		{name: "異常系/JSON でない", code: http.StatusOK, body: `<html>`},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.code)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			_, err := FetchForecast(context.Background(), srv.Client(), srv.URL, "", Location{Name: "自宅"}, time.UTC)
+			ta.Error(err)
+		})
+	}
+}
+
+func TestFetchAirQuality(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	var query map[string][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{
+			"hourly": {
+				"time": ["2025-03-01T09:00", "2025-03-01T10:00"],
+				"pm2_5": [12.5, 38.2],
+				"alder_pollen": [20, null],
+				"birch_pollen": [30, 45],
+				"grass_pollen": [null, null]
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	loc := Location{Name: "自宅", Latitude: 35.6812, Longitude: 139.7671}
+	a, err := FetchAirQuality(context.Background(), srv.Client(), srv.URL, loc, time.UTC)
+	tr.NoError(err)
+
+	ta.Equal("35.6812", query["latitude"][0])
+	ta.Equal(38.2, a.PM25)
+	// 時間ごとに種類を合計した最大
+	tr.NotNil(a.Pollen)
+	ta.Equal(50.0, *a.Pollen)
+}
+
+func TestFetchAirQualityWithoutPollen(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"hourly": {"time": ["2025-03-01T09:00"], "pm2_5": [8], "alder_pollen": [null], "birch_pollen": [null]}}`))
+	}))
+	defer srv.Close()
+
+	a, err := FetchAirQuality(context.Background(), srv.Client(), srv.URL, Location{Name: "自宅"}, time.UTC)
+	tr.NoError(err)
+	ta.Equal(8.0, a.PM25)
+	ta.Nil(a.Pollen)
+}
+
+func TestFetchAirQualityError(t *testing.T) {
+	cases := []struct {
+		name string
+		code int
+		body string
+	}{
+		{name: "異常系/エラーのステータスコード", code: http.StatusBadRequest, body: `{"error": true}`},
+		{name: "異常系/PM2.5 がない", code: http.StatusOK, body: `{"hourly": {}}`},
+		{name: "異常系/JSON でない", code: http.StatusOK, body: `<html>`},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := assert.New(t)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.code)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			_, err := FetchAirQuality(context.Background(), srv.Client(), srv.URL, Location{Name: "自宅"}, time.UTC)
+			ta.Error(err)
+		})
+	}
+}
+
+func TestMaskRecommended(t *testing.T) {
+	pollen := func(v float64) *float64 { return &v }
+	cases := []struct {
+		name     string
+		air      AirQuality
+		expected bool
+	}{
+		{name: "正常系/どちらも基準未満", air: AirQuality{PM25: 20, Pollen: pollen(10)}, expected: false},
+		{name: "正常系/PM2.5 が基準以上", air: AirQuality{PM25: 35}, expected: true},
+		{name: "正常系/花粉が基準以上", air: AirQuality{PM25: 10, Pollen: pollen(80)}, expected: true},
+		{name: "正常系/花粉の予報がない", air: AirQuality{PM25: 10}, expected: false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.air.MaskRecommended(35, 50))
+		})
+	}
+}
+
+func TestFetchWarnings(t *testing.T) {
+	ta := assert.New(t)
+	tr := require.New(t)
+
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{
+			"areaTypes": [
+				{"areas": [{"code": "130010", "warnings": [{"code": "14", "status": "発表"}]}]},
+				{"areas": [
+					{"code": "1310100", "warnings": [
+						{"code": "03", "status": "発表"},
+						{"code": "14", "status": "継続"},
+						{"code": "15", "status": "解除"},
+						{"code": "14", "status": "継続"}
+					]},
+					{"code": "1310200", "warnings": [{"code": "04", "status": "発表"}]}
+				]}
+			]
+		}`))
+	}))
+	defer srv.Close()
+
+	loc := Location{Name: "自宅", Office: "130000", Area: "1310100"}
+	got, err := FetchWarnings(context.Background(), srv.Client(), srv.URL, loc)
+	tr.NoError(err)
+
+	ta.Equal("/130000.json", path)
+	ta.Equal([]string{"大雨警報", "雷注意報"}, got)
+
+	// 地域を指定しない場合は気象台が受け持つすべての地域を対象とする
+	got, err = FetchOfficeWarnings(context.Background(), srv.Client(), srv.URL, "130000", "")
+	tr.NoError(err)
+	ta.Equal([]string{"雷注意報", "大雨警報", "洪水警報"}, got)
+}
+
+func TestLevelOf(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.Equal(LevelAdvisory, LevelOf("雷注意報"))
+	ta.Equal(LevelWarning, LevelOf("大雨警報"))
+	ta.Equal(LevelEmergency, LevelOf("大雨特別警報"))
+}
+
+func TestDescribe(t *testing.T) {
+	ta := assert.New(t)
+
+	ta.Equal("☀️ 快晴", Describe(0))
+	ta.Equal("☁️ くもり", Describe(3))
+	ta.Equal("☔ 雨", Describe(63))
+	ta.Equal("⛈️ 雷雨", Describe(95))
+	ta.Equal("❓ 不明", Describe(42))
+}
+
+// 指定した時の予報を当日の時刻で作成する
+func hours(tz *time.Location, values map[int]Hourly) []Hourly {
+	var hs []Hourly
+	for h := 0; h < 24; h++ {
+		v, ok := values[h]
+		if !ok {
+			continue
+		}
+		v.Time = time.Date(2025, 6, 1, h, 0, 0, 0, tz)
+		hs = append(hs, v)
+	}
+
+	return hs
+}
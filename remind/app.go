@@ -1,9 +0,0 @@
-package main
-
-type App struct {
-	source EventSource
-}
-
-func NewApp(source EventSource) *App {
-	return &App{source: source}
-}